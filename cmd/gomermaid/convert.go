@@ -0,0 +1,211 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/block"
+	"github.com/TyphonHill/go-mermaid/diagrams/class"
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/sequence"
+	"github.com/TyphonHill/go-mermaid/diagrams/serialize"
+	"github.com/TyphonHill/go-mermaid/diagrams/spec"
+	"github.com/TyphonHill/go-mermaid/diagrams/state"
+	"github.com/TyphonHill/go-mermaid/render/d2"
+	"github.com/TyphonHill/go-mermaid/render/dot"
+	"github.com/TyphonHill/go-mermaid/render/drawio"
+	"github.com/TyphonHill/go-mermaid/render/plantuml"
+	"github.com/TyphonHill/go-mermaid/render/svg"
+	"github.com/TyphonHill/go-mermaid/render/text"
+)
+
+const (
+	toFlag              string = "to"
+	toUsage             string = "output format: %s"
+	asciiFlag           string = "ascii"
+	asciiUsage          string = "draw text output with ASCII characters only"
+	missingFormat       string = "missing output format"
+	unknownFormat       string = "unknown output format %q"
+	unsupportedString   string = "%s: %w: %s cannot be converted to %s"
+	formatListSeparator string = ", "
+)
+
+// errUnsupportedConversion is returned for a diagram type a format cannot represent.
+var errUnsupportedConversion = errors.New("unsupported conversion")
+
+// converter converts the diagrams of the supported types to an output format.
+type converter struct {
+	name      string
+	extension string
+	convert   func(model serialize.Diagram, options convertOptions) (string, bool, error)
+}
+
+// convertOptions holds the flags of the convert command that apply to some formats.
+type convertOptions struct {
+	ascii bool
+}
+
+// converters lists the output formats.
+var converters = []converter{
+	{name: "mermaid", extension: spec.FileExtension, convert: convertMermaid},
+	{name: "json", extension: ".json", convert: convertJSON},
+	{name: "yaml", extension: ".yaml", convert: convertYAML},
+	{name: "dot", extension: ".dot", convert: convertDOT},
+	{name: "plantuml", extension: ".puml", convert: convertPlantUML},
+	{name: "svg", extension: ".svg", convert: convertSVG},
+	{name: "d2", extension: ".d2", convert: convertD2},
+	{name: "drawio", extension: ".drawio", convert: convertDrawio},
+	{name: "text", extension: ".txt", convert: convertText},
+}
+
+// runConvert converts every diagram to the format given by -to. Nothing is written when an
+// input is invalid or a diagram cannot be converted.
+func runConvert(c *cli, cmd *command, args []string) error {
+	names := make([]string, 0, len(converters))
+	for _, conv := range converters {
+		names = append(names, conv.name)
+	}
+
+	flags := c.flags(cmd)
+	to := flags.String(toFlag, "", fmt.Sprintf(toUsage, strings.Join(names, formatListSeparator)))
+	ascii := flags.Bool(asciiFlag, false, asciiUsage)
+	dir := flags.String(outputDirFlag, "", outputDirUsage)
+	args, err := c.parse(flags, args)
+	if err != nil {
+		return err
+	}
+
+	var conv *converter
+	for i := range converters {
+		if converters[i].name == *to {
+			conv = &converters[i]
+		}
+	}
+	switch {
+	case *to == "":
+		return c.usageError(flags, missingFormat)
+	case conv == nil:
+		return c.usageError(flags, unknownFormat, *to)
+	}
+
+	diagrams, failed, err := c.loadDiagrams(args)
+	if err != nil {
+		return err
+	}
+
+	outputs := make([]output, 0, len(diagrams))
+	for _, diagram := range diagrams {
+		content, ok, err := conv.convert(diagram.Model, convertOptions{ascii: *ascii})
+		if err != nil {
+			return err
+		}
+		if !ok {
+			c.report(fmt.Errorf(unsupportedString, location(diagram), errUnsupportedConversion, diagram.Type, conv.name))
+			failed = true
+			continue
+		}
+		outputs = append(outputs, output{name: diagram.Name + conv.extension, content: content})
+	}
+
+	if failed {
+		return errFailed
+	}
+
+	return c.writeOutputs(*dir, outputs)
+}
+
+// convertMermaid returns the Mermaid syntax of a diagram.
+func convertMermaid(model serialize.Diagram, _ convertOptions) (string, bool, error) {
+	return model.String(), true, nil
+}
+
+// convertJSON returns the JSON document of a diagram.
+func convertJSON(model serialize.Diagram, _ convertOptions) (string, bool, error) {
+	data, err := serialize.MarshalJSON(model)
+	return string(data), true, err
+}
+
+// convertYAML returns the YAML document of a diagram.
+func convertYAML(model serialize.Diagram, _ convertOptions) (string, bool, error) {
+	data, err := serialize.MarshalYAML(model)
+	return string(data), true, err
+}
+
+// convertDOT returns the Graphviz DOT document of a flowchart, state or class diagram.
+func convertDOT(model serialize.Diagram, _ convertOptions) (string, bool, error) {
+	switch d := model.(type) {
+	case *flowchart.Flowchart:
+		return dot.RenderFlowchart(d), true, nil
+	case *state.Diagram:
+		return dot.RenderState(d), true, nil
+	case *class.ClassDiagram:
+		return dot.RenderClass(d), true, nil
+	}
+
+	return "", false, nil
+}
+
+// convertPlantUML returns the PlantUML text of a sequence or class diagram.
+func convertPlantUML(model serialize.Diagram, _ convertOptions) (string, bool, error) {
+	switch d := model.(type) {
+	case *sequence.Diagram:
+		return plantuml.RenderSequence(d), true, nil
+	case *class.ClassDiagram:
+		return plantuml.RenderClass(d), true, nil
+	}
+
+	return "", false, nil
+}
+
+// convertSVG returns the SVG document of a flowchart or sequence diagram.
+func convertSVG(model serialize.Diagram, _ convertOptions) (string, bool, error) {
+	switch d := model.(type) {
+	case *flowchart.Flowchart:
+		return svg.RenderFlowchart(d, svg.Options{}), true, nil
+	case *sequence.Diagram:
+		return svg.RenderSequence(d, svg.Options{}), true, nil
+	}
+
+	return "", false, nil
+}
+
+// convertD2 returns the D2 source of a flowchart or block diagram.
+func convertD2(model serialize.Diagram, _ convertOptions) (string, bool, error) {
+	switch d := model.(type) {
+	case *flowchart.Flowchart:
+		return d2.RenderFlowchart(d), true, nil
+	case *block.Diagram:
+		return d2.RenderBlock(d), true, nil
+	}
+
+	return "", false, nil
+}
+
+// convertDrawio returns the draw.io file of a flowchart or block diagram.
+func convertDrawio(model serialize.Diagram, _ convertOptions) (string, bool, error) {
+	switch d := model.(type) {
+	case *flowchart.Flowchart:
+		return drawio.RenderFlowchart(d), true, nil
+	case *block.Diagram:
+		return drawio.RenderBlock(d), true, nil
+	}
+
+	return "", false, nil
+}
+
+// convertText returns the text drawing of a flowchart, sequence or state diagram.
+func convertText(model serialize.Diagram, options convertOptions) (string, bool, error) {
+	textOptions := text.Options{ASCII: options.ascii}
+
+	switch d := model.(type) {
+	case *flowchart.Flowchart:
+		return text.RenderFlowchart(d, textOptions), true, nil
+	case *sequence.Diagram:
+		return text.RenderSequence(d, textOptions), true, nil
+	case *state.Diagram:
+		return text.RenderState(d, textOptions), true, nil
+	}
+
+	return "", false, nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"orders.yaml": flowchartDocument,
+		"spec.yaml":   twoDiagramSpec,
+		"login.json":  sequenceDocument,
+		"flow.mmd":    "graph LR\n    a --> b\n",
+	})
+	out := filepath.Join(dir, "out")
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+		wantFiles  map[string]string
+	}{
+		{
+			name:       "DOT",
+			args:       []string{"-to", "dot", "orders.yaml"},
+			wantCode:   exitOK,
+			wantStdout: "\"a\" -> \"b\";",
+		},
+		{
+			name:       "PlantUML",
+			args:       []string{"-to", "plantuml", "login.json"},
+			wantCode:   exitOK,
+			wantStdout: "@startuml",
+		},
+		{
+			name:       "JSON",
+			args:       []string{"-to", "json", "orders.yaml"},
+			wantCode:   exitOK,
+			wantStdout: "\"type\": \"flowchart\",",
+		},
+		{
+			name:       "SVG",
+			args:       []string{"-to", "svg", "login.json"},
+			wantCode:   exitOK,
+			wantStdout: "<svg",
+		},
+		{
+			name:       "ASCII text",
+			args:       []string{"-to", "text", "-ascii", "orders.yaml"},
+			wantCode:   exitOK,
+			wantStdout: "+---+",
+		},
+		{
+			name:       "Mermaid file",
+			args:       []string{"-to", "dot", "flow.mmd"},
+			wantCode:   exitOK,
+			wantStdout: "\"a\" -> \"b\";",
+		},
+		{
+			name:      "Output directory",
+			args:      []string{"-to", "dot", "-o", out, "spec.yaml"},
+			wantCode:  exitOK,
+			wantFiles: map[string]string{"first.dot": "digraph", "second.dot": "digraph"},
+		},
+		{
+			name:       "Unsupported diagram type",
+			args:       []string{"-to", "plantuml", "orders.yaml", "login.json"},
+			wantCode:   exitFailure,
+			wantStderr: "orders.yaml:1: unsupported conversion: flowchart cannot be converted to plantuml\n",
+		},
+		{
+			name:       "Unsupported Mermaid diagram type",
+			args:       []string{"-to", "plantuml", "flow.mmd"},
+			wantCode:   exitFailure,
+			wantStderr: "flow.mmd:1: unsupported conversion: flowchart cannot be converted to plantuml\n",
+		},
+		{
+			name:       "Missing format",
+			args:       []string{"orders.yaml"},
+			wantCode:   exitUsage,
+			wantStderr: "gomermaid: missing output format\n",
+		},
+		{
+			name:       "Unknown format",
+			args:       []string{"-to", "png", "orders.yaml"},
+			wantCode:   exitUsage,
+			wantStderr: "gomermaid: unknown output format \"png\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := []string{"convert"}
+			for _, arg := range tt.args {
+				if strings.Contains(arg, ".") {
+					arg = filepath.Join(dir, arg)
+				}
+				args = append(args, arg)
+			}
+
+			code, stdout, stderr := runCLI(t, "", args...)
+			if code != tt.wantCode {
+				t.Fatalf("run() = %d, want %d (stderr: %s)", code, tt.wantCode, stderr)
+			}
+			if !strings.Contains(stdout, tt.wantStdout) {
+				t.Errorf("stdout = %q, want it to contain %q", stdout, tt.wantStdout)
+			}
+			if !strings.Contains(stderr, tt.wantStderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.wantStderr)
+			}
+			for name, want := range tt.wantFiles {
+				data, err := os.ReadFile(filepath.Join(out, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !strings.Contains(string(data), want) {
+					t.Errorf("%s = %q, want it to contain %q", name, data, want)
+				}
+			}
+		})
+	}
+}
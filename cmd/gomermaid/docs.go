@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/markdown"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+const (
+	docsWriteUsage string = "rewrite the valid mermaid blocks in canonical form"
+	docsListUsage  string = "list the files with blocks not in canonical form and fail if there are any"
+	strictFlag     string = "strict"
+	strictUsage    string = "fail on Mermaid syntax the diagram models cannot check"
+	currentDir     string = "."
+)
+
+// runDocs checks the mermaid blocks of the markdown files under directories, the current
+// one by default. Syntax and validation problems fail the check; syntax the models cannot
+// represent is reported and only fails with -strict.
+func runDocs(c *cli, cmd *command, args []string) error {
+	flags := c.flags(cmd)
+	write := flags.Bool(writeFlag, false, docsWriteUsage)
+	list := flags.Bool(listFlag, false, docsListUsage)
+	strict := flags.Bool(strictFlag, false, strictUsage)
+	args, err := c.parse(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		args = []string{currentDir}
+	}
+
+	failed, differs := false, false
+	for _, dir := range args {
+		files, err := markdown.Scan(os.DirFS(dir), markdown.ScanOptions{Canonical: *write || *list})
+		if err != nil {
+			c.report(err)
+			failed = true
+			continue
+		}
+
+		for _, file := range files {
+			path := filepath.Join(dir, filepath.FromSlash(file.Path))
+
+			for _, problem := range file.Problems {
+				problem.File = path
+				c.report(problem)
+				if *strict || !errors.Is(problem, basediagram.ErrUnsupported) {
+					failed = true
+				}
+			}
+
+			if len(file.Stale) == 0 {
+				continue
+			}
+
+			differs = true
+			if *list {
+				fmt.Fprintln(c.stdout, path)
+			}
+			if *write {
+				if err := rewrite(path, file.Canonical); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if failed || (differs && *list && !*write) {
+		return errFailed
+	}
+
+	return nil
+}
+
+// rewrite replaces the content of a file, keeping its permissions.
+func rewrite(path string, content string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(content), info.Mode().Perm())
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDocs(t *testing.T) {
+	const (
+		valid       = "# Flow\n```mermaid\nflowchart TB\n    A --> B\n```\n"
+		unsupported = "```mermaid\nsequenceDiagram\n    loop Every minute\n        A->>B: ping\n    end\n```\n"
+		invalid     = "text\n```mermaid\nflowchart TB\n    A -->\n```\n"
+	)
+
+	tests := []struct {
+		name       string
+		files      map[string]string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr []string
+		wantFile   string
+	}{
+		{
+			name:     "Valid blocks",
+			files:    map[string]string{"README.md": valid},
+			wantCode: exitOK,
+			wantFile: valid,
+		},
+		{
+			name:       "Syntax error",
+			files:      map[string]string{"README.md": valid, "docs/guide.md": invalid},
+			wantCode:   exitFailure,
+			wantStderr: []string{filepath.Join("docs", "guide.md") + ":4: syntax error: expected node ID at column 6\n"},
+		},
+		{
+			name:       "Unsupported syntax is reported",
+			files:      map[string]string{"README.md": unsupported},
+			wantCode:   exitOK,
+			wantStderr: []string{"README.md:3: unsupported syntax: loop Every minute\n"},
+		},
+		{
+			name:       "Unsupported syntax fails when strict",
+			files:      map[string]string{"README.md": unsupported},
+			args:       []string{"-strict"},
+			wantCode:   exitFailure,
+			wantStderr: []string{"README.md:3: unsupported syntax: loop Every minute\n"},
+		},
+		{
+			name:       "List blocks not in canonical form",
+			files:      map[string]string{"README.md": valid},
+			args:       []string{"-l"},
+			wantCode:   exitFailure,
+			wantStdout: "README.md\n",
+			wantFile:   valid,
+		},
+		{
+			name:     "Rewrite in canonical form",
+			files:    map[string]string{"README.md": valid},
+			args:     []string{"-w"},
+			wantCode: exitOK,
+			wantFile: "# Flow\n```mermaid\nflowchart TB\n    A@{ shape: rect, label: \"A\"}\n    B@{ shape: rect, label: \"B\"}\n    A --> B\n```\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeFiles(t, tt.files)
+
+			code, stdout, stderr := runCLI(t, "", append(append([]string{"docs"}, tt.args...), dir)...)
+			if code != tt.wantCode {
+				t.Fatalf("run() = %d, want %d (stderr: %s)", code, tt.wantCode, stderr)
+			}
+
+			wantStdout := ""
+			if tt.wantStdout != "" {
+				wantStdout = filepath.Join(dir, tt.wantStdout)
+			}
+			if strings.TrimSuffix(stdout, "\n") != strings.TrimSuffix(wantStdout, "\n") {
+				t.Errorf("stdout = %q, want %q", stdout, wantStdout)
+			}
+			for _, want := range tt.wantStderr {
+				if !strings.Contains(stderr, filepath.Join(dir, want)) {
+					t.Errorf("stderr = %q, want it to contain %q", stderr, filepath.Join(dir, want))
+				}
+			}
+			if len(tt.wantStderr) == 0 && stderr != "" {
+				t.Errorf("stderr = %q, want nothing", stderr)
+			}
+
+			if tt.wantFile == "" {
+				return
+			}
+			data, err := os.ReadFile(filepath.Join(dir, "README.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.wantFile {
+				t.Errorf("README.md = %q, want %q", data, tt.wantFile)
+			}
+		})
+	}
+}
+
+func TestDocsMissingDirectory(t *testing.T) {
+	code, _, stderr := runCLI(t, "", "docs", filepath.Join(t.TempDir(), "missing"))
+	if code != exitFailure || stderr == "" {
+		t.Errorf("run() = %d, %q, want a failure", code, stderr)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/serialize"
+	"github.com/TyphonHill/go-mermaid/diagrams/spec"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	jsonExtension string = ".json"
+	jsonStart     string = "{"
+
+	writeFlag        string = "w"
+	writeUsage       string = "write the canonical form back to the files"
+	listFlag         string = "l"
+	listUsage        string = "list the files whose formatting differs and fail if there are any"
+	stdinWriteString string = "cannot write standard input in place"
+
+	documentTypeMember string = "type"
+	specNameMember     string = "name"
+	specDiagramsMember string = "diagrams"
+)
+
+// errSpecInPlace is returned by fmt -w and -l for spec files, which are formatted to their
+// diagrams in Mermaid syntax.
+var errSpecInPlace = errors.New("spec files cannot be formatted in place")
+
+// runFmt writes Mermaid files in their canonical form: the rendering of the diagram they
+// parse to, keeping the frontmatter members they set. Diagram documents are written in the member order and layout produced by
+// encoding their model, in JSON for .json files and JSON input, YAML otherwise. The
+// diagrams of spec files are written in canonical Mermaid syntax.
+func runFmt(c *cli, cmd *command, args []string) error {
+	flags := c.flags(cmd)
+	write := flags.Bool(writeFlag, false, writeUsage)
+	list := flags.Bool(listFlag, false, listUsage)
+	args, err := c.parse(flags, args)
+	if err != nil {
+		return err
+	}
+
+	inputs, err := c.readInputs(args)
+	if err != nil {
+		return err
+	}
+
+	failed, differs := false, false
+	for _, in := range inputs {
+		if *write && in.name == stdinName {
+			return c.usageError(flags, stdinWriteString)
+		}
+
+		formatted, err := format(in, *write || *list)
+		if err != nil {
+			c.report(err)
+			failed = true
+			continue
+		}
+
+		if !*write && !*list {
+			if _, err := c.stdout.Write(formatted); err != nil {
+				return err
+			}
+			continue
+		}
+		if bytes.Equal(formatted, in.data) {
+			continue
+		}
+
+		differs = true
+		if *list {
+			fmt.Fprintln(c.stdout, in.name)
+		}
+		if *write {
+			if err := os.WriteFile(in.name, formatted, 0644); err != nil {
+				return err
+			}
+		}
+	}
+
+	if failed || (differs && *list && !*write) {
+		return errFailed
+	}
+
+	return nil
+}
+
+// format returns the canonical form of Mermaid source, of a diagram document, or of the
+// diagrams of a spec file unless the result replaces the file.
+func format(in input, inPlace bool) ([]byte, error) {
+	if spec.IsMermaid(in.name, in.data) {
+		return formatMermaid(in)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(in.data, &root); err != nil {
+		return nil, &spec.Error{File: in.name, Err: err}
+	}
+
+	s, err := spec.Parse(in.name, in.data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(root.Content) > 0 && isDocument(root.Content[0]) && len(s.Diagrams) == 1 {
+		model := s.Diagrams[0].Model
+		if strings.EqualFold(filepath.Ext(in.name), jsonExtension) ||
+			(in.name == stdinName && strings.HasPrefix(strings.TrimSpace(string(in.data)), jsonStart)) {
+			return serialize.MarshalJSON(model)
+		}
+		return serialize.MarshalYAML(model)
+	}
+
+	if inPlace {
+		return nil, &spec.Error{File: in.name, Err: errSpecInPlace}
+	}
+	outputs := make([]output, 0, len(s.Diagrams))
+	for _, diagram := range s.Diagrams {
+		outputs = append(outputs, output{name: diagram.Name + spec.FileExtension, content: diagram.Model.String()})
+	}
+
+	return []byte(joinOutputs(outputs)), nil
+}
+
+// formatMermaid returns the canonical form of Mermaid source, see serialize.Format. Syntax
+// errors are reported at their line of the input.
+func formatMermaid(in input) ([]byte, error) {
+	formatted, err := serialize.Format(string(in.data))
+	if err != nil {
+		return nil, spec.Locate(in.name, err)
+	}
+
+	return []byte(formatted), nil
+}
+
+// isDocument reports whether a YAML node is a diagram document without a spec name.
+func isDocument(node *yaml.Node) bool {
+	if node.Kind != yaml.MappingNode {
+		return false
+	}
+
+	hasType := false
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		switch node.Content[i].Value {
+		case documentTypeMember:
+			hasType = true
+		case specNameMember, specDiagramsMember:
+			return false
+		}
+	}
+
+	return hasType
+}
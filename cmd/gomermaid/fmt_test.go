@@ -0,0 +1,179 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const canonicalFlowchart = `version: 1
+type: flowchart
+direction: TB
+nodes:
+  - id: a
+    text: A
+  - id: b
+    text: B
+links:
+  - from: a
+    to: b
+`
+
+const (
+	flowchartSource    = "flowchart TB\n    a[A] --> b[B]\n"
+	canonicalMermaid   = "flowchart TB\n    a@{ shape: rect, label: \"A\"}\n    b@{ shape: rect, label: \"B\"}\n    a --> b\n"
+	canonicalFirstSpec = "%% first.mmd\n---\nconfig:\n    theme: default\n    maxTextSize: 50000\n    maxEdges: 500\n    fontSize: 16\n---\nflowchart TB\n    x@{ shape: rect, label: \"\"}\n"
+)
+
+func TestFmt(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		stdin      string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+		wantFiles  map[string]string
+	}{
+		{
+			name:       "YAML document",
+			files:      map[string]string{"a.yaml": flowchartDocument},
+			args:       []string{"a.yaml"},
+			wantCode:   exitOK,
+			wantStdout: canonicalFlowchart,
+		},
+		{
+			name:       "JSON from standard input",
+			stdin:      `{"type": "timeline", "title": "History"}`,
+			wantCode:   exitOK,
+			wantStdout: "{\n  \"version\": 1,\n  \"type\": \"timeline\",\n  \"title\": \"History\"\n}\n",
+		},
+		{
+			name:       "List unformatted files",
+			files:      map[string]string{"a.yaml": flowchartDocument, "b.yaml": canonicalFlowchart},
+			args:       []string{"-l", "a.yaml", "b.yaml"},
+			wantCode:   exitFailure,
+			wantStdout: "a.yaml\n",
+		},
+		{
+			name:       "List formatted files",
+			files:      map[string]string{"b.yaml": canonicalFlowchart},
+			args:       []string{"-l", "b.yaml"},
+			wantCode:   exitOK,
+			wantStdout: "",
+		},
+		{
+			name:       "Write in place",
+			files:      map[string]string{"a.yaml": flowchartDocument},
+			args:       []string{"-w", "a.yaml"},
+			wantCode:   exitOK,
+			wantStdout: "",
+			wantFiles:  map[string]string{"a.yaml": canonicalFlowchart},
+		},
+		{
+			name:       "Write standard input",
+			stdin:      flowchartDocument,
+			args:       []string{"-w"},
+			wantCode:   exitUsage,
+			wantStderr: "gomermaid: cannot write standard input in place\n",
+		},
+		{
+			name:       "Mermaid file",
+			files:      map[string]string{"a.mmd": flowchartSource},
+			args:       []string{"a.mmd"},
+			wantCode:   exitOK,
+			wantStdout: canonicalMermaid,
+		},
+		{
+			name:       "Mermaid frontmatter is kept as written",
+			files:      map[string]string{"a.mmd": "---\ntitle: Orders\nconfig:\n  theme: dark\n---\n" + flowchartSource},
+			args:       []string{"a.mmd"},
+			wantCode:   exitOK,
+			wantStdout: "---\ntitle: Orders\nconfig:\n    theme: dark\n---\n" + canonicalMermaid,
+		},
+		{
+			name:       "Mermaid from standard input",
+			stdin:      flowchartSource,
+			wantCode:   exitOK,
+			wantStdout: canonicalMermaid,
+		},
+		{
+			name:       "Write Mermaid file in place",
+			files:      map[string]string{"a.mmd": flowchartSource, "b.mermaid": canonicalMermaid},
+			args:       []string{"-w", "-l", "a.mmd", "b.mermaid"},
+			wantCode:   exitOK,
+			wantStdout: "a.mmd\n",
+			wantFiles:  map[string]string{"a.mmd": canonicalMermaid, "b.mermaid": canonicalMermaid},
+		},
+		{
+			name:       "Mermaid syntax error",
+			files:      map[string]string{"a.mmd": "%% orders\nflowchart TB\n    a -->\n"},
+			args:       []string{"a.mmd"},
+			wantCode:   exitFailure,
+			wantStderr: "a.mmd:3: syntax error: expected node ID",
+		},
+		{
+			name:       "Mermaid diagram type not parsed",
+			files:      map[string]string{"a.mmd": "pie\n    \"A\": 1\n"},
+			args:       []string{"a.mmd"},
+			wantCode:   exitFailure,
+			wantStderr: "a.mmd: diagram type not parsed \"pie\"\n",
+		},
+		{
+			name:       "Spec file",
+			files:      map[string]string{"spec.yaml": twoDiagramSpec},
+			args:       []string{"spec.yaml"},
+			wantCode:   exitOK,
+			wantStdout: canonicalFirstSpec + "\n%% second.mmd\n---\nconfig:\n    theme: default\n    maxTextSize: 50000\n    maxEdges: 500\n    fontSize: 16\n---\nstateDiagram-v2\n",
+		},
+		{
+			name:       "Spec file in place",
+			files:      map[string]string{"spec.yaml": twoDiagramSpec},
+			args:       []string{"-l", "spec.yaml"},
+			wantCode:   exitFailure,
+			wantStderr: "spec.yaml: spec files cannot be formatted in place\n",
+		},
+		{
+			name:       "Invalid document",
+			files:      map[string]string{"a.yaml": "type: flowchart\nlinks:\n  - {from: a, to: b}\n"},
+			args:       []string{"a.yaml"},
+			wantCode:   exitFailure,
+			wantStderr: "a.yaml:3: unknown reference: node \"a\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeFiles(t, tt.files)
+			args := []string{"fmt"}
+			for _, arg := range tt.args {
+				if _, ok := tt.files[arg]; ok {
+					arg = filepath.Join(dir, arg)
+				}
+				args = append(args, arg)
+			}
+
+			code, stdout, stderr := runCLI(t, tt.stdin, args...)
+			if code != tt.wantCode {
+				t.Fatalf("run() = %d, want %d (stderr: %s)", code, tt.wantCode, stderr)
+			}
+			if got := strings.ReplaceAll(stdout, dir+string(filepath.Separator), ""); got != tt.wantStdout {
+				t.Errorf("stdout = %q, want %q", got, tt.wantStdout)
+			}
+			if !strings.Contains(stderr, tt.wantStderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.wantStderr)
+			}
+			for name, want := range tt.wantFiles {
+				data, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != want {
+					t.Errorf("%s = %q, want %q", name, data, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/TyphonHill/go-mermaid/diagrams/spec"
+)
+
+// runGenerate writes the Mermaid syntax of every diagram. Nothing is written when an input
+// is invalid.
+func runGenerate(c *cli, cmd *command, args []string) error {
+	flags := c.flags(cmd)
+	dir := flags.String(outputDirFlag, "", outputDirUsage)
+	args, err := c.parse(flags, args)
+	if err != nil {
+		return err
+	}
+
+	diagrams, failed, err := c.loadDiagrams(args)
+	if err != nil {
+		return err
+	}
+	if failed {
+		return errFailed
+	}
+
+	outputs := make([]output, 0, len(diagrams))
+	for _, diagram := range diagrams {
+		outputs = append(outputs, output{name: diagram.Name + spec.FileExtension, content: diagram.Model.String()})
+	}
+
+	return c.writeOutputs(*dir, outputs)
+}
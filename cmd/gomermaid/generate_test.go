@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"spec.yaml":      twoDiagramSpec,
+		"orders.yaml":    flowchartDocument,
+		"duplicate.yaml": "diagrams:\n  - {name: orders, type: timeline}\n",
+		"invalid.yaml":   "diagrams:\n  - {name: bad, type: flowchart, nodes: [{id: a, shape: blob}]}\n",
+	})
+	out := filepath.Join(dir, "out")
+
+	tests := []struct {
+		name       string
+		stdin      string
+		args       []string
+		wantCode   int
+		wantStdout []string
+		wantStderr string
+		wantFiles  []string
+	}{
+		{
+			name:       "Document to standard output",
+			args:       []string{filepath.Join(dir, "orders.yaml")},
+			wantCode:   exitOK,
+			wantStdout: []string{"flowchart TB\n", "a --> b\n"},
+		},
+		{
+			name:       "Standard input",
+			stdin:      sequenceDocument,
+			wantCode:   exitOK,
+			wantStdout: []string{"sequenceDiagram\n"},
+		},
+		{
+			name:       "Several diagrams to standard output",
+			args:       []string{filepath.Join(dir, "spec.yaml")},
+			wantCode:   exitOK,
+			wantStdout: []string{"%% first.mmd\n", "\n%% second.mmd\n", "stateDiagram-v2\n"},
+		},
+		{
+			name:      "Output directory",
+			args:      []string{"-o", out, filepath.Join(dir, "spec.yaml"), filepath.Join(dir, "orders.yaml")},
+			wantCode:  exitOK,
+			wantFiles: []string{"first.mmd", "second.mmd", "orders.mmd"},
+		},
+		{
+			name:       "Duplicate name across files",
+			args:       []string{filepath.Join(dir, "orders.yaml"), filepath.Join(dir, "duplicate.yaml")},
+			wantCode:   exitFailure,
+			wantStderr: `duplicate.yaml:2: diagram "orders" already defined at ` + filepath.Join(dir, "orders.yaml") + ":1\n",
+		},
+		{
+			name:       "Invalid input",
+			args:       []string{filepath.Join(dir, "invalid.yaml")},
+			wantCode:   exitFailure,
+			wantStderr: `invalid.yaml:2: invalid document: document.nodes[0].shape: value "blob" is not one of`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, tt.stdin, append([]string{"generate"}, tt.args...)...)
+			if code != tt.wantCode {
+				t.Fatalf("run() = %d, want %d (stderr: %s)", code, tt.wantCode, stderr)
+			}
+			for _, want := range tt.wantStdout {
+				if !strings.Contains(stdout, want) {
+					t.Errorf("stdout = %q, want it to contain %q", stdout, want)
+				}
+			}
+			if !strings.Contains(stderr, tt.wantStderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.wantStderr)
+			}
+			if tt.wantCode != exitOK && stdout != "" {
+				t.Errorf("stdout = %q, want nothing written on failure", stdout)
+			}
+			for _, name := range tt.wantFiles {
+				data, err := os.ReadFile(filepath.Join(out, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !strings.HasPrefix(string(data), "---\n") {
+					t.Errorf("%s = %q, want a Mermaid diagram", name, data)
+				}
+			}
+		})
+	}
+}
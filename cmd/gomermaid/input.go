@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/spec"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+)
+
+const (
+	stdinArg  string = "-"
+	stdinName string = "stdin"
+
+	locationString  string = "%s:%d"
+	duplicateString string = "%s: diagram %q already defined at %s"
+	headerString    string = "%%%% %s\n"
+	outputDirFlag   string = "o"
+	outputDirUsage  string = "write one file per diagram to this directory instead of standard output"
+)
+
+// errStdinTwice is returned when standard input is given more than once.
+var errStdinTwice = errors.New("standard input given more than once")
+
+// input is a file given on the command line.
+type input struct {
+	name string
+	data []byte
+}
+
+// output is a file produced by a command.
+type output struct {
+	name    string
+	content string
+}
+
+// readInputs reads the files given on the command line, or standard input if there are none.
+func (c *cli) readInputs(args []string) ([]input, error) {
+	if len(args) == 0 {
+		args = []string{stdinArg}
+	}
+
+	inputs := make([]input, 0, len(args))
+	for _, arg := range args {
+		if arg != stdinArg {
+			data, err := os.ReadFile(arg)
+			if err != nil {
+				return nil, err
+			}
+			inputs = append(inputs, input{name: arg, data: data})
+			continue
+		}
+
+		if c.stdinUsed {
+			return nil, errStdinTwice
+		}
+		c.stdinUsed = true
+		data, err := io.ReadAll(c.stdin)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, input{name: stdinName, data: data})
+	}
+
+	return inputs, nil
+}
+
+// loadDiagrams reads the inputs and returns the diagrams they describe. Invalid inputs and
+// diagrams named like a diagram of another input are reported, and failed is set.
+func (c *cli) loadDiagrams(args []string) (diagrams []*spec.Diagram, failed bool, err error) {
+	inputs, err := c.readInputs(args)
+	if err != nil {
+		return nil, false, err
+	}
+
+	names := make(map[string]*spec.Diagram)
+	for _, in := range inputs {
+		s, err := spec.Parse(in.name, in.data)
+		if err != nil {
+			c.report(err)
+			failed = true
+			continue
+		}
+
+		for _, diagram := range s.Diagrams {
+			if previous := names[diagram.Name]; previous != nil {
+				c.report(fmt.Errorf(duplicateString, location(diagram), diagram.Name, location(previous)))
+				failed = true
+				continue
+			}
+			names[diagram.Name] = diagram
+			diagrams = append(diagrams, diagram)
+		}
+	}
+
+	return diagrams, failed, nil
+}
+
+// writeOutputs writes each output to a file of the directory, or else to standard output,
+// preceded by a comment naming it when there are several.
+func (c *cli) writeOutputs(dir string, outputs []output) error {
+	if dir != "" {
+		for _, out := range outputs {
+			if err := utils.RenderToFile(filepath.Join(dir, out.name), out.content); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	_, err := io.WriteString(c.stdout, joinOutputs(outputs))
+	return err
+}
+
+// joinOutputs returns the content of the outputs, each preceded by a comment naming it when
+// there are several.
+func joinOutputs(outputs []output) string {
+	var sb strings.Builder
+	for i, out := range outputs {
+		if len(outputs) > 1 {
+			if i > 0 {
+				sb.WriteByte('\n')
+			}
+			sb.WriteString(fmt.Sprintf(headerString, out.name))
+		}
+		sb.WriteString(out.content)
+		if !strings.HasSuffix(out.content, "\n") {
+			sb.WriteByte('\n')
+		}
+	}
+
+	return sb.String()
+}
+
+// location returns the file and line a diagram was declared at.
+func location(diagram *spec.Diagram) string {
+	return fmt.Sprintf(locationString, diagram.File, diagram.Line)
+}
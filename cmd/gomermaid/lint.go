@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/block"
+	"github.com/TyphonHill/go-mermaid/diagrams/class"
+	"github.com/TyphonHill/go-mermaid/diagrams/entityrelationship"
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/sequence"
+	"github.com/TyphonHill/go-mermaid/diagrams/serialize"
+	"github.com/TyphonHill/go-mermaid/diagrams/state"
+	"github.com/TyphonHill/go-mermaid/diagrams/timeline"
+	"github.com/TyphonHill/go-mermaid/diagrams/userjourney"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+const (
+	textSizeString string = "%s: diagram %q is %d characters long, more than the limit of %d"
+	edgesString    string = "%s: diagram %q has %d edges, more than the limit of %d"
+)
+
+// runLint reports invalid inputs and diagrams Mermaid would refuse to render because they
+// exceed the text size or edge limits of their configuration.
+func runLint(c *cli, cmd *command, args []string) error {
+	flags := c.flags(cmd)
+	args, err := c.parse(flags, args)
+	if err != nil {
+		return err
+	}
+
+	diagrams, failed, err := c.loadDiagrams(args)
+	if err != nil {
+		return err
+	}
+
+	for _, diagram := range diagrams {
+		edges, config := limits(diagram.Model)
+		if config == nil {
+			continue
+		}
+
+		if size := len(diagram.Model.String()); size > config.MaxTextSize() {
+			c.report(fmt.Errorf(textSizeString, location(diagram), diagram.Name, size, config.MaxTextSize()))
+			failed = true
+		}
+		if edges > config.MaxEdges() {
+			c.report(fmt.Errorf(edgesString, location(diagram), diagram.Name, edges, config.MaxEdges()))
+			failed = true
+		}
+	}
+
+	if failed {
+		return errFailed
+	}
+
+	return nil
+}
+
+// limits returns the number of edges of a diagram and the configuration holding its limits.
+func limits(model serialize.Diagram) (edges int, config *basediagram.ConfigurationProperties) {
+	switch d := model.(type) {
+	case *flowchart.Flowchart:
+		return len(d.Links()), &d.Config.ConfigurationProperties
+	case *state.Diagram:
+		return len(d.Transitions), &d.Config.ConfigurationProperties
+	case *class.ClassDiagram:
+		return len(d.Relations()), &d.Config.ConfigurationProperties
+	case *entityrelationship.Diagram:
+		return len(d.Relationships), &d.Config.ConfigurationProperties
+	case *block.Diagram:
+		return len(d.Links), &d.Config.ConfigurationProperties
+	case *sequence.Diagram:
+		return 0, &d.Config.ConfigurationProperties
+	case *timeline.Diagram:
+		return 0, &d.Config.ConfigurationProperties
+	case *userjourney.Diagram:
+		return 0, &d.Config.ConfigurationProperties
+	}
+
+	return 0, nil
+}
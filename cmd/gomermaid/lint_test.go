@@ -0,0 +1,99 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// chainDocument is a flowchart of four nodes linked in a chain, limited to two edges.
+const chainDocument = `type: flowchart
+config: {maxEdges: 2}
+nodes: [{id: a}, {id: b}, {id: c}, {id: d}]
+links:
+  - {from: a, to: b}
+  - {from: b, to: c}
+  - {from: c, to: d}
+`
+
+func TestLint(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"spec.yaml":    twoDiagramSpec,
+		"chain.yaml":   chainDocument,
+		"small.yaml":   "type: timeline\ntitle: A long enough title\nconfig: {maxTextSize: 10}\n",
+		"invalid.yaml": "diagrams:\n  - name: a\n    type: state\n    statez: []\n",
+		"flow.mmd":     "---\nconfig:\n  maxEdges: 1\n---\nflowchart TB\n    a --> b\n    b --> c\n",
+		"broken.mmd":   "flowchart TB\n    a -->\n",
+	})
+
+	tests := []struct {
+		name       string
+		files      []string
+		wantCode   int
+		wantStderr []string
+	}{
+		{
+			name:     "Valid files",
+			files:    []string{"spec.yaml"},
+			wantCode: exitOK,
+		},
+		{
+			name:       "Too many edges",
+			files:      []string{"spec.yaml", "chain.yaml"},
+			wantCode:   exitFailure,
+			wantStderr: []string{"chain.yaml:1: diagram \"chain\" has 3 edges, more than the limit of 2\n"},
+		},
+		{
+			name:       "Text too long",
+			files:      []string{"small.yaml"},
+			wantCode:   exitFailure,
+			wantStderr: []string{"small.yaml:1: diagram \"small\" is ", " characters long, more than the limit of 10\n"},
+		},
+		{
+			name:       "Mermaid file",
+			files:      []string{"flow.mmd"},
+			wantCode:   exitFailure,
+			wantStderr: []string{"flow.mmd:1: diagram \"flow\" has 2 edges, more than the limit of 1\n"},
+		},
+		{
+			name:       "Mermaid syntax error",
+			files:      []string{"broken.mmd"},
+			wantCode:   exitFailure,
+			wantStderr: []string{"broken.mmd:2: syntax error: expected node ID"},
+		},
+		{
+			name:     "Every problem is reported",
+			files:    []string{"invalid.yaml", "chain.yaml"},
+			wantCode: exitFailure,
+			wantStderr: []string{
+				"invalid.yaml:4: invalid document: document: unknown member \"statez\"\n",
+				"chain.yaml:1: diagram \"chain\" has 3 edges",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := []string{"lint"}
+			for _, name := range tt.files {
+				args = append(args, filepath.Join(dir, name))
+			}
+
+			code, stdout, stderr := runCLI(t, "", args...)
+			if code != tt.wantCode {
+				t.Fatalf("run() = %d, want %d (stderr: %s)", code, tt.wantCode, stderr)
+			}
+			if stdout != "" {
+				t.Errorf("stdout = %q, want nothing", stdout)
+			}
+			if len(tt.wantStderr) == 0 && stderr != "" {
+				t.Errorf("stderr = %q, want nothing", stderr)
+			}
+			for _, want := range tt.wantStderr {
+				if !strings.Contains(stderr, want) {
+					t.Errorf("stderr = %q, want it to contain %q", stderr, want)
+				}
+			}
+		})
+	}
+}
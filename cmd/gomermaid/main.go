@@ -0,0 +1,190 @@
+// Command gomermaid generates, formats, checks, converts and splits the diagrams described
+// by spec files and diagram documents, checks the diagrams of markdown documentation and
+// regenerates diagrams as their spec files change.
+//
+// Usage:
+//
+//	gomermaid <command> [flags] [files]
+//
+// The commands are:
+//
+//	generate  write the Mermaid files of the diagrams
+//	fmt       rewrite Mermaid files and diagram documents in their canonical form
+//	lint      check the diagrams for errors and exceeded Mermaid limits
+//	convert   convert the diagrams to DOT, PlantUML, JSON, YAML, SVG and other formats
+//	split     split oversized flowcharts and entity relationship diagrams
+//	markdown  regenerate the diagrams embedded in markdown files, see package markdown
+//	docs      check the mermaid blocks of the markdown files under directories
+//	watch     regenerate the diagrams of spec files when they change, with a live preview
+//
+// Files are spec files, single diagram documents in YAML or JSON, or Mermaid files of the
+// diagram types the diagram packages parse, see packages spec and serialize. Standard input
+// is read when no file or "-" is given. Results are written to standard output, or to the
+// directory given by -o. The docs command checks the Mermaid blocks of markdown files
+// instead, see markdown.Scan. The watch command runs until interrupted, see package watch.
+//
+// The exit status is 0 on success, 1 when an input is invalid, a check fails or an output
+// cannot be written, and 2 when the command line is invalid.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const programName string = "gomermaid"
+
+// Exit statuses.
+const (
+	exitOK      int = 0
+	exitFailure int = 1
+	exitUsage   int = 2
+)
+
+const (
+	usageString        string = "usage: %s <command> [flags] [files]\n\ncommands:\n"
+	usageCommandString string = "  %-9s %s\n"
+	usageFooterString  string = "\nRun '%s <command> -h' for the flags of a command.\n"
+	commandUsageString string = "usage: %s %s [flags] %s\n\n%s.\n\nflags:\n"
+	errorString        string = "%s: %v\n"
+	unknownCommand     string = "unknown command %q"
+	helpCommand        string = "help"
+)
+
+var (
+	// errUsage is returned for invalid command lines, once the error has been printed.
+	errUsage = errors.New("invalid usage")
+	// errFailed is returned when the problems of the inputs have been reported.
+	errFailed = errors.New("failed")
+)
+
+// command is a subcommand of the tool.
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(c *cli, cmd *command, args []string) error
+}
+
+// commands lists the subcommands in the order of the usage message.
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{name: "generate", args: "[files]", summary: "Write the Mermaid files of the diagrams", run: runGenerate},
+		{name: "fmt", args: "[files]", summary: "Rewrite Mermaid files and diagram documents in their canonical form", run: runFmt},
+		{name: "lint", args: "[files]", summary: "Check the diagrams for errors and exceeded Mermaid limits", run: runLint},
+		{name: "convert", args: "-to format [files]", summary: "Convert the diagrams to another format", run: runConvert},
+		{name: "split", args: "[files]", summary: "Split oversized flowcharts and entity relationship diagrams", run: runSplit},
+		{name: "markdown", args: "-from file markdown-files", summary: "Regenerate the diagrams embedded in markdown files", run: runMarkdown},
+		{name: "docs", args: "[directories]", summary: "Check the mermaid blocks of markdown files for Mermaid errors", run: runDocs},
+		{name: "watch", args: "-o dir | -http addr spec-files", summary: "Regenerate the diagrams of spec files when they change", run: runWatch},
+	}
+}
+
+// cli holds the standard streams of a run of the tool.
+type cli struct {
+	stdin     io.Reader
+	stdout    io.Writer
+	stderr    io.Writer
+	stdinUsed bool
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command line and returns the exit status.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	c := &cli{stdin: stdin, stdout: stdout, stderr: stderr}
+
+	if len(args) == 0 {
+		c.usage(stderr)
+		return exitUsage
+	}
+
+	name := args[0]
+	if name == helpCommand || name == "-h" || name == "-help" || name == "--help" {
+		c.usage(stdout)
+		return exitOK
+	}
+
+	var cmd *command
+	for _, candidate := range commands {
+		if candidate.name == name {
+			cmd = candidate
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(stderr, errorString, programName, fmt.Sprintf(unknownCommand, name))
+		c.usage(stderr)
+		return exitUsage
+	}
+
+	err := cmd.run(c, cmd, args[1:])
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.Is(err, errUsage):
+		return exitUsage
+	case errors.Is(err, errFailed):
+		return exitFailure
+	}
+
+	fmt.Fprintf(stderr, errorString, programName, err)
+	return exitFailure
+}
+
+// usage writes the list of commands.
+func (c *cli) usage(w io.Writer) {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf(usageString, programName))
+	for _, cmd := range commands {
+		sb.WriteString(fmt.Sprintf(usageCommandString, cmd.name, cmd.summary))
+	}
+	sb.WriteString(fmt.Sprintf(usageFooterString, programName))
+
+	io.WriteString(w, sb.String())
+}
+
+// flags returns the flag set of a command, printing its errors and usage to standard error.
+func (c *cli) flags(cmd *command) *flag.FlagSet {
+	flags := flag.NewFlagSet(programName+" "+cmd.name, flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(c.stderr, commandUsageString, programName, cmd.name, cmd.args, cmd.summary)
+		flags.PrintDefaults()
+	}
+
+	return flags
+}
+
+// parse parses the flags of a command and returns the remaining arguments.
+func (c *cli) parse(flags *flag.FlagSet, args []string) ([]string, error) {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, errUsage
+	}
+
+	return flags.Args(), nil
+}
+
+// usageError prints an invalid use of a command followed by its usage.
+func (c *cli) usageError(flags *flag.FlagSet, format string, args ...interface{}) error {
+	fmt.Fprintf(c.stderr, errorString, programName, fmt.Sprintf(format, args...))
+	flags.Usage()
+
+	return errUsage
+}
+
+// report prints a problem found in the inputs.
+func (c *cli) report(err error) {
+	fmt.Fprintln(c.stderr, err)
+}
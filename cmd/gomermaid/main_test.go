@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const flowchartDocument = `version: 1
+type: flowchart
+nodes:
+  - {id: a, text: A}
+  - {id: b, text: B}
+links:
+  - {from: a, to: b}
+`
+
+const sequenceDocument = `{"type": "sequence", "actors": [{"id": "u", "name": "User"}]}`
+
+const twoDiagramSpec = `diagrams:
+  - name: first
+    type: flowchart
+    nodes: [{id: x}]
+  - name: second
+    type: state
+`
+
+// runCLI runs the tool and returns its exit status and outputs.
+func runCLI(t *testing.T, stdin string, args ...string) (code int, stdout string, stderr string) {
+	t.Helper()
+
+	var out, errOut bytes.Buffer
+	code = run(args, strings.NewReader(stdin), &out, &errOut)
+
+	return code, out.String(), errOut.String()
+}
+
+// writeFiles creates files with the given content in a temporary directory and returns it.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "No command",
+			args:       nil,
+			wantCode:   exitUsage,
+			wantStderr: "usage: gomermaid <command>",
+		},
+		{
+			name:       "Help",
+			args:       []string{"help"},
+			wantCode:   exitOK,
+			wantStdout: "  convert   Convert the diagrams to another format\n",
+		},
+		{
+			name:       "Unknown command",
+			args:       []string{"draw"},
+			wantCode:   exitUsage,
+			wantStderr: "gomermaid: unknown command \"draw\"\nusage:",
+		},
+		{
+			name:       "Command help",
+			args:       []string{"generate", "-h"},
+			wantCode:   exitOK,
+			wantStderr: "usage: gomermaid generate [flags] [files]",
+		},
+		{
+			name:       "Unknown flag",
+			args:       []string{"lint", "-x"},
+			wantCode:   exitUsage,
+			wantStderr: "flag provided but not defined: -x",
+		},
+		{
+			name:       "Missing file",
+			args:       []string{"lint", "missing.yaml"},
+			wantCode:   exitFailure,
+			wantStderr: "gomermaid: open missing.yaml: no such file or directory\n",
+		},
+		{
+			name:       "Standard input given twice",
+			args:       []string{"lint", "-", "-"},
+			wantCode:   exitFailure,
+			wantStderr: "gomermaid: standard input given more than once\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, "", tt.args...)
+			if code != tt.wantCode {
+				t.Errorf("run() = %d, want %d (stderr: %s)", code, tt.wantCode, stderr)
+			}
+			if !strings.Contains(stdout, tt.wantStdout) {
+				t.Errorf("stdout = %q, want it to contain %q", stdout, tt.wantStdout)
+			}
+			if !strings.Contains(stderr, tt.wantStderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.wantStderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/markdown"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+const (
+	fromFlag      string = "from"
+	fromUsage     string = "spec file or diagram document providing the diagrams (repeatable, - for standard input)"
+	checkFlag     string = "check"
+	checkUsage    string = "report stale blocks and fail instead of updating the files"
+	missingFrom   string = "missing -from"
+	missingFiles  string = "missing markdown files"
+	staleString   string = "%s:%d: block %q is stale"
+	listSeparator string = ","
+)
+
+// stringList is a flag that may be given several times.
+type stringList []string
+
+// String returns the values of the flag.
+func (l *stringList) String() string {
+	return strings.Join(*l, listSeparator)
+}
+
+// Set adds a value to the flag.
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// runMarkdown regenerates the diagrams embedded between marker comments in markdown files,
+// or with -check reports the stale ones without writing.
+func runMarkdown(c *cli, cmd *command, args []string) error {
+	var from stringList
+	flags := c.flags(cmd)
+	flags.Var(&from, fromFlag, fromUsage)
+	check := flags.Bool(checkFlag, false, checkUsage)
+	args, err := c.parse(flags, args)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(from) == 0:
+		return c.usageError(flags, missingFrom)
+	case len(args) == 0:
+		return c.usageError(flags, missingFiles)
+	}
+
+	loaded, failed, err := c.loadDiagrams(from)
+	if err != nil {
+		return err
+	}
+	if failed {
+		return errFailed
+	}
+
+	diagrams := make(map[string]basediagram.Diagram, len(loaded))
+	for _, diagram := range loaded {
+		diagrams[diagram.Name] = diagram.Model
+	}
+
+	update := markdown.UpdateFile
+	if *check {
+		update = markdown.CheckFile
+	}
+
+	for _, path := range args {
+		stale, err := update(path, diagrams)
+		if err != nil {
+			c.report(err)
+			failed = true
+			continue
+		}
+
+		if *check {
+			for _, block := range stale {
+				c.report(fmt.Errorf(staleString, path, block.Line, block.Name))
+				failed = true
+			}
+		}
+	}
+
+	if failed {
+		return errFailed
+	}
+
+	return nil
+}
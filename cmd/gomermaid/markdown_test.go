@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMarkdown(t *testing.T) {
+	const stale = "# Orders\n<!-- gomermaid:begin name=orders -->\n<!-- gomermaid:end -->\n"
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStderr string
+		wantFile   string
+	}{
+		{
+			name:       "Check stale blocks",
+			args:       []string{"-check", "-from", "orders.yaml", "README.md"},
+			wantCode:   exitFailure,
+			wantStderr: "README.md:2: block \"orders\" is stale\n",
+			wantFile:   stale,
+		},
+		{
+			name:     "Update",
+			args:     []string{"-from", "orders.yaml", "README.md"},
+			wantCode: exitOK,
+			wantFile: "# Orders\n<!-- gomermaid:begin name=orders -->\n```mermaid\n---\nconfig:",
+		},
+		{
+			name:       "Unknown diagram",
+			args:       []string{"-from", "spec.yaml", "README.md"},
+			wantCode:   exitFailure,
+			wantStderr: "README.md:2: unknown diagram \"orders\"\n",
+			wantFile:   stale,
+		},
+		{
+			name:       "Missing -from",
+			args:       []string{"README.md"},
+			wantCode:   exitUsage,
+			wantStderr: "gomermaid: missing -from\n",
+		},
+		{
+			name:       "Missing markdown files",
+			args:       []string{"-from", "orders.yaml"},
+			wantCode:   exitUsage,
+			wantStderr: "gomermaid: missing markdown files\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeFiles(t, map[string]string{
+				"README.md":   stale,
+				"orders.yaml": flowchartDocument,
+				"spec.yaml":   twoDiagramSpec,
+			})
+
+			args := []string{"markdown"}
+			for _, arg := range tt.args {
+				if strings.Contains(arg, ".") {
+					arg = filepath.Join(dir, arg)
+				}
+				args = append(args, arg)
+			}
+
+			code, stdout, stderr := runCLI(t, "", args...)
+			if code != tt.wantCode {
+				t.Fatalf("run() = %d, want %d (stderr: %s)", code, tt.wantCode, stderr)
+			}
+			if stdout != "" {
+				t.Errorf("stdout = %q, want nothing", stdout)
+			}
+			if !strings.Contains(stderr, tt.wantStderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.wantStderr)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, "README.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(data), tt.wantFile) {
+				t.Errorf("README.md = %q, want it to start with %q", data, tt.wantFile)
+			}
+		})
+	}
+}
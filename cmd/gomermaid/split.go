@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/entityrelationship"
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/spec"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+const (
+	strategyFlag       string = "strategy"
+	strategyUsage      string = "how to group elements: %s"
+	maxEdgesFlag       string = "max-edges"
+	maxEdgesUsage      string = "maximum number of edges of a part (default: the diagram limit)"
+	maxTextSizeFlag    string = "max-text-size"
+	maxTextSizeUsage   string = "maximum length of a part (default: the diagram limit)"
+	unknownStrategy    string = "unknown strategy %q"
+	unsplittableString string = "%s: %w: %s diagrams cannot be split"
+	partNameString     string = "%s-%d"
+	indexString        string = "%s:\n%s"
+)
+
+// errUnsplittable is returned for diagram types that cannot be split.
+var errUnsplittable = errors.New("unsupported diagram type")
+
+// strategies lists the split strategies.
+var strategies = []basediagram.SplitStrategy{
+	basediagram.SplitByComponent,
+	basediagram.SplitBySubgraph,
+	basediagram.SplitByCommunity,
+}
+
+// runSplit splits the flowcharts and entity relationship diagrams exceeding the limits.
+// Diagrams within the limits are written unchanged, the parts of the others are numbered
+// after the diagram name. The index of the parts is printed when writing to a directory.
+func runSplit(c *cli, cmd *command, args []string) error {
+	names := make([]string, 0, len(strategies))
+	for _, strategy := range strategies {
+		names = append(names, string(strategy))
+	}
+
+	flags := c.flags(cmd)
+	strategy := flags.String(strategyFlag, string(basediagram.SplitByComponent), fmt.Sprintf(strategyUsage, strings.Join(names, formatListSeparator)))
+	maxEdges := flags.Int(maxEdgesFlag, 0, maxEdgesUsage)
+	maxTextSize := flags.Int(maxTextSizeFlag, 0, maxTextSizeUsage)
+	dir := flags.String(outputDirFlag, "", outputDirUsage)
+	args, err := c.parse(flags, args)
+	if err != nil {
+		return err
+	}
+
+	options := basediagram.SplitOptions{Strategy: basediagram.SplitStrategy(*strategy), MaxEdges: *maxEdges, MaxTextSize: *maxTextSize}
+	known := false
+	for _, candidate := range strategies {
+		known = known || candidate == options.Strategy
+	}
+	if !known {
+		return c.usageError(flags, unknownStrategy, *strategy)
+	}
+
+	diagrams, failed, err := c.loadDiagrams(args)
+	if err != nil {
+		return err
+	}
+
+	var outputs []output
+	var index strings.Builder
+	for _, diagram := range diagrams {
+		parts, partIndex := split(diagram, options)
+		if parts == nil {
+			c.report(fmt.Errorf(unsplittableString, location(diagram), errUnsplittable, diagram.Type))
+			failed = true
+			continue
+		}
+
+		if len(parts) == 1 {
+			outputs = append(outputs, output{name: diagram.Name + spec.FileExtension, content: parts[0]})
+			continue
+		}
+		for i, part := range parts {
+			outputs = append(outputs, output{name: fmt.Sprintf(partNameString, diagram.Name, i+1) + spec.FileExtension, content: part})
+		}
+		index.WriteString(fmt.Sprintf(indexString, diagram.Name, partIndex))
+	}
+
+	if failed {
+		return errFailed
+	}
+
+	if err := c.writeOutputs(*dir, outputs); err != nil {
+		return err
+	}
+	if *dir != "" {
+		_, err = fmt.Fprint(c.stdout, index.String())
+	}
+
+	return err
+}
+
+// split returns the Mermaid syntax of the parts of a diagram and their index, or nil if
+// the diagram type cannot be split.
+func split(diagram *spec.Diagram, options basediagram.SplitOptions) (parts []string, index *basediagram.SplitIndex) {
+	switch d := diagram.Model.(type) {
+	case *flowchart.Flowchart:
+		charts, index := d.Split(options)
+		for _, chart := range charts {
+			parts = append(parts, chart.String())
+		}
+		return parts, index
+	case *entityrelationship.Diagram:
+		erds, index := d.Split(options)
+		for _, erd := range erds {
+			parts = append(parts, erd.String())
+		}
+		return parts, index
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"chain.yaml":  chainDocument,
+		"orders.yaml": flowchartDocument,
+		"login.json":  sequenceDocument,
+	})
+	out := filepath.Join(dir, "out")
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout []string
+		wantStderr string
+		wantFiles  []string
+	}{
+		{
+			name:       "Within the limits",
+			args:       []string{"orders.yaml"},
+			wantCode:   exitOK,
+			wantStdout: []string{"flowchart TB\n"},
+		},
+		{
+			name:       "Parts to standard output",
+			args:       []string{"chain.yaml"},
+			wantCode:   exitOK,
+			wantStdout: []string{"%% chain-1.mmd\n", "%% chain-2.mmd\n", "continued in diagram"},
+		},
+		{
+			name:       "Parts to a directory",
+			args:       []string{"-o", out, "-max-edges", "1", "orders.yaml", "chain.yaml"},
+			wantCode:   exitOK,
+			wantStdout: []string{"chain:\nDiagram 1: "},
+			wantFiles:  []string{"orders.mmd", "chain-1.mmd", "chain-2.mmd", "chain-3.mmd"},
+		},
+		{
+			name:       "Unsupported diagram type",
+			args:       []string{"login.json"},
+			wantCode:   exitFailure,
+			wantStderr: "login.json:1: unsupported diagram type: sequence diagrams cannot be split\n",
+		},
+		{
+			name:       "Unknown strategy",
+			args:       []string{"-strategy", "random", "chain.yaml"},
+			wantCode:   exitUsage,
+			wantStderr: "gomermaid: unknown strategy \"random\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := []string{"split"}
+			for _, arg := range tt.args {
+				if strings.HasSuffix(arg, ".yaml") || strings.HasSuffix(arg, ".json") {
+					arg = filepath.Join(dir, arg)
+				}
+				args = append(args, arg)
+			}
+
+			code, stdout, stderr := runCLI(t, "", args...)
+			if code != tt.wantCode {
+				t.Fatalf("run() = %d, want %d (stderr: %s)", code, tt.wantCode, stderr)
+			}
+			for _, want := range tt.wantStdout {
+				if !strings.Contains(stdout, want) {
+					t.Errorf("stdout = %q, want it to contain %q", stdout, want)
+				}
+			}
+			if !strings.Contains(stderr, tt.wantStderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.wantStderr)
+			}
+			for _, name := range tt.wantFiles {
+				if _, err := os.Stat(filepath.Join(out, name)); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/watch"
+)
+
+const (
+	httpFlag        string = "http"
+	httpUsage       string = "serve a live preview of the diagrams on this address, such as localhost:8080"
+	mermaidFlag     string = "mermaid"
+	mermaidUsage    string = "local copy of mermaid.js rendering the diagrams of the preview"
+	intervalFlag    string = "interval"
+	intervalUsage   string = "delay between two checks of the spec files"
+	missingSpecs    string = "missing spec files"
+	missingOutput   string = "missing -o or -http"
+	mermaidWithout  string = "-mermaid requires -http"
+	servingString   string = "serving the preview at http://%s/\n"
+	writtenString   string = "wrote %s\n"
+	watchStdinUsage string = "cannot watch standard input"
+)
+
+// watchContext returns the context of the watch command, done on interrupt.
+var watchContext = func() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+// runWatch regenerates the Mermaid files of the diagrams of spec files whenever the files or
+// those they include change, and optionally serves a preview reloading itself, until
+// interrupted.
+func runWatch(c *cli, cmd *command, args []string) error {
+	flags := c.flags(cmd)
+	dir := flags.String(outputDirFlag, "", outputDirUsage)
+	addr := flags.String(httpFlag, "", httpUsage)
+	mermaidJS := flags.String(mermaidFlag, "", mermaidUsage)
+	interval := flags.Duration(intervalFlag, watch.DefaultInterval, intervalUsage)
+	args, err := c.parse(flags, args)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(args) == 0:
+		return c.usageError(flags, missingSpecs)
+	case *dir == "" && *addr == "":
+		return c.usageError(flags, missingOutput)
+	case *mermaidJS != "" && *addr == "":
+		return c.usageError(flags, mermaidWithout)
+	}
+
+	generators := make([]watch.Generator, 0, len(args))
+	for _, arg := range args {
+		if arg == stdinArg {
+			return c.usageError(flags, watchStdinUsage)
+		}
+		generators = append(generators, watch.Spec(arg))
+	}
+	if *mermaidJS != "" {
+		if _, err := os.Stat(*mermaidJS); err != nil {
+			return err
+		}
+	}
+
+	w := watch.New(watch.Options{
+		Dir:      *dir,
+		Interval: *interval,
+		Written: func(path string) {
+			fmt.Fprintf(c.stdout, writtenString, path)
+		},
+		Failed: c.report,
+	}, generators...)
+
+	ctx, cancel := watchContext()
+	defer cancel()
+
+	if *addr != "" {
+		listener, err := net.Listen("tcp", *addr)
+		if err != nil {
+			return err
+		}
+		server := &http.Server{Handler: w.Handler(*mermaidJS)}
+		defer server.Close()
+		go server.Serve(listener)
+
+		fmt.Fprintf(c.stdout, servingString, listener.Addr())
+	}
+
+	if err := w.Run(ctx); !errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	return nil
+}
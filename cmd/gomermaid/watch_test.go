@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout []string
+		wantStderr string
+		wantFiles  []string
+	}{
+		{
+			name:       "Writes the diagrams",
+			args:       []string{"-o", "out", "specs.yaml"},
+			wantCode:   exitOK,
+			wantStdout: []string{"wrote " + filepath.Join("out", "first.mmd"), "wrote " + filepath.Join("out", "second.mmd")},
+			wantFiles:  []string{"first.mmd", "second.mmd"},
+		},
+		{
+			name:       "Serves the preview",
+			args:       []string{"-http", "127.0.0.1:0", "specs.yaml"},
+			wantCode:   exitOK,
+			wantStdout: []string{"serving the preview at http://127.0.0.1:"},
+		},
+		{
+			name:       "Invalid spec is reported",
+			args:       []string{"-o", "out", "missing.yaml"},
+			wantCode:   exitOK,
+			wantStderr: "missing.yaml",
+		},
+		{
+			name:       "Missing spec files",
+			args:       []string{"-o", "out"},
+			wantCode:   exitUsage,
+			wantStderr: missingSpecs,
+		},
+		{
+			name:       "Missing output",
+			args:       []string{"specs.yaml"},
+			wantCode:   exitUsage,
+			wantStderr: missingOutput,
+		},
+		{
+			name:       "Mermaid without preview",
+			args:       []string{"-o", "out", "-mermaid", "mermaid.js", "specs.yaml"},
+			wantCode:   exitUsage,
+			wantStderr: mermaidWithout,
+		},
+		{
+			name:       "Standard input",
+			args:       []string{"-o", "out", "-"},
+			wantCode:   exitUsage,
+			wantStderr: watchStdinUsage,
+		},
+	}
+
+	previous := watchContext
+	defer func() { watchContext = previous }()
+	watchContext = func() (context.Context, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		return ctx, cancel
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeFiles(t, map[string]string{"specs.yaml": twoDiagramSpec})
+			args := append([]string{"watch"}, tt.args...)
+			for i, arg := range args {
+				if strings.HasSuffix(arg, ".yaml") || arg == "out" || arg == "mermaid.js" {
+					args[i] = filepath.Join(dir, arg)
+				}
+			}
+
+			code, stdout, stderr := runCLI(t, "", args...)
+			if code != tt.wantCode {
+				t.Fatalf("run() = %d, want %d (stderr: %s)", code, tt.wantCode, stderr)
+			}
+			for _, want := range tt.wantStdout {
+				if !strings.Contains(stdout, strings.Replace(want, "wrote ", "wrote "+dir+string(filepath.Separator), 1)) {
+					t.Errorf("stdout = %q, want it to contain %q", stdout, want)
+				}
+			}
+			if !strings.Contains(stderr, tt.wantStderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.wantStderr)
+			}
+			for _, name := range tt.wantFiles {
+				if _, err := os.Stat(filepath.Join(dir, "out", name)); err != nil {
+					t.Errorf("output %s: %v", name, err)
+				}
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
 	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
 )
 
@@ -119,6 +120,21 @@ func (b *Block) RemoveColumn() *Block {
 	return b
 }
 
+// Columns returns the number of columns for this block's children, or 0 if it is not set
+func (b *Block) Columns() int {
+	return b.columns
+}
+
+// IsArrow reports whether this block is drawn as an arrow
+func (b *Block) IsArrow() bool {
+	return b.isArrow
+}
+
+// ArrowDirections returns the directions of this block arrow
+func (b *Block) ArrowDirections() []BlockArrowDirection {
+	return utils.CopySlice(b.direction)
+}
+
 // String returns the Mermaid syntax representation of this block
 func (b *Block) String() string {
 	var sb strings.Builder
@@ -184,3 +200,48 @@ func (b *Block) String() string {
 
 	return sb.String()
 }
+
+// FindBlock returns this block or a nested block with the given ID, or nil if there is none.
+func (b *Block) FindBlock(id string) *Block {
+	if !b.IsSpace && b.ID == id {
+		return b
+	}
+
+	for _, child := range b.Children {
+		if found := child.FindBlock(id); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// RemoveBlock removes a block nested directly in this block.
+// It returns false if the block is not a direct child of this block.
+func (b *Block) RemoveBlock(block *Block) (found bool) {
+	b.Children, found = utils.RemoveItem(b.Children, block)
+	return
+}
+
+// parentOf returns the block below b that directly contains the target block.
+func (b *Block) parentOf(target *Block) *Block {
+	for _, child := range b.Children {
+		if child == target {
+			return b
+		}
+		if found := child.parentOf(target); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// walk calls fn for this block and every block nested below it.
+func (b *Block) walk(fn func(*Block)) {
+	fn(b)
+
+	for _, child := range b.Children {
+		child.walk(fn)
+	}
+}
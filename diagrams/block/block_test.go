@@ -567,3 +567,27 @@ func TestBlock_RemoveColumn(t *testing.T) {
 		})
 	}
 }
+
+func TestBlock_Accessors(t *testing.T) {
+	block := NewBlock("0", "Parent")
+	if block.Columns() != 0 || block.IsArrow() || len(block.ArrowDirections()) != 0 {
+		t.Fatalf("new block should have no columns and no arrow")
+	}
+
+	block.SetColumns(3).SetArrow(BlockArrowDirectionLeft, BlockArrowDirectionUp)
+	if got := block.Columns(); got != 3 {
+		t.Errorf("Columns() = %d, want 3", got)
+	}
+	if !block.IsArrow() {
+		t.Errorf("IsArrow() = false, want true")
+	}
+
+	directions := block.ArrowDirections()
+	if !reflect.DeepEqual(directions, []BlockArrowDirection{BlockArrowDirectionLeft, BlockArrowDirectionUp}) {
+		t.Errorf("ArrowDirections() = %v", directions)
+	}
+	directions[0] = BlockArrowDirectionDown
+	if block.ArrowDirections()[0] != BlockArrowDirectionLeft {
+		t.Errorf("ArrowDirections() should return a copy")
+	}
+}
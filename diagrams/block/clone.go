@@ -0,0 +1,53 @@
+package block
+
+// Clone returns a deep copy of the diagram.
+// Links of the copy reference the copied blocks.
+func (d *Diagram) Clone() *Diagram {
+	blocks := make(map[*Block]*Block)
+
+	clone := &Diagram{
+		BaseDiagram: d.BaseDiagram,
+		Blocks:      make([]*Block, 0, len(d.Blocks)),
+		Links:       make([]*Link, 0, len(d.Links)),
+		Columns:     d.Columns,
+	}
+	clone.Config = d.Config.Clone()
+
+	for _, block := range d.Blocks {
+		cloned := block.clone(blocks)
+		if block.diagram != nil {
+			cloned.diagram = clone
+		}
+		clone.Blocks = append(clone.Blocks, cloned)
+	}
+
+	mapBlock := func(block *Block) *Block {
+		if cloned, ok := blocks[block]; ok {
+			return cloned
+		}
+		return block.clone(blocks)
+	}
+
+	for _, link := range d.Links {
+		cloned := *link
+		cloned.From = mapBlock(link.From)
+		cloned.To = mapBlock(link.To)
+		clone.Links = append(clone.Links, &cloned)
+	}
+
+	return clone
+}
+
+// clone copies the block tree, recording every copy in blocks.
+func (b *Block) clone(blocks map[*Block]*Block) *Block {
+	clone := *b
+	clone.Children = make([]*Block, 0, len(b.Children))
+	clone.direction = append([]BlockArrowDirection(nil), b.direction...)
+	blocks[b] = &clone
+
+	for _, child := range b.Children {
+		clone.Children = append(clone.Children, child.clone(blocks))
+	}
+
+	return &clone
+}
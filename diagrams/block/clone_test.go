@@ -0,0 +1,35 @@
+package block
+
+import "testing"
+
+func TestDiagram_Clone(t *testing.T) {
+	idGenerator = idGenerator.Reset()
+	original := NewDiagram().SetColumns(3)
+	parent := original.AddBlock("Parent")
+	child := parent.AddBlock("Child").SetArrow(BlockArrowDirectionRight)
+	other := original.AddBlock("Other").SetShape(BlockShapeCircle)
+	original.AddSpaceWithWidth(2)
+	original.AddLink(child, other).SetText("uses")
+
+	clone := original.Clone()
+
+	if clone.String() != original.String() {
+		t.Errorf("Clone() output differs:\nwant:\n%s\ngot:\n%s", original.String(), clone.String())
+	}
+
+	clonedChild := clone.FindBlock(child.ID)
+	if clonedChild == child || clone.Blocks[0].diagram != clone {
+		t.Fatal("Clone() should copy blocks and attach them to the cloned diagram")
+	}
+
+	if clone.Links[0].From != clonedChild || clone.Links[0].To != clone.FindBlock(other.ID) {
+		t.Error("Clone() links should reference cloned blocks")
+	}
+
+	clone.Blocks[0].AddBlock("Extra")
+	clonedChild.direction[0] = BlockArrowDirectionLeft
+
+	if len(parent.Children) != 1 || child.direction[0] != BlockArrowDirectionRight {
+		t.Error("Modifying the clone changed the original")
+	}
+}
@@ -40,10 +40,18 @@ func (c BlockConfigurationProperties) String() string {
 
 	if len(c.properties) > 0 {
 		sb.WriteString(baseBlockConfigurationProperties)
-		for _, prop := range c.properties {
+		for _, prop := range basediagram.SortedProperties(c.properties) {
 			sb.WriteString(prop.Format())
 		}
 	}
 
 	return sb.String()
 }
+
+// Clone returns a deep copy of the configuration properties.
+func (c BlockConfigurationProperties) Clone() BlockConfigurationProperties {
+	return BlockConfigurationProperties{
+		ConfigurationProperties: c.ConfigurationProperties.Clone(),
+		properties:              basediagram.CloneProperties(c.properties),
+	}
+}
@@ -104,3 +104,96 @@ func (d *Diagram) String() string {
 func (d *Diagram) RenderToFile(path string) error {
 	return utils.RenderToFile(path, d.String())
 }
+
+// FindBlock returns the block with the given ID, searching nested blocks, or nil if there is none.
+func (d *Diagram) FindBlock(id string) *Block {
+	for _, block := range d.Blocks {
+		if found := block.FindBlock(id); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// ParentOf returns the block that directly contains the given block,
+// or nil if the block is declared at the top level of the diagram.
+func (d *Diagram) ParentOf(block *Block) *Block {
+	for _, current := range d.Blocks {
+		if found := current.parentOf(block); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// LinksOf returns all links starting or ending at the given block.
+func (d *Diagram) LinksOf(block *Block) (links []*Link) {
+	for _, link := range d.Links {
+		if link.From == block || link.To == block {
+			links = append(links, link)
+		}
+	}
+
+	return
+}
+
+// RemoveBlock removes the block and its nested blocks from the diagram,
+// together with every link that references any of them.
+// It returns false if the block is not part of the diagram.
+func (d *Diagram) RemoveBlock(block *Block) bool {
+	var found bool
+
+	if d.Blocks, found = utils.RemoveItem(d.Blocks, block); !found {
+		parent := d.ParentOf(block)
+		if parent == nil {
+			return false
+		}
+		parent.RemoveBlock(block)
+	}
+
+	removed := make(map[*Block]bool)
+	block.walk(func(b *Block) {
+		removed[b] = true
+	})
+
+	d.Links, _ = utils.RemoveFunc(d.Links, func(link *Link) bool {
+		return removed[link.From] || removed[link.To]
+	})
+
+	return true
+}
+
+// ReplaceBlock replaces oldBlock with newBlock in place, re-pointing every link
+// that referenced oldBlock.
+// It returns false if oldBlock is not part of the diagram.
+func (d *Diagram) ReplaceBlock(oldBlock *Block, newBlock *Block) bool {
+	if utils.ReplaceItem(d.Blocks, oldBlock, newBlock) {
+		newBlock.diagram = d
+	} else {
+		parent := d.ParentOf(oldBlock)
+		if parent == nil {
+			return false
+		}
+		utils.ReplaceItem(parent.Children, oldBlock, newBlock)
+	}
+
+	for _, link := range d.Links {
+		if link.From == oldBlock {
+			link.From = newBlock
+		}
+		if link.To == oldBlock {
+			link.To = newBlock
+		}
+	}
+
+	return true
+}
+
+// RemoveLink removes the link from the diagram.
+// It returns false if the link is not part of the diagram.
+func (d *Diagram) RemoveLink(link *Link) (found bool) {
+	d.Links, found = utils.RemoveItem(d.Links, link)
+	return
+}
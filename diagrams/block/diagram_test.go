@@ -243,3 +243,79 @@ func TestDiagram_AddSpaceWithWidth(t *testing.T) {
 		})
 	}
 }
+
+func TestDiagram_FindBlock(t *testing.T) {
+	idGenerator = idGenerator.Reset()
+	diagram := NewDiagram()
+	parent := diagram.AddBlock("Parent")
+	child := parent.AddBlock("Child")
+	diagram.AddSpace()
+
+	if diagram.FindBlock(parent.ID) != parent {
+		t.Error("FindBlock() did not find top-level block")
+	}
+
+	if diagram.FindBlock(child.ID) != child {
+		t.Error("FindBlock() did not find nested block")
+	}
+
+	if diagram.FindBlock("missing") != nil {
+		t.Error("FindBlock() should return nil for missing block")
+	}
+
+	if diagram.ParentOf(child) != parent {
+		t.Error("ParentOf() did not return the containing block")
+	}
+}
+
+func TestDiagram_RemoveBlock(t *testing.T) {
+	idGenerator = idGenerator.Reset()
+	diagram := NewDiagram()
+	parent := diagram.AddBlock("Parent")
+	child := parent.AddBlock("Child")
+	other := diagram.AddBlock("Other")
+	last := diagram.AddBlock("Last")
+	diagram.AddLink(child, other)
+	kept := diagram.AddLink(other, last)
+
+	if !diagram.RemoveBlock(parent) {
+		t.Fatal("RemoveBlock() = false, want true")
+	}
+
+	if diagram.FindBlock(child.ID) != nil {
+		t.Error("RemoveBlock() did not remove nested blocks")
+	}
+
+	if !reflect.DeepEqual(diagram.Links, []*Link{kept}) {
+		t.Errorf("RemoveBlock() left links %v, want %v", diagram.Links, []*Link{kept})
+	}
+
+	if diagram.RemoveBlock(parent) {
+		t.Error("RemoveBlock() on removed block = true, want false")
+	}
+}
+
+func TestDiagram_ReplaceBlock(t *testing.T) {
+	idGenerator = idGenerator.Reset()
+	diagram := NewDiagram()
+	first := diagram.AddBlock("First")
+	second := diagram.AddBlock("Second")
+	link := diagram.AddLink(first, second)
+	replacement := NewBlock("R", "Replacement")
+
+	if !diagram.ReplaceBlock(second, replacement) {
+		t.Fatal("ReplaceBlock() = false, want true")
+	}
+
+	if link.To != replacement {
+		t.Errorf("ReplaceBlock() link.To = %v, want %v", link.To, replacement)
+	}
+
+	if len(diagram.LinksOf(replacement)) != 1 {
+		t.Error("LinksOf() should return the re-pointed link")
+	}
+
+	if !diagram.RemoveLink(link) || diagram.RemoveLink(link) {
+		t.Error("RemoveLink() should succeed once")
+	}
+}
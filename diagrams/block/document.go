@@ -0,0 +1,217 @@
+package block
+
+import (
+	"encoding/json"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// DocumentType is the type of block diagram JSON documents.
+const DocumentType string = "block"
+
+const (
+	documentElementBlock    string = "block"
+	documentValueBlockShape string = "block shape"
+)
+
+// blockShapeNames are the names of block shapes in JSON documents.
+var blockShapeNames = map[blockShape]string{
+	BlockShapeDefault:       "default",
+	BlockShapeRoundEdges:    "roundEdges",
+	BlockShapeStadium:       "stadium",
+	BlockShapeSubroutine:    "subroutine",
+	BlockShapeCylindrical:   "cylindrical",
+	BlockShapeCircle:        "circle",
+	BlockShapeAsymmetric:    "asymmetric",
+	BlockShapeRhombus:       "rhombus",
+	BlockShapeHexagon:       "hexagon",
+	BlockShapeParallelogram: "parallelogram",
+	BlockShapeTrapezoid:     "trapezoid",
+	BlockShapeTrapezoidAlt:  "trapezoidAlt",
+	BlockShapeDoubleCircle:  "doubleCircle",
+}
+
+// diagramDocument is the JSON form of a block diagram. Links reference blocks by ID.
+type diagramDocument struct {
+	basediagram.Document
+	Columns int             `json:"columns,omitempty"`
+	Blocks  []blockDocument `json:"blocks,omitempty"`
+	Links   []linkDocument  `json:"links,omitempty"`
+}
+
+// blockDocument describes a block or, when Space is set, a space. The width is omitted
+// when it is the default: 1 for blocks and 0 for spaces.
+type blockDocument struct {
+	ID       string                 `json:"id,omitempty"`
+	Space    bool                   `json:"space,omitempty"`
+	Text     string                 `json:"text,omitempty"`
+	Shape    string                 `json:"shape,omitempty"`
+	Arrow    *[]BlockArrowDirection `json:"arrow,omitempty"`
+	Style    string                 `json:"style,omitempty"`
+	Width    int                    `json:"width,omitempty"`
+	Columns  int                    `json:"columns,omitempty"`
+	Children []blockDocument        `json:"children,omitempty"`
+}
+
+type linkDocument struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Text string `json:"text,omitempty"`
+}
+
+// MarshalJSON encodes the block diagram as a versioned JSON document. Links reference
+// blocks by ID, so every block they use must be part of the diagram.
+func (d *Diagram) MarshalJSON() ([]byte, error) {
+	config, err := d.Config.EncodeDocument(d.Config.properties)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := diagramDocument{Document: d.EncodeDocument(DocumentType, config), Columns: d.Columns}
+
+	blocks := make(map[*Block]bool)
+	for _, block := range d.Blocks {
+		block.walk(func(b *Block) { blocks[b] = true })
+		blockDoc, err := encodeBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		doc.Blocks = append(doc.Blocks, blockDoc)
+	}
+
+	for _, link := range d.Links {
+		for _, block := range []*Block{link.From, link.To} {
+			if block == nil {
+				return nil, basediagram.UnknownReference(documentElementBlock, "")
+			}
+			if !blocks[block] || block.IsSpace {
+				return nil, basediagram.UnknownReference(documentElementBlock, block.ID)
+			}
+		}
+		doc.Links = append(doc.Links, linkDocument{From: link.From.ID, To: link.To.ID, Text: link.Text})
+	}
+
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON replaces the block diagram with the one described by a JSON document and
+// re-links links to the blocks. The package ID generator skips the decoded block IDs.
+func (d *Diagram) UnmarshalJSON(data []byte) error {
+	var doc diagramDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	decoded := NewDiagram()
+	if err := decoded.DecodeDocument(DocumentType, doc.Document); err != nil {
+		return err
+	}
+
+	var err error
+	if decoded.Config.ConfigurationProperties, decoded.Config.properties, err = doc.Config.Decode(); err != nil {
+		return err
+	}
+	decoded.Columns = doc.Columns
+
+	blocks := make(map[string]*Block)
+	for _, blockDoc := range doc.Blocks {
+		block, err := decodeBlock(blockDoc, blocks)
+		if err != nil {
+			return err
+		}
+		if !block.IsSpace {
+			block.diagram = decoded
+		}
+		decoded.Blocks = append(decoded.Blocks, block)
+	}
+
+	for _, linkDoc := range doc.Links {
+		from, to := blocks[linkDoc.From], blocks[linkDoc.To]
+		if from == nil {
+			return basediagram.UnknownReference(documentElementBlock, linkDoc.From)
+		}
+		if to == nil {
+			return basediagram.UnknownReference(documentElementBlock, linkDoc.To)
+		}
+		decoded.AddLink(from, to).SetText(linkDoc.Text)
+	}
+
+	for id := range blocks {
+		idGenerator.Skip(id)
+	}
+
+	*d = *decoded
+
+	return nil
+}
+
+// encodeBlock returns the document of a block or space and its nested blocks.
+func encodeBlock(block *Block) (doc blockDocument, err error) {
+	if block.IsSpace {
+		return blockDocument{Space: true, Width: block.Width}, nil
+	}
+
+	doc = blockDocument{
+		ID:      block.ID,
+		Text:    block.Text,
+		Style:   block.Style,
+		Columns: block.columns,
+	}
+	if doc.Shape, err = basediagram.EncodeName(blockShapeNames, block.Shape, BlockShapeDefault, documentValueBlockShape); err != nil {
+		return
+	}
+	if block.isArrow {
+		directions := utils.CopySlice(block.direction)
+		doc.Arrow = &directions
+	}
+	if block.Width != 1 {
+		doc.Width = block.Width
+	}
+
+	for _, child := range block.Children {
+		var childDoc blockDocument
+		if childDoc, err = encodeBlock(child); err != nil {
+			return
+		}
+		doc.Children = append(doc.Children, childDoc)
+	}
+
+	return
+}
+
+// decodeBlock returns the block or space described by the document and records it and its
+// nested blocks by ID.
+func decodeBlock(doc blockDocument, blocks map[string]*Block) (*Block, error) {
+	if doc.Space {
+		return &Block{IsSpace: true, Width: doc.Width}, nil
+	}
+
+	if blocks[doc.ID] != nil {
+		return nil, basediagram.DuplicateID(documentElementBlock, doc.ID)
+	}
+
+	shape, err := basediagram.DecodeName(blockShapeNames, doc.Shape, BlockShapeDefault, documentValueBlockShape)
+	if err != nil {
+		return nil, err
+	}
+
+	block := NewBlock(doc.ID, doc.Text).SetShape(shape).SetStyle(doc.Style).SetColumns(doc.Columns)
+	if doc.Arrow != nil {
+		block.SetArrow(utils.CopySlice(*doc.Arrow)...)
+	}
+	if doc.Width != 0 {
+		block.Width = doc.Width
+	}
+	blocks[block.ID] = block
+
+	for _, childDoc := range doc.Children {
+		child, err := decodeBlock(childDoc, blocks)
+		if err != nil {
+			return nil, err
+		}
+		block.Children = append(block.Children, child)
+	}
+
+	return block, nil
+}
@@ -0,0 +1,126 @@
+package block
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+func TestDiagram_JSONRoundTrip(t *testing.T) {
+	idGenerator = idGenerator.Reset()
+
+	original := NewDiagram()
+	original.Title = "Layout"
+	original.SetColumns(3)
+	original.Config.SetPadding(4)
+	a := original.AddBlock("A").SetShape(BlockShapeCircle).SetWidth(2).SetStyle("fill:#f9f")
+	original.AddSpace()
+	original.AddSpaceWithWidth(2)
+	group := original.AddBlock("").SetColumns(2)
+	child := group.AddBlock("child").SetShape(BlockShapeHexagon)
+	group.AddBlock("go").SetArrow(BlockArrowDirectionRight, BlockArrowDirectionDown)
+	original.AddLink(a, child).SetText("to")
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	decoded := NewDiagram()
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if decoded.String() != original.String() {
+		t.Errorf("round trip output differs:\nwant:\n%s\ngot:\n%s", original.String(), decoded.String())
+	}
+
+	if decoded.Links[0].To != decoded.FindBlock(child.ID) {
+		t.Error("decoded links should reference decoded blocks")
+	}
+
+	again, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("Marshal() of decoded diagram error = %v", err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("encoding is not stable:\nfirst:  %s\nsecond: %s", data, again)
+	}
+}
+
+func TestDiagram_UnmarshalJSON_SkipsDecodedIDs(t *testing.T) {
+	idGenerator = idGenerator.Reset()
+
+	d := NewDiagram()
+	if err := json.Unmarshal([]byte(`{"version":1,"type":"block","blocks":[{"id":"7"}]}`), d); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if got := d.AddBlock("new").ID; got != "8" {
+		t.Errorf("AddBlock() after decoding ID = %q, want %q", got, "8")
+	}
+}
+
+func TestDiagram_MarshalJSON(t *testing.T) {
+	idGenerator = idGenerator.Reset()
+
+	d := NewDiagram()
+	a := d.AddBlock("A")
+	d.AddSpace()
+	d.AddBlock("").SetArrow()
+	d.AddLink(a, a)
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `{"version":1,"type":"block","blocks":[{"id":"0","text":"A"},{"space":true},{"id":"1","arrow":[]}],` +
+		`"links":[{"from":"0","to":"0"}]}`
+	if string(data) != want {
+		t.Errorf("Marshal() =\n%s\nwant\n%s", data, want)
+	}
+
+	d.AddLink(a, NewBlock("x", "outside"))
+	if _, err := json.Marshal(d); !errors.Is(err, basediagram.ErrUnknownReference) {
+		t.Errorf("Marshal() with a block outside the diagram error = %v, want %v", err, basediagram.ErrUnknownReference)
+	}
+}
+
+func TestDiagram_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{
+			name:  "Link to a nested block",
+			input: `{"version":1,"type":"block","blocks":[{"id":"a","children":[{"id":"b","shape":"stadium"}]}],"links":[{"from":"a","to":"b"}]}`,
+		},
+		{
+			name:  "Unknown block",
+			input: `{"version":1,"type":"block","blocks":[{"id":"a"}],"links":[{"from":"a","to":"b"}]}`,
+			want:  basediagram.ErrUnknownReference,
+		},
+		{
+			name:  "Duplicate block",
+			input: `{"version":1,"type":"block","blocks":[{"id":"a","children":[{"id":"a"}]}]}`,
+			want:  basediagram.ErrDuplicateID,
+		},
+		{
+			name:  "Unknown shape",
+			input: `{"version":1,"type":"block","blocks":[{"id":"a","shape":"star"}]}`,
+			want:  basediagram.ErrUnknownValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(tt.input), NewDiagram()); !errors.Is(err, tt.want) {
+				t.Errorf("Unmarshal() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,404 @@
+package block
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// Keywords of block diagram source.
+const (
+	keywordBlockBeta = "block-beta"
+	keywordBlock     = "block"
+	keywordColumns   = "columns"
+	keywordSpace     = "space"
+	keywordEnd       = "end"
+	keywordStyle     = "style"
+
+	// configKey is the frontmatter configuration member holding the block properties.
+	configKey = "block"
+
+	shapeText      = "%s"
+	quote          = `"`
+	arrowSeparator = ","
+	styleSeparator = ","
+	linkArrow      = "-->"
+)
+
+// Statement patterns. Blocks are an ID followed by an optional shape and ":width", several
+// of them may share a line, and links are "A --> B" with an optional "-- text" before the
+// arrow.
+var (
+	parentPattern    = regexp.MustCompile(`^block:([^\s"(){}\[\]<>:]+)(?::(\d+))?$`)
+	spacePattern     = regexp.MustCompile(`^space(?::(\d+))?$`)
+	itemPattern      = regexp.MustCompile(`^([^\s"(){}\[\]<>:]+)(.*?)(?::(\d+))?$`)
+	arrowPattern     = regexp.MustCompile(`^<\["(.*)"\]>\(([^)]*)\)$`)
+	linkPattern      = regexp.MustCompile(`^(\S+?)\s*(?:--\s*(?:"([^"]*)"|([^"]*?))\s*)?-->\s*(\S+)$`)
+	linkIDPattern    = regexp.MustCompile(`^[^\s"(){}\[\]<>:-]+$`)
+	otherLinkPattern = regexp.MustCompile(`[-=.]{2,}>|---|===|<-`)
+	quotedPattern    = regexp.MustCompile(`"[^"]*"`)
+)
+
+// blockShapes lists the shapes with the longest openings first, so that the first matching
+// opening is the right one.
+var blockShapes = []blockShape{
+	BlockShapeDoubleCircle,
+	BlockShapeStadium,
+	BlockShapeCircle,
+	BlockShapeSubroutine,
+	BlockShapeCylindrical,
+	BlockShapeParallelogram,
+	BlockShapeTrapezoid,
+	BlockShapeTrapezoidAlt,
+	BlockShapeHexagon,
+	BlockShapeDefault,
+	BlockShapeRoundEdges,
+	BlockShapeRhombus,
+	BlockShapeAsymmetric,
+}
+
+// arrowDirections holds the directions of block arrows.
+var arrowDirections = map[string]BlockArrowDirection{
+	string(BlockArrowDirectionRight): BlockArrowDirectionRight,
+	string(BlockArrowDirectionLeft):  BlockArrowDirectionLeft,
+	string(BlockArrowDirectionUp):    BlockArrowDirectionUp,
+	string(BlockArrowDirectionDown):  BlockArrowDirectionDown,
+	string(BlockArrowDirectionX):     BlockArrowDirectionX,
+	string(BlockArrowDirectionY):     BlockArrowDirectionY,
+}
+
+// unsupportedKeywords start the statements the model has no counterpart for.
+var unsupportedKeywords = []string{"classDef", "class", "accTitle", "accDescr"}
+
+// Parse returns the block diagram described by Mermaid source, such as the output of
+// String. Blocks are created by their declaration or by their first mention in a link.
+// Errors are *basediagram.SyntaxError values: invalid syntax wraps basediagram.ErrSyntax,
+// statements the model cannot represent, such as nested composite blocks, spaces, widths
+// and styles inside a composite block, or links other than "-->", wrap
+// basediagram.ErrUnsupported, and duplicate and unknown blocks wrap the document errors.
+// Unsupported statements are reported together, see basediagram.Source.Read.
+func Parse(source string) (*Diagram, error) {
+	parsed, err := basediagram.ParseSource(source, configKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyword := parsed.Header.Text; keyword != keywordBlockBeta && keyword != keywordBlock {
+		return nil, basediagram.Syntax(parsed.Header.Line, "expected %s, found %q", keywordBlockBeta, keyword)
+	}
+
+	p := &blockParser{
+		diagram: NewDiagram(),
+		blocks:  make(map[string]*Block),
+		linked:  make(map[string]bool),
+	}
+	if err = parsed.Read(p.statement); err != nil {
+		return nil, err
+	}
+	if p.open != nil {
+		return nil, basediagram.Syntax(p.opened.Line, "block %q is not closed", p.open.ID)
+	}
+
+	for id := range p.blocks {
+		idGenerator.Skip(id)
+	}
+
+	d := p.diagram
+	d.DecodeSource(parsed)
+	if d.Config.ConfigurationProperties, d.Config.properties, err = parsed.Config.Decode(); err != nil {
+		return nil, basediagram.AtLine(1, err)
+	}
+
+	return d, nil
+}
+
+// blockParser builds a block diagram statement by statement.
+type blockParser struct {
+	diagram *Diagram
+	blocks  map[string]*Block
+	// linked holds the blocks created by their mention in a link.
+	linked map[string]bool
+	// open is the composite block read until its end, opened by the opened statement.
+	open   *Block
+	opened basediagram.Statement
+	// skipped counts the composite blocks left out and not ended yet.
+	skipped int
+}
+
+// statement reads a statement of the diagram body.
+func (p *blockParser) statement(statement basediagram.Statement) error {
+	text := strings.TrimSpace(strings.TrimRight(statement.Text, ";"))
+
+	if p.skipped > 0 {
+		switch {
+		case text == keywordEnd:
+			p.skipped--
+		case basediagram.HasKeyword(text, keywordBlock):
+			p.skipped++
+		}
+		return nil
+	}
+
+	switch {
+	case text == keywordEnd:
+		return p.end(statement)
+	case basediagram.HasKeyword(text, keywordBlock):
+		return p.begin(statement, text)
+	case basediagram.HasKeyword(text, unsupportedKeywords...):
+		return basediagram.Unsupported(statement)
+	}
+	if rest, ok := basediagram.CutKeyword(text, keywordColumns); ok {
+		return p.columns(statement, rest)
+	}
+	if rest, ok := basediagram.CutKeyword(text, keywordStyle); ok {
+		return p.style(statement, rest)
+	}
+
+	if unquoted := quotedPattern.ReplaceAllString(text, ""); strings.Contains(unquoted, linkArrow) || otherLinkPattern.MatchString(unquoted) {
+		if match := linkPattern.FindStringSubmatch(text); match != nil {
+			return p.link(statement, match)
+		}
+		return basediagram.Unsupported(statement)
+	}
+
+	for _, item := range splitItems(text) {
+		if err := p.item(statement, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// begin opens a composite block. Composite blocks nested in another one and anonymous ones
+// are left out up to their end.
+func (p *blockParser) begin(statement basediagram.Statement, text string) error {
+	match := parentPattern.FindStringSubmatch(text)
+	if match == nil || p.open != nil {
+		p.skipped = 1
+		return basediagram.Unsupported(statement)
+	}
+
+	if p.blocks[match[1]] != nil {
+		return basediagram.AtLine(statement.Line, basediagram.DuplicateID(documentElementBlock, match[1]))
+	}
+
+	block := NewBlock(match[1], "")
+	if match[2] != "" {
+		block.Width, _ = strconv.Atoi(match[2])
+	}
+	p.add(block)
+	p.open, p.opened = block, statement
+
+	return nil
+}
+
+// end closes the open composite block. A composite block without blocks would be rendered
+// as a block of its own, so it is not supported.
+func (p *blockParser) end(statement basediagram.Statement) error {
+	if p.open == nil {
+		return basediagram.Syntax(statement.Line, "%s without %s", keywordEnd, keywordBlock)
+	}
+
+	open := p.open
+	p.open = nil
+	if len(open.Children) == 0 {
+		return basediagram.Unsupported(p.opened)
+	}
+
+	return nil
+}
+
+// columns reads the column count of the diagram or of the open composite block.
+func (p *blockParser) columns(statement basediagram.Statement, rest string) error {
+	count, err := strconv.Atoi(rest)
+	switch {
+	case rest == "auto":
+		return basediagram.Unsupported(statement)
+	case err != nil || count < 1:
+		return basediagram.Syntax(statement.Line, "invalid column count %q", rest)
+	case p.open != nil:
+		p.open.SetColumns(count)
+	default:
+		p.diagram.SetColumns(count)
+	}
+
+	return nil
+}
+
+// style reads the style of a block. The styles of blocks in a composite block are not
+// rendered, so they are not supported.
+func (p *blockParser) style(statement basediagram.Statement, rest string) error {
+	id, css, _ := strings.Cut(rest, " ")
+	css = strings.TrimSpace(css)
+	if css == "" {
+		return basediagram.Syntax(statement.Line, "missing style of block %q", id)
+	}
+
+	block := p.blocks[id]
+	switch {
+	case block == nil:
+		return basediagram.AtLine(statement.Line, basediagram.UnknownReference(documentElementBlock, id))
+	case block.diagram == nil:
+		return basediagram.Unsupported(statement)
+	case block.Style != "":
+		block.Style += styleSeparator + css
+	default:
+		block.Style = css
+	}
+
+	return nil
+}
+
+// link reads a link between two blocks, given by their IDs.
+func (p *blockParser) link(statement basediagram.Statement, match []string) error {
+	if !linkIDPattern.MatchString(match[1]) || !linkIDPattern.MatchString(match[4]) {
+		return basediagram.Unsupported(statement)
+	}
+
+	text := match[2]
+	if text == "" {
+		text = strings.TrimSpace(match[3])
+	}
+	p.diagram.AddLink(p.reference(match[1]), p.reference(match[4])).SetText(text)
+
+	return nil
+}
+
+// reference returns the block with an ID, created in the open composite block or in the
+// diagram at its first mention.
+func (p *blockParser) reference(id string) *Block {
+	if block := p.blocks[id]; block != nil {
+		return block
+	}
+
+	block := NewBlock(id, "")
+	p.add(block)
+	p.linked[id] = true
+
+	return block
+}
+
+// item reads a space or a block declaration.
+func (p *blockParser) item(statement basediagram.Statement, text string) error {
+	if basediagram.HasKeyword(text, keywordSpace) {
+		match := spacePattern.FindStringSubmatch(text)
+		switch {
+		case match == nil:
+			return basediagram.Syntax(statement.Line, "invalid space %q", text)
+		case p.open != nil:
+			return basediagram.Unsupported(statement)
+		}
+		space := &Block{IsSpace: true}
+		space.Width, _ = strconv.Atoi(match[1])
+		p.diagram.Blocks = append(p.diagram.Blocks, space)
+		return nil
+	}
+
+	match := itemPattern.FindStringSubmatch(text)
+	if match == nil {
+		return basediagram.Syntax(statement.Line, "invalid block %q", text)
+	}
+
+	id := match[1]
+	if p.blocks[id] != nil {
+		if p.linked[id] {
+			return basediagram.Unsupported(statement)
+		}
+		return basediagram.AtLine(statement.Line, basediagram.DuplicateID(documentElementBlock, id))
+	}
+
+	block := NewBlock(id, "")
+	if match[2] != "" {
+		if err := parseShape(block, match[2]); err != nil {
+			return basediagram.Syntax(statement.Line, "invalid block %q", text)
+		}
+	}
+	if match[3] != "" {
+		block.Width, _ = strconv.Atoi(match[3])
+		if p.open != nil && block.Width != 1 {
+			return basediagram.Unsupported(statement)
+		}
+	}
+	p.add(block)
+
+	return nil
+}
+
+// parseShape reads the shape and text of a block, quoted or not, or its arrow.
+func parseShape(block *Block, text string) error {
+	if match := arrowPattern.FindStringSubmatch(text); match != nil {
+		var directions []BlockArrowDirection
+		for _, name := range strings.Split(match[2], arrowSeparator) {
+			direction, ok := arrowDirections[strings.TrimSpace(name)]
+			if !ok {
+				return basediagram.UnknownValue(documentValueBlockShape, name)
+			}
+			directions = append(directions, direction)
+		}
+		block.Text = match[1]
+		block.SetArrow(directions...)
+		return nil
+	}
+
+	for _, quoted := range []bool{true, false} {
+		for _, shape := range blockShapes {
+			open, close, _ := strings.Cut(string(shape), shapeText)
+			if !quoted {
+				open, close = strings.TrimSuffix(open, quote), strings.TrimPrefix(close, quote)
+			}
+			if len(text) >= len(open)+len(close) && strings.HasPrefix(text, open) && strings.HasSuffix(text, close) {
+				block.Text = text[len(open) : len(text)-len(close)]
+				block.Shape = shape
+				return nil
+			}
+		}
+	}
+
+	return basediagram.UnknownValue(documentValueBlockShape, text)
+}
+
+// add adds a block to the open composite block, or to the diagram.
+func (p *blockParser) add(block *Block) {
+	if p.open != nil {
+		p.open.Children = append(p.open.Children, block)
+	} else {
+		block.diagram = p.diagram
+		p.diagram.Blocks = append(p.diagram.Blocks, block)
+	}
+	p.blocks[block.ID] = block
+}
+
+// splitItems splits a statement declaring several blocks at the spaces outside quotes and
+// brackets.
+func splitItems(text string) []string {
+	var items []string
+	var current strings.Builder
+	depth, quoted := 0, false
+
+	for _, r := range text {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case quoted:
+		case strings.ContainsRune("[({", r):
+			depth++
+		case strings.ContainsRune("])}", r) && depth > 0:
+			depth--
+		case (r == ' ' || r == '\t') && depth == 0:
+			if current.Len() > 0 {
+				items = append(items, current.String())
+				current.Reset()
+			}
+			continue
+		}
+		current.WriteRune(r)
+	}
+	if current.Len() > 0 {
+		items = append(items, current.String())
+	}
+
+	return items
+}
@@ -0,0 +1,146 @@
+package block
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/testutils"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "Blocks on one line",
+			source: "block-beta\n    columns 3\n    a[\"Alpha\"] b(\"Beta\"):2 c\n",
+			want:   "block-beta\n    columns 3\n    a[\"Alpha\"]\n    b(\"Beta\"):2\n    c\n",
+		},
+		{
+			name:   "Shapes",
+			source: "block-beta\n    a(((\"A\")))\n    b([\"B\"])\n    c((C))\n    d[/\"D\"\\]\n    e>\"E\"]\n    f{{F}}\n",
+			want:   "block-beta\n    a(((\"A\")))\n    b([\"B\"])\n    c((\"C\"))\n    d[/\"D\"\\]\n    e>\"E\"]\n    f{{\"F\"}}\n",
+		},
+		{
+			name:   "Spaces and arrows",
+			source: "block-beta\n    a space:2 b<[\"go\"]>(right, down) space\n",
+			want:   "block-beta\n    a\n    space:2\n    b<[\"go\"]>(right, down)\n    space\n",
+		},
+		{
+			name:   "Composite block",
+			source: "block-beta\n    block:group:2\n        columns 2\n        x[\"X\"] y\n    end\n    style group fill:#f9f\n",
+			want:   "block-beta\n    block:group:2\n    columns 2\n    x[\"X\"]\n    y\n    end\n    style group fill:#f9f\n",
+		},
+		{
+			name:   "Links",
+			source: "block-beta\n    a b\n    a --> b\n    b -- \"next\" --> c\n",
+			want:   "block-beta\n    a\n    b\n    c\n    a --> b\n    b -- \"next\" --> c\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := testutils.DiagramBody(t, d.String()); got != tt.want {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+
+			reparsed, err := Parse(d.String())
+			if err != nil {
+				t.Fatalf("Parse() of the rendering error = %v", err)
+			}
+			if reparsed.String() != d.String() {
+				t.Errorf("Parse() of the rendering = %q, want %q", reparsed.String(), d.String())
+			}
+		})
+	}
+}
+
+func TestParse_SkipsParsedIDs(t *testing.T) {
+	idGenerator = idGenerator.Reset()
+
+	d, err := Parse("block-beta\n    0 1\n")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := d.AddBlock("new").ID; got != "2" {
+		t.Errorf("AddBlock() ID = %q, want %q", got, "2")
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "Other diagram type",
+			source:  "flowchart\n",
+			wantErr: basediagram.ErrSyntax,
+		},
+		{
+			name:    "Duplicate block",
+			source:  "block-beta\n    a a\n",
+			wantErr: basediagram.ErrDuplicateID,
+			wantMsg: `line 2: duplicate identifier: block "a"`,
+		},
+		{
+			name:    "Unknown styled block",
+			source:  "block-beta\n    style a fill:#f9f\n",
+			wantErr: basediagram.ErrUnknownReference,
+		},
+		{
+			name:    "End without block",
+			source:  "block-beta\n    end\n",
+			wantErr: basediagram.ErrSyntax,
+			wantMsg: "line 2: syntax error: end without block",
+		},
+		{
+			name:    "Unclosed block",
+			source:  "block-beta\n    block:group\n    a\n",
+			wantErr: basediagram.ErrSyntax,
+			wantMsg: `line 2: syntax error: block "group" is not closed`,
+		},
+		{
+			name:    "Unknown arrow direction",
+			source:  "block-beta\n    a<[\"go\"]>(around)\n",
+			wantErr: basediagram.ErrSyntax,
+		},
+		{
+			name:    "Nested composite block",
+			source:  "block-beta\n    block:outer\n    a\n    block:inner\n    b\n    end\n    end\n",
+			wantErr: basediagram.ErrUnsupported,
+			wantMsg: "line 4: unsupported syntax: block:inner",
+		},
+		{
+			name:    "Unsupported statements are all reported",
+			source:  "block-beta\n    a b\n    a --- b\n    block:group\n    space\n    d c:2\n    end\n    classDef warm fill:#f96\n",
+			wantErr: basediagram.ErrUnsupported,
+			wantMsg: "line 3: unsupported syntax: a --- b\nline 5: unsupported syntax: space\nline 6: unsupported syntax: d c:2\nline 8: unsupported syntax: classDef warm fill:#f96",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.source)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			var syntaxErr *basediagram.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Errorf("Parse() error = %T, want *basediagram.SyntaxError", err)
+			}
+			if tt.wantMsg != "" && err.Error() != tt.wantMsg {
+				t.Errorf("Parse() error = %q, want %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
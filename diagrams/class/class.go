@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
 	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
 )
 
@@ -98,3 +99,49 @@ func (c *Class) String(curIndentation string) string {
 
 	return sb.String()
 }
+
+// Fields returns the fields of the class.
+func (c *Class) Fields() []*Field {
+	return utils.CopySlice(c.fields)
+}
+
+// Methods returns the methods of the class.
+func (c *Class) Methods() []*Method {
+	return utils.CopySlice(c.methods)
+}
+
+// FindField returns the field with the given name, or nil if there is none.
+func (c *Class) FindField(name string) *Field {
+	for _, field := range c.fields {
+		if field.Name == name {
+			return field
+		}
+	}
+
+	return nil
+}
+
+// FindMethod returns the first method with the given name, or nil if there is none.
+func (c *Class) FindMethod(name string) *Method {
+	for _, method := range c.methods {
+		if method.Name == name {
+			return method
+		}
+	}
+
+	return nil
+}
+
+// RemoveField removes the field from the class.
+// It returns false if the field is not part of the class.
+func (c *Class) RemoveField(field *Field) (found bool) {
+	c.fields, found = utils.RemoveItem(c.fields, field)
+	return
+}
+
+// RemoveMethod removes the method from the class.
+// It returns false if the method is not part of the class.
+func (c *Class) RemoveMethod(method *Method) (found bool) {
+	c.methods, found = utils.RemoveItem(c.methods, method)
+	return
+}
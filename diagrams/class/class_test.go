@@ -206,3 +206,33 @@ func TestClass_SetAnnotation(t *testing.T) {
 		})
 	}
 }
+
+func TestClass_FindAndRemoveMembers(t *testing.T) {
+	class := NewClass("Duck")
+	field := class.AddField("name", "string")
+	method := class.AddMethod("Quack")
+
+	if class.FindField("name") != field {
+		t.Error("FindField() did not find field")
+	}
+
+	if class.FindMethod("Quack") != method {
+		t.Error("FindMethod() did not find method")
+	}
+
+	if class.FindField("missing") != nil || class.FindMethod("missing") != nil {
+		t.Error("Find on missing member should return nil")
+	}
+
+	if !class.RemoveField(field) || len(class.Fields()) != 0 {
+		t.Error("RemoveField() did not remove field")
+	}
+
+	if !class.RemoveMethod(method) || len(class.Methods()) != 0 {
+		t.Error("RemoveMethod() did not remove method")
+	}
+
+	if class.RemoveField(field) || class.RemoveMethod(method) {
+		t.Error("Removing a missing member should return false")
+	}
+}
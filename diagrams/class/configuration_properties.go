@@ -151,10 +151,18 @@ func (c ClassConfigurationProperties) String() string {
 
 	if len(c.properties) > 0 {
 		sb.WriteString(baseClassConfigurationProperties)
-		for _, prop := range c.properties {
+		for _, prop := range basediagram.SortedProperties(c.properties) {
 			sb.WriteString(prop.Format())
 		}
 	}
 
 	return sb.String()
 }
+
+// Clone returns a deep copy of the configuration properties.
+func (c ClassConfigurationProperties) Clone() ClassConfigurationProperties {
+	return ClassConfigurationProperties{
+		ConfigurationProperties: c.ConfigurationProperties.Clone(),
+		properties:              basediagram.CloneProperties(c.properties),
+	}
+}
@@ -124,3 +124,189 @@ func (cd *ClassDiagram) AddRelation(classA *Class, classB *Class) (newRelation *
 
 	return
 }
+
+// Classes returns all classes of the diagram, including classes declared in namespaces.
+func (cd *ClassDiagram) Classes() []*Class {
+	classes := utils.CopySlice(cd.classes)
+
+	for _, namespace := range cd.namespaces {
+		classes = append(classes, namespace.allClasses()...)
+	}
+
+	return classes
+}
+
+// Namespaces returns the top-level namespaces of the diagram.
+func (cd *ClassDiagram) Namespaces() []*Namespace {
+	return utils.CopySlice(cd.namespaces)
+}
+
+// Relations returns the relations of the diagram.
+func (cd *ClassDiagram) Relations() []*Relation {
+	return utils.CopySlice(cd.relations)
+}
+
+// Notes returns the notes of the diagram.
+func (cd *ClassDiagram) Notes() []*Note {
+	return utils.CopySlice(cd.notes)
+}
+
+// FindClass returns the class with the given name, searching namespaces, or nil if there is none.
+func (cd *ClassDiagram) FindClass(name string) *Class {
+	for _, class := range cd.Classes() {
+		if class.Name == name {
+			return class
+		}
+	}
+
+	return nil
+}
+
+// FindNamespace returns the namespace with the given name, searching nested namespaces, or nil if there is none.
+func (cd *ClassDiagram) FindNamespace(name string) *Namespace {
+	for _, namespace := range cd.namespaces {
+		if found := namespace.FindNamespace(name); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// NamespaceOf returns the namespace that declares the class, or nil if the class
+// is declared directly in the diagram.
+func (cd *ClassDiagram) NamespaceOf(class *Class) *Namespace {
+	for _, namespace := range cd.namespaces {
+		if found := namespace.namespaceOf(class); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// RelationsOf returns all relations in which the class takes part.
+func (cd *ClassDiagram) RelationsOf(class *Class) (relations []*Relation) {
+	for _, relation := range cd.relations {
+		if relation.ClassA == class || relation.ClassB == class {
+			relations = append(relations, relation)
+		}
+	}
+
+	return
+}
+
+// NotesOf returns all notes attached to the class.
+func (cd *ClassDiagram) NotesOf(class *Class) (notes []*Note) {
+	for _, note := range cd.notes {
+		if note.Class == class {
+			notes = append(notes, note)
+		}
+	}
+
+	return
+}
+
+// RemoveClass removes the class from the diagram or its namespace,
+// together with every relation and note that references it.
+// It returns false if the class is not part of the diagram.
+func (cd *ClassDiagram) RemoveClass(class *Class) bool {
+	var found bool
+
+	cd.classes, found = utils.RemoveItem(cd.classes, class)
+	if !found {
+		namespace := cd.NamespaceOf(class)
+		if namespace == nil {
+			return false
+		}
+		namespace.RemoveClass(class)
+	}
+
+	cd.relations, _ = utils.RemoveFunc(cd.relations, func(relation *Relation) bool {
+		return relation.ClassA == class || relation.ClassB == class
+	})
+
+	cd.notes, _ = utils.RemoveFunc(cd.notes, func(note *Note) bool {
+		return note.Class == class
+	})
+
+	return true
+}
+
+// ReplaceClass replaces oldClass with newClass in place, re-pointing every relation
+// and note that referenced oldClass.
+// It returns false if oldClass is not part of the diagram.
+func (cd *ClassDiagram) ReplaceClass(oldClass *Class, newClass *Class) bool {
+	if !utils.ReplaceItem(cd.classes, oldClass, newClass) {
+		namespace := cd.NamespaceOf(oldClass)
+		if namespace == nil {
+			return false
+		}
+		utils.ReplaceItem(namespace.Classes, oldClass, newClass)
+	}
+
+	for _, relation := range cd.relations {
+		if relation.ClassA == oldClass {
+			relation.ClassA = newClass
+		}
+		if relation.ClassB == oldClass {
+			relation.ClassB = newClass
+		}
+	}
+
+	for _, note := range cd.notes {
+		if note.Class == oldClass {
+			note.Class = newClass
+		}
+	}
+
+	return true
+}
+
+// RemoveRelation removes the relation from the diagram.
+// It returns false if the relation is not part of the diagram.
+func (cd *ClassDiagram) RemoveRelation(relation *Relation) (found bool) {
+	cd.relations, found = utils.RemoveItem(cd.relations, relation)
+	return
+}
+
+// RemoveNote removes the note from the diagram.
+// It returns false if the note is not part of the diagram.
+func (cd *ClassDiagram) RemoveNote(note *Note) (found bool) {
+	cd.notes, found = utils.RemoveItem(cd.notes, note)
+	return
+}
+
+// RemoveNamespace removes the namespace, including its nested namespaces and classes.
+// Relations and notes referencing the removed classes are removed as well.
+// It returns false if the namespace is not part of the diagram.
+func (cd *ClassDiagram) RemoveNamespace(namespace *Namespace) bool {
+	var found bool
+
+	if cd.namespaces, found = utils.RemoveItem(cd.namespaces, namespace); !found {
+		for _, parent := range cd.namespaces {
+			if found = parent.removeNamespaceRecursive(namespace); found {
+				break
+			}
+		}
+	}
+
+	if !found {
+		return false
+	}
+
+	removed := make(map[*Class]bool)
+	for _, class := range namespace.allClasses() {
+		removed[class] = true
+	}
+
+	cd.relations, _ = utils.RemoveFunc(cd.relations, func(relation *Relation) bool {
+		return removed[relation.ClassA] || removed[relation.ClassB]
+	})
+
+	cd.notes, _ = utils.RemoveFunc(cd.notes, func(note *Note) bool {
+		return note.Class != nil && removed[note.Class]
+	})
+
+	return true
+}
@@ -192,3 +192,165 @@ func TestClassDiagram_SetDirection(t *testing.T) {
 		})
 	}
 }
+
+func TestClassDiagram_FindClass(t *testing.T) {
+	diagram := NewClassDiagram()
+	namespace := diagram.AddNamespace("Shapes")
+	animal := diagram.AddClass("Animal", nil)
+	circle := diagram.AddClass("Circle", namespace)
+
+	tests := []struct {
+		name      string
+		className string
+		want      *Class
+	}{
+		{
+			name:      "Find top-level class",
+			className: "Animal",
+			want:      animal,
+		},
+		{
+			name:      "Find class in namespace",
+			className: "Circle",
+			want:      circle,
+		},
+		{
+			name:      "Missing class",
+			className: "Missing",
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diagram.FindClass(tt.className); got != tt.want {
+				t.Errorf("FindClass() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if diagram.NamespaceOf(circle) != namespace {
+		t.Error("NamespaceOf() did not return the declaring namespace")
+	}
+
+	if diagram.NamespaceOf(animal) != nil {
+		t.Error("NamespaceOf() for top-level class should be nil")
+	}
+}
+
+func TestClassDiagram_RemoveClass(t *testing.T) {
+	diagram := NewClassDiagram()
+	namespace := diagram.AddNamespace("Zoo")
+	animal := diagram.AddClass("Animal", nil)
+	duck := diagram.AddClass("Duck", namespace)
+	fish := diagram.AddClass("Fish", nil)
+	diagram.AddRelation(animal, duck)
+	kept := diagram.AddRelation(animal, fish)
+	diagram.AddNote("Quacks", duck)
+	diagram.AddNote("General note", nil)
+
+	if !diagram.RemoveClass(duck) {
+		t.Fatal("RemoveClass() = false, want true")
+	}
+
+	if diagram.FindClass("Duck") != nil {
+		t.Error("RemoveClass() did not remove class from namespace")
+	}
+
+	relations := diagram.Relations()
+	if len(relations) != 1 || relations[0] != kept {
+		t.Errorf("RemoveClass() left relations %v, want only %v", relations, kept)
+	}
+
+	if len(diagram.Notes()) != 1 || diagram.Notes()[0].Class != nil {
+		t.Error("RemoveClass() should only remove notes attached to the class")
+	}
+
+	if !diagram.RemoveClass(fish) {
+		t.Error("RemoveClass() top-level = false, want true")
+	}
+
+	if diagram.RemoveClass(fish) {
+		t.Error("RemoveClass() on removed class = true, want false")
+	}
+}
+
+func TestClassDiagram_ReplaceClass(t *testing.T) {
+	diagram := NewClassDiagram()
+	namespace := diagram.AddNamespace("Zoo")
+	animal := diagram.AddClass("Animal", nil)
+	duck := diagram.AddClass("Duck", namespace)
+	relation := diagram.AddRelation(animal, duck)
+	diagram.AddNote("Quacks", duck)
+	goose := NewClass("Goose")
+
+	if !diagram.ReplaceClass(duck, goose) {
+		t.Fatal("ReplaceClass() = false, want true")
+	}
+
+	if relation.ClassB != goose {
+		t.Errorf("ReplaceClass() relation.ClassB = %v, want %v", relation.ClassB, goose)
+	}
+
+	if diagram.Notes()[0].Class != goose {
+		t.Error("ReplaceClass() did not re-point note")
+	}
+
+	if diagram.NamespaceOf(goose) != namespace {
+		t.Error("ReplaceClass() should keep the class in its namespace")
+	}
+
+	if diagram.ReplaceClass(duck, goose) {
+		t.Error("ReplaceClass() on missing class = true, want false")
+	}
+}
+
+func TestClassDiagram_RemoveNamespace(t *testing.T) {
+	diagram := NewClassDiagram()
+	namespace := diagram.AddNamespace("Zoo")
+	animal := diagram.AddClass("Animal", nil)
+	duck := diagram.AddClass("Duck", namespace)
+	diagram.AddRelation(animal, duck)
+	diagram.AddNote("Quacks", duck)
+
+	if diagram.FindNamespace("Zoo") != namespace {
+		t.Fatal("FindNamespace() did not find namespace")
+	}
+
+	if !diagram.RemoveNamespace(namespace) {
+		t.Fatal("RemoveNamespace() = false, want true")
+	}
+
+	if len(diagram.Namespaces()) != 0 {
+		t.Error("RemoveNamespace() did not remove namespace")
+	}
+
+	if len(diagram.Relations()) != 0 || len(diagram.Notes()) != 0 {
+		t.Error("RemoveNamespace() did not remove dependent relations and notes")
+	}
+
+	if diagram.FindClass("Animal") != animal {
+		t.Error("RemoveNamespace() removed unrelated class")
+	}
+}
+
+func TestClassDiagram_RemoveRelationAndNote(t *testing.T) {
+	diagram := NewClassDiagram()
+	classA := diagram.AddClass("A", nil)
+	classB := diagram.AddClass("B", nil)
+	relation := diagram.AddRelation(classA, classB)
+	diagram.AddNote("Note", classA)
+	note := diagram.NotesOf(classA)[0]
+
+	if len(diagram.RelationsOf(classB)) != 1 {
+		t.Errorf("RelationsOf() returned %d relations, want 1", len(diagram.RelationsOf(classB)))
+	}
+
+	if !diagram.RemoveRelation(relation) || diagram.RemoveRelation(relation) {
+		t.Error("RemoveRelation() should succeed once")
+	}
+
+	if !diagram.RemoveNote(note) || diagram.RemoveNote(note) {
+		t.Error("RemoveNote() should succeed once")
+	}
+}
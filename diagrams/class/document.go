@@ -0,0 +1,427 @@
+package class
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// DocumentType is the type of class diagram JSON documents.
+const DocumentType string = "class"
+
+// Element and value kinds named in document errors.
+const (
+	documentElementClass    string = "class"
+	documentValueAnnotation string = "annotation"
+	documentValueVisibility string = "visibility"
+	documentValueClassifier string = "classifier"
+	documentValueRelation   string = "relation type"
+	documentValueLink       string = "relation link"
+	cardinalityQuote        string = `"`
+)
+
+// annotationNames maps class annotations to their names in documents.
+var annotationNames = map[classAnnotation]string{
+	ClassAnnotationInterface:   "interface",
+	ClassAnnotationAbstract:    "abstract",
+	ClassAnnotationService:     "service",
+	ClassAnnotationEnumeration: "enumeration",
+}
+
+// fieldVisibilityNames maps field visibilities to their names in documents.
+var fieldVisibilityNames = map[fieldVisibility]string{
+	"":                       "none",
+	FieldVisibilityPublic:    "public",
+	FieldVisibilityPrivate:   "private",
+	FieldVisibilityProtected: "protected",
+	FieldVisibilityInternal:  "internal",
+}
+
+// fieldClassifierNames maps field classifiers to their names in documents.
+var fieldClassifierNames = map[fieldClassifier]string{
+	FieldClassifierStatic: "static",
+}
+
+// methodVisibilityNames maps method visibilities to their names in documents.
+var methodVisibilityNames = map[methodVisibility]string{
+	"":                        "none",
+	MethodVisibilityPublic:    "public",
+	MethodVisibilityPrivate:   "private",
+	MethodVisibilityProtected: "protected",
+	MethodVisibilityInternal:  "internal",
+}
+
+// methodClassifierNames maps method classifiers to their names in documents.
+var methodClassifierNames = map[methodClassifier]string{
+	MethodClassifierAbstract: "abstract",
+	MethodClassifierStatic:   "static",
+}
+
+// relationTypeNames maps relation ends to their names in documents.
+var relationTypeNames = map[relationType]string{
+	RelationTypeAssociation:     "association",
+	RelationTypeAssociationLeft: "associationLeft",
+	RelationTypeInheritance:     "inheritance",
+	RelationTypeInheritanceLeft: "inheritanceLeft",
+	RelationTypeComposition:     "composition",
+	RelationTypeAggregation:     "aggregation",
+}
+
+// relationLinkNames maps relation lines to their names in documents.
+var relationLinkNames = map[relationLink]string{
+	RelationLinkSolid:  "solid",
+	RelationLinkDashed: "dashed",
+}
+
+// diagramDocument is the JSON form of a class diagram. Classes are declared at the top
+// level or in their namespace, and relations and notes reference them by name.
+type diagramDocument struct {
+	basediagram.Document
+	Direction  classDiagramDirection `json:"direction,omitempty"`
+	Namespaces []namespaceDocument   `json:"namespaces,omitempty"`
+	Classes    []classDocument       `json:"classes,omitempty"`
+	Relations  []relationDocument    `json:"relations,omitempty"`
+	Notes      []noteDocument        `json:"notes,omitempty"`
+}
+
+type namespaceDocument struct {
+	Name       string              `json:"name"`
+	Classes    []classDocument     `json:"classes,omitempty"`
+	Namespaces []namespaceDocument `json:"namespaces,omitempty"`
+}
+
+type classDocument struct {
+	Name       string           `json:"name"`
+	Label      string           `json:"label,omitempty"`
+	Annotation string           `json:"annotation,omitempty"`
+	Fields     []fieldDocument  `json:"fields,omitempty"`
+	Methods    []methodDocument `json:"methods,omitempty"`
+}
+
+// fieldDocument omits the visibility of public fields.
+type fieldDocument struct {
+	Name       string `json:"name"`
+	Type       string `json:"type,omitempty"`
+	Visibility string `json:"visibility,omitempty"`
+	Classifier string `json:"classifier,omitempty"`
+}
+
+// methodDocument omits the visibility of public methods.
+type methodDocument struct {
+	Name       string              `json:"name"`
+	Parameters []parameterDocument `json:"parameters,omitempty"`
+	ReturnType string              `json:"returnType,omitempty"`
+	Visibility string              `json:"visibility,omitempty"`
+	Classifier string              `json:"classifier,omitempty"`
+}
+
+type parameterDocument struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+// relationDocument holds cardinalities without their quotes and omits a solid link.
+type relationDocument struct {
+	ClassA              string `json:"classA"`
+	ClassB              string `json:"classB"`
+	RelationToClassA    string `json:"relationToClassA,omitempty"`
+	RelationToClassB    string `json:"relationToClassB,omitempty"`
+	CardinalityToClassA string `json:"cardinalityToClassA,omitempty"`
+	CardinalityToClassB string `json:"cardinalityToClassB,omitempty"`
+	Link                string `json:"link,omitempty"`
+	Label               string `json:"label,omitempty"`
+}
+
+// noteDocument omits the class of notes about the whole diagram.
+type noteDocument struct {
+	Text  string `json:"text"`
+	Class string `json:"class,omitempty"`
+}
+
+// MarshalJSON encodes the class diagram as a versioned JSON document. Relations and notes
+// reference classes by name, so every class they use must be part of the diagram.
+func (cd *ClassDiagram) MarshalJSON() ([]byte, error) {
+	config, err := cd.Config.EncodeDocument(cd.Config.properties)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := diagramDocument{
+		Document:  cd.EncodeDocument(DocumentType, config),
+		Direction: cd.Direction,
+	}
+
+	for _, namespace := range cd.namespaces {
+		namespaceDoc, err := encodeNamespace(namespace)
+		if err != nil {
+			return nil, err
+		}
+		doc.Namespaces = append(doc.Namespaces, namespaceDoc)
+	}
+
+	if doc.Classes, err = encodeClasses(cd.classes); err != nil {
+		return nil, err
+	}
+
+	classes := make(map[*Class]bool)
+	for _, class := range cd.Classes() {
+		classes[class] = true
+	}
+
+	for _, relation := range cd.relations {
+		relationDoc := relationDocument{
+			CardinalityToClassA: strings.Trim(string(relation.CardinalityToClassA), cardinalityQuote),
+			CardinalityToClassB: strings.Trim(string(relation.CardinalityToClassB), cardinalityQuote),
+			Label:               relation.Label,
+		}
+		if relationDoc.ClassA, err = encodeClass(relation.ClassA, classes); err != nil {
+			return nil, err
+		}
+		if relationDoc.ClassB, err = encodeClass(relation.ClassB, classes); err != nil {
+			return nil, err
+		}
+		if relationDoc.RelationToClassA, err = basediagram.EncodeName(relationTypeNames, relation.RelationToClassA, "", documentValueRelation); err != nil {
+			return nil, err
+		}
+		if relationDoc.RelationToClassB, err = basediagram.EncodeName(relationTypeNames, relation.RelationToClassB, "", documentValueRelation); err != nil {
+			return nil, err
+		}
+		if relationDoc.Link, err = basediagram.EncodeName(relationLinkNames, relation.Link, RelationLinkSolid, documentValueLink); err != nil {
+			return nil, err
+		}
+		doc.Relations = append(doc.Relations, relationDoc)
+	}
+
+	for _, note := range cd.notes {
+		noteDoc := noteDocument{Text: note.Text}
+		if note.Class != nil {
+			if noteDoc.Class, err = encodeClass(note.Class, classes); err != nil {
+				return nil, err
+			}
+		}
+		doc.Notes = append(doc.Notes, noteDoc)
+	}
+
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON replaces the class diagram with the one described by a JSON document and
+// re-links relations and notes to the classes.
+func (cd *ClassDiagram) UnmarshalJSON(data []byte) error {
+	var doc diagramDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	decoded := NewClassDiagram()
+	if err := decoded.DecodeDocument(DocumentType, doc.Document); err != nil {
+		return err
+	}
+
+	var err error
+	if decoded.Config.ConfigurationProperties, decoded.Config.properties, err = doc.Config.Decode(); err != nil {
+		return err
+	}
+
+	if doc.Direction != "" {
+		decoded.Direction = doc.Direction
+	}
+
+	classes := make(map[string]*Class)
+
+	for _, namespaceDoc := range doc.Namespaces {
+		namespace, err := decodeNamespace(namespaceDoc, classes)
+		if err != nil {
+			return err
+		}
+		decoded.namespaces = append(decoded.namespaces, namespace)
+	}
+
+	if decoded.classes, err = decodeClasses(doc.Classes, classes); err != nil {
+		return err
+	}
+
+	for _, relationDoc := range doc.Relations {
+		relation := NewRelation(classes[relationDoc.ClassA], classes[relationDoc.ClassB])
+		if relation.ClassA == nil {
+			return basediagram.UnknownReference(documentElementClass, relationDoc.ClassA)
+		}
+		if relation.ClassB == nil {
+			return basediagram.UnknownReference(documentElementClass, relationDoc.ClassB)
+		}
+		relation.CardinalityToClassA = decodeCardinality(relationDoc.CardinalityToClassA)
+		relation.CardinalityToClassB = decodeCardinality(relationDoc.CardinalityToClassB)
+		relation.Label = relationDoc.Label
+		if relation.RelationToClassA, err = basediagram.DecodeName(relationTypeNames, relationDoc.RelationToClassA, "", documentValueRelation); err != nil {
+			return err
+		}
+		if relation.RelationToClassB, err = basediagram.DecodeName(relationTypeNames, relationDoc.RelationToClassB, "", documentValueRelation); err != nil {
+			return err
+		}
+		if relation.Link, err = basediagram.DecodeName(relationLinkNames, relationDoc.Link, RelationLinkSolid, documentValueLink); err != nil {
+			return err
+		}
+		decoded.relations = append(decoded.relations, relation)
+	}
+
+	for _, noteDoc := range doc.Notes {
+		note := NewNote(noteDoc.Text, nil)
+		if noteDoc.Class != "" {
+			if note.Class = classes[noteDoc.Class]; note.Class == nil {
+				return basediagram.UnknownReference(documentElementClass, noteDoc.Class)
+			}
+		}
+		decoded.notes = append(decoded.notes, note)
+	}
+
+	*cd = *decoded
+
+	return nil
+}
+
+// encodeClass returns the name of a class of the diagram.
+func encodeClass(class *Class, classes map[*Class]bool) (string, error) {
+	if class == nil {
+		return "", basediagram.UnknownReference(documentElementClass, "")
+	}
+	if !classes[class] {
+		return "", basediagram.UnknownReference(documentElementClass, class.Name)
+	}
+
+	return class.Name, nil
+}
+
+// decodeCardinality returns a relation cardinality with its quotes.
+func decodeCardinality(cardinality string) relationCardinality {
+	if cardinality == "" {
+		return ""
+	}
+
+	return relationCardinality(cardinalityQuote + cardinality + cardinalityQuote)
+}
+
+// encodeNamespace returns the document of a namespace with its classes and nested
+// namespaces.
+func encodeNamespace(namespace *Namespace) (doc namespaceDocument, err error) {
+	doc.Name = namespace.Name
+
+	if doc.Classes, err = encodeClasses(namespace.Classes); err != nil {
+		return
+	}
+
+	for _, child := range namespace.Children {
+		childDoc, err := encodeNamespace(child)
+		if err != nil {
+			return doc, err
+		}
+		doc.Namespaces = append(doc.Namespaces, childDoc)
+	}
+
+	return
+}
+
+// decodeNamespace returns the namespace described by the document and records its classes
+// by name.
+func decodeNamespace(doc namespaceDocument, classes map[string]*Class) (namespace *Namespace, err error) {
+	namespace = NewNamespace(doc.Name)
+
+	if namespace.Classes, err = decodeClasses(doc.Classes, classes); err != nil {
+		return nil, err
+	}
+
+	for _, childDoc := range doc.Namespaces {
+		child, err := decodeNamespace(childDoc, classes)
+		if err != nil {
+			return nil, err
+		}
+		namespace.Children = append(namespace.Children, child)
+	}
+
+	return
+}
+
+// encodeClasses returns the documents of classes with their fields and methods.
+func encodeClasses(classes []*Class) (docs []classDocument, err error) {
+	for _, class := range classes {
+		doc := classDocument{Name: class.Name, Label: class.Label}
+		if doc.Annotation, err = basediagram.EncodeName(annotationNames, class.Annotation, ClassAnnotationNone, documentValueAnnotation); err != nil {
+			return nil, err
+		}
+
+		for _, field := range class.fields {
+			fieldDoc := fieldDocument{Name: field.Name, Type: field.Type}
+			if fieldDoc.Visibility, err = basediagram.EncodeName(fieldVisibilityNames, field.Visibility, FieldVisibilityPublic, documentValueVisibility); err != nil {
+				return nil, err
+			}
+			if fieldDoc.Classifier, err = basediagram.EncodeName(fieldClassifierNames, field.Classifier, "", documentValueClassifier); err != nil {
+				return nil, err
+			}
+			doc.Fields = append(doc.Fields, fieldDoc)
+		}
+
+		for _, method := range class.methods {
+			methodDoc := methodDocument{Name: method.Name, ReturnType: method.ReturnType}
+			for _, parameter := range method.Parameters {
+				methodDoc.Parameters = append(methodDoc.Parameters, parameterDocument{Name: parameter.Name, Type: parameter.Type})
+			}
+			if methodDoc.Visibility, err = basediagram.EncodeName(methodVisibilityNames, method.Visibility, MethodVisibilityPublic, documentValueVisibility); err != nil {
+				return nil, err
+			}
+			if methodDoc.Classifier, err = basediagram.EncodeName(methodClassifierNames, method.Classifier, "", documentValueClassifier); err != nil {
+				return nil, err
+			}
+			doc.Methods = append(doc.Methods, methodDoc)
+		}
+
+		docs = append(docs, doc)
+	}
+
+	return
+}
+
+// decodeClasses returns the classes described by the documents and records them by name.
+func decodeClasses(docs []classDocument, classes map[string]*Class) (decoded []*Class, err error) {
+	for _, doc := range docs {
+		if classes[doc.Name] != nil {
+			return nil, basediagram.DuplicateID(documentElementClass, doc.Name)
+		}
+
+		class := NewClass(doc.Name)
+		class.Label = doc.Label
+		if class.Annotation, err = basediagram.DecodeName(annotationNames, doc.Annotation, ClassAnnotationNone, documentValueAnnotation); err != nil {
+			return nil, err
+		}
+
+		for _, fieldDoc := range doc.Fields {
+			field := NewField(fieldDoc.Name, fieldDoc.Type)
+			if field.Visibility, err = basediagram.DecodeName(fieldVisibilityNames, fieldDoc.Visibility, FieldVisibilityPublic, documentValueVisibility); err != nil {
+				return nil, err
+			}
+			if field.Classifier, err = basediagram.DecodeName(fieldClassifierNames, fieldDoc.Classifier, "", documentValueClassifier); err != nil {
+				return nil, err
+			}
+			class.fields = append(class.fields, field)
+		}
+
+		for _, methodDoc := range doc.Methods {
+			method := NewMethod(methodDoc.Name)
+			method.ReturnType = methodDoc.ReturnType
+			for _, parameterDoc := range methodDoc.Parameters {
+				method.Parameters = append(method.Parameters, Parameter{Name: parameterDoc.Name, Type: parameterDoc.Type})
+			}
+			if method.Visibility, err = basediagram.DecodeName(methodVisibilityNames, methodDoc.Visibility, MethodVisibilityPublic, documentValueVisibility); err != nil {
+				return nil, err
+			}
+			if method.Classifier, err = basediagram.DecodeName(methodClassifierNames, methodDoc.Classifier, "", documentValueClassifier); err != nil {
+				return nil, err
+			}
+			class.methods = append(class.methods, method)
+		}
+
+		classes[class.Name] = class
+		decoded = append(decoded, class)
+	}
+
+	return
+}
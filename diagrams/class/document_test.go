@@ -0,0 +1,130 @@
+package class
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+func TestClassDiagram_JSONRoundTrip(t *testing.T) {
+	original := NewClassDiagram()
+	original.Title = "Zoo"
+	original.SetDirection(ClassDiagramDirectionLeftRight)
+	original.Config.SetHideEmptyMembersBox(true)
+	namespace := original.AddNamespace("Birds")
+	namespace.AddNamespace("Water")
+	animal := original.AddClass("Animal", nil).SetAnnotation(ClassAnnotationAbstract).SetLabel("Any animal")
+	animal.AddField("name", "string").SetVisibility(FieldVisibilityPrivate)
+	animal.AddField("count", "int").Classifier = FieldClassifierStatic
+	speak := animal.AddMethod("Speak").SetReturnType("string").SetClassifier(MethodClassifierAbstract)
+	speak.AddParameter("volume", "int")
+	duck := original.AddClass("Duck", namespace)
+	relation := original.AddRelation(duck, animal)
+	relation.RelationToClassB = RelationTypeInheritance
+	relation.CardinalityToClassA = RelationCardinalityOneOrMore
+	relation.Link = RelationLinkDashed
+	relation.Label = "is a"
+	original.AddNote("Quacks", duck)
+	original.AddNote("Everything", nil)
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	decoded := NewClassDiagram()
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if decoded.String() != original.String() {
+		t.Errorf("round trip output differs:\nwant:\n%s\ngot:\n%s", original.String(), decoded.String())
+	}
+
+	decodedDuck := decoded.FindClass("Duck")
+	if decoded.Relations()[0].ClassA != decodedDuck || decoded.Notes()[0].Class != decodedDuck {
+		t.Error("decoded relations and notes should reference decoded classes")
+	}
+	if decoded.NamespaceOf(decodedDuck) != decoded.FindNamespace("Birds") {
+		t.Error("decoded class should be declared in its namespace")
+	}
+
+	again, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("Marshal() of decoded diagram error = %v", err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("encoding is not stable:\nfirst:  %s\nsecond: %s", data, again)
+	}
+}
+
+func TestClassDiagram_MarshalJSON(t *testing.T) {
+	cd := NewClassDiagram()
+	a := cd.AddClass("A", nil)
+	a.AddField("id", "int")
+	cd.AddRelation(a, a).CardinalityToClassB = RelationCardinalityMany
+
+	data, err := json.Marshal(cd)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `{"version":1,"type":"class","direction":"TB","classes":[{"name":"A","fields":[{"name":"id","type":"int"}]}],` +
+		`"relations":[{"classA":"A","classB":"A","cardinalityToClassB":"*"}]}`
+	if string(data) != want {
+		t.Errorf("Marshal() =\n%s\nwant\n%s", data, want)
+	}
+
+	cd.AddNote("Outside", NewClass("B"))
+	if _, err := json.Marshal(cd); !errors.Is(err, basediagram.ErrUnknownReference) {
+		t.Errorf("Marshal() with a class outside the diagram error = %v, want %v", err, basediagram.ErrUnknownReference)
+	}
+}
+
+func TestClassDiagram_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{
+			name:  "Valid document",
+			input: `{"version":1,"type":"class","classes":[{"name":"A","methods":[{"name":"run","visibility":"private"}]}]}`,
+		},
+		{
+			name:  "Unknown relation class",
+			input: `{"version":1,"type":"class","classes":[{"name":"A"}],"relations":[{"classA":"A","classB":"B"}]}`,
+			want:  basediagram.ErrUnknownReference,
+		},
+		{
+			name:  "Unknown note class",
+			input: `{"version":1,"type":"class","notes":[{"text":"x","class":"A"}]}`,
+			want:  basediagram.ErrUnknownReference,
+		},
+		{
+			name:  "Class declared twice",
+			input: `{"version":1,"type":"class","namespaces":[{"name":"N","classes":[{"name":"A"}]}],"classes":[{"name":"A"}]}`,
+			want:  basediagram.ErrDuplicateID,
+		},
+		{
+			name:  "Unknown visibility",
+			input: `{"version":1,"type":"class","classes":[{"name":"A","fields":[{"name":"x","visibility":"secret"}]}]}`,
+			want:  basediagram.ErrUnknownValue,
+		},
+		{
+			name:  "Unknown relation type",
+			input: `{"version":1,"type":"class","classes":[{"name":"A"}],"relations":[{"classA":"A","classB":"A","relationToClassA":"friend"}]}`,
+			want:  basediagram.ErrUnknownValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(tt.input), NewClassDiagram()); !errors.Is(err, tt.want) {
+				t.Errorf("Unmarshal() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
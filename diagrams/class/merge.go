@@ -0,0 +1,259 @@
+package class
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// Merge conflict element kinds reported by ClassDiagram.Merge.
+const (
+	MergeElementClass  string = "class"
+	MergeElementField  string = "field"
+	MergeElementMethod string = "method"
+)
+
+const (
+	mergeReasonLabel      string = "label differs (%q vs %q)"
+	mergeReasonAnnotation string = "annotation differs (%q vs %q)"
+	mergeReasonMember     string = "definition differs (%q vs %q)"
+	mergeMemberName       string = "%s.%s"
+)
+
+// Clone returns a deep copy of the class diagram.
+// Relations and notes of the copy reference the copied classes.
+func (cd *ClassDiagram) Clone() *ClassDiagram {
+	classes := make(map[*Class]*Class)
+
+	cloneClass := func(class *Class) *Class {
+		if class == nil {
+			return nil
+		}
+		if cloned, ok := classes[class]; ok {
+			return cloned
+		}
+		cloned := class.Clone()
+		classes[class] = cloned
+		return cloned
+	}
+
+	clone := &ClassDiagram{
+		BaseDiagram: cd.BaseDiagram,
+		Direction:   cd.Direction,
+	}
+	clone.Config = cd.Config.Clone()
+
+	for _, namespace := range cd.namespaces {
+		clone.namespaces = append(clone.namespaces, namespace.clone(cloneClass))
+	}
+
+	for _, class := range cd.classes {
+		clone.classes = append(clone.classes, cloneClass(class))
+	}
+
+	for _, relation := range cd.relations {
+		cloned := *relation
+		cloned.ClassA = cloneClass(relation.ClassA)
+		cloned.ClassB = cloneClass(relation.ClassB)
+		clone.relations = append(clone.relations, &cloned)
+	}
+
+	for _, note := range cd.notes {
+		clone.notes = append(clone.notes, NewNote(note.Text, cloneClass(note.Class)))
+	}
+
+	return clone
+}
+
+// Clone returns a deep copy of the class, including its fields and methods.
+func (c *Class) Clone() *Class {
+	clone := &Class{
+		Name:       c.Name,
+		Label:      c.Label,
+		Annotation: c.Annotation,
+	}
+
+	for _, field := range c.fields {
+		cloned := *field
+		clone.fields = append(clone.fields, &cloned)
+	}
+
+	for _, method := range c.methods {
+		clone.methods = append(clone.methods, method.clone())
+	}
+
+	return clone
+}
+
+// Merge copies the namespaces, classes, relations and notes of other into the diagram
+// and returns the conflicts that were encountered. The other diagram is not modified.
+//
+// Classes are matched by name, wherever they are declared. When a class exists in both
+// diagrams its fields and methods are unioned by name; a label, annotation or member
+// defined differently in both is reported as a conflict and the existing definition is
+// kept. New classes are placed in the namespace of the same name, which is created when
+// missing. Duplicate relations and notes are skipped.
+func (cd *ClassDiagram) Merge(other *ClassDiagram) (conflicts []basediagram.MergeConflict) {
+	incoming := other.Clone()
+	classMap := make(map[*Class]*Class)
+
+	for _, class := range incoming.Classes() {
+		if existing := cd.FindClass(class.Name); existing != nil {
+			classMap[class] = existing
+			conflicts = append(conflicts, existing.merge(class)...)
+		}
+	}
+
+	for _, class := range incoming.classes {
+		if _, ok := classMap[class]; !ok {
+			cd.classes = append(cd.classes, class)
+		}
+	}
+
+	cd.namespaces = mergeNamespaces(cd.namespaces, incoming.namespaces, classMap)
+
+	mapClass := func(class *Class) *Class {
+		if mapped, ok := classMap[class]; ok {
+			return mapped
+		}
+		return class
+	}
+
+	for _, relation := range incoming.relations {
+		relation.ClassA = mapClass(relation.ClassA)
+		relation.ClassB = mapClass(relation.ClassB)
+		if !cd.containsRelation(relation) {
+			cd.relations = append(cd.relations, relation)
+		}
+	}
+
+	for _, note := range incoming.notes {
+		note.Class = mapClass(note.Class)
+		if !cd.containsNote(note) {
+			cd.notes = append(cd.notes, note)
+		}
+	}
+
+	return
+}
+
+// mergeNamespaces adds the classes of incoming namespaces that are not mapped to an existing
+// class into the namespace with the same name, creating namespaces where needed.
+func mergeNamespaces(existing []*Namespace, incoming []*Namespace, classMap map[*Class]*Class) []*Namespace {
+	for _, namespace := range incoming {
+		var target *Namespace
+		for _, current := range existing {
+			if current.Name == namespace.Name {
+				target = current
+				break
+			}
+		}
+
+		created := target == nil
+		if created {
+			target = NewNamespace(namespace.Name)
+		}
+
+		for _, class := range namespace.Classes {
+			if _, ok := classMap[class]; !ok {
+				target.AddClass(class)
+			}
+		}
+
+		target.Children = mergeNamespaces(target.Children, namespace.Children, classMap)
+
+		if created && (len(target.Classes) > 0 || len(target.Children) > 0) {
+			existing = append(existing, target)
+		}
+	}
+
+	return existing
+}
+
+// containsRelation reports whether the diagram has a relation equivalent to relation.
+func (cd *ClassDiagram) containsRelation(relation *Relation) bool {
+	for _, current := range cd.relations {
+		if *current == *relation {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containsNote reports whether the diagram has a note equivalent to note.
+func (cd *ClassDiagram) containsNote(note *Note) bool {
+	for _, current := range cd.notes {
+		if current.Text == note.Text && current.Class == note.Class {
+			return true
+		}
+	}
+
+	return false
+}
+
+// merge unions the members of incoming into the class and returns the conflicts.
+func (c *Class) merge(incoming *Class) (conflicts []basediagram.MergeConflict) {
+	conflict := func(element string, id string, reason string) {
+		conflicts = append(conflicts, basediagram.MergeConflict{Element: element, ID: id, Reason: reason})
+	}
+
+	switch {
+	case incoming.Label == "" || c.Label == incoming.Label:
+	case c.Label == "":
+		c.Label = incoming.Label
+	default:
+		conflict(MergeElementClass, c.Name, fmt.Sprintf(mergeReasonLabel, c.Label, incoming.Label))
+	}
+
+	switch {
+	case incoming.Annotation == ClassAnnotationNone || c.Annotation == incoming.Annotation:
+	case c.Annotation == ClassAnnotationNone:
+		c.Annotation = incoming.Annotation
+	default:
+		conflict(MergeElementClass, c.Name, fmt.Sprintf(mergeReasonAnnotation, c.Annotation, incoming.Annotation))
+	}
+
+	for _, field := range incoming.fields {
+		existing := c.FindField(field.Name)
+		if existing == nil {
+			c.fields = append(c.fields, field)
+		} else if *existing != *field {
+			conflict(MergeElementField, fmt.Sprintf(mergeMemberName, c.Name, field.Name), fmt.Sprintf(mergeReasonMember, strings.TrimSpace(existing.String()), strings.TrimSpace(field.String())))
+		}
+	}
+
+	for _, method := range incoming.methods {
+		existing := c.FindMethod(method.Name)
+		if existing == nil {
+			c.methods = append(c.methods, method)
+		} else if existing.String() != method.String() {
+			conflict(MergeElementMethod, fmt.Sprintf(mergeMemberName, c.Name, method.Name), fmt.Sprintf(mergeReasonMember, strings.TrimSpace(existing.String()), strings.TrimSpace(method.String())))
+		}
+	}
+
+	return
+}
+
+// clone returns a copy of the namespace tree, copying classes with cloneClass.
+func (n *Namespace) clone(cloneClass func(*Class) *Class) *Namespace {
+	clone := NewNamespace(n.Name)
+
+	for _, class := range n.Classes {
+		clone.Classes = append(clone.Classes, cloneClass(class))
+	}
+
+	for _, child := range n.Children {
+		clone.Children = append(clone.Children, child.clone(cloneClass))
+	}
+
+	return clone
+}
+
+// clone returns a copy of the method that does not share its parameters.
+func (m *Method) clone() *Method {
+	clone := *m
+	clone.Parameters = append([]Parameter(nil), m.Parameters...)
+	return &clone
+}
@@ -0,0 +1,106 @@
+package class
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassDiagram_Clone(t *testing.T) {
+	original := NewClassDiagram()
+	original.Title = "Zoo"
+	namespace := original.AddNamespace("Birds")
+	animal := original.AddClass("Animal", nil).SetAnnotation(ClassAnnotationAbstract)
+	animal.AddField("name", "string")
+	animal.AddMethod("Speak").AddParameter("volume", "int")
+	duck := original.AddClass("Duck", namespace)
+	original.AddRelation(duck, animal).RelationToClassB = RelationTypeInheritance
+	original.AddNote("Quacks", duck)
+
+	clone := original.Clone()
+
+	if clone.String() != original.String() {
+		t.Errorf("Clone() output differs:\nwant:\n%s\ngot:\n%s", original.String(), clone.String())
+	}
+
+	clonedAnimal := clone.FindClass("Animal")
+	clonedDuck := clone.FindClass("Duck")
+	if clonedAnimal == animal || clonedDuck == duck {
+		t.Fatal("Clone() should copy classes")
+	}
+
+	if clone.Relations()[0].ClassA != clonedDuck || clone.Notes()[0].Class != clonedDuck {
+		t.Error("Clone() relations and notes should reference cloned classes")
+	}
+
+	clonedAnimal.Methods()[0].AddParameter("pitch", "float")
+	clonedAnimal.AddField("age", "int")
+	clone.FindNamespace("Birds").AddClass(NewClass("Goose"))
+
+	if len(animal.Methods()[0].Parameters) != 1 || len(animal.Fields()) != 1 || len(namespace.Classes) != 1 {
+		t.Error("Modifying the clone changed the original")
+	}
+}
+
+func TestClassDiagram_Merge(t *testing.T) {
+	base := NewClassDiagram()
+	animal := base.AddClass("Animal", nil).SetLabel("Animal")
+	animal.AddField("name", "string")
+	base.AddClass("Cat", nil)
+
+	other := NewClassDiagram()
+	birds := other.AddNamespace("Birds")
+	otherAnimal := other.AddClass("Animal", nil).SetLabel("Creature")
+	otherAnimal.AddField("name", "int")
+	otherAnimal.AddMethod("Eat")
+	duck := other.AddClass("Duck", birds)
+	other.AddRelation(duck, otherAnimal)
+	other.AddNote("Quacks", duck)
+	otherOutput := other.String()
+
+	conflicts := base.Merge(other)
+
+	names := []string{}
+	for _, class := range base.Classes() {
+		names = append(names, class.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"Animal", "Cat", "Duck"}) {
+		t.Errorf("Merge() classes = %v", names)
+	}
+
+	mergedDuck := base.FindClass("Duck")
+	if base.FindNamespace("Birds") == nil || base.NamespaceOf(mergedDuck) == nil {
+		t.Error("Merge() should place new classes in their namespace")
+	}
+
+	if animal.FindMethod("Eat") == nil {
+		t.Error("Merge() should union methods")
+	}
+
+	if len(base.Relations()) != 1 || base.Relations()[0].ClassB != animal {
+		t.Error("Merge() relations should reference existing classes")
+	}
+
+	if len(base.NotesOf(mergedDuck)) != 1 {
+		t.Error("Merge() should copy notes")
+	}
+
+	got := []string{}
+	for _, conflict := range conflicts {
+		got = append(got, conflict.Error())
+	}
+	want := []string{
+		`class "Animal": label differs ("Animal" vs "Creature")`,
+		`field "Animal.name": definition differs ("+string name" vs "+int name")`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() conflicts = %v, want %v", got, want)
+	}
+
+	if other.String() != otherOutput {
+		t.Error("Merge() modified the other diagram")
+	}
+
+	if conflicts := base.Merge(other); len(base.Relations()) != 1 || len(base.Notes()) != 1 || len(conflicts) != 2 {
+		t.Error("Merging the same diagram twice should not duplicate relations or notes")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
 	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
 )
 
@@ -57,3 +58,70 @@ func (n *Namespace) String(curIndentation string) string {
 
 	return sb.String()
 }
+
+// FindNamespace returns the namespace itself or a nested namespace with the given name, or nil if there is none.
+func (n *Namespace) FindNamespace(name string) *Namespace {
+	if n.Name == name {
+		return n
+	}
+
+	for _, child := range n.Children {
+		if found := child.FindNamespace(name); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// RemoveClass removes a class declared directly in this namespace.
+// It returns false if the class is not part of the namespace.
+func (n *Namespace) RemoveClass(class *Class) (found bool) {
+	n.Classes, found = utils.RemoveItem(n.Classes, class)
+	return
+}
+
+// allClasses returns the classes of this namespace and all nested namespaces.
+func (n *Namespace) allClasses() []*Class {
+	classes := utils.CopySlice(n.Classes)
+
+	for _, child := range n.Children {
+		classes = append(classes, child.allClasses()...)
+	}
+
+	return classes
+}
+
+// namespaceOf returns the namespace below n that declares the class.
+func (n *Namespace) namespaceOf(class *Class) *Namespace {
+	for _, current := range n.Classes {
+		if current == class {
+			return n
+		}
+	}
+
+	for _, child := range n.Children {
+		if found := child.namespaceOf(class); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// removeNamespaceRecursive removes a namespace nested anywhere below n.
+func (n *Namespace) removeNamespaceRecursive(target *Namespace) bool {
+	var found bool
+
+	if n.Children, found = utils.RemoveItem(n.Children, target); found {
+		return true
+	}
+
+	for _, child := range n.Children {
+		if child.removeNamespaceRecursive(target) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -211,3 +211,38 @@ func TestNamespace_AddNamespace(t *testing.T) {
 		})
 	}
 }
+
+func TestNamespace_FindNamespace(t *testing.T) {
+	root := NewNamespace("Root")
+	child := root.AddNamespace("Child")
+
+	if root.FindNamespace("Root") != root {
+		t.Error("FindNamespace() should find itself")
+	}
+
+	if root.FindNamespace("Child") != child {
+		t.Error("FindNamespace() should find nested namespace")
+	}
+
+	if root.FindNamespace("Missing") != nil {
+		t.Error("FindNamespace() should return nil for missing namespace")
+	}
+}
+
+func TestNamespace_RemoveClass(t *testing.T) {
+	namespace := NewNamespace("Zoo")
+	class := NewClass("Duck")
+	namespace.AddClass(class)
+
+	if !namespace.RemoveClass(class) {
+		t.Error("RemoveClass() = false, want true")
+	}
+
+	if len(namespace.Classes) != 0 {
+		t.Errorf("RemoveClass() left %d classes, want 0", len(namespace.Classes))
+	}
+
+	if namespace.RemoveClass(class) {
+		t.Error("RemoveClass() on removed class = true, want false")
+	}
+}
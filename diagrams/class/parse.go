@@ -0,0 +1,319 @@
+package class
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// Keywords of class diagram source.
+const (
+	keywordClassDiagram = "classDiagram"
+	keywordClass        = "class"
+	keywordNamespace    = "namespace"
+	keywordNote         = "note"
+	keywordDirection    = "direction"
+	bodyOpen            = "{"
+	bodyClose           = "}"
+
+	// configKey is the frontmatter configuration member holding the class properties.
+	configKey = "class"
+)
+
+// Statement patterns.
+var (
+	declarationPattern = regexp.MustCompile(`^(\w+)\s*(?:\["([^"]*)"\])?\s*(\{\}|\{)?$`)
+	relationPattern    = regexp.MustCompile(`^(\w+)\s*(?:"([^"]*)"\s*)?(<\||\*|o|<)?(--|\.\.)(\|>|\*|>|o\s)?\s*(?:"([^"]*)"\s*)?(\w+)\s*(?::(.*))?$`)
+	memberPattern      = regexp.MustCompile(`^(\w+)\s*:(.*)$`)
+	methodPattern      = regexp.MustCompile(`^([+\-#~]?)([^(\s]+)\((.*)\)([$*]?)\s*(.*)$`)
+	fieldPattern       = regexp.MustCompile(`^([+\-#~]?)(.+?)\s*(\$?)$`)
+	parameterPattern   = regexp.MustCompile(`^\s*(\w+)\s*:\s*(\S+)\s*$`)
+	annotationPattern  = regexp.MustCompile(`^(<<\w+>>)\s*(\w+)?$`)
+	notePattern        = regexp.MustCompile(`^(?:for\s+(\w+)\s+)?"(.*)"$`)
+)
+
+// annotations holds the class annotations of the model.
+var annotations = map[string]classAnnotation{
+	string(ClassAnnotationInterface):   ClassAnnotationInterface,
+	string(ClassAnnotationAbstract):    ClassAnnotationAbstract,
+	string(ClassAnnotationService):     ClassAnnotationService,
+	string(ClassAnnotationEnumeration): ClassAnnotationEnumeration,
+}
+
+// directions holds the directions of class diagrams.
+var directions = map[string]classDiagramDirection{
+	string(ClassDiagramDirectionTopToBottom): ClassDiagramDirectionTopToBottom,
+	string(ClassDiagramDirectionBottomUp):    ClassDiagramDirectionBottomUp,
+	string(ClassDiagramDirectionRightLeft):   ClassDiagramDirectionRightLeft,
+	string(ClassDiagramDirectionLeftRight):   ClassDiagramDirectionLeftRight,
+}
+
+// unsupportedKeywords start the statements the model has no counterpart for.
+var unsupportedKeywords = []string{"click", "link", "callback", "style", "classDef", "cssClass", "accTitle", "accDescr"}
+
+// Parse returns the class diagram described by Mermaid source, such as the output of
+// String. Classes are created by their declaration or by their first mention. Errors are
+// *basediagram.SyntaxError values: invalid syntax wraps basediagram.ErrSyntax, statements
+// the model cannot represent, such as generic classes, styles, nested namespaces and
+// parameters not written as name:type, wrap basediagram.ErrUnsupported, and notes for
+// unknown classes wrap basediagram.ErrUnknownReference. Unsupported statements are
+// reported together, see basediagram.Source.Read.
+func Parse(source string) (*ClassDiagram, error) {
+	parsed, err := basediagram.ParseSource(source, configKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed.Header.Text != keywordClassDiagram {
+		return nil, basediagram.Syntax(parsed.Header.Line, "expected %s, found %q", keywordClassDiagram, parsed.Header.Text)
+	}
+
+	p := &classParser{diagram: NewClassDiagram()}
+	if err = parsed.Read(p.statement); err != nil {
+		return nil, err
+	}
+	if p.class != nil || p.namespace != nil {
+		return nil, basediagram.Syntax(p.openLine, "%s is not closed", bodyOpen)
+	}
+	if err = p.resolveNotes(); err != nil {
+		return nil, err
+	}
+
+	d := p.diagram
+	d.DecodeSource(parsed)
+	if d.Config.ConfigurationProperties, d.Config.properties, err = parsed.Config.Decode(); err != nil {
+		return nil, basediagram.AtLine(1, err)
+	}
+
+	return d, nil
+}
+
+// classParser builds a class diagram statement by statement. A class body or a namespace
+// may be open.
+type classParser struct {
+	diagram   *ClassDiagram
+	namespace *Namespace
+	class     *Class
+	openLine  int
+	notes     []noteReference
+}
+
+// noteReference is a note for a class named before the end of the source.
+type noteReference struct {
+	statement basediagram.Statement
+	note      *Note
+	name      string
+}
+
+// statement reads a statement of the diagram body, or a member of the open class.
+func (p *classParser) statement(statement basediagram.Statement) error {
+	text := strings.TrimSpace(strings.TrimRight(statement.Text, ";"))
+
+	if p.class != nil {
+		if text == bodyClose {
+			p.class = nil
+			return nil
+		}
+		if match := annotationPattern.FindStringSubmatch(text); match != nil && match[2] == "" {
+			return p.annotate(statement, p.class, match[1])
+		}
+		return p.member(statement, p.class, text)
+	}
+
+	if text == bodyClose {
+		if p.namespace == nil {
+			return basediagram.Syntax(statement.Line, "%s without class or namespace", bodyClose)
+		}
+		p.namespace = nil
+		return nil
+	}
+
+	if rest, ok := basediagram.CutKeyword(text, keywordClass); ok {
+		return p.declaration(statement, rest)
+	}
+	if rest, ok := basediagram.CutKeyword(strings.TrimSuffix(text, bodyOpen), keywordNamespace); ok {
+		if p.namespace != nil || !strings.HasSuffix(text, bodyOpen) {
+			return basediagram.Unsupported(statement)
+		}
+		p.namespace = p.diagram.AddNamespace(strings.TrimSpace(rest))
+		p.openLine = statement.Line
+		return nil
+	}
+	if p.namespace != nil {
+		return basediagram.Unsupported(statement)
+	}
+
+	if rest, ok := basediagram.CutKeyword(text, keywordNote); ok {
+		return p.note(statement, rest)
+	}
+	if rest, ok := basediagram.CutKeyword(text, keywordDirection); ok {
+		direction, ok := directions[rest]
+		if !ok {
+			return basediagram.Syntax(statement.Line, "invalid direction %q", rest)
+		}
+		p.diagram.Direction = direction
+		return nil
+	}
+	if basediagram.HasKeyword(text, unsupportedKeywords...) {
+		return basediagram.Unsupported(statement)
+	}
+
+	if match := annotationPattern.FindStringSubmatch(text); match != nil && match[2] != "" {
+		return p.annotate(statement, p.classNamed(match[2]), match[1])
+	}
+	if match := relationPattern.FindStringSubmatch(text); match != nil {
+		relation := p.diagram.AddRelation(p.classNamed(match[1]), p.classNamed(match[7]))
+		relation.CardinalityToClassA = cardinality(match[2])
+		relation.RelationToClassA = relationType(match[3])
+		relation.Link = relationLink(match[4])
+		relation.RelationToClassB = relationType(strings.TrimSpace(match[5]))
+		relation.CardinalityToClassB = cardinality(match[6])
+		relation.Label = strings.TrimSpace(match[8])
+		return nil
+	}
+	if match := memberPattern.FindStringSubmatch(text); match != nil {
+		return p.member(statement, p.classNamed(match[1]), strings.TrimSpace(match[2]))
+	}
+
+	if strings.ContainsAny(text, "~()") {
+		return basediagram.Unsupported(statement)
+	}
+
+	return basediagram.Syntax(statement.Line, "expected a class, relation or note")
+}
+
+// declaration reads a class declaration: "Name", "Name[\"label\"]", optionally opening its
+// body with "{".
+func (p *classParser) declaration(statement basediagram.Statement, rest string) error {
+	match := declarationPattern.FindStringSubmatch(rest)
+	if match == nil {
+		if strings.ContainsAny(rest, "~:") {
+			return basediagram.Unsupported(statement)
+		}
+		return basediagram.Syntax(statement.Line, "invalid class declaration %q", rest)
+	}
+
+	class := p.diagram.FindClass(match[1])
+	switch {
+	case class == nil:
+		class = p.diagram.AddClass(match[1], p.namespace)
+	case p.namespace != nil && !p.inNamespace(class):
+		// The model cannot move a class mentioned before into a namespace.
+		return basediagram.Unsupported(statement)
+	}
+	if match[2] != "" {
+		class.Label = match[2]
+	}
+	if match[3] == bodyOpen {
+		p.class = class
+		p.openLine = statement.Line
+	}
+
+	return nil
+}
+
+// inNamespace reports whether a class belongs to the open namespace.
+func (p *classParser) inNamespace(class *Class) bool {
+	for _, candidate := range p.namespace.Classes {
+		if candidate == class {
+			return true
+		}
+	}
+
+	return false
+}
+
+// classNamed returns the class with a name, adding it to the diagram on its first mention.
+func (p *classParser) classNamed(name string) *Class {
+	if class := p.diagram.FindClass(name); class != nil {
+		return class
+	}
+
+	return p.diagram.AddClass(name, nil)
+}
+
+// annotate sets the annotation of a class.
+func (p *classParser) annotate(statement basediagram.Statement, class *Class, name string) error {
+	annotation, ok := annotations[name]
+	if !ok {
+		return basediagram.Unsupported(statement)
+	}
+	class.Annotation = annotation
+
+	return nil
+}
+
+// member adds a field, or a method when the member has parentheses, to a class.
+func (p *classParser) member(statement basediagram.Statement, class *Class, text string) error {
+	if match := methodPattern.FindStringSubmatch(text); match != nil {
+		method := class.AddMethod(match[2])
+		method.Visibility = methodVisibility(match[1])
+		method.Classifier = methodClassifier(match[4])
+		method.ReturnType = strings.TrimSpace(match[5])
+
+		if strings.TrimSpace(match[3]) == "" {
+			return nil
+		}
+		for _, parameter := range strings.Split(match[3], ",") {
+			parameterMatch := parameterPattern.FindStringSubmatch(parameter)
+			if parameterMatch == nil {
+				return basediagram.Unsupported(statement)
+			}
+			method.AddParameter(parameterMatch[1], parameterMatch[2])
+		}
+		return nil
+	}
+
+	match := fieldPattern.FindStringSubmatch(text)
+	if match == nil || strings.HasSuffix(text, "*") {
+		return basediagram.Unsupported(statement)
+	}
+
+	var field *Field
+	if index := strings.LastIndexAny(match[2], " \t"); index >= 0 {
+		field = class.AddField(strings.TrimSpace(match[2][index+1:]), strings.TrimSpace(match[2][:index]))
+	} else {
+		field = class.AddField(match[2], "")
+	}
+	field.Visibility = fieldVisibility(match[1])
+	field.Classifier = fieldClassifier(match[3])
+
+	return nil
+}
+
+// note adds a note to the diagram or, with "for Name", to a class.
+func (p *classParser) note(statement basediagram.Statement, rest string) error {
+	match := notePattern.FindStringSubmatch(rest)
+	if match == nil {
+		return basediagram.Syntax(statement.Line, "invalid note %q", rest)
+	}
+
+	p.diagram.AddNote(match[2], nil)
+	if match[1] != "" {
+		note := p.diagram.notes[len(p.diagram.notes)-1]
+		p.notes = append(p.notes, noteReference{statement: statement, note: note, name: match[1]})
+	}
+
+	return nil
+}
+
+// resolveNotes attaches the notes to their classes, which may be declared after the notes.
+func (p *classParser) resolveNotes() error {
+	for _, reference := range p.notes {
+		if reference.note.Class = p.diagram.FindClass(reference.name); reference.note.Class == nil {
+			return basediagram.AtLine(reference.statement.Line, basediagram.UnknownReference(documentElementClass, reference.name))
+		}
+	}
+
+	return nil
+}
+
+// cardinality returns the quoted cardinality of a relation end, or none.
+func cardinality(text string) relationCardinality {
+	if text == "" {
+		return ""
+	}
+
+	return relationCardinality(cardinalityQuote + text + cardinalityQuote)
+}
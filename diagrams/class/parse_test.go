@@ -0,0 +1,122 @@
+package class
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/testutils"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "Classes, relations and notes",
+			source: "classDiagram\n    direction LR\n    note \"General\"\n    note for Duck \"Quacks\"\n    class Animal {\n        <<Interface>>\n        +String name\n        -int age$\n        +speak(word:string) string\n        +move()$\n    }\n    class Duck[\"Rubber duck\"]\n    Animal <|-- Duck\n    Duck \"1\" --> \"*\" Pond : swims\n    Duck : +swim()\n",
+			want:   "classDiagram\n    direction LR\n    note \"General\"\n    note for Duck \"Quacks\"\n    class Animal{\n    <<Interface>>\n        +String name\n        -int age$\n        +speak(word:string) string\n        +move()$ \n    }\n    class Duck[\"Rubber duck\"]{\n        +swim() \n    }\n    class Pond{\n    }\n    Animal <|-- Duck\n    Duck \"1\"-->\"*\" Pond : swims\n",
+		},
+		{
+			name:   "Namespace",
+			source: "classDiagram\n    namespace Water {\n        class Pond\n        class Lake\n    }\n    Pond --> Lake\n",
+			want:   "classDiagram\n    direction TB\n    namespace Water{\n        class Pond{\n        }\n        class Lake{\n        }\n    }\n    Pond --> Lake\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := testutils.DiagramBody(t, d.String()); got != tt.want {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+
+			reparsed, err := Parse(d.String())
+			if err != nil {
+				t.Fatalf("Parse() of the rendering error = %v", err)
+			}
+			if reparsed.String() != d.String() {
+				t.Errorf("Parse() of the rendering = %q, want %q", reparsed.String(), d.String())
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "Other diagram type",
+			source:  "erDiagram\n",
+			wantErr: basediagram.ErrSyntax,
+		},
+		{
+			name:    "Unclosed body",
+			source:  "classDiagram\n    class A {\n",
+			wantErr: basediagram.ErrSyntax,
+			wantMsg: "line 2: syntax error: { is not closed",
+		},
+		{
+			name:    "Generic class",
+			source:  "classDiagram\n    class List~T~\n",
+			wantErr: basediagram.ErrUnsupported,
+		},
+		{
+			name:    "Unknown annotation",
+			source:  "classDiagram\n    <<Entity>> A\n",
+			wantErr: basediagram.ErrUnsupported,
+		},
+		{
+			name:    "Parameter without type",
+			source:  "classDiagram\n    A : +run(x)\n",
+			wantErr: basediagram.ErrUnsupported,
+		},
+		{
+			name:    "Nested namespace",
+			source:  "classDiagram\n    namespace A {\n        namespace B {\n",
+			wantErr: basediagram.ErrUnsupported,
+		},
+		{
+			name:    "Class moved into a namespace",
+			source:  "classDiagram\n    A --> B\n    namespace N {\n        class A\n    }\n",
+			wantErr: basediagram.ErrUnsupported,
+			wantMsg: "line 4: unsupported syntax: class A",
+		},
+		{
+			name:    "Style",
+			source:  "classDiagram\n    style A fill:#f00\n",
+			wantErr: basediagram.ErrUnsupported,
+		},
+		{
+			name:    "Note for unknown class",
+			source:  "classDiagram\n    note for Ghost \"Boo\"\n",
+			wantErr: basediagram.ErrUnknownReference,
+			wantMsg: "line 2: unknown reference: class \"Ghost\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.source)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			var syntaxErr *basediagram.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Errorf("Parse() error = %T, want *basediagram.SyntaxError", err)
+			}
+			if tt.wantMsg != "" && err.Error() != tt.wantMsg {
+				t.Errorf("Parse() error = %q, want %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
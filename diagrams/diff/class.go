@@ -0,0 +1,103 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/class"
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+)
+
+const (
+	namespaceDescriptionString string = " in %s"
+)
+
+// ClassDiagrams compares two class diagrams. Classes are matched by name, fields and
+// methods by name within their class, and relations by the classes they connect.
+func ClassDiagrams(before *class.ClassDiagram, after *class.ClassDiagram) *Result {
+	result := &Result{}
+
+	result.compare(ElementClass, classItems(before), classItems(after))
+	result.compare(ElementField, fieldItems(before), fieldItems(after))
+	result.compare(ElementMethod, methodItems(before), methodItems(after))
+	result.compare(ElementRelation, relationItems(before), relationItems(after))
+	result.compare(ElementNote, noteItems(before), noteItems(after))
+
+	return result
+}
+
+// ClassDiagramReview returns a flowchart with one node per class and one link per relation
+// of both diagrams, coloured with review classes. Classes whose members changed are
+// marked as changed.
+func ClassDiagramReview(before *class.ClassDiagram, after *class.ClassDiagram) *flowchart.Flowchart {
+	return overview(ClassDiagrams(before, after), ElementClass, ElementRelation,
+		classItems(before), classItems(after), relationItems(before), relationItems(after))
+}
+
+// classItems describes the classes of the diagram, including the namespace they belong to.
+func classItems(cd *class.ClassDiagram) (items []item) {
+	for _, c := range cd.Classes() {
+		description := strings.TrimSpace(string(c.Annotation) + " " + c.Label)
+		if namespace := cd.NamespaceOf(c); namespace != nil {
+			description += fmt.Sprintf(namespaceDescriptionString, namespace.Name)
+		}
+
+		items = append(items, item{id: c.Name, description: strings.TrimSpace(description), label: c.Name})
+	}
+
+	return
+}
+
+// fieldItems describes the fields of every class of the diagram.
+func fieldItems(cd *class.ClassDiagram) (items []item) {
+	for _, c := range cd.Classes() {
+		for _, field := range c.Fields() {
+			items = append(items, item{id: memberID(c.Name, field.Name), owner: c.Name, description: strings.TrimSpace(field.String())})
+		}
+	}
+
+	return
+}
+
+// methodItems describes the methods of every class of the diagram.
+func methodItems(cd *class.ClassDiagram) (items []item) {
+	for _, c := range cd.Classes() {
+		for _, method := range c.Methods() {
+			items = append(items, item{id: memberID(c.Name, method.Name), owner: c.Name, description: strings.TrimSpace(method.String())})
+		}
+	}
+
+	return
+}
+
+// relationItems describes the relations of the diagram.
+func relationItems(cd *class.ClassDiagram) (items []item) {
+	ids := edgeIDs{}
+
+	for _, relation := range cd.Relations() {
+		items = append(items, item{
+			id:          ids.next(relation.ClassA.Name, relation.ClassB.Name),
+			description: strings.TrimSpace(relation.String()),
+			label:       relation.Label,
+			from:        relation.ClassA.Name,
+			to:          relation.ClassB.Name,
+		})
+	}
+
+	return
+}
+
+// noteItems describes the notes of the diagram. Notes are identified by their content.
+func noteItems(cd *class.ClassDiagram) (items []item) {
+	for _, note := range cd.Notes() {
+		description := strings.TrimSpace(note.String())
+		owner := ""
+		if note.Class != nil {
+			owner = note.Class.Name
+		}
+
+		items = append(items, item{id: description, owner: owner, description: description})
+	}
+
+	return
+}
@@ -0,0 +1,77 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/class"
+)
+
+// newClassDiagramVersions returns two versions of a small class diagram.
+func newClassDiagramVersions() (before *class.ClassDiagram, after *class.ClassDiagram) {
+	before = class.NewClassDiagram()
+	animal := before.AddClass("Animal", nil)
+	animal.AddField("name", "string")
+	animal.AddMethod("speak")
+	dog := before.AddClass("Dog", nil)
+	cat := before.AddClass("Cat", nil)
+	before.AddRelation(dog, animal)
+	before.AddRelation(cat, animal)
+
+	after = class.NewClassDiagram()
+	animal = after.AddClass("Animal", nil).SetAnnotation(class.ClassAnnotationAbstract)
+	animal.AddField("name", "int")
+	animal.AddField("age", "int")
+	dog = after.AddClass("Dog", nil)
+	bird := after.AddClass("Bird", nil)
+	after.AddRelation(dog, animal)
+	after.AddRelation(bird, animal)
+
+	return
+}
+
+func TestClassDiagrams(t *testing.T) {
+	before, after := newClassDiagramVersions()
+
+	result := ClassDiagrams(before, after)
+
+	want := strings.Join([]string{
+		`~ class "Animal": "" -> <<Abstract>>`,
+		`- class "Cat"`,
+		`+ class "Bird"`,
+		`~ field "Animal.name": +string name -> +int name`,
+		`+ field "Animal.age": +int age`,
+		`- method "Animal.speak": +speak()`,
+		`- relation "Cat -> Animal": Cat -- Animal`,
+		`+ relation "Bird -> Animal": Bird -- Animal`,
+	}, "\n") + "\n"
+
+	if got := result.String(); got != want {
+		t.Errorf("ClassDiagrams() =\n%s\nwant:\n%s", got, want)
+	}
+
+	if result.Find(ElementField, "Animal.age").Owner != "Animal" {
+		t.Error("ClassDiagrams() member changes should record the owning class")
+	}
+}
+
+func TestClassDiagramReview(t *testing.T) {
+	before, after := newClassDiagramVersions()
+
+	output := ClassDiagramReview(before, after).String()
+
+	wants := []string{
+		`0@{ shape: rect, label: "Animal"}:::diffChanged`,
+		`1@{ shape: rect, label: "Dog"}` + "\n",
+		`2@{ shape: rect, label: "Bird"}:::diffAdded`,
+		`3@{ shape: rect, label: "Cat"}:::diffRemoved`,
+		"1 --> 0",
+		"2 ==> 0",
+		"3 -.-> 0",
+	}
+	for _, want := range wants {
+		if !strings.Contains(output, want) {
+			t.Errorf("ClassDiagramReview() missing %q in:\n%s", want, output)
+		}
+	}
+}
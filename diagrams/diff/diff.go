@@ -0,0 +1,214 @@
+// Package diff compares two versions of a diagram by identity and reports the
+// elements that were added, removed or changed.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChangeType describes how an element differs between two diagrams.
+type ChangeType string
+
+// List of change types.
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	ChangeChanged ChangeType = "changed"
+)
+
+// Element kinds reported in changes.
+const (
+	ElementNode         string = "node"
+	ElementLink         string = "link"
+	ElementSubgraph     string = "subgraph"
+	ElementClass        string = "class"
+	ElementField        string = "field"
+	ElementMethod       string = "method"
+	ElementRelation     string = "relation"
+	ElementNote         string = "note"
+	ElementEntity       string = "entity"
+	ElementAttribute    string = "attribute"
+	ElementRelationship string = "relationship"
+	ElementState        string = "state"
+	ElementTransition   string = "transition"
+)
+
+const (
+	changeAddedString   string = "+ %s %q"
+	changeRemovedString string = "- %s %q"
+	changeChangedString string = "~ %s %q: %s -> %s"
+	descriptionString   string = ": %s"
+	emptyDescription    string = `""`
+	memberIDString      string = "%s.%s"
+	edgeIDString        string = "%s -> %s"
+	edgeRepeatIDString  string = "%s -> %s #%d"
+)
+
+// Change describes a single element that differs between two diagrams.
+type Change struct {
+	// Type tells whether the element was added, removed or changed.
+	Type ChangeType
+	// Element is the kind of element, such as "node", "link" or "attribute".
+	Element string
+	// ID is the identity used to match the element in both diagrams.
+	ID string
+	// Owner is the ID of the element containing this one, such as the class of a field.
+	Owner string
+	// Before describes the element in the old diagram. It is empty for additions.
+	Before string
+	// After describes the element in the new diagram. It is empty for removals.
+	After string
+}
+
+// String returns a one-line description of the change.
+func (c Change) String() string {
+	switch c.Type {
+	case ChangeAdded:
+		return fmt.Sprintf(changeAddedString, c.Element, c.ID) + optionalDescription(c.After)
+	case ChangeRemoved:
+		return fmt.Sprintf(changeRemovedString, c.Element, c.ID) + optionalDescription(c.Before)
+	default:
+		return fmt.Sprintf(changeChangedString, c.Element, c.ID, quoteEmpty(c.Before), quoteEmpty(c.After))
+	}
+}
+
+// optionalDescription returns the description suffix of a change line, if any.
+func optionalDescription(description string) string {
+	if description == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(descriptionString, description)
+}
+
+// quoteEmpty makes an empty description visible in a change line.
+func quoteEmpty(description string) string {
+	if description == "" {
+		return emptyDescription
+	}
+
+	return description
+}
+
+// Result holds the changes between two diagrams. Changes are grouped by element kind, in
+// the order the kinds are compared, such as classes before their fields. Within a kind,
+// removed and changed elements come in their old order, followed by added elements.
+type Result struct {
+	Changes []Change
+}
+
+// HasChanges reports whether the diagrams differ.
+func (r *Result) HasChanges() bool {
+	return len(r.Changes) > 0
+}
+
+// Filter returns the changes of the given type.
+func (r *Result) Filter(changeType ChangeType) (changes []Change) {
+	for _, change := range r.Changes {
+		if change.Type == changeType {
+			changes = append(changes, change)
+		}
+	}
+
+	return
+}
+
+// Find returns the change for the element with the given kind and ID, or nil if it did not change.
+func (r *Result) Find(element string, id string) *Change {
+	for i := range r.Changes {
+		if r.Changes[i].Element == element && r.Changes[i].ID == id {
+			return &r.Changes[i]
+		}
+	}
+
+	return nil
+}
+
+// String returns one line per change.
+func (r *Result) String() string {
+	var sb strings.Builder
+
+	for _, change := range r.Changes {
+		sb.WriteString(change.String())
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
+
+// statusOf returns how the element with the given kind and ID changed. An element
+// that is itself unchanged but owns changed elements is reported as changed.
+func (r *Result) statusOf(element string, id string) (ChangeType, bool) {
+	if change := r.Find(element, id); change != nil {
+		return change.Type, true
+	}
+
+	for _, change := range r.Changes {
+		if change.Owner == id {
+			return ChangeChanged, true
+		}
+	}
+
+	return "", false
+}
+
+// item is a diagram element reduced to its identity and a description of its content.
+// Edges additionally record their endpoints and label.
+type item struct {
+	id          string
+	owner       string
+	description string
+	label       string
+	from        string
+	to          string
+}
+
+// compare appends the changes between the before and after items of one element kind.
+// Removed and changed items are reported in their old order, followed by added items.
+func (r *Result) compare(element string, before []item, after []item) {
+	afterByID := make(map[string]item, len(after))
+	for _, current := range after {
+		afterByID[current.id] = current
+	}
+
+	beforeIDs := make(map[string]bool, len(before))
+	for _, old := range before {
+		beforeIDs[old.id] = true
+
+		current, ok := afterByID[old.id]
+		switch {
+		case !ok:
+			r.Changes = append(r.Changes, Change{Type: ChangeRemoved, Element: element, ID: old.id, Owner: old.owner, Before: old.description})
+		case current.description != old.description:
+			r.Changes = append(r.Changes, Change{Type: ChangeChanged, Element: element, ID: old.id, Owner: current.owner, Before: old.description, After: current.description})
+		}
+	}
+
+	for _, current := range after {
+		if !beforeIDs[current.id] {
+			r.Changes = append(r.Changes, Change{Type: ChangeAdded, Element: element, ID: current.id, Owner: current.owner, After: current.description})
+		}
+	}
+}
+
+// edgeIDs assigns identities to edges: edges are identified by their endpoints, and
+// repeated edges between the same endpoints are numbered in declaration order.
+type edgeIDs map[string]int
+
+// next returns the identity of the next edge between from and to.
+func (e edgeIDs) next(from string, to string) string {
+	key := fmt.Sprintf(edgeIDString, from, to)
+	e[key]++
+
+	if e[key] == 1 {
+		return key
+	}
+
+	return fmt.Sprintf(edgeRepeatIDString, from, to, e[key])
+}
+
+// memberID returns the identity of a member of an owning element.
+func memberID(owner string, name string) string {
+	return fmt.Sprintf(memberIDString, owner, name)
+}
@@ -0,0 +1,140 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChange_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		change Change
+		want   string
+	}{
+		{
+			name:   "Added",
+			change: Change{Type: ChangeAdded, Element: ElementNode, ID: "a", After: "A"},
+			want:   `+ node "a": A`,
+		},
+		{
+			name:   "Removed",
+			change: Change{Type: ChangeRemoved, Element: ElementLink, ID: "a -> b", Before: "a --> b"},
+			want:   `- link "a -> b": a --> b`,
+		},
+		{
+			name:   "Added without description",
+			change: Change{Type: ChangeAdded, Element: ElementClass, ID: "Dog"},
+			want:   `+ class "Dog"`,
+		},
+		{
+			name:   "Changed from empty",
+			change: Change{Type: ChangeChanged, Element: ElementEntity, ID: "USER", After: "Customer"},
+			want:   `~ entity "USER": "" -> Customer`,
+		},
+		{
+			name:   "Changed",
+			change: Change{Type: ChangeChanged, Element: ElementAttribute, ID: "USER.id", Before: "int", After: "string"},
+			want:   `~ attribute "USER.id": int -> string`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.change.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_Compare(t *testing.T) {
+	before := []item{{id: "a", description: "A"}, {id: "b", description: "B"}, {id: "c", description: "C"}}
+	after := []item{{id: "d", description: "D"}, {id: "b", description: "B2"}, {id: "c", description: "C"}}
+
+	result := &Result{}
+	result.compare(ElementNode, before, after)
+
+	want := []Change{
+		{Type: ChangeRemoved, Element: ElementNode, ID: "a", Before: "A"},
+		{Type: ChangeChanged, Element: ElementNode, ID: "b", Before: "B", After: "B2"},
+		{Type: ChangeAdded, Element: ElementNode, ID: "d", After: "D"},
+	}
+	if !reflect.DeepEqual(result.Changes, want) {
+		t.Errorf("compare() = %v, want %v", result.Changes, want)
+	}
+
+	if !result.HasChanges() {
+		t.Error("HasChanges() = false, want true")
+	}
+
+	if got := result.Filter(ChangeAdded); len(got) != 1 || got[0].ID != "d" {
+		t.Errorf("Filter() = %v", got)
+	}
+
+	if result.Find(ElementNode, "c") != nil || result.Find(ElementNode, "b") == nil {
+		t.Error("Find() should only return changed elements")
+	}
+
+	wantString := "- node \"a\": A\n~ node \"b\": B -> B2\n+ node \"d\": D\n"
+	if got := result.String(); got != wantString {
+		t.Errorf("String() = %q, want %q", got, wantString)
+	}
+}
+
+func TestResult_GroupedByKind(t *testing.T) {
+	beforeClasses, afterClasses := newClassDiagramVersions()
+	beforeFlowchart, afterFlowchart := newFlowchartVersions()
+
+	for name, result := range map[string]*Result{
+		"ClassDiagrams": ClassDiagrams(beforeClasses, afterClasses),
+		"Flowcharts":    Flowcharts(beforeFlowchart, afterFlowchart),
+	} {
+		done := make(map[string]bool)
+		for i, change := range result.Changes {
+			if i > 0 && change.Element != result.Changes[i-1].Element {
+				done[result.Changes[i-1].Element] = true
+			}
+			if done[change.Element] {
+				t.Errorf("%s() change %d is a %s after other kinds of changes", name, i, change.Element)
+			}
+		}
+	}
+}
+
+func TestResult_StatusOf(t *testing.T) {
+	result := &Result{Changes: []Change{
+		{Type: ChangeAdded, Element: ElementClass, ID: "New"},
+		{Type: ChangeRemoved, Element: ElementField, ID: "Old.name", Owner: "Old"},
+	}}
+
+	tests := []struct {
+		name   string
+		id     string
+		want   ChangeType
+		wantOK bool
+	}{
+		{name: "Own change", id: "New", want: ChangeAdded, wantOK: true},
+		{name: "Changed member", id: "Old", want: ChangeChanged, wantOK: true},
+		{name: "Unchanged", id: "Other", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := result.statusOf(ElementClass, tt.id)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("statusOf() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestEdgeIDs_Next(t *testing.T) {
+	ids := edgeIDs{}
+
+	got := []string{ids.next("a", "b"), ids.next("a", "b"), ids.next("b", "a")}
+	want := []string{"a -> b", "a -> b #2", "b -> a"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("next() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,79 @@
+package diff
+
+import (
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/entityrelationship"
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+)
+
+// ERDiagrams compares two entity relationship diagrams. Entities are matched by name,
+// attributes by name within their entity, and relationships by the entities they connect.
+func ERDiagrams(before *entityrelationship.Diagram, after *entityrelationship.Diagram) *Result {
+	result := &Result{}
+
+	result.compare(ElementEntity, entityItems(before), entityItems(after))
+	result.compare(ElementAttribute, attributeItems(before), attributeItems(after))
+	result.compare(ElementRelationship, relationshipItems(before), relationshipItems(after))
+
+	return result
+}
+
+// ERDiagramReview returns a flowchart with one node per entity and one link per relationship
+// of both diagrams, coloured with review classes. Entities whose attributes changed are
+// marked as changed.
+func ERDiagramReview(before *entityrelationship.Diagram, after *entityrelationship.Diagram) *flowchart.Flowchart {
+	return overview(ERDiagrams(before, after), ElementEntity, ElementRelationship,
+		entityItems(before), entityItems(after), relationshipItems(before), relationshipItems(after))
+}
+
+// entityItems describes the entities of the diagram.
+func entityItems(d *entityrelationship.Diagram) (items []item) {
+	for _, entity := range d.Entities {
+		items = append(items, item{id: entity.Name, description: entity.Alias, label: entity.Name})
+	}
+
+	return
+}
+
+// attributeItems describes the attributes of every entity of the diagram.
+func attributeItems(d *entityrelationship.Diagram) (items []item) {
+	for _, entity := range d.Entities {
+		for _, attr := range entity.Attributes {
+			keys := []string{string(attr.Type)}
+			if attr.PK {
+				keys = append(keys, "PK")
+			}
+			if attr.FK {
+				keys = append(keys, "FK")
+			}
+			if attr.UK {
+				keys = append(keys, "UK")
+			}
+			if attr.Required {
+				keys = append(keys, "required")
+			}
+
+			items = append(items, item{id: memberID(entity.Name, attr.Name), owner: entity.Name, description: strings.Join(keys, " ")})
+		}
+	}
+
+	return
+}
+
+// relationshipItems describes the relationships of the diagram.
+func relationshipItems(d *entityrelationship.Diagram) (items []item) {
+	ids := edgeIDs{}
+
+	for _, rel := range d.Relationships {
+		items = append(items, item{
+			id:          ids.next(rel.From.Name, rel.To.Name),
+			description: strings.TrimSpace(rel.String()),
+			label:       rel.Label,
+			from:        rel.From.Name,
+			to:          rel.To.Name,
+		})
+	}
+
+	return
+}
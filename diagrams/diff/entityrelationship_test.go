@@ -0,0 +1,56 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/entityrelationship"
+)
+
+func TestERDiagrams(t *testing.T) {
+	before := entityrelationship.NewDiagram()
+	customer := before.AddEntity("CUSTOMER")
+	customer.AddAttribute("id", entityrelationship.TypeInteger).SetPrimaryKey()
+	customer.AddAttribute("fax", entityrelationship.TypeString)
+	order := before.AddEntity("ORDER")
+	before.AddRelationship(customer, order).SetLabel("places")
+
+	after := entityrelationship.NewDiagram()
+	customer = after.AddEntity("CUSTOMER").SetAlias("Client")
+	customer.AddAttribute("id", entityrelationship.TypeString).SetPrimaryKey()
+	customer.AddAttribute("email", entityrelationship.TypeString).SetRequired()
+	order = after.AddEntity("ORDER")
+	product := after.AddEntity("PRODUCT")
+	after.AddRelationship(customer, order).SetLabel("places")
+	after.AddRelationship(order, product).SetLabel("contains")
+
+	result := ERDiagrams(before, after)
+
+	want := strings.Join([]string{
+		`~ entity "CUSTOMER": "" -> Client`,
+		`+ entity "PRODUCT"`,
+		`~ attribute "CUSTOMER.id": int PK -> string PK`,
+		`- attribute "CUSTOMER.fax": string`,
+		`+ attribute "CUSTOMER.email": string required`,
+		`+ relationship "ORDER -> PRODUCT": ORDER || PRODUCT : contains`,
+	}, "\n") + "\n"
+
+	if got := result.String(); got != want {
+		t.Errorf("ERDiagrams() =\n%s\nwant:\n%s", got, want)
+	}
+
+	output := ERDiagramReview(before, after).String()
+
+	wants := []string{
+		`0@{ shape: rect, label: "CUSTOMER"}:::diffChanged`,
+		`1@{ shape: rect, label: "ORDER"}` + "\n",
+		`2@{ shape: rect, label: "PRODUCT"}:::diffAdded`,
+		"0 -->|places| 1",
+		"1 ==>|contains| 2",
+	}
+	for _, want := range wants {
+		if !strings.Contains(output, want) {
+			t.Errorf("ERDiagramReview() missing %q in:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+)
+
+const (
+	nodeDescriptionString     string = "%q (%s)"
+	nodeClassString           string = " :::%s"
+	nodeStyleString           string = " {%s}"
+	subgraphDescriptionString string = "%q"
+	subgraphDirectionString   string = " direction %s"
+)
+
+// Flowcharts compares two flowcharts. Nodes and subgraphs are matched by ID, classes
+// by name and links by their endpoints.
+func Flowcharts(before *flowchart.Flowchart, after *flowchart.Flowchart) *Result {
+	result := &Result{}
+
+	result.compare(ElementClass, flowchartClasses(before), flowchartClasses(after))
+	result.compare(ElementNode, flowchartNodes(before), flowchartNodes(after))
+	result.compare(ElementSubgraph, flowchartSubgraphs(before), flowchartSubgraphs(after))
+	result.compare(ElementLink, flowchartLinks(before), flowchartLinks(after))
+
+	return result
+}
+
+// FlowchartReview returns a copy of the after flowchart in which added, removed and
+// changed nodes and subgraphs are coloured using review classes. Removed nodes, links
+// and subgraphs are added back, removed subgraphs without their content; added and
+// changed links are drawn thick and removed links dotted, all coloured by linkStyle.
+func FlowchartReview(before *flowchart.Flowchart, after *flowchart.Flowchart) *flowchart.Flowchart {
+	result := Flowcharts(before, after)
+	review := after.Clone()
+	classes := addReviewClasses(review)
+
+	nodes := make(map[string]*flowchart.Node)
+	for _, node := range reachableNodes(review) {
+		nodes[node.ID] = node
+		if change := result.Find(ElementNode, node.ID); change != nil {
+			node.SetClass(classes[change.Type]).SetStyle(nil)
+		}
+	}
+
+	for _, node := range reachableNodes(before) {
+		if change := result.Find(ElementNode, node.ID); change != nil && change.Type == ChangeRemoved {
+			removed := flowchart.NewNode(node.ID, node.Text).SetShape(node.Shape).SetClass(classes[ChangeRemoved])
+			review.AddNode(removed)
+			nodes[removed.ID] = removed
+		}
+	}
+
+	for _, subgraph := range flowchartSubgraphs(review) {
+		if change := result.Find(ElementSubgraph, subgraph.id); change != nil {
+			review.FindSubgraph(subgraph.id).SetClass(classes[change.Type])
+		}
+	}
+
+	for _, subgraph := range flowchartSubgraphs(before) {
+		change := result.Find(ElementSubgraph, subgraph.id)
+		if change == nil || change.Type != ChangeRemoved {
+			continue
+		}
+
+		original := before.FindSubgraph(subgraph.id)
+		var removed *flowchart.Subgraph
+		if owner := review.FindSubgraph(subgraph.owner); subgraph.owner != "" && owner != nil {
+			removed = owner.AddSubgraph(original.Title)
+		} else {
+			removed = review.AddSubgraph(original.Title)
+		}
+		removed.ID = original.ID
+		removed.Direction = original.Direction
+		removed.SetClass(classes[ChangeRemoved])
+	}
+
+	ids := edgeIDs{}
+	for _, link := range review.Links() {
+		if change := result.Find(ElementLink, ids.next(link.From.ID, link.To.ID)); change != nil {
+			markLink(link, change.Type)
+		}
+	}
+
+	ids = edgeIDs{}
+	for _, link := range before.Links() {
+		change := result.Find(ElementLink, ids.next(link.From.ID, link.To.ID))
+		if change == nil || change.Type != ChangeRemoved {
+			continue
+		}
+
+		removed := *link
+		removed.From = nodes[link.From.ID]
+		removed.To = nodes[link.To.ID]
+		markLink(&removed, ChangeRemoved)
+		review.AddLink(&removed)
+	}
+
+	return review
+}
+
+// reachableNodes returns the nodes of the flowchart and the nodes only referenced by links.
+func reachableNodes(f *flowchart.Flowchart) []*flowchart.Node {
+	seen := make(map[*flowchart.Node]bool)
+	nodes := make([]*flowchart.Node, 0)
+
+	add := func(node *flowchart.Node) {
+		if node != nil && !seen[node] {
+			seen[node] = true
+			nodes = append(nodes, node)
+		}
+	}
+
+	for _, node := range f.Nodes() {
+		add(node)
+	}
+
+	for _, link := range f.Links() {
+		add(link.From)
+		add(link.To)
+	}
+
+	return nodes
+}
+
+// flowchartNodes describes the nodes of the flowchart.
+func flowchartNodes(f *flowchart.Flowchart) (items []item) {
+	for _, node := range reachableNodes(f) {
+		description := fmt.Sprintf(nodeDescriptionString, node.Text, node.Shape)
+		if node.Class != nil {
+			description += fmt.Sprintf(nodeClassString, node.Class.Name)
+		}
+		if node.Style != nil {
+			description += fmt.Sprintf(nodeStyleString, node.Style.String())
+		}
+
+		items = append(items, item{id: node.ID, description: description})
+	}
+
+	return
+}
+
+// flowchartClasses describes the classes of the flowchart.
+func flowchartClasses(f *flowchart.Flowchart) (items []item) {
+	for _, class := range f.Classes() {
+		description := ""
+		if class.Style != nil {
+			description = class.Style.String()
+		}
+
+		items = append(items, item{id: class.Name, description: description})
+	}
+
+	return
+}
+
+// flowchartSubgraphs describes the subgraphs of the flowchart, including nested ones.
+func flowchartSubgraphs(f *flowchart.Flowchart) (items []item) {
+	var walk func(subgraphs []*flowchart.Subgraph, owner string)
+	walk = func(subgraphs []*flowchart.Subgraph, owner string) {
+		for _, subgraph := range subgraphs {
+			description := fmt.Sprintf(subgraphDescriptionString, subgraph.Title)
+			if subgraph.Direction != flowchart.SubgraphDirectionNone {
+				description += fmt.Sprintf(subgraphDirectionString, subgraph.Direction)
+			}
+			if subgraph.Class != nil {
+				description += fmt.Sprintf(nodeClassString, subgraph.Class.Name)
+			}
+
+			items = append(items, item{id: subgraph.ID, owner: owner, description: description})
+			walk(subgraph.Subgraphs(), subgraph.ID)
+		}
+	}
+
+	walk(f.Subgraphs(), "")
+
+	return
+}
+
+// flowchartLinks describes the links of the flowchart, including links declared in subgraphs.
+func flowchartLinks(f *flowchart.Flowchart) (items []item) {
+	ids := edgeIDs{}
+
+	for _, link := range f.Links() {
+		description := strings.TrimSpace(link.String())
+		if link.Style != nil {
+			description += fmt.Sprintf(nodeStyleString, link.Style.String())
+		}
+
+		items = append(items, item{
+			id:          ids.next(link.From.ID, link.To.ID),
+			description: description,
+			label:       link.Text,
+			from:        link.From.ID,
+			to:          link.To.ID,
+		})
+	}
+
+	return
+}
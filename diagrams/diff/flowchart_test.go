@@ -0,0 +1,171 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+)
+
+// newFlowchartVersions returns two versions of a small flowchart.
+func newFlowchartVersions() (before *flowchart.Flowchart, after *flowchart.Flowchart) {
+	before = flowchart.NewFlowchart()
+	start := flowchart.NewNode("start", "Start")
+	check := flowchart.NewNode("check", "Check")
+	old := flowchart.NewNode("old", "Legacy")
+	before.AddNode(start)
+	before.AddNode(check)
+	before.AddNode(old)
+	before.NewLink(start, check)
+	before.NewLink(check, old)
+
+	after = flowchart.NewFlowchart()
+	start = flowchart.NewNode("start", "Start")
+	check = flowchart.NewNode("check", "Validate").SetShape(flowchart.NodeShapeDecision)
+	done := flowchart.NewNode("done", "Done")
+	after.AddNode(start)
+	after.AddNode(check)
+	after.AddNode(done)
+	after.NewLink(start, check)
+	after.NewLink(check, done).SetText("ok")
+
+	return
+}
+
+func TestFlowcharts(t *testing.T) {
+	before, after := newFlowchartVersions()
+
+	result := Flowcharts(before, after)
+
+	want := strings.Join([]string{
+		`~ node "check": "Check" (rect) -> "Validate" (diam)`,
+		`- node "old": "Legacy" (rect)`,
+		`+ node "done": "Done" (rect)`,
+		`- link "check -> old": check --> old`,
+		`+ link "check -> done": check -->|ok| done`,
+	}, "\n") + "\n"
+
+	if got := result.String(); got != want {
+		t.Errorf("Flowcharts() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFlowcharts_NoChanges(t *testing.T) {
+	before, _ := newFlowchartVersions()
+	before.AddClass("hot")
+	before.AddSubgraph("Group").AddLink(before.FindNode("start"), before.FindNode("old"))
+
+	if result := Flowcharts(before, before.Clone()); result.HasChanges() {
+		t.Errorf("Flowcharts() of a clone = %v, want no changes", result.Changes)
+	}
+}
+
+func TestFlowcharts_ClassesAndSubgraphs(t *testing.T) {
+	before := flowchart.NewFlowchart()
+	before.AddClass("hot").Style.Fill = "#f00"
+	before.AddSubgraph("Group")
+
+	after := flowchart.NewFlowchart()
+	after.AddClass("hot").Style.Fill = "#0f0"
+	after.AddSubgraph("Renamed").Direction = flowchart.SubgraphDirectionLeftRight
+
+	result := Flowcharts(before, after)
+
+	if change := result.Find(ElementClass, "hot"); change == nil || change.Type != ChangeChanged {
+		t.Errorf("Flowcharts() class change = %v", change)
+	}
+
+	change := result.Find(ElementSubgraph, "0")
+	if change == nil || change.After != `"Renamed" direction LR` {
+		t.Errorf("Flowcharts() subgraph change = %v", change)
+	}
+}
+
+func TestFlowchartReview(t *testing.T) {
+	before, after := newFlowchartVersions()
+	afterOutput := after.String()
+
+	review := FlowchartReview(before, after)
+	output := review.String()
+
+	wants := []string{
+		"classDef diffAdded color:#155724,fill:#d4edda,stroke:#28a745,stroke-width:2",
+		"classDef diffRemoved color:#721c24,fill:#f8d7da,stroke:#dc3545,stroke-width:2,stroke-dasharray:5 5",
+		"classDef diffChanged",
+		`start@{ shape: rect, label: "Start"}` + "\n",
+		`check@{ shape: diam, label: "Validate"}:::diffChanged`,
+		`done@{ shape: rect, label: "Done"}:::diffAdded`,
+		`old@{ shape: rect, label: "Legacy"}:::diffRemoved`,
+		"start --> check",
+		"check ==>|ok| done",
+		"check -.-> old",
+		"linkStyle 1 stroke:#28a745,stroke-width:2\n",
+		"linkStyle 2 stroke:#dc3545,stroke-width:2,stroke-dasharray:5 5\n",
+	}
+	for _, want := range wants {
+		if !strings.Contains(output, want) {
+			t.Errorf("FlowchartReview() missing %q in:\n%s", want, output)
+		}
+	}
+
+	if after.String() != afterOutput {
+		t.Error("FlowchartReview() modified the after flowchart")
+	}
+}
+
+func TestFlowchartReview_LinksAndSubgraphs(t *testing.T) {
+	before := flowchart.NewFlowchart()
+	a, b := flowchart.NewNode("a", "A"), flowchart.NewNode("b", "B")
+	before.AddNode(a)
+	before.AddNode(b)
+	kept := before.AddSubgraph("Kept")
+	kept.AddLink(a, b).SetText("old")
+	kept.AddSubgraph("Dropped").Direction = flowchart.SubgraphDirectionLeftRight
+	before.AddSubgraph("Gone")
+
+	after := flowchart.NewFlowchart()
+	a, b = flowchart.NewNode("a", "A"), flowchart.NewNode("b", "B")
+	after.AddNode(a)
+	after.AddNode(b)
+	after.AddSubgraph("Renamed").AddLink(a, b).SetText("new")
+	after.NewLink(b, a)
+	after.AddSubgraph("Fresh").ID = "fresh"
+
+	output := FlowchartReview(before, after).String()
+
+	wants := []string{
+		"    subgraph 0 [Renamed]\n",
+		"        subgraph 1 [Dropped]\n        direction LR\n        end\n        class 1 diffRemoved\n",
+		"        a ==>|new| b\n    end\n    class 0 diffChanged\n",
+		"    subgraph fresh [Fresh]\n    end\n    class fresh diffAdded\n",
+		"    subgraph 2 [Gone]\n    end\n    class 2 diffRemoved\n",
+		"    linkStyle 0 stroke:#ffc107,stroke-width:2\n",
+		"    linkStyle 1 stroke:#28a745,stroke-width:2\n",
+	}
+	for _, want := range wants {
+		if !strings.Contains(output, want) {
+			t.Errorf("FlowchartReview() missing %q in:\n%s", want, output)
+		}
+	}
+
+	reparsed, err := flowchart.Parse(output)
+	if err != nil {
+		t.Fatalf("Parse() of the review error = %v", err)
+	}
+	if reparsed.String() != output {
+		t.Errorf("Parse() of the review = %q, want %q", reparsed.String(), output)
+	}
+}
+
+func TestFlowchartReview_ExistingClassNames(t *testing.T) {
+	before := flowchart.NewFlowchart()
+	after := flowchart.NewFlowchart()
+	after.AddClass(ReviewClassAdded)
+	after.AddNode(flowchart.NewNode("a", "A"))
+
+	output := FlowchartReview(before, after).String()
+
+	if !strings.Contains(output, ":::diffAdded_2") {
+		t.Errorf("FlowchartReview() should not reuse existing class names:\n%s", output)
+	}
+}
@@ -0,0 +1,104 @@
+package diff
+
+import (
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+)
+
+// Names of the classes used to highlight changes in review diagrams.
+const (
+	ReviewClassAdded   string = "diffAdded"
+	ReviewClassRemoved string = "diffRemoved"
+	ReviewClassChanged string = "diffChanged"
+)
+
+// reviewStyles holds the colours of additions, removals and changes.
+var reviewStyles = map[ChangeType]flowchart.NodeStyle{
+	ChangeAdded:   {Color: "#155724", Fill: "#d4edda", Stroke: "#28a745", StrokeWidth: 2},
+	ChangeRemoved: {Color: "#721c24", Fill: "#f8d7da", Stroke: "#dc3545", StrokeWidth: 2, StrokeDash: "5 5"},
+	ChangeChanged: {Color: "#856404", Fill: "#fff3cd", Stroke: "#ffc107", StrokeWidth: 2},
+}
+
+// addReviewClasses defines the review classes in the flowchart and returns them by change type.
+// Class names already used by the flowchart are suffixed to keep them unique.
+func addReviewClasses(f *flowchart.Flowchart) map[ChangeType]*flowchart.Class {
+	names := map[ChangeType]string{
+		ChangeAdded:   ReviewClassAdded,
+		ChangeRemoved: ReviewClassRemoved,
+		ChangeChanged: ReviewClassChanged,
+	}
+
+	taken := func(name string) bool {
+		return f.FindClass(name) != nil
+	}
+
+	classes := make(map[ChangeType]*flowchart.Class, len(names))
+	for _, changeType := range []ChangeType{ChangeAdded, ChangeRemoved, ChangeChanged} {
+		class := f.AddClass(utils.UniqueName(names[changeType], taken))
+		style := reviewStyles[changeType]
+		class.Style = &style
+		classes[changeType] = class
+	}
+
+	return classes
+}
+
+// markLink changes the shape of a link and colours it to show how it changed.
+func markLink(link *flowchart.Link, changeType ChangeType) {
+	if changeType == ChangeRemoved {
+		link.SetShape(flowchart.LinkShapeDotted)
+	} else {
+		link.SetShape(flowchart.LinkShapeThick)
+	}
+
+	style := reviewStyles[changeType]
+	link.SetStyle(&flowchart.NodeStyle{Stroke: style.Stroke, StrokeWidth: style.StrokeWidth, StrokeDash: style.StrokeDash})
+}
+
+// overview builds a review flowchart with one node per element and one link per edge
+// of a diagram that has no styling of its own.
+func overview(result *Result, nodeElement string, edgeElement string, before []item, after []item, beforeEdges []item, afterEdges []item) *flowchart.Flowchart {
+	review := flowchart.NewFlowchart()
+	classes := addReviewClasses(review)
+	nodes := make(map[string]*flowchart.Node)
+
+	addNode := func(element item) {
+		node := review.NewNode(element.label)
+		nodes[element.id] = node
+		if changeType, ok := result.statusOf(nodeElement, element.id); ok {
+			node.SetClass(classes[changeType])
+		}
+	}
+
+	nodeFor := func(id string) *flowchart.Node {
+		if _, ok := nodes[id]; !ok {
+			addNode(item{id: id, label: id})
+		}
+		return nodes[id]
+	}
+
+	for _, element := range after {
+		addNode(element)
+	}
+
+	for _, element := range before {
+		if change := result.Find(nodeElement, element.id); change != nil && change.Type == ChangeRemoved {
+			addNode(element)
+		}
+	}
+
+	for _, edge := range afterEdges {
+		link := review.NewLink(nodeFor(edge.from), nodeFor(edge.to)).SetText(edge.label)
+		if change := result.Find(edgeElement, edge.id); change != nil {
+			markLink(link, change.Type)
+		}
+	}
+
+	for _, edge := range beforeEdges {
+		if change := result.Find(edgeElement, edge.id); change != nil && change.Type == ChangeRemoved {
+			markLink(review.NewLink(nodeFor(edge.from), nodeFor(edge.to)).SetText(edge.label), ChangeRemoved)
+		}
+	}
+
+	return review
+}
@@ -0,0 +1,86 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/state"
+)
+
+const (
+	terminalStateID        string = "[*]"
+	stateNoteString        string = " note %s: %q"
+	stateDescriptionString string = "%q (%s)"
+)
+
+// StateDiagrams compares two state diagrams. States are matched by ID, including nested
+// states, and transitions by the states they connect.
+func StateDiagrams(before *state.Diagram, after *state.Diagram) *Result {
+	result := &Result{}
+
+	result.compare(ElementState, stateItems(before), stateItems(after))
+	result.compare(ElementTransition, transitionItems(before), transitionItems(after))
+
+	return result
+}
+
+// StateDiagramReview returns a flowchart with one node per state and one link per transition
+// of both diagrams, coloured with review classes.
+func StateDiagramReview(before *state.Diagram, after *state.Diagram) *flowchart.Flowchart {
+	return overview(StateDiagrams(before, after), ElementState, ElementTransition,
+		stateItems(before), stateItems(after), transitionItems(before), transitionItems(after))
+}
+
+// stateItems describes the states of the diagram, including nested states.
+func stateItems(d *state.Diagram) (items []item) {
+	var walk func(states []*state.State, owner string)
+	walk = func(states []*state.State, owner string) {
+		for _, s := range states {
+			description := fmt.Sprintf(stateDescriptionString, s.Description, s.Type)
+			if s.Note != nil {
+				description += fmt.Sprintf(stateNoteString, s.Note.Position, s.Note.Text)
+			}
+
+			label := s.Description
+			if label == "" {
+				label = s.ID
+			}
+
+			items = append(items, item{id: s.ID, owner: owner, description: description, label: label})
+			walk(s.Nested, s.ID)
+		}
+	}
+
+	walk(d.States, "")
+
+	return
+}
+
+// transitionItems describes the transitions of the diagram.
+func transitionItems(d *state.Diagram) (items []item) {
+	ids := edgeIDs{}
+
+	for _, transition := range d.Transitions {
+		from, to := stateID(transition.From), stateID(transition.To)
+
+		items = append(items, item{
+			id:          ids.next(from, to),
+			description: strings.TrimSpace(transition.String("")),
+			label:       transition.Description,
+			from:        from,
+			to:          to,
+		})
+	}
+
+	return
+}
+
+// stateID returns the ID of the state, using the terminal state for nil.
+func stateID(s *state.State) string {
+	if s == nil {
+		return terminalStateID
+	}
+
+	return s.ID
+}
@@ -0,0 +1,62 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/state"
+)
+
+func TestStateDiagrams(t *testing.T) {
+	before := state.NewDiagram()
+	idle := before.AddState("Idle", "Waiting", state.StateNormal)
+	busy := before.AddState("Busy", "Working", state.StateNormal)
+	busy.AddNestedState("Loading", "Loading data", state.StateNormal)
+	before.AddTransition(nil, idle, "")
+	before.AddTransition(idle, busy, "start")
+	before.AddTransition(busy, idle, "done")
+
+	after := state.NewDiagram()
+	idle = after.AddState("Idle", "Waiting", state.StateNormal)
+	idle.AddNote("Default state", state.NoteRight)
+	busy = after.AddState("Busy", "Working", state.StateNormal)
+	busy.AddNestedState("Saving", "Saving data", state.StateNormal)
+	after.AddTransition(nil, idle, "")
+	after.AddTransition(idle, busy, "start")
+	after.AddTransition(busy, nil, "")
+
+	result := StateDiagrams(before, after)
+
+	want := strings.Join([]string{
+		`~ state "Idle": "Waiting" (normal) -> "Waiting" (normal) note right: "Default state"`,
+		`- state "Loading": "Loading data" (normal)`,
+		`+ state "Saving": "Saving data" (normal)`,
+		`- transition "Busy -> Idle": Busy --> Idle: done`,
+		`+ transition "Busy -> [*]": Busy --> [*]`,
+	}, "\n") + "\n"
+
+	if got := result.String(); got != want {
+		t.Errorf("StateDiagrams() =\n%s\nwant:\n%s", got, want)
+	}
+
+	if change := result.Find(ElementState, "Saving"); change.Owner != "Busy" {
+		t.Errorf("StateDiagrams() nested state owner = %q, want Busy", change.Owner)
+	}
+
+	output := StateDiagramReview(before, after).String()
+
+	wants := []string{
+		`0@{ shape: rect, label: "Waiting"}:::diffChanged`,
+		`1@{ shape: rect, label: "Working"}:::diffChanged`,
+		`2@{ shape: rect, label: "Saving data"}:::diffAdded`,
+		`3@{ shape: rect, label: "Loading data"}:::diffRemoved`,
+		`4@{ shape: rect, label: "[*]"}`,
+		"1 ==> 4",
+		"1 -.->|done| 0",
+	}
+	for _, want := range wants {
+		if !strings.Contains(output, want) {
+			t.Errorf("StateDiagramReview() missing %q in:\n%s", want, output)
+		}
+	}
+}
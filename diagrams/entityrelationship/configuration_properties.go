@@ -128,10 +128,18 @@ func (c ErConfigurationProperties) String() string {
 
 	if len(c.properties) > 0 {
 		sb.WriteString(baseErConfigurationProperties)
-		for _, prop := range c.properties {
+		for _, prop := range basediagram.SortedProperties(c.properties) {
 			sb.WriteString(prop.Format())
 		}
 	}
 
 	return sb.String()
 }
+
+// Clone returns a deep copy of the configuration properties.
+func (c ErConfigurationProperties) Clone() ErConfigurationProperties {
+	return ErConfigurationProperties{
+		ConfigurationProperties: c.ConfigurationProperties.Clone(),
+		properties:              basediagram.CloneProperties(c.properties),
+	}
+}
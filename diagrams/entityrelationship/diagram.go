@@ -69,3 +69,69 @@ func (d *Diagram) String() string {
 func (d *Diagram) RenderToFile(path string) error {
 	return utils.RenderToFile(path, d.String())
 }
+
+// FindEntity returns the entity with the given name, or nil if there is none.
+func (d *Diagram) FindEntity(name string) *Entity {
+	for _, entity := range d.Entities {
+		if entity.Name == name {
+			return entity
+		}
+	}
+
+	return nil
+}
+
+// RelationshipsOf returns all relationships in which the entity takes part.
+func (d *Diagram) RelationshipsOf(entity *Entity) (relationships []*Relationship) {
+	for _, rel := range d.Relationships {
+		if rel.From == entity || rel.To == entity {
+			relationships = append(relationships, rel)
+		}
+	}
+
+	return
+}
+
+// RemoveEntity removes the entity and every relationship that references it.
+// It returns false if the entity is not part of the diagram.
+func (d *Diagram) RemoveEntity(entity *Entity) bool {
+	var found bool
+
+	d.Entities, found = utils.RemoveItem(d.Entities, entity)
+	if !found {
+		return false
+	}
+
+	d.Relationships, _ = utils.RemoveFunc(d.Relationships, func(rel *Relationship) bool {
+		return rel.From == entity || rel.To == entity
+	})
+
+	return true
+}
+
+// ReplaceEntity replaces oldEntity with newEntity in place, re-pointing every relationship
+// that referenced oldEntity.
+// It returns false if oldEntity is not part of the diagram.
+func (d *Diagram) ReplaceEntity(oldEntity *Entity, newEntity *Entity) bool {
+	if !utils.ReplaceItem(d.Entities, oldEntity, newEntity) {
+		return false
+	}
+
+	for _, rel := range d.Relationships {
+		if rel.From == oldEntity {
+			rel.From = newEntity
+		}
+		if rel.To == oldEntity {
+			rel.To = newEntity
+		}
+	}
+
+	return true
+}
+
+// RemoveRelationship removes the relationship from the diagram.
+// It returns false if the relationship is not part of the diagram.
+func (d *Diagram) RemoveRelationship(rel *Relationship) (found bool) {
+	d.Relationships, found = utils.RemoveItem(d.Relationships, rel)
+	return
+}
@@ -2,6 +2,7 @@ package entityrelationship
 
 import (
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -204,3 +205,74 @@ func TestDiagram_RenderToFile(t *testing.T) {
 		})
 	}
 }
+
+func TestDiagram_RemoveEntity(t *testing.T) {
+	diagram := NewDiagram()
+	customer := diagram.AddEntity("CUSTOMER")
+	order := diagram.AddEntity("ORDER")
+	product := diagram.AddEntity("PRODUCT")
+	diagram.AddRelationship(customer, order)
+	kept := diagram.AddRelationship(product, customer)
+	diagram.AddRelationship(order, product)
+
+	if diagram.FindEntity("ORDER") != order {
+		t.Fatal("FindEntity() did not find entity")
+	}
+
+	if !diagram.RemoveEntity(order) {
+		t.Fatal("RemoveEntity() = false, want true")
+	}
+
+	if diagram.FindEntity("ORDER") != nil {
+		t.Error("RemoveEntity() did not remove entity")
+	}
+
+	if len(diagram.Relationships) != 1 || diagram.Relationships[0] != kept {
+		t.Errorf("RemoveEntity() left relationships %v", diagram.Relationships)
+	}
+
+	if diagram.RemoveEntity(order) {
+		t.Error("RemoveEntity() on removed entity = true, want false")
+	}
+}
+
+func TestDiagram_ReplaceEntity(t *testing.T) {
+	diagram := NewDiagram()
+	customer := diagram.AddEntity("CUSTOMER")
+	order := diagram.AddEntity("ORDER")
+	rel := diagram.AddRelationship(customer, order)
+	client := NewEntity("CLIENT")
+
+	if !diagram.ReplaceEntity(customer, client) {
+		t.Fatal("ReplaceEntity() = false, want true")
+	}
+
+	if rel.From != client {
+		t.Errorf("ReplaceEntity() rel.From = %v, want %v", rel.From, client)
+	}
+
+	if diagram.Entities[0] != client {
+		t.Error("ReplaceEntity() should keep entity position")
+	}
+
+	if diagram.ReplaceEntity(customer, client) {
+		t.Error("ReplaceEntity() on missing entity = true, want false")
+	}
+}
+
+func TestDiagram_RelationshipsOf(t *testing.T) {
+	diagram := NewDiagram()
+	customer := diagram.AddEntity("CUSTOMER")
+	order := diagram.AddEntity("ORDER")
+	product := diagram.AddEntity("PRODUCT")
+	rel1 := diagram.AddRelationship(customer, order)
+	rel2 := diagram.AddRelationship(order, product)
+
+	if got := diagram.RelationshipsOf(order); !reflect.DeepEqual(got, []*Relationship{rel1, rel2}) {
+		t.Errorf("RelationshipsOf() = %v, want %v", got, []*Relationship{rel1, rel2})
+	}
+
+	if !diagram.RemoveRelationship(rel1) || diagram.RemoveRelationship(rel1) {
+		t.Error("RemoveRelationship() should succeed once")
+	}
+}
@@ -0,0 +1,147 @@
+package entityrelationship
+
+import (
+	"encoding/json"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// DocumentType is the type of entity relationship diagram JSON documents.
+const DocumentType string = "entityrelationship"
+
+const documentElementEntity string = "entity"
+
+// diagramDocument is the JSON form of an entity relationship diagram. Relationships
+// reference entities by name.
+type diagramDocument struct {
+	basediagram.Document
+	Entities      []entityDocument       `json:"entities,omitempty"`
+	Relationships []relationshipDocument `json:"relationships,omitempty"`
+}
+
+type entityDocument struct {
+	Name       string              `json:"name"`
+	Alias      string              `json:"alias,omitempty"`
+	Attributes []attributeDocument `json:"attributes,omitempty"`
+}
+
+type attributeDocument struct {
+	Name     string   `json:"name"`
+	Type     DataType `json:"type"`
+	PK       bool     `json:"pk,omitempty"`
+	FK       bool     `json:"fk,omitempty"`
+	UK       bool     `json:"uk,omitempty"`
+	Required bool     `json:"required,omitempty"`
+}
+
+// relationshipDocument omits the cardinality when it is the default of NewRelationship.
+type relationshipDocument struct {
+	From        string      `json:"from"`
+	To          string      `json:"to"`
+	Label       string      `json:"label,omitempty"`
+	Cardinality Cardinality `json:"cardinality,omitempty"`
+}
+
+// MarshalJSON encodes the diagram as a versioned JSON document. Relationships reference
+// entities by name, so every entity they use must be part of the diagram.
+func (d *Diagram) MarshalJSON() ([]byte, error) {
+	config, err := d.Config.EncodeDocument(d.Config.properties)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := diagramDocument{Document: d.EncodeDocument(DocumentType, config)}
+
+	entities := make(map[*Entity]bool, len(d.Entities))
+	for _, entity := range d.Entities {
+		entities[entity] = true
+		entityDoc := entityDocument{Name: entity.Name, Alias: entity.Alias}
+		for _, attribute := range entity.Attributes {
+			entityDoc.Attributes = append(entityDoc.Attributes, attributeDocument{
+				Name:     attribute.Name,
+				Type:     attribute.Type,
+				PK:       attribute.PK,
+				FK:       attribute.FK,
+				UK:       attribute.UK,
+				Required: attribute.Required,
+			})
+		}
+		doc.Entities = append(doc.Entities, entityDoc)
+	}
+
+	for _, relationship := range d.Relationships {
+		for _, entity := range []*Entity{relationship.From, relationship.To} {
+			if entity == nil {
+				return nil, basediagram.UnknownReference(documentElementEntity, "")
+			}
+			if !entities[entity] {
+				return nil, basediagram.UnknownReference(documentElementEntity, entity.Name)
+			}
+		}
+
+		relationshipDoc := relationshipDocument{
+			From:  relationship.From.Name,
+			To:    relationship.To.Name,
+			Label: relationship.Label,
+		}
+		if relationship.Cardinality != ExactlyOne {
+			relationshipDoc.Cardinality = relationship.Cardinality
+		}
+		doc.Relationships = append(doc.Relationships, relationshipDoc)
+	}
+
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON replaces the diagram with the one described by a JSON document and
+// re-links relationships to the entities.
+func (d *Diagram) UnmarshalJSON(data []byte) error {
+	var doc diagramDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	decoded := NewDiagram()
+	if err := decoded.DecodeDocument(DocumentType, doc.Document); err != nil {
+		return err
+	}
+
+	var err error
+	if decoded.Config.ConfigurationProperties, decoded.Config.properties, err = doc.Config.Decode(); err != nil {
+		return err
+	}
+
+	entities := make(map[string]*Entity, len(doc.Entities))
+	for _, entityDoc := range doc.Entities {
+		if entities[entityDoc.Name] != nil {
+			return basediagram.DuplicateID(documentElementEntity, entityDoc.Name)
+		}
+
+		entity := decoded.AddEntity(entityDoc.Name).SetAlias(entityDoc.Alias)
+		for _, attributeDoc := range entityDoc.Attributes {
+			attribute := entity.AddAttribute(attributeDoc.Name, attributeDoc.Type)
+			attribute.PK, attribute.FK, attribute.UK = attributeDoc.PK, attributeDoc.FK, attributeDoc.UK
+			attribute.Required = attributeDoc.Required
+		}
+		entities[entity.Name] = entity
+	}
+
+	for _, relationshipDoc := range doc.Relationships {
+		from, to := entities[relationshipDoc.From], entities[relationshipDoc.To]
+		if from == nil {
+			return basediagram.UnknownReference(documentElementEntity, relationshipDoc.From)
+		}
+		if to == nil {
+			return basediagram.UnknownReference(documentElementEntity, relationshipDoc.To)
+		}
+
+		relationship := decoded.AddRelationship(from, to).SetLabel(relationshipDoc.Label)
+		if relationshipDoc.Cardinality != "" {
+			relationship.Cardinality = relationshipDoc.Cardinality
+		}
+	}
+
+	*d = *decoded
+
+	return nil
+}
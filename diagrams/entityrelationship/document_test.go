@@ -0,0 +1,107 @@
+package entityrelationship
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+func TestDiagram_JSONRoundTrip(t *testing.T) {
+	original := NewDiagram()
+	original.Title = "Shop"
+	original.Config.SetLayoutDirection("LR")
+	customer := original.AddEntity("Customer").SetAlias("Buyer")
+	customer.AddAttribute("id", TypeInteger).SetPrimaryKey()
+	customer.AddAttribute("email", TypeString).SetRequired()
+	order := original.AddEntity("Order")
+	order.AddAttribute("customer_id", TypeInteger).SetForeignKey()
+	original.AddRelationship(customer, order).SetLabel("places").SetCardinality(OneToZeroOrMore)
+	original.AddRelationship(order, order).SetLabel("follows")
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	decoded := NewDiagram()
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if decoded.String() != original.String() {
+		t.Errorf("round trip output differs:\nwant:\n%s\ngot:\n%s", original.String(), decoded.String())
+	}
+
+	if decoded.Relationships[0].From != decoded.Entities[0] || decoded.Relationships[0].To != decoded.Entities[1] {
+		t.Error("decoded relationships should reference decoded entities")
+	}
+
+	again, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("Marshal() of decoded diagram error = %v", err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("encoding is not stable:\nfirst:  %s\nsecond: %s", data, again)
+	}
+}
+
+func TestDiagram_MarshalJSON(t *testing.T) {
+	d := NewDiagram()
+	user := d.AddEntity("User")
+	user.AddAttribute("id", TypeInteger).SetPrimaryKey()
+	d.AddRelationship(user, user)
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `{"version":1,"type":"entityrelationship","entities":[{"name":"User","attributes":[{"name":"id","type":"int","pk":true}]}],` +
+		`"relationships":[{"from":"User","to":"User"}]}`
+	if string(data) != want {
+		t.Errorf("Marshal() =\n%s\nwant\n%s", data, want)
+	}
+
+	d.AddRelationship(user, NewEntity("Other"))
+	if _, err := json.Marshal(d); !errors.Is(err, basediagram.ErrUnknownReference) {
+		t.Errorf("Marshal() with an entity outside the diagram error = %v, want %v", err, basediagram.ErrUnknownReference)
+	}
+}
+
+func TestDiagram_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{
+			name:  "Valid document",
+			input: `{"version":1,"type":"entityrelationship","entities":[{"name":"A"},{"name":"B"}],"relationships":[{"from":"A","to":"B","cardinality":"}o--o{"}]}`,
+		},
+		{
+			name:  "Unknown entity",
+			input: `{"version":1,"type":"entityrelationship","entities":[{"name":"A"}],"relationships":[{"from":"A","to":"B"}]}`,
+			want:  basediagram.ErrUnknownReference,
+		},
+		{
+			name:  "Duplicate entity",
+			input: `{"version":1,"type":"entityrelationship","entities":[{"name":"A"},{"name":"A"}]}`,
+			want:  basediagram.ErrDuplicateID,
+		},
+		{
+			name:  "Wrong diagram type",
+			input: `{"version":1,"type":"class"}`,
+			want:  basediagram.ErrDiagramType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(tt.input), NewDiagram()); !errors.Is(err, tt.want) {
+				t.Errorf("Unmarshal() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
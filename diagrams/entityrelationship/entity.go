@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
 	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
 )
 
@@ -22,6 +23,7 @@ const (
 	baseEntityNoAliasString   = basediagram.Indentation + "%s {\n"
 	baseEntityWithAliasString = basediagram.Indentation + "%s [%s] {\n"
 	baseEntityAttributeString = basediagram.Indentation + basediagram.Indentation + "%s %s%s\n"
+	baseEntityKeysString      = " %s"
 )
 
 // Entity represents a table or entity in the ERD
@@ -37,6 +39,7 @@ type Attribute struct {
 	Type     DataType
 	PK       bool
 	FK       bool
+	UK       bool
 	Required bool
 }
 
@@ -70,6 +73,12 @@ func (a *Attribute) SetForeignKey() *Attribute {
 	return a
 }
 
+// SetUniqueKey marks the attribute as a unique key and returns it for chaining
+func (a *Attribute) SetUniqueKey() *Attribute {
+	a.UK = true
+	return a
+}
+
 // SetRequired marks the attribute as required and returns it for chaining
 func (a *Attribute) SetRequired() *Attribute {
 	a.Required = true
@@ -93,17 +102,47 @@ func (e *Entity) String() string {
 	}
 
 	for _, attr := range e.Attributes {
-		keys := ""
-		if attr.PK && attr.FK {
-			keys = " PK,FK"
-		} else if attr.PK {
-			keys = " PK"
-		} else if attr.FK {
-			keys = " FK"
-		}
-		sb.WriteString(fmt.Sprintf(string(baseEntityAttributeString), attr.Type, attr.Name, keys))
+		sb.WriteString(fmt.Sprintf(string(baseEntityAttributeString), attr.Type, attr.Name, attr.keys()))
 	}
 
 	sb.WriteString(basediagram.Indentation + "}\n")
 	return sb.String()
 }
+
+// keys returns the key constraints of the attribute as written after its name, or an empty
+// string when it has none.
+func (a *Attribute) keys() string {
+	var keys []string
+	if a.PK {
+		keys = append(keys, keyPrimary)
+	}
+	if a.FK {
+		keys = append(keys, keyForeign)
+	}
+	if a.UK {
+		keys = append(keys, keyUnique)
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(baseEntityKeysString, strings.Join(keys, keySeparator))
+}
+
+// FindAttribute returns the attribute with the given name, or nil if there is none.
+func (e *Entity) FindAttribute(name string) *Attribute {
+	for _, attr := range e.Attributes {
+		if attr.Name == name {
+			return attr
+		}
+	}
+
+	return nil
+}
+
+// RemoveAttribute removes the attribute from the entity.
+// It returns false if the attribute is not part of the entity.
+func (e *Entity) RemoveAttribute(attr *Attribute) (found bool) {
+	e.Attributes, found = utils.RemoveItem(e.Attributes, attr)
+	return
+}
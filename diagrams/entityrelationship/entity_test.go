@@ -153,6 +153,19 @@ func TestEntity_String(t *testing.T) {
 				"int id PK,FK",
 			},
 		},
+		{
+			name: "Entity with unique keys",
+			setup: func() *Entity {
+				e := NewEntity("TEST")
+				e.AddAttribute("email", TypeString).SetUniqueKey()
+				e.AddAttribute("id", TypeInteger).SetPrimaryKey().SetForeignKey().SetUniqueKey()
+				return e
+			},
+			contains: []string{
+				"string email UK",
+				"int id PK,FK,UK",
+			},
+		},
 		{
 			name: "Entity with all attribute types",
 			setup: func() *Entity {
@@ -193,3 +206,25 @@ func TestEntity_String(t *testing.T) {
 		})
 	}
 }
+
+func TestEntity_FindAttribute(t *testing.T) {
+	entity := NewEntity("CUSTOMER")
+	id := entity.AddAttribute("id", TypeInteger)
+	entity.AddAttribute("name", TypeString)
+
+	if entity.FindAttribute("id") != id {
+		t.Error("FindAttribute() did not find attribute")
+	}
+
+	if entity.FindAttribute("missing") != nil {
+		t.Error("FindAttribute() should return nil for missing attribute")
+	}
+
+	if !entity.RemoveAttribute(id) || entity.RemoveAttribute(id) {
+		t.Error("RemoveAttribute() should succeed once")
+	}
+
+	if len(entity.Attributes) != 1 {
+		t.Errorf("RemoveAttribute() left %d attributes, want 1", len(entity.Attributes))
+	}
+}
@@ -0,0 +1,176 @@
+package entityrelationship
+
+import (
+	"fmt"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// Merge conflict element kinds reported by Diagram.Merge.
+const (
+	MergeElementEntity    string = "entity"
+	MergeElementAttribute string = "attribute"
+)
+
+const (
+	mergeReasonAlias     string = "alias differs (%q vs %q)"
+	mergeReasonAttribute string = "attribute %s differs (%s vs %s)"
+	mergeAttributeName   string = "%s.%s"
+)
+
+// Clone returns a deep copy of the diagram.
+// Relationships of the copy reference the copied entities.
+func (d *Diagram) Clone() *Diagram {
+	entities := make(map[*Entity]*Entity)
+
+	clone := &Diagram{
+		BaseDiagram:   d.BaseDiagram,
+		Entities:      make([]*Entity, 0, len(d.Entities)),
+		Relationships: make([]*Relationship, 0, len(d.Relationships)),
+	}
+	clone.Config = d.Config.Clone()
+
+	cloneEntity := func(entity *Entity) *Entity {
+		if entity == nil {
+			return nil
+		}
+		if cloned, ok := entities[entity]; ok {
+			return cloned
+		}
+		cloned := entity.Clone()
+		entities[entity] = cloned
+		return cloned
+	}
+
+	for _, entity := range d.Entities {
+		clone.Entities = append(clone.Entities, cloneEntity(entity))
+	}
+
+	for _, rel := range d.Relationships {
+		cloned := *rel
+		cloned.From = cloneEntity(rel.From)
+		cloned.To = cloneEntity(rel.To)
+		clone.Relationships = append(clone.Relationships, &cloned)
+	}
+
+	return clone
+}
+
+// Clone returns a deep copy of the entity and its attributes.
+func (e *Entity) Clone() *Entity {
+	clone := &Entity{
+		Name:       e.Name,
+		Alias:      e.Alias,
+		Attributes: make([]*Attribute, 0, len(e.Attributes)),
+	}
+
+	for _, attr := range e.Attributes {
+		cloned := *attr
+		clone.Attributes = append(clone.Attributes, &cloned)
+	}
+
+	return clone
+}
+
+// Merge copies the entities and relationships of other into the diagram
+// and returns the conflicts that were encountered. The other diagram is not modified.
+//
+// Entities are matched by name. When an entity exists in both diagrams its attributes
+// are unioned by name; an alias or attribute that is defined differently in both is
+// reported as a conflict and the existing definition is kept. Duplicate relationships
+// are skipped.
+func (d *Diagram) Merge(other *Diagram) (conflicts []basediagram.MergeConflict) {
+	incoming := other.Clone()
+	entityMap := make(map[*Entity]*Entity)
+
+	for _, entity := range incoming.Entities {
+		existing := d.FindEntity(entity.Name)
+		if existing == nil {
+			d.Entities = append(d.Entities, entity)
+			continue
+		}
+
+		entityMap[entity] = existing
+		conflicts = append(conflicts, existing.merge(entity)...)
+	}
+
+	for _, rel := range incoming.Relationships {
+		if mapped, ok := entityMap[rel.From]; ok {
+			rel.From = mapped
+		}
+		if mapped, ok := entityMap[rel.To]; ok {
+			rel.To = mapped
+		}
+
+		if !d.containsRelationship(rel) {
+			d.Relationships = append(d.Relationships, rel)
+		}
+	}
+
+	return
+}
+
+// containsRelationship reports whether the diagram has a relationship equivalent to rel.
+func (d *Diagram) containsRelationship(rel *Relationship) bool {
+	for _, current := range d.Relationships {
+		if current.From == rel.From && current.To == rel.To &&
+			current.Cardinality == rel.Cardinality && current.Label == rel.Label {
+			return true
+		}
+	}
+
+	return false
+}
+
+// merge unions the attributes of incoming into the entity and returns the conflicts.
+func (e *Entity) merge(incoming *Entity) (conflicts []basediagram.MergeConflict) {
+	switch {
+	case incoming.Alias == "" || e.Alias == incoming.Alias:
+	case e.Alias == "":
+		e.Alias = incoming.Alias
+	default:
+		conflicts = append(conflicts, basediagram.MergeConflict{
+			Element: MergeElementEntity,
+			ID:      e.Name,
+			Reason:  fmt.Sprintf(mergeReasonAlias, e.Alias, incoming.Alias),
+		})
+	}
+
+	for _, attr := range incoming.Attributes {
+		existing := e.FindAttribute(attr.Name)
+		if existing == nil {
+			e.Attributes = append(e.Attributes, attr)
+			continue
+		}
+
+		if *existing != *attr {
+			conflicts = append(conflicts, basediagram.MergeConflict{
+				Element: MergeElementAttribute,
+				ID:      fmt.Sprintf(mergeAttributeName, e.Name, attr.Name),
+				Reason:  fmt.Sprintf(mergeReasonAttribute, attr.Name, existing.describe(), attr.describe()),
+			})
+		}
+	}
+
+	return
+}
+
+// describe returns a short description of the attribute used in conflict reports.
+func (a *Attribute) describe() string {
+	description := string(a.Type)
+
+	if a.PK {
+		description += " PK"
+	}
+	if a.FK {
+		description += " FK"
+	}
+	if a.UK {
+		description += " UK"
+	}
+	if a.Required {
+		description += " required"
+	}
+
+	return description
+}
@@ -0,0 +1,96 @@
+package entityrelationship
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiagram_Clone(t *testing.T) {
+	original := NewDiagram()
+	original.Title = "Shop"
+	customer := original.AddEntity("CUSTOMER").SetAlias("Client")
+	customer.AddAttribute("id", TypeInteger).SetPrimaryKey()
+	order := original.AddEntity("ORDER")
+	original.AddRelationship(customer, order).SetLabel("places").SetCardinality(OneToZeroOrMore)
+
+	clone := original.Clone()
+
+	if clone.String() != original.String() {
+		t.Errorf("Clone() output differs:\nwant:\n%s\ngot:\n%s", original.String(), clone.String())
+	}
+
+	if clone.Entities[0] == customer || clone.Entities[0].Attributes[0] == customer.Attributes[0] {
+		t.Error("Clone() should copy entities and attributes")
+	}
+
+	if clone.Relationships[0].From != clone.Entities[0] || clone.Relationships[0].To != clone.Entities[1] {
+		t.Error("Clone() relationships should reference cloned entities")
+	}
+
+	clone.Entities[0].AddAttribute("name", TypeString)
+	clone.AddEntity("PRODUCT")
+
+	if len(customer.Attributes) != 1 || len(original.Entities) != 2 {
+		t.Error("Modifying the clone changed the original")
+	}
+}
+
+func TestDiagram_Merge(t *testing.T) {
+	base := NewDiagram()
+	customer := base.AddEntity("CUSTOMER").SetAlias("Client")
+	customer.AddAttribute("id", TypeInteger).SetPrimaryKey()
+	order := base.AddEntity("ORDER")
+	base.AddRelationship(customer, order).SetLabel("places")
+
+	other := NewDiagram()
+	otherCustomer := other.AddEntity("CUSTOMER").SetAlias("Buyer")
+	otherCustomer.AddAttribute("id", TypeString).SetPrimaryKey()
+	otherCustomer.AddAttribute("email", TypeString)
+	otherOrder := other.AddEntity("ORDER")
+	product := other.AddEntity("PRODUCT")
+	other.AddRelationship(otherCustomer, otherOrder).SetLabel("places")
+	other.AddRelationship(otherOrder, product).SetLabel("contains")
+	otherOutput := other.String()
+
+	conflicts := base.Merge(other)
+
+	names := []string{}
+	for _, entity := range base.Entities {
+		names = append(names, entity.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"CUSTOMER", "ORDER", "PRODUCT"}) {
+		t.Errorf("Merge() entities = %v", names)
+	}
+
+	if customer.FindAttribute("email") == nil {
+		t.Error("Merge() should union attributes")
+	}
+
+	if customer.Alias != "Client" || customer.FindAttribute("id").Type != TypeInteger {
+		t.Error("Merge() should keep existing definitions on conflict")
+	}
+
+	if len(base.Relationships) != 2 {
+		t.Errorf("Merge() relationships = %d, want 2", len(base.Relationships))
+	}
+
+	if base.Relationships[1].From != order {
+		t.Error("Merge() relationships should reference existing entities")
+	}
+
+	got := []string{}
+	for _, conflict := range conflicts {
+		got = append(got, conflict.Error())
+	}
+	want := []string{
+		`entity "CUSTOMER": alias differs ("Client" vs "Buyer")`,
+		`attribute "CUSTOMER.id": attribute id differs (int PK vs string PK)`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() conflicts = %v, want %v", got, want)
+	}
+
+	if other.String() != otherOutput {
+		t.Error("Merge() modified the other diagram")
+	}
+}
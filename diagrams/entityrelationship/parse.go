@@ -0,0 +1,147 @@
+package entityrelationship
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// Keywords of entity relationship diagram source.
+const (
+	keywordDiagram = "erDiagram"
+	bodyClose      = "}"
+	keyPrimary     = "PK"
+	keyForeign     = "FK"
+	keyUnique      = "UK"
+	keySeparator   = ","
+
+	// configKey is the frontmatter configuration member holding the ER properties.
+	configKey = "er"
+)
+
+// Statement patterns. Relationships are written "FROM cardinality TO : label", where the
+// cardinality is two ends joined by an identifying "--" or non-identifying ".." line.
+var (
+	entityPattern       = regexp.MustCompile(`^([\w-]+)\s*(?:\[\s*"?([^"\]]*?)"?\s*\])?\s*(\{)?$`)
+	relationshipPattern = regexp.MustCompile(`^([\w-]+)\s+((?:\|o|\|\||\}o|\}\|)(?:--|\.\.)(?:o\||\|\||o\{|\|\{))\s+([\w-]+)\s*:\s*(.*)$`)
+	attributePattern    = regexp.MustCompile(`^(\S+)\s+(\S+)(?:\s+((?:PK|FK|UK)(?:\s*,\s*(?:PK|FK|UK))*))?\s*("[^"]*")?$`)
+)
+
+// unsupportedKeywords start the statements the model has no counterpart for.
+var unsupportedKeywords = []string{"style", "classDef", "class", "direction", "accTitle", "accDescr"}
+
+// Parse returns the entity relationship diagram described by Mermaid source, such as the
+// output of String. Entities are created by their declaration or by their first mention.
+// Errors are *basediagram.SyntaxError values: invalid syntax wraps basediagram.ErrSyntax,
+// and statements the model cannot represent, such as attribute comments and
+// cardinalities written in words, wrap basediagram.ErrUnsupported. Unsupported statements
+// are reported together, see basediagram.Source.Read.
+func Parse(source string) (*Diagram, error) {
+	parsed, err := basediagram.ParseSource(source, configKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed.Header.Text != keywordDiagram {
+		return nil, basediagram.Syntax(parsed.Header.Line, "expected %s, found %q", keywordDiagram, parsed.Header.Text)
+	}
+
+	d := NewDiagram()
+	var open *Entity
+	openLine := 0
+
+	err = parsed.Read(func(statement basediagram.Statement) error {
+		text := strings.TrimSpace(strings.TrimRight(statement.Text, ";"))
+
+		if open != nil {
+			if text == bodyClose {
+				open = nil
+				return nil
+			}
+			return parseAttribute(statement, open, text)
+		}
+
+		if basediagram.HasKeyword(text, unsupportedKeywords...) {
+			return basediagram.Unsupported(statement)
+		}
+
+		if match := entityPattern.FindStringSubmatch(text); match != nil {
+			entity := entityNamed(d, match[1])
+			if match[2] != "" {
+				entity.Alias = match[2]
+			}
+			if match[3] != "" {
+				open, openLine = entity, statement.Line
+			}
+			return nil
+		}
+
+		if match := relationshipPattern.FindStringSubmatch(text); match != nil {
+			relationship := d.AddRelationship(entityNamed(d, match[1]), entityNamed(d, match[3]))
+			relationship.Cardinality = Cardinality(match[2])
+			relationship.Label = basediagram.Unquote(strings.TrimSpace(match[4]))
+			return nil
+		}
+
+		if strings.Contains(text, ":") {
+			return basediagram.Unsupported(statement)
+		}
+
+		return basediagram.Syntax(statement.Line, "expected an entity or relationship")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if open != nil {
+		return nil, basediagram.Syntax(openLine, "entity %s is not closed", open.Name)
+	}
+
+	d.DecodeSource(parsed)
+	if d.Config.ConfigurationProperties, d.Config.properties, err = parsed.Config.Decode(); err != nil {
+		return nil, basediagram.AtLine(1, err)
+	}
+
+	return d, nil
+}
+
+// entityNamed returns the entity with a name, adding it to the diagram on its first mention.
+func entityNamed(d *Diagram, name string) *Entity {
+	if entity := d.FindEntity(name); entity != nil {
+		return entity
+	}
+
+	return d.AddEntity(name)
+}
+
+// parseAttribute adds an attribute written "type name [keys]" to an entity.
+func parseAttribute(statement basediagram.Statement, entity *Entity, text string) error {
+	match := attributePattern.FindStringSubmatch(text)
+	if match == nil {
+		return basediagram.Syntax(statement.Line, "expected an attribute")
+	}
+	if match[4] != "" {
+		return basediagram.Unsupported(statement)
+	}
+
+	attribute := entity.AddAttribute(match[2], DataType(match[1]))
+	if match[3] == "" {
+		return nil
+	}
+
+	for _, key := range strings.Split(match[3], keySeparator) {
+		switch strings.TrimSpace(key) {
+		case keyPrimary:
+			attribute.PK = true
+		case keyForeign:
+			attribute.FK = true
+		case keyUnique:
+			attribute.UK = true
+		default:
+			return basediagram.Unsupported(statement)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package entityrelationship
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/testutils"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "Entities and relationships",
+			source: "erDiagram\n    CUSTOMER [Customer] {\n        string id PK\n        int order FK\n        int both PK,FK\n    }\n    CUSTOMER ||--o{ ORDER : places\n    LINE-ITEM }|..|{ ORDER : \"is in\"\n",
+			want:   "erDiagram\n    CUSTOMER [Customer] {\n        string id PK\n        int order FK\n        int both PK,FK\n    }\n    ORDER {\n    }\n    LINE-ITEM {\n    }\n\n    CUSTOMER ||--o{ ORDER : places\n    LINE-ITEM }|..|{ ORDER : \"is in\"\n",
+		},
+		{
+			name:   "Unique keys",
+			source: "erDiagram\n    USER {\n        string email UK\n        int account FK, UK\n        int id PK,UK\n    }\n",
+			want:   "erDiagram\n    USER {\n        string email UK\n        int account FK,UK\n        int id PK,UK\n    }\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := testutils.DiagramBody(t, d.String()); got != tt.want {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+
+			reparsed, err := Parse(d.String())
+			if err != nil {
+				t.Fatalf("Parse() of the rendering error = %v", err)
+			}
+			if reparsed.String() != d.String() {
+				t.Errorf("Parse() of the rendering = %q, want %q", reparsed.String(), d.String())
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "Other diagram type",
+			source:  "journey\n",
+			wantErr: basediagram.ErrSyntax,
+		},
+		{
+			name:    "Unclosed entity",
+			source:  "erDiagram\n    A {\n",
+			wantErr: basediagram.ErrSyntax,
+			wantMsg: "line 2: syntax error: entity A is not closed",
+		},
+		{
+			name:    "Invalid attribute",
+			source:  "erDiagram\n    A {\n        id\n    }\n",
+			wantErr: basediagram.ErrSyntax,
+			wantMsg: "line 3: syntax error: expected an attribute",
+		},
+		{
+			name:    "Attribute comment",
+			source:  "erDiagram\n    A {\n        string id \"the key\"\n    }\n",
+			wantErr: basediagram.ErrUnsupported,
+		},
+		{
+			name:    "Cardinality in words",
+			source:  "erDiagram\n    A one to many B : has\n",
+			wantErr: basediagram.ErrUnsupported,
+		},
+		{
+			name:    "Missing label",
+			source:  "erDiagram\n    A ||--o{ B\n",
+			wantErr: basediagram.ErrSyntax,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.source)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			var syntaxErr *basediagram.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Errorf("Parse() error = %T, want *basediagram.SyntaxError", err)
+			}
+			if tt.wantMsg != "" && err.Error() != tt.wantMsg {
+				t.Errorf("Parse() error = %q, want %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
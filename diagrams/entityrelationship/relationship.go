@@ -2,6 +2,7 @@ package entityrelationship
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
 )
@@ -11,6 +12,7 @@ type Cardinality string
 
 const (
 	baseRelationshipString = basediagram.Indentation + "%s %s %s : %s\n"
+	quotedLabelString      = "%q"
 )
 
 // Common relationship patterns
@@ -18,7 +20,9 @@ const (
 	OneToZeroOrMore Cardinality = "||--o{" // One to many (optional)
 	OneToOneOrMore  Cardinality = "||--|{" // One to many (required)
 	OneToExactlyOne Cardinality = "||--||" // One to one
+	OneToZeroOrOne  Cardinality = "||--o|" // One to one (optional)
 	ZeroOrOneToMany Cardinality = "|o--o{" // Optional one to many
+	ZeroOrOneToOne  Cardinality = "|o--o|" // Optional one to one
 	ManyToMany      Cardinality = "}o--o{" // Many to many
 
 	// Base cardinality symbols
@@ -63,5 +67,8 @@ func (r *Relationship) String() string {
 	if label == "" {
 		label = "relates"
 	}
+	if strings.ContainsAny(label, " \t") {
+		label = fmt.Sprintf(quotedLabelString, label)
+	}
 	return fmt.Sprintf(string(baseRelationshipString), r.From.Name, string(r.Cardinality), r.To.Name, label)
 }
@@ -90,6 +90,20 @@ func TestRelationship_String(t *testing.T) {
 				"writes",
 			},
 		},
+		{
+			name: "Label with spaces is quoted",
+			setup: func() *Relationship {
+				rel := NewRelationship(
+					NewEntity("User"),
+					NewEntity("Post"),
+				)
+				rel.SetLabel("writes many")
+				return rel
+			},
+			contains: []string{
+				`: "writes many"`,
+			},
+		},
 		{
 			name: "Relationship with custom cardinality",
 			setup: func() *Relationship {
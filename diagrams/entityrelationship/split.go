@@ -0,0 +1,176 @@
+package entityrelationship
+
+import (
+	"fmt"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/graph"
+)
+
+const (
+	splitStubAliasString string = "%s (" + basediagram.SplitStubText + ")"
+)
+
+// Split partitions a diagram exceeding the edge or text size limits into several diagrams
+// and returns them with an index of the parts. A diagram within the limits is returned as a
+// single copy. Limits missing from the options are taken from the diagram configuration.
+//
+// Entities are grouped by connected component or by community; entity relationship
+// diagrams have no subgraphs, so SplitBySubgraph groups by connected component. Groups are
+// packed into as few parts as possible and only divided further when they exceed the limits
+// on their own. Every relationship cut by the split is kept in the part of each endpoint,
+// pointing to a stub entity without attributes aliased "continued in diagram N".
+func (d *Diagram) Split(options basediagram.SplitOptions) ([]*Diagram, *basediagram.SplitIndex) {
+	maxEdges, maxTextSize := options.Limits(d.Config.ConfigurationProperties)
+
+	g := graph.New[*Entity]()
+	for _, entity := range d.Entities {
+		g.AddNode(entity)
+	}
+	for _, rel := range d.Relationships {
+		g.AddEdge(rel.From, rel.To)
+	}
+
+	if len(d.Relationships) <= maxEdges && len(d.String()) <= maxTextSize {
+		return d.splitParts([][]*Entity{g.Nodes()})
+	}
+
+	empty, _ := d.extract(nil)
+	overhead := len(empty.String()) + len(fmt.Sprintf(basediagram.SplitTitleString, d.Title, len(d.Relationships), len(d.Relationships)))
+
+	fits := func(entities []*Entity) bool {
+		in := make(map[*Entity]bool, len(entities))
+		size := overhead
+		for _, entity := range entities {
+			in[entity] = true
+			size += len(entity.String())
+		}
+
+		edges := 0
+		stubs := make(map[*Entity]bool)
+		for _, rel := range d.Relationships {
+			fromIn, toIn := in[rel.From], in[rel.To]
+			if !fromIn && !toIn {
+				continue
+			}
+
+			edges++
+			size += len(rel.String())
+
+			if !fromIn || !toIn {
+				other := rel.From
+				if fromIn {
+					other = rel.To
+				}
+				if !stubs[other] {
+					stubs[other] = true
+					size += len(stubEntity(other, len(d.Relationships)).String())
+				}
+			}
+		}
+
+		return edges <= maxEdges && size <= maxTextSize
+	}
+
+	groups := g.WeaklyConnectedComponents()
+	if options.Strategy == basediagram.SplitByCommunity {
+		groups = g.Communities()
+	}
+
+	return d.splitParts(g.Pack(groups, fits))
+}
+
+// extract returns a copy of the diagram with the given entities and the relationships between them.
+// It also returns the map from the original entities to their copies.
+func (d *Diagram) extract(entities []*Entity) (*Diagram, map[*Entity]*Entity) {
+	copies := make(map[*Entity]*Entity, len(entities))
+	extract := &Diagram{
+		BaseDiagram:   d.BaseDiagram,
+		Entities:      make([]*Entity, 0, len(entities)),
+		Relationships: make([]*Relationship, 0),
+	}
+	extract.Config = d.Config.Clone()
+
+	for _, entity := range entities {
+		copies[entity] = entity.Clone()
+		extract.Entities = append(extract.Entities, copies[entity])
+	}
+
+	for _, rel := range d.Relationships {
+		if copies[rel.From] != nil && copies[rel.To] != nil {
+			cloned := *rel
+			cloned.From = copies[rel.From]
+			cloned.To = copies[rel.To]
+			extract.Relationships = append(extract.Relationships, &cloned)
+		}
+	}
+
+	return extract, copies
+}
+
+// splitParts builds one diagram per group of entities, adding stub entities for cut relationships.
+func (d *Diagram) splitParts(groups [][]*Entity) ([]*Diagram, *basediagram.SplitIndex) {
+	partOf := make(map[*Entity]int)
+	for number, group := range groups {
+		for _, entity := range group {
+			partOf[entity] = number + 1
+		}
+	}
+
+	parts := make([]*Diagram, 0, len(groups))
+	index := &basediagram.SplitIndex{}
+
+	for number, group := range groups {
+		part, copies := d.extract(group)
+		if len(groups) > 1 && d.Title != "" {
+			part.Title = fmt.Sprintf(basediagram.SplitTitleString, d.Title, number+1, len(groups))
+		}
+
+		entry := basediagram.SplitPart{Number: number + 1}
+		for _, entity := range group {
+			entry.Elements = append(entry.Elements, entity.Name)
+		}
+
+		continues := make(map[int]bool)
+		stubFor := func(entity *Entity) *Entity {
+			if stub, ok := copies[entity]; ok {
+				return stub
+			}
+
+			stub := stubEntity(entity, partOf[entity])
+			copies[entity] = stub
+			part.Entities = append(part.Entities, stub)
+
+			if !continues[partOf[entity]] {
+				continues[partOf[entity]] = true
+				entry.Continues = append(entry.Continues, partOf[entity])
+			}
+
+			return stub
+		}
+
+		for _, rel := range d.Relationships {
+			fromIn, toIn := partOf[rel.From] == number+1, partOf[rel.To] == number+1
+			if fromIn == toIn {
+				continue
+			}
+
+			cut := *rel
+			cut.From = stubFor(rel.From)
+			cut.To = stubFor(rel.To)
+			part.Relationships = append(part.Relationships, &cut)
+		}
+
+		entry.Edges = len(part.Relationships)
+		entry.TextSize = len(part.String())
+		index.Parts = append(index.Parts, entry)
+		parts = append(parts, part)
+	}
+
+	return parts, index
+}
+
+// stubEntity returns an entity standing in for entity, which is placed in the given part.
+func stubEntity(entity *Entity, part int) *Entity {
+	return NewEntity(entity.Name).SetAlias(fmt.Sprintf(splitStubAliasString, entity.Name, part))
+}
@@ -0,0 +1,114 @@
+package entityrelationship
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// newSplitTestDiagram returns two groups of three related entities joined by one relationship.
+func newSplitTestDiagram() *Diagram {
+	d := NewDiagram()
+	d.Title = "Schema"
+
+	entities := make(map[string]*Entity)
+	for _, name := range []string{"USER", "ROLE", "GRANT", "ORDER", "ITEM", "PRODUCT"} {
+		entities[name] = d.AddEntity(name)
+		entities[name].AddAttribute("id", TypeInteger).SetPrimaryKey()
+	}
+
+	d.AddRelationship(entities["USER"], entities["ROLE"]).SetLabel("has")
+	d.AddRelationship(entities["ROLE"], entities["GRANT"]).SetLabel("holds")
+	d.AddRelationship(entities["GRANT"], entities["USER"]).SetLabel("applies")
+	d.AddRelationship(entities["ORDER"], entities["ITEM"]).SetLabel("contains")
+	d.AddRelationship(entities["ITEM"], entities["PRODUCT"]).SetLabel("refers")
+	d.AddRelationship(entities["PRODUCT"], entities["ORDER"]).SetLabel("appears")
+	d.AddRelationship(entities["USER"], entities["ORDER"]).SetLabel("places")
+
+	return d
+}
+
+func TestDiagram_Split(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   basediagram.SplitOptions
+		want      [][]string
+		continues [][]int
+	}{
+		{
+			name:      "Within limits",
+			options:   basediagram.SplitOptions{},
+			want:      [][]string{{"USER", "ROLE", "GRANT", "ORDER", "ITEM", "PRODUCT"}},
+			continues: [][]int{nil},
+		},
+		{
+			name:      "By community",
+			options:   basediagram.SplitOptions{Strategy: basediagram.SplitByCommunity, MaxEdges: 4},
+			want:      [][]string{{"USER", "ROLE", "GRANT"}, {"ORDER", "ITEM", "PRODUCT"}},
+			continues: [][]int{{2}, {1}},
+		},
+		{
+			name:      "Oversized component is divided",
+			options:   basediagram.SplitOptions{Strategy: basediagram.SplitByComponent, MaxEdges: 4},
+			want:      [][]string{{"USER", "ROLE", "GRANT"}, {"ORDER", "ITEM", "PRODUCT"}},
+			continues: [][]int{{2}, {1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newSplitTestDiagram()
+			original := d.String()
+
+			parts, index := d.Split(tt.options)
+
+			got := [][]string{}
+			gotContinues := [][]int{}
+			for _, part := range index.Parts {
+				got = append(got, part.Elements)
+				gotContinues = append(gotContinues, part.Continues)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split() elements = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotContinues, tt.continues) {
+				t.Errorf("Split() continues = %v, want %v", gotContinues, tt.continues)
+			}
+
+			if len(parts) != len(tt.want) {
+				t.Fatalf("Split() parts = %d, want %d", len(parts), len(tt.want))
+			}
+
+			if d.String() != original {
+				t.Error("Split() modified the original diagram")
+			}
+		})
+	}
+}
+
+func TestDiagram_SplitStubs(t *testing.T) {
+	parts, _ := newSplitTestDiagram().Split(basediagram.SplitOptions{MaxEdges: 4})
+
+	first := parts[0].String()
+	wants := []string{
+		"title: Schema (1/2)",
+		`ORDER [ORDER (continued in diagram 2)] {`,
+		"USER || ORDER : places",
+	}
+	for _, want := range wants {
+		if !strings.Contains(first, want) {
+			t.Errorf("part 1 missing %q in:\n%s", want, first)
+		}
+	}
+
+	second := parts[1].String()
+	if !strings.Contains(second, `USER [USER (continued in diagram 1)] {`) {
+		t.Errorf("part 2 should contain a stub for USER:\n%s", second)
+	}
+
+	if parts[0].FindEntity("ORDER").FindAttribute("id") != nil {
+		t.Error("stub entities should not have attributes")
+	}
+}
@@ -140,10 +140,18 @@ func (c FlowchartConfigurationProperties) String() string {
 
 	if len(c.properties) > 0 {
 		sb.WriteString(BaseFlowchartConfigurationProperties)
-		for _, prop := range c.properties {
+		for _, prop := range basediagram.SortedProperties(c.properties) {
 			sb.WriteString(prop.Format())
 		}
 	}
 
 	return sb.String()
 }
+
+// Clone returns a deep copy of the configuration properties.
+func (c FlowchartConfigurationProperties) Clone() FlowchartConfigurationProperties {
+	return FlowchartConfigurationProperties{
+		ConfigurationProperties: c.ConfigurationProperties.Clone(),
+		properties:              basediagram.CloneProperties(c.properties),
+	}
+}
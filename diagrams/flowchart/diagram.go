@@ -90,6 +90,7 @@ func (f *Flowchart) RenderToFile(path string) error {
 // AddSubgraph adds a new subgraph to the flowchart and returns the created subgraph.
 func (f *Flowchart) AddSubgraph(title string) (newSubgraph *Subgraph) {
 	newSubgraph = NewSubgraph(f.idGenerator.NextID(), title)
+	newSubgraph.idGenerator = f.idGenerator
 
 	f.subgraphs = append(f.subgraphs, newSubgraph)
 
@@ -155,5 +156,248 @@ func (f *Flowchart) String() string {
 		sb.WriteString(link.String())
 	}
 
+	for i, link := range f.renderedLinks() {
+		if link.Style != nil {
+			sb.WriteString(fmt.Sprintf(string(baseLinkStyleString), i, link.Style.String()))
+		}
+	}
+
 	return f.BaseDiagram.String(sb.String())
 }
+
+// renderedLinks returns the links of the flowchart in the order String writes them, which
+// is the order linkStyle statements number them in.
+func (f *Flowchart) renderedLinks() []*Link {
+	var links []*Link
+
+	for _, subgraph := range f.subgraphs {
+		links = append(links, subgraph.renderedLinks()...)
+	}
+
+	return append(links, f.links...)
+}
+
+// allSubgraphs returns the subgraphs of the flowchart, including nested ones.
+func (f *Flowchart) allSubgraphs() []*Subgraph {
+	var subgraphs []*Subgraph
+
+	for _, subgraph := range f.subgraphs {
+		subgraph.walk(func(s *Subgraph) {
+			subgraphs = append(subgraphs, s)
+		})
+	}
+
+	return subgraphs
+}
+
+// Nodes returns the nodes of the flowchart in the order they were added.
+func (f *Flowchart) Nodes() []*Node {
+	return utils.CopySlice(f.nodes)
+}
+
+// Links returns all links of the flowchart, including links declared inside subgraphs.
+func (f *Flowchart) Links() []*Link {
+	links := utils.CopySlice(f.links)
+
+	for _, subgraph := range f.subgraphs {
+		links = append(links, subgraph.allLinks()...)
+	}
+
+	return links
+}
+
+// Subgraphs returns the top-level subgraphs of the flowchart.
+func (f *Flowchart) Subgraphs() []*Subgraph {
+	return utils.CopySlice(f.subgraphs)
+}
+
+// Classes returns the classes defined in the flowchart.
+func (f *Flowchart) Classes() []*Class {
+	return utils.CopySlice(f.classes)
+}
+
+// FindNode returns the node with the given ID, or nil if there is none.
+func (f *Flowchart) FindNode(id string) *Node {
+	for _, node := range f.nodes {
+		if node.ID == id {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// FindSubgraph returns the subgraph with the given ID, searching nested subgraphs, or nil if there is none.
+func (f *Flowchart) FindSubgraph(id string) *Subgraph {
+	for _, subgraph := range f.subgraphs {
+		if found := subgraph.FindSubgraph(id); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// FindClass returns the class with the given name, or nil if there is none.
+func (f *Flowchart) FindClass(name string) *Class {
+	for _, class := range f.classes {
+		if class.Name == name {
+			return class
+		}
+	}
+
+	return nil
+}
+
+// LinksFrom returns all links starting at the given node.
+func (f *Flowchart) LinksFrom(node *Node) (links []*Link) {
+	for _, link := range f.Links() {
+		if link.From == node {
+			links = append(links, link)
+		}
+	}
+
+	return
+}
+
+// LinksTo returns all links ending at the given node.
+func (f *Flowchart) LinksTo(node *Node) (links []*Link) {
+	for _, link := range f.Links() {
+		if link.To == node {
+			links = append(links, link)
+		}
+	}
+
+	return
+}
+
+// LinksOf returns all links starting or ending at the given node.
+func (f *Flowchart) LinksOf(node *Node) (links []*Link) {
+	for _, link := range f.Links() {
+		if link.From == node || link.To == node {
+			links = append(links, link)
+		}
+	}
+
+	return
+}
+
+// RemoveNode removes the node from the flowchart together with every link that references it.
+// It returns false if the node is not part of the flowchart.
+func (f *Flowchart) RemoveNode(node *Node) bool {
+	var found bool
+
+	f.nodes, found = utils.RemoveItem(f.nodes, node)
+	if !found {
+		return false
+	}
+
+	for _, link := range f.LinksOf(node) {
+		f.RemoveLink(link)
+	}
+
+	return true
+}
+
+// ReplaceNode replaces oldNode with newNode, re-pointing every link that referenced oldNode.
+// It returns false if oldNode is not part of the flowchart.
+func (f *Flowchart) ReplaceNode(oldNode *Node, newNode *Node) bool {
+	if !utils.ReplaceItem(f.nodes, oldNode, newNode) {
+		return false
+	}
+
+	for _, link := range f.Links() {
+		if link.From == oldNode {
+			link.From = newNode
+		}
+		if link.To == oldNode {
+			link.To = newNode
+		}
+	}
+
+	return true
+}
+
+// RemoveLink removes the link from the flowchart or from the subgraph that declares it.
+// It returns false if the link is not part of the flowchart.
+func (f *Flowchart) RemoveLink(link *Link) bool {
+	var found bool
+
+	if f.links, found = utils.RemoveItem(f.links, link); found {
+		return true
+	}
+
+	for _, subgraph := range f.subgraphs {
+		if subgraph.removeLinkRecursive(link) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RemoveSubgraph removes the subgraph, including its nested subgraphs and links.
+// Nodes referenced by the removed links remain part of the flowchart.
+// It returns false if the subgraph is not part of the flowchart.
+func (f *Flowchart) RemoveSubgraph(subgraph *Subgraph) bool {
+	var found bool
+
+	if f.subgraphs, found = utils.RemoveItem(f.subgraphs, subgraph); found {
+		return true
+	}
+
+	for _, parent := range f.subgraphs {
+		if parent.removeSubgraphRecursive(subgraph) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RemoveClass removes the class definition and detaches it from every node using it.
+// It returns false if the class is not part of the flowchart.
+func (f *Flowchart) RemoveClass(class *Class) bool {
+	var found bool
+
+	f.classes, found = utils.RemoveItem(f.classes, class)
+	if !found {
+		return false
+	}
+
+	for _, node := range f.nodes {
+		if node.Class == class {
+			node.Class = nil
+		}
+	}
+
+	for _, subgraph := range f.allSubgraphs() {
+		if subgraph.Class == class {
+			subgraph.Class = nil
+		}
+	}
+
+	return true
+}
+
+// ReplaceClass replaces oldClass with newClass, updating every node that used oldClass.
+// It returns false if oldClass is not part of the flowchart.
+func (f *Flowchart) ReplaceClass(oldClass *Class, newClass *Class) bool {
+	if !utils.ReplaceItem(f.classes, oldClass, newClass) {
+		return false
+	}
+
+	for _, node := range f.nodes {
+		if node.Class == oldClass {
+			node.Class = newClass
+		}
+	}
+
+	for _, subgraph := range f.allSubgraphs() {
+		if subgraph.Class == oldClass {
+			subgraph.Class = newClass
+		}
+	}
+
+	return true
+}
@@ -103,6 +103,18 @@ func TestFlowchart_String(t *testing.T) {
 				"0 --> 1",
 			},
 		},
+		{
+			name: "Link styles numbered in rendered order",
+			setup: func(f *Flowchart) {
+				node1 := f.NewNode("My Node 1")
+				node2 := f.NewNode("My Node 2")
+				f.NewLink(node1, node2).SetStyle(&NodeStyle{Stroke: "#f00"})
+				f.AddSubgraph("My Subgraph").AddLink(node2, node1).SetStyle(&NodeStyle{StrokeWidth: 2})
+			},
+			contains: []string{
+				"    end\n    0 --> 1\n    linkStyle 0 stroke-width:2\n    linkStyle 1 stroke:#f00\n",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -382,3 +394,225 @@ func TestLink_SetTail(t *testing.T) {
 		})
 	}
 }
+
+func TestFlowchart_FindNode(t *testing.T) {
+	flowchart := NewFlowchart()
+	node1 := flowchart.NewNode("First")
+	node2 := flowchart.NewNode("Second")
+
+	tests := []struct {
+		name string
+		id   string
+		want *Node
+	}{
+		{
+			name: "Find first node",
+			id:   node1.ID,
+			want: node1,
+		},
+		{
+			name: "Find second node",
+			id:   node2.ID,
+			want: node2,
+		},
+		{
+			name: "Missing node",
+			id:   "missing",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := flowchart.FindNode(tt.id); got != tt.want {
+				t.Errorf("FindNode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlowchart_RemoveNode(t *testing.T) {
+	flowchart := NewFlowchart()
+	node1 := flowchart.NewNode("First")
+	node2 := flowchart.NewNode("Second")
+	node3 := flowchart.NewNode("Third")
+	flowchart.NewLink(node1, node2)
+	flowchart.NewLink(node2, node3)
+	kept := flowchart.NewLink(node1, node3)
+	subgraph := flowchart.AddSubgraph("Group")
+	subgraph.AddLink(node2, node1)
+
+	if !flowchart.RemoveNode(node2) {
+		t.Fatal("RemoveNode() = false, want true")
+	}
+
+	if flowchart.FindNode(node2.ID) != nil {
+		t.Error("RemoveNode() did not remove the node")
+	}
+
+	links := flowchart.Links()
+	if len(links) != 1 || links[0] != kept {
+		t.Errorf("RemoveNode() left links %v, want only %v", links, kept)
+	}
+
+	if flowchart.RemoveNode(node2) {
+		t.Error("RemoveNode() on removed node = true, want false")
+	}
+}
+
+func TestFlowchart_ReplaceNode(t *testing.T) {
+	flowchart := NewFlowchart()
+	node1 := flowchart.NewNode("First")
+	node2 := flowchart.NewNode("Second")
+	link := flowchart.NewLink(node1, node2)
+	replacement := NewNode("R", "Replacement")
+
+	if !flowchart.ReplaceNode(node2, replacement) {
+		t.Fatal("ReplaceNode() = false, want true")
+	}
+
+	if link.To != replacement {
+		t.Errorf("ReplaceNode() link.To = %v, want %v", link.To, replacement)
+	}
+
+	if flowchart.FindNode("R") != replacement {
+		t.Error("ReplaceNode() did not insert the replacement node")
+	}
+
+	if flowchart.ReplaceNode(node2, replacement) {
+		t.Error("ReplaceNode() on missing node = true, want false")
+	}
+}
+
+func TestFlowchart_LinksOf(t *testing.T) {
+	flowchart := NewFlowchart()
+	node1 := flowchart.NewNode("First")
+	node2 := flowchart.NewNode("Second")
+	node3 := flowchart.NewNode("Third")
+	link1 := flowchart.NewLink(node1, node2)
+	link2 := flowchart.NewLink(node2, node3)
+	subgraph := flowchart.AddSubgraph("Group")
+	link3 := subgraph.AddLink(node3, node2)
+
+	tests := []struct {
+		name string
+		got  []*Link
+		want []*Link
+	}{
+		{
+			name: "Links from node",
+			got:  flowchart.LinksFrom(node2),
+			want: []*Link{link2},
+		},
+		{
+			name: "Links to node",
+			got:  flowchart.LinksTo(node2),
+			want: []*Link{link1, link3},
+		},
+		{
+			name: "Links of node",
+			got:  flowchart.LinksOf(node2),
+			want: []*Link{link1, link2, link3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlowchart_RemoveLink(t *testing.T) {
+	flowchart := NewFlowchart()
+	node1 := flowchart.NewNode("First")
+	node2 := flowchart.NewNode("Second")
+	link := flowchart.NewLink(node1, node2)
+	nested := flowchart.AddSubgraph("Outer").AddSubgraph("Inner")
+	nestedLink := nested.AddLink(node2, node1)
+
+	if !flowchart.RemoveLink(link) {
+		t.Error("RemoveLink() top-level link = false, want true")
+	}
+
+	if !flowchart.RemoveLink(nestedLink) {
+		t.Error("RemoveLink() nested link = false, want true")
+	}
+
+	if len(flowchart.Links()) != 0 {
+		t.Errorf("RemoveLink() left %d links, want 0", len(flowchart.Links()))
+	}
+
+	if flowchart.RemoveLink(link) {
+		t.Error("RemoveLink() on removed link = true, want false")
+	}
+}
+
+func TestFlowchart_RemoveSubgraph(t *testing.T) {
+	flowchart := NewFlowchart()
+	outer := flowchart.AddSubgraph("Outer")
+	inner := outer.AddSubgraph("Inner")
+
+	if flowchart.FindSubgraph(inner.ID) != inner {
+		t.Fatal("FindSubgraph() did not find nested subgraph")
+	}
+
+	if !flowchart.RemoveSubgraph(inner) {
+		t.Error("RemoveSubgraph() nested = false, want true")
+	}
+
+	if flowchart.FindSubgraph(inner.ID) != nil {
+		t.Error("RemoveSubgraph() did not remove nested subgraph")
+	}
+
+	if !flowchart.RemoveSubgraph(outer) {
+		t.Error("RemoveSubgraph() top-level = false, want true")
+	}
+
+	if len(flowchart.Subgraphs()) != 0 {
+		t.Errorf("RemoveSubgraph() left %d subgraphs, want 0", len(flowchart.Subgraphs()))
+	}
+}
+
+func TestFlowchart_RemoveClass(t *testing.T) {
+	flowchart := NewFlowchart()
+	class := flowchart.AddClass("highlight")
+	node := flowchart.NewNode("Node").SetClass(class)
+
+	if flowchart.FindClass("highlight") != class {
+		t.Fatal("FindClass() did not find class")
+	}
+
+	if !flowchart.RemoveClass(class) {
+		t.Error("RemoveClass() = false, want true")
+	}
+
+	if node.Class != nil {
+		t.Error("RemoveClass() did not detach class from node")
+	}
+
+	if len(flowchart.Classes()) != 0 {
+		t.Errorf("RemoveClass() left %d classes, want 0", len(flowchart.Classes()))
+	}
+}
+
+func TestFlowchart_ReplaceClass(t *testing.T) {
+	flowchart := NewFlowchart()
+	oldClass := flowchart.AddClass("old")
+	node := flowchart.NewNode("Node").SetClass(oldClass)
+	newClass := NewClass("new")
+
+	if !flowchart.ReplaceClass(oldClass, newClass) {
+		t.Fatal("ReplaceClass() = false, want true")
+	}
+
+	if node.Class != newClass {
+		t.Errorf("ReplaceClass() node class = %v, want %v", node.Class, newClass)
+	}
+
+	if flowchart.FindClass("new") != newClass {
+		t.Error("ReplaceClass() did not insert the new class")
+	}
+}
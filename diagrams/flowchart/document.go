@@ -0,0 +1,389 @@
+package flowchart
+
+import (
+	"encoding/json"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// DocumentType is the type of flowchart JSON documents.
+const DocumentType string = "flowchart"
+
+// Element kinds named in document errors.
+const (
+	documentElementNode     string = "node"
+	documentElementSubgraph string = "subgraph"
+	documentElementClass    string = "class"
+	documentValueLinkShape  string = "link shape"
+	documentValueArrowType  string = "arrow type"
+)
+
+// linkShapeNames maps link shapes to their names in documents.
+var linkShapeNames = map[LinkShape]string{
+	LinkShapeOpen:      "open",
+	LinkShapeDotted:    "dotted",
+	LinkShapeThick:     "thick",
+	LinkShapeInvisible: "invisible",
+}
+
+// arrowTypeNames maps link arrow types to their names in documents.
+var arrowTypeNames = map[LinkArrowType]string{
+	LinkArrowTypeNone:      "none",
+	LinkArrowTypeArrow:     "arrow",
+	LinkArrowTypeLeftArrow: "leftArrow",
+	LinkArrowTypeBullet:    "bullet",
+	LinkArrowTypeCross:     "cross",
+}
+
+// flowchartDocument is the JSON form of a flowchart. Nodes are referenced by ID and
+// classes by name.
+type flowchartDocument struct {
+	basediagram.Document
+	Direction  FlowchartDirection `json:"direction,omitempty"`
+	CurveStyle CurveStyle         `json:"curveStyle,omitempty"`
+	Classes    []classDocument    `json:"classes,omitempty"`
+	Nodes      []nodeDocument     `json:"nodes,omitempty"`
+	Subgraphs  []subgraphDocument `json:"subgraphs,omitempty"`
+	Links      []linkDocument     `json:"links,omitempty"`
+}
+
+type classDocument struct {
+	Name  string         `json:"name"`
+	Style *styleDocument `json:"style,omitempty"`
+}
+
+type styleDocument struct {
+	Color       string `json:"color,omitempty"`
+	Fill        string `json:"fill,omitempty"`
+	Stroke      string `json:"stroke,omitempty"`
+	StrokeWidth int    `json:"strokeWidth,omitempty"`
+	StrokeDash  string `json:"strokeDash,omitempty"`
+}
+
+// nodeDocument omits the shape when it is the default shape of NewNode.
+type nodeDocument struct {
+	ID    string         `json:"id"`
+	Shape NodeShape      `json:"shape,omitempty"`
+	Text  string         `json:"text,omitempty"`
+	Style *styleDocument `json:"style,omitempty"`
+	Class string         `json:"class,omitempty"`
+}
+
+type subgraphDocument struct {
+	ID        string             `json:"id"`
+	Title     string             `json:"title,omitempty"`
+	Direction SubgraphDirection  `json:"direction,omitempty"`
+	Class     string             `json:"class,omitempty"`
+	Subgraphs []subgraphDocument `json:"subgraphs,omitempty"`
+	Links     []linkDocument     `json:"links,omitempty"`
+}
+
+// linkDocument omits the shape and arrows when they are the defaults of NewLink.
+type linkDocument struct {
+	From   string         `json:"from"`
+	To     string         `json:"to"`
+	Shape  string         `json:"shape,omitempty"`
+	Head   string         `json:"head,omitempty"`
+	Tail   string         `json:"tail,omitempty"`
+	Text   string         `json:"text,omitempty"`
+	Length int            `json:"length,omitempty"`
+	Style  *styleDocument `json:"style,omitempty"`
+}
+
+// MarshalJSON encodes the flowchart as a versioned JSON document. Links reference their
+// nodes by ID and nodes their class by name, so every linked node must be part of the
+// flowchart and every node class must be defined in it.
+func (f *Flowchart) MarshalJSON() ([]byte, error) {
+	config, err := f.Config.EncodeDocument(f.Config.properties)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := flowchartDocument{
+		Document:   f.EncodeDocument(DocumentType, config),
+		Direction:  f.Direction,
+		CurveStyle: f.CurveStyle,
+	}
+
+	classes := make(map[*Class]bool, len(f.classes))
+	for _, class := range f.classes {
+		classes[class] = true
+		doc.Classes = append(doc.Classes, classDocument{Name: class.Name, Style: encodeStyle(class.Style)})
+	}
+
+	nodes := make(map[*Node]bool, len(f.nodes))
+	for _, node := range f.nodes {
+		nodes[node] = true
+		nodeDoc := nodeDocument{ID: node.ID, Text: node.Text, Style: encodeStyle(node.Style)}
+		if node.Shape != NodeShapeProcess {
+			nodeDoc.Shape = node.Shape
+		}
+		if node.Class != nil {
+			if !classes[node.Class] {
+				return nil, basediagram.UnknownReference(documentElementClass, node.Class.Name)
+			}
+			nodeDoc.Class = node.Class.Name
+		}
+		doc.Nodes = append(doc.Nodes, nodeDoc)
+	}
+
+	if doc.Links, err = encodeLinks(f.links, nodes); err != nil {
+		return nil, err
+	}
+
+	for _, subgraph := range f.subgraphs {
+		subgraphDoc, err := encodeSubgraph(subgraph, nodes, classes)
+		if err != nil {
+			return nil, err
+		}
+		doc.Subgraphs = append(doc.Subgraphs, subgraphDoc)
+	}
+
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON replaces the flowchart with the one described by a JSON document and
+// re-links nodes, classes and links. New nodes and subgraphs get IDs that are not used by
+// the document.
+func (f *Flowchart) UnmarshalJSON(data []byte) error {
+	var doc flowchartDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	decoded := NewFlowchart()
+	if err := decoded.DecodeDocument(DocumentType, doc.Document); err != nil {
+		return err
+	}
+
+	var err error
+	if decoded.Config.ConfigurationProperties, decoded.Config.properties, err = doc.Config.Decode(); err != nil {
+		return err
+	}
+
+	if doc.Direction != "" {
+		decoded.Direction = doc.Direction
+	}
+	decoded.CurveStyle = doc.CurveStyle
+
+	d := &flowchartDecoder{
+		classes:  make(map[string]*Class, len(doc.Classes)),
+		nodes:    make(map[string]*Node, len(doc.Nodes)),
+		ids:      make(map[string]bool),
+		generate: utils.NewIDGenerator(),
+	}
+
+	for _, classDoc := range doc.Classes {
+		if d.classes[classDoc.Name] != nil {
+			return basediagram.DuplicateID(documentElementClass, classDoc.Name)
+		}
+		class := &Class{Name: classDoc.Name, Style: classDoc.Style.decode()}
+		d.classes[class.Name] = class
+		decoded.classes = append(decoded.classes, class)
+	}
+
+	for _, nodeDoc := range doc.Nodes {
+		node, err := d.node(nodeDoc)
+		if err != nil {
+			return err
+		}
+		decoded.nodes = append(decoded.nodes, node)
+	}
+
+	for _, subgraphDoc := range doc.Subgraphs {
+		subgraph, err := d.subgraph(subgraphDoc)
+		if err != nil {
+			return err
+		}
+		decoded.subgraphs = append(decoded.subgraphs, subgraph)
+	}
+
+	if decoded.links, err = d.links(doc.Links); err != nil {
+		return err
+	}
+
+	decoded.SetIDGenerator(d.generate)
+	*f = *decoded
+
+	return nil
+}
+
+// encodeStyle returns the document of a node style, or nil when there is none.
+func encodeStyle(style *NodeStyle) *styleDocument {
+	if style == nil {
+		return nil
+	}
+
+	return &styleDocument{
+		Color:       style.Color,
+		Fill:        style.Fill,
+		Stroke:      style.Stroke,
+		StrokeWidth: style.StrokeWidth,
+		StrokeDash:  style.StrokeDash,
+	}
+}
+
+// decode returns the node style described by the document, or nil when there is none.
+func (d *styleDocument) decode() *NodeStyle {
+	if d == nil {
+		return nil
+	}
+
+	return &NodeStyle{
+		Color:       d.Color,
+		Fill:        d.Fill,
+		Stroke:      d.Stroke,
+		StrokeWidth: d.StrokeWidth,
+		StrokeDash:  d.StrokeDash,
+	}
+}
+
+// encodeSubgraph returns the document of a subgraph and its nested subgraphs.
+func encodeSubgraph(subgraph *Subgraph, nodes map[*Node]bool, classes map[*Class]bool) (doc subgraphDocument, err error) {
+	doc = subgraphDocument{ID: subgraph.ID, Title: subgraph.Title, Direction: subgraph.Direction}
+	if subgraph.Class != nil {
+		if !classes[subgraph.Class] {
+			return doc, basediagram.UnknownReference(documentElementClass, subgraph.Class.Name)
+		}
+		doc.Class = subgraph.Class.Name
+	}
+
+	if doc.Links, err = encodeLinks(subgraph.links, nodes); err != nil {
+		return
+	}
+
+	for _, nested := range subgraph.subgraphs {
+		nestedDoc, err := encodeSubgraph(nested, nodes, classes)
+		if err != nil {
+			return doc, err
+		}
+		doc.Subgraphs = append(doc.Subgraphs, nestedDoc)
+	}
+
+	return
+}
+
+// encodeLinks returns the documents of links between nodes of the flowchart.
+func encodeLinks(links []*Link, nodes map[*Node]bool) (docs []linkDocument, err error) {
+	for _, link := range links {
+		for _, node := range []*Node{link.From, link.To} {
+			if node == nil {
+				return nil, basediagram.UnknownReference(documentElementNode, "")
+			}
+			if !nodes[node] {
+				return nil, basediagram.UnknownReference(documentElementNode, node.ID)
+			}
+		}
+
+		doc := linkDocument{From: link.From.ID, To: link.To.ID, Text: link.Text, Length: link.Length, Style: encodeStyle(link.Style)}
+		if doc.Shape, err = basediagram.EncodeName(linkShapeNames, link.Shape, LinkShapeOpen, documentValueLinkShape); err != nil {
+			return nil, err
+		}
+		if doc.Head, err = basediagram.EncodeName(arrowTypeNames, link.Head, LinkArrowTypeArrow, documentValueArrowType); err != nil {
+			return nil, err
+		}
+		if doc.Tail, err = basediagram.EncodeName(arrowTypeNames, link.Tail, LinkArrowTypeNone, documentValueArrowType); err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+
+	return
+}
+
+// flowchartDecoder rebuilds the elements of a flowchart document, resolving references
+// as it goes.
+type flowchartDecoder struct {
+	classes  map[string]*Class
+	nodes    map[string]*Node
+	ids      map[string]bool
+	generate *utils.DefaultIDGenerator
+}
+
+// claim records an ID shared by nodes and subgraphs and reports duplicates.
+func (d *flowchartDecoder) claim(element string, id string) error {
+	if d.ids[id] {
+		return basediagram.DuplicateID(element, id)
+	}
+
+	d.ids[id] = true
+	d.generate.Skip(id)
+
+	return nil
+}
+
+func (d *flowchartDecoder) node(doc nodeDocument) (*Node, error) {
+	if err := d.claim(documentElementNode, doc.ID); err != nil {
+		return nil, err
+	}
+
+	node := NewNode(doc.ID, doc.Text)
+	node.Style = doc.Style.decode()
+	if doc.Shape != "" {
+		node.Shape = doc.Shape
+	}
+	if doc.Class != "" {
+		if node.Class = d.classes[doc.Class]; node.Class == nil {
+			return nil, basediagram.UnknownReference(documentElementClass, doc.Class)
+		}
+	}
+	d.nodes[node.ID] = node
+
+	return node, nil
+}
+
+func (d *flowchartDecoder) subgraph(doc subgraphDocument) (subgraph *Subgraph, err error) {
+	if err = d.claim(documentElementSubgraph, doc.ID); err != nil {
+		return
+	}
+
+	subgraph = NewSubgraph(doc.ID, doc.Title)
+	subgraph.Direction = doc.Direction
+	if doc.Class != "" {
+		if subgraph.Class = d.classes[doc.Class]; subgraph.Class == nil {
+			return nil, basediagram.UnknownReference(documentElementClass, doc.Class)
+		}
+	}
+
+	for _, nestedDoc := range doc.Subgraphs {
+		nested, err := d.subgraph(nestedDoc)
+		if err != nil {
+			return nil, err
+		}
+		subgraph.subgraphs = append(subgraph.subgraphs, nested)
+	}
+
+	subgraph.links, err = d.links(doc.Links)
+
+	return
+}
+
+func (d *flowchartDecoder) links(docs []linkDocument) (links []*Link, err error) {
+	for _, doc := range docs {
+		from, to := d.nodes[doc.From], d.nodes[doc.To]
+		if from == nil {
+			return nil, basediagram.UnknownReference(documentElementNode, doc.From)
+		}
+		if to == nil {
+			return nil, basediagram.UnknownReference(documentElementNode, doc.To)
+		}
+
+		link := NewLink(from, to)
+		link.Text = doc.Text
+		link.Length = doc.Length
+		link.Style = doc.Style.decode()
+		if link.Shape, err = basediagram.DecodeName(linkShapeNames, doc.Shape, LinkShapeOpen, documentValueLinkShape); err != nil {
+			return nil, err
+		}
+		if link.Head, err = basediagram.DecodeName(arrowTypeNames, doc.Head, LinkArrowTypeArrow, documentValueArrowType); err != nil {
+			return nil, err
+		}
+		if link.Tail, err = basediagram.DecodeName(arrowTypeNames, doc.Tail, LinkArrowTypeNone, documentValueArrowType); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	return
+}
@@ -0,0 +1,202 @@
+package flowchart
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+func TestFlowchart_JSONRoundTrip(t *testing.T) {
+	original := NewFlowchart()
+	original.Title = "Build"
+	original.Direction = FlowchartDirectionLeftRight
+	original.CurveStyle = CurveStyleStep
+	original.EnableMarkdownFence()
+	original.Config.SetNodeSpacing(40).SetHtmlLabels(false)
+	original.Config.SetTheme(basediagram.ThemeDark).SetPrimaryColor("#f00")
+	class := original.AddClass("highlight")
+	class.Style.Fill = "#ff0"
+	start := original.NewNode("Start").SetShape(NodeShapeTerminal).SetClass(class)
+	check := original.NewNode(`Check "input"`).SetShape(NodeShapeDecision)
+	check.SetStyle(&NodeStyle{Stroke: "#00f", StrokeWidth: 2})
+	end := original.NewNode("End")
+	original.NewLink(start, check).SetText("go").SetShape(LinkShapeDotted)
+	link := original.NewLink(check, end)
+	link.Head, link.Tail, link.Length = LinkArrowTypeCross, LinkArrowTypeBullet, 2
+	group := original.AddSubgraph("Group")
+	group.Direction = SubgraphDirectionLeftRight
+	group.SetClass(class)
+	group.AddLink(end, start).SetStyle(&NodeStyle{Stroke: "#f00", StrokeDash: "5 5"})
+	group.AddSubgraph("Nested").AddLink(start, end)
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	decoded := NewFlowchart()
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if decoded.String() != original.String() {
+		t.Errorf("round trip output differs:\nwant:\n%s\ngot:\n%s", original.String(), decoded.String())
+	}
+
+	nodes := decoded.Nodes()
+	if nodes[0].Class != decoded.Classes()[0] {
+		t.Error("decoded node class should reference the decoded class")
+	}
+	for _, link := range decoded.Links() {
+		if decoded.FindNode(link.From.ID) != link.From || decoded.FindNode(link.To.ID) != link.To {
+			t.Errorf("decoded link %v should reference decoded nodes", link)
+		}
+	}
+
+	again, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("Marshal() of decoded flowchart error = %v", err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("encoding is not stable:\nfirst:  %s\nsecond: %s", data, again)
+	}
+
+	if id := decoded.NewNode("Next").ID; id != "5" {
+		t.Errorf("NewNode() after decoding got ID %q, want %q", id, "5")
+	}
+}
+
+func TestFlowchart_MarshalJSON(t *testing.T) {
+	f := NewFlowchart()
+	a := f.NewNode("A")
+	b := f.NewNode("B").SetShape(NodeShapeDatabase)
+	f.NewLink(a, b).Tail = LinkArrowTypeLeftArrow
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `{"version":1,"type":"flowchart","direction":"TB",` +
+		`"nodes":[{"id":"0","text":"A"},{"id":"1","shape":"cyl","text":"B"}],` +
+		`"links":[{"from":"0","to":"1","tail":"leftArrow"}]}`
+	if string(data) != want {
+		t.Errorf("Marshal() =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestFlowchart_MarshalJSONErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(f *Flowchart)
+		want  error
+	}{
+		{
+			name: "Link to node outside the flowchart",
+			setup: func(f *Flowchart) {
+				f.NewLink(f.NewNode("A"), NewNode("x", "X"))
+			},
+			want: basediagram.ErrUnknownReference,
+		},
+		{
+			name: "Node class outside the flowchart",
+			setup: func(f *Flowchart) {
+				f.NewNode("A").SetClass(NewClass("missing"))
+			},
+			want: basediagram.ErrUnknownReference,
+		},
+		{
+			name: "Unknown link shape",
+			setup: func(f *Flowchart) {
+				a := f.NewNode("A")
+				f.NewLink(a, a).SetShape("~%s~")
+			},
+			want: basediagram.ErrUnknownValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFlowchart()
+			tt.setup(f)
+
+			if _, err := json.Marshal(f); !errors.Is(err, tt.want) {
+				t.Errorf("Marshal() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlowchart_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		want     error
+		contains []string
+	}{
+		{
+			name:  "Defaults",
+			input: `{"version":1,"type":"flowchart","nodes":[{"id":"a"},{"id":"b","class":"c"}],"classes":[{"name":"c"}],"links":[{"from":"a","to":"b"}]}`,
+			contains: []string{
+				"flowchart TB",
+				`a@{ shape: rect, label: ""}`,
+				"a --> b",
+			},
+		},
+		{
+			name:  "Unsupported version",
+			input: `{"version":2,"type":"flowchart"}`,
+			want:  basediagram.ErrSchemaVersion,
+		},
+		{
+			name:  "Wrong diagram type",
+			input: `{"version":1,"type":"sequence"}`,
+			want:  basediagram.ErrDiagramType,
+		},
+		{
+			name:  "Unknown node",
+			input: `{"version":1,"type":"flowchart","nodes":[{"id":"a"}],"subgraphs":[{"id":"s","links":[{"from":"a","to":"b"}]}]}`,
+			want:  basediagram.ErrUnknownReference,
+		},
+		{
+			name:  "Unknown class",
+			input: `{"version":1,"type":"flowchart","nodes":[{"id":"a","class":"c"}]}`,
+			want:  basediagram.ErrUnknownReference,
+		},
+		{
+			name:  "Subgraph ID used by a node",
+			input: `{"version":1,"type":"flowchart","nodes":[{"id":"a"}],"subgraphs":[{"id":"a"}]}`,
+			want:  basediagram.ErrDuplicateID,
+		},
+		{
+			name:  "Unknown arrow type",
+			input: `{"version":1,"type":"flowchart","nodes":[{"id":"a"}],"links":[{"from":"a","to":"a","head":"diamond"}]}`,
+			want:  basediagram.ErrUnknownValue,
+		},
+		{
+			name:  "Invalid property",
+			input: `{"version":1,"type":"flowchart","config":{"properties":{"curve":null}}}`,
+			want:  basediagram.ErrPropertyValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFlowchart()
+			err := json.Unmarshal([]byte(tt.input), f)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Unmarshal() error = %v, want %v", err, tt.want)
+			}
+
+			output := f.String()
+			for _, want := range tt.contains {
+				if !strings.Contains(output, want) {
+					t.Errorf("String() = %q, want to contain %q", output, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package flowchart
+
+import (
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/graph"
+)
+
+// Graph is a directed graph view of a flowchart. It provides successors, predecessors,
+// reachability, shortest paths, cycle detection, topological order and strongly
+// connected components over the flowchart nodes.
+//
+// The view is a snapshot: later changes to the flowchart are not reflected.
+type Graph struct {
+	*graph.Graph[*Node]
+}
+
+// Graph returns a graph view of the flowchart built from its nodes and links,
+// including links declared inside subgraphs.
+func (f *Flowchart) Graph() *Graph {
+	g := graph.New[*Node]()
+
+	for _, node := range f.nodes {
+		g.AddNode(node)
+	}
+
+	for _, link := range f.Links() {
+		g.AddEdge(link.From, link.To)
+	}
+
+	return &Graph{Graph: g}
+}
+
+// Extract returns a copy of the flowchart that only contains the given nodes and the links
+// between them. Node styles, the classes used by the kept nodes and the subgraphs declaring
+// kept links are preserved; subgraphs left without links are dropped.
+//
+// Combined with Graph it cuts focused diagrams out of large ones, for example
+// f.Extract(f.Graph().Neighborhood(node, 2)) or f.Extract(append(f.Graph().Upstream(node, -1), node)).
+// Upstream and Downstream exclude the node they start from, so it is appended to keep it;
+// nodes given twice are kept once.
+func (f *Flowchart) Extract(nodes []*Node) *Flowchart {
+	extract, _ := f.extract(nodes)
+	return extract
+}
+
+// extract implements Extract and returns the cloner mapping original nodes to their copies.
+func (f *Flowchart) extract(nodes []*Node) (*Flowchart, *flowchartCloner) {
+	keep := make(map[*Node]bool, len(nodes))
+	for _, node := range nodes {
+		keep[node] = true
+	}
+
+	c := newFlowchartCloner()
+	extract := &Flowchart{
+		BaseDiagram: f.BaseDiagram,
+		Direction:   f.Direction,
+		CurveStyle:  f.CurveStyle,
+		classes:     make([]*Class, 0),
+		nodes:       make([]*Node, 0),
+		subgraphs:   make([]*Subgraph, 0),
+		links:       make([]*Link, 0),
+		idGenerator: utils.CloneIDGenerator(f.idGenerator),
+	}
+	extract.Config = f.Config.Clone()
+
+	used := make(map[*Class]bool)
+	for node := range keep {
+		if node.Class != nil {
+			used[node.Class] = true
+		}
+	}
+
+	for _, node := range f.nodes {
+		if keep[node] {
+			extract.nodes = append(extract.nodes, c.node(node))
+		}
+	}
+
+	for _, subgraph := range f.subgraphs {
+		if extracted := c.extractSubgraph(subgraph, keep, extract.idGenerator); extracted != nil {
+			extract.subgraphs = append(extract.subgraphs, extracted)
+		}
+	}
+
+	for _, link := range f.links {
+		if keep[link.From] && keep[link.To] {
+			extract.links = append(extract.links, c.link(link))
+		}
+	}
+
+	// Classes are copied last, as the extracted subgraphs may use classes no kept node uses.
+	for _, class := range f.classes {
+		if used[class] || c.classes[class] != nil {
+			extract.classes = append(extract.classes, c.class(class))
+		}
+	}
+
+	return extract, c
+}
+
+// extractSubgraph copies the subgraph with the links between kept nodes,
+// returning nil if neither it nor its nested subgraphs keep any link.
+func (c *flowchartCloner) extractSubgraph(subgraph *Subgraph, keep map[*Node]bool, idGenerator utils.IDGenerator) *Subgraph {
+	extracted := &Subgraph{
+		ID:          subgraph.ID,
+		Title:       subgraph.Title,
+		Direction:   subgraph.Direction,
+		idGenerator: idGenerator,
+	}
+
+	for _, nested := range subgraph.subgraphs {
+		if nestedExtract := c.extractSubgraph(nested, keep, idGenerator); nestedExtract != nil {
+			extracted.subgraphs = append(extracted.subgraphs, nestedExtract)
+		}
+	}
+
+	for _, link := range subgraph.links {
+		if keep[link.From] && keep[link.To] {
+			extracted.links = append(extracted.links, c.link(link))
+		}
+	}
+
+	if len(extracted.links) == 0 && len(extracted.subgraphs) == 0 {
+		return nil
+	}
+	extracted.Class = c.class(subgraph.Class)
+
+	return extracted
+}
+
+// NodeSubgraphs maps every node referenced by a subgraph link to the subgraph it is drawn in:
+// the first subgraph, in depth-first declaration order, whose own links reference the node.
+// Renderers use it to draw subgraphs as clusters around their nodes.
+func (f *Flowchart) NodeSubgraphs() map[*Node]*Subgraph {
+	subgraphs := make(map[*Node]*Subgraph)
+
+	for _, subgraph := range f.subgraphs {
+		subgraph.walk(func(s *Subgraph) {
+			for _, link := range s.links {
+				for _, node := range []*Node{link.From, link.To} {
+					if _, ok := subgraphs[node]; !ok {
+						subgraphs[node] = s
+					}
+				}
+			}
+		})
+	}
+
+	return subgraphs
+}
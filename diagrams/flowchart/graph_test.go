@@ -0,0 +1,141 @@
+package flowchart
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newGraphTestFlowchart returns a flowchart a -> b -> c -> d with e -> c declared in a subgraph.
+func newGraphTestFlowchart() (*Flowchart, map[string]*Node) {
+	f := NewFlowchart()
+	nodes := make(map[string]*Node)
+
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		nodes[id] = NewNode(id, strings.ToUpper(id))
+		f.AddNode(nodes[id])
+	}
+
+	f.NewLink(nodes["a"], nodes["b"])
+	f.NewLink(nodes["b"], nodes["c"])
+	f.NewLink(nodes["c"], nodes["d"])
+	f.AddSubgraph("Group").AddLink(nodes["e"], nodes["c"])
+
+	return f, nodes
+}
+
+// nodeIDs returns the IDs of the nodes.
+func nodeIDs(nodes []*Node) []string {
+	ids := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		ids = append(ids, node.ID)
+	}
+	return ids
+}
+
+func TestFlowchart_Graph(t *testing.T) {
+	f, nodes := newGraphTestFlowchart()
+	g := f.Graph()
+
+	tests := []struct {
+		name string
+		got  []*Node
+		want []string
+	}{
+		{name: "Successors", got: g.Successors(nodes["b"]), want: []string{"c"}},
+		{name: "Predecessors include subgraph links", got: g.Predecessors(nodes["c"]), want: []string{"b", "e"}},
+		{name: "Downstream", got: g.Downstream(nodes["b"], -1), want: []string{"c", "d"}},
+		{name: "Upstream", got: g.Upstream(nodes["d"], -1), want: []string{"a", "b", "c", "e"}},
+		{name: "Neighborhood", got: g.Neighborhood(nodes["c"], 1), want: []string{"c", "b", "d", "e"}},
+		{name: "Shortest path", got: g.ShortestPath(nodes["a"], nodes["d"]), want: []string{"a", "b", "c", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nodeIDs(tt.got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if g.HasCycle() {
+		t.Error("HasCycle() = true, want false")
+	}
+
+	order, err := g.TopologicalSort()
+	if err != nil || !reflect.DeepEqual(nodeIDs(order), []string{"a", "b", "e", "c", "d"}) {
+		t.Errorf("TopologicalSort() = %v, %v", nodeIDs(order), err)
+	}
+
+	f.NewLink(nodes["d"], nodes["b"])
+
+	if !f.Graph().HasCycle() {
+		t.Error("HasCycle() = false after adding a back link")
+	}
+
+	components := f.Graph().StronglyConnectedComponents()
+	if len(components) != 3 || !reflect.DeepEqual(nodeIDs(components[1]), []string{"b", "c", "d"}) {
+		t.Errorf("StronglyConnectedComponents() = %v", components)
+	}
+}
+
+func TestFlowchart_Extract(t *testing.T) {
+	f, nodes := newGraphTestFlowchart()
+	hot := f.AddClass("hot")
+	cold := f.AddClass("cold")
+	nodes["c"].SetClass(hot)
+	nodes["e"].SetStyle(&NodeStyle{Fill: "#eee"})
+	nodes["a"].SetClass(cold)
+	f.AddSubgraph("Empty")
+
+	extract := f.Extract(append(f.Graph().Upstream(nodes["c"], 1), nodes["c"]))
+
+	if got := nodeIDs(extract.Nodes()); !reflect.DeepEqual(got, []string{"b", "c", "e"}) {
+		t.Errorf("Extract() nodes = %v", got)
+	}
+
+	if len(extract.Classes()) != 1 || extract.Classes()[0].Name != "hot" || extract.FindNode("c").Class != extract.Classes()[0] {
+		t.Error("Extract() should keep the classes used by kept nodes")
+	}
+
+	if extract.FindNode("e").Style == nodes["e"].Style || extract.FindNode("e").Style.Fill != "#eee" {
+		t.Error("Extract() should copy node styles")
+	}
+
+	if len(extract.Subgraphs()) != 1 || len(extract.Subgraphs()[0].Links()) != 1 {
+		t.Errorf("Extract() should keep subgraphs with links and drop empty ones, got %d", len(extract.Subgraphs()))
+	}
+
+	if len(extract.Links()) != 2 {
+		t.Errorf("Extract() links = %d, want 2", len(extract.Links()))
+	}
+
+	output := extract.String()
+	if strings.Contains(output, "a@") || strings.Contains(output, "cold") {
+		t.Errorf("Extract() output contains removed elements:\n%s", output)
+	}
+
+	if len(f.Nodes()) != 5 {
+		t.Error("Extract() modified the original flowchart")
+	}
+}
+
+func TestFlowchart_NodeSubgraphs(t *testing.T) {
+	f, nodes := newGraphTestFlowchart()
+	group := f.Subgraphs()[0]
+	nested := group.AddSubgraph("Nested")
+	nested.AddLink(nodes["c"], nodes["d"])
+	f.AddSubgraph("Later").AddLink(nodes["e"], nodes["a"])
+
+	got := f.NodeSubgraphs()
+
+	want := map[*Node]*Subgraph{
+		nodes["e"]: group,
+		nodes["c"]: group,
+		nodes["d"]: nested,
+		nodes["a"]: f.Subgraphs()[1],
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NodeSubgraphs() = %v, want %v", got, want)
+	}
+}
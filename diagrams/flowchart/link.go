@@ -33,8 +33,9 @@ const (
 )
 
 const (
-	baseLinkString     string = basediagram.Indentation + "%s %s%s%s%s %s\n"
-	baseLinkTextString string = "|%s|"
+	baseLinkString      string = basediagram.Indentation + "%s %s%s%s%s %s\n"
+	baseLinkTextString  string = "|%s|"
+	baseLinkStyleString string = basediagram.Indentation + "linkStyle %d %s\n"
 )
 
 // Link represents a connection between nodes in a flowchart
@@ -46,6 +47,7 @@ type Link struct {
 	From   *Node
 	To     *Node
 	Length int
+	Style  *NodeStyle
 }
 
 // NewLink creates a new Link and sets default values to some attributes
@@ -74,6 +76,12 @@ func (l *Link) SetShape(shape LinkShape) *Link {
 	return l
 }
 
+// SetStyle sets the style of the link line and returns the link for chaining
+func (l *Link) SetStyle(style *NodeStyle) *Link {
+	l.Style = style
+	return l
+}
+
 // SetLength sets the link length and returns the link for chaining
 func (l *Link) SetLength(length int) *Link {
 	l.Length = length
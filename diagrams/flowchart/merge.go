@@ -0,0 +1,441 @@
+package flowchart
+
+import (
+	"fmt"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// Merge conflict element kinds reported by Flowchart.Merge.
+const (
+	MergeElementNode     string = "node"
+	MergeElementClass    string = "class"
+	MergeElementSubgraph string = "subgraph"
+)
+
+const (
+	mergeReasonText       string = "text differs (%q vs %q)"
+	mergeReasonShape      string = "shape differs (%s vs %s)"
+	mergeReasonTitle      string = "title differs (%q vs %q)"
+	mergeReasonStyle      string = "style %s differs (%v vs %v)"
+	mergeReasonNodeClass  string = "class differs (%s vs %s)"
+	mergeReasonIDInUse    string = "ID already in use"
+	mergeStyleColor       string = "color"
+	mergeStyleFill        string = "fill"
+	mergeStyleStroke      string = "stroke"
+	mergeStyleStrokeWidth string = "stroke-width"
+	mergeStyleStrokeDash  string = "stroke-dasharray"
+)
+
+// SetIDGenerator replaces the generator used for node and subgraph IDs and returns the flowchart for chaining.
+func (f *Flowchart) SetIDGenerator(idGenerator utils.IDGenerator) *Flowchart {
+	f.idGenerator = idGenerator
+	for _, subgraph := range f.subgraphs {
+		subgraph.setIDGenerator(idGenerator)
+	}
+	return f
+}
+
+// Clone returns a deep copy of the flowchart.
+// Links, classes and subgraphs of the copy reference the copied nodes.
+func (f *Flowchart) Clone() *Flowchart {
+	c := newFlowchartCloner()
+
+	clone := &Flowchart{
+		BaseDiagram: f.BaseDiagram,
+		Direction:   f.Direction,
+		CurveStyle:  f.CurveStyle,
+		classes:     make([]*Class, 0, len(f.classes)),
+		nodes:       make([]*Node, 0, len(f.nodes)),
+		subgraphs:   make([]*Subgraph, 0, len(f.subgraphs)),
+		links:       make([]*Link, 0, len(f.links)),
+		idGenerator: utils.CloneIDGenerator(f.idGenerator),
+	}
+	clone.Config = f.Config.Clone()
+
+	for _, class := range f.classes {
+		clone.classes = append(clone.classes, c.class(class))
+	}
+
+	for _, node := range f.nodes {
+		clone.nodes = append(clone.nodes, c.node(node))
+	}
+
+	for _, subgraph := range f.subgraphs {
+		clone.subgraphs = append(clone.subgraphs, c.subgraph(subgraph, clone.idGenerator))
+	}
+
+	for _, link := range f.links {
+		clone.links = append(clone.links, c.link(link))
+	}
+
+	return clone
+}
+
+// Merge copies the classes, nodes, subgraphs and links of other into the flowchart
+// and returns the conflicts that were encountered. The other flowchart is not modified.
+//
+// Nodes, including those only referenced by links, are matched by ID. A node whose ID
+// already exists with the same text and shape is treated as the same node: its style and
+// class are merged into the existing node and links are re-pointed to it. A node whose ID
+// exists with a different text or shape is added under a new ID drawn from the
+// flowchart's IDGenerator and reported as a conflict.
+// Subgraphs are matched by ID and title in the same way. The IDGenerator is advanced past
+// the IDs taken over, so nodes and subgraphs created after the merge do not reuse them.
+//
+// Classes are matched by name. Style properties missing from the existing class are
+// taken from the incoming one; properties set differently in both are reported as
+// conflicts and the existing value is kept. Duplicate links are skipped.
+func (f *Flowchart) Merge(other *Flowchart) (conflicts []basediagram.MergeConflict) {
+	incoming := other.Clone()
+
+	taken := make(map[string]bool)
+	existingNodes := make(map[string]*Node)
+	for _, node := range f.allNodes() {
+		taken[node.ID] = true
+		if existingNodes[node.ID] == nil {
+			existingNodes[node.ID] = node
+		}
+	}
+	for _, subgraph := range f.subgraphs {
+		subgraph.walk(func(s *Subgraph) {
+			taken[s.ID] = true
+		})
+	}
+	nextID := func() string {
+		id := utils.NextFreeID(f.idGenerator, func(id string) bool { return taken[id] })
+		taken[id] = true
+		return id
+	}
+
+	classMap := make(map[*Class]*Class)
+	for _, class := range incoming.classes {
+		existing := f.FindClass(class.Name)
+		if existing == nil {
+			f.classes = append(f.classes, class)
+			continue
+		}
+
+		classMap[class] = existing
+		if existing.Style == nil {
+			existing.Style = class.Style
+		}
+		for _, reason := range existing.Style.merge(class.Style) {
+			conflicts = append(conflicts, basediagram.MergeConflict{Element: MergeElementClass, ID: class.Name, Reason: reason})
+		}
+	}
+
+	registered := make(map[*Node]bool)
+	for _, node := range incoming.nodes {
+		registered[node] = true
+	}
+
+	// Nodes only referenced by links take part in conflict detection as well,
+	// but stay unregistered in the merged flowchart.
+	add := func(node *Node) {
+		existingNodes[node.ID] = node
+		if registered[node] {
+			f.nodes = append(f.nodes, node)
+		}
+	}
+
+	nodeMap := make(map[*Node]*Node)
+	for _, node := range incoming.allNodes() {
+		if mapped, ok := classMap[node.Class]; ok {
+			node.Class = mapped
+		}
+
+		existing := existingNodes[node.ID]
+		if existing == nil && !taken[node.ID] {
+			taken[node.ID] = true
+			utils.SkipIDs(f.idGenerator, node.ID)
+			add(node)
+			continue
+		}
+
+		if existing != nil && existing.Text == node.Text && existing.Shape == node.Shape {
+			nodeMap[node] = existing
+			for _, reason := range existing.merge(node) {
+				conflicts = append(conflicts, basediagram.MergeConflict{Element: MergeElementNode, ID: node.ID, Reason: reason})
+			}
+			continue
+		}
+
+		conflict := basediagram.MergeConflict{Element: MergeElementNode, ID: node.ID, Reason: nodeDifference(existing, node)}
+		node.ID = nextID()
+		conflict.NewID = node.ID
+		conflicts = append(conflicts, conflict)
+		add(node)
+	}
+
+	remap := func(link *Link) {
+		if mapped, ok := nodeMap[link.From]; ok {
+			link.From = mapped
+		}
+		if mapped, ok := nodeMap[link.To]; ok {
+			link.To = mapped
+		}
+	}
+
+	for _, subgraph := range incoming.subgraphs {
+		subgraph.walk(func(s *Subgraph) {
+			if mapped, ok := classMap[s.Class]; ok {
+				s.Class = mapped
+			}
+			for _, link := range s.links {
+				remap(link)
+			}
+		})
+	}
+
+	f.subgraphs, conflicts = mergeSubgraphs(f.subgraphs, incoming.subgraphs, f.idGenerator, taken, nextID, conflicts)
+
+	existingLinks := f.Links()
+	for _, link := range incoming.links {
+		remap(link)
+		if !containsLink(existingLinks, link) {
+			f.links = append(f.links, link)
+			existingLinks = append(existingLinks, link)
+		}
+	}
+
+	return
+}
+
+// mergeSubgraphs merges incoming subgraphs into existing ones, matching them by ID and title.
+func mergeSubgraphs(existing []*Subgraph, incoming []*Subgraph, idGenerator utils.IDGenerator, taken map[string]bool, nextID func() string, conflicts []basediagram.MergeConflict) ([]*Subgraph, []basediagram.MergeConflict) {
+	for _, subgraph := range incoming {
+		var match *Subgraph
+		for _, current := range existing {
+			if current.ID == subgraph.ID {
+				match = current
+				break
+			}
+		}
+
+		if match != nil && match.Title == subgraph.Title {
+			if match.Class == nil {
+				match.Class = subgraph.Class
+			}
+			for _, link := range subgraph.links {
+				if !containsLink(match.links, link) {
+					match.links = append(match.links, link)
+				}
+			}
+			match.subgraphs, conflicts = mergeSubgraphs(match.subgraphs, subgraph.subgraphs, idGenerator, taken, nextID, conflicts)
+			continue
+		}
+
+		subgraph.walk(func(s *Subgraph) {
+			if !taken[s.ID] {
+				taken[s.ID] = true
+				utils.SkipIDs(idGenerator, s.ID)
+				return
+			}
+
+			conflict := basediagram.MergeConflict{Element: MergeElementSubgraph, ID: s.ID, Reason: mergeReasonIDInUse}
+			if s == subgraph && match != nil {
+				conflict.Reason = fmt.Sprintf(mergeReasonTitle, match.Title, s.Title)
+			}
+			s.ID = nextID()
+			conflict.NewID = s.ID
+			conflicts = append(conflicts, conflict)
+		})
+		subgraph.setIDGenerator(idGenerator)
+		existing = append(existing, subgraph)
+	}
+
+	return existing, conflicts
+}
+
+// containsLink reports whether links contains a link equivalent to link.
+func containsLink(links []*Link, link *Link) bool {
+	for _, current := range links {
+		if current.From == link.From && current.To == link.To && current.Text == link.Text &&
+			current.Shape == link.Shape && current.Head == link.Head && current.Tail == link.Tail &&
+			current.Length == link.Length {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nodeDifference describes why two nodes sharing an ID cannot be unified.
+func nodeDifference(existing *Node, incoming *Node) string {
+	if existing == nil {
+		return mergeReasonIDInUse
+	}
+
+	if existing.Text != incoming.Text {
+		return fmt.Sprintf(mergeReasonText, existing.Text, incoming.Text)
+	}
+
+	return fmt.Sprintf(mergeReasonShape, existing.Shape, incoming.Shape)
+}
+
+// allNodes returns the registered nodes and every node that is only referenced by links.
+func (f *Flowchart) allNodes() []*Node {
+	seen := make(map[*Node]bool)
+	nodes := make([]*Node, 0, len(f.nodes))
+
+	add := func(node *Node) {
+		if node != nil && !seen[node] {
+			seen[node] = true
+			nodes = append(nodes, node)
+		}
+	}
+
+	for _, node := range f.nodes {
+		add(node)
+	}
+
+	for _, link := range f.Links() {
+		add(link.From)
+		add(link.To)
+	}
+
+	return nodes
+}
+
+// merge copies the style and class of incoming into the node where the node has none,
+// and returns the differences that could not be merged.
+func (n *Node) merge(incoming *Node) (differences []string) {
+	if n.Style == nil {
+		n.Style = incoming.Style
+	} else {
+		differences = append(differences, n.Style.merge(incoming.Style)...)
+	}
+
+	if n.Class == nil {
+		n.Class = incoming.Class
+	} else if incoming.Class != nil && incoming.Class.Name != n.Class.Name {
+		differences = append(differences, fmt.Sprintf(mergeReasonNodeClass, n.Class.Name, incoming.Class.Name))
+	}
+
+	return
+}
+
+// merge copies the properties of incoming that are unset in the style
+// and returns the properties that are set differently in both.
+func (n *NodeStyle) merge(incoming *NodeStyle) (differences []string) {
+	if incoming == nil {
+		return
+	}
+
+	mergeString := func(name string, current *string, value string) {
+		switch {
+		case value == "" || *current == value:
+		case *current == "":
+			*current = value
+		default:
+			differences = append(differences, fmt.Sprintf(mergeReasonStyle, name, *current, value))
+		}
+	}
+
+	mergeString(mergeStyleColor, &n.Color, incoming.Color)
+	mergeString(mergeStyleFill, &n.Fill, incoming.Fill)
+	mergeString(mergeStyleStroke, &n.Stroke, incoming.Stroke)
+	mergeString(mergeStyleStrokeDash, &n.StrokeDash, incoming.StrokeDash)
+
+	switch {
+	case incoming.StrokeWidth == 0 || n.StrokeWidth == incoming.StrokeWidth:
+	case n.StrokeWidth == 0:
+		n.StrokeWidth = incoming.StrokeWidth
+	default:
+		differences = append(differences, fmt.Sprintf(mergeReasonStyle, mergeStyleStrokeWidth, n.StrokeWidth, incoming.StrokeWidth))
+	}
+
+	return
+}
+
+// flowchartCloner copies flowchart elements while preserving the references between them.
+type flowchartCloner struct {
+	nodes   map[*Node]*Node
+	classes map[*Class]*Class
+}
+
+func newFlowchartCloner() *flowchartCloner {
+	return &flowchartCloner{
+		nodes:   make(map[*Node]*Node),
+		classes: make(map[*Class]*Class),
+	}
+}
+
+func (c *flowchartCloner) class(class *Class) *Class {
+	if class == nil {
+		return nil
+	}
+
+	if cloned, ok := c.classes[class]; ok {
+		return cloned
+	}
+
+	cloned := &Class{
+		Name:  class.Name,
+		Style: class.Style.clone(),
+	}
+	c.classes[class] = cloned
+
+	return cloned
+}
+
+func (c *flowchartCloner) node(node *Node) *Node {
+	if node == nil {
+		return nil
+	}
+
+	if cloned, ok := c.nodes[node]; ok {
+		return cloned
+	}
+
+	cloned := &Node{
+		ID:    node.ID,
+		Shape: node.Shape,
+		Text:  node.Text,
+		Style: node.Style.clone(),
+		Class: c.class(node.Class),
+	}
+	c.nodes[node] = cloned
+
+	return cloned
+}
+
+func (c *flowchartCloner) link(link *Link) *Link {
+	cloned := *link
+	cloned.From = c.node(link.From)
+	cloned.To = c.node(link.To)
+	cloned.Style = link.Style.clone()
+	return &cloned
+}
+
+func (c *flowchartCloner) subgraph(subgraph *Subgraph, idGenerator utils.IDGenerator) *Subgraph {
+	cloned := &Subgraph{
+		ID:          subgraph.ID,
+		Title:       subgraph.Title,
+		Direction:   subgraph.Direction,
+		Class:       c.class(subgraph.Class),
+		idGenerator: idGenerator,
+	}
+
+	for _, nested := range subgraph.subgraphs {
+		cloned.subgraphs = append(cloned.subgraphs, c.subgraph(nested, idGenerator))
+	}
+
+	for _, link := range subgraph.links {
+		cloned.links = append(cloned.links, c.link(link))
+	}
+
+	return cloned
+}
+
+// clone returns a copy of the style, or nil for a nil style.
+func (n *NodeStyle) clone() *NodeStyle {
+	if n == nil {
+		return nil
+	}
+
+	cloned := *n
+	return &cloned
+}
@@ -0,0 +1,305 @@
+package flowchart
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+)
+
+func TestFlowchart_Clone(t *testing.T) {
+	original := NewFlowchart()
+	original.Title = "Original"
+	original.Config.SetPadding(10)
+	class := original.AddClass("highlight")
+	class.Style.Fill = "#f00"
+	node1 := original.NewNode("Start").SetClass(class)
+	node1.SetStyle(&NodeStyle{Color: "#000"})
+	node2 := original.NewNode("End")
+	original.NewLink(node1, node2).SetText("go")
+	subgraph := original.AddSubgraph("Group").SetClass(class)
+	subgraph.AddLink(node2, node1).SetStyle(&NodeStyle{Stroke: "#00f"})
+	subgraph.AddSubgraph("Nested")
+
+	clone := original.Clone()
+
+	if clone.String() != original.String() {
+		t.Errorf("Clone() output differs:\nwant:\n%s\ngot:\n%s", original.String(), clone.String())
+	}
+
+	clonedNodes := clone.Nodes()
+	if clonedNodes[0] == node1 || clonedNodes[0].Style == node1.Style || clonedNodes[0].Class == class {
+		t.Error("Clone() should copy nodes, styles and classes")
+	}
+
+	if clonedNodes[0].Class != clone.Classes()[0] || clone.Subgraphs()[0].Class != clone.Classes()[0] {
+		t.Error("Clone() node and subgraph classes should reference the cloned class")
+	}
+
+	if clone.Subgraphs()[0].Links()[0].Style == subgraph.Links()[0].Style {
+		t.Error("Clone() should copy link styles")
+	}
+
+	for _, link := range clone.Links() {
+		if link.From != clonedNodes[0] && link.From != clonedNodes[1] {
+			t.Errorf("Clone() link %v should reference cloned nodes", link)
+		}
+	}
+
+	clone.NewNode("Extra")
+	clone.Config.SetCurve("basis")
+	clone.Subgraphs()[0].AddLink(clonedNodes[0], clonedNodes[1])
+
+	if len(original.Nodes()) != 2 || len(original.Links()) != 2 {
+		t.Error("Modifying the clone changed the original")
+	}
+
+	if strings.Contains(original.String(), "curve") {
+		t.Error("Modifying the clone configuration changed the original")
+	}
+
+	if clone.NewNode("Next").ID == original.NewNode("Next").ID {
+		t.Error("Clone() should continue ID generation independently of the original")
+	}
+}
+
+func TestFlowchart_Merge(t *testing.T) {
+	tests := []struct {
+		name          string
+		setup         func(base *Flowchart, other *Flowchart)
+		wantNodes     []string
+		wantLinks     int
+		wantConflicts []string
+	}{
+		{
+			name: "Disjoint flowcharts",
+			setup: func(base *Flowchart, other *Flowchart) {
+				base.AddNode(NewNode("a", "A"))
+				other.AddNode(NewNode("b", "B"))
+			},
+			wantNodes: []string{"a", "b"},
+		},
+		{
+			name: "Identical nodes are unified",
+			setup: func(base *Flowchart, other *Flowchart) {
+				a := NewNode("a", "A")
+				base.AddNode(a)
+				b := NewNode("b", "B")
+				base.AddNode(b)
+				base.NewLink(a, b)
+
+				otherA := NewNode("a", "A")
+				other.AddNode(otherA)
+				c := NewNode("c", "C")
+				other.AddNode(c)
+				other.NewLink(otherA, c)
+			},
+			wantNodes: []string{"a", "b", "c"},
+			wantLinks: 2,
+		},
+		{
+			name: "Conflicting nodes are remapped",
+			setup: func(base *Flowchart, other *Flowchart) {
+				base.NewNode("First")
+				other.NewNode("Other")
+			},
+			wantNodes:     []string{"0", "1"},
+			wantConflicts: []string{`node "0": text differs ("First" vs "Other") (added as "1")`},
+		},
+		{
+			name: "Duplicate links are skipped",
+			setup: func(base *Flowchart, other *Flowchart) {
+				for _, f := range []*Flowchart{base, other} {
+					a := NewNode("a", "A")
+					b := NewNode("b", "B")
+					f.AddNode(a)
+					f.AddNode(b)
+					f.NewLink(a, b)
+				}
+			},
+			wantNodes: []string{"a", "b"},
+			wantLinks: 1,
+		},
+		{
+			name: "Class styles are merged",
+			setup: func(base *Flowchart, other *Flowchart) {
+				base.AddClass("hot").Style.Fill = "#f00"
+				otherClass := other.AddClass("hot")
+				otherClass.Style.Fill = "#0f0"
+				otherClass.Style.Color = "#fff"
+			},
+			wantNodes:     []string{},
+			wantConflicts: []string{`class "hot": style fill differs (#f00 vs #0f0)`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewFlowchart()
+			other := NewFlowchart()
+			tt.setup(base, other)
+			otherOutput := other.String()
+
+			conflicts := base.Merge(other)
+
+			gotNodes := []string{}
+			for _, node := range base.Nodes() {
+				gotNodes = append(gotNodes, node.ID)
+			}
+			if !reflect.DeepEqual(gotNodes, tt.wantNodes) {
+				t.Errorf("Merge() nodes = %v, want %v", gotNodes, tt.wantNodes)
+			}
+
+			if len(base.Links()) != tt.wantLinks {
+				t.Errorf("Merge() links = %d, want %d", len(base.Links()), tt.wantLinks)
+			}
+
+			gotConflicts := []string{}
+			for _, conflict := range conflicts {
+				gotConflicts = append(gotConflicts, conflict.Error())
+			}
+			if len(tt.wantConflicts) == 0 {
+				tt.wantConflicts = []string{}
+			}
+			if !reflect.DeepEqual(gotConflicts, tt.wantConflicts) {
+				t.Errorf("Merge() conflicts = %v, want %v", gotConflicts, tt.wantConflicts)
+			}
+
+			if other.String() != otherOutput {
+				t.Error("Merge() modified the other flowchart")
+			}
+		})
+	}
+}
+
+func TestFlowchart_MergeLinksReferenceUnifiedNodes(t *testing.T) {
+	base := NewFlowchart()
+	a := NewNode("a", "A")
+	base.AddNode(a)
+
+	other := NewFlowchart()
+	otherA := NewNode("a", "A")
+	otherA.SetStyle(&NodeStyle{Fill: "#eee"})
+	b := NewNode("b", "B")
+	other.AddNode(otherA)
+	other.AddNode(b)
+	other.AddSubgraph("Group").AddLink(otherA, b)
+
+	if conflicts := base.Merge(other); len(conflicts) != 0 {
+		t.Fatalf("Merge() conflicts = %v, want none", conflicts)
+	}
+
+	links := base.Links()
+	if len(links) != 1 || links[0].From != a {
+		t.Errorf("Merge() link should reference the existing node, got %v", links)
+	}
+
+	if a.Style == nil || a.Style.Fill != "#eee" {
+		t.Error("Merge() should copy the style of a unified node")
+	}
+}
+
+func TestFlowchart_MergeLinkOnlyNodes(t *testing.T) {
+	base := NewFlowchart()
+	base.NewNode("Alpha")
+	base.NewNode("Beta")
+
+	other := NewFlowchart()
+	group := other.AddSubgraph("Group")
+	group.ID = "group"
+	group.AddLink(NewNode("0", "Other"), NewNode("1", "Yet"))
+
+	conflicts := base.Merge(other)
+
+	want := []string{
+		`node "0": text differs ("Alpha" vs "Other") (added as "2")`,
+		`node "1": text differs ("Beta" vs "Yet") (added as "3")`,
+	}
+	if len(conflicts) != len(want) {
+		t.Fatalf("Merge() conflicts = %v, want %v", conflicts, want)
+	}
+	for i, conflict := range conflicts {
+		if conflict.Error() != want[i] {
+			t.Errorf("Merge() conflict = %q, want %q", conflict.Error(), want[i])
+		}
+	}
+
+	ids := make(map[string]bool)
+	for _, node := range base.allNodes() {
+		if ids[node.ID] {
+			t.Errorf("Merge() node ID %q is used twice", node.ID)
+		}
+		ids[node.ID] = true
+	}
+
+	if len(base.Nodes()) != 2 {
+		t.Errorf("Merge() nodes = %d, want 2", len(base.Nodes()))
+	}
+}
+
+func TestFlowchart_MergeSubgraphs(t *testing.T) {
+	base := NewFlowchart()
+	base.AddSubgraph("Shared")
+	base.AddSubgraph("Base only")
+
+	other := NewFlowchart()
+	other.AddSubgraph("Shared")
+	other.AddSubgraph("Other only")
+
+	conflicts := base.Merge(other)
+
+	if len(base.Subgraphs()) != 3 {
+		t.Fatalf("Merge() subgraphs = %d, want 3", len(base.Subgraphs()))
+	}
+
+	if len(conflicts) != 1 || conflicts[0].Element != MergeElementSubgraph || conflicts[0].NewID != "2" {
+		t.Errorf("Merge() conflicts = %v, want one remapped subgraph", conflicts)
+	}
+}
+
+func TestFlowchart_MergeAdvancesIDGenerator(t *testing.T) {
+	base := NewFlowchart()
+	base.NewNode("Base")
+
+	other := NewFlowchart()
+	other.AddNode(NewNode("2", "Other"))
+	other.AddSubgraph("Group").ID = "3"
+
+	base.Merge(other)
+
+	ids := make(map[string]bool)
+	for _, node := range base.Nodes() {
+		ids[node.ID] = true
+	}
+	for _, subgraph := range base.Subgraphs() {
+		ids[subgraph.ID] = true
+	}
+
+	for _, id := range []string{base.NewNode("After").ID, base.NewNode("After").ID, base.AddSubgraph("After").ID} {
+		if ids[id] {
+			t.Errorf("NewNode() or AddSubgraph() after Merge() = %v, already in use", id)
+		}
+		ids[id] = true
+	}
+}
+
+func TestFlowchart_SetIDGenerator(t *testing.T) {
+	generator := utils.NewIDGenerator()
+	generator.NextID()
+
+	flowchart := NewFlowchart()
+	subgraph := flowchart.AddSubgraph("Group")
+
+	if flowchart.SetIDGenerator(generator) != flowchart {
+		t.Error("SetIDGenerator() should return flowchart for chaining")
+	}
+
+	if node := flowchart.NewNode("Node"); node.ID != "1" {
+		t.Errorf("NewNode() ID = %v, want 1", node.ID)
+	}
+
+	if nested := subgraph.AddSubgraph("Nested"); nested.ID != "2" {
+		t.Errorf("AddSubgraph() ID = %v, want 2", nested.ID)
+	}
+}
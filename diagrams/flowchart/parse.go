@@ -0,0 +1,821 @@
+package flowchart
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// Keywords of flowchart source.
+const (
+	keywordFlowchart = "flowchart"
+	keywordGraph     = "graph"
+	keywordSubgraph  = "subgraph"
+	keywordEnd       = "end"
+	keywordDirection = "direction"
+	keywordClassDef  = "classDef"
+	keywordClass     = "class"
+	keywordStyle     = "style"
+	keywordLinkStyle = "linkStyle"
+	keywordDefault   = "default"
+
+	// configKey is the frontmatter configuration member holding the flowchart properties.
+	configKey = "flowchart"
+
+	classSeparator = ":::"
+	nodeSeparator  = "&"
+	listSeparator  = ","
+	shapeStart     = "@{"
+	shapeEnd       = "}"
+	shapeKeyShape  = "shape"
+	shapeKeyLabel  = "label"
+	pixelSuffix    = "px"
+	quote          = `"`
+)
+
+// Style properties of classDef and style statements.
+const (
+	stylePropertyColor       = "color"
+	stylePropertyFill        = "fill"
+	stylePropertyStroke      = "stroke"
+	stylePropertyStrokeWidth = "stroke-width"
+	stylePropertyStrokeDash  = "stroke-dasharray"
+)
+
+// unsupportedKeywords start the statements the model has no counterpart for.
+var unsupportedKeywords = []string{"click", "accTitle", "accDescr"}
+
+// Link patterns. A link is either complete, such as "-.->" followed by an optional "|text|",
+// or opened before its text and closed after it, as in "-- text -->".
+var (
+	linkPattern       = regexp.MustCompile(`^([<ox]?)(-{2,}|-\.+-|={2,}|~{3,})([>ox]?)`)
+	linkOpenPattern   = regexp.MustCompile(`^([<ox]?)(--|-\.|==)(?:\s|[^-.=>ox|])`)
+	linkClosePatterns = map[string]*regexp.Regexp{
+		"--": regexp.MustCompile(`(-{2,})([>ox]?)`),
+		"-.": regexp.MustCompile(`(\.+-)([>ox]?)`),
+		"==": regexp.MustCompile(`(={2,})([>ox]?)`),
+	}
+)
+
+// bracketShape is the bracket syntax of a node shape, such as "([" and "])" for a stadium.
+type bracketShape struct {
+	open  string
+	close string
+	shape NodeShape
+}
+
+// bracketShapes lists the bracket syntaxes with the longest openings first, so that the
+// first matching opening is the right one.
+var bracketShapes = []bracketShape{
+	{"(((", ")))", NodeShapeStopDouble},
+	{"([", "])", NodeShapeTerminal},
+	{"((", "))", NodeShapeStart},
+	{"[[", "]]", NodeShapeSubprocess},
+	{"[(", ")]", NodeShapeDatabase},
+	{"[/", "/]", NodeShapeInputOutput},
+	{"[/", `\]`, NodeShapeManualOperation},
+	{`[\`, `\]`, NodeShapeOutputInput},
+	{`[\`, "/]", NodeShapeManual},
+	{"{{", "}}", NodeShapePrepare},
+	{"[", "]", NodeShapeProcess},
+	{"(", ")", NodeShapeEvent},
+	{"{", "}", NodeShapeDecision},
+	{">", "]", NodeShapeOdd},
+}
+
+// nodeShapes holds the shape names accepted by the @{ shape: ... } syntax.
+var nodeShapes = map[NodeShape]bool{
+	NodeShapeProcess: true, NodeShapeEvent: true, NodeShapeTerminal: true, NodeShapeSubprocess: true,
+	NodeShapeDatabase: true, NodeShapeStart: true, NodeShapeOdd: true, NodeShapeDecision: true,
+	NodeShapePrepare: true, NodeShapeInputOutput: true, NodeShapeOutputInput: true,
+	NodeShapeManualOperation: true, NodeShapeManual: true, NodeShapeStopDouble: true,
+	NodeShapeText: true, NodeShapeCard: true, NodeShapeLinedProcess: true, NodeShapeStartSmall: true,
+	NodeShapeStopFramed: true, NodeShapeForkJoin: true, NodeShapeCollate: true, NodeShapeComment: true,
+	NodeShapeCommentRight: true, NodeShapeCommentBothSides: true, NodeShapeComLink: true,
+	NodeShapeDocument: true, NodeShapeDelay: true, NodeShapeStorage: true, NodeShapeDiskStorage: true,
+	NodeShapeDisplay: true, NodeShapeDividedProcess: true, NodeShapeExtract: true,
+	NodeShapeInternalStorage: true, NodeShapeJunction: true, NodeShapeLinedDocument: true,
+	NodeShapeLoopLimit: true, NodeShapeManualFile: true, NodeShapeManualInput: true,
+	NodeShapeMultiDocument: true, NodeShapeMultiProcess: true, NodeShapePaperTape: true,
+	NodeShapeStoredData: true, NodeShapeSummary: true, NodeShapeTaggedDocument: true,
+	NodeShapeTaggedProcess: true,
+}
+
+// flowchartDirections holds the directions of flowcharts and subgraphs.
+var flowchartDirections = map[string]bool{
+	string(FlowchartDirectionTopToBottom): true,
+	string(FlowchartDirectionTopDown):     true,
+	string(FlowchartDirectionBottomUp):    true,
+	string(FlowchartDirectionRightLeft):   true,
+	string(FlowchartDirectionLeftRight):   true,
+}
+
+// Parse returns the flowchart described by Mermaid source, such as the output of String.
+// Nodes are created by their first mention and take their ID as text until a shape gives
+// them one. Errors are *basediagram.SyntaxError values: invalid syntax wraps
+// basediagram.ErrSyntax, statements the model cannot represent, such as click, default
+// link styles or nodes declared alone inside a subgraph, wrap basediagram.ErrUnsupported,
+// and unknown classes and duplicate subgraphs wrap the document errors. Unsupported statements are
+// reported together, see basediagram.Source.Read.
+func Parse(source string) (*Flowchart, error) {
+	parsed, err := basediagram.ParseSource(source, configKey)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &flowchartParser{
+		flowchart: NewFlowchart(),
+		nodes:     make(map[string]*Node),
+		mentions:  make(map[string]basediagram.Statement),
+		classes:   make(map[string]*Class),
+		subgraphs: make(map[string]basediagram.Statement),
+		named:     make(map[string]*Subgraph),
+		generate:  utils.NewIDGenerator(),
+	}
+
+	if err = p.header(parsed.Header); err != nil {
+		return nil, err
+	}
+
+	if err = parsed.Read(p.statement); err != nil {
+		return nil, err
+	}
+
+	if err = p.finish(); err != nil {
+		return nil, err
+	}
+
+	f := p.flowchart
+	f.DecodeSource(parsed)
+	if f.Config.ConfigurationProperties, f.Config.properties, err = parsed.Config.Decode(); err != nil {
+		return nil, basediagram.AtLine(1, err)
+	}
+
+	return f, nil
+}
+
+// classReference is a class applied to a node or subgraph before the end of the source,
+// where classes defined after their use are known.
+type classReference struct {
+	statement basediagram.Statement
+	node      *Node
+	subgraph  *Subgraph
+	name      string
+}
+
+// linkStyleReference is a style applied to a link by its index, which may refer to a link
+// declared later in the source.
+type linkStyleReference struct {
+	statement basediagram.Statement
+	index     int
+	style     *NodeStyle
+}
+
+// flowchartParser builds a flowchart statement by statement.
+type flowchartParser struct {
+	flowchart  *Flowchart
+	nodes      map[string]*Node
+	mentions   map[string]basediagram.Statement
+	classes    map[string]*Class
+	references []classReference
+	links      []*Link
+	linkStyles []linkStyleReference
+	subgraphs  map[string]basediagram.Statement
+	named      map[string]*Subgraph
+	open       []*Subgraph
+	openLines  []int
+	untitled   []*Subgraph
+	generate   *utils.DefaultIDGenerator
+}
+
+// header reads the flowchart declaration and its direction.
+func (p *flowchartParser) header(statement basediagram.Statement) error {
+	fields := strings.Fields(statement.Text)
+	if fields[0] != keywordFlowchart && fields[0] != keywordGraph {
+		return basediagram.Syntax(statement.Line, "expected %s or %s, found %q", keywordFlowchart, keywordGraph, fields[0])
+	}
+
+	switch {
+	case len(fields) == 1:
+	case len(fields) == 2 && flowchartDirections[fields[1]]:
+		p.flowchart.Direction = FlowchartDirection(fields[1])
+	default:
+		return basediagram.Syntax(statement.Line, "invalid direction %q", strings.Join(fields[1:], " "))
+	}
+
+	return nil
+}
+
+// statement reads a statement of the flowchart body.
+func (p *flowchartParser) statement(statement basediagram.Statement) error {
+	statement.Text = strings.TrimSpace(strings.TrimRight(statement.Text, ";"))
+	text := statement.Text
+
+	if text == keywordEnd {
+		if len(p.open) == 0 {
+			return basediagram.Syntax(statement.Line, "end without subgraph")
+		}
+		p.open, p.openLines = p.open[:len(p.open)-1], p.openLines[:len(p.openLines)-1]
+		return nil
+	}
+	if rest, ok := basediagram.CutKeyword(text, keywordSubgraph); ok {
+		return p.subgraph(statement, rest)
+	}
+	if rest, ok := basediagram.CutKeyword(text, keywordDirection); ok {
+		if len(p.open) == 0 {
+			return basediagram.Unsupported(statement)
+		}
+		if !flowchartDirections[rest] {
+			return basediagram.Syntax(statement.Line, "invalid direction %q", rest)
+		}
+		if rest == string(FlowchartDirectionTopDown) {
+			rest = string(SubgraphDirectionTopToBottom)
+		}
+		p.open[len(p.open)-1].Direction = SubgraphDirection(rest)
+		return nil
+	}
+	if rest, ok := basediagram.CutKeyword(text, keywordClassDef); ok {
+		return p.classDef(statement, rest)
+	}
+	if rest, ok := basediagram.CutKeyword(text, keywordClass); ok {
+		return p.class(statement, rest)
+	}
+	if rest, ok := basediagram.CutKeyword(text, keywordStyle); ok {
+		return p.style(statement, rest)
+	}
+	if rest, ok := basediagram.CutKeyword(text, keywordLinkStyle); ok {
+		return p.linkStyle(statement, rest)
+	}
+	if basediagram.HasKeyword(text, unsupportedKeywords...) {
+		return basediagram.Unsupported(statement)
+	}
+
+	return p.chain(statement)
+}
+
+// subgraph opens a subgraph declared as "id [title]", "id" or "title".
+func (p *flowchartParser) subgraph(statement basediagram.Statement, rest string) error {
+	var id, title string
+
+	switch open := strings.Index(rest, "["); {
+	case rest == "":
+		return basediagram.Syntax(statement.Line, "subgraph without ID")
+	case open > 0 && strings.HasSuffix(rest, "]"):
+		id = strings.TrimSpace(rest[:open])
+		title = basediagram.Unquote(strings.TrimSpace(rest[open+1 : len(rest)-1]))
+		if strings.ContainsAny(id, " \t") {
+			return basediagram.Syntax(statement.Line, "invalid subgraph ID %q", id)
+		}
+	case strings.HasPrefix(rest, quote) || strings.ContainsAny(rest, " \t"):
+		title = basediagram.Unquote(rest)
+	default:
+		id, title = rest, rest
+	}
+
+	subgraph := NewSubgraph(id, title)
+	if id == "" {
+		p.untitled = append(p.untitled, subgraph)
+	} else {
+		if _, ok := p.subgraphs[id]; ok {
+			return basediagram.AtLine(statement.Line, basediagram.DuplicateID(documentElementSubgraph, id))
+		}
+		p.subgraphs[id] = statement
+		p.named[id] = subgraph
+		p.generate.Skip(id)
+	}
+
+	if len(p.open) == 0 {
+		p.flowchart.subgraphs = append(p.flowchart.subgraphs, subgraph)
+	} else {
+		parent := p.open[len(p.open)-1]
+		parent.subgraphs = append(parent.subgraphs, subgraph)
+	}
+	p.open, p.openLines = append(p.open, subgraph), append(p.openLines, statement.Line)
+
+	return nil
+}
+
+// classDef defines classes: "classDef name[,name...] style".
+func (p *flowchartParser) classDef(statement basediagram.Statement, rest string) error {
+	names, text, ok := cutStyle(rest)
+	if !ok {
+		return basediagram.Syntax(statement.Line, "expected classDef name style")
+	}
+
+	for _, name := range strings.Split(names, listSeparator) {
+		style, err := parseStyle(statement, text)
+		if err != nil {
+			return err
+		}
+
+		if class := p.classes[name]; class != nil {
+			class.Style = style
+			continue
+		}
+		class := &Class{Name: name, Style: style}
+		p.classes[name] = class
+		p.flowchart.classes = append(p.flowchart.classes, class)
+	}
+
+	return nil
+}
+
+// class applies a class to nodes and subgraphs: "class id[,id...] name".
+func (p *flowchartParser) class(statement basediagram.Statement, rest string) error {
+	fields := strings.Fields(rest)
+	if len(fields) != 2 {
+		return basediagram.Syntax(statement.Line, "expected class ids name")
+	}
+
+	for _, id := range strings.Split(fields[0], listSeparator) {
+		reference := classReference{statement: statement, node: p.nodes[id], subgraph: p.named[id], name: fields[1]}
+		if reference.node == nil && reference.subgraph == nil {
+			return basediagram.AtLine(statement.Line, basediagram.UnknownReference(documentElementNode, id))
+		}
+		p.references = append(p.references, reference)
+	}
+
+	return nil
+}
+
+// style sets the style of a node: "style id style".
+func (p *flowchartParser) style(statement basediagram.Statement, rest string) error {
+	id, text, ok := cutStyle(rest)
+	if !ok {
+		return basediagram.Syntax(statement.Line, "expected style id style")
+	}
+
+	node := p.nodes[id]
+	if node == nil {
+		if _, ok := p.subgraphs[id]; ok {
+			return basediagram.Unsupported(statement)
+		}
+		return basediagram.AtLine(statement.Line, basediagram.UnknownReference(documentElementNode, id))
+	}
+
+	style, err := parseStyle(statement, text)
+	if err != nil {
+		return err
+	}
+	node.Style = style
+
+	return nil
+}
+
+// linkStyle sets the style of links by their index in the source: "linkStyle i[,i...] style".
+// The default link style and curve interpolation are not supported.
+func (p *flowchartParser) linkStyle(statement basediagram.Statement, rest string) error {
+	indexes, text, ok := cutStyle(rest)
+	if !ok {
+		return basediagram.Syntax(statement.Line, "expected linkStyle indexes style")
+	}
+	if indexes == keywordDefault || strings.HasPrefix(text, "interpolate") {
+		return basediagram.Unsupported(statement)
+	}
+
+	style, err := parseStyle(statement, text)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range strings.Split(indexes, listSeparator) {
+		index, err := strconv.Atoi(field)
+		if err != nil || index < 0 {
+			return basediagram.Syntax(statement.Line, "invalid link index %q", field)
+		}
+		p.linkStyles = append(p.linkStyles, linkStyleReference{statement: statement, index: index, style: style})
+	}
+
+	return nil
+}
+
+// cutStyle splits the target of a style statement from its style, which may contain spaces
+// as in "stroke-dasharray:5 5".
+func cutStyle(rest string) (target string, style string, ok bool) {
+	target, style, _ = strings.Cut(rest, " ")
+	style = strings.TrimSpace(style)
+
+	return target, style, target != "" && style != ""
+}
+
+// parseStyle returns the node style of a comma separated list of style properties.
+func parseStyle(statement basediagram.Statement, text string) (*NodeStyle, error) {
+	style := &NodeStyle{}
+
+	for _, property := range strings.Split(text, listSeparator) {
+		name, value, ok := strings.Cut(property, ":")
+		if !ok || value == "" {
+			return nil, basediagram.Syntax(statement.Line, "invalid style property %q", property)
+		}
+
+		switch name {
+		case stylePropertyColor:
+			style.Color = value
+		case stylePropertyFill:
+			style.Fill = value
+		case stylePropertyStroke:
+			style.Stroke = value
+		case stylePropertyStrokeWidth:
+			width, err := strconv.Atoi(strings.TrimSuffix(value, pixelSuffix))
+			if err != nil {
+				return nil, basediagram.Syntax(statement.Line, "invalid stroke width %q", value)
+			}
+			style.StrokeWidth = width
+		case stylePropertyStrokeDash:
+			style.StrokeDash = value
+		default:
+			return nil, basediagram.Unsupported(statement)
+		}
+	}
+
+	return style, nil
+}
+
+// chain reads a node declaration or a chain of links such as "a & b --> c -.-> d".
+func (p *flowchartParser) chain(statement basediagram.Statement) error {
+	c := &cursor{text: statement.Text, line: statement.Line}
+
+	from, err := p.group(c, statement)
+	if err != nil {
+		return err
+	}
+
+	if c.done() {
+		if len(p.open) > 0 {
+			return basediagram.Unsupported(statement)
+		}
+		return nil
+	}
+
+	for !c.done() {
+		template, err := c.link()
+		if err != nil {
+			return err
+		}
+
+		to, err := p.group(c, statement)
+		if err != nil {
+			return err
+		}
+
+		for _, fromNode := range from {
+			for _, toNode := range to {
+				link := *template
+				link.From, link.To = fromNode, toNode
+				p.addLink(&link)
+			}
+		}
+		from = to
+	}
+
+	return nil
+}
+
+// addLink adds a link to the innermost open subgraph or to the flowchart, and records its
+// index for linkStyle statements.
+func (p *flowchartParser) addLink(link *Link) {
+	p.links = append(p.links, link)
+
+	if len(p.open) == 0 {
+		p.flowchart.links = append(p.flowchart.links, link)
+		return
+	}
+
+	subgraph := p.open[len(p.open)-1]
+	subgraph.links = append(subgraph.links, link)
+}
+
+// group reads nodes separated by "&".
+func (p *flowchartParser) group(c *cursor, statement basediagram.Statement) (nodes []*Node, err error) {
+	for {
+		node, err := p.node(c, statement)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+
+		c.skipSpace()
+		if !strings.HasPrefix(c.rest(), nodeSeparator) {
+			return nodes, nil
+		}
+		c.pos += len(nodeSeparator)
+	}
+}
+
+// node reads a node ID with its optional shape and class.
+func (p *flowchartParser) node(c *cursor, statement basediagram.Statement) (*Node, error) {
+	c.skipSpace()
+	id := c.identifier()
+	if id == "" {
+		return nil, c.errorf("expected node ID")
+	}
+
+	node := p.nodes[id]
+	if node == nil {
+		node = NewNode(id, id)
+		p.nodes[id] = node
+		p.mentions[id] = statement
+		p.flowchart.nodes = append(p.flowchart.nodes, node)
+		p.generate.Skip(id)
+	}
+
+	if strings.HasPrefix(c.rest(), shapeStart) {
+		if err := c.shapeData(node); err != nil {
+			return nil, err
+		}
+	} else if err := c.bracketShape(node); err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(c.rest(), classSeparator) {
+		c.pos += len(classSeparator)
+		name := c.identifier()
+		if name == "" {
+			return nil, c.errorf("expected class name")
+		}
+		p.references = append(p.references, classReference{statement: statement, node: node, name: name})
+	}
+
+	return node, nil
+}
+
+// finish checks what can only be checked once every statement has been read.
+func (p *flowchartParser) finish() error {
+	if len(p.open) > 0 {
+		return basediagram.Syntax(p.openLines[len(p.openLines)-1], "subgraph is not closed")
+	}
+
+	for _, reference := range p.references {
+		class := p.classes[reference.name]
+		if class == nil {
+			return basediagram.AtLine(reference.statement.Line, basediagram.UnknownReference(documentElementClass, reference.name))
+		}
+		if reference.node != nil {
+			reference.node.Class = class
+		} else {
+			reference.subgraph.Class = class
+		}
+	}
+
+	for _, reference := range p.linkStyles {
+		if reference.index >= len(p.links) {
+			return basediagram.Syntax(reference.statement.Line, "link index %d out of range", reference.index)
+		}
+		p.links[reference.index].Style = reference.style.clone()
+	}
+
+	for _, node := range p.flowchart.nodes {
+		if _, ok := p.subgraphs[node.ID]; ok {
+			return basediagram.Unsupported(p.mentions[node.ID])
+		}
+	}
+
+	for _, subgraph := range p.untitled {
+		subgraph.ID = p.generate.NextID()
+	}
+	p.flowchart.SetIDGenerator(p.generate)
+
+	return nil
+}
+
+// cursor is a position in the text of a statement.
+type cursor struct {
+	text string
+	pos  int
+	line int
+}
+
+func (c *cursor) rest() string {
+	return c.text[c.pos:]
+}
+
+func (c *cursor) done() bool {
+	c.skipSpace()
+	return c.pos == len(c.text)
+}
+
+func (c *cursor) skipSpace() {
+	for c.pos < len(c.text) && (c.text[c.pos] == ' ' || c.text[c.pos] == '\t') {
+		c.pos++
+	}
+}
+
+func (c *cursor) errorf(format string, args ...interface{}) error {
+	return basediagram.Syntax(c.line, format+" at column %d", append(args, c.pos+1)...)
+}
+
+// identifier reads a node ID or class name.
+func (c *cursor) identifier() string {
+	start := c.pos
+	for _, r := range c.rest() {
+		if !isIdentifierRune(r) {
+			break
+		}
+		c.pos += len(string(r))
+	}
+
+	return c.text[start:c.pos]
+}
+
+func isIdentifierRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// bracketShape reads the shape and text of a node declared with brackets, such as "(text)".
+func (c *cursor) bracketShape(node *Node) error {
+	rest := c.rest()
+
+	for i, candidate := range bracketShapes {
+		if !strings.HasPrefix(rest, candidate.open) {
+			continue
+		}
+
+		body := rest[len(candidate.open):]
+		end, closing := -1, candidate
+		for _, alternative := range bracketShapes[i:] {
+			if alternative.open != candidate.open {
+				continue
+			}
+			if index := closingIndex(body, alternative.close); index >= 0 && (end < 0 || index < end) {
+				end, closing = index, alternative
+			}
+		}
+		if end < 0 {
+			return c.errorf("unclosed node shape %q", candidate.open)
+		}
+
+		node.Shape = closing.shape
+		node.Text = basediagram.Unquote(strings.TrimSpace(body[:end]))
+		c.pos += len(closing.open) + end + len(closing.close)
+
+		return nil
+	}
+
+	return nil
+}
+
+// closingIndex returns the index of the closing bracket of a node text, skipping a quoted
+// text, or -1.
+func closingIndex(body string, close string) int {
+	skip := 0
+	if strings.HasPrefix(body, quote) {
+		end := strings.Index(body[1:], quote)
+		if end < 0 {
+			return -1
+		}
+		skip = end + 2
+	}
+
+	index := strings.Index(body[skip:], close)
+	if index < 0 {
+		return -1
+	}
+
+	return skip + index
+}
+
+// shapeData reads the shape and label of a node declared with "@{ shape: name, label: "text" }".
+func (c *cursor) shapeData(node *Node) error {
+	body := c.rest()[len(shapeStart):]
+
+	quoted := false
+	end := -1
+	for i := 0; i < len(body) && end < 0; i++ {
+		switch {
+		case body[i] == '\\' && quoted:
+			i++
+		case body[i] == '"':
+			quoted = !quoted
+		case body[i] == '}' && !quoted:
+			end = i
+		}
+	}
+	if end < 0 {
+		return c.errorf("unclosed shape data")
+	}
+
+	for _, member := range splitMembers(body[:end]) {
+		key, value, ok := strings.Cut(member, ":")
+		if !ok {
+			return c.errorf("invalid shape data %q", member)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		switch key {
+		case shapeKeyShape:
+			if !nodeShapes[NodeShape(value)] {
+				return basediagram.Unsupported(basediagram.Statement{Line: c.line, Text: c.text})
+			}
+			node.Shape = NodeShape(value)
+		case shapeKeyLabel:
+			node.Text = strings.ReplaceAll(basediagram.Unquote(value), `\"`, quote)
+		default:
+			return basediagram.Unsupported(basediagram.Statement{Line: c.line, Text: c.text})
+		}
+	}
+
+	c.pos += len(shapeStart) + end + len(shapeEnd)
+
+	return nil
+}
+
+// splitMembers splits shape data at the commas outside quotes.
+func splitMembers(data string) (members []string) {
+	quoted := false
+	start := 0
+	for i := 0; i < len(data); i++ {
+		switch {
+		case data[i] == '\\' && quoted:
+			i++
+		case data[i] == '"':
+			quoted = !quoted
+		case data[i] == ',' && !quoted:
+			members = append(members, data[start:i])
+			start = i + 1
+		}
+	}
+	if strings.TrimSpace(data[start:]) != "" {
+		members = append(members, data[start:])
+	}
+
+	return
+}
+
+// link reads a link and its text, returning a link without nodes.
+func (c *cursor) link() (*Link, error) {
+	c.skipSpace()
+	rest := c.rest()
+
+	if open := linkOpenPattern.FindStringSubmatch(rest); open != nil {
+		start := len(open[1]) + len(open[2])
+		body := rest[start:]
+		close := linkClosePatterns[open[2]].FindStringSubmatchIndex(body)
+		if close != nil && strings.TrimSpace(body[:close[0]]) != "" && arrowEnds(body, close[1]) {
+			line := body[close[2]:close[3]]
+			if open[2] == "-." {
+				line = "-" + line
+			}
+			link := newParsedLink(open[1], line, body[close[4]:close[5]])
+			link.Text = basediagram.Unquote(strings.TrimSpace(body[:close[0]]))
+			c.pos += start + close[1]
+			return link, nil
+		}
+	}
+
+	match := linkPattern.FindStringSubmatchIndex(rest)
+	if match == nil {
+		return nil, c.errorf("expected link")
+	}
+	head, end := rest[match[6]:match[7]], match[1]
+	if !arrowEnds(rest, end) {
+		head, end = "", match[6]
+	}
+	link := newParsedLink(rest[match[2]:match[3]], rest[match[4]:match[5]], head)
+	c.pos += end
+
+	c.skipSpace()
+	if strings.HasPrefix(c.rest(), "|") {
+		close := strings.Index(c.rest()[1:], "|")
+		if close < 0 {
+			return nil, c.errorf("unclosed link text")
+		}
+		link.Text = basediagram.Unquote(strings.TrimSpace(c.rest()[1 : close+1]))
+		c.pos += close + 2
+	}
+
+	return link, nil
+}
+
+// arrowEnds reports whether a link ending at the given index is not followed by a node ID,
+// which tells the "o" and "x" arrow heads from the start of an ID.
+func arrowEnds(text string, end int) bool {
+	if end == 0 || (text[end-1] != 'o' && text[end-1] != 'x') || end == len(text) {
+		return true
+	}
+
+	return !isIdentifierRune(rune(text[end]))
+}
+
+// newParsedLink returns the link of a tail, a line such as "---" or "-..-", and a head.
+func newParsedLink(tail string, line string, head string) *Link {
+	link := &Link{Tail: LinkArrowType(tail), Head: LinkArrowType(head)}
+
+	switch line[0] {
+	case '=':
+		link.Shape, link.Length = LinkShapeThick, len(line)-2
+	case '~':
+		link.Shape, link.Length = LinkShapeInvisible, len(line)-2
+	default:
+		if dots := strings.Count(line, "."); dots > 0 {
+			link.Shape, link.Length = LinkShapeDotted, dots-1
+		} else {
+			link.Shape, link.Length = LinkShapeOpen, len(line)-2
+		}
+	}
+
+	return link
+}
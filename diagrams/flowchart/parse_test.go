@@ -0,0 +1,192 @@
+package flowchart
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/testutils"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "Shapes and links",
+			source: "flowchart LR\n    A[Start] --> B{Is it?}\n    B -- Yes --> C([Done])\n    B -.->|No| D((Retry))\n    D ==> A\n    C & D --- E\n",
+			want:   "flowchart LR\n    A@{ shape: rect, label: \"Start\"}\n    B@{ shape: diam, label: \"Is it?\"}\n    C@{ shape: stadium, label: \"Done\"}\n    D@{ shape: circle, label: \"Retry\"}\n    E@{ shape: rect, label: \"E\"}\n    A --> B\n    B -->|Yes| C\n    B -.->|No| D\n    D ==> A\n    C --- E\n    D --- E\n",
+		},
+		{
+			name:   "Link heads and lengths",
+			source: "flowchart TB\n    A ~~~ B\n    A o--o B\n    A x--x B\n    A ----> B\n",
+			want:   "flowchart TB\n    A@{ shape: rect, label: \"A\"}\n    B@{ shape: rect, label: \"B\"}\n    A ~~~ B\n    A o--o B\n    A x--x B\n    A ----> B\n",
+		},
+		{
+			name:   "Subgraphs",
+			source: "graph TD\n    subgraph one [First]\n        direction LR\n        A --> B\n    end\n    subgraph Two words\n        C --> D\n    end\n    B --> C\n",
+			want:   "flowchart TD\n    A@{ shape: rect, label: \"A\"}\n    B@{ shape: rect, label: \"B\"}\n    C@{ shape: rect, label: \"C\"}\n    D@{ shape: rect, label: \"D\"}\n    subgraph one [First]\n    direction LR\n        A --> B\n    end\n    subgraph 0 [Two words]\n        C --> D\n    end\n    B --> C\n",
+		},
+		{
+			name:   "Classes and styles",
+			source: "flowchart TB\n    A@{ shape: diam, label: \"Ask\" } --> B:::hot\n    classDef hot fill:#f00,stroke:#333,stroke-width:2px\n    style A color:#fff\n",
+			want:   "flowchart TB\n    classDef hot fill:#f00,stroke:#333,stroke-width:2\n    A@{ shape: diam, label: \"Ask\"}\n    style A color:#fff\n    B@{ shape: rect, label: \"B\"}:::hot\n    A --> B\n",
+		},
+		{
+			name:   "Link styles and subgraph classes",
+			source: "flowchart TB\n    A --> B\n    subgraph group\n        B --> C\n    end\n    class group hot\n    linkStyle 0,1 stroke:#f00,stroke-dasharray:5 5\n    classDef hot fill:#f00\n",
+			want:   "flowchart TB\n    classDef hot fill:#f00\n    A@{ shape: rect, label: \"A\"}\n    B@{ shape: rect, label: \"B\"}\n    C@{ shape: rect, label: \"C\"}\n    subgraph group [group]\n        B --> C\n    end\n    class group hot\n    A --> B\n    linkStyle 0 stroke:#f00,stroke-dasharray:5 5\n    linkStyle 1 stroke:#f00,stroke-dasharray:5 5\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := testutils.DiagramBody(t, d.String()); got != tt.want {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+
+			reparsed, err := Parse(d.String())
+			if err != nil {
+				t.Fatalf("Parse() of the rendering error = %v", err)
+			}
+			if reparsed.String() != d.String() {
+				t.Errorf("Parse() of the rendering = %q, want %q", reparsed.String(), d.String())
+			}
+		})
+	}
+}
+
+func TestParse_Frontmatter(t *testing.T) {
+	source := "```mermaid\n---\ntitle: Flow\nconfig:\n    maxEdges: 20\n    flowchart:\n        curve: basis\n---\nflowchart TB\n    A --> B\n```\n"
+
+	d, err := Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if d.Title != "Flow" || !d.IsMarkdownFenceEnabled() || d.Config.MaxEdges() != 20 {
+		t.Errorf("Parse() = %q, %v, %d, want Flow, true, 20", d.Title, d.IsMarkdownFenceEnabled(), d.Config.MaxEdges())
+	}
+	if !strings.Contains(d.String(), "    flowchart:\n        curve: basis\n") {
+		t.Errorf("Parse() = %q, want the curve property", d.String())
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "Other diagram type",
+			source:  "pie\n",
+			wantErr: basediagram.ErrSyntax,
+			wantMsg: "line 1: syntax error: expected flowchart or graph, found \"pie\"",
+		},
+		{
+			name:    "Invalid direction",
+			source:  "flowchart XX\n",
+			wantErr: basediagram.ErrSyntax,
+		},
+		{
+			name:    "End without subgraph",
+			source:  "flowchart TB\n    end\n",
+			wantErr: basediagram.ErrSyntax,
+			wantMsg: "line 2: syntax error: end without subgraph",
+		},
+		{
+			name:    "Unclosed subgraph",
+			source:  "flowchart TB\n    subgraph a\n",
+			wantErr: basediagram.ErrSyntax,
+		},
+		{
+			name:    "Missing link end",
+			source:  "flowchart TB\n    A -->\n",
+			wantErr: basediagram.ErrSyntax,
+			wantMsg: "line 2: syntax error: expected node ID at column 6",
+		},
+		{
+			name:    "Unclosed shape",
+			source:  "flowchart TB\n    A[Start\n",
+			wantErr: basediagram.ErrSyntax,
+		},
+		{
+			name:    "Click",
+			source:  "flowchart TB\n    A\n    click A callback\n",
+			wantErr: basediagram.ErrUnsupported,
+			wantMsg: "line 3: unsupported syntax: click A callback",
+		},
+		{
+			name:    "Default link style",
+			source:  "flowchart TB\n    A --> B\n    linkStyle default stroke:#f00\n",
+			wantErr: basediagram.ErrUnsupported,
+			wantMsg: "line 3: unsupported syntax: linkStyle default stroke:#f00",
+		},
+		{
+			name:    "Link style index out of range",
+			source:  "flowchart TB\n    A --> B\n    linkStyle 1 stroke:#f00\n",
+			wantErr: basediagram.ErrSyntax,
+			wantMsg: "line 3: syntax error: link index 1 out of range",
+		},
+		{
+			name:    "Invalid link style index",
+			source:  "flowchart TB\n    A --> B\n    linkStyle first stroke:#f00\n",
+			wantErr: basediagram.ErrSyntax,
+		},
+		{
+			name:    "Accessible title",
+			source:  "flowchart TB\n    accTitle: Flow\n",
+			wantErr: basediagram.ErrUnsupported,
+		},
+		{
+			name:    "Unknown shape",
+			source:  "flowchart TB\n    A@{ shape: blob }\n",
+			wantErr: basediagram.ErrUnsupported,
+		},
+		{
+			name:    "Unsupported style",
+			source:  "flowchart TB\n    A\n    style A font-size:3px\n",
+			wantErr: basediagram.ErrUnsupported,
+		},
+		{
+			name:    "Top level direction",
+			source:  "flowchart TB\n    direction LR\n",
+			wantErr: basediagram.ErrUnsupported,
+		},
+		{
+			name:    "Duplicate subgraph",
+			source:  "flowchart TB\n    subgraph a\n    end\n    subgraph a\n    end\n",
+			wantErr: basediagram.ErrDuplicateID,
+			wantMsg: "line 4: duplicate identifier: subgraph \"a\"",
+		},
+		{
+			name:    "Unknown class",
+			source:  "flowchart TB\n    A\n    class A missing\n",
+			wantErr: basediagram.ErrUnknownReference,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.source)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			var syntaxErr *basediagram.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Errorf("Parse() error = %T, want *basediagram.SyntaxError", err)
+			}
+			if tt.wantMsg != "" && err.Error() != tt.wantMsg {
+				t.Errorf("Parse() error = %q, want %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package flowchart
+
+import (
+	"fmt"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+const (
+	// SplitStubClass is the class of stub nodes that stand in for nodes of another part.
+	SplitStubClass string = "continued"
+
+	splitStubIDString   string = "%s_part%d"
+	splitStubTextString string = "%s (" + basediagram.SplitStubText + ")"
+)
+
+// splitStubStyle is the style of the stub node class.
+var splitStubStyle = NodeStyle{Fill: "#f4f4f4", Stroke: "#999", StrokeWidth: 1, StrokeDash: "5 5"}
+
+// Split partitions a flowchart exceeding the edge or text size limits into several flowcharts
+// and returns them with an index of the parts. A flowchart within the limits is returned as a
+// single copy. Limits missing from the options are taken from the flowchart configuration.
+//
+// Nodes are grouped according to the strategy: by the top-level subgraph whose links first
+// reference them, by connected component, or by community. Groups are packed into as few
+// parts as possible and only divided further when they exceed the limits on their own.
+// Every link cut by the split is kept in the part of each endpoint, pointing to a stub node
+// labelled "continued in diagram N". Parts keep the classes, styles and subgraphs of their nodes.
+func (f *Flowchart) Split(options basediagram.SplitOptions) ([]*Flowchart, *basediagram.SplitIndex) {
+	maxEdges, maxTextSize := options.Limits(f.Config.ConfigurationProperties)
+	links := f.Links()
+	g := f.Graph()
+
+	if len(links) <= maxEdges && len(f.String()) <= maxTextSize {
+		return f.splitParts(g.Nodes(), [][]*Node{g.Nodes()})
+	}
+
+	overhead := len(f.Extract(nil).String()) + len(fmt.Sprintf(basediagram.SplitTitleString, f.Title, len(links), len(links)))
+	for _, class := range f.classes {
+		overhead += len(class.String())
+	}
+	stubClass := NewClass(SplitStubClass)
+	stubStyle := splitStubStyle
+	stubClass.Style = &stubStyle
+	overhead += len(stubClass.String())
+
+	fits := func(nodes []*Node) bool {
+		in := make(map[*Node]bool, len(nodes))
+		size := overhead
+		for _, node := range nodes {
+			in[node] = true
+			size += len(node.String())
+		}
+
+		edges := 0
+		stubs := make(map[*Node]bool)
+		for _, link := range links {
+			fromIn, toIn := in[link.From], in[link.To]
+			if !fromIn && !toIn {
+				continue
+			}
+
+			edges++
+			size += len(link.String())
+
+			if !fromIn || !toIn {
+				other := link.From
+				if fromIn {
+					other = link.To
+				}
+				size += len(fmt.Sprintf(splitStubIDString, "", len(links)))
+				if !stubs[other] {
+					stubs[other] = true
+					size += len(stubNode(other, len(links)).String()) + len(SplitStubClass)
+				}
+			}
+		}
+
+		return edges <= maxEdges && size <= maxTextSize
+	}
+
+	return f.splitParts(g.Nodes(), g.Pack(f.splitGroups(options.Strategy, g), fits))
+}
+
+// splitGroups groups the nodes of the flowchart according to the strategy.
+func (f *Flowchart) splitGroups(strategy basediagram.SplitStrategy, g *Graph) [][]*Node {
+	switch strategy {
+	case basediagram.SplitByCommunity:
+		return g.Communities()
+	case basediagram.SplitBySubgraph:
+		groups := make([][]*Node, 0)
+		grouped := make(map[*Node]bool)
+
+		for _, subgraph := range f.subgraphs {
+			group := make([]*Node, 0)
+			for _, link := range subgraph.allLinks() {
+				for _, node := range []*Node{link.From, link.To} {
+					if !grouped[node] {
+						grouped[node] = true
+						group = append(group, node)
+					}
+				}
+			}
+			if len(group) > 0 {
+				groups = append(groups, group)
+			}
+		}
+
+		rest := make([]*Node, 0)
+		for _, node := range g.Nodes() {
+			if !grouped[node] {
+				rest = append(rest, node)
+			}
+		}
+
+		return append(groups, g.Subgraph(rest).WeaklyConnectedComponents()...)
+	default:
+		return g.WeaklyConnectedComponents()
+	}
+}
+
+// splitParts builds one flowchart per group of nodes, adding stub nodes for cut links.
+func (f *Flowchart) splitParts(nodes []*Node, groups [][]*Node) ([]*Flowchart, *basediagram.SplitIndex) {
+	partOf := make(map[*Node]int, len(nodes))
+	for number, group := range groups {
+		for _, node := range group {
+			partOf[node] = number + 1
+		}
+	}
+
+	parts := make([]*Flowchart, 0, len(groups))
+	index := &basediagram.SplitIndex{}
+
+	for number, group := range groups {
+		part, c := f.extract(group)
+		if len(groups) > 1 && f.Title != "" {
+			part.Title = fmt.Sprintf(basediagram.SplitTitleString, f.Title, number+1, len(groups))
+		}
+
+		entry := basediagram.SplitPart{Number: number + 1}
+		for _, node := range group {
+			entry.Elements = append(entry.Elements, node.ID)
+		}
+
+		continues := make(map[int]bool)
+		stubs := make(map[*Node]*Node)
+		var stubClass *Class
+
+		stubFor := func(node *Node) *Node {
+			if stub, ok := stubs[node]; ok {
+				return stub
+			}
+
+			if stubClass == nil {
+				stubClass = part.AddClass(utils.UniqueName(SplitStubClass, func(name string) bool { return part.FindClass(name) != nil }))
+				style := splitStubStyle
+				stubClass.Style = &style
+			}
+
+			stub := stubNode(node, partOf[node])
+			stub.ID = utils.UniqueName(stub.ID, func(id string) bool { return part.FindNode(id) != nil })
+			stub.SetClass(stubClass)
+			part.AddNode(stub)
+			stubs[node] = stub
+
+			if !continues[partOf[node]] {
+				continues[partOf[node]] = true
+				entry.Continues = append(entry.Continues, partOf[node])
+			}
+
+			return stub
+		}
+
+		for _, link := range f.Links() {
+			fromIn, toIn := partOf[link.From] == number+1, partOf[link.To] == number+1
+			if fromIn == toIn {
+				continue
+			}
+
+			cut := *link
+			if fromIn {
+				cut.From = c.node(link.From)
+				cut.To = stubFor(link.To)
+			} else {
+				cut.From = stubFor(link.From)
+				cut.To = c.node(link.To)
+			}
+			part.AddLink(&cut)
+		}
+
+		entry.Edges = len(part.Links())
+		entry.TextSize = len(part.String())
+		index.Parts = append(index.Parts, entry)
+		parts = append(parts, part)
+	}
+
+	return parts, index
+}
+
+// stubNode returns a node standing in for node, which is placed in the given part.
+func stubNode(node *Node, part int) *Node {
+	return NewNode(fmt.Sprintf(splitStubIDString, node.ID, part), fmt.Sprintf(splitStubTextString, node.Text, part)).
+		SetShape(NodeShapeOdd)
+}
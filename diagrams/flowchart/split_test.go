@@ -0,0 +1,172 @@
+package flowchart
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// newSplitTestFlowchart returns two triangles, each declared in its own subgraph,
+// connected by a single link, plus an isolated pair.
+func newSplitTestFlowchart() *Flowchart {
+	f := NewFlowchart()
+	f.Title = "Big"
+	hot := f.AddClass("hot")
+
+	nodes := make(map[string]*Node)
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "x", "y"} {
+		nodes[id] = NewNode(id, strings.ToUpper(id))
+		f.AddNode(nodes[id])
+	}
+	nodes["a"].SetClass(hot)
+
+	left := f.AddSubgraph("Left")
+	left.AddLink(nodes["a"], nodes["b"])
+	left.AddLink(nodes["b"], nodes["c"])
+	left.AddLink(nodes["c"], nodes["a"])
+
+	right := f.AddSubgraph("Right")
+	right.AddLink(nodes["d"], nodes["e"])
+	right.AddLink(nodes["e"], nodes["f"])
+	right.AddLink(nodes["f"], nodes["d"])
+
+	f.NewLink(nodes["c"], nodes["d"]).SetText("next")
+	f.NewLink(nodes["x"], nodes["y"])
+
+	return f
+}
+
+func TestFlowchart_SplitWithinLimits(t *testing.T) {
+	f := newSplitTestFlowchart()
+
+	parts, index := f.Split(basediagram.SplitOptions{})
+
+	if len(parts) != 1 || parts[0].String() != f.String() {
+		t.Fatalf("Split() within limits should return a single copy, got %d parts", len(parts))
+	}
+
+	if len(index.Parts) != 1 || index.Parts[0].Edges != 8 || index.PartOf("x") != 1 {
+		t.Errorf("Split() index = %+v", index.Parts)
+	}
+}
+
+func TestFlowchart_Split(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   basediagram.SplitOptions
+		want      [][]string
+		continues [][]int
+	}{
+		{
+			name:      "By subgraph",
+			options:   basediagram.SplitOptions{Strategy: basediagram.SplitBySubgraph, MaxEdges: 5},
+			want:      [][]string{{"a", "b", "c"}, {"d", "e", "f", "x", "y"}},
+			continues: [][]int{{2}, {1}},
+		},
+		{
+			name:      "By component",
+			options:   basediagram.SplitOptions{Strategy: basediagram.SplitByComponent, MaxEdges: 7},
+			want:      [][]string{{"a", "b", "c", "d", "e", "f"}, {"x", "y"}},
+			continues: [][]int{nil, nil},
+		},
+		{
+			name:      "By community",
+			options:   basediagram.SplitOptions{Strategy: basediagram.SplitByCommunity, MaxEdges: 4},
+			want:      [][]string{{"a", "b", "c"}, {"d", "e", "f"}, {"x", "y"}},
+			continues: [][]int{{2}, {1}, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSplitTestFlowchart()
+			original := f.String()
+
+			parts, index := f.Split(tt.options)
+
+			got := [][]string{}
+			gotContinues := [][]int{}
+			for _, part := range index.Parts {
+				got = append(got, part.Elements)
+				gotContinues = append(gotContinues, part.Continues)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split() elements = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotContinues, tt.continues) {
+				t.Errorf("Split() continues = %v, want %v", gotContinues, tt.continues)
+			}
+
+			for i, part := range parts {
+				if len(part.Links()) > tt.options.MaxEdges {
+					t.Errorf("part %d has %d edges, limit %d", i+1, len(part.Links()), tt.options.MaxEdges)
+				}
+				if want := fmt.Sprintf("title: Big (%d/%d)", i+1, len(parts)); !strings.Contains(part.String(), want) {
+					t.Errorf("part %d missing title %q", i+1, want)
+				}
+			}
+
+			if f.String() != original {
+				t.Error("Split() modified the original flowchart")
+			}
+		})
+	}
+}
+
+func TestFlowchart_SplitStubs(t *testing.T) {
+	f := newSplitTestFlowchart()
+
+	parts, _ := f.Split(basediagram.SplitOptions{Strategy: basediagram.SplitBySubgraph, MaxEdges: 5})
+
+	first := parts[0].String()
+	wants := []string{
+		"classDef hot",
+		"classDef continued",
+		`d_part2@{ shape: odd, label: "D (continued in diagram 2)"}:::continued`,
+		"c -->|next| d_part2",
+		"subgraph 0 [Left]",
+	}
+	for _, want := range wants {
+		if !strings.Contains(first, want) {
+			t.Errorf("part 1 missing %q in:\n%s", want, first)
+		}
+	}
+
+	second := parts[1].String()
+	if !strings.Contains(second, `c_part1@{ shape: odd, label: "C (continued in diagram 1)"}`) ||
+		!strings.Contains(second, "c_part1 -->|next| d") {
+		t.Errorf("part 2 should start from a stub:\n%s", second)
+	}
+
+	if strings.Contains(second, "classDef hot") {
+		t.Error("part 2 should not define unused classes")
+	}
+}
+
+func TestFlowchart_SplitTextSize(t *testing.T) {
+	f := NewFlowchart()
+	var previous *Node
+	for i := 0; i < 40; i++ {
+		node := f.NewNode(strings.Repeat("x", 50))
+		if previous != nil {
+			f.NewLink(previous, node)
+		}
+		previous = node
+	}
+
+	maxTextSize := 1000
+	parts, index := f.Split(basediagram.SplitOptions{MaxTextSize: maxTextSize})
+
+	if len(parts) < 2 {
+		t.Fatalf("Split() parts = %d, want several", len(parts))
+	}
+
+	for _, part := range index.Parts {
+		if part.TextSize > maxTextSize {
+			t.Errorf("part %d text size %d exceeds %d", part.Number, part.TextSize, maxTextSize)
+		}
+	}
+}
@@ -15,6 +15,7 @@ const (
 	BaseSubgraphEndString       string = basediagram.Indentation + "end\n"
 	BaseSubgraphLinkString      string = basediagram.Indentation + "%s"
 	BaseSubgraphSubgraphString  string = basediagram.Indentation + "%s"
+	BaseSubgraphClassString     string = basediagram.Indentation + "class %s %s\n"
 )
 
 // List of possible Subgraph directions.
@@ -35,6 +36,7 @@ type Subgraph struct {
 	ID          string
 	Title       string
 	Direction   SubgraphDirection
+	Class       *Class
 	subgraphs   []*Subgraph
 	links       []*Link
 	idGenerator utils.IDGenerator
@@ -52,6 +54,12 @@ func NewSubgraph(id string, title string) (newSubgraph *Subgraph) {
 	return
 }
 
+// SetClass sets the subgraph class and returns the subgraph for chaining
+func (s *Subgraph) SetClass(class *Class) *Subgraph {
+	s.Class = class
+	return s
+}
+
 // AddSubgraph adds a new Subgraph to the current Subgraph and returns the created subgraph.
 func (s *Subgraph) AddSubgraph(title string) (newSubgraph *Subgraph) {
 	if s.idGenerator == nil {
@@ -100,5 +108,112 @@ func (s *Subgraph) String(curIndentation string) string {
 
 	sb.WriteString(fmt.Sprintf(string(curIndentation), BaseSubgraphEndString))
 
+	if s.Class != nil {
+		sb.WriteString(fmt.Sprintf(string(curIndentation), fmt.Sprintf(string(BaseSubgraphClassString), s.ID, s.Class.Name)))
+	}
+
 	return sb.String()
 }
+
+// Subgraphs returns the direct child subgraphs of the Subgraph.
+func (s *Subgraph) Subgraphs() []*Subgraph {
+	return utils.CopySlice(s.subgraphs)
+}
+
+// Links returns the links declared directly in the Subgraph.
+func (s *Subgraph) Links() []*Link {
+	return utils.CopySlice(s.links)
+}
+
+// FindSubgraph returns the Subgraph itself or a nested subgraph with the given ID, or nil if there is none.
+func (s *Subgraph) FindSubgraph(id string) *Subgraph {
+	if s.ID == id {
+		return s
+	}
+
+	for _, subgraph := range s.subgraphs {
+		if found := subgraph.FindSubgraph(id); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// RemoveLink removes a link declared directly in the Subgraph.
+// It returns false if the link is not part of the Subgraph.
+func (s *Subgraph) RemoveLink(link *Link) (found bool) {
+	s.links, found = utils.RemoveItem(s.links, link)
+	return
+}
+
+// allLinks returns the links of the Subgraph and all nested subgraphs.
+func (s *Subgraph) allLinks() []*Link {
+	links := utils.CopySlice(s.links)
+
+	for _, subgraph := range s.subgraphs {
+		links = append(links, subgraph.allLinks()...)
+	}
+
+	return links
+}
+
+// renderedLinks returns the links of the Subgraph and all nested subgraphs in the order
+// String writes them, which is the order linkStyle statements number them in.
+func (s *Subgraph) renderedLinks() []*Link {
+	var links []*Link
+
+	for _, subgraph := range s.subgraphs {
+		links = append(links, subgraph.renderedLinks()...)
+	}
+
+	return append(links, s.links...)
+}
+
+// removeLinkRecursive removes a link from the Subgraph or any nested subgraph.
+func (s *Subgraph) removeLinkRecursive(link *Link) bool {
+	if s.RemoveLink(link) {
+		return true
+	}
+
+	for _, subgraph := range s.subgraphs {
+		if subgraph.removeLinkRecursive(link) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removeSubgraphRecursive removes a subgraph nested anywhere below the Subgraph.
+func (s *Subgraph) removeSubgraphRecursive(target *Subgraph) bool {
+	var found bool
+
+	if s.subgraphs, found = utils.RemoveItem(s.subgraphs, target); found {
+		return true
+	}
+
+	for _, subgraph := range s.subgraphs {
+		if subgraph.removeSubgraphRecursive(target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// walk calls fn for the Subgraph and every nested subgraph.
+func (s *Subgraph) walk(fn func(*Subgraph)) {
+	fn(s)
+
+	for _, subgraph := range s.subgraphs {
+		subgraph.walk(fn)
+	}
+}
+
+// setIDGenerator sets the ID generator of the Subgraph and every nested subgraph.
+func (s *Subgraph) setIDGenerator(idGenerator utils.IDGenerator) {
+	s.walk(func(subgraph *Subgraph) {
+		subgraph.idGenerator = idGenerator
+	})
+}
@@ -174,6 +174,14 @@ func TestSubgraph_String(t *testing.T) {
 				"end",
 			},
 		},
+		{
+			name:        "Subgraph with class",
+			subgraph:    NewSubgraph("1", "Test").SetClass(NewClass("hot")),
+			indentation: "%s",
+			contains: []string{
+				"end\n    class 1 hot\n",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -193,3 +201,56 @@ func TestSubgraph_String(t *testing.T) {
 		})
 	}
 }
+
+func TestSubgraph_FindSubgraph(t *testing.T) {
+	root := NewSubgraph("root", "Root")
+	child := root.AddSubgraph("Child")
+	grandchild := child.AddSubgraph("Grandchild")
+
+	tests := []struct {
+		name string
+		id   string
+		want *Subgraph
+	}{
+		{
+			name: "Find itself",
+			id:   "root",
+			want: root,
+		},
+		{
+			name: "Find nested subgraph",
+			id:   grandchild.ID,
+			want: grandchild,
+		},
+		{
+			name: "Missing subgraph",
+			id:   "missing",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := root.FindSubgraph(tt.id); got != tt.want {
+				t.Errorf("FindSubgraph() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubgraph_RemoveLink(t *testing.T) {
+	subgraph := NewSubgraph("0", "Group")
+	link := subgraph.AddLink(NewNode("1", "A"), NewNode("2", "B"))
+
+	if !subgraph.RemoveLink(link) {
+		t.Error("RemoveLink() = false, want true")
+	}
+
+	if len(subgraph.Links()) != 0 {
+		t.Errorf("RemoveLink() left %d links, want 0", len(subgraph.Links()))
+	}
+
+	if subgraph.RemoveLink(link) {
+		t.Error("RemoveLink() on removed link = true, want false")
+	}
+}
@@ -0,0 +1,476 @@
+// Package godeps draws the import graph of the packages of a Go module as a flowchart.
+//
+// The packages are read from the output of go list -deps -json, either by running it with
+// List or by decoding saved output with Decode. Generate draws the packages of the main
+// module grouped into subgraphs by directory, and the standard library and the other
+// modules either package by package, collapsed into one node each, or not at all. Import
+// cycles are drawn with thick links between nodes of the cycle class.
+package godeps
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/graph"
+)
+
+// ErrList is returned when go list fails.
+var ErrList = errors.New("go list failed")
+
+// Mode selects how packages outside the main module are drawn.
+type Mode int
+
+// Drawing modes of the packages outside the main module.
+const (
+	// Show draws every package.
+	Show Mode = iota
+	// Collapse draws a single node for the standard library, and one node per module.
+	Collapse
+	// Hide leaves the packages out of the graph.
+	Hide
+)
+
+// Names of the nodes of collapsed packages and of the class of import cycles.
+const (
+	StandardNode string = "std"
+	CycleClass   string = "cycle"
+)
+
+const (
+	goCommand       string = "go"
+	listErrorString string = "%w: %v: %s"
+	cycleFill       string = "#fdd"
+	cycleStroke     string = "#c00"
+	cycleWidth      int    = 2
+	rootDir         string = "."
+)
+
+// listArgs are the arguments of go list before the patterns.
+var listArgs = []string{"list", "-deps", "-json"}
+
+// Package is a package in the output of go list -json. Only the fields used to draw the
+// graph are decoded.
+type Package struct {
+	ImportPath string
+	Name       string
+	Dir        string
+	Standard   bool
+	DepOnly    bool
+	Module     *Module
+	Imports    []string
+}
+
+// Module is the module of a package in the output of go list -json.
+type Module struct {
+	Path string
+	Main bool
+}
+
+// Options controls the graph drawn by Generate.
+type Options struct {
+	// Standard selects how the packages of the standard library are drawn. Their own
+	// imports are never drawn.
+	Standard Mode
+	// External selects how the packages of other modules are drawn.
+	External Mode
+	// Prefixes limits the graph to the packages of the main module and of other modules
+	// whose import path starts with one of the prefixes. All are drawn when it is empty.
+	Prefixes []string
+	// MaxDepth limits the graph to the packages within that many imports of the packages
+	// named on the go list command line. All are drawn when it is zero.
+	MaxDepth int
+	// Direction is the direction of the flowchart, its default when empty.
+	Direction flowchart.FlowchartDirection
+}
+
+// List runs go list -deps -json on the patterns in a directory and returns the packages.
+func List(dir string, patterns ...string) ([]*Package, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(goCommand, append(append([]string{}, listArgs...), patterns...)...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf(listErrorString, ErrList, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return Decode(&stdout)
+}
+
+// Decode reads the stream of JSON objects written by go list -json.
+func Decode(r io.Reader) ([]*Package, error) {
+	var packages []*Package
+
+	decoder := json.NewDecoder(r)
+	for {
+		var pkg Package
+		err := decoder.Decode(&pkg)
+		if errors.Is(err, io.EOF) {
+			return packages, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, &pkg)
+	}
+}
+
+// generator holds the state of a graph being drawn.
+type generator struct {
+	options  Options
+	packages map[string]*Package
+	// keys maps the import path of every drawn package to its node key: the import path, the
+	// module path of a collapsed module, or StandardNode.
+	keys  map[string]string
+	graph *graph.Graph[string]
+	nodes map[string]*flowchart.Node
+}
+
+// Generate draws the import graph of packages.
+func Generate(packages []*Package, options Options) *flowchart.Flowchart {
+	g := &generator{
+		options:  options,
+		packages: make(map[string]*Package, len(packages)),
+		keys:     make(map[string]string),
+		graph:    graph.New[string](),
+		nodes:    make(map[string]*flowchart.Node),
+	}
+	for _, pkg := range packages {
+		g.packages[pkg.ImportPath] = pkg
+	}
+
+	g.selectPackages(packages)
+	g.addEdges()
+
+	return g.draw()
+}
+
+// isMain reports whether a package belongs to the main module. Without module information,
+// the packages named on the command line are the main ones.
+func isMain(pkg *Package) bool {
+	if pkg.Standard {
+		return false
+	}
+	if pkg.Module == nil {
+		return !pkg.DepOnly
+	}
+
+	return pkg.Module.Main
+}
+
+// key returns the node key of a package, or "" when the package is not drawn.
+func (g *generator) key(pkg *Package) string {
+	switch {
+	case pkg.Standard:
+		switch g.options.Standard {
+		case Collapse:
+			return StandardNode
+		case Hide:
+			return ""
+		}
+		return pkg.ImportPath
+	case isMain(pkg):
+		if !g.matches(pkg.ImportPath) {
+			return ""
+		}
+		return pkg.ImportPath
+	}
+
+	switch g.options.External {
+	case Collapse:
+		if pkg.Module != nil {
+			return pkg.Module.Path
+		}
+		return pkg.ImportPath
+	case Hide:
+		return ""
+	}
+	if !g.matches(pkg.ImportPath) {
+		return ""
+	}
+
+	return pkg.ImportPath
+}
+
+// matches reports whether an import path starts with one of the prefixes of the options.
+func (g *generator) matches(importPath string) bool {
+	if len(g.options.Prefixes) == 0 {
+		return true
+	}
+
+	for _, prefix := range g.options.Prefixes {
+		if strings.HasPrefix(importPath, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// expanded reports whether the imports of a package are drawn: those of the packages drawn
+// one by one outside the standard library.
+func (g *generator) expanded(pkg *Package) bool {
+	return !pkg.Standard && g.keys[pkg.ImportPath] == pkg.ImportPath
+}
+
+// selectPackages walks the imports breadth first from the packages of the main module named
+// on the command line, and records the key of every drawn package within the maximum
+// depth.
+func (g *generator) selectPackages(packages []*Package) {
+	depths := make(map[string]int)
+	var queue []*Package
+
+	for _, pkg := range packages {
+		if !pkg.DepOnly && isMain(pkg) && g.key(pkg) != "" {
+			depths[pkg.ImportPath] = 0
+			queue = append(queue, pkg)
+		}
+	}
+
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+
+		g.keys[pkg.ImportPath] = g.key(pkg)
+		g.graph.AddNode(g.keys[pkg.ImportPath])
+		if !g.expanded(pkg) || (g.options.MaxDepth > 0 && depths[pkg.ImportPath] >= g.options.MaxDepth) {
+			continue
+		}
+
+		for _, importPath := range pkg.Imports {
+			imported, ok := g.packages[importPath]
+			if _, seen := depths[importPath]; !ok || seen || g.key(imported) == "" {
+				continue
+			}
+			depths[importPath] = depths[pkg.ImportPath] + 1
+			queue = append(queue, imported)
+		}
+	}
+}
+
+// addEdges adds an edge for every import between drawn packages.
+func (g *generator) addEdges() {
+	seen := make(map[[2]string]bool)
+
+	for _, from := range g.sortedPaths() {
+		pkg := g.packages[from]
+		if !g.expanded(pkg) {
+			continue
+		}
+
+		for _, importPath := range pkg.Imports {
+			to, ok := g.keys[importPath]
+			edge := [2]string{from, to}
+			if !ok || to == from || seen[edge] {
+				continue
+			}
+			seen[edge] = true
+			g.graph.AddEdge(from, to)
+		}
+	}
+}
+
+// sortedPaths returns the import paths of the drawn packages in alphabetical order.
+func (g *generator) sortedPaths() []string {
+	paths := make([]string, 0, len(g.keys))
+	for importPath := range g.keys {
+		paths = append(paths, importPath)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// draw builds the flowchart of the graph.
+func (g *generator) draw() *flowchart.Flowchart {
+	f := flowchart.NewFlowchart()
+	if g.options.Direction != "" {
+		f.SetDirection(g.options.Direction)
+	}
+
+	cycles := make(map[string]int)
+	for i, component := range g.graph.StronglyConnectedComponents() {
+		if len(component) > 1 {
+			for _, key := range component {
+				cycles[key] = i + 1
+			}
+		}
+	}
+
+	var cycleClass *flowchart.Class
+	if len(cycles) > 0 {
+		cycleClass = f.AddClass(CycleClass)
+		cycleClass.Style.Fill = cycleFill
+		cycleClass.Style.Stroke = cycleStroke
+		cycleClass.Style.StrokeWidth = cycleWidth
+	}
+
+	keys := g.graph.Nodes()
+	sort.SliceStable(keys, func(i, j int) bool {
+		return g.rank(keys[i]) < g.rank(keys[j]) || (g.rank(keys[i]) == g.rank(keys[j]) && keys[i] < keys[j])
+	})
+
+	for _, key := range keys {
+		node := f.NewNode(g.label(key))
+		if g.rank(key) > 0 {
+			node.SetShape(flowchart.NodeShapeTerminal)
+		}
+		if cycles[key] > 0 {
+			node.SetClass(cycleClass)
+		}
+		g.nodes[key] = node
+	}
+
+	groups := g.group(f, keys)
+
+	for _, from := range keys {
+		for _, to := range g.graph.Successors(from) {
+			var link *flowchart.Link
+			if group := groups[from]; group != nil && group == groups[to] {
+				link = group.subgraph.AddLink(g.nodes[from], g.nodes[to])
+				group.covered[from], group.covered[to] = true, true
+			} else {
+				link = f.NewLink(g.nodes[from], g.nodes[to])
+			}
+			if cycles[from] > 0 && cycles[from] == cycles[to] {
+				link.SetShape(flowchart.LinkShapeThick)
+			}
+		}
+	}
+
+	// Packages without a link inside their subgraph are placed in it with invisible links.
+	for _, group := range groups.sorted() {
+		for _, member := range group.members[1:] {
+			if !group.covered[member] {
+				group.subgraph.AddLink(g.nodes[group.members[0]], g.nodes[member]).
+					SetShape(flowchart.LinkShapeInvisible).
+					SetHead(flowchart.LinkArrowTypeNone).
+					SetLength(1)
+			}
+		}
+	}
+
+	return f
+}
+
+// rank orders the nodes: packages of the main module, then of the standard library, then
+// of other modules.
+func (g *generator) rank(key string) int {
+	pkg, ok := g.packages[key]
+	switch {
+	case key == StandardNode || (ok && pkg.Standard):
+		return 1
+	case ok && isMain(pkg):
+		return 0
+	}
+
+	return 2
+}
+
+// label returns the text of the node of a key: the path of packages of the main module
+// relative to the module, and the key otherwise.
+func (g *generator) label(key string) string {
+	pkg, ok := g.packages[key]
+	if !ok || !isMain(pkg) || pkg.Module == nil {
+		return key
+	}
+
+	if relative := strings.TrimPrefix(key, pkg.Module.Path+"/"); relative != key {
+		return relative
+	}
+
+	return key
+}
+
+// dir returns the directory grouping a package of the main module, relative to the module,
+// or "" for packages outside the main module and at its root.
+func (g *generator) dir(key string) string {
+	pkg, ok := g.packages[key]
+	if !ok || !isMain(pkg) || pkg.Module == nil {
+		return ""
+	}
+
+	relative := strings.TrimPrefix(key, pkg.Module.Path+"/")
+	if relative == key {
+		return ""
+	}
+	if dir := path.Dir(relative); dir != rootDir {
+		return dir
+	}
+
+	return ""
+}
+
+// group is the subgraph of the packages of a directory.
+type group struct {
+	dir      string
+	subgraph *flowchart.Subgraph
+	members  []string
+	covered  map[string]bool
+}
+
+// groups maps the packages of the main module to the group of their directory.
+type groups map[string]*group
+
+// sorted returns the distinct groups ordered by directory.
+func (gs groups) sorted() (sorted []*group) {
+	seen := make(map[*group]bool)
+	for _, group := range gs {
+		if !seen[group] {
+			seen[group] = true
+			sorted = append(sorted, group)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].dir < sorted[j].dir })
+
+	return sorted
+}
+
+// group creates a subgraph for every directory holding two or more drawn packages, nested
+// in the subgraph of the closest parent directory that has one.
+func (g *generator) group(f *flowchart.Flowchart, keys []string) groups {
+	members := make(map[string][]string)
+	for _, key := range keys {
+		if dir := g.dir(key); dir != "" {
+			members[dir] = append(members[dir], key)
+		}
+	}
+
+	dirs := make([]string, 0, len(members))
+	for dir, keys := range members {
+		if len(keys) > 1 {
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+
+	subgraphs := make(map[string]*flowchart.Subgraph)
+	result := make(groups)
+	for _, dir := range dirs {
+		var subgraph *flowchart.Subgraph
+		for parent := path.Dir(dir); subgraph == nil; parent = path.Dir(parent) {
+			if parentSubgraph, ok := subgraphs[parent]; ok {
+				subgraph = parentSubgraph.AddSubgraph(dir)
+			} else if parent == rootDir || parent == "/" {
+				subgraph = f.AddSubgraph(dir)
+			}
+		}
+		subgraphs[dir] = subgraph
+
+		group := &group{dir: dir, subgraph: subgraph, members: members[dir], covered: make(map[string]bool)}
+		for _, key := range members[dir] {
+			result[key] = group
+		}
+	}
+
+	return result
+}
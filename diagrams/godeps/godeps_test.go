@@ -0,0 +1,173 @@
+package godeps
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/testutils"
+)
+
+// loadPackages decodes the go list output of the test module.
+func loadPackages(t *testing.T) []*Package {
+	t.Helper()
+
+	file, err := os.Open("testdata/list.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	packages, err := Decode(file)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	return packages
+}
+
+func TestDecode(t *testing.T) {
+	packages := loadPackages(t)
+
+	if len(packages) != 8 {
+		t.Fatalf("Decode() = %d packages, want 8", len(packages))
+	}
+	order := packages[4]
+	if order.ImportPath != "example.com/shop/internal/order" || !order.Module.Main || len(order.Imports) != 2 {
+		t.Errorf("Decode() = %+v, want the order package", order)
+	}
+
+	if _, err := Decode(strings.NewReader(`{"ImportPath": `)); err == nil {
+		t.Error("Decode() of truncated output error = nil, want an error")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  Options
+		want     string
+		contains []string
+	}{
+		{
+			name:    "Collapsed dependencies",
+			options: Options{Standard: Collapse, External: Collapse, Direction: flowchart.FlowchartDirectionLeftRight},
+			want: "flowchart LR\n" +
+				"    classDef cycle fill:#fdd,stroke:#c00,stroke-width:2,stroke-dasharray:0\n" +
+				"    0@{ shape: rect, label: \"example.com/shop\"}\n" +
+				"    1@{ shape: rect, label: \"cmd/shop\"}\n" +
+				"    2@{ shape: rect, label: \"internal/db\"}:::cycle\n" +
+				"    3@{ shape: rect, label: \"internal/order\"}:::cycle\n" +
+				"    4@{ shape: rect, label: \"internal/util\"}\n" +
+				"    5@{ shape: stadium, label: \"std\"}\n" +
+				"    6@{ shape: stadium, label: \"gopkg.in/yaml.v3\"}\n" +
+				"    subgraph 7 [internal]\n" +
+				"        2 ==> 3\n" +
+				"        3 ==> 2\n" +
+				"        2 ~~~ 4\n" +
+				"    end\n" +
+				"    0 --> 4\n" +
+				"    1 --> 5\n" +
+				"    1 --> 3\n" +
+				"    1 --> 0\n" +
+				"    2 --> 5\n" +
+				"    3 --> 6\n" +
+				"    4 --> 5\n" +
+				"    6 --> 5\n",
+		},
+		{
+			name:     "Shown dependencies",
+			contains: []string{`label: "fmt"`, `label: "strings"`, `label: "gopkg.in/yaml.v3"`},
+		},
+		{
+			name:    "Hidden dependencies",
+			options: Options{Standard: Hide, External: Hide},
+			contains: []string{
+				"    1 --> 3\n    1 --> 0\n",
+			},
+		},
+		{
+			name:    "Maximum depth",
+			options: Options{Standard: Collapse, External: Hide, MaxDepth: 1},
+			want: "flowchart TB\n" +
+				"    0@{ shape: rect, label: \"example.com/shop\"}\n" +
+				"    1@{ shape: rect, label: \"cmd/shop\"}\n" +
+				"    2@{ shape: rect, label: \"internal/order\"}\n" +
+				"    3@{ shape: stadium, label: \"std\"}\n" +
+				"    1 --> 3\n" +
+				"    1 --> 2\n" +
+				"    1 --> 0\n",
+		},
+		{
+			name:    "Path prefix",
+			options: Options{Standard: Hide, External: Hide, Prefixes: []string{"example.com/shop/cmd", "example.com/shop/internal/order"}},
+			want: "flowchart TB\n" +
+				"    0@{ shape: rect, label: \"cmd/shop\"}\n" +
+				"    1@{ shape: rect, label: \"internal/order\"}\n" +
+				"    0 --> 1\n",
+		},
+	}
+
+	packages := loadPackages(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := testutils.DiagramBody(t, Generate(packages, tt.options).String())
+
+			if tt.want != "" && got != tt.want {
+				t.Errorf("Generate() = %q, want %q", got, tt.want)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("Generate() missing %q in:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_NestedDirectories(t *testing.T) {
+	module := &Module{Path: "example.com/m", Main: true}
+	packages := []*Package{
+		{ImportPath: "example.com/m/a/x", Module: module},
+		{ImportPath: "example.com/m/a/y", Module: module},
+		{ImportPath: "example.com/m/a/b/x", Module: module, Imports: []string{"example.com/m/a/b/y"}},
+		{ImportPath: "example.com/m/a/b/y", Module: module},
+	}
+
+	got := testutils.DiagramBody(t, Generate(packages, Options{}).String())
+	want := "    subgraph 4 [a]\n" +
+		"        subgraph 5 [a/b]\n" +
+		"            0 --> 1\n" +
+		"        end\n" +
+		"        2 ~~~ 3\n" +
+		"    end\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("Generate() = %q, want it to contain %q", got, want)
+	}
+	if strings.Contains(got, "cycle") {
+		t.Errorf("Generate() = %q, want no cycle class without cycles", got)
+	}
+}
+
+func TestList(t *testing.T) {
+	packages, err := List(".", ".")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	found := false
+	for _, pkg := range packages {
+		if pkg.ImportPath == "github.com/TyphonHill/go-mermaid/diagrams/godeps" {
+			found = !pkg.DepOnly && pkg.Module != nil && pkg.Module.Main
+		}
+	}
+	if !found {
+		t.Errorf("List() = %d packages, want this package as a root of the main module", len(packages))
+	}
+
+	if _, err := List(".", "./missing"); !errors.Is(err, ErrList) {
+		t.Errorf("List() of a missing package error = %v, want %v", err, ErrList)
+	}
+}
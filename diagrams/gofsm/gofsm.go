@@ -0,0 +1,385 @@
+// Package gofsm draws state diagrams of the finite state machines of Go programs, from
+// their transition tables.
+//
+// A machine lists its transitions, each from a state to another on an event, optionally
+// under a guard, and its initial states. Machines may also list their final states and
+// nest states in composite states. Table reads transition tables such as
+// []struct{From, Event, To string} or the fsm.Events of github.com/looplab/fsm, and
+// Definition describes a machine field by field.
+//
+// The initial and final states of the machine are drawn with transitions from and to [*],
+// and those of composite states with [*] inside them. Transitions are labelled with their
+// event and guard. States that cannot be reached from the initial states, and states other
+// than the final ones that cannot be left, are flagged with notes, and reported by Analyze.
+package gofsm
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/state"
+)
+
+// Errors returned for invalid machines.
+var (
+	ErrNoState        = errors.New("transition without state")
+	ErrHierarchyCycle = errors.New("state nested in itself")
+)
+
+const (
+	guardString           string = "%s [%s]"
+	guardOnlyString       string = "[%s]"
+	stateErrorString      string = "%w: %s"
+	transitionErrorString string = "%w: transition %d"
+	nameJoin              string = "_"
+	unreachableNote       string = "unreachable"
+	deadEndNote           string = "dead end"
+	noteSeparator         string = ", "
+)
+
+// unsafeID matches the characters that cannot appear in state IDs.
+var unsafeID = regexp.MustCompile(`[^\w.]+`)
+
+// Transition is a transition of a state machine from a state to another on an event,
+// allowed only when its guard, if any, holds. Completion transitions have no event.
+type Transition struct {
+	From  string
+	Event string
+	To    string
+	Guard string
+}
+
+// Machine is a state machine described by its transition table. Its states are those of its
+// transitions, initial states and final states.
+type Machine interface {
+	// Transitions returns the transitions of the machine, in the order to draw them.
+	Transitions() []Transition
+	// Initial returns the initial state of the machine, and those of its composite states.
+	Initial() []string
+}
+
+// Final is implemented by the machines that declare their final states. Other states
+// without transitions leaving them are dead ends. The states of other machines without
+// transitions leaving them are final.
+type Final interface {
+	Final() []string
+}
+
+// Hierarchical is implemented by the machines whose states are nested in composite states.
+// The transitions leaving a composite state leave all the states nested in it.
+type Hierarchical interface {
+	// Parent returns the composite state a state is nested in, or an empty string for the
+	// states of the machine itself.
+	Parent(state string) string
+}
+
+// Definition is a state machine described field by field. It implements Machine, Final
+// and Hierarchical.
+type Definition struct {
+	Table   []Transition
+	Start   []string
+	End     []string
+	Parents map[string]string
+}
+
+// Transitions returns the transition table.
+func (d *Definition) Transitions() []Transition {
+	return d.Table
+}
+
+// Initial returns the initial states.
+func (d *Definition) Initial() []string {
+	return d.Start
+}
+
+// Final returns the final states.
+func (d *Definition) Final() []string {
+	return d.End
+}
+
+// Parent returns the composite state a state is nested in.
+func (d *Definition) Parent(state string) string {
+	return d.Parents[state]
+}
+
+// Analysis lists the problems of the states of a machine, in the order of their first
+// mention.
+type Analysis struct {
+	// Unreachable lists the states that cannot be reached from the initial states.
+	Unreachable []string
+	// DeadEnds lists the states other than the final ones that cannot be left. Only the
+	// machines declaring their final states have dead ends.
+	DeadEnds []string
+}
+
+// machine is the structure of a machine, with its states in the order of their first
+// mention, parents before the states nested in them.
+type machine struct {
+	transitions []Transition
+	states      []string
+	parents     map[string]string
+	children    map[string][]string
+	initial     map[string]bool
+	final       map[string]bool
+	declared    bool
+	outgoing    map[string][]Transition
+}
+
+// Generate draws the states and transitions of a machine.
+func Generate(m Machine) (*state.Diagram, error) {
+	fsm, err := read(m)
+	if err != nil {
+		return nil, err
+	}
+	analysis := fsm.analyze()
+
+	d := state.NewDiagram()
+	ids := make(map[string]bool)
+	states := make(map[string]*state.State)
+	for _, name := range fsm.states {
+		id := unsafeID.ReplaceAllString(name, nameJoin)
+		for ids[id] {
+			id += nameJoin
+		}
+		ids[id] = true
+
+		parent := fsm.parents[name]
+		stateType := state.StateNormal
+		switch {
+		case len(fsm.children[name]) > 0:
+			stateType = state.StateComposite
+		case parent != "" && fsm.initial[name]:
+			stateType = state.StateStart
+		case parent != "" && fsm.final[name]:
+			stateType = state.StateEnd
+		}
+
+		description := ""
+		if id != name || parent != "" || !fsm.inTransition(name) {
+			description = name
+		}
+
+		if parent == "" {
+			states[name] = d.AddState(id, description, stateType)
+		} else {
+			states[name] = states[parent].AddNestedState(id, description, stateType)
+		}
+	}
+
+	for _, name := range fsm.states {
+		if fsm.parents[name] == "" && fsm.initial[name] {
+			d.AddTransition(nil, states[name], "")
+		}
+	}
+	for _, t := range fsm.transitions {
+		d.AddTransition(states[t.From], states[t.To], label(t))
+	}
+	for _, name := range fsm.states {
+		if fsm.parents[name] == "" && fsm.final[name] && len(fsm.children[name]) == 0 {
+			d.AddTransition(states[name], nil, "")
+		}
+	}
+
+	notes := make(map[string][]string)
+	for _, name := range analysis.Unreachable {
+		notes[name] = append(notes[name], unreachableNote)
+	}
+	for _, name := range analysis.DeadEnds {
+		notes[name] = append(notes[name], deadEndNote)
+	}
+	for _, name := range fsm.states {
+		if len(notes[name]) > 0 {
+			states[name].AddNote(strings.Join(notes[name], noteSeparator), state.NoteRight)
+		}
+	}
+
+	return d, nil
+}
+
+// Analyze reports the unreachable states and dead ends of a machine.
+func Analyze(m Machine) (Analysis, error) {
+	fsm, err := read(m)
+	if err != nil {
+		return Analysis{}, err
+	}
+
+	return fsm.analyze(), nil
+}
+
+// read returns the structure of a machine.
+func read(m Machine) (*machine, error) {
+	fsm := &machine{
+		transitions: m.Transitions(),
+		parents:     make(map[string]string),
+		children:    make(map[string][]string),
+		initial:     make(map[string]bool),
+		final:       make(map[string]bool),
+		outgoing:    make(map[string][]Transition),
+	}
+	hierarchical, _ := m.(Hierarchical)
+	seen := make(map[string]bool)
+
+	var add func(name string, path map[string]bool) error
+	add = func(name string, path map[string]bool) error {
+		if seen[name] {
+			return nil
+		}
+		if path[name] {
+			return fmt.Errorf(stateErrorString, ErrHierarchyCycle, name)
+		}
+		path[name] = true
+
+		if hierarchical != nil {
+			if parent := hierarchical.Parent(name); parent != "" {
+				if err := add(parent, path); err != nil {
+					return err
+				}
+				fsm.parents[name] = parent
+				fsm.children[parent] = append(fsm.children[parent], name)
+			}
+		}
+		seen[name] = true
+		fsm.states = append(fsm.states, name)
+
+		return nil
+	}
+	addAll := func(names []string) error {
+		for _, name := range names {
+			if err := add(name, make(map[string]bool)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := addAll(m.Initial()); err != nil {
+		return nil, err
+	}
+	for _, name := range m.Initial() {
+		fsm.initial[name] = true
+	}
+	for i, t := range fsm.transitions {
+		if t.From == "" || t.To == "" {
+			return nil, fmt.Errorf(transitionErrorString, ErrNoState, i)
+		}
+		if err := addAll([]string{t.From, t.To}); err != nil {
+			return nil, err
+		}
+		fsm.outgoing[t.From] = append(fsm.outgoing[t.From], t)
+	}
+
+	if final, ok := m.(Final); ok && len(final.Final()) > 0 {
+		fsm.declared = true
+		if err := addAll(final.Final()); err != nil {
+			return nil, err
+		}
+		for _, name := range final.Final() {
+			fsm.final[name] = true
+		}
+	} else {
+		for _, name := range fsm.states {
+			fsm.final[name] = len(fsm.children[name]) == 0 && !fsm.leavable(name)
+		}
+	}
+
+	return fsm, nil
+}
+
+// analyze reports the unreachable states and dead ends of the machine. Entering a composite
+// state enters its initial states, or all the states nested in it when none is initial,
+// and the composite states around a state are active while it is. Without initial states,
+// no state is unreachable.
+func (fsm *machine) analyze() (analysis Analysis) {
+	entered := make(map[string]bool)
+	active := make(map[string]bool)
+
+	var enter, activate func(name string)
+	enter = func(name string) {
+		if entered[name] {
+			return
+		}
+		entered[name] = true
+
+		var initial []string
+		for _, child := range fsm.children[name] {
+			if fsm.initial[child] {
+				initial = append(initial, child)
+			}
+		}
+		if len(initial) == 0 {
+			initial = fsm.children[name]
+		}
+		activate(name)
+		for _, child := range initial {
+			enter(child)
+		}
+	}
+	activate = func(name string) {
+		if active[name] {
+			return
+		}
+		active[name] = true
+		for _, t := range fsm.outgoing[name] {
+			enter(t.To)
+		}
+		if parent := fsm.parents[name]; parent != "" {
+			activate(parent)
+		}
+	}
+
+	started := false
+	for _, name := range fsm.states {
+		if fsm.initial[name] && fsm.parents[name] == "" {
+			enter(name)
+			started = true
+		}
+	}
+
+	for _, name := range fsm.states {
+		if started && !active[name] {
+			analysis.Unreachable = append(analysis.Unreachable, name)
+		}
+		if fsm.declared && !fsm.final[name] && len(fsm.children[name]) == 0 && !fsm.leavable(name) {
+			analysis.DeadEnds = append(analysis.DeadEnds, name)
+		}
+	}
+
+	return analysis
+}
+
+// leavable reports whether a transition leaves a state or a composite state it is nested
+// in.
+func (fsm *machine) leavable(name string) bool {
+	for ; name != ""; name = fsm.parents[name] {
+		if len(fsm.outgoing[name]) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inTransition reports whether a transition starts or ends at a state.
+func (fsm *machine) inTransition(name string) bool {
+	for _, t := range fsm.transitions {
+		if t.From == name || t.To == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// label returns the label of a transition: its event followed by its guard in brackets.
+func label(t Transition) string {
+	switch {
+	case t.Guard == "":
+		return t.Event
+	case t.Event == "":
+		return fmt.Sprintf(guardOnlyString, t.Guard)
+	default:
+		return fmt.Sprintf(guardString, t.Event, t.Guard)
+	}
+}
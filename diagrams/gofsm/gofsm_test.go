@@ -0,0 +1,202 @@
+package gofsm
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/testutils"
+)
+
+// order is the machine of an order, with the states of payments nested in Paid.
+func order() *Definition {
+	return &Definition{
+		Table: []Transition{
+			{From: "Cart", Event: "checkout", To: "Payment"},
+			{From: "Payment", Event: "authorize", To: "Authorized", Guard: "amount > 0"},
+			{From: "Payment", Event: "fail", To: "Failed"},
+			{From: "Authorized", Event: "capture", To: "Captured"},
+			{From: "Paid", Event: "refund", To: "Refunded"},
+			{From: "Archived", Event: "restore", To: "Cart"},
+		},
+		Start:   []string{"Cart", "Authorized"},
+		End:     []string{"Captured", "Refunded"},
+		Parents: map[string]string{"Authorized": "Paid", "Captured": "Paid"},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name    string
+		machine Machine
+		want    string
+	}{
+		{
+			name:    "Hierarchical machine",
+			machine: order(),
+			want: "stateDiagram-v2\n" +
+				"    state Paid {\n" +
+				"        [*] --> Authorized\n" +
+				"        Captured --> [*]\n" +
+				"    }\n" +
+				"    note right of Failed: dead end\n" +
+				"    note right of Archived: unreachable\n" +
+				"\t[*] --> Cart\n" +
+				"\tCart --> Payment: checkout\n" +
+				"\tPayment --> Authorized: authorize [amount > 0]\n" +
+				"\tPayment --> Failed: fail\n" +
+				"\tAuthorized --> Captured: capture\n" +
+				"\tPaid --> Refunded: refund\n" +
+				"\tArchived --> Cart: restore\n" +
+				"\tRefunded --> [*]\n",
+		},
+		{
+			name: "Final states without transitions leaving them",
+			machine: &Definition{
+				Table: []Transition{
+					{From: "Idle", Event: "start", To: "Running"},
+					{From: "Running", To: "Done"},
+					{From: "Running", Guard: "retries > 3", To: "Gave up"},
+				},
+				Start: []string{"Idle"},
+			},
+			want: "stateDiagram-v2\n" +
+				"    state \"Gave up\" as Gave_up\n" +
+				"\t[*] --> Idle\n" +
+				"\tIdle --> Running: start\n" +
+				"\tRunning --> Done\n" +
+				"\tRunning --> Gave_up: [retries > 3]\n" +
+				"\tDone --> [*]\n" +
+				"\tGave_up --> [*]\n",
+		},
+		{
+			name: "States without transitions",
+			machine: &Definition{
+				Start: []string{"Idle"},
+				End:   []string{"Idle"},
+			},
+			want: "stateDiagram-v2\n" +
+				"    state \"Idle\" as Idle\n" +
+				"\t[*] --> Idle\n" +
+				"\tIdle --> [*]\n",
+		},
+		{
+			name: "Without initial states",
+			machine: &Definition{
+				Table: []Transition{
+					{From: "a", Event: "go", To: "b"},
+					{From: "c", Event: "go", To: "b"},
+				},
+			},
+			want: "stateDiagram-v2\n" +
+				"\ta --> b: go\n" +
+				"\tc --> b: go\n" +
+				"\tb --> [*]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Generate(tt.machine)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if got := testutils.DiagramBody(t, d.String()); got != tt.want {
+				t.Errorf("Generate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		machine Machine
+		want    error
+	}{
+		{
+			name:    "Transition without destination",
+			machine: &Definition{Table: []Transition{{From: "a", Event: "go"}}},
+			want:    ErrNoState,
+		},
+		{
+			name: "State nested in itself",
+			machine: &Definition{
+				Table:   []Transition{{From: "a", Event: "go", To: "b"}},
+				Parents: map[string]string{"a": "p", "p": "q", "q": "a"},
+			},
+			want: ErrHierarchyCycle,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Generate(tt.machine); !errors.Is(err, tt.want) {
+				t.Errorf("Generate() error = %v, want %v", err, tt.want)
+			}
+			if _, err := Analyze(tt.machine); !errors.Is(err, tt.want) {
+				t.Errorf("Analyze() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name    string
+		machine Machine
+		want    Analysis
+	}{
+		{
+			name:    "Hierarchical machine",
+			machine: order(),
+			want:    Analysis{Unreachable: []string{"Archived"}, DeadEnds: []string{"Failed"}},
+		},
+		{
+			name: "Transitions inherited from composite states",
+			machine: &Definition{
+				Table: []Transition{
+					{From: "Off", Event: "power", To: "On"},
+					{From: "On", Event: "power", To: "Off"},
+					{From: "Low", Event: "up", To: "High"},
+					{From: "Boost", Event: "down", To: "High"},
+				},
+				Start:   []string{"Off", "Low"},
+				End:     []string{"Off"},
+				Parents: map[string]string{"Low": "On", "High": "On", "Boost": "On"},
+			},
+			want: Analysis{Unreachable: []string{"Boost"}},
+		},
+		{
+			name: "Composite states without initial states",
+			machine: &Definition{
+				Table: []Transition{
+					{From: "Off", Event: "power", To: "On"},
+				},
+				Start:   []string{"Off"},
+				End:     []string{"Low", "High"},
+				Parents: map[string]string{"Low": "On", "High": "On"},
+			},
+		},
+		{
+			name: "Machine without final states",
+			machine: &Definition{
+				Table: []Transition{{From: "a", Event: "go", To: "b"}},
+				Start: []string{"b"},
+			},
+			want: Analysis{Unreachable: []string{"a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Analyze(tt.machine)
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Analyze() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
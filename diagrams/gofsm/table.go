@@ -0,0 +1,110 @@
+package gofsm
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrNotTable is returned by Table for values other than slices of structs with source,
+// event and destination fields.
+var ErrNotTable = errors.New("not a transition table")
+
+const tableErrorString string = "%w: %s"
+
+// Field names of the rows of transition tables, in order of preference.
+var (
+	sourceFields      = []string{"From", "Src", "Source"}
+	eventFields       = []string{"Event", "Name"}
+	destinationFields = []string{"To", "Dst", "Destination"}
+	guardFields       = []string{"Guard", "Cond", "Condition"}
+)
+
+// Table returns the machine described by a transition table and its initial and final
+// states. The table is a slice or array of structs with a From, Src or Source field, an
+// Event or Name field, a To, Dst or Destination field and an optional Guard, Cond or
+// Condition field, such as []struct{From, Event, To string} or the fsm.Events of
+// github.com/looplab/fsm. Sources may list several states, one transition being drawn from
+// each. States are strings, or values of other types printed with fmt, such as
+// enumerations with a String method.
+//
+// Without final states, the states without transitions leaving them are final, so Analyze
+// reports no dead ends: list the final states to have the others flagged.
+func Table(table interface{}, initial []string, final []string) (*Definition, error) {
+	rows := reflect.ValueOf(table)
+	if rows.Kind() != reflect.Slice && rows.Kind() != reflect.Array {
+		return nil, fmt.Errorf(tableErrorString, ErrNotTable, reflect.TypeOf(table))
+	}
+	row := rows.Type().Elem()
+	for row.Kind() == reflect.Pointer {
+		row = row.Elem()
+	}
+	if row.Kind() != reflect.Struct {
+		return nil, fmt.Errorf(tableErrorString, ErrNotTable, rows.Type())
+	}
+
+	source, event, destination := field(row, sourceFields), field(row, eventFields), field(row, destinationFields)
+	guard := field(row, guardFields)
+	if source == nil || event == nil || destination == nil {
+		return nil, fmt.Errorf(tableErrorString, ErrNotTable, rows.Type())
+	}
+
+	d := &Definition{Start: initial, End: final}
+	for i := 0; i < rows.Len(); i++ {
+		r := reflect.Indirect(rows.Index(i))
+		if !r.IsValid() {
+			continue
+		}
+
+		t := Transition{
+			Event: value(r.FieldByIndex(event)),
+			To:    value(r.FieldByIndex(destination)),
+		}
+		if guard != nil {
+			t.Guard = value(r.FieldByIndex(guard))
+		}
+		for _, from := range values(r.FieldByIndex(source)) {
+			t.From = from
+			d.Table = append(d.Table, t)
+		}
+	}
+
+	return d, nil
+}
+
+// field returns the index of the first of the named fields of a struct.
+func field(row reflect.Type, names []string) []int {
+	for _, name := range names {
+		if f, ok := row.FieldByName(name); ok {
+			return f.Index
+		}
+	}
+
+	return nil
+}
+
+// values returns the states of a field holding a state or a list of states.
+func values(v reflect.Value) []string {
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8 {
+		states := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			states = append(states, value(v.Index(i)))
+		}
+		return states
+	}
+
+	return []string{value(v)}
+}
+
+// value returns the state or event held by a field.
+func value(v reflect.Value) string {
+	if (v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer) && v.IsNil() {
+		return ""
+	}
+	v = reflect.Indirect(v)
+	if v.CanInterface() {
+		return fmt.Sprint(v.Interface())
+	}
+
+	return fmt.Sprint(v)
+}
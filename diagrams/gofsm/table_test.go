@@ -0,0 +1,128 @@
+package gofsm
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// EventDesc mirrors fsm.EventDesc of github.com/looplab/fsm.
+type EventDesc struct {
+	Name string
+	Src  []string
+	Dst  string
+}
+
+type light int
+
+const (
+	red light = iota
+	green
+)
+
+func (l light) String() string {
+	return [...]string{"Red", "Green"}[l]
+}
+
+func TestTable(t *testing.T) {
+	tests := []struct {
+		name  string
+		table interface{}
+		want  []Transition
+	}{
+		{
+			name: "Transition table",
+			table: []struct{ From, Event, To, Guard string }{
+				{From: "closed", Event: "open", To: "open", Guard: "unlocked"},
+				{From: "open", Event: "close", To: "closed"},
+			},
+			want: []Transition{
+				{From: "closed", Event: "open", To: "open", Guard: "unlocked"},
+				{From: "open", Event: "close", To: "closed"},
+			},
+		},
+		{
+			name: "looplab/fsm events",
+			table: []EventDesc{
+				{Name: "open", Src: []string{"closed"}, Dst: "open"},
+				{Name: "close", Src: []string{"open", "ajar"}, Dst: "closed"},
+			},
+			want: []Transition{
+				{From: "closed", Event: "open", To: "open"},
+				{From: "open", Event: "close", To: "closed"},
+				{From: "ajar", Event: "close", To: "closed"},
+			},
+		},
+		{
+			name: "Enumerated states",
+			table: [...]*struct {
+				Source      light
+				Event       string
+				Destination light
+			}{
+				{Source: red, Event: "go", Destination: green},
+				nil,
+			},
+			want: []Transition{
+				{From: "Red", Event: "go", To: "Green"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Table(tt.table, []string{"closed"}, nil)
+			if err != nil {
+				t.Fatalf("Table() error = %v", err)
+			}
+			if !reflect.DeepEqual(d.Table, tt.want) {
+				t.Errorf("Table() = %+v, want %+v", d.Table, tt.want)
+			}
+			if !reflect.DeepEqual(d.Start, []string{"closed"}) {
+				t.Errorf("Table() initial = %v, want %v", d.Start, []string{"closed"})
+			}
+		})
+	}
+}
+
+func TestTable_Errors(t *testing.T) {
+	for _, table := range []interface{}{nil, "closed", []string{"closed"}, []struct{ From, To string }{}} {
+		if _, err := Table(table, nil, nil); !errors.Is(err, ErrNotTable) {
+			t.Errorf("Table(%T) error = %v, want %v", table, err, ErrNotTable)
+		}
+	}
+}
+
+func TestTable_DeadEnds(t *testing.T) {
+	table := []EventDesc{
+		{Name: "open", Src: []string{"closed"}, Dst: "open"},
+		{Name: "close", Src: []string{"open"}, Dst: "closed"},
+		{Name: "break", Src: []string{"open"}, Dst: "broken"},
+		{Name: "lock", Src: []string{"closed"}, Dst: "locked"},
+	}
+
+	tests := []struct {
+		name  string
+		final []string
+		want  Analysis
+	}{
+		{name: "Without final states", want: Analysis{}},
+		{name: "With final states", final: []string{"locked"}, want: Analysis{DeadEnds: []string{"broken"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Table(table, []string{"closed"}, tt.final)
+			if err != nil {
+				t.Fatalf("Table() error = %v", err)
+			}
+			got, err := Analyze(d)
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Analyze() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,554 @@
+// Package gomodels draws entity relationship diagrams of the Go structs an ORM maps to
+// database tables, keeping the documented schema in sync with the code.
+//
+// The structs are read by reflection, along with their gorm, bun and db (sqlx) struct tags.
+// Every model becomes an entity named after its table: the result of its TableName method,
+// the table of its bun.BaseModel tag, or the snake case plural of the struct name. Every
+// exported field becomes an attribute named after its column, embedded structs being
+// flattened. Fields holding other structs, or slices of them, are relations: belongs to,
+// has one and has many are drawn from their foreign keys, found by the bun join or the gorm
+// foreignKey tag, or guessed from the field names as gorm does, and many to many from the
+// join table of the gorm many2many or bun m2m tag. Related structs are drawn even when they
+// are not given.
+package gomodels
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/entityrelationship"
+)
+
+// ErrNotStruct is returned when a model is not a struct or a pointer to one.
+var ErrNotStruct = errors.New("model is not a struct")
+
+const (
+	modelErrorString string = "%w: %T"
+	idField          string = "ID"
+	idColumn         string = "id"
+	labelSeparator   string = ", "
+	nameJoin         string = "_"
+	validField       string = "Valid"
+)
+
+// blobType is the attribute type of byte slices.
+const blobType entityrelationship.DataType = "blob"
+
+// relationKind is the kind of relation a field holding other models stands for.
+type relationKind int
+
+// Kinds of relations.
+const (
+	unknownRelation relationKind = iota
+	belongsTo
+	hasOne
+	hasMany
+	manyToMany
+)
+
+// unsafeName matches the characters that cannot appear in entity, attribute and type names.
+var unsafeName = regexp.MustCompile(`[^\w-]+`)
+
+// Types that are stored in a single column.
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// Options controls the diagram drawn by Generate.
+type Options struct {
+	// SingularTable names tables after their struct without making the name plural, as the
+	// gorm naming strategy option of the same name.
+	SingularTable bool
+}
+
+// tabler is implemented by the models that name their own table.
+type tabler interface {
+	TableName() string
+}
+
+// model is a struct mapped to a table.
+type model struct {
+	typ       reflect.Type
+	table     string
+	columns   []*column
+	relations []*relationField
+}
+
+// column is a field of a model mapped to a column.
+type column struct {
+	field      string
+	name       string
+	dataType   entityrelationship.DataType
+	primaryKey bool
+	unique     bool
+	foreignKey bool
+	nullable   bool
+}
+
+// relationField is a field of a model holding one or many other models.
+type relationField struct {
+	name   string
+	target reflect.Type
+	many   bool
+	tags   tags
+}
+
+// relationship is a relation between two models, from the model the foreign key
+// references, or from the model declaring a many to many relation. The field is the has
+// one or has many field of the parent, empty while only the child declares the relation.
+type relationship struct {
+	parent     *model
+	child      *model
+	field      string
+	columns    []*column
+	label      string
+	unique     bool
+	manyToMany bool
+}
+
+// generator holds the state of a diagram being drawn.
+type generator struct {
+	options       Options
+	models        []*model
+	byType        map[reflect.Type]*model
+	relationships []*relationship
+}
+
+// Generate draws the models, given as structs or pointers to structs such as &User{}, and
+// the models related to them.
+func Generate(models []interface{}, options Options) (*entityrelationship.Diagram, error) {
+	g := &generator{options: options, byType: make(map[reflect.Type]*model)}
+
+	for _, value := range models {
+		t := reflect.TypeOf(value)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			return nil, fmt.Errorf(modelErrorString, ErrNotStruct, value)
+		}
+		g.add(t)
+	}
+
+	for i := 0; i < len(g.models); i++ {
+		g.parse(g.models[i])
+	}
+	for _, m := range g.models {
+		for _, r := range m.relations {
+			g.relate(m, r)
+		}
+	}
+
+	return g.diagram(), nil
+}
+
+// add adds a struct to the models, unless it is one already.
+func (g *generator) add(t reflect.Type) {
+	if _, ok := g.byType[t]; ok {
+		return
+	}
+
+	m := &model{typ: t}
+	g.models = append(g.models, m)
+	g.byType[t] = m
+}
+
+// parse reads the table, columns and relations of a model, adding the related structs to
+// the models. Without a primary key tag, the ID field is the primary key.
+func (g *generator) parse(m *model) {
+	g.addFields(m, m.typ, "")
+
+	if m.table == "" {
+		m.table = g.tableName(m.typ)
+	}
+
+	primaryKey := false
+	for _, c := range m.columns {
+		primaryKey = primaryKey || c.primaryKey
+	}
+	for _, c := range m.columns {
+		if !primaryKey && (c.field == idField || c.name == idColumn) {
+			c.primaryKey = true
+		}
+		c.nullable = c.nullable && !c.primaryKey
+	}
+}
+
+// addFields adds the fields of a struct to a model, with a prefix before their column
+// names.
+func (g *generator) addFields(m *model, t reflect.Type, prefix string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tags := parseTags(field)
+		if tags.table != "" {
+			m.table = tags.table
+			continue
+		}
+		if tags.ignored {
+			continue
+		}
+
+		fieldType := indirect(field.Type)
+		if fieldType.Kind() == reflect.Struct && !storedInColumn(fieldType) && (field.Anonymous || tags.embedded) {
+			g.addFields(m, fieldType, prefix+tags.prefix)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if target, many, ok := relationTarget(field.Type); ok {
+			m.relations = append(m.relations, &relationField{name: field.Name, target: target, many: many, tags: tags})
+			g.add(target)
+			continue
+		}
+
+		name := tags.column
+		if name == "" {
+			name = snakeCase(field.Name)
+		}
+		m.columns = append(m.columns, &column{
+			field:      field.Name,
+			name:       prefix + name,
+			dataType:   dataType(field.Type),
+			primaryKey: tags.primaryKey,
+			unique:     tags.unique,
+			nullable:   (nullable(field.Type) || tags.nullZero) && !tags.notNull,
+		})
+	}
+}
+
+// tableName returns the table of a model without a bun table tag.
+func (g *generator) tableName(t reflect.Type) string {
+	if tabler, ok := reflect.New(t).Interface().(tabler); ok {
+		return tabler.TableName()
+	}
+
+	name := snakeCase(t.Name())
+	if !g.options.SingularTable {
+		name = plural(name)
+	}
+
+	return name
+}
+
+// relate adds the relationship of a relation field of a model. Relations whose foreign key
+// cannot be found are left out.
+func (g *generator) relate(owner *model, r *relationField) {
+	target := g.byType[r.target]
+	kind := r.tags.relation
+	if kind == manyToMany {
+		g.addManyToMany(owner, target, r.tags.joinTable)
+		return
+	}
+
+	var columns []*column
+	switch {
+	case len(r.tags.baseColumns) > 0:
+		if kind == unknownRelation {
+			kind = belongsTo
+			if r.many {
+				kind = hasMany
+			}
+		}
+		if kind == belongsTo {
+			columns = owner.find(r.tags.baseColumns)
+		} else {
+			columns = target.find(r.tags.joinColumns)
+		}
+	default:
+		hasKeys, belongsKeys := r.tags.foreignKeys, r.tags.foreignKeys
+		if len(r.tags.foreignKeys) == 0 {
+			hasKeys = []string{owner.typ.Name() + idField}
+			belongsKeys = []string{r.name + idField}
+		}
+
+		if kind != belongsTo {
+			columns = target.find(hasKeys)
+			if columns != nil && kind == unknownRelation {
+				kind = hasOne
+				if r.many {
+					kind = hasMany
+				}
+			}
+		}
+		if columns == nil && !r.many && (kind == unknownRelation || kind == belongsTo) {
+			columns, kind = owner.find(belongsKeys), belongsTo
+		}
+	}
+	if columns == nil {
+		return
+	}
+
+	parent, child, field := owner, target, r.name
+	if kind == belongsTo {
+		parent, child, field = target, owner, ""
+	}
+	for _, c := range columns {
+		c.foreignKey = true
+	}
+	g.addForeignKey(parent, child, field, columns, kind == hasOne)
+}
+
+// addForeignKey adds the relationship of a foreign key, unless the relation is also
+// declared from the other model. Has one and has many fields of the parent sharing a
+// foreign key each add their own relationship.
+func (g *generator) addForeignKey(parent *model, child *model, field string, columns []*column, unique bool) {
+	names := make([]string, 0, len(columns))
+	for _, c := range columns {
+		names = append(names, c.name)
+	}
+	label := strings.Join(names, labelSeparator)
+
+	for _, r := range g.relationships {
+		if !r.manyToMany && r.parent == parent && r.child == child && r.label == label &&
+			(r.field == "" || field == "" || r.field == field) {
+			if r.field == "" {
+				r.field = field
+			}
+			r.unique = r.unique || unique
+			return
+		}
+	}
+
+	g.relationships = append(g.relationships, &relationship{parent: parent, child: child, field: field, columns: columns, label: label, unique: unique})
+}
+
+// addManyToMany adds a many to many relationship through a join table, unless the relation
+// is also declared from the other model.
+func (g *generator) addManyToMany(owner *model, target *model, joinTable string) {
+	for _, r := range g.relationships {
+		if r.manyToMany && r.label == joinTable &&
+			(r.parent == owner && r.child == target || r.parent == target && r.child == owner) {
+			return
+		}
+	}
+
+	g.relationships = append(g.relationships, &relationship{parent: owner, child: target, label: joinTable, manyToMany: true})
+}
+
+// diagram returns the diagram of the models and their relationships.
+func (g *generator) diagram() *entityrelationship.Diagram {
+	d := entityrelationship.NewDiagram()
+
+	entities := make(map[*model]*entityrelationship.Entity)
+	for _, m := range g.models {
+		e := d.AddEntity(unsafeName.ReplaceAllString(m.table, nameJoin))
+		if e.Name != m.table {
+			e.SetAlias(m.table)
+		}
+		entities[m] = e
+
+		for _, c := range m.columns {
+			attribute := e.AddAttribute(unsafeName.ReplaceAllString(c.name, nameJoin), c.dataType)
+			if c.primaryKey {
+				attribute.SetPrimaryKey()
+			}
+			if c.foreignKey {
+				attribute.SetForeignKey()
+			}
+			if c.unique {
+				attribute.SetUniqueKey()
+			}
+			if !c.nullable {
+				attribute.SetRequired()
+			}
+		}
+	}
+
+	for _, r := range g.relationships {
+		d.AddRelationship(entities[r.parent], entities[r.child]).
+			SetLabel(r.label).
+			SetCardinality(r.cardinality())
+	}
+
+	return d
+}
+
+// cardinality returns the cardinality of the relationship: the parent is optional when a
+// column of the foreign key is nullable, and has at most one child for a has one relation
+// or when the foreign key includes a unique column or the primary key of the child.
+func (r *relationship) cardinality() entityrelationship.Cardinality {
+	if r.manyToMany {
+		return entityrelationship.ManyToMany
+	}
+
+	optional, unique := false, r.unique
+	for _, c := range r.columns {
+		optional = optional || c.nullable
+		unique = unique || c.unique
+	}
+
+	primaryKey := false
+	includesPrimaryKey := true
+	for _, c := range r.child.columns {
+		if c.primaryKey {
+			primaryKey = true
+			includesPrimaryKey = includesPrimaryKey && r.has(c)
+		}
+	}
+	unique = unique || primaryKey && includesPrimaryKey
+
+	switch {
+	case unique && optional:
+		return entityrelationship.ZeroOrOneToOne
+	case unique:
+		return entityrelationship.OneToZeroOrOne
+	case optional:
+		return entityrelationship.ZeroOrOneToMany
+	default:
+		return entityrelationship.OneToZeroOrMore
+	}
+}
+
+// has reports whether a column is part of the foreign key of the relationship.
+func (r *relationship) has(c *column) bool {
+	for _, other := range r.columns {
+		if other == c {
+			return true
+		}
+	}
+
+	return false
+}
+
+// find returns the columns of a model with the given field or column names, or nil unless
+// all of them are found.
+func (m *model) find(names []string) []*column {
+	columns := make([]*column, 0, len(names))
+	for _, name := range names {
+		var found *column
+		for _, c := range m.columns {
+			if c.field == name || strings.EqualFold(c.name, name) {
+				found = c
+				break
+			}
+		}
+		if found == nil {
+			return nil
+		}
+		columns = append(columns, found)
+	}
+
+	return columns
+}
+
+// relationTarget returns the struct held by a relation field, directly, through pointers or
+// in a slice, reporting whether the field is a relation.
+func relationTarget(t reflect.Type) (target reflect.Type, many bool, ok bool) {
+	t = indirect(t)
+	if t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+		t, many = indirect(t.Elem()), true
+	}
+
+	return t, many, t.Kind() == reflect.Struct && !storedInColumn(t)
+}
+
+// storedInColumn reports whether values of a struct type are stored in a single column:
+// times, nullable wrappers such as sql.NullString, and types implementing sql.Scanner or
+// driver.Valuer.
+func storedInColumn(t reflect.Type) bool {
+	return t == timeType || nullWrapper(t) ||
+		t.Implements(valuerType) || reflect.PtrTo(t).Implements(valuerType) || reflect.PtrTo(t).Implements(scannerType)
+}
+
+// nullWrapper reports whether a type wraps a nullable value as sql.NullString does, with
+// the value followed by a Valid flag.
+func nullWrapper(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.NumField() == 2 &&
+		t.Field(1).Name == validField && t.Field(1).Type.Kind() == reflect.Bool
+}
+
+// nullable reports whether a field of a type can hold NULL.
+func nullable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	}
+
+	return nullWrapper(t)
+}
+
+// dataType returns the attribute type of a field: times, integers, floats, booleans and
+// strings are mapped to the types of the entityrelationship package, byte slices are blobs
+// and other types keep their name in lower case.
+func dataType(t reflect.Type) entityrelationship.DataType {
+	t = indirect(t)
+	switch {
+	case t == timeType:
+		return entityrelationship.TypeDateTime
+	case nullWrapper(t):
+		return dataType(t.Field(0).Type)
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return blobType
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return entityrelationship.TypeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return entityrelationship.TypeInteger
+	case reflect.Float32, reflect.Float64:
+		return entityrelationship.TypeFloat
+	case reflect.String:
+		return entityrelationship.TypeString
+	}
+
+	name := t.Name()
+	if name == "" {
+		name = t.Kind().String()
+	}
+
+	return entityrelationship.DataType(strings.ToLower(unsafeName.ReplaceAllString(name, nameJoin)))
+}
+
+// indirect returns the type pointers point to.
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// snakeCase returns a Go name in snake case as gorm names columns and tables, keeping
+// initialisms together: UserID becomes user_id and HTTPServer http_server.
+func snakeCase(name string) string {
+	var sb strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				sb.WriteString(nameJoin)
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+// plural returns the plural of an English noun by its regular rules.
+func plural(noun string) string {
+	switch {
+	case strings.HasSuffix(noun, "y") && len(noun) > 1 && !strings.ContainsRune("aeiou", rune(noun[len(noun)-2])):
+		return noun[:len(noun)-1] + "ies"
+	case strings.HasSuffix(noun, "s"), strings.HasSuffix(noun, "x"), strings.HasSuffix(noun, "z"),
+		strings.HasSuffix(noun, "ch"), strings.HasSuffix(noun, "sh"):
+		return noun + "es"
+	default:
+		return noun + "s"
+	}
+}
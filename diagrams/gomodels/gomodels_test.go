@@ -0,0 +1,352 @@
+package gomodels
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/entityrelationship"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/testutils"
+)
+
+// Model mirrors gorm.Model.
+type Model struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt sql.NullTime `gorm:"index"`
+}
+
+type User struct {
+	Model
+	Name       string
+	Email      *string `gorm:"unique"`
+	Active     bool
+	Score      float64
+	Avatar     []byte
+	CompanyID  *int
+	Company    Company
+	CreditCard CreditCard
+	Orders     []Order
+	Languages  []Language `gorm:"many2many:user_languages;"`
+	ManagerID  *uint
+	Manager    *User
+	Address    Address `gorm:"embedded;embeddedPrefix:address_"`
+	Ignored    string  `gorm:"-"`
+	secret     string
+}
+
+type Company struct {
+	ID   int
+	Name string `gorm:"not null;uniqueIndex"`
+}
+
+type CreditCard struct {
+	Model
+	Number string
+	UserID uint
+}
+
+type Order struct {
+	ID        uint
+	UserID    uint
+	Amount    float64
+	Status    Status
+	ShippedAt *time.Time
+}
+
+// TableName names the table of orders.
+func (Order) TableName() string {
+	return "purchase_orders"
+}
+
+type Status string
+
+type Language struct {
+	ID    uint
+	Code  string  `gorm:"column:iso_code"`
+	Users []*User `gorm:"many2many:user_languages;"`
+}
+
+type Address struct {
+	Street string
+	City   string
+}
+
+// BaseModel mirrors bun.BaseModel.
+type BaseModel struct{}
+
+type Author struct {
+	BaseModel `bun:"table:writers,alias:w"`
+	ID        int64    `bun:",pk,autoincrement"`
+	Name      string   `bun:"full_name,notnull"`
+	Bio       string   `bun:",nullzero"`
+	Books     []*Book  `bun:"rel:has-many,join:id=author_id"`
+	Profile   *Profile `bun:"rel:has-one,join:id=author_id"`
+}
+
+type Book struct {
+	BaseModel `bun:"table:books"`
+	ISBN      string  `bun:"isbn,pk"`
+	AuthorID  int64   `bun:",notnull"`
+	Author    *Author `bun:"rel:belongs-to,join:author_id=id"`
+	Tags      []Tag   `bun:"m2m:book_tags,join:Book=Tag"`
+}
+
+type Profile struct {
+	AuthorID int64 `bun:",pk"`
+	Website  sql.NullString
+}
+
+type Tag struct {
+	ID    int64  `bun:",pk"`
+	Label string `bun:",unique"`
+}
+
+type Account struct {
+	ID      int          `db:"id"`
+	Owner   string       `db:"owner_name"`
+	Balance float32      `db:"balance"`
+	Opened  time.Time    `db:"opened_at"`
+	Closed  sql.NullTime `db:"closed_at"`
+	Notes   string       `db:"-"`
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name    string
+		models  []interface{}
+		options Options
+		want    string
+	}{
+		{
+			name:   "gorm",
+			models: []interface{}{&User{}},
+			want: "erDiagram\n" +
+				"    users {\n" +
+				"        int id PK\n" +
+				"        datetime created_at\n" +
+				"        datetime updated_at\n" +
+				"        datetime deleted_at\n" +
+				"        string name\n" +
+				"        string email UK\n" +
+				"        boolean active\n" +
+				"        float score\n" +
+				"        blob avatar\n" +
+				"        int company_id FK\n" +
+				"        int manager_id FK\n" +
+				"        string address_street\n" +
+				"        string address_city\n" +
+				"    }\n" +
+				"    companies {\n" +
+				"        int id PK\n" +
+				"        string name UK\n" +
+				"    }\n" +
+				"    credit_cards {\n" +
+				"        int id PK\n" +
+				"        datetime created_at\n" +
+				"        datetime updated_at\n" +
+				"        datetime deleted_at\n" +
+				"        string number\n" +
+				"        int user_id FK\n" +
+				"    }\n" +
+				"    purchase_orders {\n" +
+				"        int id PK\n" +
+				"        int user_id FK\n" +
+				"        float amount\n" +
+				"        string status\n" +
+				"        datetime shipped_at\n" +
+				"    }\n" +
+				"    languages {\n" +
+				"        int id PK\n" +
+				"        string iso_code\n" +
+				"    }\n" +
+				"\n" +
+				"    companies |o--o{ users : company_id\n" +
+				"    users ||--o| credit_cards : user_id\n" +
+				"    users ||--o{ purchase_orders : user_id\n" +
+				"    users }o--o{ languages : user_languages\n" +
+				"    users |o--o{ users : manager_id\n",
+		},
+		{
+			name:   "bun",
+			models: []interface{}{Book{}, (*Author)(nil)},
+			want: "erDiagram\n" +
+				"    books {\n" +
+				"        string isbn PK\n" +
+				"        int author_id FK\n" +
+				"    }\n" +
+				"    writers {\n" +
+				"        int id PK\n" +
+				"        string full_name\n" +
+				"        string bio\n" +
+				"    }\n" +
+				"    tags {\n" +
+				"        int id PK\n" +
+				"        string label UK\n" +
+				"    }\n" +
+				"    profiles {\n" +
+				"        int author_id PK,FK\n" +
+				"        string website\n" +
+				"    }\n" +
+				"\n" +
+				"    writers ||--o{ books : author_id\n" +
+				"    books }o--o{ tags : book_tags\n" +
+				"    writers ||--o| profiles : author_id\n",
+		},
+		{
+			name:    "sqlx with singular tables",
+			models:  []interface{}{Account{}},
+			options: Options{SingularTable: true},
+			want: "erDiagram\n" +
+				"    account {\n" +
+				"        int id PK\n" +
+				"        string owner_name\n" +
+				"        float balance\n" +
+				"        datetime opened_at\n" +
+				"        datetime closed_at\n" +
+				"    }\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Generate(tt.models, tt.options)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if got := testutils.DiagramBody(t, d.String()); got != tt.want {
+				t.Errorf("Generate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_Required(t *testing.T) {
+	d, err := Generate([]interface{}{&User{}, &Author{}, &Account{}}, Options{})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	tests := []struct {
+		entity    string
+		attribute string
+		want      bool
+	}{
+		{entity: "users", attribute: "id", want: true},
+		{entity: "users", attribute: "name", want: true},
+		{entity: "users", attribute: "email", want: false},
+		{entity: "users", attribute: "deleted_at", want: false},
+		{entity: "users", attribute: "avatar", want: false},
+		{entity: "writers", attribute: "full_name", want: true},
+		{entity: "writers", attribute: "bio", want: false},
+		{entity: "accounts", attribute: "closed_at", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entity+"."+tt.attribute, func(t *testing.T) {
+			attribute := d.FindEntity(tt.entity).FindAttribute(tt.attribute)
+			if attribute.Required != tt.want {
+				t.Errorf("Required = %v, want %v", attribute.Required, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	for _, model := range []interface{}{nil, 42, []User{}} {
+		if _, err := Generate([]interface{}{model}, Options{}); !errors.Is(err, ErrNotStruct) {
+			t.Errorf("Generate(%T) error = %v, want %v", model, err, ErrNotStruct)
+		}
+	}
+}
+
+func TestGenerate_Cardinalities(t *testing.T) {
+	type Parent struct {
+		ID int
+	}
+	type Child struct {
+		ID       int
+		ParentID *int `gorm:"unique"`
+		Parent   Parent
+	}
+
+	d, err := Generate([]interface{}{Child{}}, Options{})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got := d.Relationships[0].Cardinality; got != entityrelationship.ZeroOrOneToOne {
+		t.Errorf("Cardinality = %q, want %q", got, entityrelationship.ZeroOrOneToOne)
+	}
+}
+
+func TestGenerate_RelationsSharingForeignKey(t *testing.T) {
+	type Item struct {
+		ID       int
+		ParentID int
+	}
+	type Parent struct {
+		ID     int
+		Latest Item
+		Items  []Item
+	}
+
+	d, err := Generate([]interface{}{Parent{}, Item{}}, Options{})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(d.Relationships) != 2 {
+		t.Fatalf("Relationships = %d, want 2", len(d.Relationships))
+	}
+	if got := d.Relationships[0].Cardinality; got != entityrelationship.OneToZeroOrOne {
+		t.Errorf("has one Cardinality = %q, want %q", got, entityrelationship.OneToZeroOrOne)
+	}
+	if got := d.Relationships[1].Cardinality; got != entityrelationship.OneToZeroOrMore {
+		t.Errorf("has many Cardinality = %q, want %q", got, entityrelationship.OneToZeroOrMore)
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "ID", want: "id"},
+		{name: "UserID", want: "user_id"},
+		{name: "HTTPServer", want: "http_server"},
+		{name: "CreatedAt", want: "created_at"},
+		{name: "Address2Line", want: "address2_line"},
+		{name: "already_snake", want: "already_snake"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snakeCase(tt.name); got != tt.want {
+				t.Errorf("snakeCase() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlural(t *testing.T) {
+	tests := []struct {
+		noun string
+		want string
+	}{
+		{noun: "user", want: "users"},
+		{noun: "company", want: "companies"},
+		{noun: "day", want: "days"},
+		{noun: "address", want: "addresses"},
+		{noun: "box", want: "boxes"},
+		{noun: "match", want: "matches"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.noun, func(t *testing.T) {
+			if got := plural(tt.noun); got != tt.want {
+				t.Errorf("plural() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
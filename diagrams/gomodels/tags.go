@@ -0,0 +1,179 @@
+package gomodels
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Struct tags read from the fields of models.
+const (
+	tagGorm = "gorm"
+	tagBun  = "bun"
+	tagDB   = "db"
+)
+
+// Separators of the struct tags.
+const (
+	gormSeparator = ";"
+	gormValue     = ":"
+	bunSeparator  = ","
+	bunValue      = ":"
+	bunJoin       = "="
+	listSeparator = ","
+	ignoredField  = "-"
+	ignoredPrefix = "-:"
+)
+
+// Keys of the gorm tag, in upper case as gorm compares them.
+const (
+	gormColumn         = "COLUMN"
+	gormPrimaryKey     = "PRIMARYKEY"
+	gormPrimaryKeyOld  = "PRIMARY_KEY"
+	gormUnique         = "UNIQUE"
+	gormUniqueIndex    = "UNIQUEINDEX"
+	gormNotNull        = "NOT NULL"
+	gormForeignKey     = "FOREIGNKEY"
+	gormMany2Many      = "MANY2MANY"
+	gormEmbedded       = "EMBEDDED"
+	gormEmbeddedPrefix = "EMBEDDEDPREFIX"
+)
+
+// Keys of the bun tag.
+const (
+	bunTable    = "table"
+	bunPK       = "pk"
+	bunNotNull  = "notnull"
+	bunNullZero = "nullzero"
+	bunUnique   = "unique"
+	bunRel      = "rel"
+	bunJoinKey  = "join"
+	bunM2M      = "m2m"
+	bunEmbed    = "embed"
+)
+
+// Relations of the bun rel key.
+const (
+	bunBelongsTo = "belongs-to"
+	bunHasOne    = "has-one"
+	bunHasMany   = "has-many"
+)
+
+// tags are the settings of a field read from its gorm, bun and db struct tags. The foreign
+// keys of gorm name fields, while the join of bun pairs the columns of the model holding the
+// field with those of the related model.
+type tags struct {
+	ignored     bool
+	column      string
+	primaryKey  bool
+	unique      bool
+	notNull     bool
+	nullZero    bool
+	table       string
+	relation    relationKind
+	foreignKeys []string
+	baseColumns []string
+	joinColumns []string
+	joinTable   string
+	embedded    bool
+	prefix      string
+}
+
+// parseTags reads the struct tags of a field.
+func parseTags(field reflect.StructField) tags {
+	var t tags
+	t.parseGorm(field.Tag.Get(tagGorm))
+	t.parseBun(field.Tag.Get(tagBun))
+
+	if name, ok := field.Tag.Lookup(tagDB); ok {
+		name, _, _ = strings.Cut(name, listSeparator)
+		if name == ignoredField {
+			t.ignored = true
+		} else if t.column == "" {
+			t.column = name
+		}
+	}
+
+	return t
+}
+
+// parseGorm reads a gorm tag, such as "column:name;primaryKey;not null".
+func (t *tags) parseGorm(tag string) {
+	if tag == ignoredField || strings.HasPrefix(tag, ignoredPrefix) {
+		t.ignored = true
+		return
+	}
+
+	for _, setting := range strings.Split(tag, gormSeparator) {
+		key, value, _ := strings.Cut(setting, gormValue)
+		switch strings.ToUpper(strings.TrimSpace(key)) {
+		case gormColumn:
+			t.column = value
+		case gormPrimaryKey, gormPrimaryKeyOld:
+			t.primaryKey = true
+		case gormUnique, gormUniqueIndex:
+			t.unique = true
+		case gormNotNull:
+			t.notNull = true
+		case gormForeignKey:
+			t.foreignKeys = strings.Split(value, listSeparator)
+		case gormMany2Many:
+			t.relation, t.joinTable = manyToMany, value
+		case gormEmbedded:
+			t.embedded = true
+		case gormEmbeddedPrefix:
+			t.prefix = value
+		}
+	}
+}
+
+// parseBun reads a bun tag, such as "name,pk,notnull", "rel:belongs-to,join:user_id=id" or
+// "table:users" on the embedded bun.BaseModel.
+func (t *tags) parseBun(tag string) {
+	if tag == "" {
+		return
+	}
+	if tag == ignoredField {
+		t.ignored = true
+		return
+	}
+
+	for i, setting := range strings.Split(tag, bunSeparator) {
+		key, value, hasValue := strings.Cut(setting, bunValue)
+		if i == 0 && !hasValue {
+			if key != "" && t.column == "" {
+				t.column = key
+			}
+			continue
+		}
+
+		switch key {
+		case bunTable:
+			t.table = value
+		case bunPK:
+			t.primaryKey = true
+		case bunNotNull:
+			t.notNull = true
+		case bunNullZero:
+			t.nullZero = true
+		case bunUnique:
+			t.unique = true
+		case bunRel:
+			t.relation = bunRelations[value]
+		case bunJoinKey:
+			base, join, _ := strings.Cut(value, bunJoin)
+			t.baseColumns = append(t.baseColumns, base)
+			t.joinColumns = append(t.joinColumns, join)
+		case bunM2M:
+			t.relation, t.joinTable = manyToMany, value
+		case bunEmbed:
+			t.embedded, t.prefix = true, value
+		}
+	}
+}
+
+// bunRelations maps the relations of the bun rel key to relation kinds.
+var bunRelations = map[string]relationKind{
+	bunBelongsTo: belongsTo,
+	bunHasOne:    hasOne,
+	bunHasMany:   hasMany,
+}
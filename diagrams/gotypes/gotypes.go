@@ -0,0 +1,442 @@
+// Package gotypes draws class diagrams of the types of Go packages, as type-checked by
+// go/types.
+//
+// Every named type becomes a class listing its fields and declared methods, exported
+// members being public and the others private. Interfaces are annotated as such and list
+// their methods as abstract. Embedded types are drawn as inheritance, and fields of the type
+// of another class as composition when they hold values, directly or as the elements of a
+// slice, array, map or channel, and as aggregation when they hold pointers or interfaces.
+// Every package is a namespace.
+package gotypes
+
+import (
+	"fmt"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/class"
+)
+
+const (
+	compiler          string = "source"
+	qualifiedString   string = "%s_%s"
+	genericString     string = "%s~%s~"
+	resultsString     string = "(%s)"
+	arrayString       string = "[%d]%s"
+	mapString         string = "map[%s]%s"
+	funcString        string = "func(%s)%s"
+	listSeparator     string = ", "
+	pointerPrefix     string = "*"
+	slicePrefix       string = "[]"
+	variadicPrefix    string = "..."
+	chanPrefix        string = "chan "
+	sendChanPrefix    string = "chan<- "
+	receiveChanPrefix string = "<-chan "
+	anyType           string = "any"
+	interfaceType     string = "interface"
+	structType        string = "struct"
+	unnamedParameter  string = "_"
+)
+
+// unsafeName matches the characters that cannot appear in class and namespace names.
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// Options controls the diagram drawn by Generate.
+type Options struct {
+	// ExportedOnly leaves out unexported types, fields and methods.
+	ExportedOnly bool
+	// Packages limits the drawn types to those of the packages with these import paths. The
+	// types of all the given packages are drawn when it is empty.
+	Packages []string
+	// MaxDepth also draws the types of other packages reached from the drawn types through
+	// fields and embedding, up to that many steps away. Only the types of the selected
+	// packages are drawn when it is zero.
+	MaxDepth int
+}
+
+// Load type-checks from source the packages with the given import paths, relative paths
+// being resolved from dir, along with the packages they import.
+func Load(dir string, importPaths ...string) ([]*types.Package, error) {
+	imp := importer.ForCompiler(token.NewFileSet(), compiler, nil).(types.ImporterFrom)
+
+	packages := make([]*types.Package, 0, len(importPaths))
+	for _, importPath := range importPaths {
+		pkg, err := imp.ImportFrom(importPath, dir, 0)
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, pkg)
+	}
+
+	return packages, nil
+}
+
+// generator holds the state of a diagram being drawn.
+type generator struct {
+	options    Options
+	diagram    *class.ClassDiagram
+	types      []*types.TypeName
+	classes    map[*types.TypeName]*class.Class
+	namespaces map[*types.Package]*class.Namespace
+	current    *types.Package
+}
+
+// Generate draws the types of packages.
+func Generate(packages []*types.Package, options Options) *class.ClassDiagram {
+	g := &generator{
+		options:    options,
+		diagram:    class.NewClassDiagram(),
+		classes:    make(map[*types.TypeName]*class.Class),
+		namespaces: make(map[*types.Package]*class.Namespace),
+	}
+
+	g.selectTypes(packages)
+	g.addClasses()
+	for _, obj := range g.types {
+		g.addMembers(obj)
+	}
+
+	return g.diagram
+}
+
+// selectTypes collects the named types of the selected packages, then those reached from
+// them within the maximum depth.
+func (g *generator) selectTypes(packages []*types.Package) {
+	selected := make(map[string]bool)
+	for _, importPath := range g.options.Packages {
+		selected[importPath] = true
+	}
+
+	depths := make(map[*types.TypeName]int)
+	for _, pkg := range packages {
+		if len(selected) > 0 && !selected[pkg.Path()] {
+			continue
+		}
+
+		scope := pkg.Scope()
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if _, seen := depths[obj]; !ok || seen || !g.drawn(obj) {
+				continue
+			}
+			depths[obj] = 0
+			g.types = append(g.types, obj)
+		}
+	}
+
+	for i := 0; i < len(g.types); i++ {
+		obj := g.types[i]
+		if depths[obj] >= g.options.MaxDepth {
+			continue
+		}
+
+		for _, reference := range g.references(obj) {
+			if _, seen := depths[reference]; seen || !g.drawn(reference) {
+				continue
+			}
+			depths[reference] = depths[obj] + 1
+			g.types = append(g.types, reference)
+		}
+	}
+}
+
+// drawn reports whether a type can be drawn as a class.
+func (g *generator) drawn(obj *types.TypeName) bool {
+	if obj.IsAlias() || obj.Pkg() == nil {
+		return false
+	}
+	if _, ok := obj.Type().(*types.Named); !ok {
+		return false
+	}
+
+	return !g.options.ExportedOnly || obj.Exported()
+}
+
+// references returns the named types embedded in a type or held by the fields drawn.
+func (g *generator) references(obj *types.TypeName) (referenced []*types.TypeName) {
+	seen := make(map[*types.TypeName]bool)
+	add := func(t types.Type) {
+		if named, _, ok := held(t); ok && !seen[named.Obj()] {
+			seen[named.Obj()] = true
+			referenced = append(referenced, named.Obj())
+		}
+	}
+
+	switch underlying := obj.Type().Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < underlying.NumFields(); i++ {
+			if field := underlying.Field(i); field.Embedded() || !g.options.ExportedOnly || field.Exported() {
+				add(field.Type())
+			}
+		}
+	case *types.Interface:
+		for i := 0; i < underlying.NumEmbeddeds(); i++ {
+			add(underlying.EmbeddedType(i))
+		}
+	}
+
+	return referenced
+}
+
+// held returns the named type held by a field of type t, and whether it is held by value:
+// neither through a pointer nor as an interface. The elements of collections are held.
+func held(t types.Type) (named *types.Named, byValue bool, ok bool) {
+	byValue = true
+	for {
+		switch current := t.(type) {
+		case *types.Named:
+			if types.IsInterface(current) {
+				byValue = false
+			}
+			return current, byValue, true
+		case *types.Pointer:
+			t, byValue = current.Elem(), false
+		case *types.Slice:
+			t = current.Elem()
+		case *types.Array:
+			t = current.Elem()
+		case *types.Map:
+			t = current.Elem()
+		case *types.Chan:
+			t = current.Elem()
+		default:
+			return nil, false, false
+		}
+	}
+}
+
+// collection reports whether a field type holds several values.
+func collection(t types.Type) bool {
+	switch t.(type) {
+	case *types.Slice, *types.Array, *types.Map, *types.Chan:
+		return true
+	}
+
+	return false
+}
+
+// addClasses creates the classes of the selected types in the namespaces of their
+// packages. Types whose names clash across packages are qualified by their package.
+func (g *generator) addClasses() {
+	counts := make(map[string]int)
+	packageNames := make(map[string]map[string]bool)
+	for _, obj := range g.types {
+		counts[obj.Name()]++
+		if packageNames[obj.Pkg().Name()] == nil {
+			packageNames[obj.Pkg().Name()] = make(map[string]bool)
+		}
+		packageNames[obj.Pkg().Name()][obj.Pkg().Path()] = true
+	}
+
+	for _, obj := range g.types {
+		pkg := obj.Pkg()
+		namespace, ok := g.namespaces[pkg]
+		if !ok {
+			name := pkg.Name()
+			if len(packageNames[name]) > 1 {
+				name = safeName(pkg.Path())
+			}
+			namespace = g.diagram.AddNamespace(name)
+			g.namespaces[pkg] = namespace
+		}
+
+		name := obj.Name()
+		if counts[name] > 1 {
+			name = fmt.Sprintf(qualifiedString, safeName(namespace.Name), obj.Name())
+		}
+		c := g.diagram.AddClass(name, namespace)
+		if name != obj.Name() {
+			c.SetLabel(obj.Name())
+		}
+		if types.IsInterface(obj.Type()) {
+			c.SetAnnotation(class.ClassAnnotationInterface)
+		}
+		g.classes[obj] = c
+	}
+}
+
+// safeName returns a name usable as a class or namespace name.
+func safeName(name string) string {
+	return strings.Trim(unsafeName.ReplaceAllString(name, "_"), "_")
+}
+
+// addMembers adds the fields, methods and relations of a class.
+func (g *generator) addMembers(obj *types.TypeName) {
+	c := g.classes[obj]
+	named := obj.Type().(*types.Named)
+	g.current = obj.Pkg()
+
+	switch underlying := named.Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < underlying.NumFields(); i++ {
+			g.addField(c, underlying.Field(i))
+		}
+	case *types.Interface:
+		for i := 0; i < underlying.NumEmbeddeds(); i++ {
+			if embedded, _, ok := held(underlying.EmbeddedType(i)); ok && g.classes[embedded.Obj()] != nil {
+				g.inherit(g.classes[embedded.Obj()], c)
+			}
+		}
+		for i := 0; i < underlying.NumExplicitMethods(); i++ {
+			if method := g.addMethod(c, underlying.ExplicitMethod(i)); method != nil {
+				method.SetClassifier(class.MethodClassifierAbstract)
+			}
+		}
+		return
+	}
+
+	for i := 0; i < named.NumMethods(); i++ {
+		g.addMethod(c, named.Method(i))
+	}
+}
+
+// addField adds a field to a class, or the relation it stands for.
+func (g *generator) addField(c *class.Class, field *types.Var) {
+	target, byValue, ok := held(field.Type())
+	var targetClass *class.Class
+	if ok {
+		targetClass = g.classes[target.Obj()]
+	}
+
+	if field.Embedded() && targetClass != nil {
+		g.inherit(targetClass, c)
+		return
+	}
+	if g.options.ExportedOnly && !field.Exported() {
+		return
+	}
+
+	f := c.AddField(field.Name(), g.typeString(field.Type()))
+	if !field.Exported() {
+		f.SetVisibility(class.FieldVisibilityPrivate)
+	}
+	if targetClass == nil {
+		return
+	}
+
+	relation := g.diagram.AddRelation(c, targetClass)
+	relation.RelationToClassA = class.RelationTypeAggregation
+	if byValue {
+		relation.RelationToClassA = class.RelationTypeComposition
+	}
+	if collection(field.Type()) {
+		relation.CardinalityToClassB = class.RelationCardinalityMany
+	}
+	relation.Label = field.Name()
+}
+
+// inherit adds an inheritance relation from a derived class to an embedded one.
+func (g *generator) inherit(base *class.Class, derived *class.Class) {
+	relation := g.diagram.AddRelation(base, derived)
+	relation.RelationToClassA = class.RelationTypeInheritanceLeft
+}
+
+// addMethod adds a method to a class, unless it is filtered out.
+func (g *generator) addMethod(c *class.Class, fn *types.Func) *class.Method {
+	if g.options.ExportedOnly && !fn.Exported() {
+		return nil
+	}
+
+	method := c.AddMethod(fn.Name())
+	if !fn.Exported() {
+		method.SetVisibility(class.MethodVisibilityPrivate)
+	}
+
+	signature := fn.Type().(*types.Signature)
+	params := signature.Params()
+	for i := 0; i < params.Len(); i++ {
+		name := params.At(i).Name()
+		if name == "" {
+			name = unnamedParameter
+		}
+		paramType := g.typeString(params.At(i).Type())
+		if signature.Variadic() && i == params.Len()-1 {
+			paramType = variadicPrefix + strings.TrimPrefix(paramType, slicePrefix)
+		}
+		method.AddParameter(name, paramType)
+	}
+	method.SetReturnType(g.results(signature.Results()))
+
+	return method
+}
+
+// results returns the result types of a signature: nothing, a type, or a parenthesized list.
+func (g *generator) results(results *types.Tuple) string {
+	switch results.Len() {
+	case 0:
+		return ""
+	case 1:
+		return g.typeString(results.At(0).Type())
+	}
+
+	list := make([]string, results.Len())
+	for i := range list {
+		list[i] = g.typeString(results.At(i).Type())
+	}
+
+	return fmt.Sprintf(resultsString, strings.Join(list, listSeparator))
+}
+
+// typeString returns a type in Go syntax, with the type arguments of generic types between
+// tildes as Mermaid expects, anonymous structs and interfaces by kind, and named types of
+// other packages qualified by their package name.
+func (g *generator) typeString(t types.Type) string {
+	switch current := t.(type) {
+	case *types.Basic:
+		return current.Name()
+	case *types.Named:
+		name := current.Obj().Name()
+		if pkg := current.Obj().Pkg(); pkg != nil && pkg != g.current {
+			name = pkg.Name() + "." + name
+		}
+		if args := current.TypeArgs(); args != nil && args.Len() > 0 {
+			list := make([]string, args.Len())
+			for i := range list {
+				list[i] = g.typeString(args.At(i))
+			}
+			name = fmt.Sprintf(genericString, name, strings.Join(list, listSeparator))
+		}
+		return name
+	case *types.TypeParam:
+		return current.Obj().Name()
+	case *types.Pointer:
+		return pointerPrefix + g.typeString(current.Elem())
+	case *types.Slice:
+		return slicePrefix + g.typeString(current.Elem())
+	case *types.Array:
+		return fmt.Sprintf(arrayString, current.Len(), g.typeString(current.Elem()))
+	case *types.Map:
+		return fmt.Sprintf(mapString, g.typeString(current.Key()), g.typeString(current.Elem()))
+	case *types.Chan:
+		switch current.Dir() {
+		case types.SendOnly:
+			return sendChanPrefix + g.typeString(current.Elem())
+		case types.RecvOnly:
+			return receiveChanPrefix + g.typeString(current.Elem())
+		}
+		return chanPrefix + g.typeString(current.Elem())
+	case *types.Signature:
+		params := make([]string, current.Params().Len())
+		for i := range params {
+			params[i] = g.typeString(current.Params().At(i).Type())
+		}
+		results := g.results(current.Results())
+		if results != "" {
+			results = " " + results
+		}
+		return fmt.Sprintf(funcString, strings.Join(params, listSeparator), results)
+	case *types.Interface:
+		if current.Empty() {
+			return anyType
+		}
+		return interfaceType
+	case *types.Struct:
+		return structType
+	}
+
+	return strconv.Quote(types.TypeString(t, nil))
+}
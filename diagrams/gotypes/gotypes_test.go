@@ -0,0 +1,219 @@
+package gotypes
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/testutils"
+)
+
+// loadShop type-checks the test package.
+func loadShop(t *testing.T) []*types.Package {
+	t.Helper()
+
+	packages, err := Load(".", "./testdata/shop")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	return packages
+}
+
+// check type-checks a package from source, importing the packages already checked.
+func check(t *testing.T, path string, source string, imported ...*types.Package) *types.Package {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path+".go", source, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := types.Config{Importer: importerFunc(func(importPath string) (*types.Package, error) {
+		for _, pkg := range imported {
+			if pkg.Path() == importPath {
+				return pkg, nil
+			}
+		}
+		return importer.Default().Import(importPath)
+	})}
+	pkg, err := config.Check(path, fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pkg
+}
+
+// importerFunc adapts a function to types.Importer.
+type importerFunc func(path string) (*types.Package, error)
+
+// Import imports a package.
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
+
+func TestGenerate(t *testing.T) {
+	want := "classDiagram\n" +
+		"    direction TB\n" +
+		"    namespace shop{\n" +
+		"        class Customer{\n" +
+		"            +string Name\n" +
+		"        }\n" +
+		"        class Entity{\n" +
+		"            +int ID\n" +
+		"            -int version\n" +
+		"        }\n" +
+		"        class Line{\n" +
+		"            +string Product\n" +
+		"            +int Count\n" +
+		"        }\n" +
+		"        class Order{\n" +
+		"            +[]Line Lines\n" +
+		"            +*Customer Customer\n" +
+		"            -Store store\n" +
+		"            -sync.Mutex mu\n" +
+		"            -map[string]string tags\n" +
+		"            -func(string) error notify\n" +
+		"            +Total() int\n" +
+		"            -add(lines:...Line) \n" +
+		"        }\n" +
+		"        class Page{\n" +
+		"            +[]T Items\n" +
+		"            +*Page~T~ Next\n" +
+		"        }\n" +
+		"        class Results{\n" +
+		"            +Page~Order~ Last\n" +
+		"        }\n" +
+		"        class Store{\n" +
+		"        <<Interface>>\n" +
+		"            +Find(id:int)* (*Entity, bool)\n" +
+		"            +Save(entity:*Entity)* error\n" +
+		"        }\n" +
+		"        class status{\n" +
+		"        }\n" +
+		"    }\n" +
+		"    Entity <|-- Customer\n" +
+		"    Entity <|-- Order\n" +
+		"    Order *--\"*\" Line : Lines\n" +
+		"    Order o-- Customer : Customer\n" +
+		"    Order o-- Store : store\n" +
+		"    Page o-- Page : Next\n" +
+		"    Results *-- Page : Last\n"
+
+	if got := testutils.DiagramBody(t, Generate(loadShop(t), Options{}).String()); got != want {
+		t.Errorf("Generate() = %q, want %q", got, want)
+	}
+}
+
+func TestGenerate_Options(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  Options
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "Exported only",
+			options:  Options{ExportedOnly: true},
+			contains: []string{"class Order{\n            +[]Line Lines\n            +*Customer Customer\n            +Total() int\n        }\n"},
+			excludes: []string{"status", "version", "store", "add("},
+		},
+		{
+			name:     "Depth reaches other packages",
+			options:  Options{MaxDepth: 1},
+			contains: []string{"namespace sync{\n        class Mutex{\n", "namespace io{\n        class Closer{\n        <<Interface>>\n            +Close()* error\n", "Closer <|-- Store\n"},
+		},
+		{
+			name:     "Exported only does not follow unexported fields",
+			options:  Options{ExportedOnly: true, MaxDepth: 1},
+			contains: []string{"class Closer{"},
+			excludes: []string{"Mutex"},
+		},
+		{
+			name:     "Package subset",
+			options:  Options{Packages: []string{"example.com/other"}},
+			excludes: []string{"namespace", "class "},
+		},
+		{
+			name:     "Selected package",
+			options:  Options{Packages: []string{"./testdata/shop"}},
+			contains: []string{"namespace shop{"},
+		},
+	}
+
+	packages := loadShop(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := testutils.DiagramBody(t, Generate(packages, tt.options).String())
+
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("Generate() missing %q in:\n%s", want, got)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("Generate() contains %q in:\n%s", unwanted, got)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_NameClashes(t *testing.T) {
+	first := check(t, "example.com/a/model", "package model\ntype Config struct{ Name string }\n")
+	second := check(t, "example.com/b/model", "package model\nimport other \"example.com/a/model\"\ntype Config struct{ Base other.Config }\n", first)
+
+	got := testutils.DiagramBody(t, Generate([]*types.Package{first, second}, Options{}).String())
+
+	for _, want := range []string{
+		"namespace example_com_a_model{\n        class example_com_a_model_Config[\"Config\"]{\n",
+		"namespace example_com_b_model{\n        class example_com_b_model_Config[\"Config\"]{\n            +model.Config Base\n",
+		"example_com_b_model_Config *-- example_com_a_model_Config : Base\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Generate() missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestGenerate_Relations(t *testing.T) {
+	pkg := check(t, "example.com/fleet", "package fleet\n"+
+		"type Car struct{}\n"+
+		"type Driver interface{ Drive() }\n"+
+		"type Fleet struct {\n"+
+		"\tFlagship Car\n"+
+		"\tSpare *Car\n"+
+		"\tCars []Car\n"+
+		"\tRented []*Car\n"+
+		"\tByPlate map[string]Car\n"+
+		"\tDrivers []Driver\n"+
+		"}\n")
+
+	got := testutils.DiagramBody(t, Generate([]*types.Package{pkg}, Options{}).String())
+
+	for _, want := range []string{
+		"Fleet *-- Car : Flagship\n",
+		"Fleet o-- Car : Spare\n",
+		"Fleet *--\"*\" Car : Cars\n",
+		"Fleet o--\"*\" Car : Rented\n",
+		"Fleet *--\"*\" Car : ByPlate\n",
+		"Fleet o--\"*\" Driver : Drivers\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Generate() missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestLoad_Error(t *testing.T) {
+	if _, err := Load(".", "./testdata/missing"); err == nil {
+		t.Error("Load() of a missing package error = nil, want an error")
+	}
+}
@@ -0,0 +1,68 @@
+// Package shop is a fixture for the class diagram generator.
+package shop
+
+import (
+	"io"
+	"sync"
+)
+
+// Entity is embedded by the stored types.
+type Entity struct {
+	ID      int
+	version int
+}
+
+// Store saves entities.
+type Store interface {
+	io.Closer
+	Save(entity *Entity) error
+	Find(id int) (*Entity, bool)
+}
+
+// Line is a line of an order.
+type Line struct {
+	Product string
+	Count   int
+}
+
+// Order is a customer order.
+type Order struct {
+	Entity
+	Lines    []Line
+	Customer *Customer
+	store    Store
+	mu       sync.Mutex
+	tags     map[string]string
+	notify   func(string) error
+}
+
+// Customer places orders.
+type Customer struct {
+	Entity
+	Name string
+}
+
+// Total returns the number of items of the order.
+func (o *Order) Total() int {
+	return 0
+}
+
+// add adds lines to the order.
+func (o *Order) add(lines ...Line) {}
+
+// Page is a page of results.
+type Page[T any] struct {
+	Items []T
+	Next  *Page[T]
+}
+
+// Orders is a page of orders.
+type Orders = Page[Order]
+
+// Results holds the last page.
+type Results struct {
+	Last Page[Order]
+}
+
+// status is an unexported type.
+type status int
@@ -0,0 +1,290 @@
+// Package markdown keeps the diagrams embedded in markdown documents up to date.
+//
+// A diagram is embedded between two marker comments naming it:
+//
+//	<!-- gomermaid:begin name=deps -->
+//	```mermaid
+//	...
+//	```
+//	<!-- gomermaid:end -->
+//
+// Updating a document replaces the mermaid fenced block between the markers with the
+// current rendering of the named diagram and preserves everything else, including text
+// placed between the markers around the block. A region without a mermaid block gets one
+// appended. Markers inside fenced code blocks are ignored, so documents can show them.
+//
+// Scan checks every mermaid fenced block of the markdown files of a file system instead:
+// it parses the blocks with the parser of their diagram package and reports syntax and
+// validation problems at lines of the files.
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// Errors returned for invalid marker blocks and unknown diagrams.
+var (
+	ErrUnclosedBlock  = errors.New("block has no end marker")
+	ErrUnexpectedEnd  = errors.New("end marker without begin marker")
+	ErrNestedBlock    = errors.New("begin marker inside a block")
+	ErrUnknownDiagram = errors.New("unknown diagram")
+)
+
+const (
+	errorLineString  string = "line %d: %v"
+	errorFileString  string = "%s:%d: %v"
+	namedErrorString string = "%w %q"
+
+	crlf          string = "\r\n"
+	lf            string = "\n"
+	mermaidInfo   string = "mermaid"
+	fenceBacktick string = "```"
+	fenceTilde    string = "~~~"
+)
+
+// Marker comment patterns. The name of an end marker is optional.
+var (
+	beginMarker = regexp.MustCompile(`^\s*<!--\s*gomermaid:begin\s+name=(\S+?)\s*-->\s*$`)
+	endMarker   = regexp.MustCompile(`^\s*<!--\s*gomermaid:end(?:\s+name=\S+?)?\s*-->\s*$`)
+)
+
+// Error is an invalid marker block or unknown diagram at a line of a document. File is
+// empty for documents that were not read from a file.
+type Error struct {
+	File string
+	Line int
+	Err  error
+}
+
+// Error returns the location and reason of the error.
+func (e *Error) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf(errorLineString, e.Line, e.Err)
+	}
+
+	return fmt.Sprintf(errorFileString, e.File, e.Line, e.Err)
+}
+
+// Unwrap returns the reason of the error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Block is a marker block of a document.
+type Block struct {
+	// Name is the name of the diagram embedded in the block.
+	Name string
+	// Line is the 1-based line of the begin marker.
+	Line int
+	// Content is the text between the markers.
+	Content string
+
+	start int
+	end   int
+}
+
+// Blocks returns the marker blocks of a document in document order.
+func Blocks(content string) ([]Block, error) {
+	blocks := make([]Block, 0)
+	var open *Block
+	fence := ""
+
+	offset := 0
+	for number, line := range strings.SplitAfter(content, lf) {
+		lineStart, text := offset, strings.TrimRight(line, crlf)
+		offset += len(line)
+
+		if open == nil {
+			if fence = nextFence(fence, text); fence != "" {
+				continue
+			}
+		}
+
+		switch {
+		case beginMarker.MatchString(text):
+			if open != nil {
+				return nil, &Error{Line: number + 1, Err: ErrNestedBlock}
+			}
+			name := beginMarker.FindStringSubmatch(text)[1]
+			open = &Block{Name: name, Line: number + 1, start: offset}
+		case endMarker.MatchString(text):
+			if open == nil {
+				return nil, &Error{Line: number + 1, Err: ErrUnexpectedEnd}
+			}
+			open.end = lineStart
+			open.Content = content[open.start:open.end]
+			blocks = append(blocks, *open)
+			open = nil
+		}
+	}
+
+	if open != nil {
+		return nil, &Error{Line: open.Line, Err: ErrUnclosedBlock}
+	}
+
+	return blocks, nil
+}
+
+// Update returns the document with the block of every diagram replaced by its current
+// rendering, and the blocks whose content changed. Every block must name one of the
+// diagrams.
+func Update(content string, diagrams map[string]basediagram.Diagram) (updated string, stale []Block, err error) {
+	blocks, err := Blocks(content)
+	if err != nil {
+		return content, nil, err
+	}
+
+	newline := lf
+	if strings.Contains(content, crlf) {
+		newline = crlf
+	}
+
+	var sb strings.Builder
+	previous := 0
+	for _, block := range blocks {
+		diagram, ok := diagrams[block.Name]
+		if !ok {
+			return content, nil, &Error{Line: block.Line, Err: fmt.Errorf(namedErrorString, ErrUnknownDiagram, block.Name)}
+		}
+
+		replaced := replaceFence(block.Content, fenced(diagram, newline), newline)
+		if replaced != block.Content {
+			stale = append(stale, block)
+		}
+
+		sb.WriteString(content[previous:block.start])
+		sb.WriteString(replaced)
+		previous = block.end
+	}
+	sb.WriteString(content[previous:])
+
+	return sb.String(), stale, nil
+}
+
+// UpdateFile updates the blocks of a markdown file, see Update, and returns the blocks that
+// were stale. The file is only written when a block changed.
+func UpdateFile(path string, diagrams map[string]basediagram.Diagram) (stale []Block, err error) {
+	return updateFile(path, diagrams, true)
+}
+
+// CheckFile returns the blocks of a markdown file that Update would change, without writing
+// the file.
+func CheckFile(path string, diagrams map[string]basediagram.Diagram) (stale []Block, err error) {
+	return updateFile(path, diagrams, false)
+}
+
+// updateFile updates the blocks of a markdown file, writing the changes if asked to.
+func updateFile(path string, diagrams map[string]basediagram.Diagram, write bool) (stale []Block, err error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, stale, err := Update(string(data), diagrams)
+	if err != nil {
+		var blockErr *Error
+		if errors.As(err, &blockErr) {
+			blockErr.File = path
+		}
+		return nil, err
+	}
+
+	if write && len(stale) > 0 {
+		err = os.WriteFile(path, []byte(updated), info.Mode().Perm())
+	}
+
+	return stale, err
+}
+
+// fenced returns the rendering of a diagram in a mermaid fenced block.
+func fenced(diagram basediagram.Diagram, newline string) string {
+	source := strings.TrimRight(basediagram.StripFence(diagram.String()), lf)
+
+	fencer := basediagram.NewMarkdownFencer()
+	fencer.EnableMarkdownFence()
+	block := fencer.WrapWithFence(source)
+
+	if newline != lf {
+		block = strings.ReplaceAll(block, lf, newline)
+	}
+
+	return block
+}
+
+// replaceFence replaces the first mermaid fenced block of a region with the given block, or
+// appends the block to a region without one.
+func replaceFence(region string, block string, newline string) string {
+	offset := 0
+	start := -1
+	open := ""
+
+	for _, line := range strings.SplitAfter(region, lf) {
+		lineStart, text := offset, strings.TrimRight(line, crlf)
+		offset += len(line)
+
+		if start < 0 {
+			if fence, info := openingFence(text); fence != "" && info == mermaidInfo {
+				start, open = lineStart, fence
+			}
+			continue
+		}
+
+		if closesFence(open, text) {
+			return region[:start] + block + region[offset:]
+		}
+	}
+
+	if strings.TrimSpace(region) == "" {
+		return block
+	}
+	if !strings.HasSuffix(region, lf) {
+		region += newline
+	}
+
+	return region + block
+}
+
+// nextFence returns the fence of the fenced code block a line leaves the document in: the
+// fence opened by the line, the fence still open, or "" when the line closes it.
+func nextFence(open string, line string) string {
+	if open == "" {
+		fence, _ := openingFence(line)
+		return fence
+	}
+	if closesFence(open, line) {
+		return ""
+	}
+
+	return open
+}
+
+// openingFence returns the fence and info string of a line opening a fenced code block, or
+// "" if the line opens none.
+func openingFence(line string) (fence string, info string) {
+	text := strings.TrimSpace(line)
+
+	for _, marker := range []string{fenceBacktick, fenceTilde} {
+		if strings.HasPrefix(text, marker) {
+			rest := strings.TrimLeft(text, marker[:1])
+			return text[:len(text)-len(rest)], strings.TrimSpace(rest)
+		}
+	}
+
+	return "", ""
+}
+
+// closesFence reports whether a line closes the fenced code block opened by the fence.
+func closesFence(open string, line string) bool {
+	text := strings.TrimSpace(line)
+	return strings.HasPrefix(text, open) && strings.TrimLeft(text, open[:1]) == ""
+}
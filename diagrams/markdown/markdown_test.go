@@ -0,0 +1,203 @@
+package markdown
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// source is a diagram with fixed Mermaid syntax.
+type source string
+
+func (s source) String() string {
+	return string(s)
+}
+
+const (
+	deps      = source("flowchart TB\n    a --> b\n")
+	depsFence = "```mermaid\nflowchart TB\n    a --> b\n```\n"
+)
+
+func TestBlocks(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantNames []string
+		wantLines []int
+		wantErr   error
+		wantMsg   string
+	}{
+		{
+			name:      "Blocks",
+			content:   "# Title\n<!-- gomermaid:begin name=deps -->\nold\n<!-- gomermaid:end -->\n\n<!--gomermaid:begin name=flow-->\n<!-- gomermaid:end name=flow -->\n",
+			wantNames: []string{"deps", "flow"},
+			wantLines: []int{2, 6},
+		},
+		{
+			name:      "Markers in code blocks are ignored",
+			content:   "````md\n<!-- gomermaid:begin name=x -->\n```\n````\n<!-- gomermaid:begin name=deps -->\n<!-- gomermaid:end -->\n~~~\n<!-- gomermaid:end -->\n~~~\n",
+			wantNames: []string{"deps"},
+			wantLines: []int{5},
+		},
+		{
+			name:    "Unclosed block",
+			content: "text\n<!-- gomermaid:begin name=deps -->\n",
+			wantErr: ErrUnclosedBlock,
+			wantMsg: "line 2: block has no end marker",
+		},
+		{
+			name:    "End without begin",
+			content: "<!-- gomermaid:end -->\n",
+			wantErr: ErrUnexpectedEnd,
+			wantMsg: "line 1: end marker without begin marker",
+		},
+		{
+			name:    "Nested block",
+			content: "<!-- gomermaid:begin name=a -->\n<!-- gomermaid:begin name=b -->\n",
+			wantErr: ErrNestedBlock,
+			wantMsg: "line 2: begin marker inside a block",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks, err := Blocks(tt.content)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || err.Error() != tt.wantMsg {
+					t.Fatalf("Blocks() error = %v, want %q", err, tt.wantMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Blocks() error = %v", err)
+			}
+
+			if len(blocks) != len(tt.wantNames) {
+				t.Fatalf("Blocks() = %d blocks, want %d", len(blocks), len(tt.wantNames))
+			}
+			for i, block := range blocks {
+				if block.Name != tt.wantNames[i] || block.Line != tt.wantLines[i] {
+					t.Errorf("block %d = %s at line %d, want %s at line %d", i, block.Name, block.Line, tt.wantNames[i], tt.wantLines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		diagrams  map[string]basediagram.Diagram
+		want      string
+		wantStale []string
+		wantErr   error
+	}{
+		{
+			name:      "Replace the fenced block only",
+			content:   "# Deps\n<!-- gomermaid:begin name=deps -->\nCaption\n\n```mermaid\nflowchart LR\n```\n\nFooter\n<!-- gomermaid:end -->\nAfter\n",
+			diagrams:  map[string]basediagram.Diagram{"deps": deps},
+			want:      "# Deps\n<!-- gomermaid:begin name=deps -->\nCaption\n\n" + depsFence + "\nFooter\n<!-- gomermaid:end -->\nAfter\n",
+			wantStale: []string{"deps"},
+		},
+		{
+			name:     "Up to date",
+			content:  "<!-- gomermaid:begin name=deps -->\n" + depsFence + "<!-- gomermaid:end -->\n",
+			diagrams: map[string]basediagram.Diagram{"deps": deps},
+			want:     "<!-- gomermaid:begin name=deps -->\n" + depsFence + "<!-- gomermaid:end -->\n",
+		},
+		{
+			name:      "Empty block",
+			content:   "<!-- gomermaid:begin name=deps -->\n<!-- gomermaid:end -->",
+			diagrams:  map[string]basediagram.Diagram{"deps": deps},
+			want:      "<!-- gomermaid:begin name=deps -->\n" + depsFence + "<!-- gomermaid:end -->",
+			wantStale: []string{"deps"},
+		},
+		{
+			name:      "Block without a mermaid fence",
+			content:   "<!-- gomermaid:begin name=deps -->\nSee below.\n```go\nx := 1\n```\n<!-- gomermaid:end -->\n",
+			diagrams:  map[string]basediagram.Diagram{"deps": deps},
+			want:      "<!-- gomermaid:begin name=deps -->\nSee below.\n```go\nx := 1\n```\n" + depsFence + "<!-- gomermaid:end -->\n",
+			wantStale: []string{"deps"},
+		},
+		{
+			name:      "Fenced diagram and CRLF line endings",
+			content:   "Intro\r\n<!-- gomermaid:begin name=deps -->\r\n<!-- gomermaid:end -->\r\n",
+			diagrams:  map[string]basediagram.Diagram{"deps": source("```mermaid\nflowchart TB\n    a --> b\n\n```\n")},
+			want:      "Intro\r\n<!-- gomermaid:begin name=deps -->\r\n" + strings.ReplaceAll(depsFence, "\n", "\r\n") + "<!-- gomermaid:end -->\r\n",
+			wantStale: []string{"deps"},
+		},
+		{
+			name:     "Unknown diagram",
+			content:  "<!-- gomermaid:begin name=deps -->\n<!-- gomermaid:end -->\n",
+			diagrams: map[string]basediagram.Diagram{},
+			want:     "<!-- gomermaid:begin name=deps -->\n<!-- gomermaid:end -->\n",
+			wantErr:  ErrUnknownDiagram,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, stale, err := Update(tt.content, tt.diagrams)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Update() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Update() = %q, want %q", got, tt.want)
+			}
+
+			names := make([]string, 0, len(stale))
+			for _, block := range stale {
+				names = append(names, block.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.wantStale, ",") {
+				t.Errorf("Update() stale = %v, want %v", names, tt.wantStale)
+			}
+		})
+	}
+}
+
+func TestUpdateFileAndCheckFile(t *testing.T) {
+	stale := "<!-- gomermaid:begin name=deps -->\n<!-- gomermaid:end -->\n"
+	path := filepath.Join(t.TempDir(), "README.md")
+	if err := os.WriteFile(path, []byte(stale), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	diagrams := map[string]basediagram.Diagram{"deps": deps}
+
+	blocks, err := CheckFile(path, diagrams)
+	if err != nil || len(blocks) != 1 {
+		t.Fatalf("CheckFile() = %v, %v, want one stale block", blocks, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != stale {
+		t.Errorf("CheckFile() wrote the file: %q", data)
+	}
+
+	if blocks, err = UpdateFile(path, diagrams); err != nil || len(blocks) != 1 {
+		t.Fatalf("UpdateFile() = %v, %v, want one stale block", blocks, err)
+	}
+	data, _ := os.ReadFile(path)
+	if want := "<!-- gomermaid:begin name=deps -->\n" + depsFence + "<!-- gomermaid:end -->\n"; string(data) != want {
+		t.Errorf("UpdateFile() wrote %q, want %q", data, want)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("UpdateFile() changed the mode to %v", info.Mode().Perm())
+	}
+
+	if blocks, err = CheckFile(path, diagrams); err != nil || len(blocks) != 0 {
+		t.Errorf("CheckFile() after update = %v, %v, want no stale block", blocks, err)
+	}
+
+	_, err = CheckFile(path, map[string]basediagram.Diagram{})
+	if want := path + `:1: unknown diagram "deps"`; err == nil || err.Error() != want {
+		t.Errorf("CheckFile() error = %v, want %q", err, want)
+	}
+
+	if _, err = UpdateFile(filepath.Join(t.TempDir(), "missing.md"), diagrams); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("UpdateFile() error = %v, want %v", err, os.ErrNotExist)
+	}
+}
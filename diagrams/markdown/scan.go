@@ -0,0 +1,293 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/class"
+	"github.com/TyphonHill/go-mermaid/diagrams/entityrelationship"
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/sequence"
+	"github.com/TyphonHill/go-mermaid/diagrams/serialize"
+	"github.com/TyphonHill/go-mermaid/diagrams/state"
+	"github.com/TyphonHill/go-mermaid/diagrams/timeline"
+	"github.com/TyphonHill/go-mermaid/diagrams/userjourney"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// Errors returned for diagrams Mermaid would refuse to render.
+var (
+	ErrTextSize = errors.New("text size limit exceeded")
+	ErrEdges    = errors.New("edge limit exceeded")
+)
+
+const (
+	limitErrorString string = "%w: %d, more than %d"
+	hiddenPrefix     string = "."
+)
+
+// markdownExtensions are the extensions of the files Scan reads.
+var markdownExtensions = []string{".md", ".markdown"}
+
+// ScanOptions controls Scan.
+type ScanOptions struct {
+	// Canonical asks for the content of every file with its diagrams in canonical form, see
+	// File.Canonical.
+	Canonical bool
+}
+
+// Fence is a mermaid fenced block of a markdown file.
+type Fence struct {
+	// Line is the 1-based line of the opening fence.
+	Line int
+	// Type is the diagram type keyword, such as "flowchart", or "" for an empty block.
+	Type string
+	// Source is the text between the fences.
+	Source string
+	// Diagram is the parsed diagram, or nil when the block has a problem or holds a type of
+	// diagram that is not parsed.
+	Diagram basediagram.Diagram
+
+	start int
+	end   int
+	rule  string
+}
+
+// File is a scanned markdown file.
+type File struct {
+	// Path is the slash-separated path of the file in the scanned file system.
+	Path string
+	// Fences holds the mermaid blocks of the file in document order.
+	Fences []Fence
+	// Problems holds the syntax and validation problems of the blocks, one for each
+	// unsupported statement. Their lines are lines of the file, and they wrap
+	// basediagram.ErrSyntax, basediagram.ErrUnsupported, the element errors of the diagram
+	// packages, ErrTextSize or ErrEdges.
+	Problems []*Error
+	// Canonical is the content of the file with the source of every parsed diagram replaced
+	// by its canonical form, see serialize.Format. Blocks with problems, blocks that are not
+	// parsed and blocks whose rendering does not parse back to itself are kept. It is only
+	// set when ScanOptions.Canonical is.
+	Canonical string
+	// Stale holds the blocks whose canonical form differs from their source. It is only set
+	// when ScanOptions.Canonical is.
+	Stale []Fence
+}
+
+// Scan reads every markdown file of a file system, skipping hidden directories, and checks
+// the mermaid blocks of the files. Only files with mermaid blocks are returned, in lexical
+// order. The returned error is a problem reading the file system; the problems of the
+// diagrams are reported in the files.
+func Scan(fsys fs.FS, options ScanOptions) ([]File, error) {
+	files := make([]File, 0)
+
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if name != "." && strings.HasPrefix(entry.Name(), hiddenPrefix) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !isMarkdown(name) {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		if file := ScanDocument(name, string(data), options); len(file.Fences) > 0 {
+			files = append(files, file)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// ScanDocument checks the mermaid blocks of the content of a markdown file, see Scan. The
+// path is only used to report the problems.
+func ScanDocument(filePath string, content string, options ScanOptions) File {
+	file := File{Path: filePath, Fences: fences(content)}
+
+	for i := range file.Fences {
+		fence := &file.Fences[i]
+		if err := fence.check(); err != nil {
+			for _, problem := range basediagram.Split(err) {
+				file.Problems = append(file.Problems, fence.problem(filePath, problem))
+			}
+		}
+	}
+
+	if options.Canonical {
+		file.Canonical, file.Stale = canonical(content, file.Fences)
+	}
+
+	return file
+}
+
+// fences returns the mermaid blocks of a document. Blocks inside other fenced code blocks
+// are ignored, and a block left open ends with the document.
+func fences(content string) []Fence {
+	found := make([]Fence, 0)
+	var open *Fence
+	fence := ""
+
+	offset := 0
+	for number, line := range strings.SplitAfter(content, lf) {
+		lineStart, text := offset, strings.TrimRight(line, crlf)
+		offset += len(line)
+
+		if fence == "" {
+			var info string
+			if fence, info = openingFence(text); fence != "" && info == mermaidInfo {
+				open = &Fence{Line: number + 1, start: offset, rule: text[:len(text)-len(strings.TrimLeft(text, " \t"))]}
+			}
+			continue
+		}
+
+		if closesFence(fence, text) {
+			fence = ""
+			if open != nil {
+				open.end = lineStart
+				found = append(found, *open)
+				open = nil
+			}
+		}
+	}
+
+	if open != nil {
+		open.end = len(content)
+		found = append(found, *open)
+	}
+	for i := range found {
+		found[i].Source = content[found[i].start:found[i].end]
+		found[i].Type = basediagram.SourceKeyword(found[i].Source)
+	}
+
+	return found
+}
+
+// check parses the block with the parser of its diagram type and checks the diagram against
+// the limits of its configuration. Blocks of the types no package parses, such as pie
+// charts, are not checked.
+func (f *Fence) check() error {
+	if f.Type != "" && serialize.MermaidType(f.Type) == "" {
+		return nil
+	}
+
+	diagram, err := serialize.Parse(f.Source)
+	if err != nil {
+		return err
+	}
+
+	edges, config := limits(diagram)
+	if config != nil {
+		if size := len(f.Source); size > config.MaxTextSize() {
+			return fmt.Errorf(limitErrorString, ErrTextSize, size, config.MaxTextSize())
+		}
+		if edges > config.MaxEdges() {
+			return fmt.Errorf(limitErrorString, ErrEdges, edges, config.MaxEdges())
+		}
+	}
+	f.Diagram = diagram
+
+	return nil
+}
+
+// problem returns the error of a block located at its line of the file. Lines of syntax
+// errors count from the line after the opening fence.
+func (f *Fence) problem(filePath string, err error) *Error {
+	var syntaxErr *basediagram.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return &Error{File: filePath, Line: f.Line + syntaxErr.Line, Err: syntaxErr.Err}
+	}
+
+	return &Error{File: filePath, Line: f.Line, Err: err}
+}
+
+// canonical returns a document with the source of every parsed diagram replaced by its
+// canonical form, indented like the opening fence, and the blocks that changed.
+func canonical(content string, fences []Fence) (updated string, stale []Fence) {
+	newline := lf
+	if strings.Contains(content, crlf) {
+		newline = crlf
+	}
+
+	var sb strings.Builder
+	previous := 0
+	for _, fence := range fences {
+		if fence.Diagram == nil {
+			continue
+		}
+
+		formatted, err := serialize.Format(fence.Source)
+		if err != nil || formatted == fence.Source {
+			continue
+		}
+
+		var source strings.Builder
+		for _, line := range strings.Split(strings.TrimSuffix(formatted, lf), lf) {
+			if line != "" {
+				source.WriteString(fence.rule)
+			}
+			source.WriteString(line)
+			source.WriteString(newline)
+		}
+		if source.String() == fence.Source {
+			continue
+		}
+
+		stale = append(stale, fence)
+		sb.WriteString(content[previous:fence.start])
+		sb.WriteString(source.String())
+		previous = fence.end
+	}
+	sb.WriteString(content[previous:])
+
+	return sb.String(), stale
+}
+
+// limits returns the number of edges of a diagram and the configuration holding its limits.
+func limits(diagram basediagram.Diagram) (edges int, config *basediagram.ConfigurationProperties) {
+	switch d := diagram.(type) {
+	case *flowchart.Flowchart:
+		return len(d.Links()), &d.Config.ConfigurationProperties
+	case *state.Diagram:
+		return len(d.Transitions), &d.Config.ConfigurationProperties
+	case *class.ClassDiagram:
+		return len(d.Relations()), &d.Config.ConfigurationProperties
+	case *entityrelationship.Diagram:
+		return len(d.Relationships), &d.Config.ConfigurationProperties
+	case *sequence.Diagram:
+		return 0, &d.Config.ConfigurationProperties
+	case *timeline.Diagram:
+		return 0, &d.Config.ConfigurationProperties
+	case *userjourney.Diagram:
+		return 0, &d.Config.ConfigurationProperties
+	}
+
+	return 0, nil
+}
+
+// isMarkdown reports whether a file name has a markdown extension.
+func isMarkdown(name string) bool {
+	extension := path.Ext(name)
+	for _, candidate := range markdownExtensions {
+		if strings.EqualFold(extension, candidate) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,217 @@
+package markdown
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+const (
+	flowSource    = "flowchart TB\n    A --> B\n"
+	flowCanonical = "flowchart TB\n    A@{ shape: rect, label: \"A\"}\n    B@{ shape: rect, label: \"B\"}\n    A --> B\n"
+)
+
+func TestScanDocument(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		wantTypes     []string
+		wantProblems  []string
+		wantErrs      []error
+		wantCanonical string
+		wantStale     []int
+	}{
+		{
+			name:          "Canonical form",
+			content:       "# Title\n\n```mermaid\n" + flowSource + "```\n",
+			wantTypes:     []string{"flowchart"},
+			wantCanonical: "# Title\n\n```mermaid\n" + flowCanonical + "```\n",
+			wantStale:     []int{3},
+		},
+		{
+			name:          "Canonical block is not stale",
+			content:       "```mermaid\n" + flowCanonical + "```\n",
+			wantTypes:     []string{"flowchart"},
+			wantCanonical: "```mermaid\n" + flowCanonical + "```\n",
+		},
+		{
+			name:          "Indented block keeps its indentation and newlines",
+			content:       "- item\r\n  ~~~mermaid\r\n  ---\r\n  title: Day\r\n  ---\r\n  journey\r\n  section Work\r\n  Code: 5\r\n  ~~~\r\n",
+			wantTypes:     []string{"journey"},
+			wantCanonical: "- item\r\n  ~~~mermaid\r\n  ---\r\n  title: Day\r\n  ---\r\n  journey\r\n      section Work\r\n          Code: 5\r\n  ~~~\r\n",
+			wantStale:     []int{2},
+		},
+		{
+			name:          "Syntax error lines are lines of the file",
+			content:       "text\n```mermaid\n%% comment\nflowchart TB\n    A -->\n```\n",
+			wantTypes:     []string{"flowchart"},
+			wantProblems:  []string{"doc.md:5: syntax error: expected node ID at column 6"},
+			wantErrs:      []error{basediagram.ErrSyntax},
+			wantCanonical: "text\n```mermaid\n%% comment\nflowchart TB\n    A -->\n```\n",
+		},
+		{
+			name:         "Unsupported syntax",
+			content:      "```mermaid\nsequenceDiagram\n    loop Every minute\n        A->>B: ping\n    end\n```\n",
+			wantTypes:    []string{"sequenceDiagram"},
+			wantProblems: []string{"doc.md:3: unsupported syntax: loop Every minute", "doc.md:5: unsupported syntax: end"},
+			wantErrs:     []error{basediagram.ErrUnsupported, basediagram.ErrUnsupported},
+		},
+		{
+			name:         "Validation error",
+			content:      "```mermaid\nflowchart TB\n    A --> B\n    class A missing\n```\n",
+			wantTypes:    []string{"flowchart"},
+			wantProblems: []string{"doc.md:4: unknown reference: class \"missing\""},
+			wantErrs:     []error{basediagram.ErrUnknownReference},
+		},
+		{
+			name:         "Edge limit",
+			content:      "```mermaid\n---\nconfig:\n    maxEdges: 1\n---\nflowchart TB\n    A --> B --> C\n```\n",
+			wantTypes:    []string{"flowchart"},
+			wantProblems: []string{"doc.md:1: edge limit exceeded: 2, more than 1"},
+			wantErrs:     []error{ErrEdges},
+		},
+		{
+			name:         "Text size limit",
+			content:      "```mermaid\n---\nconfig:\n    maxTextSize: 20\n---\nflowchart TB\n    A --> B\n```\n",
+			wantTypes:    []string{"flowchart"},
+			wantProblems: []string{"doc.md:1: text size limit exceeded: 61, more than 20"},
+			wantErrs:     []error{ErrTextSize},
+		},
+		{
+			name:         "Empty block",
+			content:      "```mermaid\n\n```\n",
+			wantTypes:    []string{""},
+			wantProblems: []string{"doc.md:2: missing diagram declaration"},
+			wantErrs:     []error{basediagram.ErrNoDiagram},
+		},
+		{
+			name:          "Other diagram types and code blocks are skipped",
+			content:       "````md\n```mermaid\nflowchart TB\n    A -->\n```\n````\n```mermaid\npie\n    \"a\" : 1\n```\n```go\nx := 1\n```\n",
+			wantTypes:     []string{"pie"},
+			wantCanonical: "````md\n```mermaid\nflowchart TB\n    A -->\n```\n````\n```mermaid\npie\n    \"a\" : 1\n```\n```go\nx := 1\n```\n",
+		},
+		{
+			name:      "Unclosed block ends with the document",
+			content:   "```mermaid\n" + flowSource,
+			wantTypes: []string{"flowchart"},
+			wantStale: []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := ScanDocument("doc.md", tt.content, ScanOptions{Canonical: true})
+
+			var types []string
+			for _, fence := range file.Fences {
+				types = append(types, fence.Type)
+			}
+			if strings.Join(types, ",") != strings.Join(tt.wantTypes, ",") {
+				t.Errorf("ScanDocument() types = %q, want %q", types, tt.wantTypes)
+			}
+
+			if len(file.Problems) != len(tt.wantProblems) {
+				t.Fatalf("ScanDocument() problems = %v, want %q", file.Problems, tt.wantProblems)
+			}
+			for i, problem := range file.Problems {
+				if problem.Error() != tt.wantProblems[i] {
+					t.Errorf("problem %d = %q, want %q", i, problem.Error(), tt.wantProblems[i])
+				}
+				if !errors.Is(problem, tt.wantErrs[i]) {
+					t.Errorf("problem %d = %v, want %v", i, problem, tt.wantErrs[i])
+				}
+			}
+
+			if tt.wantCanonical != "" && file.Canonical != tt.wantCanonical {
+				t.Errorf("ScanDocument() canonical = %q, want %q", file.Canonical, tt.wantCanonical)
+			}
+
+			var stale []int
+			for _, fence := range file.Stale {
+				stale = append(stale, fence.Line)
+			}
+			if len(stale) != len(tt.wantStale) {
+				t.Fatalf("ScanDocument() stale = %v, want %v", stale, tt.wantStale)
+			}
+			for i := range stale {
+				if stale[i] != tt.wantStale[i] {
+					t.Errorf("ScanDocument() stale = %v, want %v", stale, tt.wantStale)
+				}
+			}
+		})
+	}
+}
+
+func TestScanDocumentWithoutCanonical(t *testing.T) {
+	file := ScanDocument("doc.md", "```mermaid\n"+flowSource+"```\n", ScanOptions{})
+
+	if file.Canonical != "" || file.Stale != nil {
+		t.Errorf("ScanDocument() = %q, %v, want no canonical form", file.Canonical, file.Stale)
+	}
+	if _, ok := file.Fences[0].Diagram.(*flowchart.Flowchart); !ok {
+		t.Errorf("ScanDocument() diagram = %T, want *flowchart.Flowchart", file.Fences[0].Diagram)
+	}
+	if file.Fences[0].Source != flowSource {
+		t.Errorf("ScanDocument() source = %q, want %q", file.Fences[0].Source, flowSource)
+	}
+}
+
+func TestScan(t *testing.T) {
+	fsys := fstest.MapFS{
+		"README.md":             {Data: []byte("```mermaid\n" + flowSource + "```\n")},
+		"docs/guide.markdown":   {Data: []byte("text\n\n```mermaid\nerDiagram\n    A ||--o{ B\n```\n")},
+		"docs/plain.md":         {Data: []byte("# No diagrams\n")},
+		"docs/notes.txt":        {Data: []byte("```mermaid\nbad\n```\n")},
+		".github/template.md":   {Data: []byte("```mermaid\nbad\n```\n")},
+		"docs/sub/.hidden/x.md": {Data: []byte("```mermaid\nbad\n```\n")},
+	}
+
+	files, err := Scan(fsys, ScanOptions{})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	want := []string{"README.md", "docs/guide.markdown"}
+	if len(files) != len(want) {
+		t.Fatalf("Scan() = %d files, want %v", len(files), want)
+	}
+	for i, file := range files {
+		if file.Path != want[i] {
+			t.Errorf("file %d = %s, want %s", i, file.Path, want[i])
+		}
+	}
+
+	if len(files[0].Problems) != 0 {
+		t.Errorf("README.md problems = %v, want none", files[0].Problems)
+	}
+	wantProblem := "docs/guide.markdown:5: syntax error: expected an entity or relationship"
+	if len(files[1].Problems) != 1 || files[1].Problems[0].Error() != wantProblem {
+		t.Errorf("docs/guide.markdown problems = %v, want %q", files[1].Problems, wantProblem)
+	}
+}
+
+func TestScanError(t *testing.T) {
+	_, err := Scan(fstest.MapFS{}, ScanOptions{})
+	if err != nil {
+		t.Errorf("Scan() of an empty file system error = %v", err)
+	}
+
+	_, err = Scan(errorFS{}, ScanOptions{})
+	if !errors.Is(err, errRead) {
+		t.Errorf("Scan() error = %v, want %v", err, errRead)
+	}
+}
+
+var errRead = errors.New("read error")
+
+// errorFS is a file system that cannot be read.
+type errorFS struct{}
+
+func (errorFS) Open(string) (fs.File, error) {
+	return nil, errRead
+}
@@ -0,0 +1,67 @@
+package sequence
+
+// Clone returns a deep copy of the diagram.
+// Messages and notes of the copy reference the copied actors.
+func (d *Diagram) Clone() *Diagram {
+	actors := make(map[*Actor]*Actor)
+
+	mapActor := func(actor *Actor) *Actor {
+		if actor == nil {
+			return nil
+		}
+		if cloned, ok := actors[actor]; ok {
+			return cloned
+		}
+		cloned := *actor
+		actors[actor] = &cloned
+		return &cloned
+	}
+
+	clone := &Diagram{
+		BaseDiagram: d.BaseDiagram,
+		Actors:      make([]*Actor, 0, len(d.Actors)),
+		Messages:    make([]*Message, 0, len(d.Messages)),
+		autonumber:  d.autonumber,
+	}
+	clone.Config = d.Config.Clone()
+
+	for _, actor := range d.Actors {
+		clone.Actors = append(clone.Actors, mapActor(actor))
+	}
+
+	for _, msg := range d.Messages {
+		clone.Messages = append(clone.Messages, msg.clone(mapActor))
+	}
+
+	return clone
+}
+
+// clone returns a deep copy of the message, mapping actors with mapActor.
+func (m *Message) clone(mapActor func(*Actor) *Actor) *Message {
+	clone := &Message{
+		From: mapActor(m.From),
+		To:   mapActor(m.To),
+		Type: m.Type,
+		Text: m.Text,
+	}
+
+	if m.Nested != nil {
+		clone.Nested = make([]*Message, 0, len(m.Nested))
+	}
+
+	for _, nested := range m.Nested {
+		clone.Nested = append(clone.Nested, nested.clone(mapActor))
+	}
+
+	if m.Note != nil {
+		clone.Note = &Note{
+			Position: m.Note.Position,
+			Text:     m.Note.Text,
+		}
+		for _, actor := range m.Note.Actors {
+			clone.Note.Actors = append(clone.Note.Actors, mapActor(actor))
+		}
+	}
+
+	return clone
+}
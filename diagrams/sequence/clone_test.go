@@ -0,0 +1,41 @@
+package sequence
+
+import "testing"
+
+func TestDiagram_Clone(t *testing.T) {
+	original := NewDiagram()
+	original.EnableAutoNumber()
+	alice := original.AddActor("A", "Alice", ActorParticipant)
+	bob := original.AddActor("B", "Bob", ActorActor)
+	msg := original.AddMessage(alice, bob, MessageSolidArrow, "Hello")
+	msg.AddNestedMessage(bob, alice, MessageAsync, "Hi")
+	original.AddNote(NoteOver, "Greeting", alice, bob)
+	original.CreateActor(alice, "C", "Carol", ActorParticipant)
+	original.DestroyActor(bob)
+
+	clone := original.Clone()
+
+	if clone.String() != original.String() {
+		t.Errorf("Clone() output differs:\nwant:\n%s\ngot:\n%s", original.String(), clone.String())
+	}
+
+	clonedAlice := clone.FindActor("A")
+	if clonedAlice == alice {
+		t.Fatal("Clone() should copy actors")
+	}
+
+	if clone.Messages[0].From != clonedAlice || clone.Messages[0].Nested[0].To != clonedAlice {
+		t.Error("Clone() messages should reference cloned actors")
+	}
+
+	if clone.Messages[1].Note == original.Messages[1].Note || clone.Messages[1].Note.Actors[0] != clonedAlice {
+		t.Error("Clone() notes should be copied and reference cloned actors")
+	}
+
+	clonedAlice.Name = "Alicia"
+	clone.Messages[0].AddNestedMessage(alice, bob, MessageSolid, "Extra")
+
+	if alice.Name != "Alice" || len(msg.Nested) != 1 {
+		t.Error("Modifying the clone changed the original")
+	}
+}
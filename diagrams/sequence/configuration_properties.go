@@ -376,16 +376,153 @@ func (c *SequenceConfigurationProperties) SetLabelBoxHeight(v int) *SequenceConf
 	return c
 }
 
+// Getters for each property. The boolean is false when the property is not set.
+func (c SequenceConfigurationProperties) ArrowMarkerAbsolute() (bool, bool) {
+	return basediagram.PropertyValue[bool](c.properties, sequencePropertyArrowMarkerAbsolute)
+}
+
+func (c SequenceConfigurationProperties) HideUnusedParticipants() (bool, bool) {
+	return basediagram.PropertyValue[bool](c.properties, sequencePropertyHideUnusedParticipants)
+}
+
+func (c SequenceConfigurationProperties) ActivationWidth() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyActivationWidth)
+}
+
+func (c SequenceConfigurationProperties) DiagramMarginX() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyDiagramMarginX)
+}
+
+func (c SequenceConfigurationProperties) DiagramMarginY() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyDiagramMarginY)
+}
+
+func (c SequenceConfigurationProperties) ActorMargin() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyActorMargin)
+}
+
+func (c SequenceConfigurationProperties) Width() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyWidth)
+}
+
+func (c SequenceConfigurationProperties) Height() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyHeight)
+}
+
+func (c SequenceConfigurationProperties) BoxMargin() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyBoxMargin)
+}
+
+func (c SequenceConfigurationProperties) BoxTextMargin() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyBoxTextMargin)
+}
+
+func (c SequenceConfigurationProperties) NoteMargin() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyNoteMargin)
+}
+
+func (c SequenceConfigurationProperties) MessageMargin() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyMessageMargin)
+}
+
+func (c SequenceConfigurationProperties) MessageAlign() (string, bool) {
+	return basediagram.PropertyValue[string](c.properties, sequencePropertyMessageAlign)
+}
+
+func (c SequenceConfigurationProperties) MirrorActors() (bool, bool) {
+	return basediagram.PropertyValue[bool](c.properties, sequencePropertyMirrorActors)
+}
+
+func (c SequenceConfigurationProperties) ForceMenus() (bool, bool) {
+	return basediagram.PropertyValue[bool](c.properties, sequencePropertyForceMenus)
+}
+
+func (c SequenceConfigurationProperties) BottomMarginAdj() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyBottomMarginAdj)
+}
+
+func (c SequenceConfigurationProperties) RightAngles() (bool, bool) {
+	return basediagram.PropertyValue[bool](c.properties, sequencePropertyRightAngles)
+}
+
+func (c SequenceConfigurationProperties) ShowSequenceNumbers() (bool, bool) {
+	return basediagram.PropertyValue[bool](c.properties, sequencePropertyShowSequenceNumbers)
+}
+
+func (c SequenceConfigurationProperties) ActorFontSize() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyActorFontSize)
+}
+
+func (c SequenceConfigurationProperties) ActorFontFamily() (string, bool) {
+	return basediagram.PropertyValue[string](c.properties, sequencePropertyActorFontFamily)
+}
+
+func (c SequenceConfigurationProperties) ActorFontWeight() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyActorFontWeight)
+}
+
+func (c SequenceConfigurationProperties) NoteFontSize() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyNoteFontSize)
+}
+
+func (c SequenceConfigurationProperties) NoteFontFamily() (string, bool) {
+	return basediagram.PropertyValue[string](c.properties, sequencePropertyNoteFontFamily)
+}
+
+func (c SequenceConfigurationProperties) NoteFontWeight() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyNoteFontWeight)
+}
+
+func (c SequenceConfigurationProperties) NoteAlign() (string, bool) {
+	return basediagram.PropertyValue[string](c.properties, sequencePropertyNoteAlign)
+}
+
+func (c SequenceConfigurationProperties) MessageFontSize() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyMessageFontSize)
+}
+
+func (c SequenceConfigurationProperties) MessageFontFamily() (string, bool) {
+	return basediagram.PropertyValue[string](c.properties, sequencePropertyMessageFontFamily)
+}
+
+func (c SequenceConfigurationProperties) MessageFontWeight() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyMessageFontWeight)
+}
+
+func (c SequenceConfigurationProperties) Wrap() (bool, bool) {
+	return basediagram.PropertyValue[bool](c.properties, sequencePropertyWrap)
+}
+
+func (c SequenceConfigurationProperties) WrapPadding() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyWrapPadding)
+}
+
+func (c SequenceConfigurationProperties) LabelBoxWidth() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyLabelBoxWidth)
+}
+
+func (c SequenceConfigurationProperties) LabelBoxHeight() (int, bool) {
+	return basediagram.PropertyValue[int](c.properties, sequencePropertyLabelBoxHeight)
+}
+
 func (c SequenceConfigurationProperties) String() string {
 	var sb strings.Builder
 	sb.WriteString(c.ConfigurationProperties.String())
 
 	if len(c.properties) > 0 {
 		sb.WriteString(baseSequenceConfigurationProperties)
-		for _, prop := range c.properties {
+		for _, prop := range basediagram.SortedProperties(c.properties) {
 			sb.WriteString(prop.Format())
 		}
 	}
 
 	return sb.String()
 }
+
+// Clone returns a deep copy of the configuration properties.
+func (c SequenceConfigurationProperties) Clone() SequenceConfigurationProperties {
+	return SequenceConfigurationProperties{
+		ConfigurationProperties: c.ConfigurationProperties.Clone(),
+		properties:              basediagram.CloneProperties(c.properties),
+	}
+}
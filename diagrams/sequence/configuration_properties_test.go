@@ -390,3 +390,25 @@ func TestSequenceConfigurationProperties_Setters(t *testing.T) {
 		})
 	}
 }
+
+func TestSequenceConfigurationProperties_Getters(t *testing.T) {
+	config := NewSequenceConfigurationProperties()
+
+	if _, ok := config.ActorMargin(); ok {
+		t.Error("ActorMargin() should report an unset property")
+	}
+
+	config.SetActorMargin(25).SetMirrorActors(false).SetNoteFontFamily("Arial")
+
+	if value, ok := config.ActorMargin(); !ok || value != 25 {
+		t.Errorf("ActorMargin() = %v, %v, want 25, true", value, ok)
+	}
+
+	if value, ok := config.MirrorActors(); !ok || value {
+		t.Errorf("MirrorActors() = %v, %v, want false, true", value, ok)
+	}
+
+	if value, ok := config.NoteFontFamily(); !ok || value != "Arial" {
+		t.Errorf("NoteFontFamily() = %v, %v, want Arial, true", value, ok)
+	}
+}
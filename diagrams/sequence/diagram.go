@@ -37,6 +37,11 @@ func (d *Diagram) EnableAutoNumber() {
 	d.autonumber = true
 }
 
+// AutoNumber reports whether messages of the sequence diagram are numbered automatically.
+func (d *Diagram) AutoNumber() bool {
+	return d.autonumber
+}
+
 // AddActor creates and adds a new actor to the diagram.
 func (d *Diagram) AddActor(id, name string, actorType ActorType) *Actor {
 	actor := NewActor(id, name, actorType)
@@ -111,3 +116,112 @@ func (d *Diagram) AddNote(position NotePosition, text string, actors ...*Actor)
 
 	return note
 }
+
+// FindActor returns the actor with the given ID, or nil if there is none.
+func (d *Diagram) FindActor(id string) *Actor {
+	for _, actor := range d.Actors {
+		if actor.ID == id {
+			return actor
+		}
+	}
+
+	return nil
+}
+
+// MessagesOf returns all messages, including nested ones, sent or received by the actor.
+// Notes are not included.
+func (d *Diagram) MessagesOf(actor *Actor) (messages []*Message) {
+	walkMessages(d.Messages, func(msg *Message) {
+		if msg.Note == nil && (msg.From == actor || msg.To == actor) {
+			messages = append(messages, msg)
+		}
+	})
+
+	return
+}
+
+// RemoveActor removes the actor together with every message that references it.
+// The actor is detached from notes, and notes left without actors are removed.
+// It returns false if the actor is not part of the diagram.
+func (d *Diagram) RemoveActor(actor *Actor) bool {
+	var found bool
+
+	d.Actors, found = utils.RemoveItem(d.Actors, actor)
+	if !found {
+		return false
+	}
+
+	d.Messages = removeActorMessages(d.Messages, actor)
+
+	return true
+}
+
+// ReplaceActor replaces oldActor with newActor in place, re-pointing every message
+// and note that referenced oldActor.
+// It returns false if oldActor is not part of the diagram.
+func (d *Diagram) ReplaceActor(oldActor *Actor, newActor *Actor) bool {
+	if !utils.ReplaceItem(d.Actors, oldActor, newActor) {
+		return false
+	}
+
+	walkMessages(d.Messages, func(msg *Message) {
+		if msg.From == oldActor {
+			msg.From = newActor
+		}
+		if msg.To == oldActor {
+			msg.To = newActor
+		}
+		if msg.Note != nil {
+			utils.ReplaceItem(msg.Note.Actors, oldActor, newActor)
+		}
+	})
+
+	return true
+}
+
+// RemoveMessage removes the message, searching nested messages as well.
+// It returns false if the message is not part of the diagram.
+func (d *Diagram) RemoveMessage(message *Message) bool {
+	var found bool
+
+	if d.Messages, found = utils.RemoveItem(d.Messages, message); found {
+		return true
+	}
+
+	found = false
+	walkMessages(d.Messages, func(msg *Message) {
+		if !found {
+			msg.Nested, found = utils.RemoveItem(msg.Nested, message)
+		}
+	})
+
+	return found
+}
+
+// walkMessages calls fn for every message and nested message in depth-first order.
+func walkMessages(messages []*Message, fn func(*Message)) {
+	for _, msg := range messages {
+		fn(msg)
+		walkMessages(msg.Nested, fn)
+	}
+}
+
+// removeActorMessages drops every message that references the actor and detaches
+// the actor from notes, recursing into nested messages.
+func removeActorMessages(messages []*Message, actor *Actor) []*Message {
+	messages, _ = utils.RemoveFunc(messages, func(msg *Message) bool {
+		if msg.Note != nil {
+			msg.Note.Actors, _ = utils.RemoveFunc(msg.Note.Actors, func(a *Actor) bool {
+				return a == actor
+			})
+			return len(msg.Note.Actors) == 0
+		}
+		return msg.From == actor || msg.To == actor
+	})
+
+	for _, msg := range messages {
+		msg.Nested = removeActorMessages(msg.Nested, actor)
+	}
+
+	return messages
+}
@@ -235,7 +235,7 @@ func TestDiagram_EnableAutoNumber(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.diagram.EnableAutoNumber()
-			if !tt.diagram.autonumber {
+			if !tt.diagram.autonumber || !tt.diagram.AutoNumber() {
 				t.Errorf("EnableAutoNumber() did not set autonumber to true")
 			}
 
@@ -349,3 +349,102 @@ func TestDiagram_AddNote(t *testing.T) {
 		})
 	}
 }
+
+func TestDiagram_FindActor(t *testing.T) {
+	diagram := NewDiagram()
+	alice := diagram.AddActor("A", "Alice", ActorParticipant)
+
+	if diagram.FindActor("A") != alice {
+		t.Error("FindActor() did not find actor")
+	}
+
+	if diagram.FindActor("Missing") != nil {
+		t.Error("FindActor() should return nil for missing actor")
+	}
+}
+
+func TestDiagram_RemoveActor(t *testing.T) {
+	diagram := NewDiagram()
+	alice := diagram.AddActor("A", "Alice", ActorParticipant)
+	bob := diagram.AddActor("B", "Bob", ActorParticipant)
+	carol := diagram.AddActor("C", "Carol", ActorParticipant)
+	parent := diagram.AddMessage(alice, carol, MessageSolid, "Hello Carol")
+	parent.AddNestedMessage(carol, bob, MessageSolid, "Forward")
+	diagram.AddMessage(alice, bob, MessageSolid, "Hello Bob")
+	diagram.AddNote(NoteOver, "Shared", alice, bob)
+	diagram.AddNote(NoteLeft, "Bob only", bob)
+
+	if !diagram.RemoveActor(bob) {
+		t.Fatal("RemoveActor() = false, want true")
+	}
+
+	if diagram.FindActor("B") != nil {
+		t.Error("RemoveActor() did not remove actor")
+	}
+
+	if len(diagram.MessagesOf(bob)) != 0 {
+		t.Error("RemoveActor() left messages referencing the actor")
+	}
+
+	if len(parent.Nested) != 0 {
+		t.Error("RemoveActor() did not remove nested messages")
+	}
+
+	if len(diagram.Messages) != 2 {
+		t.Fatalf("RemoveActor() left %d messages, want 2", len(diagram.Messages))
+	}
+
+	note := diagram.Messages[1].Note
+	if note == nil || !reflect.DeepEqual(note.Actors, []*Actor{alice}) {
+		t.Errorf("RemoveActor() did not detach actor from shared note")
+	}
+
+	if diagram.RemoveActor(bob) {
+		t.Error("RemoveActor() on removed actor = true, want false")
+	}
+}
+
+func TestDiagram_ReplaceActor(t *testing.T) {
+	diagram := NewDiagram()
+	alice := diagram.AddActor("A", "Alice", ActorParticipant)
+	bob := diagram.AddActor("B", "Bob", ActorParticipant)
+	msg := diagram.AddMessage(alice, bob, MessageSolid, "Hello")
+	note := diagram.AddNote(NoteRight, "Note", bob)
+	robert := NewActor("R", "Robert", ActorActor)
+
+	if !diagram.ReplaceActor(bob, robert) {
+		t.Fatal("ReplaceActor() = false, want true")
+	}
+
+	if msg.To != robert {
+		t.Errorf("ReplaceActor() msg.To = %v, want %v", msg.To, robert)
+	}
+
+	if note.Actors[0] != robert {
+		t.Error("ReplaceActor() did not re-point note")
+	}
+
+	if diagram.ReplaceActor(bob, robert) {
+		t.Error("ReplaceActor() on missing actor = true, want false")
+	}
+}
+
+func TestDiagram_RemoveMessage(t *testing.T) {
+	diagram := NewDiagram()
+	alice := diagram.AddActor("A", "Alice", ActorParticipant)
+	bob := diagram.AddActor("B", "Bob", ActorParticipant)
+	parent := diagram.AddMessage(alice, bob, MessageSolid, "Parent")
+	nested := parent.AddNestedMessage(bob, alice, MessageSolid, "Nested")
+
+	if !diagram.RemoveMessage(nested) {
+		t.Error("RemoveMessage() nested = false, want true")
+	}
+
+	if !diagram.RemoveMessage(parent) {
+		t.Error("RemoveMessage() top-level = false, want true")
+	}
+
+	if diagram.RemoveMessage(parent) {
+		t.Error("RemoveMessage() on removed message = true, want false")
+	}
+}
@@ -0,0 +1,210 @@
+package sequence
+
+import (
+	"encoding/json"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// DocumentType is the type of sequence diagram JSON documents.
+const DocumentType string = "sequence"
+
+const documentElementActor string = "actor"
+
+// diagramDocument is the JSON form of a sequence diagram. Messages and notes reference
+// actors by ID.
+type diagramDocument struct {
+	basediagram.Document
+	AutoNumber bool              `json:"autonumber,omitempty"`
+	Actors     []actorDocument   `json:"actors,omitempty"`
+	Messages   []messageDocument `json:"messages,omitempty"`
+}
+
+// actorDocument omits the type of participants.
+type actorDocument struct {
+	ID   string    `json:"id"`
+	Name string    `json:"name,omitempty"`
+	Type ActorType `json:"type,omitempty"`
+}
+
+// messageDocument is a message between actors, or a note when it only holds a note. The
+// sender of destroy messages is omitted.
+type messageDocument struct {
+	From   string            `json:"from,omitempty"`
+	To     string            `json:"to,omitempty"`
+	Type   MessageType       `json:"type,omitempty"`
+	Text   string            `json:"text,omitempty"`
+	Nested []messageDocument `json:"nested,omitempty"`
+	Note   *noteDocument     `json:"note,omitempty"`
+}
+
+type noteDocument struct {
+	Position NotePosition `json:"position"`
+	Text     string       `json:"text,omitempty"`
+	Actors   []string     `json:"actors,omitempty"`
+}
+
+// MarshalJSON encodes the sequence diagram as a versioned JSON document. Messages and
+// notes reference their actors by ID, so every actor they use must be part of the diagram.
+func (d *Diagram) MarshalJSON() ([]byte, error) {
+	config, err := d.Config.EncodeDocument(d.Config.properties)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := diagramDocument{
+		Document:   d.EncodeDocument(DocumentType, config),
+		AutoNumber: d.autonumber,
+	}
+
+	actors := make(map[*Actor]bool, len(d.Actors))
+	for _, actor := range d.Actors {
+		actors[actor] = true
+		actorDoc := actorDocument{ID: actor.ID, Name: actor.Name}
+		if actor.Type != ActorParticipant {
+			actorDoc.Type = actor.Type
+		}
+		doc.Actors = append(doc.Actors, actorDoc)
+	}
+
+	if doc.Messages, err = encodeMessages(d.Messages, actors); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON replaces the sequence diagram with the one described by a JSON document
+// and re-links messages and notes to the actors.
+func (d *Diagram) UnmarshalJSON(data []byte) error {
+	var doc diagramDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	decoded := NewDiagram()
+	if err := decoded.DecodeDocument(DocumentType, doc.Document); err != nil {
+		return err
+	}
+
+	var err error
+	if decoded.Config.ConfigurationProperties, decoded.Config.properties, err = doc.Config.Decode(); err != nil {
+		return err
+	}
+
+	decoded.autonumber = doc.AutoNumber
+
+	actors := make(map[string]*Actor, len(doc.Actors))
+	for _, actorDoc := range doc.Actors {
+		if actors[actorDoc.ID] != nil {
+			return basediagram.DuplicateID(documentElementActor, actorDoc.ID)
+		}
+		actorType := actorDoc.Type
+		if actorType == "" {
+			actorType = ActorParticipant
+		}
+		actors[actorDoc.ID] = decoded.AddActor(actorDoc.ID, actorDoc.Name, actorType)
+	}
+
+	if decoded.Messages, err = decodeMessages(doc.Messages, actors); err != nil {
+		return err
+	}
+
+	*d = *decoded
+
+	return nil
+}
+
+// encodeMessages returns the documents of messages and their nested messages.
+func encodeMessages(messages []*Message, actors map[*Actor]bool) (docs []messageDocument, err error) {
+	for _, message := range messages {
+		doc := messageDocument{Type: message.Type, Text: message.Text}
+
+		if doc.From, err = encodeActor(message.From, actors); err != nil {
+			return nil, err
+		}
+		if doc.To, err = encodeActor(message.To, actors); err != nil {
+			return nil, err
+		}
+
+		if message.Note != nil {
+			doc.Note = &noteDocument{Position: message.Note.Position, Text: message.Note.Text}
+			for _, actor := range message.Note.Actors {
+				id, err := encodeActor(actor, actors)
+				if err != nil {
+					return nil, err
+				}
+				doc.Note.Actors = append(doc.Note.Actors, id)
+			}
+		}
+
+		if doc.Nested, err = encodeMessages(message.Nested, actors); err != nil {
+			return nil, err
+		}
+
+		docs = append(docs, doc)
+	}
+
+	return
+}
+
+// encodeActor returns the ID of an actor of the diagram, or an empty ID for no actor.
+func encodeActor(actor *Actor, actors map[*Actor]bool) (string, error) {
+	if actor == nil {
+		return "", nil
+	}
+	if !actors[actor] {
+		return "", basediagram.UnknownReference(documentElementActor, actor.ID)
+	}
+
+	return actor.ID, nil
+}
+
+// decodeMessages returns the messages described by the documents.
+func decodeMessages(docs []messageDocument, actors map[string]*Actor) (messages []*Message, err error) {
+	messages = make([]*Message, 0, len(docs))
+
+	for _, doc := range docs {
+		message := NewMessage(nil, nil, doc.Type, doc.Text)
+
+		if message.From, err = decodeActor(doc.From, actors); err != nil {
+			return nil, err
+		}
+		if message.To, err = decodeActor(doc.To, actors); err != nil {
+			return nil, err
+		}
+
+		if doc.Note != nil {
+			message.Note = newNote(doc.Note.Position, doc.Note.Text)
+			for _, id := range doc.Note.Actors {
+				actor, err := decodeActor(id, actors)
+				if err != nil {
+					return nil, err
+				}
+				message.Note.Actors = append(message.Note.Actors, actor)
+			}
+		}
+
+		if message.Nested, err = decodeMessages(doc.Nested, actors); err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, message)
+	}
+
+	return
+}
+
+// decodeActor returns the actor with the given ID, or nil for an empty ID.
+func decodeActor(id string, actors map[string]*Actor) (*Actor, error) {
+	if id == "" {
+		return nil, nil
+	}
+
+	actor := actors[id]
+	if actor == nil {
+		return nil, basediagram.UnknownReference(documentElementActor, id)
+	}
+
+	return actor, nil
+}
@@ -0,0 +1,115 @@
+package sequence
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+func TestDiagram_JSONRoundTrip(t *testing.T) {
+	original := NewDiagram()
+	original.Title = "Greeting"
+	original.EnableAutoNumber()
+	original.Config.SetMirrorActors(true).SetActorMargin(20)
+	alice := original.AddActor("A", "Alice", ActorParticipant)
+	bob := original.AddActor("B", "Bob", ActorActor)
+	msg := original.AddMessage(alice, bob, MessageSolidArrow, "Hello")
+	msg.AddNestedMessage(bob, alice, MessageAsync, "Hi")
+	original.AddNote(NoteOver, "Greeting", alice, bob)
+	original.CreateActor(alice, "C", "Carol", ActorParticipant)
+	original.DestroyActor(bob)
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	decoded := NewDiagram()
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if decoded.String() != original.String() {
+		t.Errorf("round trip output differs:\nwant:\n%s\ngot:\n%s", original.String(), decoded.String())
+	}
+
+	decodedAlice := decoded.FindActor("A")
+	if decoded.Messages[0].From != decodedAlice || decoded.Messages[0].Nested[0].To != decodedAlice {
+		t.Error("decoded messages should reference decoded actors")
+	}
+	if decoded.Messages[1].Note.Actors[1] != decoded.FindActor("B") {
+		t.Error("decoded notes should reference decoded actors")
+	}
+
+	again, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("Marshal() of decoded diagram error = %v", err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("encoding is not stable:\nfirst:  %s\nsecond: %s", data, again)
+	}
+}
+
+func TestDiagram_MarshalJSON(t *testing.T) {
+	d := NewDiagram()
+	alice := d.AddActor("A", "Alice", ActorParticipant)
+	d.DestroyActor(alice)
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `{"version":1,"type":"sequence","actors":[{"id":"A","name":"Alice"}],"messages":[{"to":"A","type":"destroy"}]}`
+	if string(data) != want {
+		t.Errorf("Marshal() =\n%s\nwant\n%s", data, want)
+	}
+
+	d.AddMessage(alice, NewActor("X", "Stranger", ActorActor), MessageAsync, "")
+	if _, err := json.Marshal(d); !errors.Is(err, basediagram.ErrUnknownReference) {
+		t.Errorf("Marshal() with an actor outside the diagram error = %v, want %v", err, basediagram.ErrUnknownReference)
+	}
+}
+
+func TestDiagram_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{
+			name:  "Valid document",
+			input: `{"version":1,"type":"sequence","actors":[{"id":"A"}],"messages":[{"from":"A","to":"A","type":"->>"}]}`,
+		},
+		{
+			name:  "Unknown message actor",
+			input: `{"version":1,"type":"sequence","actors":[{"id":"A"}],"messages":[{"from":"A","to":"B","type":"->>"}]}`,
+			want:  basediagram.ErrUnknownReference,
+		},
+		{
+			name:  "Unknown note actor",
+			input: `{"version":1,"type":"sequence","messages":[{"note":{"position":"over","actors":["A"]}}]}`,
+			want:  basediagram.ErrUnknownReference,
+		},
+		{
+			name:  "Duplicate actor",
+			input: `{"version":1,"type":"sequence","actors":[{"id":"A"},{"id":"A"}]}`,
+			want:  basediagram.ErrDuplicateID,
+		},
+		{
+			name:  "Missing version",
+			input: `{"type":"sequence"}`,
+			want:  basediagram.ErrSchemaVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(tt.input), NewDiagram()); !errors.Is(err, tt.want) {
+				t.Errorf("Unmarshal() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package sequence
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// Keywords of sequence diagram source.
+const (
+	keywordSequence   = "sequenceDiagram"
+	keywordAutonumber = "autonumber"
+	keywordActivate   = "activate"
+	keywordDeactivate = "deactivate"
+	keywordAs         = " as "
+	activationStart   = "+"
+	activationEnd     = "-"
+
+	// configKey is the frontmatter configuration member holding the sequence properties.
+	configKey = "sequence"
+)
+
+// Statement patterns. Messages are "from arrow to" with an optional ": text", and notes
+// are "Note position actors: text".
+var (
+	messagePattern = regexp.MustCompile(`^([^:<>+\-]+?)\s*(<<-->>|<<->>|-->>>|-->>|->>|-->|->|--x|-x|--\)|-\))\s*([+-]?)\s*([^:<>+\-]+?)\s*(?::(.*))?$`)
+	notePattern    = regexp.MustCompile(`^(?i:note)\s+(left of|right of|over)\s+([^:]+?)\s*:(.*)$`)
+)
+
+// messageTypes maps the arrows of messages to the message types the model can represent.
+var messageTypes = map[string]MessageType{
+	string(MessageSolid):      MessageSolid,
+	string(MessageSolidArrow): MessageSolidArrow,
+	string(MessageAsync):      MessageAsync,
+	string(MessageDotted):     MessageDotted,
+}
+
+// unsupportedKeywords start the statements the model has no counterpart for.
+var unsupportedKeywords = []string{
+	"loop", "alt", "else", "opt", "par", "and", "critical", "option", "break", "rect", "end",
+	"box", "create", "destroy", "link", "links", "properties", "details", "accTitle", "accDescr",
+}
+
+// Parse returns the sequence diagram described by Mermaid source, such as the output of
+// String. Actors are created by their declaration or by their first mention, and the
+// activation shorthand of messages, "A->>+B" and "B-->>-A", is read as the message
+// followed by an activation or deactivation. Errors are *basediagram.SyntaxError values:
+// invalid syntax wraps basediagram.ErrSyntax, and blocks such as loop and alt and arrows
+// the model has no message type for wrap basediagram.ErrUnsupported. Unsupported
+// statements are reported together, see basediagram.Source.Read.
+func Parse(source string) (*Diagram, error) {
+	parsed, err := basediagram.ParseSource(source, configKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed.Header.Text != keywordSequence {
+		return nil, basediagram.Syntax(parsed.Header.Line, "expected %s, found %q", keywordSequence, parsed.Header.Text)
+	}
+
+	p := &sequenceParser{diagram: NewDiagram(), actors: make(map[string]*Actor)}
+	if err = parsed.Read(p.statement); err != nil {
+		return nil, err
+	}
+
+	d := p.diagram
+	d.DecodeSource(parsed)
+	if d.Config.ConfigurationProperties, d.Config.properties, err = parsed.Config.Decode(); err != nil {
+		return nil, basediagram.AtLine(1, err)
+	}
+
+	return d, nil
+}
+
+// sequenceParser builds a sequence diagram statement by statement.
+type sequenceParser struct {
+	diagram *Diagram
+	actors  map[string]*Actor
+}
+
+// statement reads a statement of the diagram body.
+func (p *sequenceParser) statement(statement basediagram.Statement) error {
+	text := strings.TrimSpace(strings.TrimRight(statement.Text, ";"))
+
+	if text == keywordAutonumber {
+		p.diagram.EnableAutoNumber()
+		return nil
+	}
+	for _, actorType := range []ActorType{ActorParticipant, ActorActor} {
+		if rest, ok := basediagram.CutKeyword(text, string(actorType)); ok {
+			return p.declare(statement, rest, actorType)
+		}
+	}
+	if rest, ok := basediagram.CutKeyword(text, keywordActivate); ok {
+		p.diagram.Messages = append(p.diagram.Messages, &Message{To: p.actor(rest), Type: MessageActivate})
+		return nil
+	}
+	if rest, ok := basediagram.CutKeyword(text, keywordDeactivate); ok {
+		p.diagram.Messages = append(p.diagram.Messages, &Message{To: p.actor(rest), Type: MessageDeactivate})
+		return nil
+	}
+	if basediagram.HasKeyword(text, unsupportedKeywords...) {
+		return basediagram.Unsupported(statement)
+	}
+
+	if match := notePattern.FindStringSubmatch(text); match != nil {
+		var actors []*Actor
+		for _, id := range strings.Split(match[2], ",") {
+			actors = append(actors, p.actor(strings.TrimSpace(id)))
+		}
+		position := NotePosition(strings.ToLower(match[1]))
+		if len(actors) > 2 || (len(actors) == 2 && position != NoteOver) {
+			return basediagram.Syntax(statement.Line, "too many actors for a note %s", position)
+		}
+		p.diagram.AddNote(position, strings.TrimSpace(match[3]), actors...)
+		return nil
+	}
+
+	match := messagePattern.FindStringSubmatch(text)
+	if match == nil {
+		return basediagram.Syntax(statement.Line, "expected a message, note or declaration")
+	}
+
+	messageType, ok := messageTypes[match[2]]
+	if !ok {
+		return basediagram.Unsupported(statement)
+	}
+	from, to := p.actor(match[1]), p.actor(match[4])
+	p.diagram.AddMessage(from, to, messageType, strings.TrimSpace(match[5]))
+
+	// "+" activates the receiver and "-" deactivates the sender once the message is sent.
+	switch match[3] {
+	case activationStart:
+		p.diagram.Messages = append(p.diagram.Messages, &Message{To: to, Type: MessageActivate})
+	case activationEnd:
+		p.diagram.Messages = append(p.diagram.Messages, &Message{To: from, Type: MessageDeactivate})
+	}
+
+	return nil
+}
+
+// declare adds an actor declared as "id" or "id as name".
+func (p *sequenceParser) declare(statement basediagram.Statement, rest string, actorType ActorType) error {
+	id, name, found := strings.Cut(rest, keywordAs)
+	id = strings.TrimSpace(id)
+	if !found {
+		name = id
+	}
+	name = strings.TrimSpace(name)
+
+	if id == "" {
+		return basediagram.Syntax(statement.Line, "%s without ID", actorType)
+	}
+	if p.actors[id] != nil {
+		return basediagram.AtLine(statement.Line, basediagram.DuplicateID(documentElementActor, id))
+	}
+
+	p.actors[id] = p.diagram.AddActor(id, name, actorType)
+
+	return nil
+}
+
+// actor returns the actor with an ID, adding a participant for it on its first mention.
+func (p *sequenceParser) actor(id string) *Actor {
+	if actor := p.actors[id]; actor != nil {
+		return actor
+	}
+
+	actor := p.diagram.AddActor(id, id, ActorParticipant)
+	p.actors[id] = actor
+
+	return actor
+}
@@ -0,0 +1,108 @@
+package sequence
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/testutils"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "Messages and notes",
+			source: "sequenceDiagram\n    autonumber\n    participant A as Alice\n    actor B\n    A->>B: Hello\n    B-->>A: Hi\n    activate A\n    A-->B: dotted\n    A-->>>B: async\n    deactivate A\n    Note over A,B: shared\n    note left of C: new\n",
+			want:   "sequenceDiagram\nautonumber\n    participant A as Alice\n    actor B as B\n    participant C as C\n\tA->>B: Hello\n\tB-->>A: Hi\n\tactivate A\n\tA-->B: dotted\n\tA-->>>B: async\n\tdeactivate A\n\tNote over A,B: shared\n\tNote left of C: new\n",
+		},
+		{
+			name:   "Activation shorthand",
+			source: "sequenceDiagram\n    A->>+B: hi\n    B-->>-A: ok\n",
+			want:   "sequenceDiagram\n    participant A as A\n    participant B as B\n\tA->>B: hi\n\tactivate B\n\tB-->>A: ok\n\tdeactivate B\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := testutils.DiagramBody(t, d.String()); got != tt.want {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+
+			reparsed, err := Parse(d.String())
+			if err != nil {
+				t.Fatalf("Parse() of the rendering error = %v", err)
+			}
+			if reparsed.String() != d.String() {
+				t.Errorf("Parse() of the rendering = %q, want %q", reparsed.String(), d.String())
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "Other diagram type",
+			source:  "flowchart TB\n",
+			wantErr: basediagram.ErrSyntax,
+			wantMsg: "line 1: syntax error: expected sequenceDiagram, found \"flowchart TB\"",
+		},
+		{
+			name:    "Duplicate participant",
+			source:  "sequenceDiagram\n    participant A\n    actor A\n",
+			wantErr: basediagram.ErrDuplicateID,
+			wantMsg: "line 3: duplicate identifier: actor \"A\"",
+		},
+		{
+			name:    "Loop",
+			source:  "sequenceDiagram\n    loop Every minute\n        A->>B: ping\n    end\n",
+			wantErr: basediagram.ErrUnsupported,
+			wantMsg: "line 2: unsupported syntax: loop Every minute\nline 4: unsupported syntax: end",
+		},
+		{
+			name:    "Arrow without message type",
+			source:  "sequenceDiagram\n    A-xB: lost\n",
+			wantErr: basediagram.ErrUnsupported,
+		},
+		{
+			name:    "Note for too many actors",
+			source:  "sequenceDiagram\n    Note left of A,B: x\n",
+			wantErr: basediagram.ErrSyntax,
+		},
+		{
+			name:    "Invalid statement",
+			source:  "sequenceDiagram\n    A\n",
+			wantErr: basediagram.ErrSyntax,
+			wantMsg: "line 2: syntax error: expected a message, note or declaration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.source)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			var syntaxErr *basediagram.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Errorf("Parse() error = %T, want *basediagram.SyntaxError", err)
+			}
+			if tt.wantMsg != "" && err.Error() != tt.wantMsg {
+				t.Errorf("Parse() error = %q, want %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
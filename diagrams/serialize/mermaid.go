@@ -0,0 +1,113 @@
+package serialize
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/block"
+	"github.com/TyphonHill/go-mermaid/diagrams/class"
+	"github.com/TyphonHill/go-mermaid/diagrams/entityrelationship"
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/sequence"
+	"github.com/TyphonHill/go-mermaid/diagrams/state"
+	"github.com/TyphonHill/go-mermaid/diagrams/timeline"
+	"github.com/TyphonHill/go-mermaid/diagrams/userjourney"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// ErrNotParsed is returned by Parse and Format for the diagram types no package parses.
+var ErrNotParsed = errors.New("diagram type not parsed")
+
+const (
+	notParsedErrorString string = "%w %q"
+	sourceNewline        string = "\n"
+)
+
+// mermaidTypes maps the diagram type keywords of Mermaid syntax to the document types of
+// the models they are parsed to. Other diagram types, such as pie charts, are not parsed.
+var mermaidTypes = map[string]string{
+	"flowchart":       flowchart.DocumentType,
+	"graph":           flowchart.DocumentType,
+	"sequenceDiagram": sequence.DocumentType,
+	"stateDiagram":    state.DocumentType,
+	"stateDiagram-v2": state.DocumentType,
+	"classDiagram":    class.DocumentType,
+	"erDiagram":       entityrelationship.DocumentType,
+	"journey":         userjourney.DocumentType,
+	"timeline":        timeline.DocumentType,
+	"block-beta":      block.DocumentType,
+	"block":           block.DocumentType,
+}
+
+// parsers parses Mermaid source to the model of each document type.
+var parsers = map[string]func(source string) (Diagram, error){
+	flowchart.DocumentType:          parser(flowchart.Parse),
+	sequence.DocumentType:           parser(sequence.Parse),
+	state.DocumentType:              parser(state.Parse),
+	class.DocumentType:              parser(class.Parse),
+	entityrelationship.DocumentType: parser(entityrelationship.Parse),
+	userjourney.DocumentType:        parser(userjourney.Parse),
+	timeline.DocumentType:           parser(timeline.Parse),
+	block.DocumentType:              parser(block.Parse),
+}
+
+// MermaidType returns the document type of the model Parse returns for the diagrams of a
+// Mermaid type keyword, such as "flowchart" for "graph", or "" when Parse does not parse
+// them.
+func MermaidType(keyword string) string {
+	return mermaidTypes[keyword]
+}
+
+// Parse returns the diagram model of Mermaid source, parsed by the package of its diagram
+// type. The source may be wrapped in a markdown fence.
+func Parse(source string) (Diagram, error) {
+	keyword := basediagram.SourceKeyword(source)
+	if keyword == "" {
+		_, err := basediagram.ParseSource(source, "")
+		return nil, err
+	}
+
+	parse, ok := parsers[mermaidTypes[keyword]]
+	if !ok {
+		return nil, fmt.Errorf(notParsedErrorString, ErrNotParsed, keyword)
+	}
+
+	return parse(source)
+}
+
+// Format returns Mermaid source in canonical form: the rendering of the diagram it parses
+// to, with the frontmatter members the source sets, followed by a newline. Source whose
+// canonical form does not parse back to itself is returned unchanged, as rewriting it would
+// lose part of the diagram.
+func Format(source string) (string, error) {
+	diagram, err := Parse(source)
+	if err != nil {
+		return "", err
+	}
+
+	formatted := render(diagram, source)
+	if reparsed, err := Parse(formatted); err != nil || render(reparsed, formatted) != formatted {
+		return source, nil
+	}
+
+	return formatted, nil
+}
+
+// render returns the Mermaid source of a diagram parsed from source, without markdown
+// fence and with the frontmatter members the source sets, followed by a newline.
+func render(diagram Diagram, source string) string {
+	rendering := strings.TrimRight(basediagram.StripFence(diagram.String()), sourceNewline) + sourceNewline
+	return basediagram.TrimFrontmatter(rendering, source)
+}
+
+// parser returns the parser of a diagram package as a parser of any diagram.
+func parser[T Diagram](parse func(source string) (T, error)) func(source string) (Diagram, error) {
+	return func(source string) (Diagram, error) {
+		diagram, err := parse(source)
+		if err != nil {
+			return nil, err
+		}
+		return diagram, nil
+	}
+}
@@ -0,0 +1,88 @@
+package serialize
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+const (
+	flowSource    = "flowchart TB\n    A --> B\n"
+	flowCanonical = "flowchart TB\n    A@{ shape: rect, label: \"A\"}\n    B@{ shape: rect, label: \"B\"}\n    A --> B\n"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr error
+	}{
+		{name: "Flowchart", source: flowSource},
+		{name: "Graph", source: "graph LR\n    A --> B\n"},
+		{name: "Block", source: "block-beta\n    a[\"A\"] b\n    a --> b\n"},
+		{name: "Fenced", source: "```mermaid\nerDiagram\n    A ||--o{ B : has\n```\n"},
+		{name: "Syntax error", source: "flowchart TB\n    A -->\n", wantErr: basediagram.ErrSyntax},
+		{name: "Type not parsed", source: "pie\n    \"A\": 1\n", wantErr: ErrNotParsed},
+		{name: "No diagram", source: "%% comment\n", wantErr: basediagram.ErrNoDiagram},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Parse(tt.source)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && d == nil {
+				t.Error("Parse() returned no diagram")
+			}
+		})
+	}
+
+	if d, _ := Parse(flowSource); d != nil {
+		if _, ok := d.(*flowchart.Flowchart); !ok {
+			t.Errorf("Parse() = %T, want *flowchart.Flowchart", d)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		want    string
+		wantErr error
+	}{
+		{name: "Canonical form", source: flowSource, want: flowCanonical},
+		{name: "Canonical source is unchanged", source: flowCanonical, want: flowCanonical},
+		{name: "Fenced source", source: "```mermaid\n" + flowSource + "```\n", want: flowCanonical},
+		{
+			name:   "Frontmatter members are kept",
+			source: "---\ntitle: Flow\nconfig:\n  maxEdges: 10\n---\n" + flowSource,
+			want:   "---\ntitle: Flow\nconfig:\n    maxEdges: 10\n---\n" + flowCanonical,
+		},
+		{name: "Syntax error", source: "flowchart TB\n    A -->\n", wantErr: basediagram.ErrSyntax},
+		{name: "Type not parsed", source: "pie\n    \"A\": 1\n", wantErr: ErrNotParsed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Format(tt.source)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Format() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMermaidType(t *testing.T) {
+	for keyword, want := range map[string]string{"flowchart": "flowchart", "graph": "flowchart", "stateDiagram-v2": "state", "block-beta": "block", "pie": "", "": ""} {
+		if got := MermaidType(keyword); got != want {
+			t.Errorf("MermaidType(%q) = %q, want %q", keyword, got, want)
+		}
+	}
+}
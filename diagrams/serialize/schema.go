@@ -0,0 +1,17 @@
+package serialize
+
+import (
+	_ "embed"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+)
+
+// schema is the JSON Schema of the documents of all diagram types.
+//
+//go:embed schema.json
+var schema []byte
+
+// Schema returns the JSON Schema (draft 2020-12) of the diagram documents.
+func Schema() []byte {
+	return utils.CopySlice(schema)
+}
@@ -0,0 +1,41 @@
+package serialize
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+func TestSchema(t *testing.T) {
+	var doc struct {
+		Defs map[string]struct {
+			Properties map[string]struct {
+				Const int      `json:"const"`
+				Enum  []string `json:"enum"`
+			} `json:"properties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(Schema(), &doc); err != nil {
+		t.Fatalf("Schema() is not valid JSON: %v", err)
+	}
+
+	header := doc.Defs["document"].Properties
+	if header["version"].Const != basediagram.SchemaVersion {
+		t.Errorf("schema version = %d, want %d", header["version"].Const, basediagram.SchemaVersion)
+	}
+	if !reflect.DeepEqual(header["type"].Enum, Types()) {
+		t.Errorf("schema types = %v, want %v", header["type"].Enum, Types())
+	}
+	for _, diagramType := range Types() {
+		if _, ok := doc.Defs[diagramType]; !ok {
+			t.Errorf("schema has no definition for %q", diagramType)
+		}
+	}
+
+	Schema()[0] = 0
+	if Schema()[0] != '{' {
+		t.Error("Schema() should return a copy")
+	}
+}
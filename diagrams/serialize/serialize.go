@@ -0,0 +1,113 @@
+// Package serialize encodes diagram models as versioned JSON or YAML documents and decodes
+// such documents back to the model of their diagram type.
+//
+// Every diagram package implements json.Marshaler and json.Unmarshaler for its diagram
+// model. This package selects the model from the "type" member of a document, converts
+// between JSON and YAML, and publishes the JSON Schema of the documents. It also parses
+// Mermaid syntax to the model of its diagram type with the parsers of the diagram packages,
+// see Parse and Format.
+package serialize
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/block"
+	"github.com/TyphonHill/go-mermaid/diagrams/class"
+	"github.com/TyphonHill/go-mermaid/diagrams/entityrelationship"
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/sequence"
+	"github.com/TyphonHill/go-mermaid/diagrams/state"
+	"github.com/TyphonHill/go-mermaid/diagrams/timeline"
+	"github.com/TyphonHill/go-mermaid/diagrams/userjourney"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// ErrUnknownType is returned for a document whose diagram type has no model.
+var ErrUnknownType = errors.New("unknown diagram type")
+
+const (
+	unknownTypeErrorString string = "%w %q"
+	jsonIndent             string = "  "
+)
+
+// Diagram is a diagram model that can be written as Mermaid syntax and encoded to a
+// document.
+type Diagram interface {
+	basediagram.Diagram
+	json.Marshaler
+	json.Unmarshaler
+}
+
+// models creates an empty diagram model for each document type.
+var models = map[string]func() Diagram{
+	block.DocumentType:              func() Diagram { return block.NewDiagram() },
+	class.DocumentType:              func() Diagram { return class.NewClassDiagram() },
+	entityrelationship.DocumentType: func() Diagram { return entityrelationship.NewDiagram() },
+	flowchart.DocumentType:          func() Diagram { return flowchart.NewFlowchart() },
+	sequence.DocumentType:           func() Diagram { return sequence.NewDiagram() },
+	state.DocumentType:              func() Diagram { return state.NewDiagram() },
+	timeline.DocumentType:           func() Diagram { return timeline.NewDiagram() },
+	userjourney.DocumentType:        func() Diagram { return userjourney.NewDiagram() },
+}
+
+// Types returns the supported document types in alphabetical order.
+func Types() []string {
+	types := make([]string, 0, len(models))
+	for diagramType := range models {
+		types = append(types, diagramType)
+	}
+	sort.Strings(types)
+
+	return types
+}
+
+// New returns an empty diagram model of the given document type.
+func New(diagramType string) (Diagram, error) {
+	model, ok := models[diagramType]
+	if !ok {
+		return nil, fmt.Errorf(unknownTypeErrorString, ErrUnknownType, diagramType)
+	}
+
+	return model(), nil
+}
+
+// MarshalJSON returns the diagram as an indented JSON document.
+func MarshalJSON(d Diagram) ([]byte, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", jsonIndent); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON returns the diagram model described by a JSON document of any supported
+// type.
+func UnmarshalJSON(data []byte) (Diagram, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+
+	d, err := New(header.Type)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
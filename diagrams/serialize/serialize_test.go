@@ -0,0 +1,193 @@
+package serialize
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/block"
+	"github.com/TyphonHill/go-mermaid/diagrams/class"
+	"github.com/TyphonHill/go-mermaid/diagrams/entityrelationship"
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/sequence"
+	"github.com/TyphonHill/go-mermaid/diagrams/state"
+	"github.com/TyphonHill/go-mermaid/diagrams/timeline"
+	"github.com/TyphonHill/go-mermaid/diagrams/userjourney"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// testDiagrams returns a diagram of every supported type that uses most document members.
+func testDiagrams() []Diagram {
+	fc := flowchart.NewFlowchart()
+	fc.Title = "Flow"
+	fc.CurveStyle = flowchart.CurveStyleStep
+	fc.Config.SetNodeSpacing(40).SetTheme(basediagram.ThemeDark).SetPrimaryColor("#f00")
+	highlight := fc.AddClass("highlight")
+	highlight.Style.Fill = "#ff0"
+	start := fc.NewNode("Start").SetShape(flowchart.NodeShapeTerminal).SetClass(highlight)
+	end := fc.NewNode("End")
+	end.SetStyle(&flowchart.NodeStyle{Stroke: "#00f", StrokeWidth: 2})
+	link := fc.NewLink(start, end).SetText("go").SetShape(flowchart.LinkShapeDotted)
+	link.Head, link.Tail, link.Length = flowchart.LinkArrowTypeCross, flowchart.LinkArrowTypeBullet, 2
+	group := fc.AddSubgraph("Group")
+	group.Direction = flowchart.SubgraphDirectionLeftRight
+	group.AddSubgraph("Nested").AddLink(end, start)
+
+	sd := sequence.NewDiagram()
+	sd.EnableAutoNumber()
+	alice := sd.AddActor("alice", "Alice", sequence.ActorParticipant)
+	bob := sd.AddActor("bob", "Bob", sequence.ActorActor)
+	sd.AddMessage(alice, bob, sequence.MessageSolidArrow, "Hello").AddNestedMessage(bob, alice, sequence.MessageAsync, "Hi")
+	sd.AddNote(sequence.NoteOver, "Greeting", alice, bob)
+
+	cd := class.NewClassDiagram()
+	birds := cd.AddNamespace("Birds")
+	animal := cd.AddClass("Animal", nil).SetAnnotation(class.ClassAnnotationAbstract)
+	animal.AddField("name", "string").SetVisibility(class.FieldVisibilityPrivate)
+	animal.AddMethod("Speak").SetReturnType("string").AddParameter("volume", "int")
+	duck := cd.AddClass("Duck", birds)
+	relation := cd.AddRelation(duck, animal)
+	relation.RelationToClassB = class.RelationTypeInheritance
+	relation.CardinalityToClassA = class.RelationCardinalityOneOrMore
+	cd.AddNote("Quacks", duck)
+
+	st := state.NewDiagram()
+	idle := st.AddState("Idle", "Waiting", state.StateNormal)
+	idle.AddNote("Initial", state.NoteLeft)
+	busy := st.AddState("Busy", "", state.StateComposite)
+	working := busy.AddNestedState("Working", "", state.StateNormal)
+	st.AddTransition(nil, idle, "")
+	st.AddTransition(idle, working, "start").SetType(state.TransitionDashed)
+	st.AddTransition(working, nil, "")
+
+	er := entityrelationship.NewDiagram()
+	customer := er.AddEntity("Customer").SetAlias("Buyer")
+	customer.AddAttribute("id", entityrelationship.TypeInteger).SetPrimaryKey()
+	order := er.AddEntity("Order")
+	order.AddAttribute("customer_id", entityrelationship.TypeInteger).SetForeignKey().SetRequired()
+	er.AddRelationship(customer, order).SetLabel("places").SetCardinality(entityrelationship.OneToZeroOrMore)
+
+	uj := userjourney.NewDiagram()
+	uj.Config.SetActorColours([]string{"red", "blue"})
+	uj.AddSection("Morning").AddTask("Coffee", 5, "Me", "Cat")
+
+	tl := timeline.NewDiagram()
+	tl.Config.SetPadding(5).SetDisableMulticolor(true)
+	tl.AddSection("2020").AddEvent("Jan", "Start").AddSubEvent("Plan")
+
+	bd := block.NewDiagram()
+	bd.SetColumns(3)
+	a := bd.AddBlock("A").SetShape(block.BlockShapeCircle).SetWidth(2).SetStyle("fill:#f9f")
+	bd.AddSpaceWithWidth(2)
+	parent := bd.AddBlock("").SetColumns(2)
+	child := parent.AddBlock("child")
+	parent.AddBlock("go").SetArrow(block.BlockArrowDirectionRight)
+	bd.AddLink(a, child).SetText("to")
+
+	return []Diagram{fc, sd, cd, st, er, uj, tl, bd}
+}
+
+func TestTypes(t *testing.T) {
+	want := []string{"block", "class", "entityrelationship", "flowchart", "sequence", "state", "timeline", "userjourney"}
+	if got := Types(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Types() = %v, want %v", got, want)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name        string
+		diagramType string
+		want        Diagram
+		wantErr     error
+	}{
+		{name: "Flowchart", diagramType: flowchart.DocumentType, want: flowchart.NewFlowchart()},
+		{name: "Class diagram", diagramType: class.DocumentType, want: class.NewClassDiagram()},
+		{name: "Unknown type", diagramType: "pie", wantErr: ErrUnknownType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.diagramType)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("New() error = %v, want %v", err, tt.wantErr)
+			}
+			if reflect.TypeOf(got) != reflect.TypeOf(tt.want) {
+				t.Errorf("New() = %T, want %T", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	for _, original := range testDiagrams() {
+		t.Run(reflect.TypeOf(original).String(), func(t *testing.T) {
+			data, err := MarshalJSON(original)
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+
+			decoded, err := UnmarshalJSON(data)
+			if err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			if reflect.TypeOf(decoded) != reflect.TypeOf(original) {
+				t.Fatalf("UnmarshalJSON() = %T, want %T", decoded, original)
+			}
+			if decoded.String() != original.String() {
+				t.Errorf("round trip output differs:\nwant:\n%s\ngot:\n%s", original.String(), decoded.String())
+			}
+		})
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	st := state.NewDiagram()
+	st.AddState("Idle", "", state.StateNormal)
+
+	got, err := MarshalJSON(st)
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+
+	want := `{
+  "version": 1,
+  "type": "state",
+  "states": [
+    {
+      "id": "Idle"
+    }
+  ]
+}
+`
+	if string(got) != want {
+		t.Errorf("MarshalJSON() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{name: "Valid document", input: `{"version":1,"type":"timeline"}`},
+		{name: "Unknown type", input: `{"version":1,"type":"pie"}`, wantErr: ErrUnknownType},
+		{name: "Missing type", input: `{"version":1}`, wantErr: ErrUnknownType},
+		{name: "Unsupported version", input: `{"version":9,"type":"timeline"}`, wantErr: basediagram.ErrSchemaVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := UnmarshalJSON([]byte(tt.input)); !errors.Is(err, tt.wantErr) {
+				t.Errorf("UnmarshalJSON() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	var syntaxErr *json.SyntaxError
+	if _, err := UnmarshalJSON([]byte(`{`)); !errors.As(err, &syntaxErr) {
+		t.Errorf("UnmarshalJSON() of invalid JSON error = %v, want a syntax error", err)
+	}
+}
@@ -0,0 +1,342 @@
+package serialize
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidDocument is returned for a document that does not match the schema.
+var ErrInvalidDocument = errors.New("invalid document")
+
+const (
+	validationErrorString string = "line %d: %s: %s"
+	schemaRefPrefix       string = "#/$defs/"
+	rootPath              string = "document"
+	memberPathString      string = "%s.%s"
+	itemPathString        string = "%s[%d]"
+)
+
+// Validation messages.
+const (
+	msgUnknownMember   string = "unknown member %q"
+	msgMergeKey        string = "merge keys are not supported"
+	msgMissingMember   string = "missing required member %q"
+	msgWrongType       string = "expected %s"
+	msgNotInEnum       string = "value %q is not one of %s"
+	msgNotConst        string = "value %q must be %v"
+	msgForbidden       string = "value %q is not allowed"
+	msgTooShort        string = "value must not be empty"
+	msgOutOfRange      string = "value %s is out of range"
+	msgNoMatchingValue string = "value does not match any allowed form"
+)
+
+// ValidationError describes where a YAML document does not match the schema.
+type ValidationError struct {
+	Line    int
+	Path    string
+	Message string
+}
+
+// Error returns the line, path and message of the error.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf(validationErrorString, e.Line, e.Path, e.Message)
+}
+
+// Unwrap returns ErrInvalidDocument.
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidDocument
+}
+
+// schemaDefs holds the definitions of the embedded schema, and rootSchema the schema itself.
+var rootSchema, schemaDefs = parseSchema()
+
+// parseSchema returns the embedded schema and its definitions.
+func parseSchema() (root map[string]interface{}, defs map[string]interface{}) {
+	if err := json.Unmarshal(schema, &root); err != nil {
+		panic(err)
+	}
+	defs, _ = root["$defs"].(map[string]interface{})
+
+	return
+}
+
+// Validate checks a YAML document against the schema of the diagram documents and returns
+// a *ValidationError for the first mismatch. Plain scalars such as 2020 or 1.0 are retagged
+// as strings where the schema expects a string, so that they convert to JSON strings.
+func Validate(node *yaml.Node) error {
+	_, err := validate(resolve(node), rootSchema, rootPath, true)
+	return err
+}
+
+// resolve returns the content of document nodes and the target of aliases.
+func resolve(node *yaml.Node) *yaml.Node {
+	for {
+		switch {
+		case node.Kind == yaml.DocumentNode && len(node.Content) > 0:
+			node = node.Content[0]
+		case node.Kind == yaml.AliasNode:
+			node = node.Alias
+		default:
+			return node
+		}
+	}
+}
+
+// validate checks a node against a schema and returns the mapping members the schema
+// evaluated. Strings are only coerced outside of conditional subschemas, whose failures
+// are expected.
+func validate(node *yaml.Node, schema map[string]interface{}, path string, coerce bool) (evaluated map[string]bool, err error) {
+	evaluated = make(map[string]bool)
+	merge := func(members map[string]bool) {
+		for name := range members {
+			evaluated[name] = true
+		}
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		members, err := validate(node, definition(ref), path, coerce)
+		if err != nil {
+			return nil, err
+		}
+		merge(members)
+	}
+
+	for _, sub := range schemaList(schema["allOf"]) {
+		members, err := validate(node, sub, path, coerce)
+		if err != nil {
+			return nil, err
+		}
+		merge(members)
+	}
+
+	if condition, ok := schema["if"].(map[string]interface{}); ok {
+		if _, err := validate(node, condition, path, false); err == nil {
+			if then, ok := schema["then"].(map[string]interface{}); ok {
+				members, err := validate(node, then, path, coerce)
+				if err != nil {
+					return nil, err
+				}
+				merge(members)
+			}
+		}
+	}
+
+	if alternatives := schemaList(schema["oneOf"]); len(alternatives) > 0 {
+		matches := 0
+		for _, sub := range alternatives {
+			if _, err := validate(node, sub, path, false); err == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return nil, invalid(node, path, msgNoMatchingValue)
+		}
+	}
+
+	if forbidden, ok := schema["not"].(map[string]interface{}); ok {
+		if _, err := validate(node, forbidden, path, false); err == nil {
+			return nil, invalid(node, path, fmt.Sprintf(msgForbidden, node.Value))
+		}
+	}
+
+	if err := validateValue(node, schema, path, coerce); err != nil {
+		return nil, err
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		members, err := validateMembers(node, schema, evaluated, path, coerce)
+		if err != nil {
+			return nil, err
+		}
+		merge(members)
+
+	case yaml.SequenceNode:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range node.Content {
+				if _, err := validate(resolve(item), items, fmt.Sprintf(itemPathString, path, i), coerce); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return evaluated, nil
+}
+
+// validateValue checks the type, enumeration, constant and bounds of a node.
+func validateValue(node *yaml.Node, schema map[string]interface{}, path string, coerce bool) error {
+	if schemaType, ok := schema["type"].(string); ok && !hasType(node, schemaType) {
+		if coerce && schemaType == "string" && node.Kind == yaml.ScalarNode && node.Style == 0 && node.ShortTag() != "!!null" {
+			node.Tag = "!!str"
+		} else {
+			return invalid(node, path, fmt.Sprintf(msgWrongType, schemaType))
+		}
+	}
+
+	if values, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		names := make([]string, 0, len(values))
+		for _, value := range values {
+			name := fmt.Sprint(value)
+			names = append(names, strconv.Quote(name))
+			found = found || (node.Kind == yaml.ScalarNode && node.Value == name)
+		}
+		if !found {
+			return invalid(node, path, fmt.Sprintf(msgNotInEnum, node.Value, strings.Join(names, ", ")))
+		}
+	}
+
+	if value, ok := schema["const"]; ok && (node.Kind != yaml.ScalarNode || node.Value != fmt.Sprint(value)) {
+		return invalid(node, path, fmt.Sprintf(msgNotConst, node.Value, value))
+	}
+
+	if minLength, ok := schema["minLength"].(float64); ok && utf8.RuneCountInString(node.Value) < int(minLength) {
+		return invalid(node, path, msgTooShort)
+	}
+
+	minimum, hasMinimum := schema["minimum"].(float64)
+	maximum, hasMaximum := schema["maximum"].(float64)
+	if hasMinimum || hasMaximum {
+		var number float64
+		if err := node.Decode(&number); err != nil {
+			return invalid(node, path, fmt.Sprintf(msgWrongType, "number"))
+		}
+		if (hasMinimum && number < minimum) || (hasMaximum && number > maximum) {
+			return invalid(node, path, fmt.Sprintf(msgOutOfRange, node.Value))
+		}
+	}
+
+	return nil
+}
+
+// validateMembers checks the members of a mapping node and returns the members it
+// evaluated. Members are checked in document order, so that the first error is reported.
+func validateMembers(node *yaml.Node, schema map[string]interface{}, evaluated map[string]bool, path string, coerce bool) (map[string]bool, error) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	additional := schema["additionalProperties"]
+	members := make(map[string]bool)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], resolve(node.Content[i+1])
+		if key.Tag == "!!merge" {
+			return nil, invalid(key, path, msgMergeKey)
+		}
+		memberPath := fmt.Sprintf(memberPathString, path, key.Value)
+
+		if property, ok := properties[key.Value].(map[string]interface{}); ok {
+			members[key.Value] = true
+			if _, err := validate(value, property, memberPath, coerce); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		switch additional := additional.(type) {
+		case bool:
+			if !additional {
+				return nil, invalid(key, path, fmt.Sprintf(msgUnknownMember, key.Value))
+			}
+			members[key.Value] = true
+		case map[string]interface{}:
+			members[key.Value] = true
+			if _, err := validate(value, additional, memberPath, coerce); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, name := range requiredNames(schema["required"]) {
+		if !hasMember(node, name) {
+			return nil, invalid(node, path, fmt.Sprintf(msgMissingMember, name))
+		}
+	}
+
+	if unevaluated, ok := schema["unevaluatedProperties"].(bool); ok && !unevaluated {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if !members[key.Value] && !evaluated[key.Value] {
+				return nil, invalid(key, path, fmt.Sprintf(msgUnknownMember, key.Value))
+			}
+		}
+	}
+
+	return members, nil
+}
+
+// hasType reports whether a node has the given JSON Schema type.
+func hasType(node *yaml.Node, schemaType string) bool {
+	switch schemaType {
+	case "object":
+		return node.Kind == yaml.MappingNode
+	case "array":
+		return node.Kind == yaml.SequenceNode
+	}
+	if node.Kind != yaml.ScalarNode {
+		return false
+	}
+
+	tag := node.ShortTag()
+	switch schemaType {
+	case "string":
+		return tag == "!!str"
+	case "integer":
+		return tag == "!!int"
+	case "number":
+		return tag == "!!int" || tag == "!!float"
+	case "boolean":
+		return tag == "!!bool"
+	}
+
+	return false
+}
+
+// hasMember reports whether a mapping node has a member with the given name.
+func hasMember(node *yaml.Node, name string) bool {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// definition returns the schema definition a reference points to.
+func definition(ref string) map[string]interface{} {
+	def, _ := schemaDefs[strings.TrimPrefix(ref, schemaRefPrefix)].(map[string]interface{})
+	return def
+}
+
+// schemaList returns the subschemas of a list keyword such as allOf.
+func schemaList(value interface{}) (schemas []map[string]interface{}) {
+	list, _ := value.([]interface{})
+	for _, item := range list {
+		if sub, ok := item.(map[string]interface{}); ok {
+			schemas = append(schemas, sub)
+		}
+	}
+
+	return
+}
+
+// requiredNames returns the member names of a required keyword.
+func requiredNames(value interface{}) (names []string) {
+	list, _ := value.([]interface{})
+	for _, item := range list {
+		names = append(names, fmt.Sprint(item))
+	}
+
+	return
+}
+
+// invalid returns the validation error for a node.
+func invalid(node *yaml.Node, path string, message string) error {
+	return &ValidationError{Line: node.Line, Path: path, Message: message}
+}
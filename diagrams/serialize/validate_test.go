@@ -0,0 +1,96 @@
+package serialize
+
+import (
+	"errors"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantJSON string
+		wantErr  string
+	}{
+		{
+			name:     "Plain scalars become strings",
+			input:    "version: 1\ntype: timeline\nsections:\n  - title: 2020\n    events:\n      - title: 1.0\n        text: yes\n",
+			wantJSON: `{"version":1,"type":"timeline","sections":[{"title":"2020","events":[{"title":"1.0","text":"yes"}]}]}`,
+		},
+		{
+			name:     "Property values keep their type",
+			input:    "version: 1\ntype: timeline\nconfig:\n  properties:\n    padding: 2\n    taskFontFamily: arial\n",
+			wantJSON: `{"version":1,"type":"timeline","config":{"properties":{"padding":2,"taskFontFamily":"arial"}}}`,
+		},
+		{
+			name:    "Missing version",
+			input:   "type: flowchart\n",
+			wantErr: `line 1: document: missing required member "version"`,
+		},
+		{
+			name:    "Unknown member",
+			input:   "version: 1\ntype: flowchart\nnodes:\n  - id: a\n    txt: A\n",
+			wantErr: `line 5: document.nodes[0]: unknown member "txt"`,
+		},
+		{
+			name:    "Unknown member of the diagram type",
+			input:   "version: 1\ntype: state\nnodes: []\n",
+			wantErr: `line 3: document: unknown member "nodes"`,
+		},
+		{
+			name:    "Value not in enumeration",
+			input:   "version: 1\ntype: block\nblocks:\n  - id: a\n    shape: star\n",
+			wantErr: `line 5: document.blocks[0].shape: value "star" is not one of "default", "roundEdges", "stadium", "subroutine", "cylindrical", "circle", "asymmetric", "rhombus", "hexagon", "parallelogram", "trapezoid", "trapezoidAlt", "doubleCircle"`,
+		},
+		{
+			name:    "Wrong type",
+			input:   "version: 1\ntype: sequence\nactors: {id: a}\n",
+			wantErr: `line 3: document.actors: expected array`,
+		},
+		{
+			name:    "Out of range",
+			input:   "version: 1\ntype: userjourney\nsections:\n  - title: S\n    tasks:\n      - {title: T, score: 6}\n",
+			wantErr: `line 6: document.sections[0].tasks[0].score: value 6 is out of range`,
+		},
+		{
+			name:    "Forbidden value",
+			input:   "version: 1\ntype: state\nstates:\n  - id: '[*]'\n",
+			wantErr: `line 4: document.states[0].id: value "[*]" is not allowed`,
+		},
+		{
+			name:    "Unsupported version",
+			input:   "version: 2\ntype: state\n",
+			wantErr: `line 1: document.version: value "2" must be 1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var node yaml.Node
+			if err := yaml.Unmarshal([]byte(tt.input), &node); err != nil {
+				t.Fatalf("yaml.Unmarshal() error = %v", err)
+			}
+
+			err := Validate(&node)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr || !errors.Is(err, ErrInvalidDocument) {
+					t.Errorf("Validate() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			got, err := YAMLToJSON(&node)
+			if err != nil {
+				t.Fatalf("YAMLToJSON() error = %v", err)
+			}
+			if string(got) != tt.wantJSON {
+				t.Errorf("YAMLToJSON() after Validate() = %s, want %s", got, tt.wantJSON)
+			}
+		})
+	}
+}
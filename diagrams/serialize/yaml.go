@@ -0,0 +1,233 @@
+package serialize
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrYAMLValue is returned for YAML content that has no JSON equivalent.
+var ErrYAMLValue = errors.New("unsupported YAML value")
+
+const (
+	yamlErrorString  string = "line %d: %w: %s"
+	yamlIndent       int    = 2
+	yamlMergeKey     string = "<<"
+	yamlFloatSuffix  string = ".0"
+	jsonFloatMarkers string = ".eE"
+)
+
+// MarshalYAML returns the diagram as a YAML document with the members in the order of
+// the JSON document.
+func MarshalYAML(d Diagram) ([]byte, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	node, err := jsonToYAML(decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(yamlIndent)
+	if err := encoder.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalYAML returns the diagram model described by a YAML document of any supported
+// type. The document is validated against the schema first, see Validate.
+func UnmarshalYAML(data []byte) (Diagram, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	if err := Validate(&node); err != nil {
+		return nil, err
+	}
+
+	data, err := YAMLToJSON(&node)
+	if err != nil {
+		return nil, err
+	}
+
+	return UnmarshalJSON(data)
+}
+
+// YAMLToJSON returns the JSON form of a YAML node. Integral floats keep a decimal point so
+// that they stay floats, and errors name the line of the offending node.
+func YAMLToJSON(node *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, node); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeJSON writes the JSON form of a YAML node.
+func writeJSON(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeJSON(buf, node.Content[0])
+
+	case yaml.AliasNode:
+		return writeJSON(buf, node.Alias)
+
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Kind != yaml.ScalarNode || key.Value == yamlMergeKey {
+				return fmt.Errorf(yamlErrorString, key.Line, ErrYAMLValue, "mapping keys must be scalars")
+			}
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeString(buf, key.Value)
+			buf.WriteByte(':')
+			if err := writeJSON(buf, value); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	}
+
+	return writeScalar(buf, node)
+}
+
+// writeScalar writes the JSON value of a YAML scalar according to its resolved tag.
+// Scalars of other tags, such as timestamps, are written as strings.
+func writeScalar(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.ShortTag() {
+	case "!!null":
+		buf.WriteString("null")
+
+	case "!!bool":
+		var b bool
+		if err := node.Decode(&b); err != nil {
+			return fmt.Errorf(yamlErrorString, node.Line, ErrYAMLValue, err)
+		}
+		buf.WriteString(strconv.FormatBool(b))
+
+	case "!!int":
+		var i int64
+		if err := node.Decode(&i); err != nil {
+			return fmt.Errorf(yamlErrorString, node.Line, ErrYAMLValue, err)
+		}
+		buf.WriteString(strconv.FormatInt(i, 10))
+
+	case "!!float":
+		var f float64
+		if err := node.Decode(&f); err != nil {
+			return fmt.Errorf(yamlErrorString, node.Line, ErrYAMLValue, err)
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf(yamlErrorString, node.Line, ErrYAMLValue, node.Value)
+		}
+		number := strconv.FormatFloat(f, 'g', -1, 64)
+		if !strings.ContainsAny(number, jsonFloatMarkers) {
+			number += yamlFloatSuffix
+		}
+		buf.WriteString(number)
+
+	default:
+		writeString(buf, node.Value)
+	}
+
+	return nil
+}
+
+// writeString writes a JSON string.
+func writeString(buf *bytes.Buffer, s string) {
+	data, _ := json.Marshal(s)
+	buf.Write(data)
+}
+
+// jsonToYAML returns the YAML node of the next JSON value of the decoder, keeping the
+// order of object members and the literal form of numbers.
+func jsonToYAML(decoder *json.Decoder) (*yaml.Node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := token.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if value == '{' {
+			node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		for decoder.More() {
+			if node.Kind == yaml.MappingNode {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, scalar("!!str", key.(string)))
+			}
+			item, err := jsonToYAML(decoder)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, item)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return node, nil
+
+	case json.Number:
+		if strings.ContainsAny(value.String(), jsonFloatMarkers) {
+			return scalar("!!float", value.String()), nil
+		}
+		return scalar("!!int", value.String()), nil
+
+	case string:
+		return scalar("!!str", value), nil
+
+	case bool:
+		return scalar("!!bool", strconv.FormatBool(value)), nil
+	}
+
+	return scalar("!!null", "null"), nil
+}
+
+// scalar returns a YAML scalar node.
+func scalar(tag string, value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+}
@@ -0,0 +1,152 @@
+package serialize
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/timeline"
+	"gopkg.in/yaml.v3"
+)
+
+func TestYAMLRoundTrip(t *testing.T) {
+	for _, original := range testDiagrams() {
+		t.Run(reflect.TypeOf(original).String(), func(t *testing.T) {
+			data, err := MarshalYAML(original)
+			if err != nil {
+				t.Fatalf("MarshalYAML() error = %v", err)
+			}
+
+			decoded, err := UnmarshalYAML(data)
+			if err != nil {
+				t.Fatalf("UnmarshalYAML() error = %v", err)
+			}
+			if decoded.String() != original.String() {
+				t.Errorf("round trip output differs:\nwant:\n%s\ngot:\n%s", original.String(), decoded.String())
+			}
+		})
+	}
+}
+
+func TestMarshalYAML(t *testing.T) {
+	tl := timeline.NewDiagram()
+	tl.Config.SetPadding(2).SetDiagramMarginX(3)
+	section := tl.AddSection("true")
+	section.AddEvent("2020", "two\nlines")
+	section.AddEvent("", "null")
+
+	got, err := MarshalYAML(tl)
+	if err != nil {
+		t.Fatalf("MarshalYAML() error = %v", err)
+	}
+
+	want := `version: 1
+type: timeline
+config:
+  properties:
+    diagramMarginX: 3
+    padding: 2.0
+sections:
+  - title: "true"
+    events:
+      - title: "2020"
+        text: |-
+          two
+          lines
+      - text: "null"
+`
+	if string(got) != want {
+		t.Errorf("MarshalYAML() =\n%s\nwant\n%s", got, want)
+	}
+
+	decoded, err := UnmarshalYAML(got)
+	if err != nil {
+		t.Fatalf("UnmarshalYAML() error = %v", err)
+	}
+	if decoded.String() != tl.String() {
+		t.Errorf("round trip output differs:\nwant:\n%s\ngot:\n%s", tl.String(), decoded.String())
+	}
+}
+
+func TestUnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "Anchors and aliases",
+			input: `version: 1
+type: flowchart
+nodes:
+  - &start {id: a, text: Start}
+links:
+  - {from: a, to: a}
+`,
+			want: "Start",
+		},
+		{
+			name:    "Unknown type",
+			input:   "version: 1\ntype: pie\n",
+			wantErr: ErrInvalidDocument,
+		},
+		{
+			name:    "Merge keys",
+			input:   "version: 1\ntype: flowchart\nnodes:\n  - &base {id: a}\n  - <<: *base\n",
+			wantErr: ErrInvalidDocument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalYAML([]byte(tt.input))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UnmarshalYAML() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			fc := got.(*flowchart.Flowchart)
+			if node := fc.FindNode("a"); node == nil || node.Text != tt.want || len(fc.Links()) != 1 {
+				t.Errorf("UnmarshalYAML() =\n%s\nwant node a %q linked to itself", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestYAMLToJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{name: "Scalars", input: "a: 1\nb: 1.0\nc: 1.5e3\nd: yes\ne: true\nf: ~\ng: 0x10\n",
+			want: `{"a":1,"b":1.0,"c":1500.0,"d":"yes","e":true,"f":null,"g":16}`},
+		{name: "Order is kept", input: "z: 1\na: [x, 'y']\n", want: `{"z":1,"a":["x","y"]}`},
+		{name: "Timestamps are strings", input: "date: 2020-01-02\n", want: `{"date":"2020-01-02"}`},
+		{name: "Empty document", input: "", want: `null`},
+		{name: "Infinity", input: "a: .inf\n", wantErr: ErrYAMLValue},
+		{name: "Mapping key", input: "? [a]\n: 1\n", wantErr: ErrYAMLValue},
+		{name: "Merge key", input: "a: &a {b: 1}\nc: {<<: *a}\n", wantErr: ErrYAMLValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var node yaml.Node
+			if err := yaml.Unmarshal([]byte(tt.input), &node); err != nil {
+				t.Fatalf("yaml.Unmarshal() error = %v", err)
+			}
+
+			got, err := YAMLToJSON(&node)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("YAMLToJSON() error = %v, want %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("YAMLToJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
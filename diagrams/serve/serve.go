@@ -0,0 +1,263 @@
+// Package serve exposes a live diagram over HTTP, so that a running service can publish
+// its own topology.
+//
+// A Handler asks its callback for the current diagram on every request and writes it in
+// the format selected by the format query parameter or, without it, negotiated from the
+// Accept header: raw Mermaid syntax, a markdown mermaid fenced block, the JSON document of
+// the model (see package serialize) or the HTML page of package render/html. Every
+// response carries an ETag derived from its content, so that dashboards polling with
+// If-None-Match get a 304 response while the diagram does not change.
+package serve
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/serialize"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+	"github.com/TyphonHill/go-mermaid/render/html"
+)
+
+// Format is a representation of a diagram.
+type Format string
+
+// Supported formats, in the order preferred when the Accept header does not decide.
+const (
+	FormatMermaid  Format = "mermaid"
+	FormatMarkdown Format = "markdown"
+	FormatJSON     Format = "json"
+	FormatHTML     Format = "html"
+)
+
+// FormatParam is the query parameter selecting the format of the response.
+const FormatParam string = "format"
+
+// ErrNoDiagram may be returned by the callback of a Handler when there is no diagram to
+// serve. The response is a 404.
+var ErrNoDiagram = errors.New("no diagram")
+
+const (
+	defaultTitle     string = "Diagram"
+	etagString       string = `"%s"`
+	etagSize         int    = 16
+	fencedString     string = "```mermaid\n%s\n```\n"
+	newline          string = "\n"
+	acceptSeparator  string = ","
+	qualityParam     string = "q"
+	wildcard         string = "*/*"
+	wildcardSubtype  string = "/*"
+	unknownFormat    string = "unknown format %q, want one of mermaid, markdown, json or html"
+	allowedMethods   string = "GET, HEAD"
+	noCacheValue     string = "no-cache"
+	varyValue        string = "Accept"
+	contentTypeKey   string = "Content-Type"
+	etagKey          string = "ETag"
+	cacheControlKey  string = "Cache-Control"
+	varyKey          string = "Vary"
+	allowKey         string = "Allow"
+	acceptKey        string = "Accept"
+	mermaidMediaType string = "text/vnd.mermaid"
+	plainMediaType   string = "text/plain"
+)
+
+// formats lists the formats with the media types they are negotiated from. The first
+// media type is the content type of the response.
+var formats = []struct {
+	format      Format
+	contentType string
+	mediaTypes  []string
+}{
+	{FormatMermaid, "text/plain; charset=utf-8", []string{plainMediaType, mermaidMediaType}},
+	{FormatMarkdown, "text/markdown; charset=utf-8", []string{"text/markdown", "text/x-markdown"}},
+	{FormatJSON, "application/json", []string{"application/json"}},
+	{FormatHTML, "text/html; charset=utf-8", []string{"text/html", "application/xhtml+xml"}},
+}
+
+// Handler serves the diagram returned by its callback.
+type Handler struct {
+	// Diagram returns the diagram to serve for a request. Returning ErrNoDiagram gives a 404
+	// response, and other errors a 500 response.
+	Diagram func(r *http.Request) (serialize.Diagram, error)
+	// ScriptPath is the path of the mermaid.js script loaded by the HTML page, relative to
+	// the page, html.DefaultScriptPath when empty. The service serves it, so that the page
+	// does not load anything from the network.
+	ScriptPath string
+	// Script is the mermaid.js script embedded in the HTML page instead, if not nil.
+	Script []byte
+	// Title is the title of the HTML page, "Diagram" when empty.
+	Title string
+	// Failed is called with the errors of the callback other than ErrNoDiagram, if not nil.
+	// They are not written to the response.
+	Failed func(err error)
+}
+
+// NewHandler returns a handler serving the diagram returned by a callback.
+func NewHandler(diagram func(r *http.Request) (serialize.Diagram, error)) *Handler {
+	return &Handler{Diagram: diagram}
+}
+
+// ServeHTTP writes the current diagram in the requested format.
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		rw.Header().Set(allowKey, allowedMethods)
+		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	format, contentType, err := negotiate(r)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if format == "" {
+		http.Error(rw, http.StatusText(http.StatusNotAcceptable), http.StatusNotAcceptable)
+		return
+	}
+
+	diagram, err := h.Diagram(r)
+	if err == nil && diagram == nil {
+		err = ErrNoDiagram
+	}
+	if errors.Is(err, ErrNoDiagram) {
+		http.NotFound(rw, r)
+		return
+	}
+	if err != nil {
+		h.fail(rw, err)
+		return
+	}
+
+	body, err := h.Render(diagram, format)
+	if err != nil {
+		h.fail(rw, err)
+		return
+	}
+
+	header := rw.Header()
+	header.Set(contentTypeKey, contentType)
+	header.Set(etagKey, ETag(body))
+	header.Set(cacheControlKey, noCacheValue)
+	header.Add(varyKey, varyValue)
+
+	http.ServeContent(rw, r, "", time.Time{}, bytes.NewReader(body))
+}
+
+// Render returns a diagram in a format.
+func (h *Handler) Render(diagram serialize.Diagram, format Format) ([]byte, error) {
+	source := basediagram.StripFence(diagram.String())
+
+	switch format {
+	case FormatMermaid:
+		return []byte(source), nil
+	case FormatMarkdown:
+		return []byte(fmt.Sprintf(fencedString, strings.TrimRight(source, newline))), nil
+	case FormatJSON:
+		return serialize.MarshalJSON(diagram)
+	case FormatHTML:
+		title := h.Title
+		if title == "" {
+			title = defaultTitle
+		}
+		page := html.NewPage(title).AddDiagram(title, diagram)
+		if h.Script != nil {
+			page.SetScript(h.Script)
+		} else {
+			page.SetScriptPath(h.ScriptPath)
+		}
+		return []byte(page.String()), nil
+	}
+
+	return nil, fmt.Errorf(unknownFormat, format)
+}
+
+// fail reports an error and writes a 500 response without its details.
+func (h *Handler) fail(rw http.ResponseWriter, err error) {
+	if h.Failed != nil {
+		h.Failed(err)
+	}
+	http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// ETag returns the entity tag of a response body, a truncated SHA-256 of the content.
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf(etagString, hex.EncodeToString(sum[:etagSize]))
+}
+
+// negotiate returns the format of the response and its content type. The format query
+// parameter takes precedence over the Accept header; an empty format means that no
+// supported format is acceptable.
+func negotiate(r *http.Request) (format Format, contentType string, err error) {
+	if name := r.URL.Query().Get(FormatParam); name != "" {
+		for _, candidate := range formats {
+			if string(candidate.format) == name {
+				return candidate.format, candidate.contentType, nil
+			}
+		}
+		return "", "", fmt.Errorf(unknownFormat, name)
+	}
+
+	accept := r.Header.Get(acceptKey)
+	if strings.TrimSpace(accept) == "" {
+		return formats[0].format, formats[0].contentType, nil
+	}
+
+	best, bestQuality, bestSpecificity := -1, 0.0, -1
+	for _, entry := range strings.Split(accept, acceptSeparator) {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(entry))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params[qualityParam]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		for i, candidate := range formats {
+			specificity := matches(mediaType, candidate.mediaTypes)
+			if specificity < 0 {
+				continue
+			}
+			if quality > bestQuality || (quality == bestQuality && specificity > bestSpecificity) {
+				best, bestQuality, bestSpecificity = i, quality, specificity
+			}
+		}
+	}
+
+	if best < 0 {
+		return "", "", nil
+	}
+
+	return formats[best].format, formats[best].contentType, nil
+}
+
+// matches returns how specifically an accepted media type matches one of the media types
+// of a format: 2 for an exact match, 1 for a type wildcard, 0 for */*, and -1 otherwise.
+func matches(accepted string, mediaTypes []string) int {
+	specificity := -1
+	for _, mediaType := range mediaTypes {
+		switch {
+		case accepted == mediaType:
+			return 2
+		case strings.HasSuffix(accepted, wildcardSubtype) && strings.HasPrefix(mediaType, strings.TrimSuffix(accepted, "*")):
+			specificity = 1
+		case accepted == wildcard && specificity < 0:
+			specificity = 0
+		}
+	}
+
+	return specificity
+}
@@ -0,0 +1,303 @@
+package serve
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/serialize"
+	"github.com/TyphonHill/go-mermaid/render/html"
+)
+
+// topology returns a flowchart with a markdown fence, which raw formats must strip.
+func topology() serialize.Diagram {
+	d := flowchart.NewFlowchart()
+	d.EnableMarkdownFence()
+	api := flowchart.NewNode("api", "API <v2>")
+	db := flowchart.NewNode("db", "DB")
+	d.AddNode(api)
+	d.AddNode(db)
+	d.AddLink(flowchart.NewLink(api, db))
+	return d
+}
+
+func TestHandler_Formats(t *testing.T) {
+	tests := []struct {
+		name            string
+		target          string
+		accept          string
+		wantCode        int
+		wantContentType string
+		contains        []string
+		excludes        []string
+	}{
+		{
+			name:            "Default is raw Mermaid",
+			target:          "/",
+			wantCode:        http.StatusOK,
+			wantContentType: "text/plain; charset=utf-8",
+			contains:        []string{"flowchart TB\n", "api --> db"},
+			excludes:        []string{"```"},
+		},
+		{
+			name:            "Any type is raw Mermaid",
+			target:          "/",
+			accept:          "*/*",
+			wantCode:        http.StatusOK,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:            "Mermaid media type",
+			target:          "/",
+			accept:          "text/vnd.mermaid",
+			wantCode:        http.StatusOK,
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:            "Markdown from Accept",
+			target:          "/",
+			accept:          "text/markdown",
+			wantCode:        http.StatusOK,
+			wantContentType: "text/markdown; charset=utf-8",
+			contains:        []string{"```mermaid\n---\n", "api --> db\n```\n"},
+		},
+		{
+			name:            "JSON from Accept with qualities",
+			target:          "/",
+			accept:          "text/plain;q=0.5, application/json",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/json",
+			contains:        []string{`"type": "flowchart"`},
+		},
+		{
+			name:            "Browser gets HTML",
+			target:          "/",
+			accept:          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			wantCode:        http.StatusOK,
+			wantContentType: "text/html; charset=utf-8",
+			contains:        []string{`<pre class="mermaid">`, "API &lt;v2&gt;", `<script src="` + html.DefaultScriptPath + `">`, "<title>Diagram</title>"},
+		},
+		{
+			name:            "Query parameter overrides Accept",
+			target:          "/?format=json",
+			accept:          "text/html",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/json",
+		},
+		{
+			name:     "Unknown format",
+			target:   "/?format=png",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Not acceptable",
+			target:   "/",
+			accept:   "image/png, text/plain;q=0",
+			wantCode: http.StatusNotAcceptable,
+		},
+	}
+
+	h := NewHandler(func(r *http.Request) (serialize.Diagram, error) {
+		return topology(), nil
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+			if got := rec.Header().Get("ETag"); got != ETag(rec.Body.Bytes()) {
+				t.Errorf("ETag = %q, want %q", got, ETag(rec.Body.Bytes()))
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(rec.Body.String(), want) {
+					t.Errorf("body missing %q in:\n%s", want, rec.Body.String())
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(rec.Body.String(), unwanted) {
+					t.Errorf("body contains %q in:\n%s", unwanted, rec.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_JSONModel(t *testing.T) {
+	h := NewHandler(func(r *http.Request) (serialize.Diagram, error) {
+		return topology(), nil
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?format=json", nil))
+
+	d, err := serialize.UnmarshalJSON(rec.Body.Bytes())
+	if err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+	if d.String() != topology().String() {
+		t.Errorf("decoded model = %q, want %q", d.String(), topology().String())
+	}
+	if !json.Valid(rec.Body.Bytes()) {
+		t.Errorf("body = %s, want JSON", rec.Body.String())
+	}
+}
+
+func TestHandler_ETag(t *testing.T) {
+	label := "DB"
+	h := NewHandler(func(r *http.Request) (serialize.Diagram, error) {
+		d := flowchart.NewFlowchart()
+		d.AddNode(flowchart.NewNode("db", label))
+		return d, nil
+	})
+
+	get := func(etag string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if etag != "" {
+			r.Header.Set("If-None-Match", etag)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		return rec
+	}
+
+	first := get("")
+	etag := first.Header().Get("ETag")
+	if etag == "" || first.Header().Get("Vary") != "Accept" {
+		t.Fatalf("headers = %v, want an ETag varying with Accept", first.Header())
+	}
+
+	if rec := get(etag); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("unchanged diagram: status = %d, body = %q, want 304 without body", rec.Code, rec.Body.String())
+	}
+
+	label = "Database"
+	rec := get(etag)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Errorf("changed diagram: status = %d, ETag = %q, want 200 with a new ETag", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+func TestHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		diagram    serialize.Diagram
+		err        error
+		wantCode   int
+		wantFailed bool
+	}{
+		{
+			name:     "No diagram",
+			method:   http.MethodGet,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "ErrNoDiagram",
+			method:   http.MethodGet,
+			err:      ErrNoDiagram,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:       "Callback error",
+			method:     http.MethodGet,
+			err:        errors.New("registry unavailable"),
+			wantCode:   http.StatusInternalServerError,
+			wantFailed: true,
+		},
+		{
+			name:     "Head",
+			method:   http.MethodHead,
+			diagram:  topology(),
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Post",
+			method:   http.MethodPost,
+			diagram:  topology(),
+			wantCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var failed error
+			h := NewHandler(func(r *http.Request) (serialize.Diagram, error) {
+				return tt.diagram, tt.err
+			})
+			h.Failed = func(err error) { failed = err }
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, "/", nil))
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if (failed != nil) != tt.wantFailed {
+				t.Errorf("Failed called with %v, want called = %v", failed, tt.wantFailed)
+			}
+			if tt.wantFailed && strings.Contains(rec.Body.String(), tt.err.Error()) {
+				t.Errorf("body = %q, want no error details", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_Render(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler *Handler
+		want    []string
+	}{
+		{
+			name:    "Script path",
+			handler: &Handler{Title: "Services", ScriptPath: "/static/mermaid.js"},
+			want:    []string{"<title>Services</title>", `<script src="/static/mermaid.js">`},
+		},
+		{
+			name:    "Embedded script",
+			handler: &Handler{Script: []byte("var mermaid = {};")},
+			want:    []string{"<title>Diagram</title>", "<script>var mermaid = {};</script>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := tt.handler.Render(topology(), FormatHTML)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(body), want) {
+					t.Errorf("Render() missing %q in:\n%s", want, body)
+				}
+			}
+			if strings.Contains(string(body), "https://") {
+				t.Errorf("Render() loads a script from the network:\n%s", body)
+			}
+		})
+	}
+
+	h := &Handler{}
+
+	if _, err := h.Render(topology(), Format("png")); err == nil {
+		t.Error("Render() of an unknown format error = nil, want an error")
+	}
+}
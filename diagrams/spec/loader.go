@@ -0,0 +1,621 @@
+package spec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/block"
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/serialize"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+	"gopkg.in/yaml.v3"
+)
+
+// Members of spec files and of the diagram documents the loader rewrites.
+const (
+	memberVersion  string = "version"
+	memberInclude  string = "include"
+	memberStyles   string = "styles"
+	memberDiagrams string = "diagrams"
+	memberName     string = "name"
+	memberType     string = "type"
+	memberID       string = "id"
+	memberNodes    string = "nodes"
+	memberClasses  string = "classes"
+	memberClass    string = "class"
+	memberStyle    string = "style"
+	memberBlocks   string = "blocks"
+	memberChildren string = "children"
+)
+
+const (
+	msgNotMapping      string = "%w: %s must be a mapping"
+	msgNotSequence     string = "%w: %s must be a list"
+	msgNotString       string = "%w: %s must be a string"
+	msgUnknownMember   string = "%w: unknown member %q"
+	msgVersion         string = "%w: unsupported version %q (supported: %d)"
+	msgMissingName     string = "%w: diagram has no name"
+	msgInvalidName     string = "%w: diagram name %q is not a file name"
+	msgDefinedAt       string = "%w: %s %q already defined at %s:%d"
+	msgStyleDecode     string = "%w: %v"
+	msgIncludeCycle    string = "%w: %s"
+	msgUnknownStyle    string = "%w %q"
+	msgInvalidDocument string = "%w: %s: %s"
+	cssSeparator       string = ","
+	pathSeparators     string = `/\`
+)
+
+// Kinds of named things in duplicate name errors.
+const (
+	kindDiagram string = "diagram"
+	kindStyle   string = "style"
+)
+
+// styleDocument is a shared style class of a spec file.
+type styleDocument struct {
+	Color       string `yaml:"color"`
+	Fill        string `yaml:"fill"`
+	Stroke      string `yaml:"stroke"`
+	StrokeWidth int    `yaml:"strokeWidth"`
+	StrokeDash  string `yaml:"strokeDash"`
+}
+
+// styleMembers are the members a style class may have.
+var styleMembers = map[string]bool{"color": true, "fill": true, "stroke": true, "strokeWidth": true, "strokeDash": true}
+
+// definition is a style class or diagram together with the place it was declared at.
+// Diagrams read from Mermaid syntax are parsed already.
+type definition struct {
+	name        string
+	file        string
+	line        int
+	node        *yaml.Node
+	style       *flowchart.NodeStyle
+	diagramType string
+	model       serialize.Diagram
+}
+
+// loader reads spec files and their includes. Diagrams are built once every file is
+// loaded, so that style classes are shared by all diagrams of the spec.
+type loader struct {
+	read     func(name string) ([]byte, error)
+	resolve  func(file string, include string) string
+	loaded   map[string]bool
+	loading  map[string]bool
+	files    []string
+	styles   map[string]*definition
+	names    map[string]*definition
+	diagrams []*definition
+}
+
+// Load loads the spec file at the given path and the files it includes.
+func Load(name string) (*Spec, error) {
+	return newLoader(os.ReadFile, resolveFile).load(filepath.Clean(name))
+}
+
+// Parse loads a spec from the content of the named file, such as a file read from standard
+// input. The files it includes are read from disk, relative to the directory of name.
+func Parse(name string, data []byte) (*Spec, error) {
+	name = filepath.Clean(name)
+	l := newLoader(func(file string) ([]byte, error) {
+		if file == name {
+			return data, nil
+		}
+		return os.ReadFile(file)
+	}, resolveFile)
+
+	return l.load(name)
+}
+
+// LoadFS loads the spec file with the given name from a file system and the files it
+// includes.
+func LoadFS(fsys fs.FS, name string) (*Spec, error) {
+	l := newLoader(func(name string) ([]byte, error) {
+		return fs.ReadFile(fsys, name)
+	}, func(file string, include string) string {
+		return path.Join(path.Dir(file), include)
+	})
+
+	return l.load(path.Clean(name))
+}
+
+// resolveFile returns the path on disk of a file included by another.
+func resolveFile(file string, include string) string {
+	if filepath.IsAbs(include) {
+		return filepath.Clean(include)
+	}
+
+	return filepath.Join(filepath.Dir(file), include)
+}
+
+// newLoader creates a loader reading files and resolving includes with the given functions.
+func newLoader(read func(string) ([]byte, error), resolve func(string, string) string) *loader {
+	return &loader{
+		read:    read,
+		resolve: resolve,
+		loaded:  make(map[string]bool),
+		loading: make(map[string]bool),
+		styles:  make(map[string]*definition),
+		names:   make(map[string]*definition),
+	}
+}
+
+// load loads a spec file and builds its diagrams.
+func (l *loader) load(name string) (*Spec, error) {
+	if err := l.loadFile(name); err != nil {
+		return nil, err
+	}
+
+	spec := &Spec{Styles: make(map[string]*flowchart.NodeStyle, len(l.styles)), Files: l.files}
+	for name, style := range l.styles {
+		spec.Styles[name] = style.style
+	}
+
+	for _, pending := range l.diagrams {
+		diagram, err := l.build(pending)
+		if err != nil {
+			return nil, err
+		}
+		spec.Diagrams = append(spec.Diagrams, diagram)
+	}
+
+	return spec, nil
+}
+
+// loadFile reads every YAML document of a spec file. Files already loaded through another
+// include are skipped.
+func (l *loader) loadFile(name string) error {
+	if l.loaded[name] {
+		return nil
+	}
+	l.loaded[name] = true
+	l.files = append(l.files, name)
+	l.loading[name] = true
+	defer delete(l.loading, name)
+
+	data, err := l.read(name)
+	if err != nil {
+		return &Error{File: name, Err: err}
+	}
+	if IsMermaid(name, data) {
+		return l.loadMermaid(name, data)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return &Error{File: name, Err: err}
+		}
+
+		if err := l.loadDocument(name, &doc); err != nil {
+			return err
+		}
+	}
+}
+
+// loadDocument loads one YAML document of a spec file. Includes are loaded first, so that
+// their diagrams come before the diagrams of the including file.
+func (l *loader) loadDocument(file string, doc *yaml.Node) error {
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return l.fail(file, root, fmt.Errorf(msgNotMapping, ErrInvalidSpec, "spec"))
+	}
+
+	if member(root, memberType) != nil && member(root, memberDiagrams) == nil {
+		return l.loadDiagram(file, root, fileName(file))
+	}
+
+	if include := member(root, memberInclude); include != nil {
+		if err := l.loadIncludes(file, include); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+
+		var err error
+		switch key.Value {
+		case memberInclude:
+		case memberVersion:
+			if value.Value != strconv.Itoa(basediagram.SchemaVersion) {
+				err = l.fail(file, value, fmt.Errorf(msgVersion, ErrInvalidSpec, value.Value, basediagram.SchemaVersion))
+			}
+		case memberStyles:
+			err = l.loadStyles(file, value)
+		case memberDiagrams:
+			err = l.loadDiagrams(file, value)
+		default:
+			err = l.fail(file, key, fmt.Errorf(msgUnknownMember, ErrInvalidSpec, key.Value))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadMermaid records the diagram of a file holding Mermaid syntax, named after the file.
+func (l *loader) loadMermaid(file string, data []byte) error {
+	model, err := serialize.Parse(string(data))
+	if err != nil {
+		return Locate(file, err)
+	}
+
+	name := fileName(file)
+	if previous := l.names[name]; previous != nil {
+		return &Error{File: file, Line: 1, Err: fmt.Errorf(msgDefinedAt, ErrDuplicateName, kindDiagram, name, previous.file, previous.line)}
+	}
+
+	pending := &definition{
+		name:        name,
+		file:        file,
+		line:        1,
+		diagramType: serialize.MermaidType(basediagram.SourceKeyword(string(data))),
+		model:       model,
+	}
+	l.names[name] = pending
+	l.diagrams = append(l.diagrams, pending)
+
+	return nil
+}
+
+// loadIncludes loads the files listed by an include member.
+func (l *loader) loadIncludes(file string, include *yaml.Node) error {
+	if include.Kind != yaml.SequenceNode {
+		return l.fail(file, include, fmt.Errorf(msgNotSequence, ErrInvalidSpec, memberInclude))
+	}
+
+	for _, item := range include.Content {
+		if item.Kind != yaml.ScalarNode {
+			return l.fail(file, item, fmt.Errorf(msgNotString, ErrInvalidSpec, memberInclude))
+		}
+
+		name := l.resolve(file, item.Value)
+		if l.loading[name] {
+			return l.fail(file, item, fmt.Errorf(msgIncludeCycle, ErrIncludeCycle, name))
+		}
+
+		if err := l.loadFile(name); err != nil {
+			var specErr *Error
+			if !errors.As(err, &specErr) || specErr.File != name || specErr.Line != 0 {
+				return err
+			}
+			// The included file could not be read: report the include.
+			return l.fail(file, item, err)
+		}
+	}
+
+	return nil
+}
+
+// loadStyles records the style classes of a styles member.
+func (l *loader) loadStyles(file string, styles *yaml.Node) error {
+	if styles.Kind != yaml.MappingNode {
+		return l.fail(file, styles, fmt.Errorf(msgNotMapping, ErrInvalidSpec, memberStyles))
+	}
+
+	for i := 0; i+1 < len(styles.Content); i += 2 {
+		key, value := styles.Content[i], styles.Content[i+1]
+		if value.Kind != yaml.MappingNode {
+			return l.fail(file, value, fmt.Errorf(msgNotMapping, ErrInvalidSpec, kindStyle))
+		}
+		for j := 0; j+1 < len(value.Content); j += 2 {
+			if name := value.Content[j]; !styleMembers[name.Value] {
+				return l.fail(file, name, fmt.Errorf(msgUnknownMember, ErrInvalidSpec, name.Value))
+			}
+		}
+
+		var doc styleDocument
+		if err := value.Decode(&doc); err != nil {
+			return l.fail(file, value, fmt.Errorf(msgStyleDecode, ErrInvalidSpec, err))
+		}
+
+		if previous := l.styles[key.Value]; previous != nil {
+			return l.fail(file, key, fmt.Errorf(msgDefinedAt, ErrDuplicateName, kindStyle, key.Value, previous.file, previous.line))
+		}
+		l.styles[key.Value] = &definition{
+			file: file,
+			line: key.Line,
+			node: value,
+			style: &flowchart.NodeStyle{
+				Color:       doc.Color,
+				Fill:        doc.Fill,
+				Stroke:      doc.Stroke,
+				StrokeWidth: doc.StrokeWidth,
+				StrokeDash:  doc.StrokeDash,
+			},
+		}
+	}
+
+	return nil
+}
+
+// loadDiagrams records the diagrams of a diagrams member.
+func (l *loader) loadDiagrams(file string, diagrams *yaml.Node) error {
+	if diagrams.Kind != yaml.SequenceNode {
+		return l.fail(file, diagrams, fmt.Errorf(msgNotSequence, ErrInvalidSpec, memberDiagrams))
+	}
+
+	for _, item := range diagrams.Content {
+		if item.Kind != yaml.MappingNode {
+			return l.fail(file, item, fmt.Errorf(msgNotMapping, ErrInvalidSpec, kindDiagram))
+		}
+		if err := l.loadDiagram(file, item, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadDiagram records a diagram document, named by its name member or else by the given
+// default name. The name is removed from the document and the version is added when it is
+// missing.
+func (l *loader) loadDiagram(file string, item *yaml.Node, defaultName string) error {
+	name := removeMember(item, memberName)
+	if name == nil && defaultName != "" {
+		name = &yaml.Node{Kind: yaml.ScalarNode, Value: defaultName, Line: item.Line}
+	}
+
+	switch {
+	case name == nil || name.Value == "":
+		return l.fail(file, item, fmt.Errorf(msgMissingName, ErrInvalidSpec))
+	case name.Kind != yaml.ScalarNode:
+		return l.fail(file, name, fmt.Errorf(msgNotString, ErrInvalidSpec, memberName))
+	case strings.ContainsAny(name.Value, pathSeparators) || name.Value == "." || name.Value == "..":
+		return l.fail(file, name, fmt.Errorf(msgInvalidName, ErrInvalidSpec, name.Value))
+	}
+
+	if previous := l.names[name.Value]; previous != nil {
+		return l.fail(file, name, fmt.Errorf(msgDefinedAt, ErrDuplicateName, kindDiagram, name.Value, previous.file, previous.line))
+	}
+
+	if member(item, memberVersion) == nil {
+		item.Content = append([]*yaml.Node{
+			scalar("!!str", memberVersion),
+			scalar("!!int", strconv.Itoa(basediagram.SchemaVersion)),
+		}, item.Content...)
+	}
+
+	pending := &definition{name: name.Value, file: file, line: name.Line, node: item}
+	l.names[name.Value] = pending
+	l.diagrams = append(l.diagrams, pending)
+
+	return nil
+}
+
+// build returns the diagram described by a pending diagram document.
+func (l *loader) build(pending *definition) (*Diagram, error) {
+	node := pending.node
+	diagram := &Diagram{Name: pending.name, File: pending.file, Line: pending.line}
+	if pending.model != nil {
+		diagram.Type, diagram.Model = pending.diagramType, pending.model
+		return diagram, nil
+	}
+
+	if diagramType := member(node, memberType); diagramType != nil {
+		diagram.Type = diagramType.Value
+	}
+
+	var err error
+	switch diagram.Type {
+	case flowchart.DocumentType:
+		err = l.flowchartStyles(pending.file, node)
+	case block.DocumentType:
+		err = l.blockStyles(pending.file, member(node, memberBlocks))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var validationErr *serialize.ValidationError
+	if err := serialize.Validate(node); errors.As(err, &validationErr) {
+		err = fmt.Errorf(msgInvalidDocument, serialize.ErrInvalidDocument, validationErr.Path, validationErr.Message)
+		return nil, &Error{File: pending.file, Line: validationErr.Line, Err: err}
+	} else if err != nil {
+		return nil, l.fail(pending.file, node, err)
+	}
+
+	data, err := serialize.YAMLToJSON(node)
+	if err != nil {
+		return nil, l.fail(pending.file, node, err)
+	}
+
+	if diagram.Model, err = serialize.UnmarshalJSON(data); err != nil {
+		line := node.Line
+		var elementErr *basediagram.ElementError
+		if errors.As(err, &elementErr) {
+			line = elementLine(node, elementErr)
+		}
+		return nil, &Error{File: pending.file, Line: line, Err: err}
+	}
+
+	return diagram, nil
+}
+
+// flowchartStyles adds a class definition for every shared style class used by a node of
+// a flowchart document and not defined by the flowchart itself.
+func (l *loader) flowchartStyles(file string, node *yaml.Node) error {
+	nodes := member(node, memberNodes)
+	if nodes == nil || nodes.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	classes := member(node, memberClasses)
+	if classes == nil {
+		classes = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: node.Line}
+		node.Content = append(node.Content, scalar("!!str", memberClasses), classes)
+	}
+	if classes.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	declared := make(map[string]bool)
+	for _, class := range classes.Content {
+		if name := member(class, memberName); name != nil {
+			declared[name.Value] = true
+		}
+	}
+
+	for _, item := range nodes.Content {
+		class := member(item, memberClass)
+		if class == nil || class.Kind != yaml.ScalarNode || declared[class.Value] {
+			continue
+		}
+
+		style := l.styles[class.Value]
+		if style == nil {
+			return l.fail(file, class, fmt.Errorf(msgUnknownStyle, ErrUnknownStyle, class.Value))
+		}
+		classes.Content = append(classes.Content, &yaml.Node{
+			Kind:    yaml.MappingNode,
+			Tag:     "!!map",
+			Line:    style.line,
+			Content: []*yaml.Node{scalar("!!str", memberName), scalar("!!str", class.Value), scalar("!!str", memberStyle), style.node},
+		})
+		declared[class.Value] = true
+	}
+
+	return nil
+}
+
+// blockStyles replaces the shared style class of blocks by its CSS style, placed before
+// the style of the block itself.
+func (l *loader) blockStyles(file string, blocks *yaml.Node) error {
+	if blocks == nil || blocks.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	for _, item := range blocks.Content {
+		if class := removeMember(item, memberClass); class != nil {
+			style := l.styles[class.Value]
+			if style == nil {
+				return l.fail(file, class, fmt.Errorf(msgUnknownStyle, ErrUnknownStyle, class.Value))
+			}
+
+			css := style.style.String()
+			if own := member(item, memberStyle); own == nil {
+				item.Content = append(item.Content, scalar("!!str", memberStyle), scalar("!!str", css))
+			} else if own.Value != "" {
+				own.Value = css + cssSeparator + own.Value
+			} else {
+				own.Value = css
+			}
+		}
+
+		if err := l.blockStyles(file, member(item, memberChildren)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fail returns the error found at a node of a spec file.
+func (l *loader) fail(file string, node *yaml.Node, err error) error {
+	return &Error{File: file, Line: node.Line, Err: err}
+}
+
+// elementLine returns the line of the element an error of a diagram document is about:
+// the second declaration of a duplicate ID, or else the first reference to the ID.
+func elementLine(node *yaml.Node, err *basediagram.ElementError) int {
+	var declarations, references []*yaml.Node
+	var walk func(*yaml.Node)
+	walk = func(n *yaml.Node) {
+		switch n.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				key, value := n.Content[i], n.Content[i+1]
+				switch {
+				case value.Kind != yaml.ScalarNode:
+					walk(value)
+				case value.Value != err.ID:
+				case key.Value == memberID || key.Value == memberName:
+					declarations = append(declarations, value)
+				default:
+					references = append(references, value)
+				}
+			}
+		case yaml.SequenceNode:
+			for _, item := range n.Content {
+				if item.Kind == yaml.ScalarNode && item.Value == err.ID {
+					references = append(references, item)
+				}
+				walk(item)
+			}
+		}
+	}
+	walk(node)
+
+	switch {
+	case errors.Is(err, basediagram.ErrDuplicateID) && len(declarations) > 1:
+		return declarations[1].Line
+	case len(references) > 0:
+		return references[0].Line
+	case len(declarations) > 0:
+		return declarations[0].Line
+	}
+
+	return node.Line
+}
+
+// fileName returns the name of a file without its directory and extension.
+func fileName(file string) string {
+	base := filepath.Base(file)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// member returns the value of a member of a mapping node, or nil if there is none.
+func member(node *yaml.Node, name string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// removeMember removes a member from a mapping node and returns its value, or nil if
+// there is none.
+func removeMember(node *yaml.Node, name string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			value := node.Content[i+1]
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return value
+		}
+	}
+
+	return nil
+}
+
+// scalar returns a YAML scalar node.
+func scalar(tag string, value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+}
@@ -0,0 +1,152 @@
+// Package spec loads declarative diagram spec files written in YAML and generates the
+// Mermaid files of the diagrams they describe.
+//
+// A spec file holds one or more YAML documents of the form:
+//
+//	version: 1            # optional, the diagram document schema version
+//	include:              # other spec files, relative to this file
+//	  - common.yaml
+//	styles:               # style classes shared by the diagrams
+//	  critical: {fill: "#f96", stroke: "#333", strokeWidth: 2}
+//	diagrams:
+//	  - name: checkout    # unique name, used for the generated file
+//	    type: flowchart
+//	    nodes:
+//	      - {id: pay, text: Pay, class: critical}
+//
+// Every diagram is a document of the serialize package with an additional name, and its
+// version may be omitted. A file holding a single diagram document, in YAML or JSON, is a
+// spec with one diagram named after the file, and so is a file holding Mermaid syntax, such
+// as a generated .mmd file, parsed with serialize.Parse. Flowchart nodes and blocks may use a shared
+// style class by name: flowcharts get a class definition for it, blocks get its CSS style.
+// Errors name the file and line they were found at.
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/serialize"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// FileExtension is the extension of the generated Mermaid files.
+const FileExtension string = ".mmd"
+
+// mermaidExtensions are the extensions of the files always read as Mermaid syntax.
+var mermaidExtensions = []string{FileExtension, ".mermaid"}
+
+// Errors returned when loading a spec.
+var (
+	ErrInvalidSpec   = errors.New("invalid spec")
+	ErrIncludeCycle  = errors.New("include cycle")
+	ErrDuplicateName = errors.New("duplicate name")
+	ErrUnknownStyle  = errors.New("unknown style class")
+)
+
+const (
+	errorLineString string = "%s:%d: %v"
+	errorFileString string = "%s: %v"
+)
+
+// Error is an error found at a line of a spec file. Line is 0 when the error concerns the
+// whole file.
+type Error struct {
+	File string
+	Line int
+	Err  error
+}
+
+// Error returns the file, line and reason of the error.
+func (e *Error) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf(errorFileString, e.File, e.Err)
+	}
+
+	return fmt.Sprintf(errorLineString, e.File, e.Line, e.Err)
+}
+
+// Unwrap returns the reason of the error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Locate returns the errors of parsing the Mermaid source of a file, see serialize.Parse,
+// as errors of the file, at their line when they have one. Joined errors, such as the
+// unsupported statements of the source, are located one by one.
+func Locate(file string, err error) error {
+	errs := make([]error, 0, 1)
+	for _, err := range basediagram.Split(err) {
+		var syntaxErr *basediagram.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			errs = append(errs, &Error{File: file, Line: syntaxErr.Line, Err: syntaxErr.Err})
+		} else {
+			errs = append(errs, &Error{File: file, Err: err})
+		}
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+
+	return errors.Join(errs...)
+}
+
+// Spec holds the diagrams and style classes of a spec file and the files it includes.
+type Spec struct {
+	Diagrams []*Diagram
+	Styles   map[string]*flowchart.NodeStyle
+	// Files lists the spec file and the files it includes, in load order.
+	Files []string
+}
+
+// Diagram is a diagram of a spec with the place it was declared at.
+type Diagram struct {
+	Name  string
+	Type  string
+	File  string
+	Line  int
+	Model serialize.Diagram
+}
+
+// IsMermaid reports whether the content of a file is Mermaid syntax rather than YAML or
+// JSON: the content of files with the .mmd or .mermaid extension, and other content
+// declaring a diagram type that serialize.Parse parses.
+func IsMermaid(name string, data []byte) bool {
+	extension := filepath.Ext(name)
+	for _, candidate := range mermaidExtensions {
+		if strings.EqualFold(extension, candidate) {
+			return true
+		}
+	}
+
+	return serialize.MermaidType(basediagram.SourceKeyword(string(data))) != ""
+}
+
+// Find returns the diagram with the given name, or nil if there is none.
+func (s *Spec) Find(name string) *Diagram {
+	for _, diagram := range s.Diagrams {
+		if diagram.Name == name {
+			return diagram
+		}
+	}
+
+	return nil
+}
+
+// Generate writes every diagram of the spec to a Mermaid file named after the diagram in
+// the given directory, and returns the paths of the written files.
+func (s *Spec) Generate(dir string) (paths []string, err error) {
+	for _, diagram := range s.Diagrams {
+		path := filepath.Join(dir, diagram.Name+FileExtension)
+		if err = utils.RenderToFile(path, diagram.Model.String()); err != nil {
+			return
+		}
+		paths = append(paths, path)
+	}
+
+	return
+}
@@ -0,0 +1,451 @@
+package spec
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/block"
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/sequence"
+	"github.com/TyphonHill/go-mermaid/diagrams/serialize"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+const mainSpec = `include:
+  - shared/styles.yaml
+diagrams:
+  - name: checkout
+    type: flowchart
+    nodes:
+      - {id: cart, text: Cart}
+      - {id: pay, text: Pay, class: critical}
+    links:
+      - {from: cart, to: pay}
+  - name: layout
+    type: block
+    blocks:
+      - {id: api, text: API, class: critical, style: "stroke-dasharray:3"}
+      - id: group
+        children:
+          - {id: db, text: DB, class: muted}
+---
+diagrams:
+  - name: login
+    type: sequence
+    title: 2020
+`
+
+const stylesSpec = `version: 1
+styles:
+  critical: {fill: "#f96", stroke: "#333", strokeWidth: 2}
+  muted: {color: "#999"}
+diagrams:
+  - name: shared
+    type: timeline
+    title: Shared
+`
+
+func TestLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"specs/main.yaml":          {Data: []byte(mainSpec)},
+		"specs/shared/styles.yaml": {Data: []byte(stylesSpec)},
+	}
+
+	spec, err := LoadFS(fsys, "specs/main.yaml")
+	if err != nil {
+		t.Fatalf("LoadFS() error = %v", err)
+	}
+
+	var names []string
+	for _, diagram := range spec.Diagrams {
+		names = append(names, diagram.Name)
+	}
+	if got, want := strings.Join(names, ","), "shared,checkout,layout,login"; got != want {
+		t.Errorf("diagram names = %q, want %q", got, want)
+	}
+
+	if len(spec.Styles) != 2 || spec.Styles["critical"].StrokeWidth != 2 {
+		t.Errorf("Styles = %v, want critical and muted", spec.Styles)
+	}
+	if got, want := strings.Join(spec.Files, ","), "specs/main.yaml,specs/shared/styles.yaml"; got != want {
+		t.Errorf("Files = %q, want %q", got, want)
+	}
+
+	checkout := spec.Find("checkout")
+	if checkout == nil || checkout.Type != flowchart.DocumentType || checkout.File != "specs/main.yaml" || checkout.Line != 4 {
+		t.Fatalf("Find(checkout) = %+v", checkout)
+	}
+	fc := checkout.Model.(*flowchart.Flowchart)
+	if got := fc.String(); !strings.Contains(got, "classDef critical fill:#f96,stroke:#333,stroke-width:2") {
+		t.Errorf("checkout does not define the shared class:\n%s", got)
+	}
+
+	layout := spec.Find("layout").Model.(*block.Diagram)
+	if got := layout.FindBlock("api").Style; got != "fill:#f96,stroke:#333,stroke-width:2,stroke-dasharray:3" {
+		t.Errorf("api style = %q", got)
+	}
+	if got := layout.FindBlock("db").Style; got != "color:#999" {
+		t.Errorf("db style = %q", got)
+	}
+
+	login := spec.Find("login")
+	if login.File != "specs/main.yaml" || login.Line != 20 {
+		t.Errorf("login declared at %s:%d, want specs/main.yaml:20", login.File, login.Line)
+	}
+	if got := login.Model.(*sequence.Diagram).Title; got != "2020" {
+		t.Errorf("login title = %q, want %q", got, "2020")
+	}
+
+	if spec.Find("missing") != nil {
+		t.Error("Find(missing) should return nil")
+	}
+}
+
+func TestLoadFSErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "Missing file",
+			files:   map[string]string{},
+			wantErr: os.ErrNotExist,
+			wantMsg: "main.yaml: open main.yaml: file does not exist",
+		},
+		{
+			name:    "Syntax error",
+			files:   map[string]string{"main.yaml": "diagrams: [\n"},
+			wantMsg: "main.yaml: yaml: line 1: did not find expected node content",
+		},
+		{
+			name:    "Unknown spec member",
+			files:   map[string]string{"main.yaml": "version: 1\nstyle: {}\n"},
+			wantErr: ErrInvalidSpec,
+			wantMsg: `main.yaml:2: invalid spec: unknown member "style"`,
+		},
+		{
+			name:    "Unsupported version",
+			files:   map[string]string{"main.yaml": "version: 2\n"},
+			wantErr: ErrInvalidSpec,
+			wantMsg: `main.yaml:1: invalid spec: unsupported version "2" (supported: 1)`,
+		},
+		{
+			name: "Include cycle",
+			files: map[string]string{
+				"main.yaml":   "include: [a/one.yaml]\n",
+				"a/one.yaml":  "include: [two.yaml]\n",
+				"a/two.yaml":  "diagrams: []\ninclude:\n  - ../main.yaml\n",
+				"unused.yaml": "",
+			},
+			wantErr: ErrIncludeCycle,
+			wantMsg: "a/two.yaml:3: include cycle: main.yaml",
+		},
+		{
+			name:    "Missing include",
+			files:   map[string]string{"main.yaml": "include:\n  - other.yaml\n"},
+			wantErr: os.ErrNotExist,
+			wantMsg: "main.yaml:2: other.yaml: open other.yaml: file does not exist",
+		},
+		{
+			name: "Duplicate diagram name",
+			files: map[string]string{
+				"main.yaml":  "include: [other.yaml]\ndiagrams:\n  - {name: a, type: timeline}\n",
+				"other.yaml": "diagrams:\n  - {name: a, type: timeline}\n",
+			},
+			wantErr: ErrDuplicateName,
+			wantMsg: `main.yaml:3: duplicate name: diagram "a" already defined at other.yaml:2`,
+		},
+		{
+			name:    "Duplicate style name",
+			files:   map[string]string{"main.yaml": "styles:\n  a: {}\n---\nstyles:\n  a: {}\n"},
+			wantErr: ErrDuplicateName,
+			wantMsg: `main.yaml:5: duplicate name: style "a" already defined at main.yaml:2`,
+		},
+		{
+			name:    "Unknown style member",
+			files:   map[string]string{"main.yaml": "styles:\n  a:\n    fill: red\n    border: red\n"},
+			wantErr: ErrInvalidSpec,
+			wantMsg: `main.yaml:4: invalid spec: unknown member "border"`,
+		},
+		{
+			name:    "Missing diagram name",
+			files:   map[string]string{"main.yaml": "diagrams:\n  - type: timeline\n"},
+			wantErr: ErrInvalidSpec,
+			wantMsg: "main.yaml:2: invalid spec: diagram has no name",
+		},
+		{
+			name:    "Diagram name with a path",
+			files:   map[string]string{"main.yaml": "diagrams:\n  - {name: ../a, type: timeline}\n"},
+			wantErr: ErrInvalidSpec,
+			wantMsg: `main.yaml:2: invalid spec: diagram name "../a" is not a file name`,
+		},
+		{
+			name:    "Unknown diagram member",
+			files:   map[string]string{"main.yaml": "diagrams:\n  - name: a\n    type: flowchart\n    nodes:\n      - id: a\n        colour: red\n"},
+			wantErr: serialize.ErrInvalidDocument,
+			wantMsg: `main.yaml:6: invalid document: document.nodes[0]: unknown member "colour"`,
+		},
+		{
+			name:    "Unknown flowchart style",
+			files:   map[string]string{"main.yaml": "diagrams:\n  - name: a\n    type: flowchart\n    nodes:\n      - {id: a, class: hot}\n"},
+			wantErr: ErrUnknownStyle,
+			wantMsg: `main.yaml:5: unknown style class "hot"`,
+		},
+		{
+			name:    "Unknown block style",
+			files:   map[string]string{"main.yaml": "diagrams:\n  - name: a\n    type: block\n    blocks:\n      - id: a\n        children:\n          - {id: b, class: hot}\n"},
+			wantErr: ErrUnknownStyle,
+			wantMsg: `main.yaml:7: unknown style class "hot"`,
+		},
+		{
+			name:    "Unknown reference",
+			files:   map[string]string{"main.yaml": "diagrams:\n  - name: a\n    type: flowchart\n    nodes:\n      - id: a\n    links:\n      - from: a\n        to: b\n"},
+			wantErr: basediagram.ErrUnknownReference,
+			wantMsg: `main.yaml:8: unknown reference: node "b"`,
+		},
+		{
+			name:    "Duplicate ID",
+			files:   map[string]string{"main.yaml": "diagrams:\n  - name: a\n    type: flowchart\n    nodes:\n      - id: a\n      - id: a\n"},
+			wantErr: basediagram.ErrDuplicateID,
+			wantMsg: `main.yaml:6: duplicate identifier: node "a"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for name, data := range tt.files {
+				fsys[name] = &fstest.MapFile{Data: []byte(data)}
+			}
+
+			_, err := LoadFS(fsys, "main.yaml")
+			if err == nil {
+				t.Fatal("LoadFS() should fail")
+			}
+			var specErr *Error
+			if !errors.As(err, &specErr) {
+				t.Errorf("LoadFS() error = %T, want *Error", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("LoadFS() error = %v, want %v", err, tt.wantErr)
+			}
+			if err.Error() != tt.wantMsg {
+				t.Errorf("LoadFS() error = %q, want %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestLoadAndGenerate(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.yaml":          mainSpec,
+		"shared/styles.yaml": stylesSpec,
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	spec, err := Load(filepath.Join(dir, "main.yaml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := spec.Find("shared").File; got != filepath.Join(dir, "shared", "styles.yaml") {
+		t.Errorf("shared declared in %q", got)
+	}
+
+	out := filepath.Join(dir, "out")
+	paths, err := spec.Generate(out)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(paths) != len(spec.Diagrams) {
+		t.Fatalf("Generate() wrote %d files, want %d", len(paths), len(spec.Diagrams))
+	}
+
+	for i, diagram := range spec.Diagrams {
+		if want := filepath.Join(out, diagram.Name+FileExtension); paths[i] != want {
+			t.Errorf("Generate() path = %q, want %q", paths[i], want)
+		}
+		data, err := os.ReadFile(paths[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != diagram.Model.String() {
+			t.Errorf("%s content = %q, want %q", paths[i], data, diagram.Model.String())
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "styles.yaml"), []byte(stylesSpec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		file      string
+		data      string
+		wantNames string
+		wantErr   string
+	}{
+		{
+			name:      "Spec with includes from disk",
+			file:      filepath.Join(dir, "stdin"),
+			data:      "include: [styles.yaml]\ndiagrams:\n  - {name: a, type: flowchart, nodes: [{id: n, class: muted}]}\n",
+			wantNames: "shared,a",
+		},
+		{
+			name:      "YAML document",
+			file:      filepath.Join(dir, "orders.yaml"),
+			data:      "type: sequence\ntitle: Orders\n",
+			wantNames: "orders",
+		},
+		{
+			name:      "JSON document with a name",
+			file:      filepath.Join(dir, "orders.json"),
+			data:      `{"version": 1, "type": "timeline", "name": "history"}`,
+			wantNames: "history",
+		},
+		{
+			name:    "Invalid document",
+			file:    "orders.yaml",
+			data:    "type: sequence\ntitle: Orders\nactors: {}\n",
+			wantErr: "orders.yaml:3: invalid document: document.actors: expected array",
+		},
+		{
+			name:      "Mermaid file",
+			file:      filepath.Join(dir, "checkout.mmd"),
+			data:      "graph LR\n    a --> b\n",
+			wantNames: "checkout",
+		},
+		{
+			name:      "Mermaid from standard input",
+			file:      "stdin",
+			data:      "%% orders\nsequenceDiagram\n    A->>B: hi\n",
+			wantNames: "stdin",
+		},
+		{
+			name:    "Mermaid syntax error",
+			file:    "checkout.mmd",
+			data:    "flowchart TB\n    a -->\n",
+			wantErr: "checkout.mmd:2: syntax error: expected node ID at column 6",
+		},
+		{
+			name:    "Mermaid unsupported statements",
+			file:    "orders.mmd",
+			data:    "sequenceDiagram\n    loop Every minute\n        A->>B: ping\n    end\n",
+			wantErr: "orders.mmd:2: unsupported syntax: loop Every minute\norders.mmd:4: unsupported syntax: end",
+		},
+		{
+			name:    "Mermaid diagram type not parsed",
+			file:    "share.mermaid",
+			data:    "pie\n    \"A\": 1\n",
+			wantErr: `share.mermaid: diagram type not parsed "pie"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := Parse(tt.file, []byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			var names []string
+			for _, diagram := range spec.Diagrams {
+				names = append(names, diagram.Name)
+			}
+			if got := strings.Join(names, ","); got != tt.wantNames {
+				t.Errorf("diagram names = %q, want %q", got, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestParse_MermaidType(t *testing.T) {
+	spec, err := Parse("checkout.mmd", []byte("graph LR\n    a --> b\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	diagram := spec.Diagrams[0]
+	if diagram.Type != flowchart.DocumentType || diagram.Line != 1 {
+		t.Errorf("Parse() diagram type = %q at line %d, want %q at line 1", diagram.Type, diagram.Line, flowchart.DocumentType)
+	}
+	if _, ok := diagram.Model.(*flowchart.Flowchart); !ok {
+		t.Errorf("Parse() model = %T, want *flowchart.Flowchart", diagram.Model)
+	}
+}
+
+func TestIsMermaid(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+		want bool
+	}{
+		{name: "Mermaid extension", file: "a.mmd", data: "type: flowchart\n", want: true},
+		{name: "Other Mermaid extension", file: "a.MERMAID", data: "", want: true},
+		{name: "Diagram declaration", file: "stdin", data: "---\ntitle: A\n---\nstateDiagram-v2\n", want: true},
+		{name: "YAML document", file: "stdin", data: "type: flowchart\n", want: false},
+		{name: "Spec file", file: "spec.yaml", data: "diagrams:\n  - {name: a, type: timeline}\n", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsMermaid(tt.file, []byte(tt.data)); got != tt.want {
+				t.Errorf("IsMermaid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *Error
+		want string
+	}{
+		{
+			name: "With line",
+			err:  &Error{File: "a.yaml", Line: 3, Err: ErrInvalidSpec},
+			want: "a.yaml:3: invalid spec",
+		},
+		{
+			name: "Whole file",
+			err:  &Error{File: "a.yaml", Err: ErrInvalidSpec},
+			want: "a.yaml: invalid spec",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+			if !errors.Is(tt.err, ErrInvalidSpec) {
+				t.Error("Error should wrap its reason")
+			}
+		})
+	}
+}
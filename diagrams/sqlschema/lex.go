@@ -0,0 +1,214 @@
+package sqlschema
+
+import (
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// tokenKind classifies the tokens of SQL source.
+type tokenKind int
+
+const (
+	tokenWord       tokenKind = iota // unquoted identifier or keyword
+	tokenIdentifier                  // quoted identifier
+	tokenString                      // string literal, including dollar-quoted strings
+	tokenNumber                      // numeric literal
+	tokenSymbol                      // any other character
+)
+
+// Delimiters of SQL source.
+const (
+	lineComment     = "--"
+	blockCommentEnd = "*/"
+	blockComment    = "/*"
+	statementEnd    = ";"
+	groupOpen       = "("
+	groupClose      = ")"
+	listSeparator   = ","
+	nameSeparator   = "."
+	dollarQuote     = '$'
+)
+
+// identifierQuotes maps the opening quote of an identifier to its closing quote: standard
+// double quotes and MySQL backticks.
+var identifierQuotes = map[byte]byte{'"': '"', '`': '`'}
+
+// token is a word, literal or symbol of SQL source, at a 1-based line.
+type token struct {
+	kind tokenKind
+	text string
+	line int
+}
+
+// is reports whether the token is the unquoted keyword, in any case.
+func (t token) is(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+// isName reports whether the token can name a table, column or constraint.
+func (t token) isName() bool {
+	return t.kind == tokenWord || t.kind == tokenIdentifier
+}
+
+// lexer splits SQL source into statements of tokens, dropping comments and the
+// semicolons between statements.
+type lexer struct {
+	source     string
+	pos        int
+	line       int
+	statements [][]token
+	current    []token
+}
+
+// lex returns the statements of SQL source.
+func lex(source string) ([][]token, error) {
+	l := &lexer{source: source, line: 1}
+
+	for l.pos < len(l.source) {
+		c := l.source[l.pos]
+		switch {
+		case c == '\n':
+			l.line++
+			l.pos++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f':
+			l.pos++
+		case strings.HasPrefix(l.source[l.pos:], lineComment):
+			end := strings.IndexByte(l.source[l.pos:], '\n')
+			if end < 0 {
+				end = len(l.source) - l.pos
+			}
+			l.pos += end
+		case strings.HasPrefix(l.source[l.pos:], blockComment):
+			end := strings.Index(l.source[l.pos+len(blockComment):], blockCommentEnd)
+			if end < 0 {
+				return nil, basediagram.Syntax(l.line, "unterminated comment")
+			}
+			l.skip(len(blockComment) + end + len(blockCommentEnd))
+		case c == '\'':
+			if err := l.quoted(tokenString, '\''); err != nil {
+				return nil, err
+			}
+		case identifierQuotes[c] != 0:
+			if err := l.quoted(tokenIdentifier, identifierQuotes[c]); err != nil {
+				return nil, err
+			}
+		case c == dollarQuote && l.dollarTag() != "":
+			if err := l.dollarQuoted(); err != nil {
+				return nil, err
+			}
+		case isDigit(c):
+			l.word(tokenNumber)
+		case isWordStart(c):
+			l.word(tokenWord)
+		case string(c) == statementEnd:
+			l.endStatement()
+			l.pos++
+		default:
+			l.emit(tokenSymbol, string(c), l.line)
+			l.pos++
+		}
+	}
+	l.endStatement()
+
+	return l.statements, nil
+}
+
+// emit adds a token to the current statement.
+func (l *lexer) emit(kind tokenKind, text string, line int) {
+	l.current = append(l.current, token{kind: kind, text: text, line: line})
+}
+
+// endStatement closes the current statement, if it has any token.
+func (l *lexer) endStatement() {
+	if len(l.current) > 0 {
+		l.statements = append(l.statements, l.current)
+		l.current = nil
+	}
+}
+
+// skip advances over n bytes, counting the lines they span.
+func (l *lexer) skip(n int) {
+	l.line += strings.Count(l.source[l.pos:l.pos+n], "\n")
+	l.pos += n
+}
+
+// word reads a run of word characters.
+func (l *lexer) word(kind tokenKind) {
+	start := l.pos
+	for l.pos < len(l.source) && isWordPart(l.source[l.pos]) {
+		l.pos++
+	}
+	l.emit(kind, l.source[start:l.pos], l.line)
+}
+
+// quoted reads a string or identifier up to its closing quote, a doubled quote standing
+// for the quote itself.
+func (l *lexer) quoted(kind tokenKind, quote byte) error {
+	line := l.line
+	var sb strings.Builder
+	for i := l.pos + 1; i < len(l.source); i++ {
+		if l.source[i] != quote {
+			sb.WriteByte(l.source[i])
+			continue
+		}
+		if i+1 < len(l.source) && l.source[i+1] == quote {
+			sb.WriteByte(quote)
+			i++
+			continue
+		}
+		l.skip(i + 1 - l.pos)
+		l.emit(kind, sb.String(), line)
+		return nil
+	}
+
+	return basediagram.Syntax(line, "unterminated quoted text")
+}
+
+// dollarTag returns the opening $tag$ of a PostgreSQL dollar-quoted string at the current
+// position, or an empty string when there is none.
+func (l *lexer) dollarTag() string {
+	for i := l.pos + 1; i < len(l.source); i++ {
+		c := l.source[i]
+		if c == dollarQuote {
+			return l.source[l.pos : i+1]
+		}
+		if !isWordPart(c) || (i == l.pos+1 && isDigit(c)) {
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// dollarQuoted reads a dollar-quoted string, such as the body of a function, up to the
+// closing tag.
+func (l *lexer) dollarQuoted() error {
+	line := l.line
+	tag := l.dollarTag()
+	end := strings.Index(l.source[l.pos+len(tag):], tag)
+	if end < 0 {
+		return basediagram.Syntax(line, "unterminated quoted text")
+	}
+
+	text := l.source[l.pos+len(tag) : l.pos+len(tag)+end]
+	l.skip(len(tag) + end + len(tag))
+	l.emit(tokenString, text, line)
+	return nil
+}
+
+// isDigit reports whether c is an ASCII digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isWordStart reports whether c starts a word: a letter, an underscore or any byte of a
+// non-ASCII character.
+func isWordStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c >= 0x80
+}
+
+// isWordPart reports whether c continues a word.
+func isWordPart(c byte) bool {
+	return isWordStart(c) || isDigit(c) || c == dollarQuote
+}
@@ -0,0 +1,789 @@
+package sqlschema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// Array brackets following the type of a PostgreSQL column.
+const (
+	arrayOpen  = "["
+	arrayClose = "]"
+)
+
+// tableModifiers may appear between CREATE and TABLE.
+var tableModifiers = []string{"GLOBAL", "LOCAL", "TEMP", "TEMPORARY", "UNLOGGED"}
+
+// typeModifiers are the words that continue the type of a column, as in double precision,
+// timestamp with time zone or int unsigned.
+var typeModifiers = map[string]bool{
+	"varying": true, "precision": true, "with": true, "without": true, "time": true,
+	"zone": true, "unsigned": true, "signed": true, "zerofill": true,
+}
+
+// columnConstraints are the words that can follow the name of a column without a type in
+// SQLite.
+var columnConstraints = map[string]bool{
+	"CONSTRAINT": true, "NOT": true, "NULL": true, "PRIMARY": true, "UNIQUE": true,
+	"REFERENCES": true, "DEFAULT": true, "CHECK": true, "COLLATE": true, "GENERATED": true,
+	"AS": true,
+}
+
+// tableConstraints are the words that start a table constraint or index rather than a
+// column definition.
+var tableConstraints = map[string]bool{
+	"CONSTRAINT": true, "PRIMARY": true, "UNIQUE": true, "FOREIGN": true, "CHECK": true,
+	"EXCLUDE": true, "LIKE": true, "FULLTEXT": true, "SPATIAL": true, "PERIOD": true,
+}
+
+// indexKeywords start a MySQL index definition, or the definition of a column with that
+// name.
+var indexKeywords = map[string]bool{"KEY": true, "INDEX": true}
+
+// parser reads the tokens of a statement, or of a part of it.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Apply applies the DDL statements of SQL source to the schema, in order. Errors are
+// *basediagram.SyntaxError values giving the line of the problem: invalid syntax wraps
+// basediagram.ErrSyntax, and statements that do not apply to the schema wrap
+// ErrUnknownTable, ErrUnknownColumn, ErrDuplicateTable or ErrDuplicateColumn.
+func (s *Schema) Apply(source string) error {
+	statements, err := lex(source)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range statements {
+		if err := s.apply(&parser{tokens: statement}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// apply applies a statement, ignoring those that do not change tables or keys.
+func (s *Schema) apply(p *parser) error {
+	switch {
+	case p.keyword("CREATE"):
+		return s.create(p)
+	case p.keyword("ALTER", "TABLE"):
+		return s.alterTable(p)
+	case p.keyword("DROP", "TABLE"):
+		return s.dropTables(p)
+	case p.keyword("DROP", "INDEX"):
+		return s.dropIndex(p)
+	}
+
+	return nil
+}
+
+// create applies a CREATE TABLE or CREATE UNIQUE INDEX statement.
+func (s *Schema) create(p *parser) error {
+	p.keyword("OR", "REPLACE")
+	if p.keyword("UNIQUE", "INDEX") {
+		return s.createUniqueIndex(p)
+	}
+	for _, modifier := range tableModifiers {
+		p.keyword(modifier)
+	}
+	if !p.keyword("TABLE") {
+		return nil
+	}
+
+	line := p.peek().line
+	ifNotExists := p.keyword("IF", "NOT", "EXISTS")
+	name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if s.table(name) != nil {
+		if ifNotExists {
+			return nil
+		}
+		return basediagram.AtLine(line, fmt.Errorf(nameErrorString, ErrDuplicateTable, name))
+	}
+	if !p.at(groupOpen) {
+		// CREATE TABLE ... AS SELECT, LIKE and PARTITION OF have no definitions.
+		return nil
+	}
+
+	body, err := p.group()
+	if err != nil {
+		return err
+	}
+	t := &table{name: name}
+	for _, element := range split(body) {
+		if err := t.element(&parser{tokens: element}); err != nil {
+			return err
+		}
+	}
+	s.tables = append(s.tables, t)
+
+	return nil
+}
+
+// createUniqueIndex applies a CREATE UNIQUE INDEX statement. Indexes over expressions and
+// partial indexes do not make their columns unique, and are ignored.
+func (s *Schema) createUniqueIndex(p *parser) error {
+	p.keyword("CONCURRENTLY")
+	p.keyword("IF", "NOT", "EXISTS")
+
+	var name string
+	if !p.peek().is("ON") {
+		var err error
+		if name, err = p.qualifiedName(); err != nil {
+			return err
+		}
+	}
+	if !p.keyword("ON") {
+		return basediagram.Syntax(p.peek().line, "expected ON")
+	}
+	p.keyword("ONLY")
+
+	t, err := s.tableNamed(p, false)
+	if t == nil || err != nil {
+		return err
+	}
+	if p.keyword("USING") {
+		p.pos++
+	}
+	columns, err := p.columns()
+	if columns == nil || err != nil {
+		return err
+	}
+	for !p.done() {
+		if p.keyword("WHERE") {
+			return nil
+		}
+		if err := p.skip(); err != nil {
+			return err
+		}
+	}
+	t.unique = append(t.unique, &key{name: name, columns: columns})
+
+	return nil
+}
+
+// alterTable applies the comma separated actions of an ALTER TABLE statement.
+func (s *Schema) alterTable(p *parser) error {
+	ifExists := p.keyword("IF", "EXISTS")
+	p.keyword("ONLY")
+	t, err := s.tableNamed(p, ifExists)
+	if t == nil || err != nil {
+		return err
+	}
+
+	for _, action := range split(p.tokens[p.pos:]) {
+		if err := s.alter(t, &parser{tokens: action}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// alter applies an action of an ALTER TABLE statement to a table.
+func (s *Schema) alter(t *table, p *parser) error {
+	switch {
+	case p.keyword("ADD"):
+		if p.constraint() {
+			return t.constraint(p)
+		}
+		p.keyword("COLUMN")
+		if p.keyword("IF", "NOT", "EXISTS") && t.column(p.peek().text) != nil {
+			return nil
+		}
+		return t.addColumn(p)
+	case p.keyword("DROP"):
+		return t.drop(p)
+	case p.keyword("RENAME"):
+		return s.rename(t, p)
+	case p.keyword("ALTER"):
+		p.keyword("COLUMN")
+		c, err := t.columnNamed(p)
+		if err != nil {
+			return err
+		}
+		switch {
+		case p.keyword("SET", "NOT", "NULL"):
+			c.notNull = true
+		case p.keyword("DROP", "NOT", "NULL"):
+			c.notNull = false
+		case p.keyword("SET", "DATA", "TYPE"), p.keyword("TYPE"):
+			return p.dataType(c)
+		}
+	case p.keyword("MODIFY"):
+		p.keyword("COLUMN")
+		c, err := t.columnNamed(&parser{tokens: p.tokens, pos: p.pos})
+		if err != nil {
+			return err
+		}
+		return t.replaceColumn(c, p)
+	case p.keyword("CHANGE"):
+		p.keyword("COLUMN")
+		c, err := t.columnNamed(p)
+		if err != nil {
+			return err
+		}
+		return t.replaceColumn(c, p)
+	}
+
+	return nil
+}
+
+// drop applies the DROP action of an ALTER TABLE statement to a table.
+func (t *table) drop(p *parser) error {
+	switch {
+	case p.keyword("PRIMARY", "KEY"):
+		t.primaryKey = nil
+		return nil
+	case p.keyword("CONSTRAINT"), p.keyword("FOREIGN", "KEY"), p.keyword("INDEX"), p.keyword("KEY"):
+		p.keyword("IF", "EXISTS")
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		t.dropKey(name)
+		return nil
+	}
+
+	p.keyword("COLUMN")
+	ifExists := p.keyword("IF", "EXISTS")
+	if ifExists && t.column(p.peek().text) == nil {
+		return nil
+	}
+	c, err := t.columnNamed(p)
+	if err != nil {
+		return err
+	}
+	t.dropColumn(c)
+
+	return nil
+}
+
+// rename applies the RENAME action of an ALTER TABLE statement to a table: RENAME [TO|AS]
+// table, RENAME [COLUMN] from TO to, or RENAME CONSTRAINT|INDEX|KEY from TO to.
+func (s *Schema) rename(t *table, p *parser) error {
+	switch {
+	case p.keyword("TO"), p.keyword("AS"):
+	case p.keyword("CONSTRAINT"), p.keyword("INDEX"), p.keyword("KEY"):
+		from, to, err := p.renaming()
+		if err != nil {
+			return err
+		}
+		for _, k := range t.keys() {
+			if k.name != "" && strings.EqualFold(k.name, from) {
+				k.name = to
+			}
+		}
+		return nil
+	default:
+		p.keyword("COLUMN")
+		if len(p.tokens)-p.pos == 1 {
+			// MySQL allows RENAME table without TO.
+			break
+		}
+		line := p.peek().line
+		from, to, err := p.renaming()
+		if err != nil {
+			return err
+		}
+		c := t.column(from)
+		if c == nil {
+			return basediagram.AtLine(line, fmt.Errorf(nameErrorString, ErrUnknownColumn, from))
+		}
+		t.renameColumn(c, to)
+		return nil
+	}
+
+	name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	s.renameTable(t, name)
+
+	return nil
+}
+
+// dropTables applies a DROP TABLE statement, which removes the foreign keys referencing
+// the dropped tables.
+func (s *Schema) dropTables(p *parser) error {
+	ifExists := p.keyword("IF", "EXISTS")
+	for _, item := range split(p.tokens[p.pos:]) {
+		t, err := s.tableNamed(&parser{tokens: item}, ifExists)
+		if err != nil {
+			return err
+		}
+		if t != nil {
+			s.dropTable(t)
+		}
+	}
+
+	return nil
+}
+
+// dropIndex applies a DROP INDEX statement, which removes the unique keys created by
+// CREATE UNIQUE INDEX.
+func (s *Schema) dropIndex(p *parser) error {
+	p.keyword("CONCURRENTLY")
+	p.keyword("IF", "EXISTS")
+	for _, item := range split(p.tokens[p.pos:]) {
+		name, err := (&parser{tokens: item}).qualifiedName()
+		if err != nil {
+			return err
+		}
+		for _, t := range s.tables {
+			t.dropKey(name)
+		}
+	}
+
+	return nil
+}
+
+// tableNamed reads the name of a table of the schema. A missing table is an error unless
+// it may not exist, in which case the table is nil.
+func (s *Schema) tableNamed(p *parser, mayNotExist bool) (*table, error) {
+	line := p.peek().line
+	name, err := p.qualifiedName()
+	if err != nil {
+		return nil, err
+	}
+	t := s.table(name)
+	if t == nil && !mayNotExist {
+		return nil, basediagram.AtLine(line, fmt.Errorf(nameErrorString, ErrUnknownTable, name))
+	}
+
+	return t, nil
+}
+
+// element adds a column or table constraint of a CREATE TABLE statement to a table.
+func (t *table) element(p *parser) error {
+	if p.constraint() {
+		return t.constraint(p)
+	}
+
+	return t.addColumn(p)
+}
+
+// constraint reports whether the parser is at a table constraint or index definition. The
+// MySQL KEY and INDEX definitions are told from columns with those names by their list of
+// columns.
+func (p *parser) constraint() bool {
+	next := p.peek()
+	if next.kind != tokenWord {
+		return false
+	}
+	keyword := strings.ToUpper(next.text)
+	if tableConstraints[keyword] {
+		return true
+	}
+	if !indexKeywords[keyword] {
+		return false
+	}
+
+	for i := p.pos + 1; i+1 < len(p.tokens); i++ {
+		if p.tokens[i].kind == tokenSymbol && p.tokens[i].text == groupOpen {
+			return p.tokens[i+1].isName()
+		}
+	}
+
+	return false
+}
+
+// constraint adds a table constraint to a table. Check constraints, exclusion constraints
+// and indexes are ignored, as are keys over expressions.
+func (t *table) constraint(p *parser) error {
+	var name string
+	if p.keyword("CONSTRAINT") {
+		var err error
+		if name, err = p.name(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case p.keyword("PRIMARY", "KEY"):
+		columns, err := p.columns()
+		if columns == nil || err != nil {
+			return err
+		}
+		t.primaryKey = &key{name: name, columns: columns}
+		for _, c := range t.columns {
+			c.notNull = c.notNull || t.primaryKey.contains(c.name)
+		}
+	case p.keyword("UNIQUE"):
+		if !p.keyword("KEY") {
+			p.keyword("INDEX")
+		}
+		if !p.keyword("NULLS", "DISTINCT") {
+			p.keyword("NULLS", "NOT", "DISTINCT")
+		}
+		if p.peek().isName() {
+			index, err := p.name()
+			if err != nil {
+				return err
+			}
+			if name == "" {
+				name = index
+			}
+		}
+		columns, err := p.columns()
+		if columns == nil || err != nil {
+			return err
+		}
+		t.unique = append(t.unique, &key{name: name, columns: columns})
+	case p.keyword("FOREIGN", "KEY"):
+		if p.peek().isName() {
+			p.pos++
+		}
+		line := p.peek().line
+		columns, err := p.columns()
+		if err != nil {
+			return err
+		}
+		if columns == nil {
+			return basediagram.Syntax(line, "expected the columns of the foreign key")
+		}
+		if !p.keyword("REFERENCES") {
+			return basediagram.Syntax(p.peek().line, "expected REFERENCES")
+		}
+		referenced, err := p.references()
+		if err != nil {
+			return err
+		}
+		t.foreignKeys = append(t.foreignKeys, &foreignKey{key: key{name: name, columns: columns}, table: referenced})
+	}
+
+	return nil
+}
+
+// addColumn adds a column definition and its constraints to a table.
+func (t *table) addColumn(p *parser) error {
+	line := p.peek().line
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	if t.column(name) != nil {
+		return basediagram.AtLine(line, fmt.Errorf(nameErrorString, ErrDuplicateColumn, name))
+	}
+
+	c := &column{name: name}
+	t.columns = append(t.columns, c)
+	if next := p.peek(); next.kind != tokenWord || !columnConstraints[strings.ToUpper(next.text)] {
+		if err := p.dataType(c); err != nil {
+			return err
+		}
+	}
+
+	var constraint string
+	for !p.done() {
+		switch {
+		case p.keyword("CONSTRAINT"):
+			if constraint, err = p.name(); err != nil {
+				return err
+			}
+			continue
+		case p.keyword("NOT", "NULL"):
+			c.notNull = true
+		case p.keyword("PRIMARY", "KEY"):
+			c.notNull = true
+			t.primaryKey = &key{name: constraint, columns: []string{name}}
+		case p.keyword("UNIQUE"):
+			p.keyword("KEY")
+			t.unique = append(t.unique, &key{name: constraint, columns: []string{name}})
+		case p.keyword("REFERENCES"):
+			referenced, err := p.references()
+			if err != nil {
+				return err
+			}
+			t.foreignKeys = append(t.foreignKeys, &foreignKey{key: key{name: constraint, columns: []string{name}}, table: referenced})
+		default:
+			if err := p.skip(); err != nil {
+				return err
+			}
+		}
+		constraint = ""
+	}
+
+	return nil
+}
+
+// replaceColumn replaces a column by the definition of a MySQL MODIFY or CHANGE action,
+// keeping its position and the keys over it.
+func (t *table) replaceColumn(c *column, p *parser) error {
+	index := 0
+	for i, other := range t.columns {
+		if other == c {
+			index = i
+		}
+	}
+	t.columns = append(t.columns[:index], t.columns[index+1:]...)
+
+	if err := t.addColumn(p); err != nil {
+		return err
+	}
+	replacement := t.columns[len(t.columns)-1]
+	copy(t.columns[index+1:], t.columns[index:len(t.columns)-1])
+	t.columns[index] = replacement
+
+	name := replacement.name
+	replacement.name = c.name
+	t.renameColumn(replacement, name)
+
+	return nil
+}
+
+// keys returns the primary key, unique keys and foreign keys of the table.
+func (t *table) keys() []*key {
+	var keys []*key
+	if t.primaryKey != nil {
+		keys = append(keys, t.primaryKey)
+	}
+	keys = append(keys, t.unique...)
+	for _, fk := range t.foreignKeys {
+		keys = append(keys, &fk.key)
+	}
+
+	return keys
+}
+
+// columnNamed reads the name of a column of the table.
+func (t *table) columnNamed(p *parser) (*column, error) {
+	line := p.peek().line
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	c := t.column(name)
+	if c == nil {
+		return nil, basediagram.AtLine(line, fmt.Errorf(nameErrorString, ErrUnknownColumn, name))
+	}
+
+	return c, nil
+}
+
+// dataType reads the type of a column: its words, an optional list of parameters and
+// PostgreSQL array brackets.
+func (p *parser) dataType(c *column) error {
+	c.dataType, c.size, c.array = "", "", false
+	for !p.done() {
+		next := p.peek()
+		switch {
+		case next.isName() && c.dataType == "":
+			c.dataType = strings.ToLower(next.text)
+			p.pos++
+		case next.kind == tokenWord && typeModifiers[strings.ToLower(next.text)]:
+			p.pos++
+		case p.at(nameSeparator) && c.dataType != "":
+			// The type is qualified by its schema.
+			p.pos++
+			c.dataType = ""
+		case p.at(groupOpen) && c.dataType != "":
+			parameters, err := p.group()
+			if err != nil {
+				return err
+			}
+			if len(parameters) == 1 {
+				c.size = parameters[0].text
+			}
+		case p.at(arrayOpen):
+			for !p.done() && !p.at(arrayClose) {
+				p.pos++
+			}
+			p.pos++
+			c.array = true
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// references reads the table referenced by a foreign key, and its optional columns.
+func (p *parser) references() (string, error) {
+	name, err := p.qualifiedName()
+	if err != nil {
+		return "", err
+	}
+	if p.at(groupOpen) {
+		if _, err := p.group(); err != nil {
+			return "", err
+		}
+	}
+
+	return name, nil
+}
+
+// renaming reads "from TO to".
+func (p *parser) renaming() (from string, to string, err error) {
+	if from, err = p.name(); err != nil {
+		return "", "", err
+	}
+	if !p.keyword("TO") {
+		return "", "", basediagram.Syntax(p.peek().line, "expected TO")
+	}
+	to, err = p.name()
+
+	return from, to, err
+}
+
+// columns reads a parenthesized list of columns. Items may have a MySQL prefix length and
+// an order. The columns are nil when an item is an expression.
+func (p *parser) columns() ([]string, error) {
+	group, err := p.group()
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []string
+	for _, item := range split(group) {
+		if !item[0].isName() {
+			return nil, nil
+		}
+		if len(item) > 1 && item[1].kind == tokenSymbol && item[1].text == groupOpen &&
+			(len(item) < 3 || item[2].kind != tokenNumber) {
+			return nil, nil
+		}
+		columns = append(columns, item[0].text)
+	}
+
+	return columns, nil
+}
+
+// done reports whether all the tokens have been read.
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+// peek returns the next token without reading it. Past the end, it returns an empty token
+// at the line of the last one.
+func (p *parser) peek() token {
+	if p.done() {
+		var end token
+		if len(p.tokens) > 0 {
+			end.line = p.tokens[len(p.tokens)-1].line
+		}
+		return end
+	}
+
+	return p.tokens[p.pos]
+}
+
+// at reports whether the next token is a symbol.
+func (p *parser) at(symbol string) bool {
+	next := p.peek()
+	return next.kind == tokenSymbol && next.text == symbol
+}
+
+// keyword reads the next tokens if they are the keywords, in order, reporting whether
+// they were.
+func (p *parser) keyword(keywords ...string) bool {
+	if p.pos+len(keywords) > len(p.tokens) {
+		return false
+	}
+	for i, keyword := range keywords {
+		if !p.tokens[p.pos+i].is(keyword) {
+			return false
+		}
+	}
+	p.pos += len(keywords)
+
+	return true
+}
+
+// name reads the name of a table, column or constraint.
+func (p *parser) name() (string, error) {
+	next := p.peek()
+	if !next.isName() {
+		return "", basediagram.Syntax(next.line, "expected a name")
+	}
+	p.pos++
+
+	return next.text, nil
+}
+
+// qualifiedName reads a name qualified by a schema or database, returning its last part.
+func (p *parser) qualifiedName() (string, error) {
+	name, err := p.name()
+	for err == nil && p.at(nameSeparator) {
+		p.pos++
+		name, err = p.name()
+	}
+
+	return name, err
+}
+
+// group reads a parenthesized group, returning the tokens between the parentheses.
+func (p *parser) group() ([]token, error) {
+	open := p.peek()
+	if !p.at(groupOpen) {
+		return nil, basediagram.Syntax(open.line, "expected %q", groupOpen)
+	}
+
+	depth := 0
+	for start := p.pos + 1; !p.done(); p.pos++ {
+		if p.tokens[p.pos].kind != tokenSymbol {
+			continue
+		}
+		switch p.tokens[p.pos].text {
+		case groupOpen:
+			depth++
+		case groupClose:
+			depth--
+			if depth == 0 {
+				p.pos++
+				return p.tokens[start : p.pos-1], nil
+			}
+		}
+	}
+
+	return nil, basediagram.Syntax(open.line, "unclosed parenthesis")
+}
+
+// skip reads the next token, or the group it opens.
+func (p *parser) skip() error {
+	if p.at(groupOpen) {
+		_, err := p.group()
+		return err
+	}
+	p.pos++
+
+	return nil
+}
+
+// split returns the non-empty lists of tokens separated by commas outside parentheses.
+func split(tokens []token) [][]token {
+	var items [][]token
+	depth, start := 0, 0
+	for i, t := range tokens {
+		if t.kind != tokenSymbol {
+			continue
+		}
+		switch t.text {
+		case groupOpen:
+			depth++
+		case groupClose:
+			depth--
+		case listSeparator:
+			if depth == 0 {
+				if i > start {
+					items = append(items, tokens[start:i])
+				}
+				start = i + 1
+			}
+		}
+	}
+	if len(tokens) > start {
+		items = append(items, tokens[start:])
+	}
+
+	return items
+}
@@ -0,0 +1,472 @@
+// Package sqlschema draws entity relationship diagrams of database schemas from the SQL DDL
+// of their migration files, without connecting to a database.
+//
+// A practical subset of PostgreSQL, MySQL and SQLite is understood: CREATE TABLE, ALTER
+// TABLE, CREATE UNIQUE INDEX, DROP TABLE and DROP INDEX. Other statements, such as inserts,
+// views and functions, and the clauses that change neither the columns nor the keys of a
+// table are ignored. Every table becomes an entity with an attribute per column, marked as
+// PK, FK or UK and required when NOT NULL. Every foreign key becomes a relationship from the
+// referenced table, optional when a column of the key is nullable and one to one when the
+// key is unique.
+package sqlschema
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/entityrelationship"
+)
+
+// Errors returned when a statement does not apply to the schema. They are wrapped in a
+// *basediagram.SyntaxError giving the line of the statement.
+var (
+	ErrUnknownTable    = errors.New("unknown table")
+	ErrUnknownColumn   = errors.New("unknown column")
+	ErrDuplicateTable  = errors.New("duplicate table")
+	ErrDuplicateColumn = errors.New("duplicate column")
+)
+
+const (
+	nameErrorString  string = "%w %s"
+	fileErrorString  string = "%s: %w"
+	labelSeparator   string = ", "
+	booleanSize      string = "1"
+	primaryKeySuffix string = "_pkey"
+	uniqueKeySuffix  string = "_key"
+	foreignKeySuffix string = "_fkey"
+	nameJoin         string = "_"
+)
+
+// Attribute types that are not mapped from SQL types.
+const (
+	// untypedColumn is the type of SQLite columns declared without one, which have the blob
+	// affinity.
+	untypedColumn entityrelationship.DataType = "blob"
+	// arraySuffix follows the type of PostgreSQL array columns.
+	arraySuffix entityrelationship.DataType = "[]"
+)
+
+// unsafeName matches the characters that cannot appear in entity, attribute and type names.
+var unsafeName = regexp.MustCompile(`[^\w-]+`)
+
+// dataTypes maps the SQL types, by their first word in lower case, to attribute types.
+// Other types keep their SQL name.
+var dataTypes = map[string]entityrelationship.DataType{
+	"int":         entityrelationship.TypeInteger,
+	"integer":     entityrelationship.TypeInteger,
+	"smallint":    entityrelationship.TypeInteger,
+	"bigint":      entityrelationship.TypeInteger,
+	"tinyint":     entityrelationship.TypeInteger,
+	"mediumint":   entityrelationship.TypeInteger,
+	"int2":        entityrelationship.TypeInteger,
+	"int4":        entityrelationship.TypeInteger,
+	"int8":        entityrelationship.TypeInteger,
+	"serial":      entityrelationship.TypeInteger,
+	"smallserial": entityrelationship.TypeInteger,
+	"bigserial":   entityrelationship.TypeInteger,
+	"serial2":     entityrelationship.TypeInteger,
+	"serial4":     entityrelationship.TypeInteger,
+	"serial8":     entityrelationship.TypeInteger,
+	"real":        entityrelationship.TypeFloat,
+	"float":       entityrelationship.TypeFloat,
+	"float4":      entityrelationship.TypeFloat,
+	"float8":      entityrelationship.TypeFloat,
+	"double":      entityrelationship.TypeFloat,
+	"decimal":     entityrelationship.TypeFloat,
+	"dec":         entityrelationship.TypeFloat,
+	"numeric":     entityrelationship.TypeFloat,
+	"money":       entityrelationship.TypeFloat,
+	"bool":        entityrelationship.TypeBoolean,
+	"boolean":     entityrelationship.TypeBoolean,
+	"date":        entityrelationship.TypeDateTime,
+	"time":        entityrelationship.TypeDateTime,
+	"timetz":      entityrelationship.TypeDateTime,
+	"timestamp":   entityrelationship.TypeDateTime,
+	"timestamptz": entityrelationship.TypeDateTime,
+	"datetime":    entityrelationship.TypeDateTime,
+	"char":        entityrelationship.TypeString,
+	"character":   entityrelationship.TypeString,
+	"varchar":     entityrelationship.TypeString,
+	"nchar":       entityrelationship.TypeString,
+	"nvarchar":    entityrelationship.TypeString,
+	"text":        entityrelationship.TypeString,
+	"tinytext":    entityrelationship.TypeString,
+	"mediumtext":  entityrelationship.TypeString,
+	"longtext":    entityrelationship.TypeString,
+	"clob":        entityrelationship.TypeString,
+	"citext":      entityrelationship.TypeString,
+	"enum":        entityrelationship.TypeString,
+	"set":         entityrelationship.TypeString,
+}
+
+// Schema is a database schema built by applying DDL statements in order, such as those of
+// successive migrations.
+type Schema struct {
+	tables []*table
+}
+
+// table is a table of a schema.
+type table struct {
+	name        string
+	columns     []*column
+	primaryKey  *key
+	unique      []*key
+	foreignKeys []*foreignKey
+}
+
+// column is a column of a table. Its type is the first word of its SQL type in lower case,
+// with the single parameter of the type, if any, as its size.
+type column struct {
+	name     string
+	dataType string
+	size     string
+	array    bool
+	notNull  bool
+}
+
+// key is a primary key or a unique constraint over columns of a table. Unnamed keys are
+// matched by the names PostgreSQL gives them.
+type key struct {
+	name    string
+	columns []string
+}
+
+// foreignKey is a foreign key of a table referencing another table.
+type foreignKey struct {
+	key
+	table string
+}
+
+// NewSchema returns an empty schema.
+func NewSchema() *Schema {
+	return &Schema{}
+}
+
+// Parse returns the diagram of the schema created by SQL source.
+func Parse(source string) (*entityrelationship.Diagram, error) {
+	s := NewSchema()
+	if err := s.Apply(source); err != nil {
+		return nil, err
+	}
+
+	return s.Diagram(), nil
+}
+
+// ParseFiles returns the diagram of the schema created by the SQL files at the given paths,
+// applied in order.
+func ParseFiles(paths ...string) (*entityrelationship.Diagram, error) {
+	s := NewSchema()
+	if err := s.ApplyFiles(paths...); err != nil {
+		return nil, err
+	}
+
+	return s.Diagram(), nil
+}
+
+// ApplyFiles applies the SQL files at the given paths in order. Errors in a file are
+// prefixed with its path.
+func (s *Schema) ApplyFiles(paths ...string) error {
+	for _, path := range paths {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := s.Apply(string(source)); err != nil {
+			return fmt.Errorf(fileErrorString, path, err)
+		}
+	}
+
+	return nil
+}
+
+// Diagram returns the entity relationship diagram of the schema. Tables referenced by
+// foreign keys without being part of the schema are drawn as entities without attributes.
+func (s *Schema) Diagram() *entityrelationship.Diagram {
+	d := entityrelationship.NewDiagram()
+	entities := make(map[string]*entityrelationship.Entity)
+	entity := func(name string) *entityrelationship.Entity {
+		if e, ok := entities[strings.ToLower(name)]; ok {
+			return e
+		}
+		e := d.AddEntity(unsafeName.ReplaceAllString(name, nameJoin))
+		if e.Name != name {
+			e.SetAlias(name)
+		}
+		entities[strings.ToLower(name)] = e
+		return e
+	}
+
+	for _, t := range s.tables {
+		e := entity(t.name)
+		for _, c := range t.columns {
+			attribute := e.AddAttribute(unsafeName.ReplaceAllString(c.name, nameJoin), c.attributeType())
+			if t.primaryKey.contains(c.name) {
+				attribute.SetPrimaryKey()
+			}
+			for _, fk := range t.foreignKeys {
+				if fk.contains(c.name) {
+					attribute.SetForeignKey()
+					break
+				}
+			}
+			for _, unique := range t.unique {
+				if unique.contains(c.name) {
+					attribute.SetUniqueKey()
+					break
+				}
+			}
+			if !t.nullable(c.name) {
+				attribute.SetRequired()
+			}
+		}
+	}
+
+	for _, t := range s.tables {
+		for _, fk := range t.foreignKeys {
+			d.AddRelationship(entity(fk.table), entity(t.name)).
+				SetLabel(strings.Join(fk.columns, labelSeparator)).
+				SetCardinality(t.cardinality(fk))
+		}
+	}
+
+	return d
+}
+
+// attributeType returns the attribute type of the column. MySQL declares booleans as
+// tinyint(1).
+func (c *column) attributeType() entityrelationship.DataType {
+	dataType, ok := dataTypes[c.dataType]
+	switch {
+	case c.dataType == "tinyint" && c.size == booleanSize:
+		dataType = entityrelationship.TypeBoolean
+	case c.dataType == "":
+		dataType = untypedColumn
+	case !ok:
+		dataType = entityrelationship.DataType(unsafeName.ReplaceAllString(c.dataType, nameJoin))
+	}
+	if c.array {
+		dataType += arraySuffix
+	}
+
+	return dataType
+}
+
+// cardinality returns the cardinality of a foreign key from the referenced table: the
+// referenced row is optional when a column of the key is nullable, and has at most one
+// referencing row when the key columns include a primary or unique key.
+func (t *table) cardinality(fk *foreignKey) entityrelationship.Cardinality {
+	optional := false
+	for _, name := range fk.columns {
+		optional = optional || t.nullable(name)
+	}
+
+	unique := t.primaryKey != nil && fk.includes(t.primaryKey)
+	for _, k := range t.unique {
+		unique = unique || fk.includes(k)
+	}
+
+	switch {
+	case unique && optional:
+		return entityrelationship.ZeroOrOneToOne
+	case unique:
+		return entityrelationship.OneToZeroOrOne
+	case optional:
+		return entityrelationship.ZeroOrOneToMany
+	default:
+		return entityrelationship.OneToZeroOrMore
+	}
+}
+
+// nullable reports whether a column of the table can be null: it is neither NOT NULL nor
+// part of the primary key.
+func (t *table) nullable(name string) bool {
+	c := t.column(name)
+	return c != nil && !c.notNull && !t.primaryKey.contains(name)
+}
+
+// column returns the column with a name, or nil if there is none.
+func (t *table) column(name string) *column {
+	for _, c := range t.columns {
+		if strings.EqualFold(c.name, name) {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// contains reports whether the key is over a column. A nil key contains no column.
+func (k *key) contains(name string) bool {
+	if k == nil {
+		return false
+	}
+	for _, c := range k.columns {
+		if strings.EqualFold(c, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// includes reports whether all the columns of another key are columns of the key.
+func (k *key) includes(other *key) bool {
+	for _, c := range other.columns {
+		if !k.contains(c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// rename renames a column of the key.
+func (k *key) rename(from, to string) {
+	for i, c := range k.columns {
+		if strings.EqualFold(c, from) {
+			k.columns[i] = to
+		}
+	}
+}
+
+// named reports whether the key has a name, or would be given that name by PostgreSQL in
+// a table when it has none.
+func (k *key) named(name string, defaultName string) bool {
+	if k.name != "" {
+		return strings.EqualFold(k.name, name)
+	}
+
+	return strings.EqualFold(defaultName, name)
+}
+
+// table returns the table with a name, or nil if there is none.
+func (s *Schema) table(name string) *table {
+	for _, t := range s.tables {
+		if strings.EqualFold(t.name, name) {
+			return t
+		}
+	}
+
+	return nil
+}
+
+// dropTable removes a table and the foreign keys referencing it.
+func (s *Schema) dropTable(t *table) {
+	tables := s.tables[:0]
+	for _, other := range s.tables {
+		if other != t {
+			tables = append(tables, other)
+		}
+	}
+	s.tables = tables
+
+	for _, other := range s.tables {
+		foreignKeys := other.foreignKeys[:0]
+		for _, fk := range other.foreignKeys {
+			if !strings.EqualFold(fk.table, t.name) {
+				foreignKeys = append(foreignKeys, fk)
+			}
+		}
+		other.foreignKeys = foreignKeys
+	}
+}
+
+// renameTable renames a table and the references to it.
+func (s *Schema) renameTable(t *table, name string) {
+	for _, other := range s.tables {
+		for _, fk := range other.foreignKeys {
+			if strings.EqualFold(fk.table, t.name) {
+				fk.table = name
+			}
+		}
+	}
+	t.name = name
+}
+
+// defaultName returns the name PostgreSQL gives to an unnamed key of the table.
+func (t *table) defaultName(columns []string, suffix string) string {
+	if suffix == primaryKeySuffix {
+		return t.name + suffix
+	}
+
+	return t.name + nameJoin + strings.Join(columns, nameJoin) + suffix
+}
+
+// dropKey removes the key, unique constraint or foreign key with a name, reporting whether
+// there was one.
+func (t *table) dropKey(name string) (found bool) {
+	if t.primaryKey != nil && t.primaryKey.named(name, t.defaultName(nil, primaryKeySuffix)) {
+		t.primaryKey = nil
+		found = true
+	}
+
+	unique := t.unique[:0]
+	for _, k := range t.unique {
+		if k.named(name, t.defaultName(k.columns, uniqueKeySuffix)) {
+			found = true
+			continue
+		}
+		unique = append(unique, k)
+	}
+	t.unique = unique
+
+	foreignKeys := t.foreignKeys[:0]
+	for _, fk := range t.foreignKeys {
+		if fk.named(name, t.defaultName(fk.columns, foreignKeySuffix)) {
+			found = true
+			continue
+		}
+		foreignKeys = append(foreignKeys, fk)
+	}
+	t.foreignKeys = foreignKeys
+
+	return found
+}
+
+// dropColumn removes a column and the keys over it.
+func (t *table) dropColumn(c *column) {
+	columns := t.columns[:0]
+	for _, other := range t.columns {
+		if other != c {
+			columns = append(columns, other)
+		}
+	}
+	t.columns = columns
+
+	if t.primaryKey.contains(c.name) {
+		t.primaryKey = nil
+	}
+
+	unique := t.unique[:0]
+	for _, k := range t.unique {
+		if !k.contains(c.name) {
+			unique = append(unique, k)
+		}
+	}
+	t.unique = unique
+
+	foreignKeys := t.foreignKeys[:0]
+	for _, fk := range t.foreignKeys {
+		if !fk.contains(c.name) {
+			foreignKeys = append(foreignKeys, fk)
+		}
+	}
+	t.foreignKeys = foreignKeys
+}
+
+// renameColumn renames a column and its mentions in the keys of the table.
+func (t *table) renameColumn(c *column, name string) {
+	if t.primaryKey != nil {
+		t.primaryKey.rename(c.name, name)
+	}
+	for _, k := range t.unique {
+		k.rename(c.name, name)
+	}
+	for _, fk := range t.foreignKeys {
+		fk.rename(c.name, name)
+	}
+	c.name = name
+}
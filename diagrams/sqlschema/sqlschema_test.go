@@ -0,0 +1,336 @@
+package sqlschema
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/entityrelationship"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/testutils"
+)
+
+// migrations are the test migrations, in the order they apply.
+var migrations = []string{"testdata/migrations/001_init.up.sql", "testdata/migrations/002_products.up.sql"}
+
+func TestParseFiles(t *testing.T) {
+	want := "erDiagram\n" +
+		"    customers {\n" +
+		"        int id PK\n" +
+		"        string email UK\n" +
+		"        string display_name\n" +
+		"        datetime created_at\n" +
+		"    }\n" +
+		"    orders {\n" +
+		"        int id PK\n" +
+		"        int customer_id FK\n" +
+		"        float total\n" +
+		"        boolean paid\n" +
+		"        string[] labels\n" +
+		"    }\n" +
+		"    products {\n" +
+		"        string sku PK\n" +
+		"        float price\n" +
+		"    }\n" +
+		"    order_lines {\n" +
+		"        int order_id PK,FK\n" +
+		"        int line PK\n" +
+		"        string sku FK\n" +
+		"        int quantity\n" +
+		"    }\n" +
+		"    invoices {\n" +
+		"        uuid id PK\n" +
+		"        int order_id FK,UK\n" +
+		"    }\n" +
+		"\n" +
+		"    customers ||--o{ orders : customer_id\n" +
+		"    products |o--o{ order_lines : sku\n" +
+		"    orders ||--o{ order_lines : order_id\n" +
+		"    orders |o--o| invoices : order_id\n"
+
+	d, err := ParseFiles(migrations...)
+	if err != nil {
+		t.Fatalf("ParseFiles() error = %v", err)
+	}
+	if got := testutils.DiagramBody(t, d.String()); got != want {
+		t.Errorf("ParseFiles() = %q, want %q", got, want)
+	}
+
+	reparsed, err := entityrelationship.Parse(d.String())
+	if err != nil {
+		t.Fatalf("entityrelationship.Parse() of the rendering error = %v", err)
+	}
+	if reparsed.String() != d.String() {
+		t.Errorf("entityrelationship.Parse() of the rendering = %q, want %q", reparsed.String(), d.String())
+	}
+}
+
+func TestParseFiles_Required(t *testing.T) {
+	d, err := ParseFiles(migrations...)
+	if err != nil {
+		t.Fatalf("ParseFiles() error = %v", err)
+	}
+
+	tests := []struct {
+		entity    string
+		attribute string
+		want      bool
+	}{
+		{entity: "customers", attribute: "email", want: true},
+		{entity: "customers", attribute: "display_name", want: false},
+		{entity: "orders", attribute: "id", want: true},
+		{entity: "orders", attribute: "paid", want: true},
+		{entity: "order_lines", attribute: "sku", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entity+"."+tt.attribute, func(t *testing.T) {
+			attribute := d.FindEntity(tt.entity).FindAttribute(tt.attribute)
+			if attribute.Required != tt.want {
+				t.Errorf("Required = %v, want %v", attribute.Required, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		want     string
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "MySQL",
+			source: "CREATE TABLE `users` (\n" +
+				"  `id` int(11) unsigned NOT NULL AUTO_INCREMENT,\n" +
+				"  `active` tinyint(1) NOT NULL DEFAULT '1',\n" +
+				"  `key` varchar(64) DEFAULT NULL,\n" +
+				"  `score` double DEFAULT NULL,\n" +
+				"  PRIMARY KEY (`id`),\n" +
+				"  UNIQUE KEY `users_key` (`key`(32)),\n" +
+				"  KEY `users_score` (`score`)\n" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n" +
+				"CREATE TABLE `profiles` (\n" +
+				"  `user_id` int(11) unsigned NOT NULL,\n" +
+				"  `bio` text,\n" +
+				"  UNIQUE INDEX (`user_id`),\n" +
+				"  CONSTRAINT `profiles_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)\n" +
+				");\n" +
+				"ALTER TABLE `users` MODIFY `score` float NOT NULL, CHANGE COLUMN `key` `handle` varchar(64);\n",
+			want: "erDiagram\n" +
+				"    users {\n" +
+				"        int id PK\n" +
+				"        boolean active\n" +
+				"        string handle UK\n" +
+				"        float score\n" +
+				"    }\n" +
+				"    profiles {\n" +
+				"        int user_id FK,UK\n" +
+				"        string bio\n" +
+				"    }\n" +
+				"\n" +
+				"    users ||--o| profiles : user_id\n",
+		},
+		{
+			name: "SQLite",
+			source: "CREATE TABLE notes (id INTEGER PRIMARY KEY AUTOINCREMENT, body, author REFERENCES people(id));\n" +
+				"ALTER TABLE notes RENAME TO memos;\n",
+			want: "erDiagram\n" +
+				"    memos {\n" +
+				"        int id PK\n" +
+				"        blob body\n" +
+				"        blob author FK\n" +
+				"    }\n" +
+				"    people {\n" +
+				"    }\n" +
+				"\n" +
+				"    people |o--o{ memos : author\n",
+		},
+		{
+			name: "Composite foreign key",
+			source: "CREATE TABLE a (x int, y int, PRIMARY KEY (x, y));\n" +
+				"CREATE TABLE b (x int NOT NULL, y int, z int, FOREIGN KEY (x, y) REFERENCES a (x, y));\n",
+			contains: []string{"int x FK\n        int y FK\n        int z\n", "a |o--o{ b : \"x, y\"\n"},
+		},
+		{
+			name: "Foreign key including a unique key",
+			source: "CREATE TABLE a (id int PRIMARY KEY);\n" +
+				"CREATE TABLE b (a_id int NOT NULL PRIMARY KEY REFERENCES a);\n",
+			contains: []string{"int a_id PK,FK\n", "a ||--o| b : a_id\n"},
+		},
+		{
+			name: "Dropped constraints by their default names",
+			source: "CREATE TABLE a (id int PRIMARY KEY);\n" +
+				"CREATE TABLE b (id int PRIMARY KEY, a_id int REFERENCES a, code text UNIQUE);\n" +
+				"ALTER TABLE b DROP CONSTRAINT b_a_id_fkey, DROP CONSTRAINT IF EXISTS b_code_key, DROP CONSTRAINT b_pkey;\n",
+			contains: []string{"    b {\n        int id\n        int a_id\n        string code\n    }\n"},
+			excludes: []string{"--"},
+		},
+		{
+			name: "Unique indexes",
+			source: "CREATE TABLE a (id int, code text, slug text, deleted boolean);\n" +
+				"CREATE UNIQUE INDEX a_code ON a (code);\n" +
+				"CREATE UNIQUE INDEX a_slug ON a USING btree (slug) WHERE NOT deleted;\n" +
+				"CREATE UNIQUE INDEX a_lower_code ON a (lower(code));\n" +
+				"CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS a_id ON ONLY a (id);\n" +
+				"DROP INDEX IF EXISTS a_id;\n",
+			contains: []string{"int id\n        string code UK\n        string slug\n"},
+		},
+		{
+			name: "Ignored statements",
+			source: "CREATE INDEX a_b ON a (b);\n" +
+				"CREATE VIEW v AS SELECT 1;\n" +
+				"CREATE TABLE copy AS SELECT * FROM elsewhere;\n" +
+				"INSERT INTO t VALUES ('it''s; fine', $tag$ ; $tag$);\n" +
+				"DROP TABLE IF EXISTS missing, other CASCADE;\n" +
+				"ALTER TABLE IF EXISTS missing ADD COLUMN x int;\n",
+			want: "erDiagram\n",
+		},
+		{
+			name: "Added and dropped columns",
+			source: "CREATE TABLE a (id int PRIMARY KEY);\n" +
+				"CREATE TABLE b (id int);\n" +
+				"ALTER TABLE b ADD COLUMN IF NOT EXISTS id int, ADD a_id int NOT NULL, ADD FOREIGN KEY (a_id) REFERENCES a;\n" +
+				"ALTER TABLE b ADD COLUMN old text, DROP COLUMN old, DROP COLUMN IF EXISTS missing;\n" +
+				"ALTER TABLE b ALTER COLUMN a_id DROP NOT NULL, ALTER id TYPE varchar(10);\n",
+			contains: []string{"    b {\n        string id\n        int a_id FK\n    }\n", "a |o--o{ b : a_id\n"},
+		},
+		{
+			name: "Renamed tables, columns and constraints",
+			source: "CREATE TABLE a (id int PRIMARY KEY);\n" +
+				"CREATE TABLE b (a_id int, CONSTRAINT fk FOREIGN KEY (a_id) REFERENCES a);\n" +
+				"CREATE TABLE c (x int, CONSTRAINT c_a FOREIGN KEY (x) REFERENCES a (id));\n" +
+				"ALTER TABLE b RENAME CONSTRAINT fk TO b_a, DROP CONSTRAINT fk;\n" +
+				"ALTER TABLE a RENAME TO parents;\n" +
+				"ALTER TABLE b RENAME COLUMN a_id TO parent_id;\n" +
+				"ALTER TABLE c DROP FOREIGN KEY c_a;\n",
+			contains: []string{"    parents {\n", "int parent_id FK\n", "    parents |o--o{ b : parent_id\n"},
+			excludes: []string{"    a {", "c :"},
+		},
+		{
+			name:     "Quoted names",
+			source:   `CREATE TABLE "Order Items" ("Line No" int PRIMARY KEY, "note" "char", amount dec(5));`,
+			contains: []string{"    Order_Items [Order Items] {\n        int Line_No PK\n        string note\n        float amount\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got := testutils.DiagramBody(t, d.String())
+
+			if tt.want != "" && got != tt.want {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("Parse() missing %q in:\n%s", want, got)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("Parse() contains %q in:\n%s", unwanted, got)
+				}
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "Unterminated string",
+			source:  "CREATE TABLE a (id int);\nINSERT INTO a VALUES ('x);\n",
+			wantErr: basediagram.ErrSyntax,
+			wantMsg: "line 2: syntax error: unterminated quoted text",
+		},
+		{
+			name:    "Unterminated comment",
+			source:  "/* CREATE TABLE a (id int);\n",
+			wantErr: basediagram.ErrSyntax,
+		},
+		{
+			name:    "Unclosed parenthesis",
+			source:  "CREATE TABLE a (\n    id int\n",
+			wantErr: basediagram.ErrSyntax,
+			wantMsg: "line 1: syntax error: unclosed parenthesis",
+		},
+		{
+			name:    "Foreign key without references",
+			source:  "CREATE TABLE a (\n    id int,\n    FOREIGN KEY (id)\n);\n",
+			wantErr: basediagram.ErrSyntax,
+			wantMsg: "line 3: syntax error: expected REFERENCES",
+		},
+		{
+			name:    "Unknown table",
+			source:  "CREATE TABLE a (id int);\n\nALTER TABLE b ADD COLUMN x int;\n",
+			wantErr: ErrUnknownTable,
+			wantMsg: "line 3: unknown table b",
+		},
+		{
+			name:    "Unknown column",
+			source:  "CREATE TABLE a (id int);\nALTER TABLE a\n    ALTER COLUMN x SET NOT NULL;\n",
+			wantErr: ErrUnknownColumn,
+			wantMsg: "line 3: unknown column x",
+		},
+		{
+			name:    "Unknown renamed column",
+			source:  "CREATE TABLE a (id int);\nALTER TABLE a RENAME x TO y;\n",
+			wantErr: ErrUnknownColumn,
+		},
+		{
+			name:    "Duplicate table",
+			source:  "CREATE TABLE a (id int);\nCREATE TABLE A (id int);\n",
+			wantErr: ErrDuplicateTable,
+			wantMsg: "line 2: duplicate table A",
+		},
+		{
+			name:    "Duplicate column",
+			source:  "CREATE TABLE a (id int, ID text);\n",
+			wantErr: ErrDuplicateColumn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.source)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			var syntaxErr *basediagram.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Errorf("Parse() error = %T, want *basediagram.SyntaxError", err)
+			}
+			if tt.wantMsg != "" && err.Error() != tt.wantMsg {
+				t.Errorf("Parse() error = %q, want %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestParseFiles_Errors(t *testing.T) {
+	path := t.TempDir() + "/003_broken.sql"
+	if err := os.WriteFile(path, []byte("ALTER TABLE missing DROP COLUMN x;\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ParseFiles(append(migrations, path)...)
+	if !errors.Is(err, ErrUnknownTable) || !strings.HasPrefix(err.Error(), path+": line 1: ") {
+		t.Errorf("ParseFiles() error = %v, want %v prefixed with the path and line", err, ErrUnknownTable)
+	}
+
+	if _, err := ParseFiles("testdata/missing.sql"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ParseFiles() of a missing file error = %v, want %v", err, os.ErrNotExist)
+	}
+}
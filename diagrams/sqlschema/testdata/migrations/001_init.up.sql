@@ -0,0 +1,29 @@
+-- Customers and their orders.
+CREATE TABLE IF NOT EXISTS public.customers (
+    id bigserial PRIMARY KEY,
+    email varchar(255) NOT NULL UNIQUE,
+    "display name" text,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE TABLE orders (
+    id bigserial,
+    customer_id bigint NOT NULL REFERENCES customers (id) ON DELETE CASCADE,
+    total numeric(10, 2) NOT NULL CHECK (total >= 0),
+    paid boolean DEFAULT false,
+    tags text[],
+    CONSTRAINT orders_pkey PRIMARY KEY (id)
+);
+
+/* Replaced by the coupon code in a later migration. */
+CREATE TABLE discounts (
+    id serial PRIMARY KEY,
+    percent real NOT NULL
+);
+
+CREATE OR REPLACE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+    NEW.created_at = now();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
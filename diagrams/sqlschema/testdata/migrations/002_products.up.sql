@@ -0,0 +1,32 @@
+CREATE TABLE products (
+    sku varchar(32) PRIMARY KEY,
+    price money NOT NULL
+);
+
+CREATE TABLE order_lines (
+    order_id bigint NOT NULL,
+    line integer NOT NULL,
+    sku varchar(32) REFERENCES products,
+    quantity int NOT NULL DEFAULT 1,
+    PRIMARY KEY (order_id, line),
+    FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+
+CREATE TABLE invoices (
+    id uuid PRIMARY KEY,
+    order_id bigint
+);
+
+ALTER TABLE invoices ADD CONSTRAINT invoices_order FOREIGN KEY (order_id) REFERENCES orders (id);
+CREATE UNIQUE INDEX invoices_order_key ON invoices (order_id);
+CREATE UNIQUE INDEX customers_lower_email ON customers (lower(email));
+
+ALTER TABLE orders
+    ADD COLUMN discount_id int REFERENCES discounts,
+    ALTER COLUMN paid SET NOT NULL,
+    RENAME COLUMN tags TO labels;
+
+ALTER TABLE orders DROP COLUMN discount_id;
+DROP TABLE discounts;
+
+INSERT INTO products (sku, price) VALUES ('a;b', 1);
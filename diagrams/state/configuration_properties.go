@@ -249,10 +249,18 @@ func (c StateConfigurationProperties) String() string {
 
 	if len(c.properties) > 0 {
 		sb.WriteString(baseStateConfigurationProperties)
-		for _, prop := range c.properties {
+		for _, prop := range basediagram.SortedProperties(c.properties) {
 			sb.WriteString(prop.Format())
 		}
 	}
 
 	return sb.String()
 }
+
+// Clone returns a deep copy of the configuration properties.
+func (c StateConfigurationProperties) Clone() StateConfigurationProperties {
+	return StateConfigurationProperties{
+		ConfigurationProperties: c.ConfigurationProperties.Clone(),
+		properties:              basediagram.CloneProperties(c.properties),
+	}
+}
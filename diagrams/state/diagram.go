@@ -64,3 +64,117 @@ func (d *Diagram) String() string {
 func (d *Diagram) RenderToFile(path string) error {
 	return utils.RenderToFile(path, d.String())
 }
+
+// FindState returns the state with the given ID, searching nested states, or nil if there is none.
+func (d *Diagram) FindState(id string) *State {
+	for _, state := range d.States {
+		if found := state.FindState(id); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// ParentOf returns the composite state that contains the given state,
+// or nil if the state is declared at the top level of the diagram.
+func (d *Diagram) ParentOf(state *State) *State {
+	for _, current := range d.States {
+		if found := current.parentOf(state); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// TransitionsFrom returns all transitions leaving the given state.
+func (d *Diagram) TransitionsFrom(state *State) (transitions []*Transition) {
+	for _, transition := range d.Transitions {
+		if transition.From == state {
+			transitions = append(transitions, transition)
+		}
+	}
+
+	return
+}
+
+// TransitionsTo returns all transitions entering the given state.
+func (d *Diagram) TransitionsTo(state *State) (transitions []*Transition) {
+	for _, transition := range d.Transitions {
+		if transition.To == state {
+			transitions = append(transitions, transition)
+		}
+	}
+
+	return
+}
+
+// TransitionsOf returns all transitions leaving or entering the given state.
+func (d *Diagram) TransitionsOf(state *State) (transitions []*Transition) {
+	for _, transition := range d.Transitions {
+		if transition.From == state || transition.To == state {
+			transitions = append(transitions, transition)
+		}
+	}
+
+	return
+}
+
+// RemoveState removes the state and its nested states from the diagram,
+// together with every transition that references any of them.
+// It returns false if the state is not part of the diagram.
+func (d *Diagram) RemoveState(state *State) bool {
+	var found bool
+
+	if d.States, found = utils.RemoveItem(d.States, state); !found {
+		parent := d.ParentOf(state)
+		if parent == nil {
+			return false
+		}
+		parent.RemoveNestedState(state)
+	}
+
+	removed := make(map[*State]bool)
+	state.walk(func(s *State) {
+		removed[s] = true
+	})
+
+	d.Transitions, _ = utils.RemoveFunc(d.Transitions, func(transition *Transition) bool {
+		return (transition.From != nil && removed[transition.From]) ||
+			(transition.To != nil && removed[transition.To])
+	})
+
+	return true
+}
+
+// ReplaceState replaces oldState with newState in place, re-pointing every transition
+// that referenced oldState.
+// It returns false if oldState is not part of the diagram.
+func (d *Diagram) ReplaceState(oldState *State, newState *State) bool {
+	if !utils.ReplaceItem(d.States, oldState, newState) {
+		parent := d.ParentOf(oldState)
+		if parent == nil {
+			return false
+		}
+		utils.ReplaceItem(parent.Nested, oldState, newState)
+	}
+
+	for _, transition := range d.Transitions {
+		if transition.From == oldState {
+			transition.From = newState
+		}
+		if transition.To == oldState {
+			transition.To = newState
+		}
+	}
+
+	return true
+}
+
+// RemoveTransition removes the transition from the diagram.
+// It returns false if the transition is not part of the diagram.
+func (d *Diagram) RemoveTransition(transition *Transition) (found bool) {
+	d.Transitions, found = utils.RemoveItem(d.Transitions, transition)
+	return
+}
@@ -300,3 +300,135 @@ func TestDiagram_AddTransition(t *testing.T) {
 		})
 	}
 }
+
+func TestDiagram_FindState(t *testing.T) {
+	diagram := NewDiagram()
+	parent := diagram.AddState("Parent", "Parent", StateComposite)
+	child := parent.AddNestedState("Child", "Child", StateNormal)
+
+	tests := []struct {
+		name string
+		id   string
+		want *State
+	}{
+		{
+			name: "Find top-level state",
+			id:   "Parent",
+			want: parent,
+		},
+		{
+			name: "Find nested state",
+			id:   "Child",
+			want: child,
+		},
+		{
+			name: "Missing state",
+			id:   "Missing",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diagram.FindState(tt.id); got != tt.want {
+				t.Errorf("FindState() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if diagram.ParentOf(child) != parent {
+		t.Error("ParentOf() did not return the composite state")
+	}
+}
+
+func TestDiagram_RemoveState(t *testing.T) {
+	diagram := NewDiagram()
+	idle := diagram.AddState("Idle", "Idle", StateNormal)
+	busy := diagram.AddState("Busy", "Busy", StateComposite)
+	working := busy.AddNestedState("Working", "Working", StateNormal)
+	done := diagram.AddState("Done", "Done", StateNormal)
+	diagram.AddTransition(idle, busy, "start")
+	diagram.AddTransition(working, done, "finish")
+	kept := diagram.AddTransition(idle, done, "skip")
+	diagram.AddTransition(nil, idle, "")
+
+	if !diagram.RemoveState(busy) {
+		t.Fatal("RemoveState() = false, want true")
+	}
+
+	if diagram.FindState("Working") != nil {
+		t.Error("RemoveState() did not remove nested states")
+	}
+
+	if len(diagram.Transitions) != 2 || diagram.Transitions[0] != kept {
+		t.Errorf("RemoveState() left transitions %v", diagram.Transitions)
+	}
+
+	if diagram.RemoveState(busy) {
+		t.Error("RemoveState() on removed state = true, want false")
+	}
+}
+
+func TestDiagram_RemoveNestedState(t *testing.T) {
+	diagram := NewDiagram()
+	parent := diagram.AddState("Parent", "Parent", StateComposite)
+	child := parent.AddNestedState("Child", "Child", StateNormal)
+	other := diagram.AddState("Other", "Other", StateNormal)
+	diagram.AddTransition(child, other, "")
+
+	if !diagram.RemoveState(child) {
+		t.Fatal("RemoveState() nested = false, want true")
+	}
+
+	if len(parent.Nested) != 0 || len(diagram.Transitions) != 0 {
+		t.Error("RemoveState() did not remove nested state and its transitions")
+	}
+}
+
+func TestDiagram_ReplaceState(t *testing.T) {
+	diagram := NewDiagram()
+	idle := diagram.AddState("Idle", "Idle", StateNormal)
+	busy := diagram.AddState("Busy", "Busy", StateNormal)
+	transition := diagram.AddTransition(idle, busy, "start")
+	replacement := NewState("Running", "Running", StateNormal)
+
+	if !diagram.ReplaceState(busy, replacement) {
+		t.Fatal("ReplaceState() = false, want true")
+	}
+
+	if transition.To != replacement {
+		t.Errorf("ReplaceState() transition.To = %v, want %v", transition.To, replacement)
+	}
+
+	if diagram.FindState("Running") != replacement {
+		t.Error("ReplaceState() did not insert replacement")
+	}
+
+	if diagram.ReplaceState(busy, replacement) {
+		t.Error("ReplaceState() on missing state = true, want false")
+	}
+}
+
+func TestDiagram_TransitionsOf(t *testing.T) {
+	diagram := NewDiagram()
+	a := diagram.AddState("A", "A", StateNormal)
+	b := diagram.AddState("B", "B", StateNormal)
+	ab := diagram.AddTransition(a, b, "")
+	ba := diagram.AddTransition(b, a, "")
+
+	if got := diagram.TransitionsFrom(a); !reflect.DeepEqual(got, []*Transition{ab}) {
+		t.Errorf("TransitionsFrom() = %v, want %v", got, []*Transition{ab})
+	}
+
+	if got := diagram.TransitionsTo(a); !reflect.DeepEqual(got, []*Transition{ba}) {
+		t.Errorf("TransitionsTo() = %v, want %v", got, []*Transition{ba})
+	}
+
+	if got := diagram.TransitionsOf(a); len(got) != 2 {
+		t.Errorf("TransitionsOf() returned %d transitions, want 2", len(got))
+	}
+
+	if !diagram.RemoveTransition(ab) || diagram.RemoveTransition(ab) {
+		t.Error("RemoveTransition() should succeed once")
+	}
+}
@@ -0,0 +1,194 @@
+package state
+
+import (
+	"encoding/json"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// DocumentType is the type of state diagram JSON documents.
+const DocumentType string = "state"
+
+const documentElementState string = "state"
+
+// diagramDocument is the JSON form of a state diagram. Transitions reference states by
+// ID, with "[*]" for the start or end of the diagram.
+type diagramDocument struct {
+	basediagram.Document
+	States      []stateDocument      `json:"states,omitempty"`
+	Transitions []transitionDocument `json:"transitions,omitempty"`
+}
+
+// stateDocument omits the type of normal states.
+type stateDocument struct {
+	ID          string          `json:"id"`
+	Description string          `json:"description,omitempty"`
+	Type        StateType       `json:"type,omitempty"`
+	Note        *noteDocument   `json:"note,omitempty"`
+	Nested      []stateDocument `json:"nested,omitempty"`
+}
+
+type noteDocument struct {
+	Text     string       `json:"text"`
+	Position NotePosition `json:"position,omitempty"`
+}
+
+// transitionDocument omits the type of solid transitions.
+type transitionDocument struct {
+	From        string         `json:"from"`
+	To          string         `json:"to"`
+	Description string         `json:"description,omitempty"`
+	Type        TransitionType `json:"type,omitempty"`
+}
+
+// MarshalJSON encodes the state diagram as a versioned JSON document. Transitions reference
+// states by ID, so every state they use must be part of the diagram.
+func (d *Diagram) MarshalJSON() ([]byte, error) {
+	config, err := d.Config.EncodeDocument(d.Config.properties)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := diagramDocument{Document: d.EncodeDocument(DocumentType, config)}
+
+	states := make(map[*State]bool)
+	for _, state := range d.States {
+		state.walk(func(s *State) { states[s] = true })
+		doc.States = append(doc.States, encodeState(state))
+	}
+
+	for _, transition := range d.Transitions {
+		transitionDoc := transitionDocument{Description: transition.Description}
+		if transition.Type != TransitionSolid {
+			transitionDoc.Type = transition.Type
+		}
+		if transitionDoc.From, err = encodeReference(transition.From, states); err != nil {
+			return nil, err
+		}
+		if transitionDoc.To, err = encodeReference(transition.To, states); err != nil {
+			return nil, err
+		}
+		doc.Transitions = append(doc.Transitions, transitionDoc)
+	}
+
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON replaces the state diagram with the one described by a JSON document and
+// re-links transitions to the states.
+func (d *Diagram) UnmarshalJSON(data []byte) error {
+	var doc diagramDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	decoded := NewDiagram()
+	if err := decoded.DecodeDocument(DocumentType, doc.Document); err != nil {
+		return err
+	}
+
+	var err error
+	if decoded.Config.ConfigurationProperties, decoded.Config.properties, err = doc.Config.Decode(); err != nil {
+		return err
+	}
+
+	states := make(map[string]*State)
+	for _, stateDoc := range doc.States {
+		state, err := decodeState(stateDoc, states)
+		if err != nil {
+			return err
+		}
+		decoded.States = append(decoded.States, state)
+	}
+
+	for _, transitionDoc := range doc.Transitions {
+		from, err := decodeReference(transitionDoc.From, states)
+		if err != nil {
+			return err
+		}
+		to, err := decodeReference(transitionDoc.To, states)
+		if err != nil {
+			return err
+		}
+
+		transition := decoded.AddTransition(from, to, transitionDoc.Description)
+		if transitionDoc.Type != "" {
+			transition.Type = transitionDoc.Type
+		}
+	}
+
+	*d = *decoded
+
+	return nil
+}
+
+// encodeState returns the document of a state and its nested states.
+func encodeState(state *State) stateDocument {
+	doc := stateDocument{ID: state.ID, Description: state.Description}
+	if state.Type != StateNormal {
+		doc.Type = state.Type
+	}
+	if state.Note != nil {
+		doc.Note = &noteDocument{Text: state.Note.Text, Position: state.Note.Position}
+	}
+	for _, nested := range state.Nested {
+		doc.Nested = append(doc.Nested, encodeState(nested))
+	}
+
+	return doc
+}
+
+// decodeState returns the state described by the document and records it and its nested
+// states by ID.
+func decodeState(doc stateDocument, states map[string]*State) (*State, error) {
+	if states[doc.ID] != nil || doc.ID == terminalState {
+		return nil, basediagram.DuplicateID(documentElementState, doc.ID)
+	}
+
+	stateType := doc.Type
+	if stateType == "" {
+		stateType = StateNormal
+	}
+
+	state := NewState(doc.ID, doc.Description, stateType)
+	if doc.Note != nil {
+		state.AddNote(doc.Note.Text, doc.Note.Position)
+	}
+	states[state.ID] = state
+
+	for _, nestedDoc := range doc.Nested {
+		nested, err := decodeState(nestedDoc, states)
+		if err != nil {
+			return nil, err
+		}
+		state.Nested = append(state.Nested, nested)
+	}
+
+	return state, nil
+}
+
+// encodeReference returns the ID of a state of the diagram, or "[*]" for no state.
+func encodeReference(state *State, states map[*State]bool) (string, error) {
+	if state == nil {
+		return terminalState, nil
+	}
+	if !states[state] {
+		return "", basediagram.UnknownReference(documentElementState, state.ID)
+	}
+
+	return state.ID, nil
+}
+
+// decodeReference returns the state with the given ID, or nil for "[*]".
+func decodeReference(id string, states map[string]*State) (*State, error) {
+	if id == terminalState {
+		return nil, nil
+	}
+
+	state := states[id]
+	if state == nil {
+		return nil, basediagram.UnknownReference(documentElementState, id)
+	}
+
+	return state, nil
+}
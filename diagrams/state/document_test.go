@@ -0,0 +1,107 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+func TestDiagram_JSONRoundTrip(t *testing.T) {
+	original := NewDiagram()
+	original.Title = "Machine"
+	original.Config.SetNodeSpacing(30).SetDefaultRenderer("elk")
+	idle := original.AddState("Idle", "Waiting", StateNormal)
+	idle.AddNote("Initial", NoteLeft)
+	busy := original.AddState("Busy", "Busy", StateComposite)
+	working := busy.AddNestedState("Working", "Working", StateNormal)
+	choice := original.AddState("Check", "", StateChoice)
+	original.AddTransition(nil, idle, "")
+	original.AddTransition(idle, working, "start").SetType(TransitionDashed)
+	original.AddTransition(working, choice, "")
+	original.AddTransition(choice, nil, "done")
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	decoded := NewDiagram()
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if decoded.String() != original.String() {
+		t.Errorf("round trip output differs:\nwant:\n%s\ngot:\n%s", original.String(), decoded.String())
+	}
+
+	if decoded.Transitions[0].From != nil || decoded.Transitions[1].To != decoded.FindState("Working") {
+		t.Error("decoded transitions should reference decoded states")
+	}
+
+	again, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("Marshal() of decoded diagram error = %v", err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("encoding is not stable:\nfirst:  %s\nsecond: %s", data, again)
+	}
+}
+
+func TestDiagram_MarshalJSON(t *testing.T) {
+	d := NewDiagram()
+	idle := d.AddState("Idle", "", StateNormal)
+	d.AddTransition(nil, idle, "")
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `{"version":1,"type":"state","states":[{"id":"Idle"}],"transitions":[{"from":"[*]","to":"Idle"}]}`
+	if string(data) != want {
+		t.Errorf("Marshal() =\n%s\nwant\n%s", data, want)
+	}
+
+	d.AddTransition(idle, NewState("Other", "", StateNormal), "")
+	if _, err := json.Marshal(d); !errors.Is(err, basediagram.ErrUnknownReference) {
+		t.Errorf("Marshal() with a state outside the diagram error = %v, want %v", err, basediagram.ErrUnknownReference)
+	}
+}
+
+func TestDiagram_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{
+			name:  "Transition to a nested state",
+			input: `{"version":1,"type":"state","states":[{"id":"A","nested":[{"id":"B"}]}],"transitions":[{"from":"[*]","to":"B"}]}`,
+		},
+		{
+			name:  "Unknown state",
+			input: `{"version":1,"type":"state","states":[{"id":"A"}],"transitions":[{"from":"A","to":"B"}]}`,
+			want:  basediagram.ErrUnknownReference,
+		},
+		{
+			name:  "Duplicate nested state",
+			input: `{"version":1,"type":"state","states":[{"id":"A","nested":[{"id":"A"}]}]}`,
+			want:  basediagram.ErrDuplicateID,
+		},
+		{
+			name:  "Terminal state declared",
+			input: `{"version":1,"type":"state","states":[{"id":"[*]"}]}`,
+			want:  basediagram.ErrDuplicateID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(tt.input), NewDiagram()); !errors.Is(err, tt.want) {
+				t.Errorf("Unmarshal() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// Merge conflict element kinds reported by Diagram.Merge.
+const (
+	MergeElementState string = "state"
+)
+
+const (
+	mergeReasonDescription string = "description differs (%q vs %q)"
+	mergeReasonType        string = "type differs (%s vs %s)"
+	mergeReasonNote        string = "note differs (%q vs %q)"
+)
+
+// Clone returns a deep copy of the diagram.
+// Transitions of the copy reference the copied states.
+func (d *Diagram) Clone() *Diagram {
+	states := make(map[*State]*State)
+
+	clone := &Diagram{
+		BaseDiagram: d.BaseDiagram,
+		States:      make([]*State, 0, len(d.States)),
+		Transitions: make([]*Transition, 0, len(d.Transitions)),
+	}
+	clone.Config = d.Config.Clone()
+
+	for _, state := range d.States {
+		clone.States = append(clone.States, state.clone(states))
+	}
+
+	mapState := func(state *State) *State {
+		if state == nil {
+			return nil
+		}
+		if cloned, ok := states[state]; ok {
+			return cloned
+		}
+		return state.clone(states)
+	}
+
+	for _, transition := range d.Transitions {
+		cloned := *transition
+		cloned.From = mapState(transition.From)
+		cloned.To = mapState(transition.To)
+		clone.Transitions = append(clone.Transitions, &cloned)
+	}
+
+	return clone
+}
+
+// Clone returns a deep copy of the state, including its nested states and note.
+func (s *State) Clone() *State {
+	return s.clone(make(map[*State]*State))
+}
+
+// Merge copies the states and transitions of other into the diagram
+// and returns the conflicts that were encountered. The other diagram is not modified.
+//
+// States are matched by ID, wherever they are nested. When a state exists in both
+// diagrams the nested states of the incoming state are merged into it; a description,
+// type or note defined differently in both is reported as a conflict and the existing
+// definition is kept. Duplicate transitions are skipped.
+func (d *Diagram) Merge(other *Diagram) (conflicts []basediagram.MergeConflict) {
+	incoming := other.Clone()
+	stateMap := make(map[*State]*State)
+
+	d.States, conflicts = d.mergeStates(d.States, incoming.States, stateMap, conflicts)
+
+	for _, transition := range incoming.Transitions {
+		if mapped, ok := stateMap[transition.From]; ok {
+			transition.From = mapped
+		}
+		if mapped, ok := stateMap[transition.To]; ok {
+			transition.To = mapped
+		}
+
+		if !d.containsTransition(transition) {
+			d.Transitions = append(d.Transitions, transition)
+		}
+	}
+
+	return
+}
+
+// mergeStates adds incoming states that do not exist in the diagram to target and
+// merges the ones that do into their existing counterpart.
+func (d *Diagram) mergeStates(target []*State, incoming []*State, stateMap map[*State]*State, conflicts []basediagram.MergeConflict) ([]*State, []basediagram.MergeConflict) {
+	for _, state := range incoming {
+		existing := d.FindState(state.ID)
+		if existing == nil {
+			target = append(target, state)
+			continue
+		}
+
+		stateMap[state] = existing
+		conflicts = append(conflicts, existing.merge(state)...)
+		existing.Nested, conflicts = d.mergeStates(existing.Nested, state.Nested, stateMap, conflicts)
+	}
+
+	return target, conflicts
+}
+
+// containsTransition reports whether the diagram has a transition equivalent to transition.
+func (d *Diagram) containsTransition(transition *Transition) bool {
+	for _, current := range d.Transitions {
+		if *current == *transition {
+			return true
+		}
+	}
+
+	return false
+}
+
+// merge copies the properties of incoming that are unset in the state
+// and returns the conflicts for properties that are set differently in both.
+func (s *State) merge(incoming *State) (conflicts []basediagram.MergeConflict) {
+	conflict := func(reason string) {
+		conflicts = append(conflicts, basediagram.MergeConflict{Element: MergeElementState, ID: s.ID, Reason: reason})
+	}
+
+	switch {
+	case incoming.Description == "" || s.Description == incoming.Description:
+	case s.Description == "":
+		s.Description = incoming.Description
+	default:
+		conflict(fmt.Sprintf(mergeReasonDescription, s.Description, incoming.Description))
+	}
+
+	if s.Type != incoming.Type {
+		conflict(fmt.Sprintf(mergeReasonType, s.Type, incoming.Type))
+	}
+
+	switch {
+	case incoming.Note == nil || (s.Note != nil && *s.Note == *incoming.Note):
+	case s.Note == nil:
+		s.Note = incoming.Note
+	default:
+		conflict(fmt.Sprintf(mergeReasonNote, s.Note.Text, incoming.Note.Text))
+	}
+
+	return
+}
+
+// clone copies the state tree, recording every copy in states.
+func (s *State) clone(states map[*State]*State) *State {
+	clone := NewState(s.ID, s.Description, s.Type)
+	states[s] = clone
+
+	if s.Note != nil {
+		note := *s.Note
+		clone.Note = &note
+	}
+
+	for _, nested := range s.Nested {
+		clone.Nested = append(clone.Nested, nested.clone(states))
+	}
+
+	return clone
+}
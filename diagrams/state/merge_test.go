@@ -0,0 +1,96 @@
+package state
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiagram_Clone(t *testing.T) {
+	original := NewDiagram()
+	idle := original.AddState("Idle", "Waiting", StateNormal)
+	idle.AddNote("Initial", NoteLeft)
+	busy := original.AddState("Busy", "Busy", StateComposite)
+	working := busy.AddNestedState("Working", "Working", StateNormal)
+	original.AddTransition(nil, idle, "")
+	original.AddTransition(idle, working, "start").SetType(TransitionDashed)
+
+	clone := original.Clone()
+
+	if clone.String() != original.String() {
+		t.Errorf("Clone() output differs:\nwant:\n%s\ngot:\n%s", original.String(), clone.String())
+	}
+
+	clonedIdle := clone.FindState("Idle")
+	clonedWorking := clone.FindState("Working")
+	if clonedIdle == idle || clonedWorking == working || clonedIdle.Note == idle.Note {
+		t.Fatal("Clone() should copy states and notes")
+	}
+
+	if clone.Transitions[0].From != nil || clone.Transitions[1].From != clonedIdle || clone.Transitions[1].To != clonedWorking {
+		t.Error("Clone() transitions should reference cloned states")
+	}
+
+	clone.FindState("Busy").AddNestedState("Paused", "Paused", StateNormal)
+	if len(busy.Nested) != 1 {
+		t.Error("Modifying the clone changed the original")
+	}
+}
+
+func TestState_Clone(t *testing.T) {
+	state := NewState("Parent", "Parent", StateComposite)
+	state.AddNestedState("Child", "Child", StateNormal)
+
+	clone := state.Clone()
+
+	if !reflect.DeepEqual(clone, state) || clone == state || clone.Nested[0] == state.Nested[0] {
+		t.Error("Clone() should return an equal but independent state tree")
+	}
+}
+
+func TestDiagram_Merge(t *testing.T) {
+	base := NewDiagram()
+	idle := base.AddState("Idle", "Idle", StateNormal)
+	busy := base.AddState("Busy", "Busy", StateComposite)
+	busy.AddNestedState("Working", "Working", StateNormal)
+	base.AddTransition(idle, busy, "start")
+
+	other := NewDiagram()
+	otherIdle := other.AddState("Idle", "Waiting", StateNormal)
+	otherBusy := other.AddState("Busy", "Busy", StateComposite)
+	paused := otherBusy.AddNestedState("Paused", "Paused", StateNormal)
+	done := other.AddState("Done", "Done", StateEnd)
+	other.AddTransition(otherIdle, otherBusy, "start")
+	other.AddTransition(paused, done, "finish")
+	otherOutput := other.String()
+
+	conflicts := base.Merge(other)
+
+	ids := []string{}
+	for _, state := range base.States {
+		ids = append(ids, state.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"Idle", "Busy", "Done"}) {
+		t.Errorf("Merge() states = %v", ids)
+	}
+
+	if len(busy.Nested) != 2 || busy.Nested[1].ID != "Paused" {
+		t.Error("Merge() should merge nested states")
+	}
+
+	if len(base.Transitions) != 2 || base.Transitions[1].From != busy.Nested[1] {
+		t.Errorf("Merge() transitions = %v", base.Transitions)
+	}
+
+	want := []string{`state "Idle": description differs ("Idle" vs "Waiting")`}
+	got := []string{}
+	for _, conflict := range conflicts {
+		got = append(got, conflict.Error())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() conflicts = %v, want %v", got, want)
+	}
+
+	if other.String() != otherOutput {
+		t.Error("Merge() modified the other diagram")
+	}
+}
@@ -0,0 +1,279 @@
+package state
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// Keywords of state diagram source.
+const (
+	keywordStateDiagram   = "stateDiagram"
+	keywordStateDiagramV2 = "stateDiagram-v2"
+	keywordState          = "state"
+	compositeOpen         = "{"
+	compositeClose        = "}"
+	quote                 = `"`
+
+	// configKey is the frontmatter configuration member holding the state properties.
+	configKey = "state"
+)
+
+// Statement patterns.
+var (
+	transitionPattern  = regexp.MustCompile(`^(\[\*\]|[\w.]+)\s*-->\s*(\[\*\]|[\w.]+)\s*(?::(.*))?$`)
+	descriptionPattern = regexp.MustCompile(`^([\w.]+)\s*:(.*)$`)
+	notePattern        = regexp.MustCompile(`^note\s+(left|right)\s+of\s+([\w.]+)\s*:(.*)$`)
+	declarationPattern = regexp.MustCompile(`^(?:("(?:[^"\\]|\\.)*")\s+as\s+)?([\w.]+)\s*(<<choice>>|<<fork>>|<<join>>)?\s*(\{)?$`)
+	identifierPattern  = regexp.MustCompile(`^[\w.]+$`)
+)
+
+// stereotypes maps the stereotypes of state declarations to state types.
+var stereotypes = map[string]StateType{
+	"<<choice>>": StateChoice,
+	"<<fork>>":   StateFork,
+	"<<join>>":   StateJoin,
+}
+
+// unsupportedKeywords start the statements the model has no counterpart for.
+var unsupportedKeywords = []string{"direction", "classDef", "class", "style", "note", "--", "accTitle", "accDescr"}
+
+// Parse returns the state diagram described by Mermaid source, such as the output of
+// String. States are created by their declaration or by their first mention, and
+// transitions from or to [*] have no state at that end. Inside composite states,
+// transitions from or to [*] make start and end states, and other transitions are added
+// to the diagram. Errors are *basediagram.SyntaxError values: invalid syntax wraps
+// basediagram.ErrSyntax, and statements the model cannot represent, such as classes,
+// multi-line notes and states its rendering would leave out, wrap
+// basediagram.ErrUnsupported. Unsupported statements are reported together, see
+// basediagram.Source.Read.
+func Parse(source string) (*Diagram, error) {
+	parsed, err := basediagram.ParseSource(source, configKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed.Header.Text != keywordStateDiagram && parsed.Header.Text != keywordStateDiagramV2 {
+		return nil, basediagram.Syntax(parsed.Header.Line, "expected %s, found %q", keywordStateDiagramV2, parsed.Header.Text)
+	}
+
+	p := &stateParser{diagram: NewDiagram(), mentions: make(map[*State]basediagram.Statement)}
+	if err = parsed.Read(p.statement); err != nil {
+		return nil, err
+	}
+	if len(p.open) > 0 {
+		return nil, basediagram.Syntax(p.openLines[len(p.openLines)-1], "composite state is not closed")
+	}
+	if err = errors.Join(append(p.resolveEnds(), p.checkRendered(p.diagram.States, false)...)...); err != nil {
+		return nil, err
+	}
+
+	d := p.diagram
+	d.DecodeSource(parsed)
+	if d.Config.ConfigurationProperties, d.Config.properties, err = parsed.Config.Decode(); err != nil {
+		return nil, basediagram.AtLine(1, err)
+	}
+
+	return d, nil
+}
+
+// stateParser builds a state diagram statement by statement.
+type stateParser struct {
+	diagram   *Diagram
+	open      []*State
+	openLines []int
+	mentions  map[*State]basediagram.Statement
+	current   basediagram.Statement
+	ends      []stateEnd
+}
+
+// stateEnd is a state entered from or left to [*] inside a composite state.
+type stateEnd struct {
+	statement basediagram.Statement
+	state     *State
+	stateType StateType
+}
+
+// statement reads a statement of the diagram body.
+func (p *stateParser) statement(statement basediagram.Statement) error {
+	p.current = statement
+	text := strings.TrimSpace(strings.TrimRight(statement.Text, ";"))
+
+	if text == compositeClose {
+		if len(p.open) == 0 {
+			return basediagram.Syntax(statement.Line, "%s without composite state", compositeClose)
+		}
+		p.open, p.openLines = p.open[:len(p.open)-1], p.openLines[:len(p.openLines)-1]
+		return nil
+	}
+
+	if rest, ok := basediagram.CutKeyword(text, keywordState); ok {
+		return p.declaration(statement, rest)
+	}
+
+	if match := transitionPattern.FindStringSubmatch(text); match != nil {
+		if len(p.open) > 0 {
+			return p.nestedTransition(statement, match[1], match[2], strings.TrimSpace(match[3]))
+		}
+		transition := p.diagram.AddTransition(p.terminal(match[1]), p.terminal(match[2]), strings.TrimSpace(match[3]))
+		transition.Type = TransitionSolid
+		return nil
+	}
+
+	if match := notePattern.FindStringSubmatch(text); match != nil {
+		p.state(match[2]).AddNote(strings.TrimSpace(match[3]), NotePosition(match[1]))
+		return nil
+	}
+
+	if basediagram.HasKeyword(text, unsupportedKeywords...) {
+		return basediagram.Unsupported(statement)
+	}
+
+	if match := descriptionPattern.FindStringSubmatch(text); match != nil {
+		p.state(match[1]).Description = strings.TrimSpace(match[2])
+		return nil
+	}
+	if identifierPattern.MatchString(text) {
+		p.state(text)
+		return nil
+	}
+
+	return basediagram.Syntax(statement.Line, "expected a state, transition or note")
+}
+
+// declaration reads a state declaration: "id", "\"description\" as id", "id <<choice>>" or
+// "id {" opening a composite state.
+func (p *stateParser) declaration(statement basediagram.Statement, rest string) error {
+	match := declarationPattern.FindStringSubmatch(rest)
+	if match == nil {
+		if strings.HasSuffix(rest, compositeOpen) || strings.HasPrefix(rest, quote) {
+			return basediagram.Syntax(statement.Line, "invalid state declaration %q", rest)
+		}
+		return basediagram.Unsupported(statement)
+	}
+
+	state := p.state(match[2])
+	if match[1] != "" {
+		description, err := strconv.Unquote(match[1])
+		if err != nil {
+			description = basediagram.Unquote(match[1])
+		}
+		state.Description = description
+	}
+	if match[3] != "" {
+		state.Type = stereotypes[match[3]]
+	}
+	if match[4] != "" {
+		if match[3] != "" {
+			return basediagram.Syntax(statement.Line, "%s state cannot be composite", state.Type)
+		}
+		state.Type = StateComposite
+		p.open, p.openLines = append(p.open, state), append(p.openLines, statement.Line)
+	}
+
+	return nil
+}
+
+// nestedTransition reads a transition inside a composite state. Transitions between two
+// states are added to the diagram, which renders them after the composite states. The model
+// represents "[*] --> id" and "id --> [*]" inside a composite state as start and end states,
+// which are resolved once the diagram is read.
+func (p *stateParser) nestedTransition(statement basediagram.Statement, from string, to string, description string) error {
+	switch {
+	case from == terminalState && to == terminalState, description != "" && (from == terminalState || to == terminalState):
+		return basediagram.Unsupported(statement)
+	case from == terminalState:
+		p.ends = append(p.ends, stateEnd{statement: statement, state: p.state(to), stateType: StateStart})
+	case to == terminalState:
+		p.ends = append(p.ends, stateEnd{statement: statement, state: p.state(from), stateType: StateEnd})
+	default:
+		transition := p.diagram.AddTransition(p.state(from), p.state(to), description)
+		transition.Type = TransitionSolid
+	}
+
+	return nil
+}
+
+// resolveEnds turns the states entered from or left to [*] inside composite states into
+// start and end states. States that are already of another type, have a description or
+// are both entered from and left to [*] cannot be represented.
+func (p *stateParser) resolveEnds() (errs []error) {
+	types := make(map[*State]StateType)
+	for _, end := range p.ends {
+		if current, ok := types[end.state]; ok && current != end.stateType {
+			errs = append(errs, basediagram.Unsupported(end.statement))
+			continue
+		}
+		if end.state.Type != StateNormal && end.state.Type != end.stateType || end.state.Description != "" {
+			errs = append(errs, basediagram.Unsupported(end.statement))
+			continue
+		}
+		types[end.state] = end.stateType
+		end.state.Type = end.stateType
+	}
+
+	return errs
+}
+
+// terminal returns the state of a transition end, or nil for [*].
+func (p *stateParser) terminal(id string) *State {
+	if id == terminalState {
+		return nil
+	}
+
+	return p.state(id)
+}
+
+// state returns the state with an ID, adding it to the innermost open composite state or
+// to the diagram on its first mention.
+func (p *stateParser) state(id string) *State {
+	if state := p.diagram.FindState(id); state != nil {
+		return state
+	}
+
+	var state *State
+	if len(p.open) == 0 {
+		state = p.diagram.AddState(id, "", StateNormal)
+	} else {
+		state = p.open[len(p.open)-1].AddNestedState(id, "", StateNormal)
+	}
+	p.mentions[state] = p.current
+
+	return state
+}
+
+// checkRendered reports the states the model would leave out of its rendering: states
+// without description, nested states or note, unless a transition names them. Nested
+// states named by a transition are described by their ID, so that the rendering declares
+// them inside their composite state.
+func (p *stateParser) checkRendered(states []*State, nested bool) (errs []error) {
+	for _, state := range states {
+		errs = append(errs, p.checkRendered(state.Nested, true)...)
+
+		silent := (state.Type == StateNormal || state.Type == StateComposite) &&
+			state.Description == "" && len(state.Nested) == 0 && state.Note == nil
+		switch {
+		case !silent:
+		case !p.inTransition(state):
+			errs = append(errs, basediagram.Unsupported(p.mentions[state]))
+		case nested:
+			state.Description = state.ID
+		}
+	}
+
+	return errs
+}
+
+// inTransition reports whether a transition starts or ends at a state.
+func (p *stateParser) inTransition(state *State) bool {
+	for _, transition := range p.diagram.Transitions {
+		if transition.From == state || transition.To == state {
+			return true
+		}
+	}
+
+	return false
+}
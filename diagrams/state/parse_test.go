@@ -0,0 +1,124 @@
+package state
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/testutils"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "States and transitions",
+			source: "stateDiagram-v2\n    [*] --> Idle\n    Idle --> Busy : start\n    state \"Working hard\" as Busy\n    state Check <<choice>>\n    Busy --> Check\n    Check --> [*]\n    state Outer {\n        Inner : inside\n        state Deep {\n            note right of Leaf : deepest\n        }\n    }\n    note left of Idle : waiting\n",
+			want:   "stateDiagram-v2\n    note left of Idle: waiting\n    state \"Working hard\" as Busy\n    state Check <<choice>>\n    state Outer {\n        state \"inside\" as Inner\n        state Deep {\n            note right of Leaf: deepest\n        }\n    }\n\t[*] --> Idle\n\tIdle --> Busy: start\n\tBusy --> Check\n\tCheck --> [*]\n",
+		},
+		{
+			name:   "Transitions inside composite",
+			source: "stateDiagram-v2\n    [*] --> Active\n    state Active {\n        [*] --> Idle\n        Idle --> Running : start\n        Running --> Idle\n        Running --> [*]\n    }\n",
+			want:   "stateDiagram-v2\n    state Active {\n        [*] --> Idle\n        Running --> [*]\n    }\n\t[*] --> Active\n\tIdle --> Running: start\n\tRunning --> Idle\n",
+		},
+		{
+			name:   "Version 1 header",
+			source: "stateDiagram\n    A --> B\n",
+			want:   "stateDiagram-v2\n\tA --> B\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := testutils.DiagramBody(t, d.String()); got != tt.want {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+
+			reparsed, err := Parse(d.String())
+			if err != nil {
+				t.Fatalf("Parse() of the rendering error = %v", err)
+			}
+			if reparsed.String() != d.String() {
+				t.Errorf("Parse() of the rendering = %q, want %q", reparsed.String(), d.String())
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "Other diagram type",
+			source:  "classDiagram\n",
+			wantErr: basediagram.ErrSyntax,
+		},
+		{
+			name:    "Close without composite",
+			source:  "stateDiagram-v2\n    }\n",
+			wantErr: basediagram.ErrSyntax,
+			wantMsg: "line 2: syntax error: } without composite state",
+		},
+		{
+			name:    "Unclosed composite",
+			source:  "stateDiagram-v2\n    state A {\n",
+			wantErr: basediagram.ErrSyntax,
+			wantMsg: "line 2: syntax error: composite state is not closed",
+		},
+		{
+			name:    "State entered from and left to [*] inside composite",
+			source:  "stateDiagram-v2\n    state A {\n        [*] --> B\n        B --> [*]\n    }\n",
+			wantErr: basediagram.ErrUnsupported,
+			wantMsg: "line 4: unsupported syntax: B --> [*]",
+		},
+		{
+			name:    "Unsupported statements are all reported",
+			source:  "stateDiagram-v2\n    classDef hot fill:red\n    A --> B\n    class A hot\n",
+			wantErr: basediagram.ErrUnsupported,
+			wantMsg: "line 2: unsupported syntax: classDef hot fill:red\nline 4: unsupported syntax: class A hot",
+		},
+		{
+			name:    "State left out of the rendering",
+			source:  "stateDiagram-v2\n    state Outer {\n        Leaf\n    }\n",
+			wantErr: basediagram.ErrUnsupported,
+			wantMsg: "line 3: unsupported syntax: Leaf",
+		},
+		{
+			name:    "Class definition",
+			source:  "stateDiagram-v2\n    classDef hot fill:red\n",
+			wantErr: basediagram.ErrUnsupported,
+		},
+		{
+			name:    "Invalid declaration",
+			source:  "stateDiagram-v2\n    state \"unclosed\n",
+			wantErr: basediagram.ErrSyntax,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.source)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			var syntaxErr *basediagram.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Errorf("Parse() error = %T, want *basediagram.SyntaxError", err)
+			}
+			if tt.wantMsg != "" && err.Error() != tt.wantMsg {
+				t.Errorf("Parse() error = %q, want %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
 	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
 )
 
@@ -121,3 +122,48 @@ func (s *State) String(curIndentation string) string {
 
 	return sb.String()
 }
+
+// FindState returns the state itself or a nested state with the given ID, or nil if there is none.
+func (s *State) FindState(id string) *State {
+	if s.ID == id {
+		return s
+	}
+
+	for _, nested := range s.Nested {
+		if found := nested.FindState(id); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// RemoveNestedState removes a state nested directly in the current state.
+// It returns false if the state is not a direct child of the current state.
+func (s *State) RemoveNestedState(state *State) (found bool) {
+	s.Nested, found = utils.RemoveItem(s.Nested, state)
+	return
+}
+
+// parentOf returns the state below s that directly contains the target state.
+func (s *State) parentOf(target *State) *State {
+	for _, nested := range s.Nested {
+		if nested == target {
+			return s
+		}
+		if found := nested.parentOf(target); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// walk calls fn for the state and every state nested below it.
+func (s *State) walk(fn func(*State)) {
+	fn(s)
+
+	for _, nested := range s.Nested {
+		nested.walk(fn)
+	}
+}
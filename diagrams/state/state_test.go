@@ -349,3 +349,24 @@ func TestState_AddNote(t *testing.T) {
 		})
 	}
 }
+
+func TestState_FindState(t *testing.T) {
+	root := NewState("Root", "Root", StateComposite)
+	child := root.AddNestedState("Child", "Child", StateNormal)
+
+	if root.FindState("Root") != root {
+		t.Error("FindState() should find itself")
+	}
+
+	if root.FindState("Child") != child {
+		t.Error("FindState() should find nested state")
+	}
+
+	if root.FindState("Missing") != nil {
+		t.Error("FindState() should return nil for missing state")
+	}
+
+	if !root.RemoveNestedState(child) || root.RemoveNestedState(child) {
+		t.Error("RemoveNestedState() should succeed once")
+	}
+}
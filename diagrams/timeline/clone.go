@@ -0,0 +1,31 @@
+package timeline
+
+// Clone returns a deep copy of the diagram.
+func (d *Diagram) Clone() *Diagram {
+	clone := &Diagram{
+		BaseDiagram: d.BaseDiagram,
+		Sections:    make([]*Section, 0, len(d.Sections)),
+	}
+	clone.Config = d.Config.Clone()
+
+	for _, section := range d.Sections {
+		clonedSection := NewSection(section.Title)
+		for _, event := range section.Events {
+			clonedSection.Events = append(clonedSection.Events, event.Clone())
+		}
+		clone.Sections = append(clone.Sections, clonedSection)
+	}
+
+	return clone
+}
+
+// Clone returns a deep copy of the event and its sub-events.
+func (e *Event) Clone() *Event {
+	clone := NewEvent(e.Title, e.Text)
+
+	for _, subEvent := range e.SubEvents {
+		clone.SubEvents = append(clone.SubEvents, subEvent.Clone())
+	}
+
+	return clone
+}
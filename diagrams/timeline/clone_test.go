@@ -0,0 +1,24 @@
+package timeline
+
+import "testing"
+
+func TestDiagram_Clone(t *testing.T) {
+	original := NewDiagram()
+	original.Title = "History"
+	section := original.AddSection("2024")
+	event := section.AddEvent("Launch", "Product launch")
+	event.AddSubEvent("Press release")
+
+	clone := original.Clone()
+
+	if clone.String() != original.String() {
+		t.Errorf("Clone() output differs:\nwant:\n%s\ngot:\n%s", original.String(), clone.String())
+	}
+
+	clone.Sections[0].Events[0].AddSubEvent("Party")
+	clone.Sections[0].Events[0].Title = "Release"
+
+	if len(event.SubEvents) != 1 || event.Title != "Launch" {
+		t.Error("Modifying the clone changed the original")
+	}
+}
@@ -238,10 +238,18 @@ func (c TimelineConfigurationProperties) String() string {
 
 	if len(c.properties) > 0 {
 		sb.WriteString(baseTimelineConfigurationProperties)
-		for _, prop := range c.properties {
+		for _, prop := range basediagram.SortedProperties(c.properties) {
 			sb.WriteString(prop.Format())
 		}
 	}
 
 	return sb.String()
 }
+
+// Clone returns a deep copy of the configuration properties.
+func (c TimelineConfigurationProperties) Clone() TimelineConfigurationProperties {
+	return TimelineConfigurationProperties{
+		ConfigurationProperties: c.ConfigurationProperties.Clone(),
+		properties:              basediagram.CloneProperties(c.properties),
+	}
+}
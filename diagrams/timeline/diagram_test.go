@@ -76,3 +76,20 @@ func TestDiagram_String(t *testing.T) {
 		})
 	}
 }
+
+func TestDiagram_FindSection(t *testing.T) {
+	diagram := NewDiagram()
+	section := diagram.AddSection("2024")
+
+	if diagram.FindSection("2024") != section {
+		t.Error("FindSection() did not find section")
+	}
+
+	if diagram.FindSection("2025") != nil {
+		t.Error("FindSection() should return nil for missing section")
+	}
+
+	if !diagram.RemoveSection(section) || diagram.RemoveSection(section) {
+		t.Error("RemoveSection() should succeed once")
+	}
+}
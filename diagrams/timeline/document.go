@@ -0,0 +1,96 @@
+package timeline
+
+import (
+	"encoding/json"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// DocumentType is the type of timeline diagram JSON documents.
+const DocumentType string = "timeline"
+
+// diagramDocument is the JSON form of a timeline diagram.
+type diagramDocument struct {
+	basediagram.Document
+	Sections []sectionDocument `json:"sections,omitempty"`
+}
+
+type sectionDocument struct {
+	Title  string          `json:"title,omitempty"`
+	Events []eventDocument `json:"events,omitempty"`
+}
+
+type eventDocument struct {
+	Title     string          `json:"title,omitempty"`
+	Text      string          `json:"text,omitempty"`
+	SubEvents []eventDocument `json:"subEvents,omitempty"`
+}
+
+// MarshalJSON encodes the timeline as a versioned JSON document.
+func (d *Diagram) MarshalJSON() ([]byte, error) {
+	config, err := d.Config.EncodeDocument(d.Config.properties)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := diagramDocument{Document: d.EncodeDocument(DocumentType, config)}
+	for _, section := range d.Sections {
+		doc.Sections = append(doc.Sections, sectionDocument{
+			Title:  section.Title,
+			Events: encodeEvents(section.Events),
+		})
+	}
+
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON replaces the timeline with the one described by a JSON document.
+func (d *Diagram) UnmarshalJSON(data []byte) error {
+	var doc diagramDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	decoded := NewDiagram()
+	if err := decoded.DecodeDocument(DocumentType, doc.Document); err != nil {
+		return err
+	}
+
+	var err error
+	if decoded.Config.ConfigurationProperties, decoded.Config.properties, err = doc.Config.Decode(); err != nil {
+		return err
+	}
+
+	for _, sectionDoc := range doc.Sections {
+		section := decoded.AddSection(sectionDoc.Title)
+		section.Events = append(section.Events, decodeEvents(sectionDoc.Events)...)
+	}
+
+	*d = *decoded
+
+	return nil
+}
+
+// encodeEvents returns the documents of events and their sub-events.
+func encodeEvents(events []*Event) (docs []eventDocument) {
+	for _, event := range events {
+		docs = append(docs, eventDocument{
+			Title:     event.Title,
+			Text:      event.Text,
+			SubEvents: encodeEvents(event.SubEvents),
+		})
+	}
+
+	return
+}
+
+// decodeEvents returns the events described by the documents.
+func decodeEvents(docs []eventDocument) (events []*Event) {
+	for _, doc := range docs {
+		event := NewEvent(doc.Title, doc.Text)
+		event.SubEvents = decodeEvents(doc.SubEvents)
+		events = append(events, event)
+	}
+
+	return
+}
@@ -0,0 +1,87 @@
+package timeline
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+func TestDiagram_JSONRoundTrip(t *testing.T) {
+	original := NewDiagram()
+	original.Title = "History"
+	original.Config.SetDisableMulticolor(true).SetPadding(5)
+	section := original.AddSection("2000s")
+	section.AddEvent("2004", "Facebook").AddSubEvent("Gmail")
+	section.AddEvent("2005", "YouTube")
+	original.AddSection("").AddEvent("2010", "Instagram")
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	decoded := NewDiagram()
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if decoded.String() != original.String() {
+		t.Errorf("round trip output differs:\nwant:\n%s\ngot:\n%s", original.String(), decoded.String())
+	}
+
+	again, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("Marshal() of decoded diagram error = %v", err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("encoding is not stable:\nfirst:  %s\nsecond: %s", data, again)
+	}
+}
+
+func TestDiagram_MarshalJSON(t *testing.T) {
+	d := NewDiagram()
+	d.AddSection("S").AddEvent("2020", "").AddSubEvent("a")
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `{"version":1,"type":"timeline","sections":[{"title":"S","events":[{"title":"2020","subEvents":[{"text":"a"}]}]}]}`
+	if string(data) != want {
+		t.Errorf("Marshal() =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestDiagram_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{
+			name:  "Valid document",
+			input: `{"version":1,"type":"timeline","config":{"properties":{"padding":2.5}},"sections":[{"events":[{"title":"x"}]}]}`,
+		},
+		{
+			name:  "Unsupported version",
+			input: `{"version":2,"type":"timeline"}`,
+			want:  basediagram.ErrSchemaVersion,
+		},
+		{
+			name:  "Null property",
+			input: `{"version":1,"type":"timeline","config":{"properties":{"padding":null}}}`,
+			want:  basediagram.ErrPropertyValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(tt.input), NewDiagram()); !errors.Is(err, tt.want) {
+				t.Errorf("Unmarshal() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package timeline
+
+import (
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// Keywords of timeline source.
+const (
+	keywordTimeline = "timeline"
+	keywordTitle    = "title"
+	keywordSection  = "section"
+	eventSeparator  = ":"
+
+	// configKey is the frontmatter configuration member holding the timeline properties.
+	configKey = "timeline"
+)
+
+// unsupportedKeywords start the statements the model has no counterpart for.
+var unsupportedKeywords = []string{"accTitle", "accDescr"}
+
+// Parse returns the timeline described by Mermaid source, such as the output of String.
+// A title statement sets the diagram title, events before the first section belong to an
+// untitled section, and a statement starting with ":" continues the previous event. Errors
+// are *basediagram.SyntaxError values wrapping basediagram.ErrSyntax, or
+// basediagram.ErrUnsupported for accessibility statements. Unsupported statements are
+// reported together, see basediagram.Source.Read.
+func Parse(source string) (*Diagram, error) {
+	parsed, err := basediagram.ParseSource(source, configKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed.Header.Text != keywordTimeline {
+		return nil, basediagram.Syntax(parsed.Header.Line, "expected %s, found %q", keywordTimeline, parsed.Header.Text)
+	}
+
+	d := NewDiagram()
+	d.DecodeSource(parsed)
+
+	var section *Section
+	var event *Event
+	err = parsed.Read(func(statement basediagram.Statement) error {
+		text := statement.Text
+
+		if rest, ok := basediagram.CutKeyword(text, keywordTitle); ok {
+			d.Title = strings.TrimSpace(rest)
+			return nil
+		}
+		if rest, ok := basediagram.CutKeyword(text, keywordSection); ok {
+			section, event = d.AddSection(strings.TrimSpace(rest)), nil
+			return nil
+		}
+		if basediagram.HasKeyword(text, unsupportedKeywords...) {
+			return basediagram.Unsupported(statement)
+		}
+
+		fields := strings.Split(text, eventSeparator)
+		if title := strings.TrimSpace(fields[0]); title != "" {
+			if section == nil {
+				section = d.AddSection("")
+			}
+			event = section.AddEvent(title, "")
+		} else if event == nil {
+			return basediagram.Syntax(statement.Line, "%s without event", eventSeparator)
+		}
+
+		for _, field := range fields[1:] {
+			field = strings.TrimSpace(field)
+			if event.Text == "" {
+				event.Text = field
+			} else {
+				event.AddSubEvent(field)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Config.ConfigurationProperties, d.Config.properties, err = parsed.Config.Decode(); err != nil {
+		return nil, basediagram.AtLine(1, err)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,95 @@
+package timeline
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/testutils"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "Sections and events",
+			source: "timeline\n    title History\n    2002 : LinkedIn\n    section Later\n    2004 : Facebook : Google\n    2005 : YouTube\n         : Reddit\n",
+			want:   "timeline\n    2002\n    : LinkedIn\n    section Later\n    2004\n    : Facebook\n    : Google\n    2005\n    : YouTube\n    : Reddit\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := testutils.DiagramBody(t, d.String()); got != tt.want {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+
+			reparsed, err := Parse(d.String())
+			if err != nil {
+				t.Fatalf("Parse() of the rendering error = %v", err)
+			}
+			if reparsed.String() != d.String() {
+				t.Errorf("Parse() of the rendering = %q, want %q", reparsed.String(), d.String())
+			}
+		})
+	}
+}
+
+func TestParse_Title(t *testing.T) {
+	d, err := Parse("timeline\n    title History\n")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if d.Title != "History" {
+		t.Errorf("Parse() title = %q, want %q", d.Title, "History")
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "Other diagram type",
+			source:  "journey\n",
+			wantErr: basediagram.ErrSyntax,
+		},
+		{
+			name:    "Text without event",
+			source:  "timeline\n    : orphan\n",
+			wantErr: basediagram.ErrSyntax,
+			wantMsg: "line 2: syntax error: : without event",
+		},
+		{
+			name:    "Accessible title",
+			source:  "timeline\n    accTitle: History\n",
+			wantErr: basediagram.ErrUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.source)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			var syntaxErr *basediagram.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Errorf("Parse() error = %T, want *basediagram.SyntaxError", err)
+			}
+			if tt.wantMsg != "" && err.Error() != tt.wantMsg {
+				t.Errorf("Parse() error = %q, want %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
 	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
 )
 
@@ -47,3 +48,21 @@ func (s *Section) String() string {
 
 	return sb.String()
 }
+
+// FindEvent returns the first event with the given title, or nil if there is none.
+func (s *Section) FindEvent(title string) *Event {
+	for _, event := range s.Events {
+		if event.Title == title {
+			return event
+		}
+	}
+
+	return nil
+}
+
+// RemoveEvent removes the event from the section.
+// It returns false if the event is not part of the section.
+func (s *Section) RemoveEvent(event *Event) (found bool) {
+	s.Events, found = utils.RemoveItem(s.Events, event)
+	return
+}
@@ -107,3 +107,20 @@ func TestSection_String(t *testing.T) {
 		})
 	}
 }
+
+func TestSection_FindEvent(t *testing.T) {
+	section := NewSection("2024")
+	event := section.AddEvent("Launch", "Product launch")
+
+	if section.FindEvent("Launch") != event {
+		t.Error("FindEvent() did not find event")
+	}
+
+	if section.FindEvent("Missing") != nil {
+		t.Error("FindEvent() should return nil for missing event")
+	}
+
+	if !section.RemoveEvent(event) || section.RemoveEvent(event) {
+		t.Error("RemoveEvent() should succeed once")
+	}
+}
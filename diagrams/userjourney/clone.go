@@ -0,0 +1,22 @@
+package userjourney
+
+// Clone returns a deep copy of the diagram.
+func (d *Diagram) Clone() *Diagram {
+	clone := &Diagram{
+		BaseDiagram: d.BaseDiagram,
+		Sections:    make([]*Section, 0, len(d.Sections)),
+	}
+	clone.Config = d.Config.Clone()
+
+	for _, section := range d.Sections {
+		clonedSection := NewSection(section.Title)
+		for _, task := range section.Tasks {
+			clonedTask := *task
+			clonedTask.Participants = append([]string(nil), task.Participants...)
+			clonedSection.Tasks = append(clonedSection.Tasks, &clonedTask)
+		}
+		clone.Sections = append(clone.Sections, clonedSection)
+	}
+
+	return clone
+}
@@ -0,0 +1,23 @@
+package userjourney
+
+import "testing"
+
+func TestDiagram_Clone(t *testing.T) {
+	original := NewDiagram()
+	original.Title = "My day"
+	section := original.AddSection("Morning")
+	task := section.AddTask("Make tea", 5, "Me", "Cat")
+
+	clone := original.Clone()
+
+	if clone.String() != original.String() {
+		t.Errorf("Clone() output differs:\nwant:\n%s\ngot:\n%s", original.String(), clone.String())
+	}
+
+	clone.Sections[0].Tasks[0].Participants[0] = "You"
+	clone.Sections[0].AddTask("Go to work", 1)
+
+	if task.Participants[0] != "Me" || len(section.Tasks) != 1 {
+		t.Error("Modifying the clone changed the original")
+	}
+}
@@ -250,10 +250,18 @@ func (c JourneyConfigurationProperties) String() string {
 
 	if len(c.properties) > 0 {
 		sb.WriteString(baseJourneyConfigurationProperties)
-		for _, prop := range c.properties {
+		for _, prop := range basediagram.SortedProperties(c.properties) {
 			sb.WriteString(prop.Format())
 		}
 	}
 
 	return sb.String()
 }
+
+// Clone returns a deep copy of the configuration properties.
+func (c JourneyConfigurationProperties) Clone() JourneyConfigurationProperties {
+	return JourneyConfigurationProperties{
+		ConfigurationProperties: c.ConfigurationProperties.Clone(),
+		properties:              basediagram.CloneProperties(c.properties),
+	}
+}
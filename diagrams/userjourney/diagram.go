@@ -51,3 +51,21 @@ func (d *Diagram) String() string {
 func (d *Diagram) RenderToFile(path string) error {
 	return utils.RenderToFile(path, d.String())
 }
+
+// FindSection returns the first section with the given title, or nil if there is none.
+func (d *Diagram) FindSection(title string) *Section {
+	for _, section := range d.Sections {
+		if section.Title == title {
+			return section
+		}
+	}
+
+	return nil
+}
+
+// RemoveSection removes the section and its tasks from the diagram.
+// It returns false if the section is not part of the diagram.
+func (d *Diagram) RemoveSection(section *Section) (found bool) {
+	d.Sections, found = utils.RemoveItem(d.Sections, section)
+	return
+}
@@ -77,3 +77,20 @@ func TestDiagram_String(t *testing.T) {
 		})
 	}
 }
+
+func TestDiagram_FindSection(t *testing.T) {
+	diagram := NewDiagram()
+	section := diagram.AddSection("Morning")
+
+	if diagram.FindSection("Morning") != section {
+		t.Error("FindSection() did not find section")
+	}
+
+	if diagram.FindSection("Evening") != nil {
+		t.Error("FindSection() should return nil for missing section")
+	}
+
+	if !diagram.RemoveSection(section) || diagram.RemoveSection(section) {
+		t.Error("RemoveSection() should succeed once")
+	}
+}
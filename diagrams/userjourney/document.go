@@ -0,0 +1,80 @@
+package userjourney
+
+import (
+	"encoding/json"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// DocumentType is the type of user journey diagram JSON documents.
+const DocumentType string = "userjourney"
+
+// diagramDocument is the JSON form of a user journey diagram.
+type diagramDocument struct {
+	basediagram.Document
+	Sections []sectionDocument `json:"sections,omitempty"`
+}
+
+type sectionDocument struct {
+	Title string         `json:"title"`
+	Tasks []taskDocument `json:"tasks,omitempty"`
+}
+
+type taskDocument struct {
+	Title        string   `json:"title"`
+	Score        int      `json:"score"`
+	Participants []string `json:"participants,omitempty"`
+}
+
+// MarshalJSON encodes the user journey as a versioned JSON document.
+func (d *Diagram) MarshalJSON() ([]byte, error) {
+	config, err := d.Config.EncodeDocument(d.Config.properties)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := diagramDocument{Document: d.EncodeDocument(DocumentType, config)}
+	for _, section := range d.Sections {
+		sectionDoc := sectionDocument{Title: section.Title}
+		for _, task := range section.Tasks {
+			sectionDoc.Tasks = append(sectionDoc.Tasks, taskDocument{
+				Title:        task.Title,
+				Score:        task.Score,
+				Participants: task.Participants,
+			})
+		}
+		doc.Sections = append(doc.Sections, sectionDoc)
+	}
+
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON replaces the user journey with the one described by a JSON document.
+// Scores are clamped to 1-5 like AddTask does.
+func (d *Diagram) UnmarshalJSON(data []byte) error {
+	var doc diagramDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	decoded := NewDiagram()
+	if err := decoded.DecodeDocument(DocumentType, doc.Document); err != nil {
+		return err
+	}
+
+	var err error
+	if decoded.Config.ConfigurationProperties, decoded.Config.properties, err = doc.Config.Decode(); err != nil {
+		return err
+	}
+
+	for _, sectionDoc := range doc.Sections {
+		section := decoded.AddSection(sectionDoc.Title)
+		for _, taskDoc := range sectionDoc.Tasks {
+			section.AddTask(taskDoc.Title, taskDoc.Score, taskDoc.Participants...)
+		}
+	}
+
+	*d = *decoded
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package userjourney
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+func TestDiagram_JSONRoundTrip(t *testing.T) {
+	original := NewDiagram()
+	original.Title = "My day"
+	original.Config.SetRightAngles(true).SetActorColours([]string{"red", "blue"})
+	work := original.AddSection("Go to work")
+	work.AddTask("Make tea", 5, "Me")
+	work.AddTask("Go upstairs", 3, "Me", "Cat")
+	original.AddSection("Go home").AddTask("Sit down", 1)
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	decoded := NewDiagram()
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if decoded.String() != original.String() {
+		t.Errorf("round trip output differs:\nwant:\n%s\ngot:\n%s", original.String(), decoded.String())
+	}
+
+	again, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("Marshal() of decoded diagram error = %v", err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("encoding is not stable:\nfirst:  %s\nsecond: %s", data, again)
+	}
+}
+
+func TestDiagram_MarshalJSON(t *testing.T) {
+	d := NewDiagram()
+	d.EnableMarkdownFence()
+	d.AddSection("S").AddTask("T", 4)
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `{"version":1,"type":"userjourney","markdownFence":true,"sections":[{"title":"S","tasks":[{"title":"T","score":4}]}]}`
+	if string(data) != want {
+		t.Errorf("Marshal() =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestDiagram_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantScore int
+		wantErr   error
+	}{
+		{
+			name:      "Valid document",
+			input:     `{"version":1,"type":"userjourney","sections":[{"title":"S","tasks":[{"title":"T","score":2}]}]}`,
+			wantScore: 2,
+		},
+		{
+			name:      "Score above range",
+			input:     `{"version":1,"type":"userjourney","sections":[{"title":"S","tasks":[{"title":"T","score":9}]}]}`,
+			wantScore: 5,
+		},
+		{
+			name:    "Wrong diagram type",
+			input:   `{"version":1,"type":"timeline"}`,
+			wantErr: basediagram.ErrDiagramType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDiagram()
+			err := json.Unmarshal([]byte(tt.input), d)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Unmarshal() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && d.Sections[0].Tasks[0].Score != tt.wantScore {
+				t.Errorf("Score = %d, want %d", d.Sections[0].Tasks[0].Score, tt.wantScore)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package userjourney
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// Keywords of user journey source.
+const (
+	keywordJourney      = "journey"
+	keywordTitle        = "title"
+	keywordSection      = "section"
+	taskSeparator       = ":"
+	participantSplitter = ","
+	minScore            = 1
+	maxScore            = 5
+
+	// configKey is the frontmatter configuration member holding the journey properties.
+	configKey = "journey"
+)
+
+// unsupportedKeywords start the statements the model has no counterpart for.
+var unsupportedKeywords = []string{"accTitle", "accDescr"}
+
+// Parse returns the user journey described by Mermaid source, such as the output of
+// String. A title statement sets the diagram title. Errors are *basediagram.SyntaxError
+// values: invalid syntax, including scores outside 1 to 5, wraps basediagram.ErrSyntax,
+// and tasks outside a section wrap basediagram.ErrUnsupported. Unsupported statements are
+// reported together, see basediagram.Source.Read.
+func Parse(source string) (*Diagram, error) {
+	parsed, err := basediagram.ParseSource(source, configKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed.Header.Text != keywordJourney {
+		return nil, basediagram.Syntax(parsed.Header.Line, "expected %s, found %q", keywordJourney, parsed.Header.Text)
+	}
+
+	d := NewDiagram()
+	d.DecodeSource(parsed)
+
+	var section *Section
+	err = parsed.Read(func(statement basediagram.Statement) error {
+		text := statement.Text
+
+		if rest, ok := basediagram.CutKeyword(text, keywordTitle); ok {
+			d.Title = strings.TrimSpace(rest)
+			return nil
+		}
+		if rest, ok := basediagram.CutKeyword(text, keywordSection); ok {
+			section = d.AddSection(strings.TrimSpace(rest))
+			return nil
+		}
+		if basediagram.HasKeyword(text, unsupportedKeywords...) {
+			return basediagram.Unsupported(statement)
+		}
+
+		fields := strings.SplitN(text, taskSeparator, 3)
+		if len(fields) < 2 {
+			return basediagram.Syntax(statement.Line, "expected a section or task")
+		}
+		score, scoreErr := strconv.Atoi(strings.TrimSpace(fields[1]))
+		if scoreErr != nil || score < minScore || score > maxScore {
+			return basediagram.Syntax(statement.Line, "invalid score %q", strings.TrimSpace(fields[1]))
+		}
+		if section == nil {
+			return basediagram.Unsupported(statement)
+		}
+
+		var participants []string
+		if len(fields) == 3 {
+			for _, participant := range strings.Split(fields[2], participantSplitter) {
+				if participant = strings.TrimSpace(participant); participant != "" {
+					participants = append(participants, participant)
+				}
+			}
+		}
+		section.AddTask(strings.TrimSpace(fields[0]), score, participants...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Config.ConfigurationProperties, d.Config.properties, err = parsed.Config.Decode(); err != nil {
+		return nil, basediagram.AtLine(1, err)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,106 @@
+package userjourney
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/testutils"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "Sections and tasks",
+			source: "journey\n    title My day\n    section Go to work\n        Make tea: 5: Me\n        Go upstairs: 3: Me, Cat\n    section Home\n        Rest: 1\n",
+			want:   "journey\n    section Go to work\n        Make tea: 5: Me\n        Go upstairs: 3: Me,Cat\n    section Home\n        Rest: 1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := testutils.DiagramBody(t, d.String()); got != tt.want {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+
+			reparsed, err := Parse(d.String())
+			if err != nil {
+				t.Fatalf("Parse() of the rendering error = %v", err)
+			}
+			if reparsed.String() != d.String() {
+				t.Errorf("Parse() of the rendering = %q, want %q", reparsed.String(), d.String())
+			}
+		})
+	}
+}
+
+func TestParse_Title(t *testing.T) {
+	d, err := Parse("journey\n    title My day\n")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if d.Title != "My day" {
+		t.Errorf("Parse() title = %q, want %q", d.Title, "My day")
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "Other diagram type",
+			source:  "timeline\n",
+			wantErr: basediagram.ErrSyntax,
+		},
+		{
+			name:    "Task outside a section",
+			source:  "journey\n    Make tea: 5\n",
+			wantErr: basediagram.ErrUnsupported,
+			wantMsg: "line 2: unsupported syntax: Make tea: 5",
+		},
+		{
+			name:    "Score out of range",
+			source:  "journey\n    section Work\n        Code: 9\n",
+			wantErr: basediagram.ErrSyntax,
+			wantMsg: "line 3: syntax error: invalid score \"9\"",
+		},
+		{
+			name:    "Missing score",
+			source:  "journey\n    section Work\n        Code\n",
+			wantErr: basediagram.ErrSyntax,
+		},
+		{
+			name:    "Accessible description",
+			source:  "journey\n    accDescr: Day\n",
+			wantErr: basediagram.ErrUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.source)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			var syntaxErr *basediagram.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Errorf("Parse() error = %T, want *basediagram.SyntaxError", err)
+			}
+			if tt.wantMsg != "" && err.Error() != tt.wantMsg {
+				t.Errorf("Parse() error = %q, want %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
 	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
 )
 
@@ -71,3 +72,21 @@ func (s *Section) String() string {
 
 	return sb.String()
 }
+
+// FindTask returns the first task with the given title, or nil if there is none.
+func (s *Section) FindTask(title string) *Task {
+	for _, task := range s.Tasks {
+		if task.Title == title {
+			return task
+		}
+	}
+
+	return nil
+}
+
+// RemoveTask removes the task from the section.
+// It returns false if the task is not part of the section.
+func (s *Section) RemoveTask(task *Task) (found bool) {
+	s.Tasks, found = utils.RemoveItem(s.Tasks, task)
+	return
+}
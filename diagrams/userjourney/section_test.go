@@ -212,3 +212,20 @@ func TestSection_AddTask(t *testing.T) {
 		})
 	}
 }
+
+func TestSection_FindTask(t *testing.T) {
+	section := NewSection("Morning")
+	task := section.AddTask("Make tea", 5, "Me")
+
+	if section.FindTask("Make tea") != task {
+		t.Error("FindTask() did not find task")
+	}
+
+	if section.FindTask("Missing") != nil {
+		t.Error("FindTask() should return nil for missing task")
+	}
+
+	if !section.RemoveTask(task) || section.RemoveTask(task) {
+		t.Error("RemoveTask() should succeed once")
+	}
+}
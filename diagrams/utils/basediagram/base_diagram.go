@@ -12,6 +12,12 @@ const (
 	baseDiagramTitle     = "title: %s\n"
 )
 
+// Diagram is any diagram that can be written as Mermaid syntax. All diagram types of this
+// module implement it.
+type Diagram interface {
+	String() string
+}
+
 type BaseDiagram[T DiagramProperties] struct {
 	Title  string
 	Config T
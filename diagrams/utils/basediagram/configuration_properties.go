@@ -45,6 +45,22 @@ func (c *ConfigurationProperties) SetFontSize(fontSize int) *ConfigurationProper
 	return c
 }
 
+// MaxTextSize returns the maximum diagram text size Mermaid accepts.
+func (c *ConfigurationProperties) MaxTextSize() int {
+	return c.maxTextSize
+}
+
+// MaxEdges returns the maximum number of edges Mermaid accepts.
+func (c *ConfigurationProperties) MaxEdges() int {
+	return c.maxEdges
+}
+
+// Clone returns a deep copy of the configuration properties.
+func (c ConfigurationProperties) Clone() ConfigurationProperties {
+	c.Theme = c.Theme.Clone()
+	return c
+}
+
 func (c *ConfigurationProperties) String() string {
 	var sb strings.Builder
 
@@ -136,3 +136,35 @@ func TestConfigurationProperties_Setters(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigurationProperties_Clone(t *testing.T) {
+	config := NewConfigurationProperties()
+	config.SetFontSize(20)
+	config.Theme.SetTextColor("#333")
+
+	cloned := config.Clone()
+	cloned.SetFontSize(10)
+	cloned.Theme.SetTextColor("#000")
+
+	if config.fontSize != 20 {
+		t.Errorf("Clone() changed original fontSize to %d", config.fontSize)
+	}
+
+	if config.Theme.Variables[ThemeVarTextColor] != "#333" {
+		t.Error("Clone() should not share theme variables with the original")
+	}
+}
+
+func TestConfigurationProperties_Limits(t *testing.T) {
+	config := NewConfigurationProperties()
+
+	if config.MaxEdges() != 500 || config.MaxTextSize() != 50000 {
+		t.Errorf("default limits = %d edges, %d text size", config.MaxEdges(), config.MaxTextSize())
+	}
+
+	config.SetMaxEdges(10).SetMaxTextSize(200)
+
+	if config.MaxEdges() != 10 || config.MaxTextSize() != 200 {
+		t.Errorf("limits = %d edges, %d text size, want 10 and 200", config.MaxEdges(), config.MaxTextSize())
+	}
+}
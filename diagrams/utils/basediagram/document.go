@@ -0,0 +1,272 @@
+package basediagram
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SchemaVersion is the version of the JSON documents the diagram models are encoded to.
+// It changes when documents of the previous version can no longer be decoded.
+const SchemaVersion int = 1
+
+// Errors returned when decoding a diagram document.
+var (
+	ErrSchemaVersion    = errors.New("unsupported schema version")
+	ErrDiagramType      = errors.New("unexpected diagram type")
+	ErrUnknownReference = errors.New("unknown reference")
+	ErrDuplicateID      = errors.New("duplicate identifier")
+	ErrUnknownValue     = errors.New("unknown value")
+	ErrPropertyValue    = errors.New("unsupported property value")
+)
+
+const (
+	schemaVersionErrorString = "%w %d (supported: %d)"
+	diagramTypeErrorString   = "%w %q (want %q)"
+	elementErrorString       = "%v: %s %q"
+	propertyErrorString      = "%w for %q: %s"
+)
+
+// Document holds the members shared by the JSON documents of all diagram types.
+// Diagram packages embed it in their own document type.
+type Document struct {
+	Version       int                    `json:"version"`
+	Type          string                 `json:"type"`
+	Title         string                 `json:"title,omitempty"`
+	MarkdownFence bool                   `json:"markdownFence,omitempty"`
+	Config        *ConfigurationDocument `json:"config,omitempty"`
+}
+
+// ConfigurationDocument is the JSON form of the configuration of a diagram. Values equal to
+// the defaults of NewConfigurationProperties are omitted, and omitted values decode to
+// those defaults. Properties holds the diagram specific properties by name.
+type ConfigurationDocument struct {
+	Theme          ThemeName                  `json:"theme,omitempty"`
+	ThemeVariables map[string]interface{}     `json:"themeVariables,omitempty"`
+	MaxTextSize    int                        `json:"maxTextSize,omitempty"`
+	MaxEdges       int                        `json:"maxEdges,omitempty"`
+	FontSize       int                        `json:"fontSize,omitempty"`
+	Properties     map[string]json.RawMessage `json:"properties,omitempty"`
+}
+
+// EncodeDocument returns the shared document members of the diagram.
+func (d *BaseDiagram[T]) EncodeDocument(diagramType string, config *ConfigurationDocument) Document {
+	return Document{
+		Version:       SchemaVersion,
+		Type:          diagramType,
+		Title:         d.Title,
+		MarkdownFence: d.IsMarkdownFenceEnabled(),
+		Config:        config,
+	}
+}
+
+// DecodeDocument checks the version and type of a document and sets the title and the
+// markdown fence of the diagram. The configuration is decoded by the diagram package.
+func (d *BaseDiagram[T]) DecodeDocument(diagramType string, doc Document) error {
+	if doc.Version != SchemaVersion {
+		return fmt.Errorf(schemaVersionErrorString, ErrSchemaVersion, doc.Version, SchemaVersion)
+	}
+	if doc.Type != diagramType {
+		return fmt.Errorf(diagramTypeErrorString, ErrDiagramType, doc.Type, diagramType)
+	}
+
+	d.Title = doc.Title
+	if doc.MarkdownFence {
+		d.EnableMarkdownFence()
+	} else {
+		d.DisableMarkdownFence()
+	}
+
+	return nil
+}
+
+// ElementError is the error for an element of a document, identified by its kind and by
+// its ID or name.
+type ElementError struct {
+	Err     error
+	Element string
+	ID      string
+}
+
+// Error returns the kind and identity of the element with the reason.
+func (e *ElementError) Error() string {
+	return fmt.Sprintf(elementErrorString, e.Err, e.Element, e.ID)
+}
+
+// Unwrap returns the reason of the error, such as ErrUnknownReference.
+func (e *ElementError) Unwrap() error {
+	return e.Err
+}
+
+// UnknownReference returns the error for a reference to an element that is not part of
+// the diagram.
+func UnknownReference(element string, id string) error {
+	return &ElementError{Err: ErrUnknownReference, Element: element, ID: id}
+}
+
+// DuplicateID returns the error for an element declared twice with the same identity.
+func DuplicateID(element string, id string) error {
+	return &ElementError{Err: ErrDuplicateID, Element: element, ID: id}
+}
+
+// UnknownValue returns the error for an enumerated value that does not exist.
+func UnknownValue(kind string, value string) error {
+	return &ElementError{Err: ErrUnknownValue, Element: kind, ID: value}
+}
+
+// EncodeDocument returns the configuration and the diagram specific properties as a
+// document, or nil when nothing differs from the defaults.
+func (c *ConfigurationProperties) EncodeDocument(properties map[string]DiagramProperty) (*ConfigurationDocument, error) {
+	defaults := NewConfigurationProperties()
+	doc := &ConfigurationDocument{}
+
+	if c.Theme.Name != defaults.Theme.Name {
+		doc.Theme = c.Theme.Name
+	}
+	if len(c.Theme.Variables) > 0 {
+		doc.ThemeVariables = c.Theme.Clone().Variables
+	}
+	if c.maxTextSize != defaults.maxTextSize {
+		doc.MaxTextSize = c.maxTextSize
+	}
+	if c.maxEdges != defaults.maxEdges {
+		doc.MaxEdges = c.maxEdges
+	}
+	if c.fontSize != defaults.fontSize {
+		doc.FontSize = c.fontSize
+	}
+
+	for name, property := range properties {
+		raw, err := encodeProperty(property)
+		if err != nil {
+			return nil, fmt.Errorf(propertyErrorString, ErrPropertyValue, name, err)
+		}
+		if doc.Properties == nil {
+			doc.Properties = make(map[string]json.RawMessage, len(properties))
+		}
+		doc.Properties[name] = raw
+	}
+
+	if doc.Theme == "" && doc.ThemeVariables == nil && doc.MaxTextSize == 0 && doc.MaxEdges == 0 &&
+		doc.FontSize == 0 && doc.Properties == nil {
+		return nil, nil
+	}
+
+	return doc, nil
+}
+
+// Decode returns the configuration and the diagram specific properties described by the
+// document. A nil document decodes to the default configuration without properties.
+func (d *ConfigurationDocument) Decode() (config ConfigurationProperties, properties map[string]DiagramProperty, err error) {
+	config = NewConfigurationProperties()
+	properties = make(map[string]DiagramProperty)
+	if d == nil {
+		return
+	}
+
+	if d.Theme != "" {
+		config.Theme.Name = d.Theme
+	}
+	if len(d.ThemeVariables) > 0 {
+		config.Theme.Variables = Theme{Variables: d.ThemeVariables}.Clone().Variables
+	}
+	if d.MaxTextSize != 0 {
+		config.maxTextSize = d.MaxTextSize
+	}
+	if d.MaxEdges != 0 {
+		config.maxEdges = d.MaxEdges
+	}
+	if d.FontSize != 0 {
+		config.fontSize = d.FontSize
+	}
+
+	for name, raw := range d.Properties {
+		if properties[name], err = decodeProperty(name, raw); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// encodeProperty returns the JSON value of a property. Whole floats keep a decimal point so
+// that they decode to float properties again.
+func encodeProperty(property DiagramProperty) (json.RawMessage, error) {
+	if f, ok := property.Value().(float64); ok && f == math.Trunc(f) && !math.IsInf(f, 0) {
+		return json.RawMessage(strconv.FormatFloat(f, 'f', 1, 64)), nil
+	}
+
+	return json.Marshal(property.Value())
+}
+
+// decodeProperty returns the property of the type matching a JSON value: booleans, strings,
+// string arrays, integers and numbers with a fraction or exponent.
+func decodeProperty(name string, raw json.RawMessage) (DiagramProperty, error) {
+	base := BaseProperty{Name: name}
+
+	var (
+		b      bool
+		s      string
+		values []string
+		number json.Number
+	)
+
+	switch {
+	case bytes.Equal(bytes.TrimSpace(raw), []byte("null")):
+	case json.Unmarshal(raw, &b) == nil:
+		base.Val = b
+		return &BoolProperty{BaseProperty: base}, nil
+	case json.Unmarshal(raw, &s) == nil:
+		base.Val = s
+		return &StringProperty{BaseProperty: base}, nil
+	case json.Unmarshal(raw, &values) == nil:
+		base.Val = values
+		return &StringArrayProperty{BaseProperty: base}, nil
+	case json.Unmarshal(raw, &number) == nil:
+		if strings.ContainsAny(number.String(), ".eE") {
+			f, err := number.Float64()
+			base.Val = f
+			return &FloatProperty{BaseProperty: base}, err
+		}
+		i, err := strconv.Atoi(number.String())
+		base.Val = i
+		return &IntProperty{BaseProperty: base}, err
+	}
+
+	return nil, fmt.Errorf(propertyErrorString, ErrPropertyValue, name, raw)
+}
+
+// EncodeName returns the document name of an enumerated value, or an empty name when the
+// value is the default and can be omitted.
+func EncodeName[T comparable](names map[T]string, value T, defaultValue T, kind string) (string, error) {
+	if value == defaultValue {
+		return "", nil
+	}
+
+	name, ok := names[value]
+	if !ok {
+		return "", UnknownValue(kind, fmt.Sprint(value))
+	}
+
+	return name, nil
+}
+
+// DecodeName returns the enumerated value with the given document name, or the default
+// value when the name is empty.
+func DecodeName[T comparable](names map[T]string, name string, defaultValue T, kind string) (T, error) {
+	if name == "" {
+		return defaultValue, nil
+	}
+
+	for value, valueName := range names {
+		if valueName == name {
+			return value, nil
+		}
+	}
+
+	return defaultValue, UnknownValue(kind, name)
+}
@@ -0,0 +1,253 @@
+package basediagram
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBaseDiagram_DecodeDocument(t *testing.T) {
+	tests := []struct {
+		name      string
+		doc       Document
+		wantTitle string
+		wantFence bool
+		wantErr   error
+	}{
+		{
+			name:      "Valid document",
+			doc:       Document{Version: SchemaVersion, Type: "flowchart", Title: "Title", MarkdownFence: true},
+			wantTitle: "Title",
+			wantFence: true,
+		},
+		{
+			name:    "Unsupported version",
+			doc:     Document{Version: SchemaVersion + 1, Type: "flowchart"},
+			wantErr: ErrSchemaVersion,
+		},
+		{
+			name:    "Missing version",
+			doc:     Document{Type: "flowchart"},
+			wantErr: ErrSchemaVersion,
+		},
+		{
+			name:    "Other diagram type",
+			doc:     Document{Version: SchemaVersion, Type: "sequence"},
+			wantErr: ErrDiagramType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewBaseDiagram[testConfig](&ConfigurationProperties{})
+			err := d.DecodeDocument("flowchart", tt.doc)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodeDocument() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if d.Title != tt.wantTitle || d.IsMarkdownFenceEnabled() != tt.wantFence {
+				t.Errorf("DecodeDocument() title = %q, fence = %v, want %q, %v",
+					d.Title, d.IsMarkdownFenceEnabled(), tt.wantTitle, tt.wantFence)
+			}
+		})
+	}
+}
+
+func TestConfigurationProperties_EncodeDocument(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     func() ConfigurationProperties
+		properties map[string]DiagramProperty
+		want       string
+	}{
+		{
+			name:   "Defaults are omitted",
+			config: NewConfigurationProperties,
+			want:   `null`,
+		},
+		{
+			name: "Theme and limits",
+			config: func() ConfigurationProperties {
+				c := NewConfigurationProperties()
+				c.SetMaxEdges(10).SetFontSize(12)
+				c.Theme.SetTheme(ThemeDark).SetPrimaryColor("#fff")
+				return c
+			},
+			want: `{"theme":"dark","themeVariables":{"primaryColor":"#fff"},"maxEdges":10,"fontSize":12}`,
+		},
+		{
+			name:   "Typed properties",
+			config: NewConfigurationProperties,
+			properties: map[string]DiagramProperty{
+				"b": &BoolProperty{BaseProperty{Name: "b", Val: true}},
+				"f": &FloatProperty{BaseProperty{Name: "f", Val: 2.0}},
+				"i": &IntProperty{BaseProperty{Name: "i", Val: 2}},
+				"s": &StringProperty{BaseProperty{Name: "s", Val: "x"}},
+				"a": &StringArrayProperty{BaseProperty{Name: "a", Val: []string{"x", "y"}}},
+			},
+			want: `{"properties":{"a":["x","y"],"b":true,"f":2.0,"i":2,"s":"x"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config()
+			doc, err := config.EncodeDocument(tt.properties)
+			if err != nil {
+				t.Fatalf("EncodeDocument() error = %v", err)
+			}
+
+			data, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("EncodeDocument() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigurationDocument_Decode(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		wantTheme      ThemeName
+		wantFontSize   int
+		wantProperties map[string]interface{}
+		wantErr        error
+	}{
+		{
+			name:           "Missing document",
+			input:          `null`,
+			wantTheme:      ThemeDefault,
+			wantFontSize:   NewConfigurationProperties().fontSize,
+			wantProperties: map[string]interface{}{},
+		},
+		{
+			name:         "Theme and properties",
+			input:        `{"theme":"forest","fontSize":20,"properties":{"b":false,"f":1.0,"e":1e2,"i":3,"s":"x","a":["y"]}}`,
+			wantTheme:    ThemeForest,
+			wantFontSize: 20,
+			wantProperties: map[string]interface{}{
+				"b": false, "f": 1.0, "e": 100.0, "i": 3, "s": "x", "a": []string{"y"},
+			},
+		},
+		{
+			name:    "Null property",
+			input:   `{"properties":{"x":null}}`,
+			wantErr: ErrPropertyValue,
+		},
+		{
+			name:    "Object property",
+			input:   `{"properties":{"x":{}}}`,
+			wantErr: ErrPropertyValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc *ConfigurationDocument
+			if err := json.Unmarshal([]byte(tt.input), &doc); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			config, properties, err := doc.Decode()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Decode() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if config.Theme.Name != tt.wantTheme || config.fontSize != tt.wantFontSize {
+				t.Errorf("Decode() theme = %q, font size = %d, want %q, %d",
+					config.Theme.Name, config.fontSize, tt.wantTheme, tt.wantFontSize)
+			}
+
+			got := make(map[string]interface{}, len(properties))
+			for name, property := range properties {
+				got[name] = property.Value()
+			}
+			if !reflect.DeepEqual(got, tt.wantProperties) {
+				t.Errorf("Decode() properties = %v, want %v", got, tt.wantProperties)
+			}
+		})
+	}
+}
+
+func TestEncodeName(t *testing.T) {
+	names := map[string]string{"a": "alpha", "b": "beta"}
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr error
+	}{
+		{name: "Default value", value: "a", want: ""},
+		{name: "Named value", value: "b", want: "beta"},
+		{name: "Unknown value", value: "c", wantErr: ErrUnknownValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EncodeName(names, tt.value, "a", "letter")
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("EncodeName() = %q, %v, want %q, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeName(t *testing.T) {
+	names := map[string]string{"a": "alpha", "b": "beta"}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{name: "Empty name", input: "", want: "a"},
+		{name: "Known name", input: "beta", want: "b"},
+		{name: "Unknown name", input: "gamma", want: "a", wantErr: ErrUnknownValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeName(names, tt.input, "a", "letter")
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("DecodeName() = %q, %v, want %q, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestElementError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		want    string
+		wantErr error
+	}{
+		{name: "Unknown reference", err: UnknownReference("node", "a"), want: `unknown reference: node "a"`, wantErr: ErrUnknownReference},
+		{name: "Duplicate ID", err: DuplicateID("state", "b"), want: `duplicate identifier: state "b"`, wantErr: ErrDuplicateID},
+		{name: "Unknown value", err: UnknownValue("link shape", "c"), want: `unknown value: link shape "c"`, wantErr: ErrUnknownValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var elementErr *ElementError
+			if !errors.As(tt.err, &elementErr) || !errors.Is(tt.err, tt.wantErr) {
+				t.Fatalf("error = %#v, want an *ElementError wrapping %v", tt.err, tt.wantErr)
+			}
+			if tt.err.Error() != tt.want {
+				t.Errorf("Error() = %q, want %q", tt.err.Error(), tt.want)
+			}
+		})
+	}
+}
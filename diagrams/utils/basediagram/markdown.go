@@ -46,3 +46,12 @@ func (m *MarkdownFencer) WrapWithFence(content string) string {
 	sb.WriteString(markdownFenceEnd)
 	return sb.String()
 }
+
+// StripFence returns content without its markdown fence, undoing WrapWithFence.
+// Content that is not fenced is returned unchanged.
+func StripFence(content string) string {
+	if strings.HasPrefix(content, markdownFenceStart) && strings.HasSuffix(content, markdownFenceEnd) {
+		return strings.TrimSuffix(strings.TrimPrefix(content, markdownFenceStart), markdownFenceEnd)
+	}
+	return content
+}
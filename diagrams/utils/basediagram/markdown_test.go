@@ -165,3 +165,45 @@ func TestMarkdownFencer_WrapWithFence(t *testing.T) {
 		})
 	}
 }
+
+func TestStripFence(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "Fenced content",
+			content: "```mermaid\nflowchart TB\n```\n",
+			want:    "flowchart TB",
+		},
+		{
+			name:    "Unfenced content",
+			content: "flowchart TB\n",
+			want:    "flowchart TB\n",
+		},
+		{
+			name:    "Other fence",
+			content: "```go\nfunc main() {}\n```\n",
+			want:    "```go\nfunc main() {}\n```\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripFence(tt.content); got != tt.want {
+				t.Errorf("StripFence() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripFence_RoundTrip(t *testing.T) {
+	fencer := NewMarkdownFencer()
+	fencer.EnableMarkdownFence()
+
+	content := "sequenceDiagram\n    A->>B: Hi\n"
+	if got := StripFence(fencer.WrapWithFence(content)); got != content {
+		t.Errorf("StripFence(WrapWithFence()) = %q, want %q", got, content)
+	}
+}
@@ -0,0 +1,31 @@
+package basediagram
+
+import "fmt"
+
+const (
+	mergeConflictString         = "%s %q: %s"
+	mergeConflictRemappedString = "%s %q: %s (added as %q)"
+)
+
+// MergeConflict describes an element that exists in both diagrams of a merge
+// under the same identity but with different content.
+type MergeConflict struct {
+	// Element is the kind of element, such as "node", "class" or "entity".
+	Element string
+	// ID is the identity shared by both elements.
+	ID string
+	// NewID is the identity assigned to the incoming element when it was remapped.
+	// It is empty when the existing element was kept and the contents were merged.
+	NewID string
+	// Reason explains how the elements differ.
+	Reason string
+}
+
+// Error implements the error interface so conflicts can be returned or joined as errors.
+func (c MergeConflict) Error() string {
+	if c.NewID != "" {
+		return fmt.Sprintf(mergeConflictRemappedString, c.Element, c.ID, c.Reason, c.NewID)
+	}
+
+	return fmt.Sprintf(mergeConflictString, c.Element, c.ID, c.Reason)
+}
@@ -0,0 +1,39 @@
+package basediagram
+
+import "testing"
+
+func TestMergeConflict_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		conflict MergeConflict
+		want     string
+	}{
+		{
+			name: "Merged conflict",
+			conflict: MergeConflict{
+				Element: "entity",
+				ID:      "ORDER",
+				Reason:  "alias differs",
+			},
+			want: `entity "ORDER": alias differs`,
+		},
+		{
+			name: "Remapped conflict",
+			conflict: MergeConflict{
+				Element: "node",
+				ID:      "1",
+				NewID:   "7",
+				Reason:  "text differs",
+			},
+			want: `node "1": text differs (added as "7")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.conflict.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
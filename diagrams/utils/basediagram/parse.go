@@ -0,0 +1,459 @@
+package basediagram
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Errors returned when parsing Mermaid source.
+var (
+	ErrSyntax      = errors.New("syntax error")
+	ErrUnsupported = errors.New("unsupported syntax")
+	ErrNoDiagram   = errors.New("missing diagram declaration")
+)
+
+const (
+	syntaxErrorString      = "line %d: %v"
+	unsupportedErrorString = "%w: %s"
+	frontmatterErrorString = "%w: frontmatter: %s"
+	keyErrorString         = "%w: frontmatter key %q"
+
+	frontmatterSeparator = "---"
+	commentPrefix        = "%%"
+	directivePrefix      = "%%{"
+	sourceNewline        = "\n"
+	keywordTerminators   = " \t:{"
+	keyValueSeparator    = ":"
+
+	frontmatterTitle     = "title"
+	frontmatterConfig    = "config"
+	configTheme          = "theme"
+	configThemeVariables = "themeVariables"
+	configMaxTextSize    = "maxTextSize"
+	configMaxEdges       = "maxEdges"
+	configFontSize       = "fontSize"
+)
+
+// defaultConfigMembers are the members of the configuration that every diagram renders,
+// even when they hold their default.
+var defaultConfigMembers = map[string]bool{
+	configTheme:       true,
+	configMaxTextSize: true,
+	configMaxEdges:    true,
+	configFontSize:    true,
+}
+
+// SyntaxError is a problem at a line of Mermaid source. Lines are 1-based and count from
+// the first line of the source, including its frontmatter and markdown fence.
+type SyntaxError struct {
+	Line int
+	Err  error
+}
+
+// Error returns the line and reason of the error.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf(syntaxErrorString, e.Line, e.Err)
+}
+
+// Unwrap returns the reason of the error, such as ErrSyntax or ErrUnsupported.
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// Syntax returns the error for invalid Mermaid syntax at a line.
+func Syntax(line int, format string, args ...interface{}) error {
+	return &SyntaxError{Line: line, Err: fmt.Errorf("%w: "+format, append([]interface{}{ErrSyntax}, args...)...)}
+}
+
+// Unsupported returns the error for a valid Mermaid statement that the diagram model cannot
+// represent.
+func Unsupported(statement Statement) error {
+	return &SyntaxError{Line: statement.Line, Err: fmt.Errorf(unsupportedErrorString, ErrUnsupported, statement.Text)}
+}
+
+// AtLine returns an error, such as the ElementError of a duplicate identifier, located at a
+// line of the source.
+func AtLine(line int, err error) error {
+	return &SyntaxError{Line: line, Err: err}
+}
+
+// Split returns the errors joined into err, such as the unsupported statements reported by
+// Source.Read, or err alone.
+func Split(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+
+	return []error{err}
+}
+
+// Statement is a line of Mermaid source without its indentation.
+type Statement struct {
+	Line int
+	Text string
+}
+
+// Source is Mermaid source split into its frontmatter and its statements. Blank lines and
+// comments are left out.
+type Source struct {
+	Title  string
+	Config *ConfigurationDocument
+	// Fenced reports whether the source was wrapped in a markdown fence.
+	Fenced bool
+	// Header is the statement declaring the diagram type, such as "flowchart LR".
+	Header     Statement
+	Statements []Statement
+}
+
+// Keyword returns the diagram type keyword of the header, such as "flowchart".
+func (s *Source) Keyword() string {
+	return HeaderKeyword(s.Header.Text)
+}
+
+// Read calls read for each statement in order. Statements the diagram model cannot
+// represent do not stop the reading: their errors, wrapping ErrUnsupported, are joined and
+// returned once every statement is read. Any other error stops the reading and is returned,
+// unless an unsupported statement came before it, as it may follow from the statement left
+// out.
+func (s *Source) Read(read func(Statement) error) error {
+	var unsupported []error
+	for _, statement := range s.Statements {
+		err := read(statement)
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrUnsupported):
+			unsupported = append(unsupported, err)
+		case len(unsupported) == 0:
+			return err
+		default:
+			return errors.Join(unsupported...)
+		}
+	}
+
+	return errors.Join(unsupported...)
+}
+
+// HeaderKeyword returns the first word of a diagram header statement.
+func HeaderKeyword(header string) string {
+	if fields := strings.Fields(header); len(fields) > 0 {
+		return fields[0]
+	}
+
+	return ""
+}
+
+// SourceKeyword returns the diagram type keyword of Mermaid source, such as "flowchart",
+// without parsing the frontmatter or the statements, or "" when the source declares no
+// diagram.
+func SourceKeyword(source string) string {
+	started, frontmatter := false, false
+
+	for _, line := range strings.Split(StripFence(source), sourceNewline) {
+		text := strings.TrimSpace(line)
+
+		switch {
+		case frontmatter:
+			frontmatter = text != frontmatterSeparator
+		case text == "":
+		case text == frontmatterSeparator && !started:
+			started, frontmatter = true, true
+		case strings.HasPrefix(text, commentPrefix):
+			started = true
+		default:
+			return HeaderKeyword(text)
+		}
+	}
+
+	return ""
+}
+
+// ParseSource splits Mermaid source into its parts. The source may be wrapped in a markdown
+// fence. The configuration of the frontmatter becomes a configuration document whose
+// properties are the members of the configKey mapping, such as "flowchart"; configuration
+// Mermaid accepts but the models cannot represent is reported as unsupported, and so are
+// %%{init}%% directives.
+func ParseSource(source string, configKey string) (parsed *Source, err error) {
+	source = strings.ReplaceAll(source, "\r\n", sourceNewline)
+	stripped := StripFence(source)
+	lines := strings.Split(stripped, sourceNewline)
+	parsed = &Source{Fenced: stripped != source}
+
+	// Lines of fenced source are counted from the opening fence.
+	offset := 1
+	if parsed.Fenced {
+		offset = 2
+	}
+
+	first := 0
+	for first < len(lines) && strings.TrimSpace(lines[first]) == "" {
+		first++
+	}
+
+	if first < len(lines) && strings.TrimSpace(lines[first]) == frontmatterSeparator {
+		end := first + 1
+		for end < len(lines) && strings.TrimSpace(lines[end]) != frontmatterSeparator {
+			end++
+		}
+		if end == len(lines) {
+			return nil, Syntax(first+offset, "frontmatter is not closed")
+		}
+
+		if err = parsed.parseFrontmatter(strings.Join(lines[first+1:end], sourceNewline), first+offset, configKey); err != nil {
+			return nil, err
+		}
+		first = end + 1
+	}
+
+	for i := first; i < len(lines); i++ {
+		statement := Statement{Line: i + offset, Text: strings.TrimSpace(lines[i])}
+
+		switch {
+		case statement.Text == "":
+			continue
+		case strings.HasPrefix(statement.Text, directivePrefix):
+			return nil, Unsupported(statement)
+		case strings.HasPrefix(statement.Text, commentPrefix):
+			continue
+		}
+
+		if parsed.Header.Line == 0 {
+			parsed.Header = statement
+			continue
+		}
+		parsed.Statements = append(parsed.Statements, statement)
+	}
+
+	if parsed.Header.Line == 0 {
+		last := len(lines)
+		if last > 1 && lines[last-1] == "" {
+			last--
+		}
+		return nil, &SyntaxError{Line: last + offset - 1, Err: ErrNoDiagram}
+	}
+
+	return parsed, nil
+}
+
+// parseFrontmatter reads the title and configuration of the YAML frontmatter starting after
+// the separator at the given line.
+func (s *Source) parseFrontmatter(frontmatter string, separatorLine int, configKey string) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(frontmatter), &root); err != nil {
+		return &SyntaxError{Line: separatorLine, Err: fmt.Errorf(frontmatterErrorString, ErrSyntax, err)}
+	}
+	if len(root.Content) == 0 {
+		return nil
+	}
+
+	mapping := root.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return Syntax(separatorLine+mapping.Line, "frontmatter is not a mapping")
+	}
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		line := separatorLine + key.Line
+
+		switch key.Value {
+		case frontmatterTitle:
+			if err := value.Decode(&s.Title); err != nil {
+				return Syntax(line, "%v", err)
+			}
+		case frontmatterConfig:
+			config, err := decodeConfig(value, separatorLine, configKey)
+			if err != nil {
+				return err
+			}
+			s.Config = config
+		default:
+			return &SyntaxError{Line: line, Err: fmt.Errorf(keyErrorString, ErrUnsupported, key.Value)}
+		}
+	}
+
+	return nil
+}
+
+// decodeConfig returns the configuration document of the config mapping of a frontmatter.
+func decodeConfig(node *yaml.Node, separatorLine int, configKey string) (*ConfigurationDocument, error) {
+	config := &ConfigurationDocument{}
+	if node.Kind != yaml.MappingNode {
+		return nil, Syntax(separatorLine+node.Line, "config is not a mapping")
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		line := separatorLine + key.Line
+
+		var err error
+		switch key.Value {
+		case configTheme:
+			err = value.Decode(&config.Theme)
+		case configThemeVariables:
+			err = value.Decode(&config.ThemeVariables)
+		case configMaxTextSize:
+			err = value.Decode(&config.MaxTextSize)
+		case configMaxEdges:
+			err = value.Decode(&config.MaxEdges)
+		case configFontSize:
+			err = value.Decode(&config.FontSize)
+		case configKey:
+			config.Properties, err = decodeProperties(value)
+		default:
+			return nil, &SyntaxError{Line: line, Err: fmt.Errorf(keyErrorString, ErrUnsupported, frontmatterConfig+"."+key.Value)}
+		}
+		if err != nil {
+			return nil, Syntax(line, "%s: %v", key.Value, err)
+		}
+	}
+
+	return config, nil
+}
+
+// decodeProperties returns the JSON values of the diagram specific properties of a
+// configuration.
+func decodeProperties(node *yaml.Node) (map[string]json.RawMessage, error) {
+	var values map[string]interface{}
+	if err := node.Decode(&values); err != nil {
+		return nil, err
+	}
+
+	properties := make(map[string]json.RawMessage, len(values))
+	for name, value := range values {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = decodeProperty(name, raw); err != nil {
+			return nil, err
+		}
+		properties[name] = raw
+	}
+
+	return properties, nil
+}
+
+// DecodeSource sets the title and the markdown fence of the diagram from parsed source. The
+// configuration is decoded by the diagram package, see ConfigurationDocument.Decode.
+func (d *BaseDiagram[T]) DecodeSource(source *Source) {
+	d.Title = source.Title
+	if source.Fenced {
+		d.EnableMarkdownFence()
+	} else {
+		d.DisableMarkdownFence()
+	}
+}
+
+// TrimFrontmatter returns the rendering of the diagram parsed from Mermaid source without
+// the configuration members the source leaves to their defaults, so that formatting the
+// source keeps its frontmatter as written. A frontmatter left empty is removed. The
+// rendering must not be wrapped in a markdown fence.
+func TrimFrontmatter(rendering string, source string) string {
+	lines := strings.SplitAfter(rendering, sourceNewline)
+	if strings.TrimSpace(lines[0]) != frontmatterSeparator {
+		return rendering
+	}
+
+	written := configMembers(source)
+	kept := make([]string, 0, len(lines))
+	end := 1
+	for ; end < len(lines) && strings.TrimSpace(lines[end]) != frontmatterSeparator; end++ {
+		line := lines[end]
+		key, _, _ := strings.Cut(strings.TrimSpace(line), keyValueSeparator)
+		if strings.HasPrefix(line, Indentation) && !strings.HasPrefix(line, Indentation+Indentation) &&
+			defaultConfigMembers[key] && !written[key] {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	if end == len(lines) {
+		return rendering
+	}
+
+	// The config member is rendered even when all its members are left out.
+	if last := len(kept) - 1; last >= 0 && strings.TrimSpace(kept[last]) == frontmatterConfig+keyValueSeparator {
+		kept = kept[:last]
+	}
+	body := strings.Join(lines[end+1:], "")
+	if len(kept) == 0 {
+		return body
+	}
+
+	return lines[0] + strings.Join(kept, "") + lines[end] + body
+}
+
+// configMembers returns the members of the config mapping of the frontmatter of Mermaid
+// source.
+func configMembers(source string) map[string]bool {
+	members := make(map[string]bool)
+	lines := strings.Split(strings.ReplaceAll(StripFence(source), "\r\n", sourceNewline), sourceNewline)
+
+	first := 0
+	for first < len(lines) && strings.TrimSpace(lines[first]) == "" {
+		first++
+	}
+	if first == len(lines) || strings.TrimSpace(lines[first]) != frontmatterSeparator {
+		return members
+	}
+	end := first + 1
+	for end < len(lines) && strings.TrimSpace(lines[end]) != frontmatterSeparator {
+		end++
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(strings.Join(lines[first+1:end], sourceNewline)), &root); err != nil || len(root.Content) == 0 {
+		return members
+	}
+	mapping := root.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if config := mapping.Content[i+1]; mapping.Content[i].Value == frontmatterConfig && config.Kind == yaml.MappingNode {
+			for j := 0; j+1 < len(config.Content); j += 2 {
+				members[config.Content[j].Value] = true
+			}
+		}
+	}
+
+	return members
+}
+
+// CutKeyword returns the rest of a statement starting with a keyword followed by a space,
+// or by nothing.
+func CutKeyword(text string, keyword string) (rest string, ok bool) {
+	if !strings.HasPrefix(text, keyword) {
+		return "", false
+	}
+
+	rest = text[len(keyword):]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return "", false
+	}
+
+	return strings.TrimSpace(rest), true
+}
+
+// HasKeyword reports whether a statement starts with one of the keywords followed by a
+// space, a colon, a brace or by nothing, as statements such as "accTitle: text" are.
+func HasKeyword(text string, keywords ...string) bool {
+	for _, keyword := range keywords {
+		if !strings.HasPrefix(text, keyword) {
+			continue
+		}
+		if rest := text[len(keyword):]; rest == "" || strings.ContainsRune(keywordTerminators, rune(rest[0])) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Unquote returns a text without the double quotes around it, if any.
+func Unquote(text string) string {
+	if len(text) >= 2 && strings.HasPrefix(text, `"`) && strings.HasSuffix(text, `"`) {
+		return text[1 : len(text)-1]
+	}
+
+	return text
+}
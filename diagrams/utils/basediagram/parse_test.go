@@ -0,0 +1,307 @@
+package basediagram
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseSource(t *testing.T) {
+	tests := []struct {
+		name           string
+		source         string
+		wantTitle      string
+		wantConfig     *ConfigurationDocument
+		wantFenced     bool
+		wantHeader     Statement
+		wantStatements []Statement
+		wantErr        error
+		wantMsg        string
+	}{
+		{
+			name:           "Statements",
+			source:         "flowchart LR\n\n    A --> B\n    %% comment\n\tB --> C\n",
+			wantHeader:     Statement{Line: 1, Text: "flowchart LR"},
+			wantStatements: []Statement{{Line: 3, Text: "A --> B"}, {Line: 5, Text: "B --> C"}},
+		},
+		{
+			name:      "Frontmatter",
+			source:    "\n---\ntitle: Flow\nconfig:\n    theme: dark\n    maxEdges: 10\n    flowchart:\n        curve: basis\n---\r\nflowchart TB\r\n",
+			wantTitle: "Flow",
+			wantConfig: &ConfigurationDocument{
+				Theme:      ThemeDark,
+				MaxEdges:   10,
+				Properties: map[string]json.RawMessage{"curve": json.RawMessage(`"basis"`)},
+			},
+			wantHeader: Statement{Line: 10, Text: "flowchart TB"},
+		},
+		{
+			name:           "Fenced source counts lines from the fence",
+			source:         "```mermaid\nflowchart TB\n    A\n```\n",
+			wantFenced:     true,
+			wantHeader:     Statement{Line: 2, Text: "flowchart TB"},
+			wantStatements: []Statement{{Line: 3, Text: "A"}},
+		},
+		{
+			name:    "Directive",
+			source:  "%%{init: {\"theme\": \"dark\"}}%%\nflowchart TB\n",
+			wantErr: ErrUnsupported,
+			wantMsg: "line 1: unsupported syntax: %%{init: {\"theme\": \"dark\"}}%%",
+		},
+		{
+			name:    "Unknown frontmatter key",
+			source:  "---\ntitle: Flow\ndisplayMode: compact\n---\nflowchart TB\n",
+			wantErr: ErrUnsupported,
+			wantMsg: "line 3: unsupported syntax: frontmatter key \"displayMode\"",
+		},
+		{
+			name:    "Unknown configuration key",
+			source:  "---\nconfig:\n    look: handDrawn\n---\nflowchart TB\n",
+			wantErr: ErrUnsupported,
+			wantMsg: "line 3: unsupported syntax: frontmatter key \"config.look\"",
+		},
+		{
+			name:    "Invalid configuration value",
+			source:  "---\nconfig:\n    maxEdges: many\n---\nflowchart TB\n",
+			wantErr: ErrSyntax,
+		},
+		{
+			name:    "Invalid frontmatter",
+			source:  "---\ntitle: [\n---\nflowchart TB\n",
+			wantErr: ErrSyntax,
+		},
+		{
+			name:    "Unclosed frontmatter",
+			source:  "---\ntitle: Flow\nflowchart TB\n",
+			wantErr: ErrSyntax,
+			wantMsg: "line 1: syntax error: frontmatter is not closed",
+		},
+		{
+			name:    "No diagram",
+			source:  "---\ntitle: Flow\n---\n%% comment\n",
+			wantErr: ErrNoDiagram,
+			wantMsg: "line 4: missing diagram declaration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSource(tt.source, "flowchart")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseSource() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				var syntaxErr *SyntaxError
+				if !errors.As(err, &syntaxErr) {
+					t.Errorf("ParseSource() error = %T, want *SyntaxError", err)
+				}
+				if tt.wantMsg != "" && err.Error() != tt.wantMsg {
+					t.Errorf("ParseSource() error = %q, want %q", err.Error(), tt.wantMsg)
+				}
+				return
+			}
+
+			if got.Title != tt.wantTitle || got.Fenced != tt.wantFenced || got.Header != tt.wantHeader {
+				t.Errorf("ParseSource() = %q, %v, %v, want %q, %v, %v",
+					got.Title, got.Fenced, got.Header, tt.wantTitle, tt.wantFenced, tt.wantHeader)
+			}
+			if len(got.Statements) != len(tt.wantStatements) ||
+				(len(got.Statements) > 0 && !reflect.DeepEqual(got.Statements, tt.wantStatements)) {
+				t.Errorf("ParseSource() statements = %v, want %v", got.Statements, tt.wantStatements)
+			}
+			if tt.wantConfig == nil {
+				if got.Config != nil {
+					t.Errorf("ParseSource() config = %+v, want none", got.Config)
+				}
+				return
+			}
+			if got.Config == nil || got.Config.Theme != tt.wantConfig.Theme || got.Config.MaxEdges != tt.wantConfig.MaxEdges {
+				t.Fatalf("ParseSource() config = %+v, want %+v", got.Config, tt.wantConfig)
+			}
+			for name, want := range tt.wantConfig.Properties {
+				if string(got.Config.Properties[name]) != string(want) {
+					t.Errorf("ParseSource() property %s = %s, want %s", name, got.Config.Properties[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestSource_Read(t *testing.T) {
+	statements := []Statement{{Line: 2, Text: "a"}, {Line: 3, Text: "loop"}, {Line: 4, Text: "b"}, {Line: 5, Text: "end"}, {Line: 6, Text: "?"}}
+	readStatement := func(statement Statement) error {
+		switch statement.Text {
+		case "loop", "end":
+			return Unsupported(statement)
+		case "?":
+			return Syntax(statement.Line, "invalid statement")
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name       string
+		statements []Statement
+		wantMsg    string
+		wantErrs   int
+	}{
+		{name: "Supported statements", statements: statements[:1]},
+		{name: "Syntax error", statements: statements[4:], wantMsg: "line 6: syntax error: invalid statement", wantErrs: 1},
+		{
+			name:       "Unsupported statements are reported together",
+			statements: statements[:4],
+			wantMsg:    "line 3: unsupported syntax: loop\nline 5: unsupported syntax: end",
+			wantErrs:   2,
+		},
+		{
+			name:       "Errors after unsupported statements are left out",
+			statements: statements,
+			wantMsg:    "line 3: unsupported syntax: loop\nline 5: unsupported syntax: end",
+			wantErrs:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Source{Statements: tt.statements}).Read(readStatement)
+			if tt.wantErrs == 0 {
+				if err != nil {
+					t.Fatalf("Read() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantMsg {
+				t.Fatalf("Read() error = %v, want %q", err, tt.wantMsg)
+			}
+			if got := len(Split(err)); got != tt.wantErrs {
+				t.Errorf("len(Split()) = %d, want %d", got, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestSourceKeyword(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{name: "Header", source: "flowchart LR\n    A\n", want: "flowchart"},
+		{name: "After frontmatter and comments", source: "\n---\ntitle: x\n---\n%% comment\n\nsequenceDiagram\n", want: "sequenceDiagram"},
+		{name: "Fenced", source: "```mermaid\nerDiagram\n```\n", want: "erDiagram"},
+		{name: "No diagram", source: "%% comment\n", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SourceKeyword(tt.source); got != tt.want {
+				t.Errorf("SourceKeyword() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrimFrontmatter(t *testing.T) {
+	const rendering = "---\ntitle: Flow\nconfig:\n    theme: dark\n    maxTextSize: 50000\n    maxEdges: 500\n    fontSize: 16\n---\nflowchart TB\n"
+
+	tests := []struct {
+		name      string
+		rendering string
+		source    string
+		want      string
+	}{
+		{
+			name:      "No frontmatter",
+			rendering: "---\nconfig:\n    theme: default\n    maxTextSize: 50000\n    maxEdges: 500\n    fontSize: 16\n---\nflowchart TB\n",
+			source:    "flowchart TB\n",
+			want:      "flowchart TB\n",
+		},
+		{
+			name:      "Title only",
+			rendering: rendering,
+			source:    "---\ntitle: Flow\n---\nflowchart TB\n",
+			want:      "---\ntitle: Flow\n---\nflowchart TB\n",
+		},
+		{
+			name:      "Configuration members written",
+			rendering: rendering,
+			source:    "```mermaid\n---\ntitle: Flow\nconfig:\n  fontSize: 16\n  theme: dark\n---\nflowchart TB\n```\n",
+			want:      "---\ntitle: Flow\nconfig:\n    theme: dark\n    fontSize: 16\n---\nflowchart TB\n",
+		},
+		{
+			name:      "Diagram properties are kept",
+			rendering: "---\nconfig:\n    theme: default\n    maxTextSize: 50000\n    maxEdges: 500\n    fontSize: 16\n    flowchart:\n        curve: basis\n---\nflowchart TB\n",
+			source:    "---\nconfig:\n    flowchart:\n        curve: basis\n---\nflowchart TB\n",
+			want:      "---\nconfig:\n    flowchart:\n        curve: basis\n---\nflowchart TB\n",
+		},
+		{
+			name:      "Rendering without frontmatter",
+			rendering: "flowchart TB\n",
+			source:    "flowchart TB\n",
+			want:      "flowchart TB\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TrimFrontmatter(tt.rendering, tt.source); got != tt.want {
+				t.Errorf("TrimFrontmatter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCutKeyword(t *testing.T) {
+	tests := []struct {
+		text     string
+		keyword  string
+		wantRest string
+		wantOK   bool
+	}{
+		{text: "class A B", keyword: "class", wantRest: "A B", wantOK: true},
+		{text: "end", keyword: "end", wantOK: true},
+		{text: "classDef A", keyword: "class"},
+		{text: "A --> B", keyword: "class"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			rest, ok := CutKeyword(tt.text, tt.keyword)
+			if rest != tt.wantRest || ok != tt.wantOK {
+				t.Errorf("CutKeyword() = %q, %v, want %q, %v", rest, ok, tt.wantRest, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestHasKeyword(t *testing.T) {
+	keywords := []string{"click", "accTitle", "accDescr"}
+
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{text: "click A callback", want: true},
+		{text: "accTitle: Title", want: true},
+		{text: "accDescr {", want: true},
+		{text: "accDescr{", want: true},
+		{text: "clicked --> A"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := HasKeyword(tt.text, keywords...); got != tt.want {
+				t.Errorf("HasKeyword() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnquote(t *testing.T) {
+	for text, want := range map[string]string{`"a b"`: "a b", `a b`: "a b", `"`: `"`, `""`: ""} {
+		if got := Unquote(text); got != want {
+			t.Errorf("Unquote(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
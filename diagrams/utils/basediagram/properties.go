@@ -2,6 +2,7 @@ package basediagram
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -59,3 +60,46 @@ func (p *StringArrayProperty) Format() string {
 type DiagramProperties interface {
 	String() string
 }
+
+// CloneProperties returns a copy of a diagram property map.
+// Properties are replaced rather than mutated by their setters, so the values are shared.
+func CloneProperties(properties map[string]DiagramProperty) map[string]DiagramProperty {
+	if properties == nil {
+		return nil
+	}
+
+	cloned := make(map[string]DiagramProperty, len(properties))
+	for k, v := range properties {
+		cloned[k] = v
+	}
+
+	return cloned
+}
+
+// SortedProperties returns the properties of a diagram property map ordered by name,
+// so that configuration blocks are written in a stable order.
+func SortedProperties(properties map[string]DiagramProperty) []DiagramProperty {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sorted := make([]DiagramProperty, 0, len(names))
+	for _, name := range names {
+		sorted = append(sorted, properties[name])
+	}
+
+	return sorted
+}
+
+// PropertyValue returns the value of the named property when it is set and holds a T.
+func PropertyValue[T any](properties map[string]DiagramProperty, name string) (value T, ok bool) {
+	property, found := properties[name]
+	if !found {
+		return
+	}
+
+	value, ok = property.Value().(T)
+	return
+}
@@ -1,6 +1,7 @@
 package basediagram
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -243,3 +244,60 @@ func TestPropertyTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestCloneProperties(t *testing.T) {
+	if CloneProperties(nil) != nil {
+		t.Error("CloneProperties(nil) should return nil")
+	}
+
+	original := map[string]DiagramProperty{
+		"padding": &IntProperty{BaseProperty{Name: "padding", Val: 10}},
+	}
+
+	cloned := CloneProperties(original)
+	cloned["curve"] = &StringProperty{BaseProperty{Name: "curve", Val: "basis"}}
+
+	if len(original) != 1 {
+		t.Error("CloneProperties() should return an independent map")
+	}
+
+	if cloned["padding"].Value() != 10 {
+		t.Errorf("CloneProperties() padding = %v, want 10", cloned["padding"].Value())
+	}
+}
+
+func TestPropertyValue(t *testing.T) {
+	properties := map[string]DiagramProperty{
+		"padding": &IntProperty{BaseProperty{Name: "padding", Val: 10}},
+	}
+
+	if value, ok := PropertyValue[int](properties, "padding"); !ok || value != 10 {
+		t.Errorf("PropertyValue() = %v, %v, want 10, true", value, ok)
+	}
+
+	if _, ok := PropertyValue[string](properties, "padding"); ok {
+		t.Error("PropertyValue() should not convert between types")
+	}
+
+	if _, ok := PropertyValue[int](properties, "missing"); ok {
+		t.Error("PropertyValue() should report missing properties")
+	}
+}
+
+func TestSortedProperties(t *testing.T) {
+	properties := map[string]DiagramProperty{
+		"wrap":    &BoolProperty{BaseProperty{Name: "wrap", Val: true}},
+		"curve":   &StringProperty{BaseProperty{Name: "curve", Val: "basis"}},
+		"padding": &IntProperty{BaseProperty{Name: "padding", Val: 10}},
+	}
+
+	var values []interface{}
+	for _, property := range SortedProperties(properties) {
+		values = append(values, property.Value())
+	}
+
+	want := []interface{}{"basis", 10, true}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("SortedProperties() values = %v, want %v", values, want)
+	}
+}
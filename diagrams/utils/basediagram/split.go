@@ -0,0 +1,105 @@
+package basediagram
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SplitStrategy selects how an oversized diagram is partitioned.
+type SplitStrategy string
+
+// List of split strategies.
+const (
+	// SplitBySubgraph keeps the elements of each subgraph together.
+	SplitBySubgraph SplitStrategy = "subgraph"
+	// SplitByComponent keeps the elements of each connected component together.
+	SplitByComponent SplitStrategy = "component"
+	// SplitByCommunity keeps densely connected elements together.
+	SplitByCommunity SplitStrategy = "community"
+)
+
+const (
+	// SplitStubText is the label of stub elements that stand in for elements of another part.
+	SplitStubText string = "continued in diagram %d"
+	// SplitTitleString is the title of a part of a titled diagram.
+	SplitTitleString string = "%s (%d/%d)"
+
+	splitPartString      string = "Diagram %d: %d elements, %d edges, %d characters"
+	splitContinuesString string = ", continued in %s"
+	splitPartNumber      string = "%d"
+)
+
+// SplitOptions controls how an oversized diagram is split.
+// Zero limits are taken from the diagram configuration.
+type SplitOptions struct {
+	Strategy    SplitStrategy
+	MaxEdges    int
+	MaxTextSize int
+}
+
+// Limits returns the limits of the options, falling back to the given configuration.
+func (o SplitOptions) Limits(config ConfigurationProperties) (maxEdges int, maxTextSize int) {
+	maxEdges, maxTextSize = o.MaxEdges, o.MaxTextSize
+
+	if maxEdges <= 0 {
+		maxEdges = config.MaxEdges()
+	}
+	if maxTextSize <= 0 {
+		maxTextSize = config.MaxTextSize()
+	}
+
+	return
+}
+
+// SplitPart describes one of the diagrams produced by a split.
+type SplitPart struct {
+	// Number is the 1-based number of the part, used in stub labels.
+	Number int
+	// Elements lists the IDs of the elements placed in the part, excluding stubs.
+	Elements []string
+	// Edges is the number of edges in the part, including edges to stubs.
+	Edges int
+	// TextSize is the length of the rendered part.
+	TextSize int
+	// Continues lists the numbers of the parts that edges cut from this part lead to.
+	Continues []int
+}
+
+// SplitIndex lists the parts of a split diagram.
+type SplitIndex struct {
+	Parts []SplitPart
+}
+
+// PartOf returns the number of the part containing the element, or 0 if there is none.
+func (i *SplitIndex) PartOf(id string) int {
+	for _, part := range i.Parts {
+		for _, element := range part.Elements {
+			if element == id {
+				return part.Number
+			}
+		}
+	}
+
+	return 0
+}
+
+// String returns one line per part describing its size and the parts it continues in.
+func (i *SplitIndex) String() string {
+	var sb strings.Builder
+
+	for _, part := range i.Parts {
+		sb.WriteString(fmt.Sprintf(splitPartString, part.Number, len(part.Elements), part.Edges, part.TextSize))
+
+		if len(part.Continues) > 0 {
+			numbers := make([]string, 0, len(part.Continues))
+			for _, number := range part.Continues {
+				numbers = append(numbers, fmt.Sprintf(splitPartNumber, number))
+			}
+			sb.WriteString(fmt.Sprintf(splitContinuesString, strings.Join(numbers, ", ")))
+		}
+
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
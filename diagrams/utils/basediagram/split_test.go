@@ -0,0 +1,44 @@
+package basediagram
+
+import "testing"
+
+func TestSplitOptions_Limits(t *testing.T) {
+	config := NewConfigurationProperties()
+	config.SetMaxEdges(20)
+
+	tests := []struct {
+		name         string
+		options      SplitOptions
+		wantEdges    int
+		wantTextSize int
+	}{
+		{name: "Configuration limits", options: SplitOptions{}, wantEdges: 20, wantTextSize: 50000},
+		{name: "Explicit limits", options: SplitOptions{MaxEdges: 5, MaxTextSize: 100}, wantEdges: 5, wantTextSize: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edges, textSize := tt.options.Limits(config)
+			if edges != tt.wantEdges || textSize != tt.wantTextSize {
+				t.Errorf("Limits() = %d, %d, want %d, %d", edges, textSize, tt.wantEdges, tt.wantTextSize)
+			}
+		})
+	}
+}
+
+func TestSplitIndex(t *testing.T) {
+	index := &SplitIndex{Parts: []SplitPart{
+		{Number: 1, Elements: []string{"a", "b"}, Edges: 2, TextSize: 100, Continues: []int{2, 3}},
+		{Number: 2, Elements: []string{"c"}, Edges: 1, TextSize: 50},
+	}}
+
+	if index.PartOf("c") != 2 || index.PartOf("missing") != 0 {
+		t.Errorf("PartOf() = %d, %d", index.PartOf("c"), index.PartOf("missing"))
+	}
+
+	want := "Diagram 1: 2 elements, 2 edges, 100 characters, continued in 2, 3\n" +
+		"Diagram 2: 1 elements, 1 edges, 50 characters\n"
+	if got := index.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
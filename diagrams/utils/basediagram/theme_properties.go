@@ -2,6 +2,7 @@ package basediagram
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -193,6 +194,21 @@ func (t *Theme) SetErrorTextColor(v string) *Theme {
 	return t
 }
 
+// Clone returns a copy of the theme that does not share its variables map.
+func (t Theme) Clone() Theme {
+	if t.Variables == nil {
+		return t
+	}
+
+	variables := make(map[string]interface{}, len(t.Variables))
+	for k, v := range t.Variables {
+		variables[k] = v
+	}
+	t.Variables = variables
+
+	return t
+}
+
 func (t *Theme) String() string {
 	if len(t.Variables) == 0 {
 		return fmt.Sprintf(baseThemeString, t.Name)
@@ -202,8 +218,14 @@ func (t *Theme) String() string {
 	sb.WriteString(fmt.Sprintf(baseThemeString, t.Name))
 	sb.WriteString(Indentation + "themeVariables:\n")
 
-	for k, v := range t.Variables {
-		sb.WriteString(fmt.Sprintf(themeVariableString, k, v))
+	names := make([]string, 0, len(t.Variables))
+	for name := range t.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf(themeVariableString, name, t.Variables[name]))
 	}
 
 	return sb.String()
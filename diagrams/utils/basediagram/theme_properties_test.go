@@ -87,6 +87,18 @@ func TestTheme_String(t *testing.T) {
 	}
 }
 
+func TestTheme_StringOrder(t *testing.T) {
+	theme := NewTheme()
+	theme.SetTextColor("#333333").SetBackground("#FFFFFF").SetLineColor("#000000")
+
+	want := "    theme: default\n    themeVariables:\n        background: #FFFFFF\n        lineColor: #000000\n        textColor: #333333\n"
+	for i := 0; i < 10; i++ {
+		if got := theme.String(); got != want {
+			t.Fatalf("String() = %q, want %q", got, want)
+		}
+	}
+}
+
 func TestTheme_Setters(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -263,3 +275,24 @@ func TestTheme_Setters(t *testing.T) {
 		})
 	}
 }
+
+func TestTheme_Clone(t *testing.T) {
+	theme := NewTheme()
+	theme.SetPrimaryColor("#fff")
+
+	cloned := theme.Clone()
+	cloned.SetPrimaryColor("#000")
+
+	if theme.Variables[ThemeVarPrimaryColor] != "#fff" {
+		t.Error("Clone() should not share variables with the original theme")
+	}
+
+	if cloned.Name != theme.Name {
+		t.Errorf("Clone() Name = %v, want %v", cloned.Name, theme.Name)
+	}
+
+	empty := Theme{Name: ThemeDark}
+	if got := empty.Clone(); got.Variables != nil || got.Name != ThemeDark {
+		t.Errorf("Clone() of theme without variables = %v", got)
+	}
+}
@@ -0,0 +1,494 @@
+// Package graph provides directed graph algorithms shared by the diagram packages.
+// Results are deterministic: nodes are always visited in insertion order.
+package graph
+
+import "errors"
+
+// ErrCycle is returned when an operation requires an acyclic graph.
+var ErrCycle = errors.New("graph contains a cycle")
+
+// Graph is a directed graph over comparable node values.
+// Parallel edges and self-loops are allowed.
+type Graph[T comparable] struct {
+	nodes []T
+	index map[T]int
+	out   [][]int
+	in    [][]int
+}
+
+// New creates an empty graph.
+func New[T comparable]() *Graph[T] {
+	return &Graph[T]{
+		nodes: make([]T, 0),
+		index: make(map[T]int),
+	}
+}
+
+// AddNode adds the node to the graph if it is not already present.
+func (g *Graph[T]) AddNode(node T) {
+	g.indexOf(node)
+}
+
+// AddEdge adds an edge from one node to another, adding missing nodes.
+func (g *Graph[T]) AddEdge(from T, to T) {
+	fromIndex := g.indexOf(from)
+	toIndex := g.indexOf(to)
+
+	g.out[fromIndex] = append(g.out[fromIndex], toIndex)
+	g.in[toIndex] = append(g.in[toIndex], fromIndex)
+}
+
+// Nodes returns the nodes of the graph in insertion order.
+func (g *Graph[T]) Nodes() []T {
+	return append([]T(nil), g.nodes...)
+}
+
+// Has reports whether the node is part of the graph.
+func (g *Graph[T]) Has(node T) bool {
+	_, ok := g.index[node]
+	return ok
+}
+
+// Successors returns the distinct nodes with an edge from node.
+func (g *Graph[T]) Successors(node T) []T {
+	index, ok := g.index[node]
+	if !ok {
+		return nil
+	}
+
+	return g.values(distinct(g.out[index]))
+}
+
+// Predecessors returns the distinct nodes with an edge to node.
+func (g *Graph[T]) Predecessors(node T) []T {
+	index, ok := g.index[node]
+	if !ok {
+		return nil
+	}
+
+	return g.values(distinct(g.in[index]))
+}
+
+// Downstream returns the nodes reachable from start by following edges forward,
+// excluding start unless it lies on a cycle. A negative maxDepth means no limit.
+func (g *Graph[T]) Downstream(start T, maxDepth int) []T {
+	return g.search(start, maxDepth, g.out)
+}
+
+// Upstream returns the nodes from which target is reachable,
+// excluding target unless it lies on a cycle. A negative maxDepth means no limit.
+func (g *Graph[T]) Upstream(target T, maxDepth int) []T {
+	return g.search(target, maxDepth, g.in)
+}
+
+// Neighborhood returns start and every node within hops edges of it, ignoring edge direction.
+func (g *Graph[T]) Neighborhood(start T, hops int) []T {
+	if !g.Has(start) {
+		return nil
+	}
+
+	return append([]T{start}, g.search(start, hops, g.out, g.in)...)
+}
+
+// Reachable reports whether to can be reached from from.
+func (g *Graph[T]) Reachable(from T, to T) bool {
+	return g.ShortestPath(from, to) != nil
+}
+
+// ShortestPath returns the nodes of a path with the fewest edges from one node to another,
+// including both ends, or nil if there is none.
+func (g *Graph[T]) ShortestPath(from T, to T) []T {
+	fromIndex, ok := g.index[from]
+	if !ok {
+		return nil
+	}
+	toIndex, ok := g.index[to]
+	if !ok {
+		return nil
+	}
+
+	if fromIndex == toIndex {
+		return []T{from}
+	}
+
+	parent := make([]int, len(g.nodes))
+	for i := range parent {
+		parent[i] = -1
+	}
+	parent[fromIndex] = fromIndex
+
+	queue := []int{fromIndex}
+	for len(queue) > 0 && parent[toIndex] < 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, next := range g.out[current] {
+			if parent[next] < 0 {
+				parent[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	if parent[toIndex] < 0 {
+		return nil
+	}
+
+	path := []int{toIndex}
+	for current := toIndex; current != fromIndex; current = parent[current] {
+		path = append(path, parent[current])
+	}
+
+	reverse(path)
+
+	return g.values(path)
+}
+
+// HasCycle reports whether the graph contains a cycle, including self-loops.
+func (g *Graph[T]) HasCycle() bool {
+	return g.FindCycle() != nil
+}
+
+// FindCycle returns the nodes of one cycle in edge order, or nil if the graph is acyclic.
+// A self-loop is returned as a single node.
+func (g *Graph[T]) FindCycle() []T {
+	const (
+		unvisited = iota
+		active
+		done
+	)
+
+	state := make([]int, len(g.nodes))
+	stack := make([]int, 0)
+
+	var visit func(index int) []int
+	visit = func(index int) []int {
+		state[index] = active
+		stack = append(stack, index)
+
+		for _, next := range g.out[index] {
+			switch state[next] {
+			case active:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == next {
+						return append([]int(nil), stack[i:]...)
+					}
+				}
+			case unvisited:
+				if cycle := visit(next); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[index] = done
+		return nil
+	}
+
+	for index := range g.nodes {
+		if state[index] == unvisited {
+			if cycle := visit(index); cycle != nil {
+				return g.values(cycle)
+			}
+		}
+	}
+
+	return nil
+}
+
+// TopologicalSort returns the nodes ordered so that every edge points forward.
+// Ties are broken by insertion order. It returns ErrCycle if the graph has a cycle.
+func (g *Graph[T]) TopologicalSort() ([]T, error) {
+	inDegree := make([]int, len(g.nodes))
+	for _, targets := range g.out {
+		for _, target := range targets {
+			inDegree[target]++
+		}
+	}
+
+	ready := make([]int, 0)
+	for index, degree := range inDegree {
+		if degree == 0 {
+			ready = append(ready, index)
+		}
+	}
+
+	order := make([]int, 0, len(g.nodes))
+	for len(ready) > 0 {
+		current := ready[0]
+		ready = ready[1:]
+		order = append(order, current)
+
+		for _, next := range g.out[current] {
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				ready = insertSorted(ready, next)
+			}
+		}
+	}
+
+	if len(order) != len(g.nodes) {
+		return nil, ErrCycle
+	}
+
+	return g.values(order), nil
+}
+
+// StronglyConnectedComponents returns the strongly connected components of the graph.
+// Components are ordered by their first node and list their nodes in insertion order.
+func (g *Graph[T]) StronglyConnectedComponents() [][]T {
+	index := 0
+	indices := make([]int, len(g.nodes))
+	lowLinks := make([]int, len(g.nodes))
+	onStack := make([]bool, len(g.nodes))
+	stack := make([]int, 0)
+	component := make([]int, len(g.nodes))
+	components := 0
+
+	for i := range indices {
+		indices[i] = -1
+	}
+
+	var connect func(node int)
+	connect = func(node int) {
+		indices[node] = index
+		lowLinks[node] = index
+		index++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, next := range g.out[node] {
+			if indices[next] < 0 {
+				connect(next)
+				if lowLinks[next] < lowLinks[node] {
+					lowLinks[node] = lowLinks[next]
+				}
+			} else if onStack[next] && indices[next] < lowLinks[node] {
+				lowLinks[node] = indices[next]
+			}
+		}
+
+		if lowLinks[node] == indices[node] {
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component[top] = components
+				if top == node {
+					break
+				}
+			}
+			components++
+		}
+	}
+
+	for node := range g.nodes {
+		if indices[node] < 0 {
+			connect(node)
+		}
+	}
+
+	return g.groups(component, components)
+}
+
+// WeaklyConnectedComponents returns the components of the graph when edge direction is ignored.
+// Components are ordered by their first node and list their nodes in insertion order.
+func (g *Graph[T]) WeaklyConnectedComponents() [][]T {
+	component := make([]int, len(g.nodes))
+	for i := range component {
+		component[i] = -1
+	}
+
+	components := 0
+	for start := range g.nodes {
+		if component[start] >= 0 {
+			continue
+		}
+
+		component[start] = components
+		queue := []int{start}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+
+			for _, edges := range [][]int{g.out[current], g.in[current]} {
+				for _, next := range edges {
+					if component[next] < 0 {
+						component[next] = components
+						queue = append(queue, next)
+					}
+				}
+			}
+		}
+		components++
+	}
+
+	return g.groups(component, components)
+}
+
+// Subgraph returns the graph induced by the given nodes, keeping the insertion order of g.
+func (g *Graph[T]) Subgraph(nodes []T) *Graph[T] {
+	keep := make([]bool, len(g.nodes))
+	for _, node := range nodes {
+		if index, ok := g.index[node]; ok {
+			keep[index] = true
+		}
+	}
+
+	sub := New[T]()
+	for index, node := range g.nodes {
+		if keep[index] {
+			sub.AddNode(node)
+		}
+	}
+
+	for index, targets := range g.out {
+		if !keep[index] {
+			continue
+		}
+		for _, target := range targets {
+			if keep[target] {
+				sub.AddEdge(g.nodes[index], g.nodes[target])
+			}
+		}
+	}
+
+	return sub
+}
+
+// indexOf returns the index of the node, adding it when missing.
+func (g *Graph[T]) indexOf(node T) int {
+	if index, ok := g.index[node]; ok {
+		return index
+	}
+
+	index := len(g.nodes)
+	g.index[node] = index
+	g.nodes = append(g.nodes, node)
+	g.out = append(g.out, nil)
+	g.in = append(g.in, nil)
+
+	return index
+}
+
+// search walks the graph breadth-first from start along the given adjacency lists
+// and returns the visited nodes in insertion order, excluding start unless it is revisited.
+func (g *Graph[T]) search(start T, maxDepth int, adjacency ...[][]int) []T {
+	startIndex, ok := g.index[start]
+	if !ok {
+		return nil
+	}
+
+	depth := make([]int, len(g.nodes))
+	for i := range depth {
+		depth[i] = -1
+	}
+
+	visited := make([]bool, len(g.nodes))
+	queue := []int{startIndex}
+	depth[startIndex] = 0
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if maxDepth >= 0 && depth[current] >= maxDepth {
+			continue
+		}
+
+		for _, edges := range adjacency {
+			for _, next := range edges[current] {
+				if visited[next] {
+					continue
+				}
+				visited[next] = true
+				if depth[next] < 0 {
+					depth[next] = depth[current] + 1
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+
+	if len(adjacency) > 1 {
+		visited[startIndex] = false
+	}
+
+	result := make([]int, 0)
+	for index, ok := range visited {
+		if ok {
+			result = append(result, index)
+		}
+	}
+
+	return g.values(result)
+}
+
+// groups returns the nodes grouped by their component number, ordered by first node.
+func (g *Graph[T]) groups(component []int, count int) [][]T {
+	order := make([]int, count)
+	for i := range order {
+		order[i] = -1
+	}
+
+	groups := make([][]T, 0, count)
+	for index, c := range component {
+		if order[c] < 0 {
+			order[c] = len(groups)
+			groups = append(groups, nil)
+		}
+		groups[order[c]] = append(groups[order[c]], g.nodes[index])
+	}
+
+	return groups
+}
+
+// values returns the nodes at the given indices.
+func (g *Graph[T]) values(indices []int) []T {
+	values := make([]T, 0, len(indices))
+	for _, index := range indices {
+		values = append(values, g.nodes[index])
+	}
+
+	return values
+}
+
+// distinct returns the indices without duplicates, keeping the first occurrence.
+func distinct(indices []int) []int {
+	seen := make(map[int]bool, len(indices))
+	result := make([]int, 0, len(indices))
+
+	for _, index := range indices {
+		if !seen[index] {
+			seen[index] = true
+			result = append(result, index)
+		}
+	}
+
+	return result
+}
+
+// insertSorted inserts value into the sorted slice.
+func insertSorted(values []int, value int) []int {
+	position := len(values)
+	for i, current := range values {
+		if value < current {
+			position = i
+			break
+		}
+	}
+
+	values = append(values, 0)
+	copy(values[position+1:], values[position:])
+	values[position] = value
+
+	return values
+}
+
+// reverse reverses the slice in place.
+func reverse(values []int) {
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+}
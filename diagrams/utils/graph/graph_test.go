@@ -0,0 +1,182 @@
+package graph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// newTestGraph builds a graph from "from->to" pairs.
+func newTestGraph(nodes []string, edges [][2]string) *Graph[string] {
+	g := New[string]()
+	for _, node := range nodes {
+		g.AddNode(node)
+	}
+	for _, edge := range edges {
+		g.AddEdge(edge[0], edge[1])
+	}
+	return g
+}
+
+func TestGraph_SuccessorsPredecessors(t *testing.T) {
+	g := newTestGraph(nil, [][2]string{{"a", "b"}, {"a", "c"}, {"a", "b"}, {"c", "b"}})
+
+	if got := g.Successors("a"); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Errorf("Successors() = %v", got)
+	}
+
+	if got := g.Predecessors("b"); !reflect.DeepEqual(got, []string{"a", "c"}) {
+		t.Errorf("Predecessors() = %v", got)
+	}
+
+	if got := g.Successors("missing"); got != nil {
+		t.Errorf("Successors() of missing node = %v, want nil", got)
+	}
+
+	if !reflect.DeepEqual(g.Nodes(), []string{"a", "b", "c"}) || !g.Has("c") || g.Has("d") {
+		t.Errorf("Nodes() = %v", g.Nodes())
+	}
+}
+
+func TestGraph_Search(t *testing.T) {
+	g := newTestGraph([]string{"x"}, [][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"e", "c"}})
+
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{name: "Downstream", got: g.Downstream("a", -1), want: []string{"b", "c", "d"}},
+		{name: "Downstream limited", got: g.Downstream("a", 1), want: []string{"b"}},
+		{name: "Upstream", got: g.Upstream("c", -1), want: []string{"a", "b", "e"}},
+		{name: "Neighborhood", got: g.Neighborhood("c", 1), want: []string{"c", "b", "d", "e"}},
+		{name: "Neighborhood two hops", got: g.Neighborhood("d", 2), want: []string{"d", "b", "c", "e"}},
+		{name: "Isolated", got: g.Downstream("x", -1), want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraph_DownstreamCycle(t *testing.T) {
+	g := newTestGraph(nil, [][2]string{{"a", "b"}, {"b", "a"}})
+
+	if got := g.Downstream("a", -1); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Downstream() = %v, want start included on a cycle", got)
+	}
+}
+
+func TestGraph_ShortestPath(t *testing.T) {
+	g := newTestGraph([]string{"z"}, [][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"a", "d"}})
+
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want []string
+	}{
+		{name: "Direct edge", from: "a", to: "d", want: []string{"a", "d"}},
+		{name: "Multiple hops", from: "b", to: "d", want: []string{"b", "c", "d"}},
+		{name: "Same node", from: "c", to: "c", want: []string{"c"}},
+		{name: "Unreachable", from: "d", to: "a", want: nil},
+		{name: "Missing node", from: "a", to: "q", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := g.ShortestPath(tt.from, tt.to)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ShortestPath() = %v, want %v", got, tt.want)
+			}
+			if g.Reachable(tt.from, tt.to) != (tt.want != nil) {
+				t.Errorf("Reachable() = %v", g.Reachable(tt.from, tt.to))
+			}
+		})
+	}
+}
+
+func TestGraph_Cycles(t *testing.T) {
+	tests := []struct {
+		name      string
+		edges     [][2]string
+		wantCycle []string
+	}{
+		{name: "Acyclic", edges: [][2]string{{"a", "b"}, {"b", "c"}, {"a", "c"}}},
+		{name: "Cycle", edges: [][2]string{{"a", "b"}, {"b", "c"}, {"c", "b"}}, wantCycle: []string{"b", "c"}},
+		{name: "Self-loop", edges: [][2]string{{"a", "a"}}, wantCycle: []string{"a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGraph(nil, tt.edges)
+
+			if got := g.FindCycle(); !reflect.DeepEqual(got, tt.wantCycle) {
+				t.Errorf("FindCycle() = %v, want %v", got, tt.wantCycle)
+			}
+
+			if g.HasCycle() != (tt.wantCycle != nil) {
+				t.Errorf("HasCycle() = %v", g.HasCycle())
+			}
+
+			_, err := g.TopologicalSort()
+			if (err != nil) != (tt.wantCycle != nil) || (err != nil && !errors.Is(err, ErrCycle)) {
+				t.Errorf("TopologicalSort() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestGraph_TopologicalSort(t *testing.T) {
+	g := newTestGraph([]string{"shoes", "socks", "pants", "belt"}, [][2]string{
+		{"socks", "shoes"}, {"pants", "shoes"}, {"pants", "belt"},
+	})
+
+	got, err := g.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort() error = %v", err)
+	}
+
+	want := []string{"socks", "pants", "shoes", "belt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TopologicalSort() = %v, want %v", got, want)
+	}
+}
+
+func TestGraph_Components(t *testing.T) {
+	g := newTestGraph([]string{"solo"}, [][2]string{
+		{"a", "b"}, {"b", "c"}, {"c", "a"}, {"c", "d"}, {"e", "f"},
+	})
+
+	wantStrong := [][]string{{"solo"}, {"a", "b", "c"}, {"d"}, {"e"}, {"f"}}
+	if got := g.StronglyConnectedComponents(); !reflect.DeepEqual(got, wantStrong) {
+		t.Errorf("StronglyConnectedComponents() = %v, want %v", got, wantStrong)
+	}
+
+	wantWeak := [][]string{{"solo"}, {"a", "b", "c", "d"}, {"e", "f"}}
+	if got := g.WeaklyConnectedComponents(); !reflect.DeepEqual(got, wantWeak) {
+		t.Errorf("WeaklyConnectedComponents() = %v, want %v", got, wantWeak)
+	}
+}
+
+func TestGraph_Subgraph(t *testing.T) {
+	g := newTestGraph(nil, [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}})
+
+	sub := g.Subgraph([]string{"c", "a", "missing"})
+
+	if !reflect.DeepEqual(sub.Nodes(), []string{"a", "c"}) {
+		t.Errorf("Subgraph() nodes = %v", sub.Nodes())
+	}
+
+	if got := sub.Successors("c"); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("Subgraph() successors = %v", got)
+	}
+
+	if got := sub.Successors("a"); len(got) != 0 {
+		t.Errorf("Subgraph() should drop edges to excluded nodes, got %v", got)
+	}
+}
@@ -0,0 +1,166 @@
+package graph
+
+// maxCommunityRounds bounds the number of passes made while detecting communities.
+const maxCommunityRounds = 100
+
+// Communities detects densely connected groups of nodes by moving nodes between
+// neighbouring groups while that increases modularity (the local moving phase of the
+// Louvain method), ignoring edge direction. Nodes are visited in insertion order and
+// ties keep the current group, so the result is deterministic. Communities are ordered
+// by their first node and list their nodes in insertion order.
+func (g *Graph[T]) Communities() [][]T {
+	degree := make([]float64, len(g.nodes))
+	total := 0.0
+	for node := range g.nodes {
+		for _, edges := range [][]int{g.out[node], g.in[node]} {
+			for _, neighbor := range edges {
+				if neighbor != node {
+					degree[node]++
+					total++
+				}
+			}
+		}
+	}
+
+	community := make([]int, len(g.nodes))
+	communityDegree := make([]float64, len(g.nodes))
+	for node := range g.nodes {
+		community[node] = node
+		communityDegree[node] = degree[node]
+	}
+
+	for round := 0; round < maxCommunityRounds && total > 0; round++ {
+		moved := false
+
+		for node := range g.nodes {
+			links := make(map[int]float64)
+			for _, edges := range [][]int{g.out[node], g.in[node]} {
+				for _, neighbor := range edges {
+					if neighbor != node {
+						links[community[neighbor]]++
+					}
+				}
+			}
+
+			current := community[node]
+			communityDegree[current] -= degree[node]
+
+			gain := func(c int) float64 {
+				return links[c] - communityDegree[c]*degree[node]/total
+			}
+
+			best, bestGain := current, gain(current)
+			for c := range links {
+				if candidate := gain(c); candidate > bestGain || (candidate == bestGain && best != current && c < best) {
+					best, bestGain = c, candidate
+				}
+			}
+
+			community[node] = best
+			communityDegree[best] += degree[node]
+			if best != current {
+				moved = true
+			}
+		}
+
+		if !moved {
+			break
+		}
+	}
+
+	numbers := make(map[int]int)
+	groups := make([]int, len(g.nodes))
+	for node, c := range community {
+		if _, ok := numbers[c]; !ok {
+			numbers[c] = len(numbers)
+		}
+		groups[node] = numbers[c]
+	}
+
+	return g.groups(groups, len(numbers))
+}
+
+// Pack distributes groups of nodes over as few consecutive parts as possible, keeping each
+// part accepted by fits. Groups are never split unless they do not fit on their own, in
+// which case they are divided into communities and, as a last resort, into runs of nodes
+// in breadth-first order. A single node is always accepted.
+func (g *Graph[T]) Pack(groups [][]T, fits func(nodes []T) bool) [][]T {
+	parts := make([][]T, 0)
+	current := make([]T, 0)
+
+	for _, group := range groups {
+		for _, piece := range g.divide(group, fits) {
+			candidate := append(append(make([]T, 0, len(current)+len(piece)), current...), piece...)
+			if len(current) > 0 && !fits(candidate) {
+				parts = append(parts, current)
+				current = piece
+			} else {
+				current = candidate
+			}
+		}
+	}
+
+	if len(current) > 0 {
+		parts = append(parts, current)
+	}
+
+	return parts
+}
+
+// divide returns the group as a single piece if it fits, otherwise as pieces that do.
+func (g *Graph[T]) divide(group []T, fits func(nodes []T) bool) [][]T {
+	if len(group) <= 1 || fits(group) {
+		return [][]T{group}
+	}
+
+	sub := g.Subgraph(group)
+	if communities := sub.Communities(); len(communities) > 1 {
+		return sub.Pack(communities, fits)
+	}
+
+	pieces := make([][]T, 0)
+	current := make([]T, 0)
+	for _, node := range sub.breadthFirstOrder() {
+		candidate := append(append(make([]T, 0, len(current)+1), current...), node)
+		if len(current) > 0 && !fits(candidate) {
+			pieces = append(pieces, current)
+			current = []T{node}
+		} else {
+			current = candidate
+		}
+	}
+
+	return append(pieces, current)
+}
+
+// breadthFirstOrder returns every node of the graph, visiting the nodes reachable from each
+// unvisited node in insertion order breadth-first and ignoring edge direction.
+func (g *Graph[T]) breadthFirstOrder() []T {
+	visited := make([]bool, len(g.nodes))
+	order := make([]int, 0, len(g.nodes))
+
+	for start := range g.nodes {
+		if visited[start] {
+			continue
+		}
+
+		visited[start] = true
+		queue := []int{start}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			order = append(order, current)
+
+			for _, edges := range [][]int{g.out[current], g.in[current]} {
+				for _, next := range edges {
+					if !visited[next] {
+						visited[next] = true
+						queue = append(queue, next)
+					}
+				}
+			}
+		}
+	}
+
+	return g.values(order)
+}
@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraph_Communities(t *testing.T) {
+	g := newTestGraph(nil, [][2]string{
+		{"a", "b"}, {"b", "c"}, {"c", "a"},
+		{"d", "e"}, {"e", "f"}, {"f", "d"},
+		{"c", "d"},
+	})
+
+	want := [][]string{{"a", "b", "c"}, {"d", "e", "f"}}
+	if got := g.Communities(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Communities() = %v, want %v", got, want)
+	}
+}
+
+func TestGraph_Pack(t *testing.T) {
+	g := newTestGraph(nil, [][2]string{
+		{"a", "b"}, {"b", "c"}, {"c", "a"},
+		{"d", "e"}, {"e", "f"}, {"f", "d"},
+		{"c", "d"}, {"x", "y"},
+	})
+
+	maxSize := func(size int) func([]string) bool {
+		return func(nodes []string) bool { return len(nodes) <= size }
+	}
+
+	tests := []struct {
+		name   string
+		groups [][]string
+		fits   func([]string) bool
+		want   [][]string
+	}{
+		{
+			name:   "Groups packed together",
+			groups: [][]string{{"a", "b", "c", "d", "e", "f"}, {"x", "y"}},
+			fits:   maxSize(8),
+			want:   [][]string{{"a", "b", "c", "d", "e", "f", "x", "y"}},
+		},
+		{
+			name:   "Groups kept apart",
+			groups: [][]string{{"a", "b", "c", "d", "e", "f"}, {"x", "y"}},
+			fits:   maxSize(6),
+			want:   [][]string{{"a", "b", "c", "d", "e", "f"}, {"x", "y"}},
+		},
+		{
+			name:   "Oversized group divided into communities",
+			groups: [][]string{{"a", "b", "c", "d", "e", "f"}, {"x", "y"}},
+			fits:   maxSize(3),
+			want:   [][]string{{"a", "b", "c"}, {"d", "e", "f"}, {"x", "y"}},
+		},
+		{
+			name:   "Community divided into runs",
+			groups: [][]string{{"a", "b", "c"}},
+			fits:   maxSize(2),
+			want:   [][]string{{"a", "b"}, {"c"}},
+		},
+		{
+			name:   "Single nodes always fit",
+			groups: [][]string{{"a"}, {"b"}},
+			fits:   maxSize(0),
+			want:   [][]string{{"a"}, {"b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.Pack(tt.groups, tt.fits); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Pack() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package utils
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+)
 
 // IDGenerator defines the interface for generating unique IDs
 type IDGenerator interface {
@@ -29,3 +32,60 @@ func (g *DefaultIDGenerator) Reset() *DefaultIDGenerator {
 	g.nextID = 0
 	return g
 }
+
+// Skip advances the generator past the given numeric IDs so that it does not generate
+// them again. IDs that are not numbers are ignored.
+func (g *DefaultIDGenerator) Skip(ids ...string) *DefaultIDGenerator {
+	for _, id := range ids {
+		if n, err := strconv.Atoi(id); err == nil && n >= g.nextID {
+			g.nextID = n + 1
+		}
+	}
+	return g
+}
+
+// Clone returns a new generator that continues from the current state.
+func (g *DefaultIDGenerator) Clone() IDGenerator {
+	return &DefaultIDGenerator{nextID: g.nextID}
+}
+
+// CloneIDGenerator returns an independent copy of the generator when it provides
+// a Clone method, or the generator itself otherwise.
+func CloneIDGenerator(g IDGenerator) IDGenerator {
+	if cloner, ok := g.(interface{ Clone() IDGenerator }); ok {
+		return cloner.Clone()
+	}
+
+	return g
+}
+
+// SkipIDs advances the generator past the given IDs when it is a DefaultIDGenerator.
+// Other generators are left unchanged.
+func SkipIDs(g IDGenerator, ids ...string) {
+	if g, ok := g.(*DefaultIDGenerator); ok {
+		g.Skip(ids...)
+	}
+}
+
+// NextFreeID draws IDs from the generator until it returns one that is not taken.
+func NextFreeID(g IDGenerator, taken func(id string) bool) string {
+	for {
+		if id := g.NextID(); !taken(id) {
+			return id
+		}
+	}
+}
+
+// UniqueName returns name, or name with the smallest numeric suffix ("name_2", "name_3", ...)
+// that is not taken.
+func UniqueName(name string, taken func(name string) bool) string {
+	if !taken(name) {
+		return name
+	}
+
+	for i := 2; ; i++ {
+		if candidate := fmt.Sprintf("%s_%d", name, i); !taken(candidate) {
+			return candidate
+		}
+	}
+}
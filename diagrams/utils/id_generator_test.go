@@ -124,3 +124,110 @@ func TestDefaultIDGenerator_Reset(t *testing.T) {
 func TestIDGenerator_Interface(t *testing.T) {
 	var _ IDGenerator = (*DefaultIDGenerator)(nil)
 }
+
+func TestDefaultIDGenerator_Clone(t *testing.T) {
+	g := NewIDGenerator()
+	g.NextID()
+
+	cloned := g.Clone()
+	if got := cloned.NextID(); got != "1" {
+		t.Errorf("Clone().NextID() = %v, want 1", got)
+	}
+
+	if got := g.NextID(); got != "1" {
+		t.Errorf("original NextID() after clone = %v, want 1", got)
+	}
+}
+
+func TestDefaultIDGenerator_Skip(t *testing.T) {
+	tests := []struct {
+		name string
+		ids  []string
+		want string
+	}{
+		{name: "No IDs", want: "0"},
+		{name: "Numeric IDs", ids: []string{"3", "1"}, want: "4"},
+		{name: "IDs already generated", ids: []string{"-1"}, want: "0"},
+		{name: "Non-numeric IDs", ids: []string{"start", "7a"}, want: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewIDGenerator().Skip(tt.ids...).NextID(); got != tt.want {
+				t.Errorf("Skip(%v).NextID() = %v, want %v", tt.ids, got, tt.want)
+			}
+		})
+	}
+}
+
+type staticIDGenerator struct{}
+
+func (staticIDGenerator) NextID() string { return "static" }
+
+func TestCloneIDGenerator(t *testing.T) {
+	g := NewIDGenerator()
+	if CloneIDGenerator(g) == IDGenerator(g) {
+		t.Error("CloneIDGenerator() should copy a DefaultIDGenerator")
+	}
+
+	static := staticIDGenerator{}
+	if CloneIDGenerator(static) != IDGenerator(static) {
+		t.Error("CloneIDGenerator() should return generators without Clone unchanged")
+	}
+}
+
+func TestSkipIDs(t *testing.T) {
+	g := NewIDGenerator()
+	SkipIDs(g, "2")
+	if got := g.NextID(); got != "3" {
+		t.Errorf("SkipIDs(\"2\") then NextID() = %v, want 3", got)
+	}
+
+	SkipIDs(staticIDGenerator{}, "2")
+}
+
+func TestNextFreeID(t *testing.T) {
+	taken := map[string]bool{"0": true, "1": true, "3": true}
+
+	got := NextFreeID(NewIDGenerator(), func(id string) bool { return taken[id] })
+	if got != "2" {
+		t.Errorf("NextFreeID() = %v, want 2", got)
+	}
+}
+
+func TestUniqueName(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		taken map[string]bool
+		want  string
+	}{
+		{
+			name:  "Free name",
+			input: "ORDER",
+			taken: map[string]bool{},
+			want:  "ORDER",
+		},
+		{
+			name:  "Taken name",
+			input: "ORDER",
+			taken: map[string]bool{"ORDER": true},
+			want:  "ORDER_2",
+		},
+		{
+			name:  "Taken suffixes",
+			input: "ORDER",
+			taken: map[string]bool{"ORDER": true, "ORDER_2": true},
+			want:  "ORDER_3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UniqueName(tt.input, func(name string) bool { return tt.taken[name] })
+			if got != tt.want {
+				t.Errorf("UniqueName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package utils
+
+// RemoveItem removes the first occurrence of item from items.
+// It returns the resulting slice and whether the item was found.
+func RemoveItem[T comparable](items []T, item T) ([]T, bool) {
+	for i, current := range items {
+		if current == item {
+			return append(items[:i], items[i+1:]...), true
+		}
+	}
+
+	return items, false
+}
+
+// ReplaceItem replaces the first occurrence of oldItem in items with newItem.
+// It returns whether the item was found.
+func ReplaceItem[T comparable](items []T, oldItem T, newItem T) bool {
+	for i, current := range items {
+		if current == oldItem {
+			items[i] = newItem
+			return true
+		}
+	}
+
+	return false
+}
+
+// RemoveFunc removes every item for which remove returns true.
+// It returns the resulting slice and the number of removed items.
+func RemoveFunc[T any](items []T, remove func(T) bool) ([]T, int) {
+	kept := items[:0]
+	removed := 0
+
+	for _, item := range items {
+		if remove(item) {
+			removed++
+			continue
+		}
+		kept = append(kept, item)
+	}
+
+	var zero T
+	for i := len(kept); i < len(items); i++ {
+		items[i] = zero
+	}
+
+	return kept, removed
+}
+
+// CopySlice returns a shallow copy of items that can be iterated safely
+// while the original slice is being modified.
+func CopySlice[T any](items []T) []T {
+	copied := make([]T, len(items))
+	copy(copied, items)
+	return copied
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRemoveItem(t *testing.T) {
+	tests := []struct {
+		name      string
+		items     []string
+		item      string
+		want      []string
+		wantFound bool
+	}{
+		{
+			name:      "Remove existing item",
+			items:     []string{"a", "b", "c"},
+			item:      "b",
+			want:      []string{"a", "c"},
+			wantFound: true,
+		},
+		{
+			name:      "Remove only first occurrence",
+			items:     []string{"a", "b", "b"},
+			item:      "b",
+			want:      []string{"a", "b"},
+			wantFound: true,
+		},
+		{
+			name:      "Remove missing item",
+			items:     []string{"a", "b"},
+			item:      "z",
+			want:      []string{"a", "b"},
+			wantFound: false,
+		},
+		{
+			name:      "Remove from empty slice",
+			items:     []string{},
+			item:      "a",
+			want:      []string{},
+			wantFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := RemoveItem(tt.items, tt.item)
+			if found != tt.wantFound {
+				t.Errorf("RemoveItem() found = %v, want %v", found, tt.wantFound)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RemoveItem() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplaceItem(t *testing.T) {
+	items := []string{"a", "b", "c"}
+
+	if !ReplaceItem(items, "b", "x") {
+		t.Error("ReplaceItem() should report existing item as found")
+	}
+	if !reflect.DeepEqual(items, []string{"a", "x", "c"}) {
+		t.Errorf("ReplaceItem() = %v, want [a x c]", items)
+	}
+
+	if ReplaceItem(items, "z", "y") {
+		t.Error("ReplaceItem() should report missing item as not found")
+	}
+}
+
+func TestRemoveFunc(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	got, removed := RemoveFunc(items, func(i int) bool { return i%2 == 0 })
+
+	if removed != 2 {
+		t.Errorf("RemoveFunc() removed = %d, want 2", removed)
+	}
+	if !reflect.DeepEqual(got, []int{1, 3, 5}) {
+		t.Errorf("RemoveFunc() = %v, want [1 3 5]", got)
+	}
+}
+
+func TestCopySlice(t *testing.T) {
+	items := []string{"a", "b"}
+	copied := CopySlice(items)
+
+	copied[0] = "x"
+	if items[0] != "a" {
+		t.Error("CopySlice() should not share backing array with the original")
+	}
+	if len(copied) != len(items) {
+		t.Errorf("CopySlice() length = %d, want %d", len(copied), len(items))
+	}
+}
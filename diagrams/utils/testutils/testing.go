@@ -6,6 +6,8 @@ import (
 	"testing"
 )
 
+const frontmatterSeparator = "---\n"
+
 // TestFile represents a temporary test file
 type TestFile struct {
 	Path string
@@ -43,6 +45,18 @@ func AssertFileContent(t *testing.T, path string, want string) {
 	}
 }
 
+// DiagramBody returns the diagram source after its frontmatter, failing the test when the
+// source has none
+func DiagramBody(t *testing.T, source string) string {
+	t.Helper()
+	parts := strings.SplitN(source, frontmatterSeparator, 3)
+	if len(parts) != 3 || parts[0] != "" {
+		t.Fatalf("Source has no frontmatter:\n%s", source)
+	}
+
+	return parts[2]
+}
+
 // AssertContains checks if output contains all expected strings
 func AssertContains(t *testing.T, output string, wants ...string) {
 	t.Helper()
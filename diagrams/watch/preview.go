@@ -0,0 +1,145 @@
+package watch
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/spec"
+	"github.com/TyphonHill/go-mermaid/render/html"
+)
+
+// Paths served by the preview handler.
+const (
+	PagePath     string = "/"
+	EventsPath   string = "/events"
+	MermaidPath  string = "/mermaid.js"
+	DiagramsPath string = "/diagrams/"
+)
+
+const (
+	versionParam       string = "version"
+	eventString        string = "data: %d\n\n"
+	eventContentType   string = "text/event-stream"
+	mermaidContentType string = "text/plain; charset=utf-8"
+	noCacheValue       string = "no-cache"
+	pageTitle          string = "Mermaid preview"
+)
+
+// rendering is the Mermaid syntax of a diagram, shown as it is by the preview page.
+type rendering string
+
+// String returns the Mermaid syntax.
+func (r rendering) String() string {
+	return string(r)
+}
+
+// Handler returns an HTTP handler serving a preview of the diagrams of the watcher. The page
+// shows every diagram and the generation errors, and reloads itself when they change.
+// mermaidJS is the path of a local copy of mermaid.js, served to the page so that the
+// preview works offline; when it is empty, the page shows the Mermaid syntax of the
+// diagrams instead. The syntax of a diagram is also served under DiagramsPath.
+func (w *Watcher) Handler(mermaidJS string) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(PagePath, func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != PagePath {
+			http.NotFound(rw, r)
+			return
+		}
+		w.servePage(rw, mermaidJS != "")
+	})
+	mux.HandleFunc(EventsPath, w.serveEvents)
+	mux.HandleFunc(DiagramsPath, w.serveDiagram)
+	if mermaidJS != "" {
+		mux.HandleFunc(MermaidPath, func(rw http.ResponseWriter, r *http.Request) {
+			http.ServeFile(rw, r, mermaidJS)
+		})
+	}
+
+	return mux
+}
+
+// servePage writes the preview page, the page of package render/html.
+func (w *Watcher) servePage(rw http.ResponseWriter, mermaid bool) {
+	version := w.Version()
+	diagrams := w.Diagrams()
+
+	page := html.NewPage(pageTitle).
+		SetReloadURL(EventsPath + "?" + versionParam + "=" + strconv.FormatUint(version, 10))
+	if mermaid {
+		page.SetScriptPath(MermaidPath)
+	} else {
+		page.SetSourceOnly(true)
+	}
+	for _, err := range w.Errors() {
+		page.AddError(err.Error())
+	}
+
+	names := make([]string, 0, len(diagrams))
+	for name := range diagrams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		page.AddLinkedDiagram(name, DiagramsPath+name+spec.FileExtension, rendering(diagrams[name]))
+	}
+
+	rw.Header().Set("Cache-Control", noCacheValue)
+	io.WriteString(rw, page.String())
+}
+
+// serveEvents streams server-sent events carrying the version of the diagrams, starting
+// as soon as it differs from the version query parameter.
+func (w *Watcher) serveEvents(rw http.ResponseWriter, r *http.Request) {
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		http.Error(rw, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	seen, err := strconv.ParseUint(r.URL.Query().Get(versionParam), 10, 64)
+	if err != nil {
+		seen = 0
+	}
+
+	rw.Header().Set("Content-Type", eventContentType)
+	rw.Header().Set("Cache-Control", noCacheValue)
+	rw.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		version, changed := w.wait()
+		if version != seen {
+			if _, err := fmt.Fprintf(rw, eventString, version); err != nil {
+				return
+			}
+			flusher.Flush()
+			seen = version
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changed:
+		}
+	}
+}
+
+// serveDiagram writes the Mermaid syntax of a diagram.
+func (w *Watcher) serveDiagram(rw http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, DiagramsPath), spec.FileExtension)
+
+	source, ok := w.Diagrams()[name]
+	if !ok {
+		http.NotFound(rw, r)
+		return
+	}
+
+	rw.Header().Set("Content-Type", mermaidContentType)
+	rw.Header().Set("Cache-Control", noCacheValue)
+	fmt.Fprint(rw, source)
+}
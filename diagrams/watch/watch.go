@@ -0,0 +1,409 @@
+// Package watch regenerates diagrams when the files they are generated from change, and
+// serves a preview page that reloads itself when they do.
+//
+// A Watcher polls the size and modification time of the inputs of its generators, which
+// works on every platform and file system without native notifications. Once the inputs of
+// a generator have changed and then stayed unchanged for the debounce delay, the generator
+// runs again and only the diagrams whose rendering changed are written to the output
+// directory with utils.RenderToFile.
+package watch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/spec"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// Default polling delays.
+const (
+	DefaultInterval = 300 * time.Millisecond
+	DefaultDebounce = 300 * time.Millisecond
+)
+
+// ErrDuplicateDiagram is returned when two generators produce diagrams with the same name.
+var ErrDuplicateDiagram = errors.New("diagram generated twice")
+
+const (
+	duplicateErrorString string = "%w: %q"
+	fingerprintString    string = "%s\x00%d\x00%d\n"
+	missingString        string = "%s\x00missing\n"
+	hiddenPrefix         string = "."
+)
+
+// Generator produces named diagrams from input files.
+type Generator interface {
+	// Generate returns the diagrams by name and the files and directories they were
+	// generated from. Directories are watched recursively, skipping hidden directories. When
+	// Generate fails, the returned inputs are watched along with the previous ones, so that
+	// fixing them runs the generator again.
+	Generate() (diagrams map[string]basediagram.Diagram, inputs []string, err error)
+}
+
+// GeneratorFunc returns a generator running a function whose diagrams depend on fixed
+// inputs, such as the directories of the Go packages a diagram is built from.
+func GeneratorFunc(inputs []string, generate func() (map[string]basediagram.Diagram, error)) Generator {
+	return &funcGenerator{inputs: inputs, generate: generate}
+}
+
+// funcGenerator is a generator with fixed inputs.
+type funcGenerator struct {
+	inputs   []string
+	generate func() (map[string]basediagram.Diagram, error)
+}
+
+// Generate runs the function of the generator.
+func (g *funcGenerator) Generate() (map[string]basediagram.Diagram, []string, error) {
+	diagrams, err := g.generate()
+	return diagrams, g.inputs, err
+}
+
+// Spec returns a generator of the diagrams of a spec file, see package spec. The files it
+// includes are watched too.
+func Spec(path string) Generator {
+	return specGenerator(path)
+}
+
+// specGenerator is the path of a spec file.
+type specGenerator string
+
+// Generate loads the spec file.
+func (g specGenerator) Generate() (map[string]basediagram.Diagram, []string, error) {
+	s, err := spec.Load(string(g))
+	if err != nil {
+		return nil, []string{string(g)}, err
+	}
+
+	diagrams := make(map[string]basediagram.Diagram, len(s.Diagrams))
+	for _, diagram := range s.Diagrams {
+		diagrams[diagram.Name] = diagram.Model
+	}
+
+	return diagrams, s.Files, nil
+}
+
+// Options controls a Watcher.
+type Options struct {
+	// Dir is the directory the Mermaid files of the diagrams are written to, named after
+	// the diagrams. No file is written when it is empty.
+	Dir string
+	// Interval is the delay between two polls of the inputs, DefaultInterval when zero.
+	Interval time.Duration
+	// Debounce is how long changed inputs must stay unchanged before their generator runs
+	// again, DefaultDebounce when zero.
+	Debounce time.Duration
+	// Written is called with the path of every written file, if not nil.
+	Written func(path string)
+	// Failed is called with every generation and write error, if not nil.
+	Failed func(err error)
+}
+
+// Watcher regenerates the diagrams of its generators when their inputs change. Its methods
+// may be called concurrently; concurrent polls run one after the other.
+type Watcher struct {
+	options Options
+
+	// polling is held by Poll, which alone reads and writes the generation state of the
+	// targets.
+	polling sync.Mutex
+
+	mu         sync.Mutex
+	targets    []*target
+	renderings map[string]string
+	owners     map[string]*target
+	version    uint64
+	changed    chan struct{}
+}
+
+// target is the generation state of a generator.
+type target struct {
+	generator Generator
+	inputs    []string
+	generated bool
+	// current is the fingerprint of the inputs at the last generation.
+	current string
+	// seen is the last fingerprint polled, and seenAt when it was first polled.
+	seen   string
+	seenAt time.Time
+	names  []string
+	err    error
+}
+
+// New returns a watcher of the diagrams of the generators.
+func New(options Options, generators ...Generator) *Watcher {
+	if options.Interval <= 0 {
+		options.Interval = DefaultInterval
+	}
+	if options.Debounce <= 0 {
+		options.Debounce = DefaultDebounce
+	}
+
+	w := &Watcher{
+		options:    options,
+		renderings: make(map[string]string),
+		owners:     make(map[string]*target),
+		changed:    make(chan struct{}),
+	}
+	w.Add(generators...)
+
+	return w
+}
+
+// Run generates every diagram, then polls the inputs and regenerates the diagrams of the
+// changed ones until the context is done. It returns the error of the context.
+func (w *Watcher) Run(ctx context.Context) error {
+	w.Poll(time.Now())
+
+	ticker := time.NewTicker(w.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			w.Poll(now)
+		}
+	}
+}
+
+// Add adds generators to the watcher. Their diagrams are generated at the next poll.
+func (w *Watcher) Add(generators ...Generator) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, generator := range generators {
+		w.targets = append(w.targets, &target{generator: generator})
+	}
+}
+
+// Poll checks the inputs once at the given time. Generators that never ran run now, and
+// the others run again when their inputs changed and have stayed unchanged for the
+// debounce delay. It returns the paths of the written files.
+func (w *Watcher) Poll(now time.Time) (written []string) {
+	w.polling.Lock()
+	defer w.polling.Unlock()
+
+	w.mu.Lock()
+	targets := append([]*target(nil), w.targets...)
+	w.mu.Unlock()
+
+	for _, t := range targets {
+		if !t.generated {
+			written = append(written, w.generate(t, now)...)
+			continue
+		}
+
+		fingerprint := fingerprint(t.inputs)
+		if fingerprint != t.seen {
+			t.seen, t.seenAt = fingerprint, now
+			continue
+		}
+		if fingerprint != t.current && now.Sub(t.seenAt) >= w.options.Debounce {
+			written = append(written, w.generate(t, now)...)
+		}
+	}
+
+	return written
+}
+
+// Version returns a number that changes every time a diagram or a generation error does.
+func (w *Watcher) Version() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.version
+}
+
+// Diagrams returns the Mermaid syntax of the current diagrams by name.
+func (w *Watcher) Diagrams() map[string]string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	diagrams := make(map[string]string, len(w.renderings))
+	for name, rendering := range w.renderings {
+		diagrams[name] = rendering
+	}
+
+	return diagrams
+}
+
+// Errors returns the errors of the last generation of every generator that failed.
+func (w *Watcher) Errors() (errs []error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, t := range w.targets {
+		if t.err != nil {
+			errs = append(errs, t.err)
+		}
+	}
+
+	return errs
+}
+
+// generate runs the generator of a target and writes the diagrams whose rendering changed.
+// The diagrams of a failed generation are kept.
+func (w *Watcher) generate(t *target, now time.Time) (written []string) {
+	diagrams, inputs, err := t.generator.Generate()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err != nil {
+		inputs = union(t.inputs, inputs)
+	}
+	t.inputs, t.generated = inputs, true
+	t.current = fingerprint(inputs)
+	t.seen, t.seenAt = t.current, now
+
+	changed := !sameError(t.err, err)
+	t.err = err
+	if err != nil {
+		w.fail(err)
+		w.publish(changed)
+		return nil
+	}
+
+	names := make([]string, 0, len(diagrams))
+	for name := range diagrams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range t.names {
+		if _, ok := diagrams[name]; !ok && w.owners[name] == t {
+			delete(w.renderings, name)
+			delete(w.owners, name)
+			changed = true
+		}
+	}
+	t.names = t.names[:0]
+
+	for _, name := range names {
+		if owner := w.owners[name]; owner != nil && owner != t {
+			w.fail(fmt.Errorf(duplicateErrorString, ErrDuplicateDiagram, name))
+			continue
+		}
+		t.names = append(t.names, name)
+		w.owners[name] = t
+
+		rendering := diagrams[name].String()
+		if previous, ok := w.renderings[name]; ok && previous == rendering {
+			continue
+		}
+		w.renderings[name] = rendering
+		changed = true
+
+		if w.options.Dir == "" {
+			continue
+		}
+		path := filepath.Join(w.options.Dir, name+spec.FileExtension)
+		if err := utils.RenderToFile(path, rendering); err != nil {
+			w.fail(err)
+			continue
+		}
+		written = append(written, path)
+		if w.options.Written != nil {
+			w.options.Written(path)
+		}
+	}
+
+	w.publish(changed)
+
+	return written
+}
+
+// fail reports an error.
+func (w *Watcher) fail(err error) {
+	if w.options.Failed != nil {
+		w.options.Failed(err)
+	}
+}
+
+// publish starts a new version and wakes its waiters when something changed. The lock must
+// be held.
+func (w *Watcher) publish(changed bool) {
+	if !changed {
+		return
+	}
+
+	w.version++
+	close(w.changed)
+	w.changed = make(chan struct{})
+}
+
+// wait returns the current version and a channel closed when it changes.
+func (w *Watcher) wait() (uint64, <-chan struct{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.version, w.changed
+}
+
+// fingerprint returns a digest of the sizes and modification times of the files of the
+// inputs. Missing inputs are part of the digest, so that creating them changes it.
+func fingerprint(inputs []string) string {
+	hash := sha256.New()
+
+	for _, input := range inputs {
+		filepath.WalkDir(input, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				fmt.Fprintf(hash, missingString, path)
+				return nil
+			}
+			if entry.IsDir() {
+				if path != input && strings.HasPrefix(entry.Name(), hiddenPrefix) {
+					return fs.SkipDir
+				}
+				return nil
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				fmt.Fprintf(hash, missingString, path)
+				return nil
+			}
+			fmt.Fprintf(hash, fingerprintString, path, info.Size(), info.ModTime().UnixNano())
+			return nil
+		})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// union returns the inputs of both lists, without repeating any.
+func union(a []string, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	inputs := make([]string, 0, len(a)+len(b))
+
+	for _, input := range append(append([]string{}, a...), b...) {
+		if !seen[input] {
+			seen[input] = true
+			inputs = append(inputs, input)
+		}
+	}
+
+	return inputs
+}
+
+// sameError reports whether two generation errors have the same message.
+func sameError(a error, b error) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Error() == b.Error()
+}
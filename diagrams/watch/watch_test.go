@@ -0,0 +1,366 @@
+package watch
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+const (
+	mainSpec = `include:
+  - styles.yaml
+diagrams:
+  - name: checkout
+    type: flowchart
+    nodes:
+      - {id: cart, text: Cart}
+`
+	stylesSpec = `styles:
+  critical: {fill: "#f96"}
+diagrams:
+  - name: shared
+    type: timeline
+    title: Shared
+`
+)
+
+// writeFile writes a file of the test directory.
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readFile reads a file of the test directory.
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+// names returns the base names of paths.
+func names(paths []string) string {
+	var names []string
+	for _, path := range paths {
+		names = append(names, filepath.Base(path))
+	}
+
+	return strings.Join(names, ",")
+}
+
+func TestWatcher_Poll(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	main := filepath.Join(dir, "main.yaml")
+	styles := filepath.Join(dir, "styles.yaml")
+	writeFile(t, main, mainSpec)
+	writeFile(t, styles, stylesSpec)
+
+	var failures []error
+	w := New(Options{Dir: out, Debounce: time.Second, Failed: func(err error) {
+		failures = append(failures, err)
+	}}, Spec(main))
+	start := time.Now()
+
+	if got := names(w.Poll(start)); got != "checkout.mmd,shared.mmd" {
+		t.Fatalf("first Poll() = %q, want every diagram", got)
+	}
+	if !strings.Contains(readFile(t, filepath.Join(out, "checkout.mmd")), "cart@{ shape: rect, label: \"Cart\"}") {
+		t.Errorf("checkout.mmd = %q, want the cart node", readFile(t, filepath.Join(out, "checkout.mmd")))
+	}
+	version := w.Version()
+
+	if got := w.Poll(start.Add(2 * time.Second)); len(got) != 0 {
+		t.Errorf("Poll() without change = %v, want nothing", got)
+	}
+
+	writeFile(t, styles, strings.Replace(stylesSpec, "Shared", "Shared changes", 1))
+	if got := w.Poll(start.Add(3 * time.Second)); len(got) != 0 {
+		t.Errorf("Poll() right after a change = %v, want nothing before the debounce delay", got)
+	}
+	if got := w.Poll(start.Add(3500 * time.Millisecond)); len(got) != 0 {
+		t.Errorf("Poll() within the debounce delay = %v, want nothing", got)
+	}
+	if got := names(w.Poll(start.Add(4 * time.Second))); got != "shared.mmd" {
+		t.Errorf("Poll() after the debounce delay = %q, want only the changed diagram", got)
+	}
+	if w.Version() == version {
+		t.Error("Version() did not change with a diagram")
+	}
+
+	writeFile(t, main, mainSpec+"      - {id: broken\n")
+	w.Poll(start.Add(5 * time.Second))
+	if got := w.Poll(start.Add(6 * time.Second)); len(got) != 0 {
+		t.Errorf("Poll() of an invalid spec = %v, want nothing", got)
+	}
+	if len(failures) != 1 || len(w.Errors()) != 1 {
+		t.Fatalf("failures = %v, Errors() = %v, want the spec error", failures, w.Errors())
+	}
+	if len(w.Diagrams()) != 2 {
+		t.Errorf("Diagrams() = %v, want the diagrams of the last generation", w.Diagrams())
+	}
+
+	writeFile(t, main, strings.Replace(mainSpec, "Cart", "Basket", 1))
+	w.Poll(start.Add(7 * time.Second))
+	if got := names(w.Poll(start.Add(8 * time.Second))); got != "checkout.mmd" {
+		t.Errorf("Poll() of the fixed spec = %q, want checkout.mmd", got)
+	}
+	if len(w.Errors()) != 0 {
+		t.Errorf("Errors() = %v, want none once fixed", w.Errors())
+	}
+}
+
+func TestWatcher_GeneratorFunc(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "pkg", "main.go")
+	if err := os.MkdirAll(filepath.Dir(input), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, input, "package main\n")
+	if err := os.MkdirAll(filepath.Join(dir, "pkg", ".cache"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	runs := 0
+	generator := GeneratorFunc([]string{filepath.Join(dir, "pkg")}, func() (map[string]basediagram.Diagram, error) {
+		runs++
+		return map[string]basediagram.Diagram{"packages": flowchart.NewFlowchart()}, nil
+	})
+	w := New(Options{}, generator)
+	start := time.Now()
+
+	w.Poll(start)
+	writeFile(t, filepath.Join(dir, "pkg", ".cache", "entry"), "ignored")
+	w.Poll(start.Add(time.Second))
+	w.Poll(start.Add(2 * time.Second))
+	if runs != 1 {
+		t.Errorf("runs = %d after a change of a hidden directory, want 1", runs)
+	}
+
+	writeFile(t, filepath.Join(dir, "pkg", "extra.go"), "package main\n")
+	w.Poll(start.Add(3 * time.Second))
+	w.Poll(start.Add(4 * time.Second))
+	if runs != 2 {
+		t.Errorf("runs = %d after a new file, want 2", runs)
+	}
+	if _, ok := w.Diagrams()["packages"]; !ok {
+		t.Errorf("Diagrams() = %v, want packages", w.Diagrams())
+	}
+}
+
+func TestWatcher_Duplicates(t *testing.T) {
+	generate := func() (map[string]basediagram.Diagram, error) {
+		return map[string]basediagram.Diagram{"same": flowchart.NewFlowchart()}, nil
+	}
+
+	var failures []error
+	w := New(Options{Failed: func(err error) { failures = append(failures, err) }},
+		GeneratorFunc(nil, generate), GeneratorFunc(nil, generate))
+	w.Poll(time.Now())
+
+	if len(failures) != 1 || !errors.Is(failures[0], ErrDuplicateDiagram) {
+		t.Errorf("failures = %v, want %v", failures, ErrDuplicateDiagram)
+	}
+}
+
+func TestWatcher_Concurrent(t *testing.T) {
+	const count = 20
+
+	w := New(Options{})
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("flow%d", i)
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			w.Add(GeneratorFunc(nil, func() (map[string]basediagram.Diagram, error) {
+				return map[string]basediagram.Diagram{name: flowchart.NewFlowchart()}, nil
+			}))
+		}()
+		go func(i int) {
+			defer wg.Done()
+			w.Poll(start.Add(time.Duration(i) * time.Second))
+		}(i)
+		go func() {
+			defer wg.Done()
+			w.Diagrams()
+			w.Errors()
+		}()
+	}
+	wg.Wait()
+	w.Poll(start.Add(count * time.Second))
+
+	if got := len(w.Diagrams()); got != count {
+		t.Errorf("len(Diagrams()) = %d, want %d", got, count)
+	}
+}
+
+func TestWatcher_Run(t *testing.T) {
+	generator := GeneratorFunc(nil, func() (map[string]basediagram.Diagram, error) {
+		return map[string]basediagram.Diagram{"flow": flowchart.NewFlowchart()}, nil
+	})
+	w := New(Options{Interval: time.Millisecond}, generator)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := w.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want %v", err, context.Canceled)
+	}
+	if len(w.Diagrams()) != 1 {
+		t.Errorf("Diagrams() = %v, want the first generation", w.Diagrams())
+	}
+}
+
+func TestWatcher_Handler(t *testing.T) {
+	dir := t.TempDir()
+	mermaidJS := filepath.Join(dir, "mermaid.min.js")
+	writeFile(t, mermaidJS, "window.mermaid = {};")
+
+	fail := false
+	generator := GeneratorFunc(nil, func() (map[string]basediagram.Diagram, error) {
+		if fail {
+			return nil, errors.New("broken <spec>")
+		}
+		d := flowchart.NewFlowchart()
+		d.AddNode(flowchart.NewNode("A", "A & B"))
+		return map[string]basediagram.Diagram{"flow": d}, nil
+	})
+	w := New(Options{}, generator)
+	w.Poll(time.Now())
+
+	tests := []struct {
+		name      string
+		mermaidJS string
+		path      string
+		wantCode  int
+		contains  []string
+	}{
+		{
+			name:      "Page with mermaid.js",
+			mermaidJS: mermaidJS,
+			path:      PagePath,
+			wantCode:  http.StatusOK,
+			contains:  []string{`<pre class="mermaid">`, `A &amp; B`, `<script src="/mermaid.js">`, `/events?version=1`, `href="/diagrams/flow.mmd"`},
+		},
+		{
+			name:     "Page without mermaid.js",
+			path:     PagePath,
+			wantCode: http.StatusOK,
+			contains: []string{"<pre>\n---\n", "flowchart TB", "A &amp; B"},
+		},
+		{
+			name:      "Local mermaid.js",
+			mermaidJS: mermaidJS,
+			path:      MermaidPath,
+			wantCode:  http.StatusOK,
+			contains:  []string{"window.mermaid"},
+		},
+		{
+			name:     "Missing mermaid.js",
+			path:     MermaidPath,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Diagram source",
+			path:     DiagramsPath + "flow.mmd",
+			wantCode: http.StatusOK,
+			contains: []string{"flowchart TB", `label: "A & B"`},
+		},
+		{
+			name:     "Unknown diagram",
+			path:     DiagramsPath + "missing.mmd",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Unknown page",
+			path:     "/missing",
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			w.Handler(tt.mermaidJS).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(rec.Body.String(), want) {
+					t.Errorf("body missing %q in:\n%s", want, rec.Body.String())
+				}
+			}
+		})
+	}
+
+	fail = true
+	w.targets[0].current = ""
+	w.targets[0].seenAt = time.Time{}
+	w.Poll(time.Now())
+
+	rec := httptest.NewRecorder()
+	w.Handler("").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, PagePath, nil))
+	if !strings.Contains(rec.Body.String(), `<pre class="error">broken &lt;spec&gt;</pre>`) {
+		t.Errorf("page = %s, want the generation error", rec.Body.String())
+	}
+}
+
+func TestWatcher_Events(t *testing.T) {
+	version := 0
+	generator := GeneratorFunc(nil, func() (map[string]basediagram.Diagram, error) {
+		version++
+		d := flowchart.NewFlowchart()
+		d.Title = strings.Repeat("v", version)
+		return map[string]basediagram.Diagram{"flow": d}, nil
+	})
+	w := New(Options{}, generator)
+	w.Poll(time.Now())
+
+	server := httptest.NewServer(w.Handler(""))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + EventsPath + "?version=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != eventContentType {
+		t.Errorf("Content-Type = %q, want %q", got, eventContentType)
+	}
+
+	w.targets[0].current = ""
+	w.targets[0].seenAt = time.Time{}
+	w.Poll(time.Now())
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	if line != "data: 2\n" {
+		t.Errorf("event = %q, want the new version", line)
+	}
+}
@@ -0,0 +1,91 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cache stores rendered diagrams by key. Keys are hex-encoded SHA-256 hashes of the
+// diagram source, the output format and the render settings. Implementations must be
+// safe for concurrent use.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Put(key string, output []byte)
+}
+
+// MemoryCache is a Cache kept in memory for the lifetime of the process.
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string][]byte
+}
+
+// NewMemoryCache creates an empty MemoryCache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string][]byte)}
+}
+
+// Get returns the output stored under the key.
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	output, ok := c.entries[key]
+	return output, ok
+}
+
+// Put stores the output under the key.
+func (c *MemoryCache) Put(key string, output []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = output
+}
+
+// Len returns the number of cached outputs.
+func (c *MemoryCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.entries)
+}
+
+// DirCache is a Cache storing one file per key in a directory, so that renders are reused
+// across processes, for example between CI runs.
+type DirCache struct {
+	Dir string
+}
+
+// NewDirCache creates a DirCache in the directory, which is created on first use.
+func NewDirCache(dir string) *DirCache {
+	return &DirCache{Dir: dir}
+}
+
+// Get returns the output stored under the key.
+func (c *DirCache) Get(key string) ([]byte, bool) {
+	output, err := os.ReadFile(filepath.Join(c.Dir, key))
+	if err != nil {
+		return nil, false
+	}
+	return output, true
+}
+
+// Put stores the output under the key. Write errors are ignored, as a missing cache entry
+// only causes the diagram to be rendered again. The file is written atomically so that
+// concurrent readers never see partial output.
+func (c *DirCache) Put(key string, output []byte) {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return
+	}
+
+	file, err := os.CreateTemp(c.Dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+
+	_, writeErr := file.Write(output)
+	closeErr := file.Close()
+	if writeErr != nil || closeErr != nil || os.Rename(file.Name(), filepath.Join(c.Dir, key)) != nil {
+		os.Remove(file.Name())
+	}
+}
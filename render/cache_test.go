@@ -0,0 +1,53 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	tests := []struct {
+		name  string
+		cache Cache
+	}{
+		{name: "Memory", cache: NewMemoryCache()},
+		{name: "Directory", cache: NewDirCache(filepath.Join(t.TempDir(), "cache"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := tt.cache.Get("a"); ok {
+				t.Error("Get() on an empty cache should miss")
+			}
+
+			tt.cache.Put("a", []byte("first"))
+			tt.cache.Put("b", []byte("second"))
+			tt.cache.Put("a", []byte("third"))
+
+			if got, ok := tt.cache.Get("a"); !ok || string(got) != "third" {
+				t.Errorf("Get(a) = %q, %v", got, ok)
+			}
+			if got, ok := tt.cache.Get("b"); !ok || string(got) != "second" {
+				t.Errorf("Get(b) = %q, %v", got, ok)
+			}
+		})
+	}
+}
+
+func TestDirCache_Files(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	NewDirCache(dir).Put("key", []byte("output"))
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "key" {
+		t.Errorf("DirCache should leave only the entry file, got %v", entries)
+	}
+
+	if _, ok := NewDirCache(dir).Get("key"); !ok {
+		t.Error("DirCache entries should be shared between instances")
+	}
+}
@@ -0,0 +1,126 @@
+package d2
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/block"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+)
+
+const spaceKeyString string = "space_%d"
+
+// blockShapes maps block shapes to the nearest D2 shape. Shapes missing from the map are
+// drawn as rectangles.
+var blockShapes = map[string]string{
+	string(block.BlockShapeCylindrical):   "cylinder",
+	string(block.BlockShapeCircle):        "circle",
+	string(block.BlockShapeAsymmetric):    "step",
+	string(block.BlockShapeRhombus):       "diamond",
+	string(block.BlockShapeHexagon):       "hexagon",
+	string(block.BlockShapeParallelogram): "parallelogram",
+	string(block.BlockShapeDoubleCircle):  "circle",
+}
+
+// roundedBlockShapes maps the block shapes drawn as rectangles with rounded corners to
+// their radius.
+var roundedBlockShapes = map[string]string{
+	string(block.BlockShapeRoundEdges): "8",
+	string(block.BlockShapeStadium):    "20",
+}
+
+// framedBlockShapes are drawn with a second outline.
+var framedBlockShapes = map[string]bool{
+	string(block.BlockShapeSubroutine):   true,
+	string(block.BlockShapeDoubleCircle): true,
+}
+
+// RenderBlock returns the block diagram as D2 source.
+//
+// The diagram and every block with nested blocks become D2 grids with the same number of
+// columns; without columns, blocks are placed on a single row as Mermaid does. Links are
+// declared at the top level with the full path of their blocks.
+func RenderBlock(d *block.Diagram) string {
+	w := &writer{}
+	w.header(d.Title, "")
+
+	paths := make(map[*block.Block]string)
+	writeBlocks(w, d.Blocks, d.Columns, "", paths)
+
+	for _, link := range d.Links {
+		w.edge(paths[link.From], "->", paths[link.To], link.Text, nil)
+	}
+
+	return w.String()
+}
+
+// RenderBlockToFile writes the block diagram as D2 source to the path.
+func RenderBlockToFile(d *block.Diagram, path string) error {
+	return utils.RenderToFile(path, RenderBlock(d))
+}
+
+// writeBlocks writes the grid settings of a container followed by its blocks, and records
+// the path of every block.
+func writeBlocks(w *writer, blocks []*block.Block, columns int, parent string, paths map[*block.Block]string) {
+	if len(blocks) == 0 {
+		return
+	}
+
+	if columns > 0 {
+		w.field(attribute{key: "grid-columns", value: strconv.Itoa(columns)})
+	} else {
+		w.field(attribute{key: "grid-rows", value: "1"})
+	}
+
+	spaces := 0
+	for _, b := range blocks {
+		if b.IsSpace {
+			for i := 0; i < b.Width || i == 0; i++ {
+				w.shape(fmt.Sprintf(spaceKeyString, spaces), "", []attribute{
+					{key: "label", value: `""`},
+					{key: "style.opacity", value: "0"},
+				})
+				spaces++
+			}
+			continue
+		}
+
+		paths[b] = key(b.ID)
+		if parent != "" {
+			paths[b] = path(parent, key(b.ID))
+		}
+
+		if len(b.Children) == 0 {
+			w.shape(key(b.ID), b.Text, blockAttributes(b))
+			continue
+		}
+
+		// Mermaid does not draw the text of blocks with nested blocks.
+		w.open(key(b.ID), "")
+		for _, a := range cssStyle(b.Style) {
+			w.field(a)
+		}
+		writeBlocks(w, b.Children, b.Columns(), paths[b], paths)
+		w.close()
+	}
+}
+
+// blockAttributes returns the shape and style attributes of a block.
+func blockAttributes(b *block.Block) (attrs []attribute) {
+	if b.IsArrow() {
+		attrs = append(attrs, attribute{key: "shape", value: "step"})
+		return append(attrs, cssStyle(b.Style)...)
+	}
+
+	if shape, ok := blockShapes[string(b.Shape)]; ok {
+		attrs = append(attrs, attribute{key: "shape", value: shape})
+	}
+	if radius, ok := roundedBlockShapes[string(b.Shape)]; ok {
+		attrs = append(attrs, attribute{key: "style.border-radius", value: radius})
+	}
+	if framedBlockShapes[string(b.Shape)] {
+		attrs = append(attrs, attribute{key: "style.double-border", value: "true"})
+	}
+
+	return append(attrs, cssStyle(b.Style)...)
+}
@@ -0,0 +1,95 @@
+package d2
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/block"
+)
+
+func TestRenderBlock(t *testing.T) {
+	d := block.NewDiagram()
+	d.SetTitle("Services")
+	d.SetColumns(3)
+
+	frontend := d.AddBlock("Frontend").SetShape(block.BlockShapeRoundEdges)
+	frontend.ID = "frontend"
+	d.AddSpaceWithWidth(2)
+	backend := d.AddBlock("").SetColumns(2).SetStyle("stroke:#333")
+	backend.ID = "backend"
+	api := backend.AddBlock("API")
+	api.ID = "api"
+	backend.AddBlock("DB").SetShape(block.BlockShapeCylindrical).SetStyle("fill:#f9f,stroke-width:2px").ID = "db"
+	d.AddBlock("").SetArrow(block.BlockArrowDirectionRight).ID = "next step"
+	d.AddLink(frontend, api).SetText("REST")
+
+	want := `title: "Services" {
+  shape: text
+  near: top-center
+  style.font-size: 24
+  style.bold: true
+}
+grid-columns: 3
+frontend: "Frontend" {
+  style.border-radius: 8
+}
+space_0: {
+  label: ""
+  style.opacity: 0
+}
+space_1: {
+  label: ""
+  style.opacity: 0
+}
+backend: "" {
+  style.stroke: "#333"
+  grid-columns: 2
+  api: "API"
+  db: "DB" {
+    shape: cylinder
+    style.fill: "#f9f"
+    style.stroke-width: 2
+  }
+}
+"next step": {
+  shape: step
+}
+frontend -> backend.api: "REST"
+`
+	if got := RenderBlock(d); got != want {
+		t.Errorf("RenderBlock() = \n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderBlock_SingleRow(t *testing.T) {
+	d := block.NewDiagram()
+	d.AddBlock("A")
+	d.AddSpace()
+	d.AddBlock("B").SetShape(block.BlockShapeDoubleCircle).ID = "b"
+
+	assertContains(t, RenderBlock(d),
+		"grid-rows: 1\n",
+		"space_0: {\n",
+		"b: \"B\" {\n  shape: circle\n  style.double-border: true\n}\n",
+	)
+}
+
+func TestRenderBlock_Empty(t *testing.T) {
+	if got := RenderBlock(block.NewDiagram()); got != "" {
+		t.Errorf("RenderBlock() = %q, want empty document", got)
+	}
+}
+
+func TestRenderBlockToFile(t *testing.T) {
+	d := block.NewDiagram()
+	d.AddBlock("A")
+	path := filepath.Join(t.TempDir(), "block.d2")
+
+	if err := RenderBlockToFile(d, path); err != nil {
+		t.Fatalf("RenderBlockToFile() error = %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != RenderBlock(d) {
+		t.Errorf("RenderBlockToFile() wrote %q", content)
+	}
+}
@@ -0,0 +1,210 @@
+// Package d2 exports flowcharts and block diagrams as D2 source.
+//
+// Subgraphs and nested blocks become containers, node shapes become the closest D2 shape
+// and node styles become D2 style keywords. Block diagrams are laid out with D2 grids.
+// The following constructs have no D2 equivalent and are approximated or dropped:
+//
+//   - Diagram configuration, themes, classDef names and link lengths are not exported.
+//   - Shapes without a D2 counterpart, such as trapezoids and triangles, are drawn as
+//     rectangles; asymmetric shapes and block arrows are drawn as steps.
+//   - Dash patterns are exported as a fixed dash, D2 only supports a dash size.
+//   - Blocks spanning several columns use a single grid cell; spaces are kept as
+//     invisible cells so the following blocks stay in their column.
+package d2
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	fieldString     string = "%s: %s\n"
+	openString      string = "%s: {\n"
+	labelOpenString string = "%s: %s {\n"
+	closeString     string = "}\n"
+	edgeString      string = "%s %s %s"
+	indentation     string = "  "
+	pathSeparator   string = "."
+	titleKey        string = "title"
+	dashSize        string = "3"
+	thickLinkWidth  string = "4"
+	defaultFontSize string = "24"
+)
+
+// identifierPattern matches keys D2 accepts without quotes.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// directions maps Mermaid directions to D2 directions.
+var directions = map[string]string{
+	"TB": "down",
+	"TD": "down",
+	"BT": "up",
+	"LR": "right",
+	"RL": "left",
+}
+
+// attribute is a D2 field whose value is already formatted.
+type attribute struct {
+	key   string
+	value string
+}
+
+// attr returns an attribute whose value is quoted as a string.
+func attr(key string, value string) attribute {
+	return attribute{key: key, value: quote(value)}
+}
+
+// writer builds an indented D2 document.
+type writer struct {
+	sb    strings.Builder
+	depth int
+}
+
+// header writes the direction and the title of the diagram.
+func (w *writer) header(title string, direction string) {
+	if d, ok := directions[direction]; ok {
+		w.field(attribute{key: "direction", value: d})
+	}
+	if title != "" {
+		w.shape(titleKey, title, []attribute{
+			{key: "shape", value: "text"},
+			{key: "near", value: "top-center"},
+			{key: "style.font-size", value: defaultFontSize},
+			{key: "style.bold", value: "true"},
+		})
+	}
+}
+
+// field writes a single field.
+func (w *writer) field(a attribute) {
+	w.indent()
+	w.sb.WriteString(fmt.Sprintf(fieldString, a.key, a.value))
+}
+
+// shape writes a shape with its label and attributes. An empty label keeps the key as
+// the label.
+func (w *writer) shape(key string, label string, attrs []attribute) {
+	w.indent()
+	if len(attrs) == 0 {
+		if label == "" {
+			w.sb.WriteString(key + "\n")
+		} else {
+			w.sb.WriteString(fmt.Sprintf(fieldString, key, quote(label)))
+		}
+		return
+	}
+
+	w.sb.WriteString(opening(key, label))
+	w.block(attrs)
+}
+
+// open starts a container with the given label. Fields and shapes written before close
+// are nested in the container.
+func (w *writer) open(key string, label string) {
+	w.indent()
+	w.sb.WriteString(fmt.Sprintf(labelOpenString, key, quote(label)))
+	w.depth++
+}
+
+// close ends the innermost open container.
+func (w *writer) close() {
+	w.depth--
+	w.indent()
+	w.sb.WriteString(closeString)
+}
+
+// edge writes a connection between two shape paths with its label and attributes.
+func (w *writer) edge(from string, operator string, to string, label string, attrs []attribute) {
+	w.indent()
+	connection := fmt.Sprintf(edgeString, from, operator, to)
+	switch {
+	case len(attrs) > 0:
+		w.sb.WriteString(opening(connection, label))
+		w.block(attrs)
+	case label != "":
+		w.sb.WriteString(fmt.Sprintf(fieldString, connection, quote(label)))
+	default:
+		w.sb.WriteString(connection + "\n")
+	}
+}
+
+// block writes the attributes of a shape or connection and closes its braces.
+func (w *writer) block(attrs []attribute) {
+	w.depth++
+	for _, a := range attrs {
+		w.field(a)
+	}
+	w.close()
+}
+
+// opening returns the opening line of a block, with the label when there is one.
+func opening(key string, label string) string {
+	if label == "" {
+		return fmt.Sprintf(openString, key)
+	}
+	return fmt.Sprintf(labelOpenString, key, quote(label))
+}
+
+// indent writes the indentation of the current depth.
+func (w *writer) indent() {
+	w.sb.WriteString(strings.Repeat(indentation, w.depth))
+}
+
+// String returns the document written so far.
+func (w *writer) String() string {
+	return w.sb.String()
+}
+
+// key returns a shape key, quoting it when D2 would not accept it as is.
+func key(id string) string {
+	if identifierPattern.MatchString(id) {
+		return id
+	}
+	return quote(id)
+}
+
+// path joins shape keys into the path of a nested shape.
+func path(keys ...string) string {
+	return strings.Join(keys, pathSeparator)
+}
+
+// quote returns the string as a D2 double quoted string. Line breaks, including HTML
+// breaks used in Mermaid labels, are kept as escapes.
+func quote(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "<br>", "\n")
+	s = strings.ReplaceAll(s, "<br/>", "\n")
+	return strconv.Quote(s)
+}
+
+// cssStyle converts CSS declarations such as "fill:#f9f,stroke-width:2px" to D2 style
+// attributes. Unsupported properties are ignored.
+func cssStyle(css string) (attrs []attribute) {
+	for _, declaration := range strings.FieldsFunc(css, func(r rune) bool { return r == ',' || r == ';' }) {
+		property, value, ok := strings.Cut(declaration, ":")
+		if !ok {
+			continue
+		}
+		property, value = strings.TrimSpace(property), strings.TrimSpace(value)
+
+		switch property {
+		case "fill":
+			attrs = append(attrs, attr("style.fill", value))
+		case "stroke":
+			attrs = append(attrs, attr("style.stroke", value))
+		case "color":
+			attrs = append(attrs, attr("style.font-color", value))
+		case "stroke-width":
+			if width, err := strconv.Atoi(strings.TrimSuffix(value, "px")); err == nil && width > 0 {
+				attrs = append(attrs, attribute{key: "style.stroke-width", value: strconv.Itoa(width)})
+			}
+		case "stroke-dasharray":
+			if value != "0" && value != "none" {
+				attrs = append(attrs, attribute{key: "style.stroke-dash", value: dashSize})
+			}
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,117 @@
+package d2
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// assertContains fails the test for every expected fragment missing from got.
+func assertContains(t *testing.T, got string, fragments ...string) {
+	t.Helper()
+	for _, fragment := range fragments {
+		if !strings.Contains(got, fragment) {
+			t.Errorf("missing %q in:\n%s", fragment, got)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "Plain", s: "Start", want: `"Start"`},
+		{name: "Empty", s: "", want: `""`},
+		{name: "Quotes and backslashes", s: `say "hi" \o/`, want: `"say \"hi\" \\o/"`},
+		{name: "Line breaks", s: "one\r\ntwo<br>three<br/>four", want: `"one\ntwo\nthree\nfour"`},
+		{name: "Comment sign", s: "#f9f", want: `"#f9f"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quote(tt.s); got != tt.want {
+				t.Errorf("quote() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{id: "start", want: "start"},
+		{id: "_node_2", want: "_node_2"},
+		{id: "0", want: `"0"`},
+		{id: "a.b", want: `"a.b"`},
+		{id: "two words", want: `"two words"`},
+	}
+
+	for _, tt := range tests {
+		if got := key(tt.id); got != tt.want {
+			t.Errorf("key(%q) = %s, want %s", tt.id, got, tt.want)
+		}
+	}
+
+	if got := path(key("a"), key("1")); got != `a."1"` {
+		t.Errorf("path() = %s", got)
+	}
+}
+
+func TestCSSStyle(t *testing.T) {
+	got := cssStyle("fill:#f9f, stroke:#333;stroke-width:4px,color:white,stroke-dasharray: 5 5,opacity:0.5,invalid")
+	want := []attribute{
+		{key: "style.fill", value: `"#f9f"`},
+		{key: "style.stroke", value: `"#333"`},
+		{key: "style.stroke-width", value: "4"},
+		{key: "style.font-color", value: `"white"`},
+		{key: "style.stroke-dash", value: dashSize},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cssStyle() = %v, want %v", got, want)
+	}
+
+	if got := cssStyle("stroke-dasharray:0,stroke-width:thick"); len(got) != 0 {
+		t.Errorf("cssStyle() = %v, want no attributes", got)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	w := &writer{}
+	w.header("My diagram", "BT")
+	w.open("group", "Group")
+	w.shape("a", "", nil)
+	w.shape("b", "B", nil)
+	w.shape("c", "", []attribute{{key: "shape", value: "circle"}})
+	w.close()
+	w.edge("group.a", "->", "group.b", "", nil)
+	w.edge("group.b", "--", "group.c", "link", nil)
+	w.edge("group.c", "<-", "group.a", "", []attribute{{key: "style.opacity", value: "0"}})
+
+	want := `direction: up
+title: "My diagram" {
+  shape: text
+  near: top-center
+  style.font-size: 24
+  style.bold: true
+}
+group: "Group" {
+  a
+  b: "B"
+  c: {
+    shape: circle
+  }
+}
+group.a -> group.b
+group.b -- group.c: "link"
+group.c <- group.a: {
+  style.opacity: 0
+}
+`
+	if got := w.String(); got != want {
+		t.Errorf("writer = \n%s\nwant\n%s", got, want)
+	}
+}
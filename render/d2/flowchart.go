@@ -0,0 +1,279 @@
+package d2
+
+import (
+	"strconv"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+)
+
+// nodeShapes maps flowchart node shapes to the nearest D2 shape. Shapes missing from the
+// map are drawn as rectangles, the D2 default.
+var nodeShapes = map[flowchart.NodeShape]string{
+	flowchart.NodeShapeDatabase:         "cylinder",
+	flowchart.NodeShapeStart:            "circle",
+	flowchart.NodeShapeOdd:              "step",
+	flowchart.NodeShapeDecision:         "diamond",
+	flowchart.NodeShapePrepare:          "hexagon",
+	flowchart.NodeShapeInputOutput:      "parallelogram",
+	flowchart.NodeShapeOutputInput:      "parallelogram",
+	flowchart.NodeShapeStopDouble:       "circle",
+	flowchart.NodeShapeText:             "text",
+	flowchart.NodeShapeCard:             "page",
+	flowchart.NodeShapeStartSmall:       "circle",
+	flowchart.NodeShapeStopFramed:       "circle",
+	flowchart.NodeShapeComment:          "text",
+	flowchart.NodeShapeCommentRight:     "text",
+	flowchart.NodeShapeCommentBothSides: "text",
+	flowchart.NodeShapeComLink:          "text",
+	flowchart.NodeShapeDocument:         "document",
+	flowchart.NodeShapeStorage:          "queue",
+	flowchart.NodeShapeDiskStorage:      "cylinder",
+	flowchart.NodeShapeJunction:         "circle",
+	flowchart.NodeShapeLinedDocument:    "document",
+	flowchart.NodeShapeMultiDocument:    "document",
+	flowchart.NodeShapePaperTape:        "page",
+	flowchart.NodeShapeStoredData:       "stored_data",
+	flowchart.NodeShapeSummary:          "circle",
+	flowchart.NodeShapeTaggedDocument:   "document",
+}
+
+// roundedShapes maps the shapes drawn as rectangles with rounded corners to their radius.
+var roundedShapes = map[flowchart.NodeShape]string{
+	flowchart.NodeShapeEvent:    "8",
+	flowchart.NodeShapeDelay:    "8",
+	flowchart.NodeShapeTerminal: "20",
+}
+
+// framedShapes are drawn with a second outline.
+var framedShapes = map[flowchart.NodeShape]bool{
+	flowchart.NodeShapeSubprocess:   true,
+	flowchart.NodeShapeLinedProcess: true,
+	flowchart.NodeShapeStopDouble:   true,
+	flowchart.NodeShapeStopFramed:   true,
+}
+
+// stackedShapes are drawn as a stack of shapes.
+var stackedShapes = map[flowchart.NodeShape]bool{
+	flowchart.NodeShapeMultiDocument: true,
+	flowchart.NodeShapeMultiProcess:  true,
+}
+
+// arrowheads maps flowchart link markers to D2 arrowhead shapes. Plain arrows use the D2
+// default arrowhead.
+var arrowheads = map[flowchart.LinkArrowType]string{
+	flowchart.LinkArrowTypeBullet: "circle",
+	flowchart.LinkArrowTypeCross:  "cross",
+}
+
+// RenderFlowchart returns the flowchart as D2 source.
+//
+// Subgraphs become containers around the nodes their links reference, as returned by
+// Flowchart.NodeSubgraphs, and links are declared at the top level with the full path of
+// their nodes. Node styles and classes set the fill, stroke and text colours.
+func RenderFlowchart(f *flowchart.Flowchart) string {
+	w := &writer{}
+	w.header(f.Title, string(f.Direction))
+
+	placement := f.NodeSubgraphs()
+	paths := make(map[*flowchart.Node]string)
+	members := make(map[*flowchart.Subgraph][]*flowchart.Node)
+	for _, node := range f.Graph().Nodes() {
+		if subgraph, ok := placement[node]; ok {
+			members[subgraph] = append(members[subgraph], node)
+		} else {
+			w.shape(key(node.ID), nodeLabel(node), nodeAttributes(node))
+			paths[node] = key(node.ID)
+		}
+	}
+
+	for _, subgraph := range f.Subgraphs() {
+		writeSubgraph(w, subgraph, "", members, paths)
+	}
+
+	for _, link := range f.Links() {
+		operator, attrs := linkAttributes(link)
+		w.edge(paths[link.From], operator, paths[link.To], link.Text, attrs)
+	}
+
+	return w.String()
+}
+
+// RenderFlowchartToFile writes the flowchart as D2 source to the path.
+func RenderFlowchartToFile(f *flowchart.Flowchart, path string) error {
+	return utils.RenderToFile(path, RenderFlowchart(f))
+}
+
+// writeSubgraph writes a subgraph container with its nodes and nested subgraphs and
+// records the path of its nodes. Subgraphs without nodes are skipped.
+func writeSubgraph(w *writer, subgraph *flowchart.Subgraph, parent string, members map[*flowchart.Subgraph][]*flowchart.Node, paths map[*flowchart.Node]string) {
+	if !hasMembers(subgraph, members) {
+		return
+	}
+
+	container := key(subgraph.ID)
+	if parent != "" {
+		container = path(parent, container)
+	}
+
+	w.open(key(subgraph.ID), subgraph.Title)
+	if d, ok := directions[string(subgraph.Direction)]; ok {
+		w.field(attribute{key: "direction", value: d})
+	}
+	for _, node := range members[subgraph] {
+		w.shape(key(node.ID), nodeLabel(node), nodeAttributes(node))
+		paths[node] = path(container, key(node.ID))
+	}
+	for _, nested := range subgraph.Subgraphs() {
+		writeSubgraph(w, nested, container, members, paths)
+	}
+	w.close()
+}
+
+// hasMembers reports whether the subgraph or a nested subgraph contains a node.
+func hasMembers(subgraph *flowchart.Subgraph, members map[*flowchart.Subgraph][]*flowchart.Node) bool {
+	if len(members[subgraph]) > 0 {
+		return true
+	}
+	for _, nested := range subgraph.Subgraphs() {
+		if hasMembers(nested, members) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeLabel returns the label of a node. Fork, join and junction shapes have no label.
+func nodeLabel(node *flowchart.Node) string {
+	if node.Shape == flowchart.NodeShapeForkJoin || node.Shape == flowchart.NodeShapeJunction {
+		return ""
+	}
+	return node.Text
+}
+
+// nodeAttributes returns the shape and style attributes of a node.
+func nodeAttributes(node *flowchart.Node) (attrs []attribute) {
+	if shape, ok := nodeShapes[node.Shape]; ok {
+		attrs = append(attrs, attribute{key: "shape", value: shape})
+	}
+	if radius, ok := roundedShapes[node.Shape]; ok {
+		attrs = append(attrs, attribute{key: "style.border-radius", value: radius})
+	}
+	if framedShapes[node.Shape] {
+		attrs = append(attrs, attribute{key: "style.double-border", value: "true"})
+	}
+	if stackedShapes[node.Shape] {
+		attrs = append(attrs, attribute{key: "style.multiple", value: "true"})
+	}
+
+	style := nodeStyle(node)
+	if style.Fill == "" && (node.Shape == flowchart.NodeShapeForkJoin || node.Shape == flowchart.NodeShapeJunction) {
+		style.Fill = "black"
+	}
+
+	switch node.Shape {
+	case flowchart.NodeShapeForkJoin:
+		attrs = append(attrs,
+			attribute{key: "label", value: `""`},
+			attribute{key: "width", value: "80"},
+			attribute{key: "height", value: "10"})
+	case flowchart.NodeShapeJunction:
+		attrs = append(attrs,
+			attribute{key: "label", value: `""`},
+			attribute{key: "width", value: "16"},
+			attribute{key: "height", value: "16"})
+	case flowchart.NodeShapeStartSmall:
+		attrs = append(attrs, attribute{key: "width", value: "16"}, attribute{key: "height", value: "16"})
+	}
+
+	if style.Fill != "" {
+		attrs = append(attrs, attr("style.fill", style.Fill))
+	}
+	if style.Stroke != "" {
+		attrs = append(attrs, attr("style.stroke", style.Stroke))
+	}
+	if style.Color != "" {
+		attrs = append(attrs, attr("style.font-color", style.Color))
+	}
+	if style.StrokeWidth > 1 {
+		attrs = append(attrs, attribute{key: "style.stroke-width", value: strconv.Itoa(style.StrokeWidth)})
+	}
+	if style.StrokeDash != "" && style.StrokeDash != "0" {
+		attrs = append(attrs, attribute{key: "style.stroke-dash", value: dashSize})
+	}
+
+	return attrs
+}
+
+// nodeStyle returns the style of the class of a node overridden by the properties set in
+// the style of the node.
+func nodeStyle(node *flowchart.Node) (style flowchart.NodeStyle) {
+	if node.Class != nil && node.Class.Style != nil {
+		style = *node.Class.Style
+	}
+
+	if node.Style != nil {
+		if node.Style.Color != "" {
+			style.Color = node.Style.Color
+		}
+		if node.Style.Fill != "" {
+			style.Fill = node.Style.Fill
+		}
+		if node.Style.Stroke != "" {
+			style.Stroke = node.Style.Stroke
+		}
+		if node.Style.StrokeWidth > 0 {
+			style.StrokeWidth = node.Style.StrokeWidth
+		}
+		if node.Style.StrokeDash != "" {
+			style.StrokeDash = node.Style.StrokeDash
+		}
+	}
+
+	return
+}
+
+// linkAttributes returns the connection operator of a link with its arrowhead and line
+// style attributes.
+func linkAttributes(link *flowchart.Link) (operator string, attrs []attribute) {
+	head, tail := link.Head != flowchart.LinkArrowTypeNone, link.Tail != flowchart.LinkArrowTypeNone
+	switch {
+	case head && tail:
+		operator = "<->"
+	case head:
+		operator = "->"
+	case tail:
+		operator = "<-"
+	default:
+		operator = "--"
+	}
+
+	attrs = append(attrs, arrowhead("target-arrowhead", link.Head)...)
+	attrs = append(attrs, arrowhead("source-arrowhead", link.Tail)...)
+
+	switch link.Shape {
+	case flowchart.LinkShapeDotted:
+		attrs = append(attrs, attribute{key: "style.stroke-dash", value: dashSize})
+	case flowchart.LinkShapeThick:
+		attrs = append(attrs, attribute{key: "style.stroke-width", value: thickLinkWidth})
+	case flowchart.LinkShapeInvisible:
+		attrs = append(attrs, attribute{key: "style.opacity", value: "0"})
+	}
+
+	return operator, attrs
+}
+
+// arrowhead returns the attributes of a link end drawn with a marker other than a plain
+// arrow.
+func arrowhead(end string, marker flowchart.LinkArrowType) []attribute {
+	shape, ok := arrowheads[marker]
+	if !ok {
+		return nil
+	}
+
+	attrs := []attribute{{key: end + ".shape", value: shape}}
+	if marker == flowchart.LinkArrowTypeBullet {
+		attrs = append(attrs, attribute{key: end + ".style.filled", value: "true"})
+	}
+	return attrs
+}
@@ -0,0 +1,207 @@
+package d2
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+)
+
+func TestRenderFlowchart(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	f.Title = "Checkout"
+	f.SetDirection(flowchart.FlowchartDirectionLeftRight)
+
+	cart := f.NewNode("Cart")
+	cart.SetShape(flowchart.NodeShapeProcess)
+	pay := f.NewNode("Pay?")
+	pay.SetShape(flowchart.NodeShapeDecision)
+	done := f.NewNode("Done")
+	done.SetShape(flowchart.NodeShapeTerminal)
+
+	payment := f.AddSubgraph("Payment")
+	payment.Direction = flowchart.SubgraphDirectionTopToBottom
+	payment.AddLink(pay, done).SetText("yes")
+	f.NewLink(cart, pay)
+
+	want := `direction: right
+title: "Checkout" {
+  shape: text
+  near: top-center
+  style.font-size: 24
+  style.bold: true
+}
+"0": "Cart"
+"3": "Payment" {
+  direction: down
+  "1": "Pay?" {
+    shape: diamond
+  }
+  "2": "Done" {
+    style.border-radius: 20
+  }
+}
+"0" -> "3"."1"
+"3"."1" -> "3"."2": "yes"
+`
+	if got := RenderFlowchart(f); got != want {
+		t.Errorf("RenderFlowchart() = \n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderFlowchart_NestedSubgraphs(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	a := f.NewNode("A")
+	b := f.NewNode("B")
+	c := f.NewNode("C")
+
+	outer := f.AddSubgraph("Outer")
+	inner := outer.AddSubgraph("Inner")
+	outer.AddLink(a, b)
+	inner.AddLink(b, c)
+	f.AddSubgraph("Empty")
+
+	got := RenderFlowchart(f)
+
+	assertContains(t, got,
+		"\"3\": \"Outer\" {\n  \"0\": \"A\"",
+		"  \"4\": \"Inner\" {\n    \"2\": \"C\"",
+		"\"3\".\"0\" -> \"3\".\"1\"\n",
+		"\"3\".\"1\" -> \"3\".\"4\".\"2\"\n",
+	)
+	if strings.Contains(got, "Empty") {
+		t.Errorf("RenderFlowchart() should skip subgraphs without nodes:\n%s", got)
+	}
+}
+
+func TestRenderFlowchart_Shapes(t *testing.T) {
+	tests := []struct {
+		name     string
+		shape    flowchart.NodeShape
+		label    string
+		contains []string
+	}{
+		{name: "Rectangle", shape: flowchart.NodeShapeProcess, label: `"0": "Node"` + "\n"},
+		{name: "Rounded", shape: flowchart.NodeShapeEvent, contains: []string{"style.border-radius: 8"}},
+		{name: "Database", shape: flowchart.NodeShapeDatabase, contains: []string{"shape: cylinder"}},
+		{name: "Horizontal cylinder", shape: flowchart.NodeShapeStorage, contains: []string{"shape: queue"}},
+		{name: "Double circle", shape: flowchart.NodeShapeStopDouble, contains: []string{"shape: circle", "style.double-border: true"}},
+		{name: "Subprocess", shape: flowchart.NodeShapeSubprocess, contains: []string{"style.double-border: true"}},
+		{name: "Multi document", shape: flowchart.NodeShapeMultiDocument, contains: []string{"shape: document", "style.multiple: true"}},
+		{name: "Fork", shape: flowchart.NodeShapeForkJoin, label: "\"0\": {\n", contains: []string{`label: ""`, "height: 10", `style.fill: "black"`}},
+		{name: "Junction", shape: flowchart.NodeShapeJunction, label: "\"0\": {\n", contains: []string{"shape: circle", "width: 16", `style.fill: "black"`}},
+		{name: "Unmapped", shape: flowchart.NodeShapeExtract, label: `"0": "Node"` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := flowchart.NewFlowchart()
+			f.NewNode("Node").SetShape(tt.shape)
+
+			got := RenderFlowchart(f)
+			if tt.label == "" {
+				tt.label = "\"0\": \"Node\" {\n"
+			}
+			assertContains(t, got, append([]string{tt.label}, tt.contains...)...)
+		})
+	}
+}
+
+func TestRenderFlowchart_Styles(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	class := f.AddClass("warning")
+	class.Style = &flowchart.NodeStyle{Fill: "#ff0", Stroke: "#f00", StrokeWidth: 1}
+
+	node := f.NewNode("Alert")
+	node.SetShape(flowchart.NodeShapeProcess)
+	node.SetClass(class)
+	node.SetStyle(&flowchart.NodeStyle{Fill: "#fa0", Color: "#000", StrokeWidth: 3, StrokeDash: "5 5"})
+
+	want := `direction: down
+"0": "Alert" {
+  style.fill: "#fa0"
+  style.stroke: "#f00"
+  style.font-color: "#000"
+  style.stroke-width: 3
+  style.stroke-dash: 3
+}
+`
+	if got := RenderFlowchart(f); got != want {
+		t.Errorf("RenderFlowchart() = \n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderFlowchart_Links(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(*flowchart.Link)
+		want  string
+	}{
+		{
+			name:  "Arrow",
+			setup: func(l *flowchart.Link) {},
+			want:  "\"0\" -> \"1\"\n",
+		},
+		{
+			name:  "Open",
+			setup: func(l *flowchart.Link) { l.SetHead(flowchart.LinkArrowTypeNone) },
+			want:  "\"0\" -- \"1\"\n",
+		},
+		{
+			name:  "Both ends",
+			setup: func(l *flowchart.Link) { l.SetTail(flowchart.LinkArrowTypeArrow) },
+			want:  "\"0\" <-> \"1\"\n",
+		},
+		{
+			name: "Tail only with cross",
+			setup: func(l *flowchart.Link) {
+				l.SetHead(flowchart.LinkArrowTypeNone).SetTail(flowchart.LinkArrowTypeCross)
+			},
+			want: "\"0\" <- \"1\": {\n  source-arrowhead.shape: cross\n}\n",
+		},
+		{
+			name:  "Bullet",
+			setup: func(l *flowchart.Link) { l.SetHead(flowchart.LinkArrowTypeBullet) },
+			want:  "\"0\" -> \"1\": {\n  target-arrowhead.shape: circle\n  target-arrowhead.style.filled: true\n}\n",
+		},
+		{
+			name:  "Dotted with label",
+			setup: func(l *flowchart.Link) { l.SetShape(flowchart.LinkShapeDotted).SetText("maybe") },
+			want:  "\"0\" -> \"1\": \"maybe\" {\n  style.stroke-dash: 3\n}\n",
+		},
+		{
+			name:  "Thick",
+			setup: func(l *flowchart.Link) { l.SetShape(flowchart.LinkShapeThick) },
+			want:  "\"0\" -> \"1\": {\n  style.stroke-width: 4\n}\n",
+		},
+		{
+			name:  "Invisible",
+			setup: func(l *flowchart.Link) { l.SetShape(flowchart.LinkShapeInvisible) },
+			want:  "\"0\" -> \"1\": {\n  style.opacity: 0\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := flowchart.NewFlowchart()
+			tt.setup(f.NewLink(f.NewNode("A"), f.NewNode("B")))
+
+			assertContains(t, RenderFlowchart(f), tt.want)
+		})
+	}
+}
+
+func TestRenderFlowchartToFile(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	f.NewLink(f.NewNode("A"), f.NewNode("B"))
+	path := filepath.Join(t.TempDir(), "out", "flowchart.d2")
+
+	if err := RenderFlowchartToFile(f, path); err != nil {
+		t.Fatalf("RenderFlowchartToFile() error = %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != RenderFlowchart(f) {
+		t.Errorf("RenderFlowchartToFile() wrote %q", content)
+	}
+}
@@ -0,0 +1,180 @@
+package dot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/class"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+)
+
+const (
+	classNoteIDString string = "note:%d"
+	recordLineEnd     string = `\l`
+	recordSeparator   string = "|"
+	annotationString  string = "«%s»"
+)
+
+// relationArrows maps class relation markers to Graphviz arrowheads.
+var relationArrows = map[string]string{
+	string(class.RelationTypeInheritance):     "empty",
+	string(class.RelationTypeInheritanceLeft): "empty",
+	string(class.RelationTypeComposition):     "diamond",
+	string(class.RelationTypeAggregation):     "odiamond",
+	string(class.RelationTypeAssociation):     "vee",
+	string(class.RelationTypeAssociationLeft): "vee",
+}
+
+// RenderClass returns the class diagram as a DOT document.
+//
+// Classes become record-shaped nodes listing the annotation and name, the fields and the
+// methods in separate compartments. Namespaces become clusters. Relations are edges from
+// the first to the second class whose arrowheads show the relation markers and whose end
+// labels show the cardinalities.
+func RenderClass(cd *class.ClassDiagram) string {
+	w := &writer{}
+	w.open(cd.Title, string(cd.Direction))
+	horizontal := cd.Direction == class.ClassDiagramDirectionLeftRight || cd.Direction == class.ClassDiagramDirectionRightLeft
+
+	written := make(map[*class.Class]bool)
+	writeClass := func(c *class.Class) {
+		written[c] = true
+		w.node(c.Name, attribute{key: "label", value: classRecord(c, horizontal)}, attr("shape", "record"))
+	}
+
+	for _, namespace := range cd.Namespaces() {
+		writeNamespace(w, namespace, writeClass)
+	}
+	for _, c := range cd.Classes() {
+		if !written[c] {
+			writeClass(c)
+		}
+	}
+	for _, relation := range cd.Relations() {
+		for _, c := range []*class.Class{relation.ClassA, relation.ClassB} {
+			if !written[c] {
+				writeClass(c)
+			}
+		}
+	}
+
+	for i, note := range cd.Notes() {
+		w.node(fmt.Sprintf(classNoteIDString, i), attr("label", note.Text), attr("shape", "note"))
+	}
+
+	for _, relation := range cd.Relations() {
+		w.edge(relation.ClassA.Name, relation.ClassB.Name, relationAttributes(relation)...)
+	}
+
+	for i, note := range cd.Notes() {
+		if note.Class != nil {
+			w.edge(fmt.Sprintf(classNoteIDString, i), note.Class.Name, attr("style", "dashed"), attr("dir", "none"))
+		}
+	}
+
+	w.close()
+	return w.String()
+}
+
+// RenderClassToFile writes the class diagram as a DOT document to the path.
+func RenderClassToFile(cd *class.ClassDiagram, path string) error {
+	return utils.RenderToFile(path, RenderClass(cd))
+}
+
+// writeNamespace writes a namespace cluster with its classes and nested namespaces.
+func writeNamespace(w *writer, namespace *class.Namespace, writeClass func(*class.Class)) {
+	w.openCluster(namespace.Name, namespace.Name)
+	for _, c := range namespace.Classes {
+		writeClass(c)
+	}
+	for _, child := range namespace.Children {
+		writeNamespace(w, child, writeClass)
+	}
+	w.close()
+}
+
+// classRecord returns the quoted record label of a class. Records are laid out across the
+// rank direction, so the compartments are wrapped in braces to stack them vertically in
+// top to bottom diagrams.
+func classRecord(c *class.Class, horizontal bool) string {
+	name := c.Name
+	if c.Label != "" {
+		name = c.Label
+	}
+
+	header := escapeRecord(name)
+	if c.Annotation != class.ClassAnnotationNone {
+		annotation := strings.TrimSuffix(strings.TrimPrefix(string(c.Annotation), "<<"), ">>")
+		header = escapeRecord(fmt.Sprintf(annotationString, annotation)) + `\n` + header
+	}
+
+	var fields, methods strings.Builder
+	for _, field := range c.Fields() {
+		fields.WriteString(escapeRecord(strings.TrimSpace(field.String())) + recordLineEnd)
+	}
+	for _, method := range c.Methods() {
+		methods.WriteString(escapeRecord(strings.TrimSpace(method.String())) + recordLineEnd)
+	}
+
+	record := strings.Join([]string{header, fields.String(), methods.String()}, recordSeparator)
+	if !horizontal {
+		record = "{" + record + "}"
+	}
+
+	return `"` + record + `"`
+}
+
+// escapeRecord escapes the characters that structure record labels.
+func escapeRecord(s string) string {
+	var sb strings.Builder
+
+	for _, r := range s {
+		switch r {
+		case '{', '}', '|', '<', '>', '"', '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
+
+// relationAttributes returns the arrowhead, style and label attributes of a relation.
+func relationAttributes(relation *class.Relation) []attribute {
+	head, tail := relationArrow(string(relation.RelationToClassB)), relationArrow(string(relation.RelationToClassA))
+
+	var attrs []attribute
+	if head == "none" && tail == "none" {
+		attrs = append(attrs, attr("dir", "none"))
+	} else {
+		attrs = append(attrs, attr("dir", "both"), attr("arrowhead", head), attr("arrowtail", tail))
+	}
+
+	if relation.Link == class.RelationLinkDashed {
+		attrs = append(attrs, attr("style", "dashed"))
+	}
+	if relation.Label != "" {
+		attrs = append(attrs, attr("label", relation.Label))
+	}
+	if relation.CardinalityToClassA != "" {
+		attrs = append(attrs, attr("taillabel", strings.Trim(string(relation.CardinalityToClassA), `"`)))
+	}
+	if relation.CardinalityToClassB != "" {
+		attrs = append(attrs, attr("headlabel", strings.Trim(string(relation.CardinalityToClassB), `"`)))
+	}
+
+	return attrs
+}
+
+// relationArrow returns the Graphviz arrowhead of a relation marker.
+func relationArrow(marker string) string {
+	if arrow, ok := relationArrows[marker]; ok {
+		return arrow
+	}
+	return "none"
+}
@@ -0,0 +1,135 @@
+package dot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/class"
+)
+
+func TestRenderClass(t *testing.T) {
+	cd := class.NewClassDiagram()
+	cd.SetTitle("Zoo")
+	animals := cd.AddNamespace("animals")
+
+	animal := cd.AddClass("Animal", animals)
+	animal.SetAnnotation(class.ClassAnnotationAbstract)
+	animal.AddField("name", "string")
+	method := animal.AddMethod("Speak").SetReturnType("string")
+	method.AddParameter("loud", "bool")
+
+	dog := cd.AddClass("Dog", nil)
+	inheritance := cd.AddRelation(animal, dog)
+	inheritance.RelationToClassA = class.RelationTypeInheritanceLeft
+
+	cd.AddNote("Good boy", dog)
+
+	want := `digraph {
+    rankdir="TB";
+    label="Zoo";
+    labelloc="t";
+    subgraph "cluster_animals" {
+        label="animals";
+        "Animal" [label="{«Abstract»\nAnimal|+string name\l|+Speak(loud:bool) string\l}", shape="record"];
+    }
+    "Dog" [label="{Dog||}", shape="record"];
+    "note:0" [label="Good boy", shape="note"];
+    "Animal" -> "Dog" [dir="both", arrowhead="none", arrowtail="empty"];
+    "note:0" -> "Dog" [style="dashed", dir="none"];
+}
+`
+	if got := RenderClass(cd); got != want {
+		t.Errorf("RenderClass() = \n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderClass_Relations(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(*class.Relation)
+		want  string
+	}{
+		{
+			name:  "Plain link",
+			setup: func(r *class.Relation) {},
+			want:  `"A" -> "B" [dir="none"];`,
+		},
+		{
+			name: "Composition",
+			setup: func(r *class.Relation) {
+				r.RelationToClassA = class.RelationTypeComposition
+			},
+			want: `"A" -> "B" [dir="both", arrowhead="none", arrowtail="diamond"];`,
+		},
+		{
+			name: "Aggregation with cardinalities",
+			setup: func(r *class.Relation) {
+				r.RelationToClassA = class.RelationTypeAggregation
+				r.CardinalityToClassA = class.RelationCardinalityOnlyOne
+				r.CardinalityToClassB = class.RelationCardinalityOneOrMore
+			},
+			want: `"A" -> "B" [dir="both", arrowhead="none", arrowtail="odiamond", taillabel="1", headlabel="1..*"];`,
+		},
+		{
+			name: "Dashed association with label",
+			setup: func(r *class.Relation) {
+				r.RelationToClassB = class.RelationTypeAssociation
+				r.Link = class.RelationLinkDashed
+				r.Label = "uses"
+			},
+			want: `"A" -> "B" [dir="both", arrowhead="vee", arrowtail="none", style="dashed", label="uses"];`,
+		},
+		{
+			name: "Realization",
+			setup: func(r *class.Relation) {
+				r.RelationToClassB = class.RelationTypeInheritance
+				r.Link = class.RelationLinkDashed
+			},
+			want: `"A" -> "B" [dir="both", arrowhead="empty", arrowtail="none", style="dashed"];`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cd := class.NewClassDiagram()
+			tt.setup(cd.AddRelation(cd.AddClass("A", nil), cd.AddClass("B", nil)))
+			assertContains(t, RenderClass(cd), tt.want)
+		})
+	}
+}
+
+func TestRenderClass_Records(t *testing.T) {
+	cd := class.NewClassDiagram()
+	cd.SetDirection(class.ClassDiagramDirectionLeftRight)
+
+	c := cd.AddClass("Map", nil).SetLabel("Map<K|V>")
+	c.AddField("entries", "{}").SetVisibility(class.FieldVisibilityPrivate)
+
+	outer := cd.AddNamespace("outer")
+	inner := outer.AddNamespace("inner")
+	cd.AddClass("Nested", inner)
+
+	external := class.NewClass("External")
+	cd.AddRelation(external, c)
+
+	assertContains(t, RenderClass(cd),
+		`rankdir="LR";`,
+		`"Map" [label="Map\<K\|V\>|-\{\} entries\l|", shape="record"];`,
+		"subgraph \"cluster_outer\" {\n        label=\"outer\";\n        subgraph \"cluster_inner\" {\n            label=\"inner\";\n            \"Nested\"",
+		`"External" [label="External||", shape="record"];`,
+	)
+}
+
+func TestRenderClassToFile(t *testing.T) {
+	cd := class.NewClassDiagram()
+	cd.AddClass("A", nil)
+	path := filepath.Join(t.TempDir(), "class.dot")
+
+	if err := RenderClassToFile(cd, path); err != nil {
+		t.Fatalf("RenderClassToFile() error = %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != RenderClass(cd) {
+		t.Errorf("RenderClassToFile() wrote %q", content)
+	}
+}
@@ -0,0 +1,165 @@
+// Package dot exports diagrams as Graphviz DOT documents.
+//
+// Mermaid concepts without a DOT equivalent are mapped to their nearest counterpart:
+// subgraphs, composite states and namespaces become clusters, node shapes become the
+// closest Graphviz shape and markers become arrowheads. The output is deterministic, so
+// exported files can be committed and diffed.
+package dot
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	graphOpenString   string = "digraph {\n"
+	clusterOpenString string = "subgraph %s {\n"
+	closeString       string = "}\n"
+	graphAttrString   string = "%s=%s;\n"
+	nodeString        string = "%s%s;\n"
+	edgeString        string = "%s -> %s%s;\n"
+	attrString        string = "%s=%s"
+	clusterIDString   string = "cluster_%s"
+	indentation       string = "    "
+)
+
+// rankDirections maps Mermaid directions to Graphviz rank directions.
+var rankDirections = map[string]string{
+	"TB": "TB",
+	"TD": "TB",
+	"BT": "BT",
+	"LR": "LR",
+	"RL": "RL",
+}
+
+// attribute is a Graphviz attribute with its value already quoted.
+type attribute struct {
+	key   string
+	value string
+}
+
+// attr returns an attribute whose value is quoted as a plain string.
+func attr(key string, value string) attribute {
+	return attribute{key: key, value: quote(value)}
+}
+
+// writer builds an indented DOT document.
+type writer struct {
+	sb    strings.Builder
+	depth int
+}
+
+// open writes the graph header and its title and direction attributes.
+func (w *writer) open(title string, direction string) {
+	w.sb.WriteString(graphOpenString)
+	w.depth++
+
+	if rankdir, ok := rankDirections[direction]; ok {
+		w.graphAttr(attr("rankdir", rankdir))
+	}
+	if title != "" {
+		w.graphAttr(attr("label", title))
+		w.graphAttr(attr("labelloc", "t"))
+	}
+}
+
+// close ends the innermost open graph or cluster.
+func (w *writer) close() {
+	w.depth--
+	w.indent()
+	w.sb.WriteString(closeString)
+}
+
+// openCluster starts a cluster subgraph with the given label.
+func (w *writer) openCluster(id string, label string) {
+	w.indent()
+	w.sb.WriteString(fmt.Sprintf(clusterOpenString, quote(fmt.Sprintf(clusterIDString, id))))
+	w.depth++
+	w.graphAttr(attr("label", label))
+}
+
+// graphAttr writes an attribute of the current graph or cluster.
+func (w *writer) graphAttr(a attribute) {
+	w.indent()
+	w.sb.WriteString(fmt.Sprintf(graphAttrString, a.key, a.value))
+}
+
+// node writes a node statement.
+func (w *writer) node(id string, attrs ...attribute) {
+	w.indent()
+	w.sb.WriteString(fmt.Sprintf(nodeString, quote(id), attributeList(attrs)))
+}
+
+// edge writes an edge statement.
+func (w *writer) edge(from string, to string, attrs ...attribute) {
+	w.indent()
+	w.sb.WriteString(fmt.Sprintf(edgeString, quote(from), quote(to), attributeList(attrs)))
+}
+
+// indent writes the indentation of the current depth.
+func (w *writer) indent() {
+	w.sb.WriteString(strings.Repeat(indentation, w.depth))
+}
+
+// String returns the document.
+func (w *writer) String() string {
+	return w.sb.String()
+}
+
+// attributeList formats attributes as a DOT attribute list, or an empty string when
+// there are none.
+func attributeList(attrs []attribute) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+
+	parts := make([]string, len(attrs))
+	for i, a := range attrs {
+		parts[i] = fmt.Sprintf(attrString, a.key, a.value)
+	}
+
+	return " [" + strings.Join(parts, ", ") + "]"
+}
+
+// quote returns s as a DOT quoted string. Line breaks become centered label lines.
+func quote(s string) string {
+	var sb strings.Builder
+
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+
+	return sb.String()
+}
+
+// color converts a CSS colour to a Graphviz colour, expanding the short hexadecimal
+// form that Graphviz does not understand.
+func color(c string) string {
+	c = strings.TrimSpace(c)
+	if len(c) == 4 && c[0] == '#' {
+		return string([]byte{'#', c[1], c[1], c[2], c[2], c[3], c[3]})
+	}
+	return c
+}
+
+// styleList joins Graphviz style flags, ignoring empty ones.
+func styleList(styles ...string) string {
+	var kept []string
+	for _, style := range styles {
+		if style != "" {
+			kept = append(kept, style)
+		}
+	}
+	return strings.Join(kept, ",")
+}
@@ -0,0 +1,90 @@
+package dot
+
+import (
+	"strings"
+	"testing"
+)
+
+// assertContains fails the test for every expected fragment missing from got.
+func assertContains(t *testing.T, got string, fragments ...string) {
+	t.Helper()
+	for _, fragment := range fragments {
+		if !strings.Contains(got, fragment) {
+			t.Errorf("missing %q in:\n%s", fragment, got)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "Plain", s: "Start", want: `"Start"`},
+		{name: "Empty", s: "", want: `""`},
+		{name: "Quotes and backslashes", s: `say "hi" \o/`, want: `"say \"hi\" \\o/"`},
+		{name: "Line breaks", s: "one\r\ntwo", want: `"one\ntwo"`},
+		{name: "Unicode", s: "état", want: `"état"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quote(tt.s); got != tt.want {
+				t.Errorf("quote() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColor(t *testing.T) {
+	tests := []struct {
+		c    string
+		want string
+	}{
+		{c: "#f9f", want: "#ff99ff"},
+		{c: "#FF99FF", want: "#FF99FF"},
+		{c: " red ", want: "red"},
+		{c: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := color(tt.c); got != tt.want {
+			t.Errorf("color(%q) = %q, want %q", tt.c, got, tt.want)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	w := &writer{}
+	w.open("My graph", "TD")
+	w.openCluster("a", "Group")
+	w.node("x", attr("label", "X"), attr("shape", "box"))
+	w.close()
+	w.edge("x", "y")
+	w.close()
+
+	want := `digraph {
+    rankdir="TB";
+    label="My graph";
+    labelloc="t";
+    subgraph "cluster_a" {
+        label="Group";
+        "x" [label="X", shape="box"];
+    }
+    "x" -> "y";
+}
+`
+	if got := w.String(); got != want {
+		t.Errorf("writer = \n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestStyleList(t *testing.T) {
+	if got := styleList("rounded", "", "dashed"); got != "rounded,dashed" {
+		t.Errorf("styleList() = %q", got)
+	}
+	if got := styleList("", ""); got != "" {
+		t.Errorf("styleList() = %q", got)
+	}
+}
@@ -0,0 +1,284 @@
+package dot
+
+import (
+	"strconv"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+)
+
+// nodeShapes maps flowchart node shapes to the nearest Graphviz shape. Shapes missing from
+// the map are drawn as boxes.
+var nodeShapes = map[flowchart.NodeShape]string{
+	flowchart.NodeShapeProcess:          "box",
+	flowchart.NodeShapeEvent:            "box",
+	flowchart.NodeShapeTerminal:         "box",
+	flowchart.NodeShapeSubprocess:       "box",
+	flowchart.NodeShapeDatabase:         "cylinder",
+	flowchart.NodeShapeStart:            "circle",
+	flowchart.NodeShapeOdd:              "cds",
+	flowchart.NodeShapeDecision:         "diamond",
+	flowchart.NodeShapePrepare:          "hexagon",
+	flowchart.NodeShapeInputOutput:      "parallelogram",
+	flowchart.NodeShapeOutputInput:      "parallelogram",
+	flowchart.NodeShapeManualOperation:  "invtrapezoid",
+	flowchart.NodeShapeManual:           "trapezoid",
+	flowchart.NodeShapeStopDouble:       "doublecircle",
+	flowchart.NodeShapeText:             "plaintext",
+	flowchart.NodeShapeCard:             "note",
+	flowchart.NodeShapeStartSmall:       "circle",
+	flowchart.NodeShapeStopFramed:       "doublecircle",
+	flowchart.NodeShapeForkJoin:         "box",
+	flowchart.NodeShapeCollate:          "invtriangle",
+	flowchart.NodeShapeComment:          "plaintext",
+	flowchart.NodeShapeCommentRight:     "plaintext",
+	flowchart.NodeShapeCommentBothSides: "plaintext",
+	flowchart.NodeShapeComLink:          "plaintext",
+	flowchart.NodeShapeDocument:         "note",
+	flowchart.NodeShapeDelay:            "box",
+	flowchart.NodeShapeStorage:          "cylinder",
+	flowchart.NodeShapeDiskStorage:      "cylinder",
+	flowchart.NodeShapeDisplay:          "trapezoid",
+	flowchart.NodeShapeExtract:          "triangle",
+	flowchart.NodeShapeJunction:         "point",
+	flowchart.NodeShapeLinedDocument:    "note",
+	flowchart.NodeShapeLoopLimit:        "house",
+	flowchart.NodeShapeManualFile:       "invtriangle",
+	flowchart.NodeShapeMultiDocument:    "note",
+	flowchart.NodeShapeMultiProcess:     "box3d",
+	flowchart.NodeShapeSummary:          "Mcircle",
+	flowchart.NodeShapeTaggedDocument:   "note",
+}
+
+// roundedShapes are drawn as boxes with rounded corners.
+var roundedShapes = map[flowchart.NodeShape]bool{
+	flowchart.NodeShapeEvent:    true,
+	flowchart.NodeShapeTerminal: true,
+	flowchart.NodeShapeDelay:    true,
+}
+
+// framedShapes are drawn with a second outline.
+var framedShapes = map[flowchart.NodeShape]bool{
+	flowchart.NodeShapeSubprocess:   true,
+	flowchart.NodeShapeLinedProcess: true,
+}
+
+// arrowTypes maps flowchart link markers to Graphviz arrowheads. Graphviz has no cross
+// arrowhead, so crosses are drawn as tees.
+var arrowTypes = map[flowchart.LinkArrowType]string{
+	flowchart.LinkArrowTypeNone:      "none",
+	flowchart.LinkArrowTypeArrow:     "normal",
+	flowchart.LinkArrowTypeLeftArrow: "normal",
+	flowchart.LinkArrowTypeBullet:    "dot",
+	flowchart.LinkArrowTypeCross:     "tee",
+}
+
+// linkStyles maps flowchart link shapes to Graphviz edge styles.
+var linkStyles = map[flowchart.LinkShape]string{
+	flowchart.LinkShapeDotted:    "dashed",
+	flowchart.LinkShapeThick:     "bold",
+	flowchart.LinkShapeInvisible: "invis",
+}
+
+// RenderFlowchart returns the flowchart as a DOT document.
+//
+// Subgraphs become clusters around the nodes their links reference, as returned by
+// Flowchart.NodeSubgraphs. Node styles and classes set the fill, stroke and text colours.
+func RenderFlowchart(f *flowchart.Flowchart) string {
+	w := &writer{}
+	w.open(f.Title, string(f.Direction))
+
+	nodes := flowchartNodes(f)
+	placement := f.NodeSubgraphs()
+
+	members := make(map[*flowchart.Subgraph][]*flowchart.Node)
+	for _, node := range nodes {
+		if subgraph, ok := placement[node]; ok {
+			members[subgraph] = append(members[subgraph], node)
+		} else {
+			w.node(node.ID, nodeAttributes(node)...)
+		}
+	}
+
+	for _, subgraph := range f.Subgraphs() {
+		writeSubgraph(w, subgraph, members)
+	}
+
+	for _, link := range f.Links() {
+		w.edge(link.From.ID, link.To.ID, linkAttributes(link)...)
+	}
+
+	w.close()
+	return w.String()
+}
+
+// RenderFlowchartToFile writes the flowchart as a DOT document to the path.
+func RenderFlowchartToFile(f *flowchart.Flowchart, path string) error {
+	return utils.RenderToFile(path, RenderFlowchart(f))
+}
+
+// flowchartNodes returns the declared nodes followed by the nodes only referenced by links.
+func flowchartNodes(f *flowchart.Flowchart) []*flowchart.Node {
+	nodes := f.Nodes()
+	seen := make(map[*flowchart.Node]bool, len(nodes))
+	for _, node := range nodes {
+		seen[node] = true
+	}
+
+	for _, link := range f.Links() {
+		for _, node := range []*flowchart.Node{link.From, link.To} {
+			if !seen[node] {
+				seen[node] = true
+				nodes = append(nodes, node)
+			}
+		}
+	}
+
+	return nodes
+}
+
+// writeSubgraph writes a subgraph cluster with its nodes and nested subgraphs. Subgraphs
+// without nodes are skipped, as Graphviz does not draw empty clusters.
+func writeSubgraph(w *writer, subgraph *flowchart.Subgraph, members map[*flowchart.Subgraph][]*flowchart.Node) {
+	if !hasMembers(subgraph, members) {
+		return
+	}
+
+	w.openCluster(subgraph.ID, subgraph.Title)
+	for _, node := range members[subgraph] {
+		w.node(node.ID, nodeAttributes(node)...)
+	}
+	for _, nested := range subgraph.Subgraphs() {
+		writeSubgraph(w, nested, members)
+	}
+	w.close()
+}
+
+// hasMembers reports whether the subgraph or a nested subgraph contains a node.
+func hasMembers(subgraph *flowchart.Subgraph, members map[*flowchart.Subgraph][]*flowchart.Node) bool {
+	if len(members[subgraph]) > 0 {
+		return true
+	}
+	for _, nested := range subgraph.Subgraphs() {
+		if hasMembers(nested, members) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeAttributes returns the label, shape and style attributes of a node.
+func nodeAttributes(node *flowchart.Node) []attribute {
+	shape, ok := nodeShapes[node.Shape]
+	if !ok {
+		shape = "box"
+	}
+
+	attrs := []attribute{attr("label", node.Text), attr("shape", shape)}
+	if framedShapes[node.Shape] {
+		attrs = append(attrs, attr("peripheries", "2"))
+	}
+
+	var rounded, filled, dashed string
+	if roundedShapes[node.Shape] {
+		rounded = "rounded"
+	}
+
+	switch node.Shape {
+	case flowchart.NodeShapeForkJoin:
+		attrs[0] = attr("label", "")
+		attrs = append(attrs, attr("height", "0.1"), attr("fillcolor", "black"))
+		filled = "filled"
+	case flowchart.NodeShapeJunction, flowchart.NodeShapeStartSmall:
+		attrs = append(attrs, attr("width", "0.2"))
+	}
+
+	style := nodeStyle(node)
+	if style.Color != "" {
+		attrs = append(attrs, attr("fontcolor", color(style.Color)))
+	}
+	if style.Fill != "" {
+		attrs = append(attrs, attr("fillcolor", color(style.Fill)))
+		filled = "filled"
+	}
+	if style.Stroke != "" {
+		attrs = append(attrs, attr("color", color(style.Stroke)))
+	}
+	if style.StrokeWidth > 1 {
+		attrs = append(attrs, attr("penwidth", strconv.Itoa(style.StrokeWidth)))
+	}
+	if style.StrokeDash != "" && style.StrokeDash != "0" {
+		dashed = "dashed"
+	}
+
+	if s := styleList(rounded, filled, dashed); s != "" {
+		attrs = append(attrs, attr("style", s))
+	}
+
+	return attrs
+}
+
+// nodeStyle returns the style of the class of a node overridden by the properties set in
+// the style of the node.
+func nodeStyle(node *flowchart.Node) (style flowchart.NodeStyle) {
+	if node.Class != nil && node.Class.Style != nil {
+		style = *node.Class.Style
+	}
+
+	if node.Style != nil {
+		if node.Style.Color != "" {
+			style.Color = node.Style.Color
+		}
+		if node.Style.Fill != "" {
+			style.Fill = node.Style.Fill
+		}
+		if node.Style.Stroke != "" {
+			style.Stroke = node.Style.Stroke
+		}
+		if node.Style.StrokeWidth > 0 {
+			style.StrokeWidth = node.Style.StrokeWidth
+		}
+		if node.Style.StrokeDash != "" {
+			style.StrokeDash = node.Style.StrokeDash
+		}
+	}
+
+	return
+}
+
+// linkAttributes returns the label, style and arrowhead attributes of a link.
+func linkAttributes(link *flowchart.Link) []attribute {
+	var attrs []attribute
+
+	if link.Text != "" {
+		attrs = append(attrs, attr("label", link.Text))
+	}
+	if style, ok := linkStyles[link.Shape]; ok {
+		attrs = append(attrs, attr("style", style))
+	}
+	if link.Length > 0 {
+		attrs = append(attrs, attr("minlen", strconv.Itoa(link.Length+1)))
+	}
+
+	head, tail := arrowType(link.Head), arrowType(link.Tail)
+	switch {
+	case head == "none" && tail == "none":
+		attrs = append(attrs, attr("dir", "none"))
+	case tail == "none":
+		if head != "normal" {
+			attrs = append(attrs, attr("arrowhead", head))
+		}
+	default:
+		attrs = append(attrs, attr("dir", "both"), attr("arrowhead", head), attr("arrowtail", tail))
+	}
+
+	return attrs
+}
+
+// arrowType returns the Graphviz arrowhead of a link marker.
+func arrowType(marker flowchart.LinkArrowType) string {
+	if arrow, ok := arrowTypes[marker]; ok {
+		return arrow
+	}
+	return "none"
+}
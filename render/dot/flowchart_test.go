@@ -0,0 +1,197 @@
+package dot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+)
+
+func TestRenderFlowchart(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	f.Title = "Checkout"
+	f.SetDirection(flowchart.FlowchartDirectionLeftRight)
+
+	cart := f.NewNode("Cart")
+	pay := f.NewNode("Pay?")
+	pay.SetShape(flowchart.NodeShapeDecision)
+	done := f.NewNode("Done")
+	done.SetShape(flowchart.NodeShapeTerminal)
+
+	payment := f.AddSubgraph("Payment")
+	payment.AddLink(pay, done).SetText("yes")
+	f.NewLink(cart, pay)
+
+	want := `digraph {
+    rankdir="LR";
+    label="Checkout";
+    labelloc="t";
+    "0" [label="Cart", shape="box"];
+    subgraph "cluster_3" {
+        label="Payment";
+        "1" [label="Pay?", shape="diamond"];
+        "2" [label="Done", shape="box", style="rounded"];
+    }
+    "0" -> "1";
+    "1" -> "2" [label="yes"];
+}
+`
+	if got := RenderFlowchart(f); got != want {
+		t.Errorf("RenderFlowchart() = \n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderFlowchart_NestedSubgraphs(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	a := f.NewNode("A")
+	b := f.NewNode("B")
+	c := f.NewNode("C")
+
+	outer := f.AddSubgraph("Outer")
+	inner := outer.AddSubgraph("Inner")
+	inner.AddLink(a, b)
+	outer.AddLink(b, c)
+	f.AddSubgraph("Empty")
+
+	got := RenderFlowchart(f)
+
+	assertContains(t, got,
+		"    subgraph \"cluster_"+outer.ID+"\" {\n        label=\"Outer\";\n        \"1\" [label=\"B\", shape=\"box\"];\n        \"2\" [label=\"C\", shape=\"box\"];\n        subgraph \"cluster_"+inner.ID+"\" {\n            label=\"Inner\";\n            \"0\"",
+	)
+	if strings.Contains(got, "Empty") {
+		t.Errorf("RenderFlowchart() should skip subgraphs without nodes:\n%s", got)
+	}
+}
+
+func TestRenderFlowchart_NodeShapes(t *testing.T) {
+	tests := []struct {
+		shape flowchart.NodeShape
+		want  string
+	}{
+		{shape: flowchart.NodeShapeProcess, want: `shape="box"]`},
+		{shape: flowchart.NodeShapeEvent, want: `shape="box", style="rounded"]`},
+		{shape: flowchart.NodeShapeSubprocess, want: `shape="box", peripheries="2"]`},
+		{shape: flowchart.NodeShapeDatabase, want: `shape="cylinder"]`},
+		{shape: flowchart.NodeShapeDecision, want: `shape="diamond"]`},
+		{shape: flowchart.NodeShapePrepare, want: `shape="hexagon"]`},
+		{shape: flowchart.NodeShapeInputOutput, want: `shape="parallelogram"]`},
+		{shape: flowchart.NodeShapeManualOperation, want: `shape="invtrapezoid"]`},
+		{shape: flowchart.NodeShapeStopDouble, want: `shape="doublecircle"]`},
+		{shape: flowchart.NodeShapeDocument, want: `shape="note"]`},
+		{shape: flowchart.NodeShapeJunction, want: `shape="point", width="0.2"]`},
+		{shape: flowchart.NodeShapeForkJoin, want: `[label="", shape="box", height="0.1", fillcolor="black", style="filled"]`},
+		{shape: flowchart.NodeShape("unknown"), want: `shape="box"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.shape), func(t *testing.T) {
+			f := flowchart.NewFlowchart()
+			f.NewNode("N").SetShape(tt.shape)
+			assertContains(t, RenderFlowchart(f), tt.want)
+		})
+	}
+}
+
+func TestRenderFlowchart_Styles(t *testing.T) {
+	f := flowchart.NewFlowchart()
+
+	class := f.AddClass("warning")
+	class.Style.Fill = "#ff0"
+	class.Style.Stroke = "#333"
+	class.Style.StrokeWidth = 2
+	f.NewNode("Classed").SetClass(class)
+
+	style := flowchart.NewNodeStyle()
+	style.Color = "white"
+	style.Fill = "#f00"
+	style.StrokeDash = "5 5"
+	f.NewNode("Styled").SetClass(class).SetStyle(style)
+
+	assertContains(t, RenderFlowchart(f),
+		`"0" [label="Classed", shape="box", fillcolor="#ffff00", color="#333333", penwidth="2", style="filled"];`,
+		`"1" [label="Styled", shape="box", fontcolor="white", fillcolor="#ff0000", color="#333333", style="filled,dashed"];`,
+	)
+}
+
+func TestRenderFlowchart_Links(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(*flowchart.Link)
+		want  string
+	}{
+		{
+			name:  "Default arrow",
+			setup: func(l *flowchart.Link) {},
+			want:  `"0" -> "1";`,
+		},
+		{
+			name:  "Dotted",
+			setup: func(l *flowchart.Link) { l.SetShape(flowchart.LinkShapeDotted) },
+			want:  `"0" -> "1" [style="dashed"];`,
+		},
+		{
+			name:  "Thick",
+			setup: func(l *flowchart.Link) { l.SetShape(flowchart.LinkShapeThick) },
+			want:  `"0" -> "1" [style="bold"];`,
+		},
+		{
+			name:  "Invisible",
+			setup: func(l *flowchart.Link) { l.SetShape(flowchart.LinkShapeInvisible) },
+			want:  `"0" -> "1" [style="invis"];`,
+		},
+		{
+			name:  "No arrowheads",
+			setup: func(l *flowchart.Link) { l.SetHead(flowchart.LinkArrowTypeNone) },
+			want:  `"0" -> "1" [dir="none"];`,
+		},
+		{
+			name:  "Bullet head",
+			setup: func(l *flowchart.Link) { l.SetHead(flowchart.LinkArrowTypeBullet) },
+			want:  `"0" -> "1" [arrowhead="dot"];`,
+		},
+		{
+			name: "Bidirectional",
+			setup: func(l *flowchart.Link) {
+				l.SetTail(flowchart.LinkArrowTypeLeftArrow)
+			},
+			want: `"0" -> "1" [dir="both", arrowhead="normal", arrowtail="normal"];`,
+		},
+		{
+			name: "Cross tail with text and length",
+			setup: func(l *flowchart.Link) {
+				l.SetTail(flowchart.LinkArrowTypeCross).SetHead(flowchart.LinkArrowTypeNone).SetText("no").SetLength(2)
+			},
+			want: `"0" -> "1" [label="no", minlen="3", dir="both", arrowhead="none", arrowtail="tee"];`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := flowchart.NewFlowchart()
+			tt.setup(f.NewLink(f.NewNode("A"), f.NewNode("B")))
+			assertContains(t, RenderFlowchart(f), tt.want)
+		})
+	}
+}
+
+func TestRenderFlowchart_UndeclaredNodes(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	f.AddLink(flowchart.NewLink(flowchart.NewNode("x", "X"), flowchart.NewNode("y", "Y")))
+
+	assertContains(t, RenderFlowchart(f), `"x" [label="X", shape="box"];`, `"y" [label="Y", shape="box"];`)
+}
+
+func TestRenderFlowchartToFile(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	f.NewNode("A")
+	path := filepath.Join(t.TempDir(), "out", "flowchart.dot")
+
+	if err := RenderFlowchartToFile(f, path); err != nil {
+		t.Fatalf("RenderFlowchartToFile() error = %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != RenderFlowchart(f) {
+		t.Errorf("RenderFlowchartToFile() wrote %q", content)
+	}
+}
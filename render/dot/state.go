@@ -0,0 +1,237 @@
+package dot
+
+import (
+	"fmt"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/state"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+)
+
+const (
+	noteIDString        string = "%s:note"
+	pseudoStateIDString string = "[*]%s:%s"
+	pseudoStart         string = "start"
+	pseudoEnd           string = "end"
+	pseudoStateDim      string = "0.2"
+)
+
+// stateEdge is an edge of a state diagram collected before the states are written.
+type stateEdge struct {
+	from  string
+	to    string
+	attrs []attribute
+}
+
+// stateExporter converts the states and transitions of a diagram to DOT statements.
+type stateExporter struct {
+	w *writer
+	// pseudo records the initial and final pseudo states used in each composite state;
+	// the nil key is the diagram itself.
+	pseudo   map[*state.State]map[string]bool
+	edges    []stateEdge
+	compound bool
+}
+
+// RenderState returns the state diagram as a DOT document.
+//
+// Composite states become clusters. Start and end states are linked to initial and final
+// pseudo states of their enclosing composite state, drawn as a dot and a bullseye.
+// Transitions to or from a composite state are clipped at its cluster. Notes are linked to
+// their state with a dotted line.
+func RenderState(d *state.Diagram) string {
+	e := &stateExporter{w: &writer{}, pseudo: make(map[*state.State]map[string]bool)}
+	e.w.open(d.Title, "")
+
+	for _, current := range d.States {
+		e.addImplicitTransitions(current, nil)
+	}
+	for _, transition := range d.Transitions {
+		e.addTransition(transition)
+	}
+	for _, current := range d.States {
+		e.addNotes(current)
+	}
+
+	if e.compound {
+		e.w.graphAttr(attr("compound", "true"))
+	}
+
+	for _, current := range d.States {
+		e.writeState(current)
+	}
+	e.writePseudoStates(nil)
+
+	for _, edge := range e.edges {
+		e.w.edge(edge.from, edge.to, edge.attrs...)
+	}
+
+	e.w.close()
+	return e.w.String()
+}
+
+// RenderStateToFile writes the state diagram as a DOT document to the path.
+func RenderStateToFile(d *state.Diagram, path string) error {
+	return utils.RenderToFile(path, RenderState(d))
+}
+
+// addImplicitTransitions adds the transitions from the initial pseudo state and to the
+// final pseudo state declared by start and end states.
+func (e *stateExporter) addImplicitTransitions(current *state.State, parent *state.State) {
+	switch current.Type {
+	case state.StateStart:
+		e.edges = append(e.edges, stateEdge{from: e.pseudoState(parent, pseudoStart), to: current.ID})
+	case state.StateEnd:
+		e.edges = append(e.edges, stateEdge{from: current.ID, to: e.pseudoState(parent, pseudoEnd)})
+	}
+
+	for _, nested := range current.Nested {
+		e.addImplicitTransitions(nested, current)
+	}
+}
+
+// addTransition adds a transition. A nil state is the initial or final pseudo state of
+// the diagram.
+func (e *stateExporter) addTransition(transition *state.Transition) {
+	edge := stateEdge{}
+
+	if transition.From == nil {
+		edge.from = e.pseudoState(nil, pseudoStart)
+	} else {
+		edge.from = anchor(transition.From)
+		if isComposite(transition.From) && !contains(transition.From, transition.To) {
+			edge.attrs = append(edge.attrs, attr("ltail", fmt.Sprintf(clusterIDString, transition.From.ID)))
+			e.compound = true
+		}
+	}
+
+	if transition.To == nil {
+		edge.to = e.pseudoState(nil, pseudoEnd)
+	} else {
+		edge.to = anchor(transition.To)
+		if isComposite(transition.To) && !contains(transition.To, transition.From) {
+			edge.attrs = append(edge.attrs, attr("lhead", fmt.Sprintf(clusterIDString, transition.To.ID)))
+			e.compound = true
+		}
+	}
+
+	if transition.Description != "" {
+		edge.attrs = append(edge.attrs, attr("label", transition.Description))
+	}
+	if transition.Type == state.TransitionDashed {
+		edge.attrs = append(edge.attrs, attr("style", "dashed"))
+	}
+
+	e.edges = append(e.edges, edge)
+}
+
+// addNotes adds the dotted edges linking notes to the state and its nested states.
+func (e *stateExporter) addNotes(current *state.State) {
+	if current.Note != nil {
+		note := fmt.Sprintf(noteIDString, current.ID)
+		edge := stateEdge{from: note, to: anchor(current), attrs: []attribute{attr("style", "dotted"), attr("dir", "none")}}
+		if current.Note.Position == state.NoteRight {
+			edge.from, edge.to = edge.to, edge.from
+		}
+		if isComposite(current) {
+			key := "lhead"
+			if current.Note.Position == state.NoteRight {
+				key = "ltail"
+			}
+			edge.attrs = append(edge.attrs, attr(key, fmt.Sprintf(clusterIDString, current.ID)))
+			e.compound = true
+		}
+		e.edges = append(e.edges, edge)
+	}
+
+	for _, nested := range current.Nested {
+		e.addNotes(nested)
+	}
+}
+
+// pseudoState returns the ID of the initial or final pseudo state of a composite state,
+// or of the diagram when parent is nil, and marks it as used.
+func (e *stateExporter) pseudoState(parent *state.State, kind string) string {
+	if e.pseudo[parent] == nil {
+		e.pseudo[parent] = make(map[string]bool)
+	}
+	e.pseudo[parent][kind] = true
+
+	return pseudoStateID(parent, kind)
+}
+
+// writeState writes a state, or the cluster of a composite state, followed by its note.
+func (e *stateExporter) writeState(current *state.State) {
+	label := current.Description
+	if label == "" {
+		label = current.ID
+	}
+
+	if isComposite(current) {
+		e.w.openCluster(current.ID, label)
+		for _, nested := range current.Nested {
+			e.writeState(nested)
+		}
+		e.writePseudoStates(current)
+		e.w.close()
+	} else {
+		switch current.Type {
+		case state.StateChoice:
+			e.w.node(current.ID, attr("label", ""), attr("shape", "diamond"), attr("width", "0.3"), attr("height", "0.3"))
+		case state.StateFork, state.StateJoin:
+			e.w.node(current.ID, attr("label", ""), attr("shape", "box"), attr("style", "filled"), attr("fillcolor", "black"), attr("width", "1"), attr("height", "0.1"))
+		default:
+			e.w.node(current.ID, attr("label", label), attr("shape", "box"), attr("style", "rounded"))
+		}
+	}
+
+	if current.Note != nil {
+		e.w.node(fmt.Sprintf(noteIDString, current.ID), attr("label", current.Note.Text), attr("shape", "note"))
+	}
+}
+
+// writePseudoStates writes the pseudo states used in a composite state or the diagram.
+func (e *stateExporter) writePseudoStates(parent *state.State) {
+	if e.pseudo[parent][pseudoStart] {
+		e.w.node(pseudoStateID(parent, pseudoStart), attr("label", ""), attr("shape", "point"), attr("width", pseudoStateDim))
+	}
+	if e.pseudo[parent][pseudoEnd] {
+		e.w.node(pseudoStateID(parent, pseudoEnd), attr("label", ""), attr("shape", "point"), attr("width", pseudoStateDim), attr("peripheries", "2"))
+	}
+}
+
+// pseudoStateID returns the node ID of a pseudo state.
+func pseudoStateID(parent *state.State, kind string) string {
+	if parent == nil {
+		return fmt.Sprintf(pseudoStateIDString, kind, "")
+	}
+	return fmt.Sprintf(pseudoStateIDString, kind, parent.ID)
+}
+
+// isComposite reports whether the state is drawn as a cluster. Composite states without
+// nested states are drawn as plain states.
+func isComposite(current *state.State) bool {
+	return len(current.Nested) > 0
+}
+
+// anchor returns the node a transition to or from the state is attached to: the state
+// itself, or the first state nested in a composite state.
+func anchor(current *state.State) string {
+	for isComposite(current) {
+		current = current.Nested[0]
+	}
+	return current.ID
+}
+
+// contains reports whether target is the composite state or is nested in it at any depth.
+// Edges between such states cannot be clipped at the cluster of the composite state.
+func contains(composite *state.State, target *state.State) bool {
+	if target == composite {
+		return true
+	}
+	for _, nested := range composite.Nested {
+		if contains(nested, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package dot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/state"
+)
+
+func TestRenderState(t *testing.T) {
+	d := state.NewDiagram()
+	d.SetTitle("Order")
+	idle := d.AddState("Idle", "Waiting", state.StateStart)
+	busy := d.AddState("Busy", "Processing", state.StateComposite)
+	busy.AddNestedState("Load", "", state.StateStart)
+	busy.AddNestedState("Save", "", state.StateEnd)
+	d.AddTransition(idle, busy, "order")
+	d.AddTransition(busy, nil, "").SetType(state.TransitionDashed)
+
+	want := `digraph {
+    label="Order";
+    labelloc="t";
+    compound="true";
+    "Idle" [label="Waiting", shape="box", style="rounded"];
+    subgraph "cluster_Busy" {
+        label="Processing";
+        "Load" [label="Load", shape="box", style="rounded"];
+        "Save" [label="Save", shape="box", style="rounded"];
+        "[*]start:Busy" [label="", shape="point", width="0.2"];
+        "[*]end:Busy" [label="", shape="point", width="0.2", peripheries="2"];
+    }
+    "[*]start:" [label="", shape="point", width="0.2"];
+    "[*]end:" [label="", shape="point", width="0.2", peripheries="2"];
+    "[*]start:" -> "Idle";
+    "[*]start:Busy" -> "Load";
+    "Save" -> "[*]end:Busy";
+    "Idle" -> "Load" [lhead="cluster_Busy", label="order"];
+    "Load" -> "[*]end:" [ltail="cluster_Busy", style="dashed"];
+}
+`
+	if got := RenderState(d); got != want {
+		t.Errorf("RenderState() = \n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderState_PseudoStates(t *testing.T) {
+	d := state.NewDiagram()
+	choice := d.AddState("check", "", state.StateChoice)
+	fork := d.AddState("split", "", state.StateFork)
+	join := d.AddState("merge", "", state.StateJoin)
+	d.AddTransition(nil, choice, "")
+	d.AddTransition(choice, fork, "")
+	d.AddTransition(fork, join, "")
+
+	got := RenderState(d)
+
+	assertContains(t, got,
+		`"check" [label="", shape="diamond", width="0.3", height="0.3"];`,
+		`"split" [label="", shape="box", style="filled", fillcolor="black", width="1", height="0.1"];`,
+		`"merge" [label="", shape="box", style="filled", fillcolor="black", width="1", height="0.1"];`,
+		`"[*]start:" -> "check";`,
+	)
+	if strings.Contains(got, "compound") || strings.Contains(got, "[*]end:") {
+		t.Errorf("RenderState() should only declare what is used:\n%s", got)
+	}
+}
+
+func TestRenderState_CompositeEdges(t *testing.T) {
+	d := state.NewDiagram()
+	outer := d.AddState("Outer", "", state.StateComposite)
+	inner := outer.AddNestedState("Inner", "", state.StateComposite)
+	leaf := inner.AddNestedState("Leaf", "", state.StateNormal)
+	other := d.AddState("Other", "", state.StateNormal)
+	empty := d.AddState("Empty", "", state.StateComposite)
+
+	d.AddTransition(outer, leaf, "")
+	d.AddTransition(outer, outer, "")
+	d.AddTransition(other, inner, "")
+	d.AddTransition(empty, other, "")
+
+	assertContains(t, RenderState(d),
+		"subgraph \"cluster_Outer\" {\n        label=\"Outer\";\n        subgraph \"cluster_Inner\" {\n            label=\"Inner\";\n            \"Leaf\"",
+		`"Leaf" -> "Leaf";`,
+		`"Other" -> "Leaf" [lhead="cluster_Inner"];`,
+		`"Empty" [label="Empty", shape="box", style="rounded"];`,
+		`"Empty" -> "Other";`,
+	)
+}
+
+func TestRenderState_Notes(t *testing.T) {
+	d := state.NewDiagram()
+	d.AddState("A", "", state.StateNormal).AddNote("left note", state.NoteLeft)
+	composite := d.AddState("B", "", state.StateComposite)
+	composite.AddNestedState("C", "", state.StateNormal)
+	composite.AddNote("right note", state.NoteRight)
+
+	assertContains(t, RenderState(d),
+		`"A:note" [label="left note", shape="note"];`,
+		`"A:note" -> "A" [style="dotted", dir="none"];`,
+		"    }\n    \"B:note\" [label=\"right note\", shape=\"note\"];",
+		`"C" -> "B:note" [style="dotted", dir="none", ltail="cluster_B"];`,
+	)
+}
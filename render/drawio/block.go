@@ -0,0 +1,245 @@
+package drawio
+
+import (
+	"strconv"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/block"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+	"github.com/TyphonHill/go-mermaid/render/layout"
+)
+
+// Spacing of the block grid.
+const (
+	blockGap         float64 = 10
+	containerPadding float64 = 10
+	styleBlockGroup  string  = "container=1;collapsible=0;fillColor=none"
+	styleArrow       string  = "shape=singleArrow"
+	styleDoubleArrow string  = "shape=doubleArrow"
+)
+
+// blockShapes maps block shapes to the nearest draw.io style. Shapes missing from the map
+// are drawn as rectangles.
+var blockShapes = map[string]string{
+	string(block.BlockShapeRoundEdges):    styleRounded,
+	string(block.BlockShapeStadium):       styleStadium,
+	string(block.BlockShapeSubroutine):    styleSubroutine,
+	string(block.BlockShapeCylindrical):   styleCylinder,
+	string(block.BlockShapeCircle):        styleCircle,
+	string(block.BlockShapeAsymmetric):    styleStep,
+	string(block.BlockShapeRhombus):       styleRhombus,
+	string(block.BlockShapeHexagon):       styleHexagon,
+	string(block.BlockShapeParallelogram): styleParallelogram,
+	string(block.BlockShapeTrapezoid):     styleTrapezoid,
+	string(block.BlockShapeTrapezoidAlt):  styleTrapezoid + ";flipV=1",
+	string(block.BlockShapeDoubleCircle):  styleDoubleCircle,
+}
+
+// arrowDirections maps block arrow directions to the draw.io direction of a single arrow.
+var arrowDirections = map[block.BlockArrowDirection]string{
+	block.BlockArrowDirectionLeft: "west",
+	block.BlockArrowDirectionUp:   "north",
+	block.BlockArrowDirectionDown: "south",
+}
+
+// placement is a block positioned relative to the container it is nested in.
+type placement struct {
+	block    *block.Block
+	box      layout.Rect
+	children []placement
+}
+
+// RenderBlock returns the block diagram as a draw.io document.
+//
+// Blocks are placed on a grid following the Mermaid columns: they fill the columns from
+// left to right, wrap to a new row when a block no longer fits, span as many columns as
+// their width and stretch to the height of their row. Without columns, blocks are placed
+// on a single row. Blocks with nested blocks become containers laid out the same way.
+func RenderBlock(d *block.Diagram) string {
+	placements, width, _ := arrange(d.Blocks, d.Columns)
+
+	doc := newDocument(d.Title)
+	writeBlocks(doc, placements, layerCellID, 0)
+
+	for i, link := range d.Links {
+		doc.edge(edgeCellPrefix+strconv.Itoa(i), blockCellID(link.From), blockCellID(link.To), link.Text, style(styleEdge, "endArrow=classic"), nil)
+	}
+
+	return doc.String(width)
+}
+
+// RenderBlockToFile writes the block diagram as a draw.io document to the path.
+func RenderBlockToFile(d *block.Diagram, path string) error {
+	return utils.RenderToFile(path, RenderBlock(d))
+}
+
+// arrange places blocks on a grid with the given number of columns and returns their
+// positions with the size of the grid. Spaces take grid cells but are not returned.
+func arrange(blocks []*block.Block, columns int) (placements []placement, width float64, height float64) {
+	type cell struct {
+		placement
+		span      int
+		column    int
+		row       int
+		minWidth  float64
+		minHeight float64
+	}
+
+	cells := make([]cell, len(blocks))
+	spans := 0
+	for i, b := range blocks {
+		c := cell{placement: placement{block: b}, span: b.Width}
+		if c.span < 1 {
+			c.span = 1
+		}
+		switch {
+		case b.IsSpace:
+		case len(b.Children) > 0:
+			var w, h float64
+			c.children, w, h = arrange(b.Children, b.Columns())
+			c.minWidth, c.minHeight = w+2*containerPadding, h+2*containerPadding
+		default:
+			c.minWidth, c.minHeight = blockSize(b)
+		}
+		cells[i] = c
+		spans += c.span
+	}
+
+	if len(cells) == 0 {
+		return nil, 0, 0
+	}
+	if columns <= 0 {
+		columns = spans
+	}
+
+	column, row := 0, 0
+	for i := range cells {
+		if cells[i].span > columns {
+			cells[i].span = columns
+		}
+		if column+cells[i].span > columns {
+			column, row = 0, row+1
+		}
+		cells[i].column, cells[i].row = column, row
+		column += cells[i].span
+	}
+
+	unit := 0.0
+	rowHeights := make([]float64, row+1)
+	for _, c := range cells {
+		if w := (c.minWidth - float64(c.span-1)*blockGap) / float64(c.span); w > unit {
+			unit = w
+		}
+		if c.minHeight > rowHeights[c.row] {
+			rowHeights[c.row] = c.minHeight
+		}
+	}
+
+	rowOffsets := make([]float64, len(rowHeights))
+	for i := range rowHeights {
+		if i > 0 {
+			rowOffsets[i] = rowOffsets[i-1] + rowHeights[i-1] + blockGap
+		}
+		height = rowOffsets[i] + rowHeights[i]
+	}
+	width = float64(columns)*unit + float64(columns-1)*blockGap
+
+	for _, c := range cells {
+		if c.block.IsSpace {
+			continue
+		}
+		c.box = layout.Rect{
+			X:      float64(c.column) * (unit + blockGap),
+			Y:      rowOffsets[c.row],
+			Width:  float64(c.span)*unit + float64(c.span-1)*blockGap,
+			Height: rowHeights[c.row],
+		}
+		placements = append(placements, c.placement)
+	}
+
+	return placements, width, height
+}
+
+// writeBlocks adds the placed blocks to the document inside the parent cell, shifted by
+// the padding of the parent.
+func writeBlocks(doc *document, placements []placement, parent string, padding float64) {
+	for _, p := range placements {
+		box := p.box
+		box.X += padding
+		box.Y += padding
+
+		if len(p.children) == 0 {
+			doc.vertex(blockCellID(p.block), parent, blockLabel(p.block), blockStyle(p.block), box)
+			continue
+		}
+
+		doc.vertex(blockCellID(p.block), parent, "", style(append([]string{styleBlockGroup}, cssStyle(p.block.Style)...)...), box)
+		writeBlocks(doc, p.children, blockCellID(p.block), containerPadding)
+	}
+}
+
+// blockCellID returns the ID of the cell of a block.
+func blockCellID(b *block.Block) string {
+	if len(b.Children) > 0 {
+		return groupCellPrefix + b.ID
+	}
+	return nodeCellPrefix + b.ID
+}
+
+// blockLabel returns the label of a block, which is its ID when it has no text.
+func blockLabel(b *block.Block) string {
+	if b.Text == "" {
+		return b.ID
+	}
+	return b.Text
+}
+
+// blockSize returns the size of a block large enough for its label and shape.
+func blockSize(b *block.Block) (width float64, height float64) {
+	width, height = textSize(blockLabel(b))
+
+	switch {
+	case b.IsArrow():
+		width += 2 * paddingX
+	case b.Shape == block.BlockShapeCircle, b.Shape == block.BlockShapeDoubleCircle:
+		if width < height {
+			width = height
+		}
+		height = width
+	case b.Shape == block.BlockShapeRhombus:
+		width, height = width*1.5, height*1.5
+	case b.Shape == block.BlockShapeHexagon, b.Shape == block.BlockShapeParallelogram,
+		b.Shape == block.BlockShapeTrapezoid, b.Shape == block.BlockShapeTrapezoidAlt:
+		width += 2 * paddingX
+	}
+	return width, height
+}
+
+// blockStyle returns the draw.io style of a block: its shape followed by its CSS style.
+func blockStyle(b *block.Block) string {
+	var shape string
+	if b.IsArrow() {
+		shape = arrowStyle(b.ArrowDirections())
+	} else if shape = blockShapes[string(b.Shape)]; shape == "" {
+		shape = styleRectangle
+	}
+
+	return style(append([]string{shape, "whiteSpace=wrap"}, cssStyle(b.Style)...)...)
+}
+
+// arrowStyle returns the draw.io arrow shape pointing in the given directions.
+func arrowStyle(directions []block.BlockArrowDirection) string {
+	has := make(map[block.BlockArrowDirection]bool, len(directions))
+	for _, direction := range directions {
+		has[direction] = true
+	}
+
+	switch {
+	case has[block.BlockArrowDirectionX] || has[block.BlockArrowDirectionLeft] && has[block.BlockArrowDirectionRight]:
+		return styleDoubleArrow
+	case has[block.BlockArrowDirectionY] || has[block.BlockArrowDirectionUp] && has[block.BlockArrowDirectionDown]:
+		return styleDoubleArrow + ";direction=north"
+	case len(directions) > 0 && arrowDirections[directions[0]] != "":
+		return styleArrow + ";direction=" + arrowDirections[directions[0]]
+	}
+	return styleArrow
+}
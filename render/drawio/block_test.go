@@ -0,0 +1,175 @@
+package drawio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/block"
+	"github.com/TyphonHill/go-mermaid/render/layout"
+)
+
+func TestArrange(t *testing.T) {
+	a := block.NewBlock("a", "A")
+	b := block.NewBlock("b", "A much longer label")
+	wide := block.NewBlock("wide", "W").SetWidth(2)
+	space := &block.Block{IsSpace: true}
+
+	unit := 19*charWidth + 2*paddingX
+	row := lineHeight + 2*paddingY
+
+	tests := []struct {
+		name       string
+		blocks     []*block.Block
+		columns    int
+		want       map[string]layout.Rect
+		wantWidth  float64
+		wantHeight float64
+	}{
+		{
+			name:    "Single row without columns",
+			blocks:  []*block.Block{a, space, b},
+			columns: 0,
+			want: map[string]layout.Rect{
+				"a": {X: 0, Y: 0, Width: unit, Height: row},
+				"b": {X: 2 * (unit + blockGap), Y: 0, Width: unit, Height: row},
+			},
+			wantWidth:  3*unit + 2*blockGap,
+			wantHeight: row,
+		},
+		{
+			name:    "Wrapping and spanning",
+			blocks:  []*block.Block{a, b, wide},
+			columns: 2,
+			want: map[string]layout.Rect{
+				"a":    {X: 0, Y: 0, Width: unit, Height: row},
+				"b":    {X: unit + blockGap, Y: 0, Width: unit, Height: row},
+				"wide": {X: 0, Y: row + blockGap, Width: 2*unit + blockGap, Height: row},
+			},
+			wantWidth:  2*unit + blockGap,
+			wantHeight: 2*row + blockGap,
+		},
+		{
+			name:    "Wide block wraps",
+			blocks:  []*block.Block{b, wide},
+			columns: 2,
+			want: map[string]layout.Rect{
+				"b":    {X: 0, Y: 0, Width: unit, Height: row},
+				"wide": {X: 0, Y: row + blockGap, Width: 2*unit + blockGap, Height: row},
+			},
+			wantWidth:  2*unit + blockGap,
+			wantHeight: 2*row + blockGap,
+		},
+		{
+			name:    "Span limited to the columns",
+			blocks:  []*block.Block{b, wide},
+			columns: 1,
+			want: map[string]layout.Rect{
+				"b":    {X: 0, Y: 0, Width: unit, Height: row},
+				"wide": {X: 0, Y: row + blockGap, Width: unit, Height: row},
+			},
+			wantWidth:  unit,
+			wantHeight: 2*row + blockGap,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			placements, width, height := arrange(tt.blocks, tt.columns)
+
+			if width != tt.wantWidth || height != tt.wantHeight {
+				t.Errorf("size = %v x %v, want %v x %v", width, height, tt.wantWidth, tt.wantHeight)
+			}
+			if len(placements) != len(tt.want) {
+				t.Fatalf("got %d placements, want %d", len(placements), len(tt.want))
+			}
+			for _, p := range placements {
+				if want := tt.want[p.block.ID]; p.box != want {
+					t.Errorf("block %s at %+v, want %+v", p.block.ID, p.box, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderBlock(t *testing.T) {
+	d := block.NewDiagram()
+	d.SetTitle("Services")
+	d.SetColumns(2)
+
+	frontend := d.AddBlock("Frontend").SetShape(block.BlockShapeRoundEdges)
+	backend := d.AddBlock("Backend").SetColumns(2).SetStyle("stroke:#333")
+	api := backend.AddBlock("API")
+	db := backend.AddBlock("").SetShape(block.BlockShapeCylindrical).SetStyle("fill:#f9f")
+	d.AddLink(frontend, api).SetText("REST")
+
+	file, cells := parse(t, RenderBlock(d))
+
+	if file.Diagram.Name != "Services" {
+		t.Errorf("page name = %q, want Services", file.Diagram.Name)
+	}
+
+	group := cells["group-"+backend.ID]
+	if group.Parent != layerCellID || group.Value != "" || group.Style != styleBlockGroup+";strokeColor=#333333;" {
+		t.Errorf("unexpected container: %+v", group)
+	}
+	if g := group.Geometry; g.Y != titleHeight || g.X <= cells["node-"+frontend.ID].Geometry.X {
+		t.Errorf("container should be the second block of the first row: %+v", g)
+	}
+
+	if c := cells["node-"+frontend.ID]; c.Style != styleRounded+";whiteSpace=wrap;" || c.Geometry.Height != group.Geometry.Height {
+		t.Errorf("blocks should stretch to the height of their row: %+v %+v", c, c.Geometry)
+	}
+
+	apiCell, dbCell := cells["node-"+api.ID], cells["node-"+db.ID]
+	if apiCell.Parent != group.ID || dbCell.Parent != group.ID {
+		t.Errorf("nested blocks should be in their container: %q, %q", apiCell.Parent, dbCell.Parent)
+	}
+	if apiCell.Geometry.X != containerPadding || apiCell.Geometry.Y != containerPadding {
+		t.Errorf("nested blocks should be placed inside the padding: %+v", apiCell.Geometry)
+	}
+	if dbCell.Value != db.ID || dbCell.Style != styleCylinder+";whiteSpace=wrap;fillColor=#ff99ff;" {
+		t.Errorf("unexpected block without text: %+v", dbCell)
+	}
+
+	if edge := cells["edge-0"]; edge.Source != "node-"+frontend.ID || edge.Target != "node-"+api.ID || edge.Value != "REST" {
+		t.Errorf("unexpected edge: %+v", edge)
+	}
+}
+
+func TestArrowStyle(t *testing.T) {
+	tests := []struct {
+		name       string
+		directions []block.BlockArrowDirection
+		want       string
+	}{
+		{name: "Default", want: styleArrow},
+		{name: "Right", directions: []block.BlockArrowDirection{block.BlockArrowDirectionRight}, want: styleArrow},
+		{name: "Left", directions: []block.BlockArrowDirection{block.BlockArrowDirectionLeft}, want: styleArrow + ";direction=west"},
+		{name: "Down then up", directions: []block.BlockArrowDirection{block.BlockArrowDirectionDown, block.BlockArrowDirectionUp}, want: styleDoubleArrow + ";direction=north"},
+		{name: "Horizontal", directions: []block.BlockArrowDirection{block.BlockArrowDirectionX}, want: styleDoubleArrow},
+		{name: "Vertical", directions: []block.BlockArrowDirection{block.BlockArrowDirectionY}, want: styleDoubleArrow + ";direction=north"},
+		{name: "First direction", directions: []block.BlockArrowDirection{block.BlockArrowDirectionUp, block.BlockArrowDirectionRight}, want: styleArrow + ";direction=north"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := arrowStyle(tt.directions); got != tt.want {
+				t.Errorf("arrowStyle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderBlockToFile(t *testing.T) {
+	d := block.NewDiagram()
+	d.AddBlock("A")
+	path := filepath.Join(t.TempDir(), "block.drawio")
+
+	if err := RenderBlockToFile(d, path); err != nil {
+		t.Fatalf("RenderBlockToFile() error = %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != RenderBlock(d) {
+		t.Errorf("RenderBlockToFile() wrote %q", content)
+	}
+}
@@ -0,0 +1,306 @@
+// Package drawio exports flowcharts and block diagrams as draw.io documents.
+//
+// Documents are uncompressed mxfile XML containing a single mxGraphModel page, which
+// draw.io and diagrams.net open directly. Subgraphs and nested blocks become containers,
+// node shapes become the closest draw.io shape and node styles become style keys.
+// Flowcharts get initial coordinates from the layered layout of the layout package and
+// block diagrams from a grid following the Mermaid columns. The following constructs have
+// no draw.io equivalent and are approximated or dropped:
+//
+//   - Diagram configuration, themes, classDef names and link lengths are not exported.
+//   - Shapes without a draw.io counterpart, such as stacked rectangles and lightning
+//     bolts, are drawn as rectangles.
+//   - Dash patterns are exported as the draw.io default dash.
+//   - Block arrows pointing in more than one direction use the first direction, unless
+//     they point both ways along one axis.
+package drawio
+
+import (
+	"encoding/xml"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/render/layout"
+)
+
+const (
+	host            string = "go-mermaid"
+	defaultPageName string = "Page-1"
+	rootCellID      string = "0"
+	layerCellID     string = "1"
+	titleCellID     string = "title"
+	nodeCellPrefix  string = "node-"
+	groupCellPrefix string = "group-"
+	edgeCellPrefix  string = "edge-"
+	gridSize        int    = 10
+)
+
+// Text metrics used to size shapes, matching the draw.io default font.
+const (
+	charWidth     float64 = 7
+	lineHeight    float64 = 17
+	paddingX      float64 = 20
+	paddingY      float64 = 12
+	minWidth      float64 = 60
+	titleHeight   float64 = 30
+	titleFontSize string  = "18"
+)
+
+// Styles shared by flowcharts and block diagrams.
+const (
+	styleRectangle     string = "rounded=0"
+	styleRounded       string = "rounded=1"
+	styleStadium       string = "rounded=1;arcSize=50"
+	styleCircle        string = "ellipse;aspect=fixed"
+	styleDoubleCircle  string = "ellipse;shape=doubleEllipse;aspect=fixed"
+	styleCylinder      string = "shape=cylinder3;boundedLbl=1;backgroundOutline=1;size=15"
+	styleSubroutine    string = "shape=process;backgroundOutline=1"
+	styleRhombus       string = "rhombus"
+	styleHexagon       string = "shape=hexagon;perimeter=hexagonPerimeter2;fixedSize=1"
+	styleParallelogram string = "shape=parallelogram;perimeter=parallelogramPerimeter;fixedSize=1"
+	styleTrapezoid     string = "shape=trapezoid;perimeter=trapezoidPerimeter;fixedSize=1"
+	styleStep          string = "shape=step;perimeter=stepPerimeter;fixedSize=1"
+	styleText          string = "text;strokeColor=none;fillColor=none"
+	styleContainer     string = "container=1;collapsible=0;verticalAlign=top;fillColor=#ffffde;strokeColor=#aaaa33"
+	styleTitle         string = "text;strokeColor=none;fillColor=none;fontStyle=1;fontSize=" + titleFontSize
+	styleEdge          string = "html=0"
+)
+
+// mxFile is the root element of a draw.io document.
+type mxFile struct {
+	XMLName xml.Name  `xml:"mxfile"`
+	Host    string    `xml:"host,attr"`
+	Diagram mxDiagram `xml:"diagram"`
+}
+
+// mxDiagram is a page of a draw.io document.
+type mxDiagram struct {
+	ID    string       `xml:"id,attr"`
+	Name  string       `xml:"name,attr"`
+	Model mxGraphModel `xml:"mxGraphModel"`
+}
+
+// mxGraphModel holds the cells of a page.
+type mxGraphModel struct {
+	Grid     int      `xml:"grid,attr"`
+	GridSize int      `xml:"gridSize,attr"`
+	Cells    []mxCell `xml:"root>mxCell"`
+}
+
+// mxCell is a vertex, an edge or one of the two root cells of a page.
+type mxCell struct {
+	ID       string      `xml:"id,attr"`
+	Value    string      `xml:"value,attr,omitempty"`
+	Style    string      `xml:"style,attr,omitempty"`
+	Vertex   string      `xml:"vertex,attr,omitempty"`
+	Edge     string      `xml:"edge,attr,omitempty"`
+	Parent   string      `xml:"parent,attr,omitempty"`
+	Source   string      `xml:"source,attr,omitempty"`
+	Target   string      `xml:"target,attr,omitempty"`
+	Geometry *mxGeometry `xml:"mxGeometry"`
+}
+
+// mxGeometry is the position of a vertex relative to its parent, or the waypoints of an
+// edge.
+type mxGeometry struct {
+	X        float64   `xml:"x,attr,omitempty"`
+	Y        float64   `xml:"y,attr,omitempty"`
+	Width    float64   `xml:"width,attr,omitempty"`
+	Height   float64   `xml:"height,attr,omitempty"`
+	Relative string    `xml:"relative,attr,omitempty"`
+	As       string    `xml:"as,attr"`
+	Points   *mxPoints `xml:"Array"`
+}
+
+// mxPoints is the list of waypoints of an edge.
+type mxPoints struct {
+	As     string    `xml:"as,attr"`
+	Points []mxPoint `xml:"mxPoint"`
+}
+
+// mxPoint is an edge waypoint.
+type mxPoint struct {
+	X float64 `xml:"x,attr"`
+	Y float64 `xml:"y,attr"`
+}
+
+// document collects the cells of a page below the two root cells.
+type document struct {
+	title string
+	cells []mxCell
+	// offset moves the top level cells below the title.
+	offset float64
+}
+
+// newDocument returns an empty document for a diagram with the given title.
+func newDocument(title string) *document {
+	d := &document{title: title}
+	if title != "" {
+		d.offset = titleHeight
+	}
+	return d
+}
+
+// vertex adds a vertex at a position relative to its parent cell.
+func (d *document) vertex(id string, parent string, value string, style string, box layout.Rect) {
+	if parent == layerCellID {
+		box.Y += d.offset
+	}
+
+	d.cells = append(d.cells, mxCell{
+		ID:     id,
+		Value:  label(value),
+		Style:  style,
+		Vertex: "1",
+		Parent: parent,
+		Geometry: &mxGeometry{
+			X:      round(box.X),
+			Y:      round(box.Y),
+			Width:  round(box.Width),
+			Height: round(box.Height),
+			As:     "geometry",
+		},
+	})
+}
+
+// edge adds an edge between two cells, with waypoints given in page coordinates.
+func (d *document) edge(id string, source string, target string, value string, style string, points []layout.Point) {
+	geometry := &mxGeometry{Relative: "1", As: "geometry"}
+	if len(points) > 0 {
+		geometry.Points = &mxPoints{As: "points"}
+		for _, p := range points {
+			geometry.Points.Points = append(geometry.Points.Points, mxPoint{X: round(p.X), Y: round(p.Y + d.offset)})
+		}
+	}
+
+	d.cells = append(d.cells, mxCell{
+		ID:       id,
+		Value:    label(value),
+		Style:    style,
+		Edge:     "1",
+		Parent:   layerCellID,
+		Source:   source,
+		Target:   target,
+		Geometry: geometry,
+	})
+}
+
+// String returns the document as draw.io XML, with the title centred above a diagram of
+// the given width.
+func (d *document) String(width float64) string {
+	name := defaultPageName
+	cells := []mxCell{{ID: rootCellID}, {ID: layerCellID, Parent: rootCellID}}
+	if d.title != "" {
+		name = d.title
+		cells = append(cells, mxCell{
+			ID:       titleCellID,
+			Value:    label(d.title),
+			Style:    styleTitle,
+			Vertex:   "1",
+			Parent:   layerCellID,
+			Geometry: &mxGeometry{Width: round(math.Max(width, textWidth(d.title))), Height: titleHeight, As: "geometry"},
+		})
+	}
+
+	file := mxFile{
+		Host: host,
+		Diagram: mxDiagram{
+			ID:   host,
+			Name: name,
+			Model: mxGraphModel{
+				Grid:     1,
+				GridSize: gridSize,
+				Cells:    append(cells, d.cells...),
+			},
+		},
+	}
+
+	// The document only holds strings and numbers, which always marshal.
+	out, _ := xml.MarshalIndent(file, "", "  ")
+	return xml.Header + string(out) + "\n"
+}
+
+// style joins style entries into a draw.io style string.
+func style(entries ...string) string {
+	var kept []string
+	for _, entry := range entries {
+		if entry != "" {
+			kept = append(kept, entry)
+		}
+	}
+	if len(kept) == 0 {
+		return ""
+	}
+	return strings.Join(kept, ";") + ";"
+}
+
+// label returns a cell value with HTML line breaks turned into new lines.
+func label(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "<br>", "\n")
+	return strings.ReplaceAll(s, "<br/>", "\n")
+}
+
+// textWidth returns the estimated width of the longest line of a label.
+func textWidth(s string) float64 {
+	longest := 0
+	for _, line := range strings.Split(label(s), "\n") {
+		if n := len([]rune(line)); n > longest {
+			longest = n
+		}
+	}
+	return float64(longest) * charWidth
+}
+
+// textSize returns the size of a shape large enough for its label.
+func textSize(s string) (width float64, height float64) {
+	lines := strings.Count(label(s), "\n") + 1
+	width = math.Max(textWidth(s)+2*paddingX, minWidth)
+	height = float64(lines)*lineHeight + 2*paddingY
+	return width, height
+}
+
+// round rounds a coordinate to a whole number, keeping the XML free of long fractions.
+func round(v float64) float64 {
+	return math.Round(v)
+}
+
+// cssStyle converts CSS declarations such as "fill:#f9f,stroke-width:2px" to draw.io
+// style entries. Unsupported properties are ignored.
+func cssStyle(css string) (entries []string) {
+	for _, declaration := range strings.FieldsFunc(css, func(r rune) bool { return r == ',' || r == ';' }) {
+		property, value, ok := strings.Cut(declaration, ":")
+		if !ok {
+			continue
+		}
+		property, value = strings.TrimSpace(property), strings.TrimSpace(value)
+
+		switch property {
+		case "fill":
+			entries = append(entries, "fillColor="+color(value))
+		case "stroke":
+			entries = append(entries, "strokeColor="+color(value))
+		case "color":
+			entries = append(entries, "fontColor="+color(value))
+		case "stroke-width":
+			if width, err := strconv.Atoi(strings.TrimSuffix(value, "px")); err == nil && width > 0 {
+				entries = append(entries, "strokeWidth="+strconv.Itoa(width))
+			}
+		case "stroke-dasharray":
+			if value != "0" && value != "none" {
+				entries = append(entries, "dashed=1")
+			}
+		}
+	}
+	return entries
+}
+
+// color expands short hexadecimal colours such as "#f9f", which draw.io does not parse.
+func color(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) == 4 && value[0] == '#' {
+		return string([]byte{'#', value[1], value[1], value[2], value[2], value[3], value[3]})
+	}
+	return value
+}
@@ -0,0 +1,150 @@
+package drawio
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/render/layout"
+)
+
+// parse decodes a draw.io document and returns its cells by ID.
+func parse(t *testing.T, document string) (file mxFile, cells map[string]mxCell) {
+	t.Helper()
+	if !strings.HasPrefix(document, xml.Header) {
+		t.Errorf("document should start with the XML header:\n%s", document)
+	}
+	if err := xml.Unmarshal([]byte(document), &file); err != nil {
+		t.Fatalf("invalid document: %v\n%s", err, document)
+	}
+
+	cells = make(map[string]mxCell)
+	for _, cell := range file.Diagram.Model.Cells {
+		if _, ok := cells[cell.ID]; ok {
+			t.Errorf("duplicate cell ID %q", cell.ID)
+		}
+		cells[cell.ID] = cell
+	}
+	return file, cells
+}
+
+func TestDocument(t *testing.T) {
+	d := newDocument("Title")
+	d.vertex("a", layerCellID, "one<br>two", "rounded=0;", layout.Rect{X: 10.4, Y: 20.6, Width: 80, Height: 40})
+	d.vertex("b", "a", "nested", "", layout.Rect{X: 5, Y: 5, Width: 20, Height: 10})
+	d.edge("e", "a", "b", "", "", []layout.Point{{X: 1, Y: 2}})
+
+	file, cells := parse(t, d.String(100))
+
+	if file.Host != host || file.Diagram.Name != "Title" || file.Diagram.Model.GridSize != gridSize {
+		t.Errorf("unexpected document attributes: %+v", file)
+	}
+
+	ids := make([]string, len(file.Diagram.Model.Cells))
+	for i, cell := range file.Diagram.Model.Cells {
+		ids[i] = cell.ID
+	}
+	if want := []string{rootCellID, layerCellID, titleCellID, "a", "b", "e"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("cells = %v, want %v", ids, want)
+	}
+
+	if title := cells[titleCellID]; title.Value != "Title" || title.Geometry.Width != 100 || title.Geometry.Height != titleHeight {
+		t.Errorf("unexpected title cell: %+v %+v", title, title.Geometry)
+	}
+
+	a := cells["a"]
+	if a.Value != "one\ntwo" || a.Vertex != "1" || a.Parent != layerCellID {
+		t.Errorf("unexpected vertex: %+v", a)
+	}
+	if g := a.Geometry; g.X != 10 || g.Y != 21+titleHeight || g.Width != 80 || g.Height != 40 {
+		t.Errorf("top level vertices should be moved below the title: %+v", g)
+	}
+	if g := cells["b"].Geometry; g.X != 5 || g.Y != 5 {
+		t.Errorf("nested vertices should keep their relative position: %+v", g)
+	}
+
+	e := cells["e"]
+	if e.Edge != "1" || e.Source != "a" || e.Target != "b" || e.Geometry.Relative != "1" {
+		t.Errorf("unexpected edge: %+v", e)
+	}
+	if points := e.Geometry.Points.Points; len(points) != 1 || points[0].X != 1 || points[0].Y != 2+titleHeight {
+		t.Errorf("unexpected waypoints: %+v", points)
+	}
+}
+
+func TestDocument_WithoutTitle(t *testing.T) {
+	d := newDocument("")
+	d.vertex("a", layerCellID, "A", "", layout.Rect{Y: 5, Width: 10, Height: 10})
+
+	file, cells := parse(t, d.String(10))
+
+	if file.Diagram.Name != defaultPageName {
+		t.Errorf("page name = %q, want %q", file.Diagram.Name, defaultPageName)
+	}
+	if _, ok := cells[titleCellID]; ok {
+		t.Errorf("document without title should have no title cell")
+	}
+	if y := cells["a"].Geometry.Y; y != 5 {
+		t.Errorf("vertex y = %v, want 5", y)
+	}
+}
+
+func TestStyle(t *testing.T) {
+	if got := style("rounded=0", "", "fillColor=#fff"); got != "rounded=0;fillColor=#fff;" {
+		t.Errorf("style() = %q", got)
+	}
+	if got := style(); got != "" {
+		t.Errorf("style() = %q, want empty", got)
+	}
+}
+
+func TestTextSize(t *testing.T) {
+	tests := []struct {
+		s          string
+		wantWidth  float64
+		wantHeight float64
+	}{
+		{s: "", wantWidth: minWidth, wantHeight: lineHeight + 2*paddingY},
+		{s: "OK", wantWidth: minWidth, wantHeight: lineHeight + 2*paddingY},
+		{s: "A rather long label", wantWidth: 19*charWidth + 2*paddingX, wantHeight: lineHeight + 2*paddingY},
+		{s: "one<br>two\nthree", wantWidth: 5*charWidth + 2*paddingX, wantHeight: 3*lineHeight + 2*paddingY},
+	}
+
+	for _, tt := range tests {
+		width, height := textSize(tt.s)
+		if width != tt.wantWidth || height != tt.wantHeight {
+			t.Errorf("textSize(%q) = %v, %v, want %v, %v", tt.s, width, height, tt.wantWidth, tt.wantHeight)
+		}
+	}
+}
+
+func TestColor(t *testing.T) {
+	tests := []struct {
+		c    string
+		want string
+	}{
+		{c: "#f9f", want: "#ff99ff"},
+		{c: "#FF99FF", want: "#FF99FF"},
+		{c: " red ", want: "red"},
+		{c: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := color(tt.c); got != tt.want {
+			t.Errorf("color(%q) = %q, want %q", tt.c, got, tt.want)
+		}
+	}
+}
+
+func TestCSSStyle(t *testing.T) {
+	got := cssStyle("fill:#f9f, stroke:#333;stroke-width:4px,color:white,stroke-dasharray: 5 5,opacity:0.5,invalid")
+	want := []string{"fillColor=#ff99ff", "strokeColor=#333333", "strokeWidth=4", "fontColor=white", "dashed=1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cssStyle() = %v, want %v", got, want)
+	}
+
+	if got := cssStyle("stroke-dasharray:none,stroke-width:thick"); len(got) != 0 {
+		t.Errorf("cssStyle() = %v, want no entries", got)
+	}
+}
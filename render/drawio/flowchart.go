@@ -0,0 +1,300 @@
+package drawio
+
+import (
+	"strconv"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+	"github.com/TyphonHill/go-mermaid/render/layout"
+)
+
+// Sizes of the shapes drawn without a label.
+const (
+	barWidth   float64 = 80
+	barHeight  float64 = 10
+	dotSize    float64 = 14
+	thickWidth string  = "3"
+)
+
+// nodeShapes maps flowchart node shapes to the nearest draw.io style. Shapes missing from
+// the map are drawn as rectangles.
+var nodeShapes = map[flowchart.NodeShape]string{
+	flowchart.NodeShapeEvent:            styleRounded,
+	flowchart.NodeShapeTerminal:         styleStadium,
+	flowchart.NodeShapeSubprocess:       styleSubroutine,
+	flowchart.NodeShapeDatabase:         styleCylinder,
+	flowchart.NodeShapeStart:            styleCircle,
+	flowchart.NodeShapeOdd:              styleStep,
+	flowchart.NodeShapeDecision:         styleRhombus,
+	flowchart.NodeShapePrepare:          styleHexagon,
+	flowchart.NodeShapeInputOutput:      styleParallelogram,
+	flowchart.NodeShapeOutputInput:      styleParallelogram + ";flipH=1",
+	flowchart.NodeShapeManualOperation:  styleTrapezoid,
+	flowchart.NodeShapeManual:           styleTrapezoid + ";flipV=1",
+	flowchart.NodeShapeStopDouble:       styleDoubleCircle,
+	flowchart.NodeShapeText:             styleText,
+	flowchart.NodeShapeCard:             "shape=card",
+	flowchart.NodeShapeLinedProcess:     styleSubroutine,
+	flowchart.NodeShapeStartSmall:       styleCircle,
+	flowchart.NodeShapeStopFramed:       styleDoubleCircle,
+	flowchart.NodeShapeCollate:          "shape=collate",
+	flowchart.NodeShapeComment:          "shape=mxgraph.flowchart.annotation_1;align=left;spacingLeft=10",
+	flowchart.NodeShapeCommentRight:     "shape=mxgraph.flowchart.annotation_1;flipH=1;align=right;spacingRight=10",
+	flowchart.NodeShapeCommentBothSides: styleText,
+	flowchart.NodeShapeDocument:         "shape=document;boundedLbl=1",
+	flowchart.NodeShapeDelay:            "shape=delay",
+	flowchart.NodeShapeStorage:          styleCylinder + ";direction=south",
+	flowchart.NodeShapeDiskStorage:      styleCylinder,
+	flowchart.NodeShapeDisplay:          "shape=display",
+	flowchart.NodeShapeExtract:          "triangle;direction=north",
+	flowchart.NodeShapeInternalStorage:  "shape=internalStorage;backgroundOutline=1",
+	flowchart.NodeShapeJunction:         styleCircle,
+	flowchart.NodeShapeLinedDocument:    "shape=document;boundedLbl=1",
+	flowchart.NodeShapeLoopLimit:        "shape=loopLimit",
+	flowchart.NodeShapeManualFile:       "triangle;direction=south",
+	flowchart.NodeShapeManualInput:      "shape=manualInput",
+	flowchart.NodeShapeMultiDocument:    "shape=mxgraph.flowchart.multi-document",
+	flowchart.NodeShapePaperTape:        "shape=tape",
+	flowchart.NodeShapeStoredData:       "shape=dataStorage",
+	flowchart.NodeShapeSummary:          "shape=sumEllipse;perimeter=ellipsePerimeter",
+	flowchart.NodeShapeTaggedDocument:   "shape=document;boundedLbl=1",
+}
+
+// squareShapes keep the same width and height.
+var squareShapes = map[flowchart.NodeShape]bool{
+	flowchart.NodeShapeStart:      true,
+	flowchart.NodeShapeStopDouble: true,
+	flowchart.NodeShapeStopFramed: true,
+	flowchart.NodeShapeSummary:    true,
+}
+
+// arrowTypes maps flowchart link markers to draw.io arrows.
+var arrowTypes = map[flowchart.LinkArrowType]string{
+	flowchart.LinkArrowTypeArrow:     "classic",
+	flowchart.LinkArrowTypeLeftArrow: "classic",
+	flowchart.LinkArrowTypeBullet:    "oval",
+	flowchart.LinkArrowTypeCross:     "cross",
+}
+
+// RenderFlowchart lays out the flowchart and returns it as a draw.io document.
+//
+// Subgraphs become containers around the nodes their links reference, as returned by
+// Flowchart.NodeSubgraphs. Nodes are placed with the layered layout of the layout package
+// and links keep the bends of their routes as waypoints, so the document opens in the
+// same arrangement as the SVG renderer draws it.
+func RenderFlowchart(f *flowchart.Flowchart) string {
+	nodes := f.Graph().Nodes()
+	index := make(map[*flowchart.Node]int, len(nodes))
+	g := layout.Graph{Nodes: make([]layout.Node, len(nodes))}
+	for i, node := range nodes {
+		index[node] = i
+		g.Nodes[i].Width, g.Nodes[i].Height = nodeSize(node)
+	}
+
+	subgraphs := buildClusters(f, nodes, &g)
+
+	links := f.Links()
+	for _, link := range links {
+		edge := layout.Edge{From: index[link.From], To: index[link.To], MinLength: 1 + link.Length}
+		if link.Text != "" {
+			edge.LabelWidth, edge.LabelHeight = textWidth(link.Text), lineHeight
+		}
+		g.Edges = append(g.Edges, edge)
+	}
+
+	result := layout.Layout(g, layout.Options{Direction: layout.Direction(f.Direction)})
+
+	// parent returns the cell containing a node or a cluster and the page position of
+	// its origin.
+	parent := func(cluster int) (string, layout.Rect) {
+		if cluster < 0 {
+			return layerCellID, layout.Rect{}
+		}
+		return groupCellPrefix + subgraphs[cluster].ID, result.Clusters[cluster]
+	}
+
+	d := newDocument(f.Title)
+	for c, subgraph := range subgraphs {
+		id, origin := parent(g.Clusters[c].Parent)
+		d.vertex(groupCellPrefix+subgraph.ID, id, subgraph.Title, style(styleContainer, "whiteSpace=wrap"), relative(result.Clusters[c], origin))
+	}
+	for i, node := range nodes {
+		id, origin := parent(g.Nodes[i].Cluster)
+		d.vertex(nodeCellPrefix+node.ID, id, nodeLabel(node), nodeStyle(node), relative(result.Nodes[i], origin))
+	}
+	for i, link := range links {
+		var bends []layout.Point
+		if points := result.Edges[i].Points; len(points) > 2 {
+			bends = points[1 : len(points)-1]
+		}
+		d.edge(edgeCellPrefix+strconv.Itoa(i), nodeCellPrefix+link.From.ID, nodeCellPrefix+link.To.ID, link.Text, linkStyle(link), bends)
+	}
+
+	return d.String(result.Width)
+}
+
+// RenderFlowchartToFile lays out the flowchart and writes it as a draw.io document to the
+// path.
+func RenderFlowchartToFile(f *flowchart.Flowchart, path string) error {
+	return utils.RenderToFile(path, RenderFlowchart(f))
+}
+
+// buildClusters adds a cluster for every subgraph containing a node and assigns the nodes
+// to their cluster. It returns the subgraph of each cluster, parents before children.
+func buildClusters(f *flowchart.Flowchart, nodes []*flowchart.Node, g *layout.Graph) (kept []*flowchart.Subgraph) {
+	var subgraphs []*flowchart.Subgraph
+	index := make(map[*flowchart.Subgraph]int)
+
+	var walk func(children []*flowchart.Subgraph, parent int)
+	walk = func(children []*flowchart.Subgraph, parent int) {
+		for _, subgraph := range children {
+			index[subgraph] = len(subgraphs)
+			subgraphs = append(subgraphs, subgraph)
+			g.Clusters = append(g.Clusters, layout.Cluster{Parent: parent, LabelWidth: textWidth(subgraph.Title), LabelHeight: lineHeight})
+			walk(subgraph.Subgraphs(), index[subgraph])
+		}
+	}
+	walk(f.Subgraphs(), -1)
+
+	placement := f.NodeSubgraphs()
+	for i, node := range nodes {
+		g.Nodes[i].Cluster = -1
+		if subgraph, ok := placement[node]; ok {
+			g.Nodes[i].Cluster = index[subgraph]
+		}
+	}
+
+	for _, i := range g.PruneClusters() {
+		kept = append(kept, subgraphs[i])
+	}
+	return kept
+}
+
+// relative returns the box with its position relative to the origin of its parent.
+func relative(box layout.Rect, origin layout.Rect) layout.Rect {
+	box.X -= origin.X
+	box.Y -= origin.Y
+	return box
+}
+
+// nodeLabel returns the label of a node. Fork, join and junction shapes have no label.
+func nodeLabel(node *flowchart.Node) string {
+	if node.Shape == flowchart.NodeShapeForkJoin || node.Shape == flowchart.NodeShapeJunction {
+		return ""
+	}
+	return node.Text
+}
+
+// nodeSize returns the size of a node large enough for its label and shape.
+func nodeSize(node *flowchart.Node) (width float64, height float64) {
+	switch node.Shape {
+	case flowchart.NodeShapeForkJoin:
+		return barWidth, barHeight
+	case flowchart.NodeShapeJunction, flowchart.NodeShapeStartSmall:
+		return dotSize, dotSize
+	}
+
+	width, height = textSize(node.Text)
+	switch {
+	case squareShapes[node.Shape]:
+		if width < height {
+			width = height
+		}
+		height = width
+	case node.Shape == flowchart.NodeShapeDecision:
+		width, height = width*1.5, height*1.5
+	case node.Shape == flowchart.NodeShapePrepare, node.Shape == flowchart.NodeShapeInputOutput, node.Shape == flowchart.NodeShapeOutputInput:
+		width += 2 * paddingX
+	}
+	return width, height
+}
+
+// nodeStyle returns the draw.io style of a node: its shape followed by its colours.
+func nodeStyle(node *flowchart.Node) string {
+	shape, ok := nodeShapes[node.Shape]
+	if !ok {
+		shape = styleRectangle
+	}
+	entries := []string{shape, "whiteSpace=wrap"}
+
+	s := nodeColors(node)
+	if s.Fill != "" {
+		entries = append(entries, "fillColor="+color(s.Fill))
+	}
+	if s.Stroke != "" {
+		entries = append(entries, "strokeColor="+color(s.Stroke))
+	}
+	if s.Color != "" {
+		entries = append(entries, "fontColor="+color(s.Color))
+	}
+	if s.StrokeWidth > 1 {
+		entries = append(entries, "strokeWidth="+strconv.Itoa(s.StrokeWidth))
+	}
+	if s.StrokeDash != "" && s.StrokeDash != "0" {
+		entries = append(entries, "dashed=1")
+	}
+
+	return style(entries...)
+}
+
+// nodeColors returns the style of the class of a node overridden by the properties set in
+// the style of the node. Fork, join and junction shapes are filled in black by default.
+func nodeColors(node *flowchart.Node) (style flowchart.NodeStyle) {
+	if node.Class != nil && node.Class.Style != nil {
+		style = *node.Class.Style
+	}
+
+	if node.Style != nil {
+		if node.Style.Color != "" {
+			style.Color = node.Style.Color
+		}
+		if node.Style.Fill != "" {
+			style.Fill = node.Style.Fill
+		}
+		if node.Style.Stroke != "" {
+			style.Stroke = node.Style.Stroke
+		}
+		if node.Style.StrokeWidth > 0 {
+			style.StrokeWidth = node.Style.StrokeWidth
+		}
+		if node.Style.StrokeDash != "" {
+			style.StrokeDash = node.Style.StrokeDash
+		}
+	}
+
+	if style.Fill == "" && (node.Shape == flowchart.NodeShapeForkJoin || node.Shape == flowchart.NodeShapeJunction) {
+		style.Fill = "#000000"
+	}
+
+	return
+}
+
+// linkStyle returns the draw.io style of a link: its arrows and line style.
+func linkStyle(link *flowchart.Link) string {
+	entries := []string{styleEdge, "endArrow=" + arrowType(link.Head), "startArrow=" + arrowType(link.Tail)}
+	if link.Head == flowchart.LinkArrowTypeBullet {
+		entries = append(entries, "endFill=1")
+	}
+	if link.Tail == flowchart.LinkArrowTypeBullet {
+		entries = append(entries, "startFill=1")
+	}
+
+	switch link.Shape {
+	case flowchart.LinkShapeDotted:
+		entries = append(entries, "dashed=1")
+	case flowchart.LinkShapeThick:
+		entries = append(entries, "strokeWidth="+thickWidth)
+	case flowchart.LinkShapeInvisible:
+		entries = append(entries, "strokeColor=none", "noLabel=1")
+	}
+
+	return style(entries...)
+}
+
+// arrowType returns the draw.io arrow of a link marker.
+func arrowType(marker flowchart.LinkArrowType) string {
+	if arrow, ok := arrowTypes[marker]; ok {
+		return arrow
+	}
+	return "none"
+}
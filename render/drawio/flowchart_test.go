@@ -0,0 +1,255 @@
+package drawio
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+)
+
+func TestRenderFlowchart(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	f.Title = "Checkout"
+
+	cart := f.NewNode("Cart")
+	pay := f.NewNode("Pay?")
+	pay.SetShape(flowchart.NodeShapeDecision)
+	done := f.NewNode("Done")
+	done.SetShape(flowchart.NodeShapeTerminal)
+
+	payment := f.AddSubgraph("Payment")
+	payment.AddLink(pay, done).SetText("yes")
+	f.NewLink(cart, pay)
+
+	file, cells := parse(t, RenderFlowchart(f))
+
+	if file.Diagram.Name != "Checkout" {
+		t.Errorf("page name = %q, want Checkout", file.Diagram.Name)
+	}
+
+	group := cells["group-"+payment.ID]
+	if group.Value != "Payment" || group.Parent != layerCellID || !strings.Contains(group.Style, "container=1") {
+		t.Errorf("subgraph should be a top level container: %+v", group)
+	}
+
+	if c := cells["node-"+cart.ID]; c.Parent != layerCellID || c.Value != "Cart" {
+		t.Errorf("unexpected cart cell: %+v", c)
+	}
+	for _, node := range []*flowchart.Node{pay, done} {
+		c := cells["node-"+node.ID]
+		if c.Parent != group.ID {
+			t.Errorf("node %s parent = %q, want %q", node.Text, c.Parent, group.ID)
+		}
+		if c.Geometry.X < 0 || c.Geometry.Y < 0 ||
+			c.Geometry.X+c.Geometry.Width > group.Geometry.Width || c.Geometry.Y+c.Geometry.Height > group.Geometry.Height {
+			t.Errorf("node %s at %+v should lie inside its container %+v", node.Text, c.Geometry, group.Geometry)
+		}
+	}
+	if !strings.HasPrefix(cells["node-"+pay.ID].Style, styleRhombus+";") {
+		t.Errorf("decision style = %q", cells["node-"+pay.ID].Style)
+	}
+
+	// Top to bottom: every link goes downwards.
+	cartY := cells["node-"+cart.ID].Geometry.Y
+	payY := group.Geometry.Y + cells["node-"+pay.ID].Geometry.Y
+	doneY := group.Geometry.Y + cells["node-"+done.ID].Geometry.Y
+	if !(cartY < payY && payY < doneY) {
+		t.Errorf("nodes should be layered top to bottom: %v, %v, %v", cartY, payY, doneY)
+	}
+	if cartY < titleHeight {
+		t.Errorf("nodes should be placed below the title, got y = %v", cartY)
+	}
+
+	yes := cells["edge-1"]
+	if yes.Source != "node-"+pay.ID || yes.Target != "node-"+done.ID || yes.Value != "yes" {
+		t.Errorf("unexpected edge: %+v", yes)
+	}
+	if edge := cells["edge-0"]; edge.Source != "node-"+cart.ID || edge.Parent != layerCellID {
+		t.Errorf("unexpected edge: %+v", edge)
+	}
+}
+
+func TestRenderFlowchart_NestedSubgraphs(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	f.SetDirection(flowchart.FlowchartDirectionLeftRight)
+	a := f.NewNode("A")
+	b := f.NewNode("B")
+	c := f.NewNode("C")
+
+	outer := f.AddSubgraph("Outer")
+	inner := outer.AddSubgraph("Inner")
+	outer.AddLink(a, b)
+	inner.AddLink(b, c)
+	f.AddSubgraph("Empty")
+
+	_, cells := parse(t, RenderFlowchart(f))
+
+	if got := cells["group-"+inner.ID].Parent; got != "group-"+outer.ID {
+		t.Errorf("inner container parent = %q", got)
+	}
+	if got := cells["node-"+c.ID].Parent; got != "group-"+inner.ID {
+		t.Errorf("node C parent = %q", got)
+	}
+	for id := range cells {
+		if strings.HasPrefix(id, groupCellPrefix) && cells[id].Value == "Empty" {
+			t.Errorf("subgraphs without nodes should be skipped")
+		}
+	}
+
+	aX := cells["node-"+a.ID].Geometry.X
+	cX := cells["group-"+inner.ID].Geometry.X + cells["node-"+c.ID].Geometry.X
+	if aX >= cX {
+		t.Errorf("left to right layout should place A before C: %v, %v", aX, cX)
+	}
+}
+
+func TestRenderFlowchart_Nodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		shape      flowchart.NodeShape
+		style      *flowchart.NodeStyle
+		wantValue  string
+		wantStyle  string
+		wantSquare bool
+	}{
+		{name: "Rectangle", shape: flowchart.NodeShapeProcess, wantValue: "Node", wantStyle: "rounded=0;whiteSpace=wrap;"},
+		{name: "Unmapped", shape: flowchart.NodeShapeComLink, wantValue: "Node", wantStyle: "rounded=0;whiteSpace=wrap;"},
+		{name: "Cylinder", shape: flowchart.NodeShapeDatabase, wantValue: "Node", wantStyle: styleCylinder + ";whiteSpace=wrap;"},
+		{name: "Circle", shape: flowchart.NodeShapeStart, wantValue: "Node", wantStyle: styleCircle + ";whiteSpace=wrap;", wantSquare: true},
+		{name: "Output", shape: flowchart.NodeShapeOutputInput, wantValue: "Node", wantStyle: styleParallelogram + ";flipH=1;whiteSpace=wrap;"},
+		{name: "Fork", shape: flowchart.NodeShapeForkJoin, wantStyle: "rounded=0;whiteSpace=wrap;fillColor=#000000;"},
+		{name: "Junction", shape: flowchart.NodeShapeJunction, wantStyle: styleCircle + ";whiteSpace=wrap;fillColor=#000000;", wantSquare: true},
+		{
+			name:      "Styled",
+			shape:     flowchart.NodeShapeProcess,
+			style:     &flowchart.NodeStyle{Fill: "#f9f", Stroke: "blue", Color: "#000", StrokeWidth: 2, StrokeDash: "5 5"},
+			wantValue: "Node",
+			wantStyle: "rounded=0;whiteSpace=wrap;fillColor=#ff99ff;strokeColor=blue;fontColor=#000000;strokeWidth=2;dashed=1;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := flowchart.NewFlowchart()
+			node := f.NewNode("Node")
+			node.SetShape(tt.shape)
+			node.Style = tt.style
+
+			_, cells := parse(t, RenderFlowchart(f))
+			cell := cells["node-"+node.ID]
+
+			if cell.Value != tt.wantValue {
+				t.Errorf("value = %q, want %q", cell.Value, tt.wantValue)
+			}
+			if cell.Style != tt.wantStyle {
+				t.Errorf("style = %q, want %q", cell.Style, tt.wantStyle)
+			}
+			if square := cell.Geometry.Width == cell.Geometry.Height; square != tt.wantSquare {
+				t.Errorf("geometry = %+v, square = %v", cell.Geometry, square)
+			}
+		})
+	}
+}
+
+func TestRenderFlowchart_ClassStyle(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	class := f.AddClass("warning")
+	class.Style = &flowchart.NodeStyle{Fill: "#ff0", Stroke: "#f00"}
+	node := f.NewNode("Alert")
+	node.SetShape(flowchart.NodeShapeProcess)
+	node.SetClass(class)
+	node.SetStyle(&flowchart.NodeStyle{Fill: "#fa0"})
+
+	_, cells := parse(t, RenderFlowchart(f))
+
+	if got := cells["node-"+node.ID].Style; got != "rounded=0;whiteSpace=wrap;fillColor=#ffaa00;strokeColor=#ff0000;" {
+		t.Errorf("style = %q", got)
+	}
+}
+
+func TestRenderFlowchart_Links(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(*flowchart.Link)
+		want  string
+	}{
+		{
+			name:  "Arrow",
+			setup: func(l *flowchart.Link) {},
+			want:  "html=0;endArrow=classic;startArrow=none;",
+		},
+		{
+			name:  "Open",
+			setup: func(l *flowchart.Link) { l.SetHead(flowchart.LinkArrowTypeNone) },
+			want:  "html=0;endArrow=none;startArrow=none;",
+		},
+		{
+			name: "Bullet and cross",
+			setup: func(l *flowchart.Link) {
+				l.SetHead(flowchart.LinkArrowTypeBullet).SetTail(flowchart.LinkArrowTypeCross)
+			},
+			want: "html=0;endArrow=oval;startArrow=cross;endFill=1;",
+		},
+		{
+			name:  "Dotted",
+			setup: func(l *flowchart.Link) { l.SetShape(flowchart.LinkShapeDotted) },
+			want:  "html=0;endArrow=classic;startArrow=none;dashed=1;",
+		},
+		{
+			name:  "Thick",
+			setup: func(l *flowchart.Link) { l.SetShape(flowchart.LinkShapeThick) },
+			want:  "html=0;endArrow=classic;startArrow=none;strokeWidth=3;",
+		},
+		{
+			name:  "Invisible",
+			setup: func(l *flowchart.Link) { l.SetShape(flowchart.LinkShapeInvisible) },
+			want:  "html=0;endArrow=classic;startArrow=none;strokeColor=none;noLabel=1;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := flowchart.NewFlowchart()
+			tt.setup(f.NewLink(f.NewNode("A"), f.NewNode("B")))
+
+			_, cells := parse(t, RenderFlowchart(f))
+			if got := cells["edge-0"].Style; got != tt.want {
+				t.Errorf("style = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderFlowchart_Waypoints(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	a := f.NewNode("A")
+	b := f.NewNode("B")
+	c := f.NewNode("C")
+	f.NewLink(a, b)
+	f.NewLink(b, c)
+	f.NewLink(a, c)
+
+	_, cells := parse(t, RenderFlowchart(f))
+
+	if points := cells["edge-0"].Geometry.Points; points != nil {
+		t.Errorf("short links should have no waypoints, got %+v", points)
+	}
+	if points := cells["edge-2"].Geometry.Points; points == nil || len(points.Points) == 0 {
+		t.Errorf("links spanning several layers should keep their bends")
+	}
+}
+
+func TestRenderFlowchartToFile(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	f.NewLink(f.NewNode("A"), f.NewNode("B"))
+	path := filepath.Join(t.TempDir(), "out", "flowchart.drawio")
+
+	if err := RenderFlowchartToFile(f, path); err != nil {
+		t.Fatalf("RenderFlowchartToFile() error = %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != RenderFlowchart(f) {
+		t.Errorf("RenderFlowchartToFile() wrote %q", content)
+	}
+}
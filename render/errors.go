@@ -0,0 +1,94 @@
+package render
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	errorString      string = "%s: %s"
+	parseErrorString string = "%s: parse error on line %d: %s"
+)
+
+// parseErrorPattern matches the parse error header printed by mermaid-cli.
+var parseErrorPattern = regexp.MustCompile(`(?i)parse error on line (\d+):`)
+
+// Error is a failed render. It carries the output of the executable and, for syntax errors,
+// the line of the Mermaid source that mermaid-cli reported.
+type Error struct {
+	// Executable is the command that was run.
+	Executable string
+	// Line is the line of the diagram source reported by a parse error, or 0.
+	Line int
+	// Message is the error reported by the executable.
+	Message string
+	// Output is the complete output of the executable.
+	Output string
+	// Err is the underlying error, such as an *exec.ExitError, exec.ErrNotFound or a
+	// context error.
+	Err error
+}
+
+// Error returns the message of the executable, including the parse error line if any.
+func (e *Error) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf(parseErrorString, e.Executable, e.Line, e.Message)
+	}
+	return fmt.Sprintf(errorString, e.Executable, e.Message)
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// newError builds the error of a failed run from the output of the executable.
+func newError(executable string, output []byte, err error) *Error {
+	e := &Error{Executable: executable, Output: string(output), Err: err}
+	e.Line, e.Message = parseOutput(e.Output)
+	if e.Message == "" {
+		e.Message = err.Error()
+	}
+	return e
+}
+
+// parseOutput extracts the parse error line and the error message from mermaid-cli output.
+//
+// Parse errors are printed as a header with the line number, an excerpt of the source, a
+// caret marking the position and the expected tokens; the message is the expectation.
+// Other errors use the first line starting with "Error".
+func parseOutput(output string) (line int, message string) {
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+
+	for i, current := range lines {
+		match := parseErrorPattern.FindStringSubmatch(current)
+		if match == nil {
+			continue
+		}
+
+		line, _ = strconv.Atoi(match[1])
+		for _, next := range lines[i+1:] {
+			next = strings.TrimSpace(next)
+			if strings.HasPrefix(next, "Expecting") || strings.HasPrefix(next, "Unrecognized") {
+				return line, next
+			}
+		}
+		return line, strings.TrimSpace(current[strings.Index(current, match[0])+len(match[0]):])
+	}
+
+	for _, current := range lines {
+		if current = strings.TrimSpace(current); strings.HasPrefix(current, "Error") {
+			return 0, strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(current, "Error"), ":"))
+		}
+	}
+
+	for i := len(lines) - 1; i >= 0; i-- {
+		if current := strings.TrimSpace(lines[i]); current != "" {
+			return 0, current
+		}
+	}
+
+	return 0, ""
+}
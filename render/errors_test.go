@@ -0,0 +1,88 @@
+package render
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+)
+
+func TestParseOutput(t *testing.T) {
+	tests := []struct {
+		name        string
+		output      string
+		wantLine    int
+		wantMessage string
+	}{
+		{
+			name:        "Parse error with expectation",
+			output:      "Error: Parse error on line 3:\n...    A --> B -->\n---------------^\nExpecting 'AMP', 'ALPHA', got 'EOF'\n",
+			wantLine:    3,
+			wantMessage: "Expecting 'AMP', 'ALPHA', got 'EOF'",
+		},
+		{
+			name:        "Lexical error",
+			output:      "Error: Parse error on line 12:\r\n...\r\n  Unrecognized text.\r\n",
+			wantLine:    12,
+			wantMessage: "Unrecognized text.",
+		},
+		{
+			name:        "Parse error without expectation",
+			output:      "Parse error on line 1: unexpected token\n",
+			wantLine:    1,
+			wantMessage: "unexpected token",
+		},
+		{
+			name:        "Generic error",
+			output:      "Generating single mermaid chart\n\nError: No diagram type detected matching given configuration\n    at detectType\n",
+			wantMessage: "No diagram type detected matching given configuration",
+		},
+		{
+			name:        "Last line",
+			output:      "Generating single mermaid chart\nfailed to launch the browser process\n\n",
+			wantMessage: "failed to launch the browser process",
+		},
+		{
+			name: "Empty output",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, message := parseOutput(tt.output)
+			if line != tt.wantLine || message != tt.wantMessage {
+				t.Errorf("parseOutput() = %d, %q, want %d, %q", line, message, tt.wantLine, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    *Error
+		output string
+		want   string
+	}{
+		{
+			name: "Parse error",
+			err:  newError("mmdc", []byte("Error: Parse error on line 2:\nExpecting 'SEMI'\n"), errors.New("exit status 1")),
+			want: "mmdc: parse error on line 2: Expecting 'SEMI'",
+		},
+		{
+			name: "No output",
+			err:  newError("mmdc", nil, exec.ErrNotFound),
+			want: "mmdc: " + exec.ErrNotFound.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+			if !errors.Is(tt.err, tt.err.Err) {
+				t.Error("Error should unwrap to the underlying error")
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+// Package html exports diagrams as a standalone HTML page rendered in the browser by
+// mermaid.js.
+//
+// The page does not load anything from the network: Mermaid is either referenced by a
+// local script path or embedded in the page. Diagrams are listed with a table of contents
+// and per-diagram anchors, styled with the page theme and can be switched between a light
+// and a dark theme.
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// DefaultScriptPath is the Mermaid script referenced when neither a script path nor
+// embedded script is set.
+const DefaultScriptPath = "mermaid.min.js"
+
+const (
+	defaultPageTitle string = "Diagrams"
+	defaultAnchor    string = "diagram"
+	anchorString     string = "%s-%d"
+)
+
+// secureKeys are the Mermaid settings that diagrams cannot override with their own
+// configuration, so the page theme applies to every diagram.
+var secureKeys = []string{"secure", "securityLevel", "startOnLoad", "maxTextSize", "maxEdges", "theme", "themeVariables", "darkMode"}
+
+var (
+	anchorInvalid = regexp.MustCompile(`[^a-z0-9]+`)
+	scriptEnd     = regexp.MustCompile(`(?i)</script`)
+)
+
+// Page is a standalone HTML page holding one or more diagrams.
+type Page struct {
+	Title      string
+	ScriptPath string
+	Script     []byte
+	Theme      basediagram.Theme
+	DarkTheme  basediagram.Theme
+	// SourceOnly shows the Mermaid syntax of the diagrams without loading mermaid.js.
+	SourceOnly bool
+	// ReloadURL is the URL of a server-sent events stream. The page reloads itself on
+	// every event, if it is set.
+	ReloadURL string
+	// Errors are shown above the diagrams.
+	Errors   []string
+	sections []section
+}
+
+// section is a titled diagram of a page, with the URL its title links to, if not its
+// anchor.
+type section struct {
+	title   string
+	link    string
+	diagram basediagram.Diagram
+}
+
+// NewPage creates an empty page using the default light and dark Mermaid themes.
+func NewPage(title string) *Page {
+	page := &Page{
+		Title:     title,
+		Theme:     basediagram.NewTheme(),
+		DarkTheme: basediagram.NewTheme(),
+	}
+	page.DarkTheme.SetTheme(basediagram.ThemeDark)
+
+	return page
+}
+
+// AddDiagram appends a diagram to the page under the given title.
+func (p *Page) AddDiagram(title string, diagram basediagram.Diagram) *Page {
+	p.sections = append(p.sections, section{title: title, diagram: diagram})
+	return p
+}
+
+// AddLinkedDiagram appends a diagram to the page under a title linking to a URL, such as
+// the URL of its source, instead of its anchor.
+func (p *Page) AddLinkedDiagram(title string, link string, diagram basediagram.Diagram) *Page {
+	p.sections = append(p.sections, section{title: title, link: link, diagram: diagram})
+	return p
+}
+
+// AddError appends an error message shown above the diagrams.
+func (p *Page) AddError(message string) *Page {
+	p.Errors = append(p.Errors, message)
+	return p
+}
+
+// SetReloadURL reloads the page on every event of the server-sent events stream at a URL.
+func (p *Page) SetReloadURL(url string) *Page {
+	p.ReloadURL = url
+	return p
+}
+
+// SetSourceOnly shows the Mermaid syntax of the diagrams instead of rendering them, without
+// loading mermaid.js.
+func (p *Page) SetSourceOnly(sourceOnly bool) *Page {
+	p.SourceOnly = sourceOnly
+	return p
+}
+
+// SetScriptPath loads Mermaid from a local script path, relative to the page.
+func (p *Page) SetScriptPath(path string) *Page {
+	p.ScriptPath = path
+	p.Script = nil
+	return p
+}
+
+// SetScript embeds the Mermaid script in the page, making it fully self-contained.
+func (p *Page) SetScript(script []byte) *Page {
+	p.Script = script
+	p.ScriptPath = ""
+	return p
+}
+
+// SetTheme sets the theme used in light mode.
+func (p *Page) SetTheme(theme basediagram.Theme) *Page {
+	p.Theme = theme.Clone()
+	return p
+}
+
+// SetDarkTheme sets the theme used in dark mode.
+func (p *Page) SetDarkTheme(theme basediagram.Theme) *Page {
+	p.DarkTheme = theme.Clone()
+	return p
+}
+
+// String returns the HTML document.
+func (p *Page) String() string {
+	var buf bytes.Buffer
+	if err := pageTemplate.Execute(&buf, p.data()); err != nil {
+		// The template is static and the data contains no functions or channels, so
+		// execution cannot fail.
+		panic(err)
+	}
+	return buf.String()
+}
+
+// RenderToFile writes the HTML document to the path, creating missing directories.
+func (p *Page) RenderToFile(path string) error {
+	return utils.RenderToFile(path, p.String())
+}
+
+// pageData is the data of the page template.
+type pageData struct {
+	Title      string
+	ScriptPath string
+	Script     template.JS
+	SourceOnly bool
+	ReloadURL  string
+	Errors     []string
+	Themes     map[string]interface{}
+	Diagrams   []diagramData
+}
+
+// diagramData is a diagram of the page template.
+type diagramData struct {
+	Title  string
+	Anchor string
+	Link   string
+	Source string
+}
+
+// data converts the page to template data.
+func (p *Page) data() pageData {
+	data := pageData{
+		Title:      p.Title,
+		ScriptPath: p.ScriptPath,
+		SourceOnly: p.SourceOnly,
+		ReloadURL:  p.ReloadURL,
+		Errors:     p.Errors,
+		Themes: map[string]interface{}{
+			"light": themeConfig(p.Theme),
+			"dark":  themeConfig(p.DarkTheme),
+		},
+	}
+	if data.Title == "" {
+		data.Title = defaultPageTitle
+	}
+
+	if p.Script != nil {
+		data.Script = template.JS(scriptEnd.ReplaceAllString(string(p.Script), `<\/script`))
+	} else if data.ScriptPath == "" {
+		data.ScriptPath = DefaultScriptPath
+	}
+
+	used := make(map[string]bool)
+	for i, s := range p.sections {
+		title := s.title
+		if title == "" {
+			title = fmt.Sprintf("Diagram %d", i+1)
+		}
+		diagram := diagramData{
+			Title:  title,
+			Anchor: uniqueAnchor(title, used),
+			Link:   s.link,
+			Source: strings.TrimSpace(basediagram.StripFence(s.diagram.String())),
+		}
+		if diagram.Link == "" {
+			diagram.Link = "#" + diagram.Anchor
+		}
+		data.Diagrams = append(data.Diagrams, diagram)
+	}
+
+	return data
+}
+
+// themeConfig returns the mermaid.initialize settings applying the theme.
+func themeConfig(theme basediagram.Theme) map[string]interface{} {
+	name := theme.Name
+	if name == "" {
+		name = basediagram.ThemeDefault
+	}
+
+	variables := make(map[string]interface{}, len(theme.Variables))
+	for k, v := range theme.Variables {
+		variables[k] = v
+	}
+
+	return map[string]interface{}{
+		"startOnLoad":    false,
+		"secure":         secureKeys,
+		"theme":          string(name),
+		"themeVariables": variables,
+	}
+}
+
+// uniqueAnchor returns an HTML id derived from the title that is not yet used.
+func uniqueAnchor(title string, used map[string]bool) string {
+	base := strings.Trim(anchorInvalid.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if base == "" {
+		base = defaultAnchor
+	}
+
+	anchor := base
+	for i := 2; used[anchor]; i++ {
+		anchor = fmt.Sprintf(anchorString, base, i)
+	}
+	used[anchor] = true
+
+	return anchor
+}
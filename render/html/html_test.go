@@ -0,0 +1,192 @@
+package html
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/sequence"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+func sampleFlowchart(label string) *flowchart.Flowchart {
+	f := flowchart.NewFlowchart()
+	f.NewNode(label)
+	return f
+}
+
+func TestNewPage(t *testing.T) {
+	page := NewPage("Docs")
+
+	if page.Title != "Docs" || page.Theme.Name != basediagram.ThemeDefault || page.DarkTheme.Name != basediagram.ThemeDark {
+		t.Errorf("NewPage() = %+v", page)
+	}
+}
+
+func TestPage_String(t *testing.T) {
+	fenced := sampleFlowchart("Fenced")
+	fenced.EnableMarkdownFence()
+
+	s := sequence.NewDiagram()
+	s.AddActor("alice", "Alice", sequence.ActorParticipant)
+
+	tests := []struct {
+		name        string
+		page        *Page
+		contains    []string
+		notContains []string
+	}{
+		{
+			name: "Table of contents and anchors",
+			page: NewPage("Architecture").
+				AddDiagram("Request flow", sampleFlowchart("A")).
+				AddDiagram("Request flow", sampleFlowchart("B")).
+				AddDiagram("Login", s),
+			contains: []string{
+				"<title>Architecture</title>",
+				"<h1>Architecture</h1>",
+				`<li><a href="#request-flow">Request flow</a></li>`,
+				`<li><a href="#request-flow-2">Request flow</a></li>`,
+				`<section id="login">`,
+				"sequenceDiagram",
+			},
+		},
+		{
+			name:        "Single diagram without contents",
+			page:        NewPage("").AddDiagram("", sampleFlowchart("A")),
+			contains:    []string{"<title>Diagrams</title>", `<section id="diagram-1">`, "Diagram 1"},
+			notContains: []string{"<nav>"},
+		},
+		{
+			name:        "Markdown fence is stripped",
+			page:        NewPage("Docs").AddDiagram("Fenced", fenced),
+			contains:    []string{"<pre class=\"mermaid\">\n---\n"},
+			notContains: []string{"```"},
+		},
+		{
+			name: "Text is escaped",
+			page: NewPage("<Docs>").AddDiagram("A & B", sampleFlowchart("</pre><script>")),
+			contains: []string{
+				"<title>&lt;Docs&gt;</title>",
+				"A &amp; B",
+				"&lt;/pre&gt;&lt;script&gt;",
+			},
+			notContains: []string{"</pre><script>"},
+		},
+		{
+			name:        "Default script path",
+			page:        NewPage("Docs"),
+			contains:    []string{`<script src="mermaid.min.js"></script>`},
+			notContains: []string{"<nav>", "<section"},
+		},
+		{
+			name:     "Script path",
+			page:     NewPage("Docs").SetScriptPath("assets/mermaid.min.js"),
+			contains: []string{`<script src="assets/mermaid.min.js"></script>`},
+		},
+		{
+			name:        "Embedded script",
+			page:        NewPage("Docs").SetScriptPath("unused.js").SetScript([]byte(`var mermaid = {}; var s = "</script>"; var u = "</SCRIPT>";`)),
+			contains:    []string{`<script>var mermaid = {}; var s = "<\/script>"; var u = "<\/script>";</script>`},
+			notContains: []string{"unused.js", `"</script>"`},
+		},
+		{
+			name: "Source only with errors, links and reload",
+			page: NewPage("Preview").
+				SetSourceOnly(true).
+				SetReloadURL("/events").
+				AddError("broken <spec>").
+				AddLinkedDiagram("flow", "/diagrams/flow.mmd", sampleFlowchart("A")),
+			contains: []string{
+				`<pre class="error">broken &lt;spec&gt;</pre>`,
+				`<h2><a href="/diagrams/flow.mmd">flow</a></h2>`,
+				"<pre>\n---\n",
+				`new EventSource("\/events")`,
+			},
+			notContains: []string{`class="mermaid"`, "<script src=", "mermaid.initialize", `id="theme-toggle"`},
+		},
+		{
+			name:     "No diagram",
+			page:     NewPage("Preview"),
+			contains: []string{"<p>No diagram.</p>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.page.String()
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("String() missing %q in:\n%s", want, got)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("String() should not contain %q in:\n%s", unwanted, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPage_Themes(t *testing.T) {
+	light := basediagram.NewTheme()
+	light.SetTheme(basediagram.ThemeForest).SetPrimaryColor("#ABCDEF")
+	dark := basediagram.NewTheme()
+	dark.SetTheme(basediagram.ThemeDark).SetDarkMode(true)
+
+	page := NewPage("Docs").SetTheme(light).SetDarkTheme(dark)
+	light.SetPrimaryColor("#000000")
+	got := page.String()
+
+	for _, want := range []string{
+		`"light":{"secure":[`,
+		`"theme":"forest","themeVariables":{"primaryColor":"#ABCDEF"}`,
+		`"theme":"dark","themeVariables":{"darkMode":true}`,
+		`"themeVariables","darkMode"]`,
+		`"startOnLoad":false`,
+		"mermaid.initialize(themes[mode])",
+		`id="theme-toggle"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("String() missing %q", want)
+		}
+	}
+}
+
+func TestUniqueAnchor(t *testing.T) {
+	used := make(map[string]bool)
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "Order Service", want: "order-service"},
+		{title: "  Order  service! ", want: "order-service-2"},
+		{title: "Order Service", want: "order-service-3"},
+		{title: "Zähler", want: "z-hler"},
+		{title: "日本", want: "diagram"},
+		{title: "!!!", want: "diagram-2"},
+	}
+
+	for _, tt := range tests {
+		if got := uniqueAnchor(tt.title, used); got != tt.want {
+			t.Errorf("uniqueAnchor(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestPage_RenderToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "site", "index.html")
+	page := NewPage("Docs").AddDiagram("Flow", sampleFlowchart("A"))
+
+	if err := page.RenderToFile(path); err != nil {
+		t.Fatalf("RenderToFile() error = %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil || string(content) != page.String() {
+		t.Errorf("RenderToFile() wrote %q, %v", content, err)
+	}
+}
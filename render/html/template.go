@@ -0,0 +1,103 @@
+package html
+
+import "html/template"
+
+// pageTemplate is the HTML document. Diagram sources are escaped in the <pre> elements
+// and read back by mermaid.js from their text content, unless the page shows them as they
+// are.
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+:root { color-scheme: light; --background: #ffffff; --text: #1f2328; --muted: #59636e; --border: #d1d9e0; }
+:root[data-theme="dark"] { color-scheme: dark; --background: #0d1117; --text: #f0f6fc; --muted: #9198a1; --border: #3d444d; }
+body { margin: 0 auto; max-width: 1200px; padding: 2rem; background: var(--background); color: var(--text); font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; }
+header { display: flex; align-items: center; justify-content: space-between; gap: 1rem; }
+nav ol { color: var(--muted); }
+a { color: inherit; }
+section { border-top: 1px solid var(--border); padding-top: 1rem; }
+pre.mermaid { display: flex; justify-content: center; background: transparent; }
+.error { color: #b00020; white-space: pre-wrap; }
+button { background: transparent; color: var(--text); border: 1px solid var(--border); border-radius: 6px; padding: 0.4rem 0.8rem; cursor: pointer; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+{{- if not .SourceOnly}}
+<button type="button" id="theme-toggle">Toggle dark mode</button>
+{{- end}}
+</header>
+{{- if gt (len .Diagrams) 1}}
+<nav>
+<h2>Contents</h2>
+<ol>
+{{- range .Diagrams}}
+<li><a href="#{{.Anchor}}">{{.Title}}</a></li>
+{{- end}}
+</ol>
+</nav>
+{{- end}}
+<main>
+{{- range .Errors}}
+<pre class="error">{{.}}</pre>
+{{- end}}
+{{- range .Diagrams}}
+<section id="{{.Anchor}}">
+<h2><a href="{{.Link}}">{{.Title}}</a></h2>
+<pre{{if not $.SourceOnly}} class="mermaid"{{end}}>
+{{.Source}}
+</pre>
+</section>
+{{- else}}
+<p>No diagram.</p>
+{{- end}}
+</main>
+{{- if .ReloadURL}}
+<script>
+new EventSource("{{.ReloadURL}}").onmessage = function () { location.reload(); };
+</script>
+{{- end}}
+{{- if .SourceOnly}}
+{{- else if .Script}}
+<script>{{.Script}}</script>
+{{- else}}
+<script src="{{.ScriptPath}}"></script>
+{{- end}}
+{{- if not .SourceOnly}}
+<script>
+(function () {
+  var themes = {{.Themes}};
+  var nodes = Array.prototype.slice.call(document.querySelectorAll("pre.mermaid"));
+  nodes.forEach(function (node) { node.setAttribute("data-source", node.textContent); });
+
+  function render(mode) {
+    document.documentElement.setAttribute("data-theme", mode);
+    nodes.forEach(function (node) {
+      node.removeAttribute("data-processed");
+      node.textContent = node.getAttribute("data-source");
+    });
+    mermaid.initialize(themes[mode]);
+    return mermaid.run({ nodes: nodes });
+  }
+
+  var stored = null;
+  try { stored = localStorage.getItem("gomermaid-theme"); } catch (e) {}
+  var mode = stored || (window.matchMedia && window.matchMedia("(prefers-color-scheme: dark)").matches ? "dark" : "light");
+
+  document.getElementById("theme-toggle").addEventListener("click", function () {
+    mode = mode === "dark" ? "light" : "dark";
+    try { localStorage.setItem("gomermaid-theme", mode); } catch (e) {}
+    render(mode);
+  });
+
+  render(mode);
+})();
+</script>
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,939 @@
+// Package layout computes layered (Sugiyama style) layouts for directed graphs.
+//
+// Nodes are assigned to layers along the layout direction, long edges are routed through
+// dummy nodes, layers are reordered to reduce crossings and nodes are positioned close to
+// their neighbours. The layout is deterministic and independent of the output format, so
+// it is shared by the SVG and text renderers.
+package layout
+
+import "sort"
+
+// Direction is the direction in which layers follow each other.
+type Direction string
+
+// List of layout directions, matching the Mermaid flowchart directions.
+const (
+	TopToBottom Direction = "TB"
+	BottomToTop Direction = "BT"
+	LeftToRight Direction = "LR"
+	RightToLeft Direction = "RL"
+)
+
+// Default spacing used for zero options.
+const (
+	DefaultNodeSpacing    float64 = 40
+	DefaultRankSpacing    float64 = 50
+	DefaultClusterPadding float64 = 12
+	DefaultSelfLoopSize   float64 = 20
+	sweepIterations       int     = 8
+	positionIterations    int     = 6
+)
+
+// Node is a node to lay out.
+type Node struct {
+	Width  float64
+	Height float64
+	// Cluster is the index of the innermost cluster containing the node, or -1.
+	Cluster int
+}
+
+// Edge is a directed edge between two nodes, identified by their index.
+type Edge struct {
+	From int
+	To   int
+	// MinLength is the minimum number of layers the edge spans. Values below 1 mean 1.
+	MinLength int
+	// LabelWidth and LabelHeight reserve space for a label in the middle of the edge.
+	LabelWidth  float64
+	LabelHeight float64
+}
+
+// Cluster is a group of nodes drawn inside a common box, such as a flowchart subgraph.
+type Cluster struct {
+	// Parent is the index of the enclosing cluster, or -1.
+	Parent int
+	// LabelWidth and LabelHeight reserve space for a title at the top of the box.
+	LabelWidth  float64
+	LabelHeight float64
+}
+
+// Graph is the input of a layout.
+type Graph struct {
+	Nodes    []Node
+	Edges    []Edge
+	Clusters []Cluster
+}
+
+// PruneClusters removes the clusters that contain no node, directly or through nested
+// clusters, and renumbers the cluster references of nodes and clusters. It returns the
+// indices the kept clusters had before pruning.
+func (g *Graph) PruneClusters() (kept []int) {
+	used := make([]bool, len(g.Clusters))
+	for _, node := range g.Nodes {
+		for c := node.Cluster; c >= 0 && c < len(g.Clusters) && !used[c]; c = g.Clusters[c].Parent {
+			used[c] = true
+		}
+	}
+
+	mapped := make([]int, len(g.Clusters))
+	for i := range g.Clusters {
+		mapped[i] = -1
+		if used[i] {
+			mapped[i] = len(kept)
+			kept = append(kept, i)
+		}
+	}
+
+	clusters := make([]Cluster, 0, len(kept))
+	for _, i := range kept {
+		cluster := g.Clusters[i]
+		if cluster.Parent >= 0 && cluster.Parent < len(mapped) {
+			cluster.Parent = mapped[cluster.Parent]
+		}
+		clusters = append(clusters, cluster)
+	}
+	g.Clusters = clusters
+
+	for i := range g.Nodes {
+		if c := g.Nodes[i].Cluster; c >= 0 && c < len(mapped) {
+			g.Nodes[i].Cluster = mapped[c]
+		}
+	}
+
+	return kept
+}
+
+// Options controls the spacing and direction of a layout. Zero values use the defaults.
+type Options struct {
+	Direction      Direction
+	NodeSpacing    float64
+	RankSpacing    float64
+	ClusterPadding float64
+	SelfLoopSize   float64
+}
+
+// Point is a position in the layout.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Rect is an axis aligned box given by its top-left corner and size.
+type Rect struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+// Center returns the center of the box.
+func (r Rect) Center() Point {
+	return Point{X: r.X + r.Width/2, Y: r.Y + r.Height/2}
+}
+
+// Route is the path of an edge. Points start at the center of the source node and end at
+// the center of the target node, except for self-loops which start and end on the border.
+type Route struct {
+	Points []Point
+	// Label is the center of the edge label.
+	Label Point
+}
+
+// Result is the outcome of a layout. All coordinates are non-negative.
+type Result struct {
+	Nodes    []Rect
+	Edges    []Route
+	Clusters []Rect
+	Width    float64
+	Height   float64
+}
+
+// vertex is a node of the layered graph, either a real node or a dummy node on a long edge.
+type vertex struct {
+	node     int
+	width    float64
+	height   float64
+	rank     int
+	cluster  int
+	extra    float64
+	x        float64
+	y        float64
+	upper    []int
+	lower    []int
+	position int
+}
+
+// Layout computes the layout of the graph.
+func Layout(g Graph, options Options) Result {
+	options = withDefaults(options)
+	horizontal := options.Direction == LeftToRight || options.Direction == RightToLeft
+
+	size := func(width float64, height float64) (float64, float64) {
+		if horizontal {
+			return height, width
+		}
+		return width, height
+	}
+
+	topCluster := make([]int, len(g.Nodes))
+	for i, node := range g.Nodes {
+		topCluster[i] = rootCluster(g.Clusters, node.Cluster)
+	}
+
+	vertices := make([]*vertex, 0, len(g.Nodes))
+	for i, node := range g.Nodes {
+		width, height := size(node.Width, node.Height)
+		vertices = append(vertices, &vertex{node: i, width: width, height: height, cluster: topCluster[i]})
+	}
+
+	labelled := false
+	loops := make(map[int][]int)
+	edges := make([]int, 0, len(g.Edges))
+	for i, edge := range g.Edges {
+		if edge.From == edge.To {
+			loops[edge.From] = append(loops[edge.From], i)
+			width, _ := size(edge.LabelWidth, edge.LabelHeight)
+			vertices[edge.From].extra += options.SelfLoopSize + width
+			continue
+		}
+		if edge.LabelWidth > 0 || edge.LabelHeight > 0 {
+			labelled = true
+		}
+		edges = append(edges, i)
+	}
+
+	rankScale, rankSpacing := 1, options.RankSpacing
+	if labelled {
+		rankScale, rankSpacing = 2, options.RankSpacing/2
+	}
+
+	reversed := breakCycles(len(g.Nodes), g.Edges, edges)
+	assignRanks(vertices, g.Edges, edges, reversed, rankScale)
+
+	chains := make(map[int][]int, len(edges))
+	labels := make(map[int]int)
+	for _, e := range edges {
+		edge := g.Edges[e]
+		from, to := edge.From, edge.To
+		if reversed[e] {
+			from, to = to, from
+		}
+
+		chain := []int{from}
+		span := vertices[to].rank - vertices[from].rank
+		cluster := -1
+		if topCluster[from] == topCluster[to] {
+			cluster = topCluster[from]
+		}
+		for step := 1; step < span; step++ {
+			dummy := &vertex{node: -1, rank: vertices[from].rank + step, cluster: cluster}
+			if step == span/2 {
+				dummy.width, dummy.height = size(edge.LabelWidth, edge.LabelHeight)
+				labels[e] = len(vertices)
+			}
+			chain = append(chain, len(vertices))
+			vertices = append(vertices, dummy)
+		}
+		chain = append(chain, to)
+
+		for i := 0; i+1 < len(chain); i++ {
+			vertices[chain[i]].lower = append(vertices[chain[i]].lower, chain[i+1])
+			vertices[chain[i+1]].upper = append(vertices[chain[i+1]].upper, chain[i])
+		}
+
+		chains[e] = chain
+	}
+
+	layers := orderLayers(vertices)
+	assignX(vertices, layers, options)
+	separateClusters(vertices, layers, g.Clusters, options)
+	assignY(vertices, layers, rankSpacing)
+
+	result := Result{
+		Nodes:    make([]Rect, len(g.Nodes)),
+		Edges:    make([]Route, len(g.Edges)),
+		Clusters: make([]Rect, len(g.Clusters)),
+	}
+
+	transform := newTransform(options.Direction, vertices)
+
+	for i := range g.Nodes {
+		v := vertices[i]
+		center := transform.point(v.x, v.y)
+		result.Nodes[i] = Rect{X: center.X - g.Nodes[i].Width/2, Y: center.Y - g.Nodes[i].Height/2, Width: g.Nodes[i].Width, Height: g.Nodes[i].Height}
+	}
+
+	for _, e := range edges {
+		chain := chains[e]
+		points := make([]Point, 0, len(chain))
+		for _, index := range chain {
+			points = append(points, transform.point(vertices[index].x, vertices[index].y))
+		}
+		if reversed[e] {
+			for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+				points[i], points[j] = points[j], points[i]
+			}
+		}
+
+		route := Route{Points: points}
+		if index, ok := labels[e]; ok {
+			route.Label = transform.point(vertices[index].x, vertices[index].y)
+		} else {
+			route.Label = midpoint(points)
+		}
+		result.Edges[e] = route
+	}
+
+	for node, indices := range loops {
+		v := vertices[node]
+		offset := v.width / 2
+		for _, e := range indices {
+			labelWidth, _ := size(g.Edges[e].LabelWidth, g.Edges[e].LabelHeight)
+			out := offset + options.SelfLoopSize
+			result.Edges[e] = Route{
+				Points: []Point{
+					transform.point(v.x+offset, v.y-v.height/4),
+					transform.point(v.x+out, v.y-v.height/4),
+					transform.point(v.x+out, v.y+v.height/4),
+					transform.point(v.x+offset, v.y+v.height/4),
+				},
+				Label: transform.point(v.x+out+labelWidth/2, v.y),
+			}
+			offset = out + labelWidth
+		}
+	}
+
+	placeClusters(g, result, options.ClusterPadding)
+	normalize(&result, g)
+
+	return result
+}
+
+// withDefaults replaces zero options with their defaults.
+func withDefaults(options Options) Options {
+	if options.Direction == "" || options.Direction == "TD" {
+		options.Direction = TopToBottom
+	}
+	if options.NodeSpacing <= 0 {
+		options.NodeSpacing = DefaultNodeSpacing
+	}
+	if options.RankSpacing <= 0 {
+		options.RankSpacing = DefaultRankSpacing
+	}
+	if options.ClusterPadding <= 0 {
+		options.ClusterPadding = DefaultClusterPadding
+	}
+	if options.SelfLoopSize <= 0 {
+		options.SelfLoopSize = DefaultSelfLoopSize
+	}
+	return options
+}
+
+// rootCluster returns the outermost cluster containing the cluster, or -1.
+func rootCluster(clusters []Cluster, cluster int) int {
+	for cluster >= 0 && cluster < len(clusters) && clusters[cluster].Parent >= 0 {
+		cluster = clusters[cluster].Parent
+	}
+	if cluster >= len(clusters) {
+		return -1
+	}
+	return cluster
+}
+
+// breakCycles marks the edges to reverse so that the graph becomes acyclic,
+// using a depth-first search in node order.
+func breakCycles(nodeCount int, all []Edge, edges []int) map[int]bool {
+	outgoing := make([][]int, nodeCount)
+	for _, e := range edges {
+		outgoing[all[e].From] = append(outgoing[all[e].From], e)
+	}
+
+	const (
+		unvisited = iota
+		active
+		done
+	)
+	state := make([]int, nodeCount)
+	reversed := make(map[int]bool)
+
+	var visit func(node int)
+	visit = func(node int) {
+		state[node] = active
+		for _, e := range outgoing[node] {
+			switch state[all[e].To] {
+			case active:
+				reversed[e] = true
+			case unvisited:
+				visit(all[e].To)
+			}
+		}
+		state[node] = done
+	}
+
+	for node := 0; node < nodeCount; node++ {
+		if state[node] == unvisited {
+			visit(node)
+		}
+	}
+
+	return reversed
+}
+
+// assignRanks places every real node on a layer using the longest path from the sources,
+// then pulls sources down next to their successors to shorten edges.
+func assignRanks(vertices []*vertex, all []Edge, edges []int, reversed map[int]bool, scale int) {
+	type arc struct{ to, length int }
+
+	nodeCount := len(vertices)
+	outgoing := make([][]arc, nodeCount)
+	inDegree := make([]int, nodeCount)
+
+	for _, e := range edges {
+		from, to := all[e].From, all[e].To
+		if reversed[e] {
+			from, to = to, from
+		}
+		length := all[e].MinLength
+		if length < 1 {
+			length = 1
+		}
+		outgoing[from] = append(outgoing[from], arc{to: to, length: length * scale})
+		inDegree[to]++
+	}
+
+	order := make([]int, 0, nodeCount)
+	ready := make([]int, 0)
+	for node := 0; node < nodeCount; node++ {
+		if inDegree[node] == 0 {
+			ready = append(ready, node)
+		}
+	}
+	remaining := append([]int(nil), inDegree...)
+	for len(ready) > 0 {
+		node := ready[0]
+		ready = ready[1:]
+		order = append(order, node)
+		for _, a := range outgoing[node] {
+			remaining[a.to]--
+			if remaining[a.to] == 0 {
+				ready = append(ready, a.to)
+			}
+		}
+	}
+
+	for _, node := range order {
+		for _, a := range outgoing[node] {
+			if rank := vertices[node].rank + a.length; rank > vertices[a.to].rank {
+				vertices[a.to].rank = rank
+			}
+		}
+	}
+
+	for i := len(order) - 1; i >= 0; i-- {
+		node := order[i]
+		if inDegree[node] > 0 || len(outgoing[node]) == 0 {
+			continue
+		}
+		lowest := -1
+		for _, a := range outgoing[node] {
+			if rank := vertices[a.to].rank - a.length; lowest < 0 || rank < lowest {
+				lowest = rank
+			}
+		}
+		vertices[node].rank = lowest
+	}
+}
+
+// orderLayers groups the vertices by rank and reorders each layer to reduce crossings.
+func orderLayers(vertices []*vertex) [][]int {
+	maxRank := 0
+	for _, v := range vertices {
+		if v.rank > maxRank {
+			maxRank = v.rank
+		}
+	}
+
+	layers := make([][]int, maxRank+1)
+	for index, v := range vertices {
+		v.position = len(layers[v.rank])
+		layers[v.rank] = append(layers[v.rank], index)
+	}
+
+	best := copyLayers(layers)
+	bestCrossings := countCrossings(vertices, layers)
+
+	for iteration := 0; iteration < sweepIterations && bestCrossings > 0; iteration++ {
+		if iteration%2 == 0 {
+			for rank := 1; rank < len(layers); rank++ {
+				sortLayer(vertices, layers[rank], func(v *vertex) []int { return v.upper })
+			}
+		} else {
+			for rank := len(layers) - 2; rank >= 0; rank-- {
+				sortLayer(vertices, layers[rank], func(v *vertex) []int { return v.lower })
+			}
+		}
+
+		if crossings := countCrossings(vertices, layers); crossings < bestCrossings {
+			best, bestCrossings = copyLayers(layers), crossings
+		}
+	}
+
+	for _, layer := range best {
+		for position, index := range layer {
+			vertices[index].position = position
+		}
+	}
+
+	return best
+}
+
+// sortLayer orders the layer by the barycenter of the neighbours returned by adjacent,
+// keeping the vertices of a cluster together.
+func sortLayer(vertices []*vertex, layer []int, adjacent func(*vertex) []int) {
+	barycenter := make(map[int]float64, len(layer))
+	for _, index := range layer {
+		v := vertices[index]
+		neighbours := adjacent(v)
+		if len(neighbours) == 0 {
+			barycenter[index] = float64(v.position)
+			continue
+		}
+		sum := 0.0
+		for _, neighbour := range neighbours {
+			sum += float64(vertices[neighbour].position)
+		}
+		barycenter[index] = sum / float64(len(neighbours))
+	}
+
+	clusterSum := make(map[int]float64)
+	clusterCount := make(map[int]float64)
+	for _, index := range layer {
+		if c := vertices[index].cluster; c >= 0 {
+			clusterSum[c] += barycenter[index]
+			clusterCount[c]++
+		}
+	}
+
+	key := func(index int) float64 {
+		if c := vertices[index].cluster; c >= 0 {
+			return clusterSum[c] / clusterCount[c]
+		}
+		return barycenter[index]
+	}
+
+	sort.SliceStable(layer, func(i, j int) bool {
+		a, b := layer[i], layer[j]
+		if key(a) != key(b) {
+			return key(a) < key(b)
+		}
+		if vertices[a].cluster != vertices[b].cluster {
+			return vertices[a].cluster < vertices[b].cluster
+		}
+		return barycenter[a] < barycenter[b]
+	})
+
+	for position, index := range layer {
+		vertices[index].position = position
+	}
+}
+
+// countCrossings counts the crossings between consecutive layers.
+func countCrossings(vertices []*vertex, layers [][]int) int {
+	crossings := 0
+
+	for rank := 0; rank+1 < len(layers); rank++ {
+		type segment struct{ from, to int }
+		segments := make([]segment, 0)
+		for _, index := range layers[rank] {
+			for _, lower := range vertices[index].lower {
+				segments = append(segments, segment{from: vertices[index].position, to: vertices[lower].position})
+			}
+		}
+
+		for i := 0; i < len(segments); i++ {
+			for j := i + 1; j < len(segments); j++ {
+				a, b := segments[i], segments[j]
+				if (a.from-b.from)*(a.to-b.to) < 0 {
+					crossings++
+				}
+			}
+		}
+	}
+
+	return crossings
+}
+
+// copyLayers returns a copy of the layers.
+func copyLayers(layers [][]int) [][]int {
+	copied := make([][]int, len(layers))
+	for i, layer := range layers {
+		copied[i] = append([]int(nil), layer...)
+	}
+	return copied
+}
+
+// assignX positions the vertices of each layer close to their neighbours while keeping
+// their order and the minimum spacing.
+func assignX(vertices []*vertex, layers [][]int, options Options) {
+	gaps := make([][]float64, len(layers))
+	for rank, layer := range layers {
+		x := 0.0
+		gaps[rank] = make([]float64, len(layer))
+		for i, index := range layer {
+			if i > 0 {
+				gaps[rank][i] = gap(vertices[layer[i-1]], vertices[index], options)
+				x += gaps[rank][i]
+			}
+			vertices[index].x = x
+		}
+	}
+
+	for iteration := 0; iteration < positionIterations; iteration++ {
+		for rank := range layers {
+			if iteration%2 == 0 {
+				place(vertices, layers[rank], gaps[rank], func(v *vertex) []int { return v.upper })
+			} else {
+				place(vertices, layers[len(layers)-1-rank], gaps[len(layers)-1-rank], func(v *vertex) []int { return v.lower })
+			}
+		}
+	}
+
+	for rank := range layers {
+		place(vertices, layers[rank], gaps[rank], func(v *vertex) []int { return append(append([]int(nil), v.upper...), v.lower...) })
+	}
+}
+
+// gap returns the minimum distance between the centers of two neighbouring vertices.
+func gap(left *vertex, right *vertex, options Options) float64 {
+	spacing := options.NodeSpacing
+	if left.node < 0 || right.node < 0 {
+		spacing /= 2
+	}
+	if left.cluster != right.cluster {
+		spacing += 2 * options.ClusterPadding
+	}
+	return left.width/2 + left.extra + right.width/2 + spacing
+}
+
+// place moves the vertices of a layer as close as possible to the mean position of their
+// neighbours, solving the ordered placement with the pool adjacent violators algorithm.
+func place(vertices []*vertex, layer []int, gaps []float64, adjacent func(*vertex) []int) {
+	if len(layer) == 0 {
+		return
+	}
+
+	type block struct {
+		sum   float64
+		count float64
+		size  int
+	}
+
+	offsets := make([]float64, len(layer))
+	blocks := make([]block, 0, len(layer))
+
+	for i, index := range layer {
+		if i > 0 {
+			offsets[i] = offsets[i-1] + gaps[i]
+		}
+
+		v := vertices[index]
+		desired := v.x
+		if neighbours := adjacent(v); len(neighbours) > 0 {
+			sum := 0.0
+			for _, neighbour := range neighbours {
+				sum += vertices[neighbour].x
+			}
+			desired = sum / float64(len(neighbours))
+		}
+
+		blocks = append(blocks, block{sum: desired - offsets[i], count: 1, size: 1})
+		for len(blocks) > 1 {
+			last, previous := blocks[len(blocks)-1], blocks[len(blocks)-2]
+			if previous.sum/previous.count <= last.sum/last.count {
+				break
+			}
+			blocks = blocks[:len(blocks)-1]
+			blocks[len(blocks)-1] = block{sum: previous.sum + last.sum, count: previous.count + last.count, size: previous.size + last.size}
+		}
+	}
+
+	i := 0
+	for _, b := range blocks {
+		value := b.sum / b.count
+		for end := i + b.size; i < end; i++ {
+			vertices[layer[i]].x = value + offsets[i]
+		}
+	}
+}
+
+// assignY positions the layers one after the other.
+func assignY(vertices []*vertex, layers [][]int, rankSpacing float64) {
+	y := 0.0
+	for _, layer := range layers {
+		height := 0.0
+		for _, index := range layer {
+			if vertices[index].height > height {
+				height = vertices[index].height
+			}
+		}
+		for _, index := range layer {
+			vertices[index].y = y + height/2
+		}
+		y += height + rankSpacing
+	}
+}
+
+// transform maps layout coordinates, where layers are rows, to the requested direction.
+type transform struct {
+	direction Direction
+	maxX      float64
+	maxY      float64
+}
+
+// newTransform creates the transform for the direction and the extent of the vertices.
+func newTransform(direction Direction, vertices []*vertex) transform {
+	t := transform{direction: direction}
+	for _, v := range vertices {
+		if x := v.x + v.width/2 + v.extra; x > t.maxX {
+			t.maxX = x
+		}
+		if y := v.y + v.height/2; y > t.maxY {
+			t.maxY = y
+		}
+	}
+	return t
+}
+
+// point maps a layout position to the output coordinates.
+func (t transform) point(x float64, y float64) Point {
+	switch t.direction {
+	case BottomToTop:
+		return Point{X: x, Y: t.maxY - y}
+	case LeftToRight:
+		return Point{X: y, Y: x}
+	case RightToLeft:
+		return Point{X: t.maxY - y, Y: x}
+	default:
+		return Point{X: x, Y: y}
+	}
+}
+
+// midpoint returns the point halfway along the polyline.
+func midpoint(points []Point) Point {
+	if len(points) == 0 {
+		return Point{}
+	}
+	if len(points)%2 == 1 {
+		return points[len(points)/2]
+	}
+	a, b := points[len(points)/2-1], points[len(points)/2]
+	return Point{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2}
+}
+
+// separateClusters moves the vertices outside a top-level cluster that overlap the span
+// the cluster covers in other layers, so that cluster boxes do not cover foreign nodes.
+// Vertices after the cluster in their layer are moved right, the others left.
+func separateClusters(vertices []*vertex, layers [][]int, clusters []Cluster, options Options) {
+	horizontal := options.Direction == LeftToRight || options.Direction == RightToLeft
+	spacing := options.NodeSpacing / 2
+
+	for c, cluster := range clusters {
+		if cluster.Parent >= 0 {
+			continue
+		}
+
+		found := false
+		var low, high float64
+		first, last := 0, 0
+		for _, v := range vertices {
+			if v.cluster != c {
+				continue
+			}
+			left, right := v.x-v.width/2, v.x+v.width/2+v.extra
+			if !found {
+				found, low, high, first, last = true, left, right, v.rank, v.rank
+				continue
+			}
+			if left < low {
+				low = left
+			}
+			if right > high {
+				high = right
+			}
+			if v.rank < first {
+				first = v.rank
+			}
+			if v.rank > last {
+				last = v.rank
+			}
+		}
+		if !found {
+			continue
+		}
+
+		low -= options.ClusterPadding
+		high += options.ClusterPadding
+		if minWidth := cluster.LabelWidth + 2*options.ClusterPadding; !horizontal && high-low < minWidth {
+			middle := (low + high) / 2
+			low, high = middle-minWidth/2, middle+minWidth/2
+		}
+
+		for rank := first; rank <= last && rank < len(layers); rank++ {
+			layer := layers[rank]
+			lastMember := -1
+			for i, index := range layer {
+				if vertices[index].cluster == c {
+					lastMember = i
+				}
+			}
+
+			for i, index := range layer {
+				v := vertices[index]
+				left, right := v.x-v.width/2, v.x+v.width/2+v.extra
+				if v.cluster == c || right+spacing <= low || left-spacing >= high {
+					continue
+				}
+
+				after := i > lastMember
+				if lastMember < 0 {
+					after = v.x >= (low+high)/2
+				}
+
+				if after {
+					shift(vertices, layer[i:], high+spacing-left)
+				} else {
+					shift(vertices, layer[:i+1], low-spacing-right)
+				}
+			}
+		}
+	}
+}
+
+// shift moves the vertices horizontally by delta.
+func shift(vertices []*vertex, indices []int, delta float64) {
+	for _, index := range indices {
+		vertices[index].x += delta
+	}
+}
+
+// placeClusters computes the boxes of the clusters around their nodes and nested clusters.
+func placeClusters(g Graph, result Result, padding float64) {
+	depth := make([]int, len(g.Clusters))
+	for i := range g.Clusters {
+		for parent := g.Clusters[i].Parent; parent >= 0 && parent < len(g.Clusters); parent = g.Clusters[parent].Parent {
+			depth[i]++
+		}
+	}
+
+	order := make([]int, len(g.Clusters))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return depth[order[i]] > depth[order[j]] })
+
+	for _, c := range order {
+		var boxes []Rect
+		for i, node := range g.Nodes {
+			if node.Cluster == c {
+				boxes = append(boxes, result.Nodes[i])
+			}
+		}
+		for child, cluster := range g.Clusters {
+			if cluster.Parent == c && result.Clusters[child].Width > 0 {
+				boxes = append(boxes, result.Clusters[child])
+			}
+		}
+
+		if len(boxes) == 0 {
+			continue
+		}
+
+		box := boxes[0]
+		for _, other := range boxes[1:] {
+			box = union(box, other)
+		}
+
+		box.X -= padding
+		box.Y -= padding + g.Clusters[c].LabelHeight
+		box.Width += 2 * padding
+		box.Height += 2*padding + g.Clusters[c].LabelHeight
+		if minWidth := g.Clusters[c].LabelWidth + 2*padding; box.Width < minWidth {
+			box.X -= (minWidth - box.Width) / 2
+			box.Width = minWidth
+		}
+
+		result.Clusters[c] = box
+	}
+}
+
+// union returns the smallest box containing both boxes.
+func union(a Rect, b Rect) Rect {
+	minX, minY := a.X, a.Y
+	if b.X < minX {
+		minX = b.X
+	}
+	if b.Y < minY {
+		minY = b.Y
+	}
+	maxX, maxY := a.X+a.Width, a.Y+a.Height
+	if b.X+b.Width > maxX {
+		maxX = b.X + b.Width
+	}
+	if b.Y+b.Height > maxY {
+		maxY = b.Y + b.Height
+	}
+	return Rect{X: minX, Y: minY, Width: maxX - minX, Height: maxY - minY}
+}
+
+// normalize shifts the result so that every coordinate is non-negative and sets its size.
+func normalize(result *Result, g Graph) {
+	first := true
+	var bounds Rect
+	include := func(r Rect) {
+		if first {
+			bounds, first = r, false
+			return
+		}
+		bounds = union(bounds, r)
+	}
+
+	for _, r := range result.Nodes {
+		include(r)
+	}
+	for _, r := range result.Clusters {
+		if r.Width > 0 {
+			include(r)
+		}
+	}
+	for e, route := range result.Edges {
+		for _, p := range route.Points {
+			include(Rect{X: p.X, Y: p.Y})
+		}
+		if g.Edges[e].LabelWidth > 0 || g.Edges[e].LabelHeight > 0 {
+			include(Rect{X: route.Label.X - g.Edges[e].LabelWidth/2, Y: route.Label.Y - g.Edges[e].LabelHeight/2, Width: g.Edges[e].LabelWidth, Height: g.Edges[e].LabelHeight})
+		}
+	}
+
+	dx, dy := -bounds.X, -bounds.Y
+
+	for i := range result.Nodes {
+		result.Nodes[i].X += dx
+		result.Nodes[i].Y += dy
+	}
+	for i := range result.Clusters {
+		if result.Clusters[i].Width > 0 {
+			result.Clusters[i].X += dx
+			result.Clusters[i].Y += dy
+		}
+	}
+	for i := range result.Edges {
+		for j := range result.Edges[i].Points {
+			result.Edges[i].Points[j].X += dx
+			result.Edges[i].Points[j].Y += dy
+		}
+		result.Edges[i].Label.X += dx
+		result.Edges[i].Label.Y += dy
+	}
+
+	result.Width, result.Height = bounds.Width, bounds.Height
+}
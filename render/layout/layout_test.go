@@ -0,0 +1,254 @@
+package layout
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// box returns a node of the given size outside any cluster.
+func box(width float64, height float64) Node {
+	return Node{Width: width, Height: height, Cluster: -1}
+}
+
+func TestLayout_Directions(t *testing.T) {
+	g := Graph{
+		Nodes: []Node{box(40, 20), box(40, 20)},
+		Edges: []Edge{{From: 0, To: 1}},
+	}
+
+	tests := []struct {
+		direction Direction
+		check     func(a Point, b Point) bool
+	}{
+		{direction: TopToBottom, check: func(a, b Point) bool { return a.Y < b.Y && a.X == b.X }},
+		{direction: BottomToTop, check: func(a, b Point) bool { return a.Y > b.Y && a.X == b.X }},
+		{direction: LeftToRight, check: func(a, b Point) bool { return a.X < b.X && a.Y == b.Y }},
+		{direction: RightToLeft, check: func(a, b Point) bool { return a.X > b.X && a.Y == b.Y }},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.direction), func(t *testing.T) {
+			result := Layout(g, Options{Direction: tt.direction})
+			a, b := result.Nodes[0].Center(), result.Nodes[1].Center()
+			if !tt.check(a, b) {
+				t.Errorf("Layout() centers = %v, %v", a, b)
+			}
+
+			route := result.Edges[0].Points
+			if len(route) != 2 || !near(route[0], a) || !near(route[1], b) {
+				t.Errorf("Layout() route = %v", route)
+			}
+		})
+	}
+}
+
+func TestLayout_Size(t *testing.T) {
+	g := Graph{
+		Nodes: []Node{box(40, 20), box(60, 30), box(40, 20)},
+		Edges: []Edge{{From: 0, To: 1}, {From: 0, To: 2}},
+	}
+
+	result := Layout(g, Options{NodeSpacing: 10, RankSpacing: 50})
+
+	if result.Height != 20+50+30 {
+		t.Errorf("Layout() height = %v, want 100", result.Height)
+	}
+
+	if result.Width != 60+10+40 {
+		t.Errorf("Layout() width = %v, want 110", result.Width)
+	}
+
+	for _, node := range result.Nodes {
+		if node.X < 0 || node.Y < 0 || node.X+node.Width > result.Width || node.Y+node.Height > result.Height {
+			t.Errorf("Layout() node %v outside of %vx%v", node, result.Width, result.Height)
+		}
+	}
+}
+
+func TestLayout_LongEdgesAndCycles(t *testing.T) {
+	g := Graph{
+		Nodes: []Node{box(40, 20), box(40, 20), box(40, 20)},
+		Edges: []Edge{{From: 0, To: 1}, {From: 1, To: 2}, {From: 0, To: 2}, {From: 2, To: 0}},
+	}
+
+	result := Layout(g, Options{})
+
+	if !(result.Nodes[0].Y < result.Nodes[1].Y && result.Nodes[1].Y < result.Nodes[2].Y) {
+		t.Errorf("Layout() should rank nodes along the longest path: %v", result.Nodes)
+	}
+
+	if got := len(result.Edges[2].Points); got != 3 {
+		t.Errorf("Layout() long edge points = %d, want 3", got)
+	}
+
+	back := result.Edges[3].Points
+	if !near(back[0], result.Nodes[2].Center()) || !near(back[len(back)-1], result.Nodes[0].Center()) {
+		t.Errorf("Layout() reversed edge should keep its direction, got %v", back)
+	}
+}
+
+func TestLayout_Labels(t *testing.T) {
+	g := Graph{
+		Nodes: []Node{box(40, 20), box(40, 20)},
+		Edges: []Edge{{From: 0, To: 1, LabelWidth: 30, LabelHeight: 16}},
+	}
+
+	result := Layout(g, Options{RankSpacing: 40})
+
+	label := result.Edges[0].Label
+	if !(label.Y > result.Nodes[0].Y+20 && label.Y < result.Nodes[1].Y) {
+		t.Errorf("Layout() label %v should lie between the nodes %v", label, result.Nodes)
+	}
+
+	if gap := result.Nodes[1].Y - (result.Nodes[0].Y + 20); gap < 16+40 {
+		t.Errorf("Layout() should reserve space for the label, gap = %v", gap)
+	}
+}
+
+func TestLayout_Crossings(t *testing.T) {
+	// a -> d, b -> c: ordering the lower layer by insertion would cross.
+	g := Graph{
+		Nodes: []Node{box(40, 20), box(40, 20), box(40, 20), box(40, 20)},
+		Edges: []Edge{{From: 0, To: 3}, {From: 1, To: 2}},
+	}
+
+	result := Layout(g, Options{})
+
+	if result.Nodes[3].X > result.Nodes[2].X {
+		t.Errorf("Layout() should uncross edges: %v", result.Nodes)
+	}
+}
+
+func TestLayout_Clusters(t *testing.T) {
+	g := Graph{
+		Nodes: []Node{
+			{Width: 40, Height: 20, Cluster: 1},
+			{Width: 40, Height: 20, Cluster: 0},
+			box(40, 20),
+		},
+		Edges: []Edge{{From: 0, To: 1}, {From: 1, To: 2}},
+		Clusters: []Cluster{
+			{Parent: -1, LabelWidth: 200, LabelHeight: 18},
+			{Parent: 0, LabelWidth: 20, LabelHeight: 18},
+		},
+	}
+
+	result := Layout(g, Options{ClusterPadding: 5})
+
+	inner, outer := result.Clusters[1], result.Clusters[0]
+	if !contains(inner, result.Nodes[0]) || contains(inner, result.Nodes[1]) {
+		t.Errorf("inner cluster %v should only contain node 0", inner)
+	}
+	if !contains(outer, inner) || !contains(outer, result.Nodes[1]) || contains(outer, result.Nodes[2]) {
+		t.Errorf("outer cluster %v should contain the inner cluster and node 1", outer)
+	}
+	if outer.Width < 210 {
+		t.Errorf("outer cluster width = %v, want room for its title", outer.Width)
+	}
+	if result.Nodes[0].Y-inner.Y != 5+18 {
+		t.Errorf("cluster should reserve padding and title height above its nodes")
+	}
+}
+
+func TestLayout_ClusterOverlap(t *testing.T) {
+	g := Graph{
+		Nodes: []Node{
+			{Width: 40, Height: 20, Cluster: 0},
+			{Width: 200, Height: 20, Cluster: 0},
+			{Width: 40, Height: 20, Cluster: 0},
+			box(40, 20),
+		},
+		Edges:    []Edge{{From: 0, To: 1}, {From: 1, To: 2}},
+		Clusters: []Cluster{{Parent: -1, LabelWidth: 20, LabelHeight: 18}},
+	}
+
+	for _, direction := range []Direction{TopToBottom, LeftToRight} {
+		result := Layout(g, Options{Direction: direction, ClusterPadding: 5})
+		if overlaps(result.Clusters[0], result.Nodes[3]) {
+			t.Errorf("%s: cluster %v overlaps node %v", direction, result.Clusters[0], result.Nodes[3])
+		}
+	}
+}
+
+func TestLayout_SelfLoop(t *testing.T) {
+	g := Graph{
+		Nodes: []Node{box(40, 20), box(40, 20)},
+		Edges: []Edge{{From: 0, To: 0}, {From: 0, To: 1}},
+	}
+
+	result := Layout(g, Options{})
+
+	loop := result.Edges[0].Points
+	if len(loop) != 4 || loop[0].X != result.Nodes[0].X+40 || loop[1].X <= loop[0].X {
+		t.Errorf("Layout() self-loop = %v for node %v", loop, result.Nodes[0])
+	}
+}
+
+func TestLayout_Deterministic(t *testing.T) {
+	g := Graph{Nodes: make([]Node, 0)}
+	for i := 0; i < 12; i++ {
+		g.Nodes = append(g.Nodes, box(30+float64(i), 20))
+	}
+	for i := 0; i < 12; i++ {
+		g.Edges = append(g.Edges, Edge{From: i, To: (i*5 + 3) % 12}, Edge{From: i, To: (i + 1) % 12})
+	}
+
+	first := Layout(g, Options{})
+	for i := 0; i < 5; i++ {
+		if again := Layout(g, Options{}); !reflect.DeepEqual(first, again) {
+			t.Fatal("Layout() is not deterministic")
+		}
+	}
+}
+
+func TestLayout_Empty(t *testing.T) {
+	result := Layout(Graph{}, Options{})
+	if result.Width != 0 || result.Height != 0 || len(result.Nodes) != 0 {
+		t.Errorf("Layout() of empty graph = %+v", result)
+	}
+}
+
+// contains reports whether outer fully contains inner.
+func contains(outer Rect, inner Rect) bool {
+	return inner.X >= outer.X && inner.Y >= outer.Y &&
+		inner.X+inner.Width <= outer.X+outer.Width && inner.Y+inner.Height <= outer.Y+outer.Height
+}
+
+// overlaps reports whether two boxes intersect.
+func overlaps(a Rect, b Rect) bool {
+	return a.X < b.X+b.Width && b.X < a.X+a.Width && a.Y < b.Y+b.Height && b.Y < a.Y+a.Height
+}
+
+// near reports whether two points are equal up to rounding errors.
+func near(a Point, b Point) bool {
+	return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9
+}
+
+func TestGraph_PruneClusters(t *testing.T) {
+	g := Graph{
+		Nodes: []Node{{Cluster: 3}, {Cluster: -1}, {Cluster: 0}},
+		Clusters: []Cluster{
+			{Parent: -1, LabelWidth: 1},
+			{Parent: -1, LabelWidth: 2},
+			{Parent: 1, LabelWidth: 3},
+			{Parent: 0, LabelWidth: 4},
+		},
+	}
+
+	kept := g.PruneClusters()
+
+	if !reflect.DeepEqual(kept, []int{0, 3}) {
+		t.Errorf("PruneClusters() = %v, want [0 3]", kept)
+	}
+
+	wantClusters := []Cluster{{Parent: -1, LabelWidth: 1}, {Parent: 0, LabelWidth: 4}}
+	if !reflect.DeepEqual(g.Clusters, wantClusters) {
+		t.Errorf("PruneClusters() clusters = %v, want %v", g.Clusters, wantClusters)
+	}
+
+	wantNodes := []Node{{Cluster: 1}, {Cluster: -1}, {Cluster: 0}}
+	if !reflect.DeepEqual(g.Nodes, wantNodes) {
+		t.Errorf("PruneClusters() nodes = %v, want %v", g.Nodes, wantNodes)
+	}
+}
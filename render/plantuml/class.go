@@ -0,0 +1,198 @@
+package plantuml
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/class"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+)
+
+const (
+	leftToRightString   string = "left to right direction\n"
+	separatorNoneString string = "set separator none\n"
+	packageString       string = "package %s {\n"
+	classString         string = "class %s%s {\n"
+	classAliasString    string = "class %s as %s%s {\n"
+	stereotypeString    string = " <<%s>>"
+	fieldString         string = "%s%s%s"
+	methodString        string = "%s%s%s(%s)"
+	parameterString     string = "%s : %s"
+	relationString      string = "%s%s %s%s%s %s%s%s\n"
+	relationLabelString string = " : %s"
+	noteDeclString      string = "note %s as N%d\n"
+	noteLinkString      string = "N%d .. %s\n"
+	staticModifier      string = "{static} "
+	abstractModifier    string = "{abstract} "
+)
+
+// RenderClass returns the class diagram as PlantUML text.
+func RenderClass(cd *class.ClassDiagram) string {
+	var sb strings.Builder
+
+	sb.WriteString(startString)
+	if cd.Title != "" {
+		sb.WriteString(fmt.Sprintf(titleString, text(cd.Title)))
+	}
+	if cd.Direction == class.ClassDiagramDirectionLeftRight || cd.Direction == class.ClassDiagramDirectionRightLeft {
+		sb.WriteString(leftToRightString)
+	}
+
+	namespaces := cd.Namespaces()
+	if len(namespaces) > 0 {
+		// Packages would otherwise qualify class names, breaking references by name.
+		sb.WriteString(separatorNoneString)
+	}
+
+	written := make(map[*class.Class]bool)
+	for _, namespace := range namespaces {
+		writePackage(&sb, namespace, "", written)
+	}
+	for _, c := range cd.Classes() {
+		if !written[c] {
+			writeClass(&sb, c, "")
+			written[c] = true
+		}
+	}
+
+	for _, relation := range cd.Relations() {
+		sb.WriteString(relationLine(relation))
+	}
+
+	for i, note := range cd.Notes() {
+		sb.WriteString(fmt.Sprintf(noteDeclString, quoted(note.Text), i))
+		if note.Class != nil {
+			sb.WriteString(fmt.Sprintf(noteLinkString, i, reference(note.Class.Name)))
+		}
+	}
+
+	sb.WriteString(endString)
+	return sb.String()
+}
+
+// RenderClassToFile writes the class diagram as PlantUML text to the path.
+func RenderClassToFile(cd *class.ClassDiagram, path string) error {
+	return utils.RenderToFile(path, RenderClass(cd))
+}
+
+// writePackage writes a namespace as a package with its classes and nested namespaces.
+func writePackage(sb *strings.Builder, namespace *class.Namespace, indent string, written map[*class.Class]bool) {
+	sb.WriteString(indent + fmt.Sprintf(packageString, reference(namespace.Name)))
+	for _, c := range namespace.Classes {
+		writeClass(sb, c, indent+indentation)
+		written[c] = true
+	}
+	for _, child := range namespace.Children {
+		writePackage(sb, child, indent+indentation, written)
+	}
+	sb.WriteString(indent + blockEndString)
+}
+
+// writeClass writes a class with its annotation as a stereotype, its fields and methods.
+func writeClass(sb *strings.Builder, c *class.Class, indent string) {
+	stereotype := ""
+	if c.Annotation != class.ClassAnnotationNone {
+		stereotype = fmt.Sprintf(stereotypeString, strings.TrimSuffix(strings.TrimPrefix(string(c.Annotation), "<<"), ">>"))
+	}
+
+	if c.Label != "" && c.Label != c.Name {
+		sb.WriteString(indent + fmt.Sprintf(classAliasString, quoted(c.Label), reference(c.Name), stereotype))
+	} else {
+		sb.WriteString(indent + fmt.Sprintf(classString, reference(c.Name), stereotype))
+	}
+
+	for _, field := range c.Fields() {
+		sb.WriteString(indent + indentation + fieldLine(field) + "\n")
+	}
+	for _, method := range c.Methods() {
+		sb.WriteString(indent + indentation + methodLine(method) + "\n")
+	}
+
+	sb.WriteString(indent + blockEndString)
+}
+
+// fieldLine returns a field as "visibility name : type".
+func fieldLine(field *class.Field) string {
+	modifier := ""
+	if field.Classifier == class.FieldClassifierStatic {
+		modifier = staticModifier
+	}
+
+	line := fmt.Sprintf(fieldString, string(field.Visibility), modifier, field.Name)
+	if field.Type != "" {
+		line += memberSeparator + field.Type
+	}
+	return line
+}
+
+// methodLine returns a method as "visibility name(parameter : type) : return type".
+func methodLine(method *class.Method) string {
+	modifier := ""
+	switch method.Classifier {
+	case class.MethodClassifierStatic:
+		modifier = staticModifier
+	case class.MethodClassifierAbstract:
+		modifier = abstractModifier
+	}
+
+	parameters := make([]string, len(method.Parameters))
+	for i, parameter := range method.Parameters {
+		if parameter.Type == "" {
+			parameters[i] = parameter.Name
+		} else {
+			parameters[i] = fmt.Sprintf(parameterString, parameter.Name, parameter.Type)
+		}
+	}
+
+	line := fmt.Sprintf(methodString, string(method.Visibility), modifier, method.Name, strings.Join(parameters, ", "))
+	if method.ReturnType != "" {
+		line += memberSeparator + method.ReturnType
+	}
+	return line
+}
+
+// relationLine returns a relation with its markers, line style, cardinalities and label.
+// PlantUML uses the same arrow syntax as Mermaid once the markers are normalised to the
+// side they are attached to.
+func relationLine(relation *class.Relation) string {
+	left := relationMarker(string(relation.RelationToClassA), true)
+	right := relationMarker(string(relation.RelationToClassB), false)
+
+	cardinalityA, cardinalityB := "", ""
+	if relation.CardinalityToClassA != "" {
+		cardinalityA = " " + string(relation.CardinalityToClassA)
+	}
+	if relation.CardinalityToClassB != "" {
+		cardinalityB = string(relation.CardinalityToClassB) + " "
+	}
+
+	label := ""
+	if relation.Label != "" {
+		label = fmt.Sprintf(relationLabelString, text(relation.Label))
+	}
+
+	link := string(relation.Link)
+	if link == "" {
+		link = string(class.RelationLinkSolid)
+	}
+
+	return fmt.Sprintf(relationString, reference(relation.ClassA.Name), cardinalityA, left, link, right, cardinalityB, reference(relation.ClassB.Name), label)
+}
+
+// relationMarker returns the PlantUML marker of a relation end. Inheritance and
+// association markers point away from the line on the side they are attached to.
+func relationMarker(marker string, left bool) string {
+	switch marker {
+	case string(class.RelationTypeInheritance), string(class.RelationTypeInheritanceLeft):
+		if left {
+			return "<|"
+		}
+		return "|>"
+	case string(class.RelationTypeAssociation), string(class.RelationTypeAssociationLeft):
+		if left {
+			return "<"
+		}
+		return ">"
+	}
+	return marker
+}
@@ -0,0 +1,169 @@
+package plantuml
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/class"
+)
+
+func TestRenderClass(t *testing.T) {
+	cd := class.NewClassDiagram()
+	cd.SetTitle("Zoo")
+	cd.SetDirection(class.ClassDiagramDirectionRightLeft)
+
+	animals := cd.AddNamespace("animals")
+	mammals := animals.AddNamespace("mammals")
+
+	animal := cd.AddClass("Animal", animals)
+	animal.SetAnnotation(class.ClassAnnotationAbstract)
+	animal.AddField("name", "string").SetVisibility(class.FieldVisibilityProtected)
+	count := animal.AddField("count", "int")
+	count.Classifier = class.FieldClassifierStatic
+	speak := animal.AddMethod("Speak").SetReturnType("string").SetClassifier(class.MethodClassifierAbstract)
+	speak.AddParameter("loud", "bool")
+	speak.AddParameter("times", "")
+
+	dog := cd.AddClass("Dog", nil)
+	dog.AddMethod("Create").SetClassifier(class.MethodClassifierStatic).SetVisibility(class.MethodVisibilityPrivate)
+	mammals.AddClass(class.NewClass("Cat").SetLabel("House Cat"))
+
+	inheritance := cd.AddRelation(animal, dog)
+	inheritance.RelationToClassA = class.RelationTypeInheritanceLeft
+	inheritance.CardinalityToClassA = class.RelationCardinalityOnlyOne
+	inheritance.CardinalityToClassB = class.RelationCardinalityMany
+	inheritance.Label = "parent of"
+
+	cd.AddNote("Good boy", dog)
+	cd.AddNote("Diagram note", nil)
+
+	want := `@startuml
+title Zoo
+left to right direction
+set separator none
+package animals {
+    class Animal <<Abstract>> {
+        #name : string
+        +{static} count : int
+        +{abstract} Speak(loud : bool, times) : string
+    }
+    package mammals {
+        class "House Cat" as Cat {
+        }
+    }
+}
+class Dog {
+    -{static} Create()
+}
+Animal "1" <|-- "*" Dog : parent of
+note "Good boy" as N0
+N0 .. Dog
+note "Diagram note" as N1
+@enduml
+`
+	if got := RenderClass(cd); got != want {
+		t.Errorf("RenderClass() = \n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderClass_Relations(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(*class.Relation)
+		want  string
+	}{
+		{
+			name:  "Link",
+			setup: func(r *class.Relation) {},
+			want:  "A -- B",
+		},
+		{
+			name: "Realization",
+			setup: func(r *class.Relation) {
+				r.RelationToClassB = class.RelationTypeInheritance
+				r.Link = class.RelationLinkDashed
+			},
+			want: "A ..|> B",
+		},
+		{
+			name: "Inheritance marker normalised",
+			setup: func(r *class.Relation) {
+				r.RelationToClassA = class.RelationTypeInheritance
+			},
+			want: "A <|-- B",
+		},
+		{
+			name: "Composition",
+			setup: func(r *class.Relation) {
+				r.RelationToClassA = class.RelationTypeComposition
+			},
+			want: "A *-- B",
+		},
+		{
+			name: "Aggregation",
+			setup: func(r *class.Relation) {
+				r.RelationToClassB = class.RelationTypeAggregation
+			},
+			want: "A --o B",
+		},
+		{
+			name: "Bidirectional association",
+			setup: func(r *class.Relation) {
+				r.RelationToClassA = class.RelationTypeAssociation
+				r.RelationToClassB = class.RelationTypeAssociationLeft
+			},
+			want: "A <--> B",
+		},
+		{
+			name: "Dependency with cardinality",
+			setup: func(r *class.Relation) {
+				r.RelationToClassB = class.RelationTypeAssociation
+				r.Link = class.RelationLinkDashed
+				r.CardinalityToClassB = class.RelationCardinalityZeroOrOne
+			},
+			want: `A ..> "0..1" B`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cd := class.NewClassDiagram()
+			tt.setup(cd.AddRelation(cd.AddClass("A", nil), cd.AddClass("B", nil)))
+
+			if got := RenderClass(cd); !strings.Contains(got, "\n"+tt.want+"\n") {
+				t.Errorf("RenderClass() missing %q in:\n%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRenderClass_Defaults(t *testing.T) {
+	cd := class.NewClassDiagram()
+	cd.AddClass("Order Line", nil)
+
+	got := RenderClass(cd)
+
+	if !strings.Contains(got, "class \"Order Line\" {\n}\n") {
+		t.Errorf("RenderClass() should quote names with spaces:\n%s", got)
+	}
+	for _, unwanted := range []string{"title", "direction", "separator"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("RenderClass() should not contain %q:\n%s", unwanted, got)
+		}
+	}
+}
+
+func TestRenderClassToFile(t *testing.T) {
+	cd := class.NewClassDiagram()
+	cd.AddClass("A", nil)
+	path := filepath.Join(t.TempDir(), "class.puml")
+
+	if err := RenderClassToFile(cd, path); err != nil {
+		t.Fatalf("RenderClassToFile() error = %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != RenderClass(cd) {
+		t.Errorf("RenderClassToFile() wrote %q", content)
+	}
+}
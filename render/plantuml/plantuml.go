@@ -0,0 +1,58 @@
+// Package plantuml exports sequence and class diagrams as PlantUML text.
+//
+// Most constructs have a direct PlantUML equivalent. The following ones do not and are
+// approximated or dropped:
+//
+//   - Diagram configuration and themes are not exported; PlantUML uses its own skin
+//     parameters.
+//   - Sequence messages without an arrowhead (Mermaid "->" and "-->") are exported with
+//     a normal arrowhead, as PlantUML arrows always have a head.
+//   - Nested sequence messages are exported in order without grouping; Mermaid does not
+//     give nesting a visual meaning either.
+//   - Notes over more than two participants are exported over the first and last one.
+//   - Class diagram directions BT and RL are exported as top to bottom and left to right,
+//     the only directions PlantUML supports.
+//   - Static and abstract members are marked with PlantUML modifiers, which PlantUML draws
+//     as underlined and italic text instead of Mermaid's "$" and "*" suffixes.
+//   - Relation markers are normalised to the side they are attached to, so a "|>" on the
+//     first class is exported as "<|".
+package plantuml
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	startString     string = "@startuml\n"
+	endString       string = "@enduml\n"
+	titleString     string = "title %s\n"
+	blockEndString  string = "}\n"
+	lineBreak       string = `\n`
+	indentation     string = "    "
+	memberSeparator string = " : "
+)
+
+// identifierPattern matches names PlantUML accepts without quotes.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+
+// reference returns a name as PlantUML can reference it, quoting it when needed.
+func reference(name string) string {
+	if identifierPattern.MatchString(name) {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `'`) + `"`
+}
+
+// text returns a label on a single line, with line breaks written as PlantUML escapes.
+func text(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "<br>", "\n")
+	s = strings.ReplaceAll(s, "<br/>", "\n")
+	return strings.ReplaceAll(s, "\n", lineBreak)
+}
+
+// quoted returns a label as a PlantUML quoted string.
+func quoted(s string) string {
+	return `"` + strings.ReplaceAll(text(s), `"`, `'`) + `"`
+}
@@ -0,0 +1,43 @@
+package plantuml
+
+import "testing"
+
+func TestReference(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Alice", want: "Alice"},
+		{name: "com.example.Order_2", want: "com.example.Order_2"},
+		{name: "Order Service", want: `"Order Service"`},
+		{name: `say "hi"`, want: `"say 'hi'"`},
+	}
+
+	for _, tt := range tests {
+		if got := reference(tt.name); got != tt.want {
+			t.Errorf("reference(%q) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{s: "plain", want: "plain"},
+		{s: "two\nlines", want: `two\nlines`},
+		{s: "crlf\r\nline", want: `crlf\nline`},
+		{s: "html<br>break<br/>s", want: `html\nbreak\ns`},
+	}
+
+	for _, tt := range tests {
+		if got := text(tt.s); got != tt.want {
+			t.Errorf("text(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+
+	if got := quoted(`a "b"` + "\nc"); got != `"a 'b'\nc"` {
+		t.Errorf("quoted() = %s", got)
+	}
+}
@@ -0,0 +1,169 @@
+package plantuml
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/sequence"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+)
+
+const (
+	autonumberString       string = "autonumber\n"
+	participantString      string = "%s %s\n"
+	participantAliasString string = "%s %s as %s\n"
+	messageString          string = "%s %s %s\n"
+	messageTextString      string = "%s %s %s : %s\n"
+	activateString         string = "activate %s\n"
+	deactivateString       string = "deactivate %s\n"
+	createString           string = "create %s"
+	destroyString          string = "destroy %s\n"
+	noteString             string = "note %s %s : %s\n"
+	noteOverPairString     string = "note over %s, %s : %s\n"
+)
+
+// RenderSequence returns the sequence diagram as PlantUML text.
+func RenderSequence(d *sequence.Diagram) string {
+	var sb strings.Builder
+
+	sb.WriteString(startString)
+	if d.Title != "" {
+		sb.WriteString(fmt.Sprintf(titleString, text(d.Title)))
+	}
+	if d.AutoNumber() {
+		sb.WriteString(autonumberString)
+	}
+
+	created := make(map[*sequence.Actor]bool)
+	walkMessages(d.Messages, func(message *sequence.Message) {
+		if message.Note == nil && message.Type == sequence.MessageCreate {
+			created[message.To] = true
+		}
+	})
+
+	for _, actor := range d.Actors {
+		if !created[actor] {
+			sb.WriteString(participant(actor))
+		}
+	}
+
+	for _, message := range d.Messages {
+		writeMessage(&sb, message)
+	}
+
+	sb.WriteString(endString)
+	return sb.String()
+}
+
+// RenderSequenceToFile writes the sequence diagram as PlantUML text to the path.
+func RenderSequenceToFile(d *sequence.Diagram, path string) error {
+	return utils.RenderToFile(path, RenderSequence(d))
+}
+
+// participant returns the declaration of an actor.
+func participant(actor *sequence.Actor) string {
+	keyword := "participant"
+	if actor.Type == sequence.ActorActor {
+		keyword = "actor"
+	}
+
+	if actor.Name == "" || actor.Name == actor.ID {
+		return fmt.Sprintf(participantString, keyword, reference(actor.ID))
+	}
+	return fmt.Sprintf(participantAliasString, keyword, quoted(actor.Name), reference(actor.ID))
+}
+
+// writeMessage writes a message, a note or an activation change, followed by the nested
+// messages.
+func writeMessage(sb *strings.Builder, message *sequence.Message) {
+	if message.Note != nil {
+		writeNote(sb, message.Note)
+		return
+	}
+
+	switch message.Type {
+	case sequence.MessageCreate:
+		sb.WriteString(fmt.Sprintf(createString, participant(message.To)))
+		writeArrow(sb, message, sequence.MessageSolid)
+	case sequence.MessageDestroy:
+		sb.WriteString(fmt.Sprintf(destroyString, reference(message.To.ID)))
+	case sequence.MessageActivate:
+		writeArrow(sb, message, sequence.MessageSolid)
+		sb.WriteString(fmt.Sprintf(activateString, reference(message.To.ID)))
+	case sequence.MessageDeactivate:
+		writeArrow(sb, message, sequence.MessageSolid)
+		sb.WriteString(fmt.Sprintf(deactivateString, reference(message.To.ID)))
+	default:
+		writeArrow(sb, message, message.Type)
+	}
+
+	for _, nested := range message.Nested {
+		writeMessage(sb, nested)
+	}
+}
+
+// writeArrow writes the arrow of a message. Activation and creation messages only have an
+// arrow when they have a text, as in Mermaid.
+func writeArrow(sb *strings.Builder, message *sequence.Message, arrowType sequence.MessageType) {
+	if message.From == nil || message.To == nil {
+		return
+	}
+
+	switch message.Type {
+	case sequence.MessageCreate, sequence.MessageActivate, sequence.MessageDeactivate:
+		if message.Text == "" {
+			return
+		}
+	}
+
+	from, to := reference(message.From.ID), reference(message.To.ID)
+	if message.Text == "" {
+		sb.WriteString(fmt.Sprintf(messageString, from, arrow(arrowType), to))
+	} else {
+		sb.WriteString(fmt.Sprintf(messageTextString, from, arrow(arrowType), to, text(message.Text)))
+	}
+}
+
+// arrow converts a Mermaid message arrow to PlantUML: a double dash is a dotted line,
+// ")" an open asynchronous head and "x" a lost message.
+func arrow(messageType sequence.MessageType) string {
+	mermaid := string(messageType)
+
+	line := "-"
+	if strings.HasPrefix(mermaid, "--") {
+		line = "--"
+	}
+
+	switch {
+	case strings.HasSuffix(mermaid, ")"):
+		return line + ">>"
+	case strings.HasSuffix(mermaid, "x"):
+		return line + ">x"
+	default:
+		return line + ">"
+	}
+}
+
+// writeNote writes a note left of, right of or over participants.
+func writeNote(sb *strings.Builder, note *sequence.Note) {
+	if len(note.Actors) == 0 {
+		return
+	}
+
+	first := reference(note.Actors[0].ID)
+	switch {
+	case note.Position == sequence.NoteOver && len(note.Actors) > 1:
+		last := reference(note.Actors[len(note.Actors)-1].ID)
+		sb.WriteString(fmt.Sprintf(noteOverPairString, first, last, text(note.Text)))
+	default:
+		sb.WriteString(fmt.Sprintf(noteString, string(note.Position), first, text(note.Text)))
+	}
+}
+
+// walkMessages calls fn for every message, including nested ones, in order.
+func walkMessages(messages []*sequence.Message, fn func(*sequence.Message)) {
+	for _, message := range messages {
+		fn(message)
+		walkMessages(message.Nested, fn)
+	}
+}
@@ -0,0 +1,123 @@
+package plantuml
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/sequence"
+)
+
+func TestRenderSequence(t *testing.T) {
+	d := sequence.NewDiagram()
+	d.SetTitle("Login")
+	d.EnableAutoNumber()
+	user := d.AddActor("user", "User", sequence.ActorActor)
+	api := d.AddActor("api", "API Server", sequence.ActorParticipant)
+	db := d.AddActor("db", "db", sequence.ActorParticipant)
+
+	request := d.AddMessage(user, api, sequence.MessageActivate, "POST /login")
+	request.AddNestedMessage(api, db, sequence.MessageSolidArrow, "SELECT user")
+	d.AddNote(sequence.NoteRight, "checks the password", api)
+	d.AddMessage(api, user, sequence.MessageDeactivate, "200 OK")
+	session := d.CreateActor(api, "session", "Session", sequence.ActorParticipant)
+	d.DestroyActor(session)
+
+	want := `@startuml
+title Login
+autonumber
+actor "User" as user
+participant "API Server" as api
+participant db
+user --> api : POST /login
+activate api
+api --> db : SELECT user
+note right of api : checks the password
+api --> user : 200 OK
+deactivate user
+create participant "Session" as session
+destroy session
+@enduml
+`
+	if got := RenderSequence(d); got != want {
+		t.Errorf("RenderSequence() = \n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderSequence_Arrows(t *testing.T) {
+	tests := []struct {
+		name        string
+		messageType sequence.MessageType
+		want        string
+	}{
+		{name: "Solid with arrowhead", messageType: sequence.MessageType("->>"), want: "a -> b : m"},
+		{name: "Dotted with arrowhead", messageType: sequence.MessageType("-->>"), want: "a --> b : m"},
+		{name: "Solid without arrowhead", messageType: sequence.MessageType("->"), want: "a -> b : m"},
+		{name: "Dotted without arrowhead", messageType: sequence.MessageType("-->"), want: "a --> b : m"},
+		{name: "Cross", messageType: sequence.MessageType("-x"), want: "a ->x b : m"},
+		{name: "Dotted cross", messageType: sequence.MessageType("--x"), want: "a -->x b : m"},
+		{name: "Async", messageType: sequence.MessageType("-)"), want: "a ->> b : m"},
+		{name: "Dotted async", messageType: sequence.MessageType("--)"), want: "a -->> b : m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := sequence.NewDiagram()
+			a := d.AddActor("a", "a", sequence.ActorParticipant)
+			b := d.AddActor("b", "b", sequence.ActorParticipant)
+			d.AddMessage(a, b, tt.messageType, "m")
+
+			if got := RenderSequence(d); !strings.Contains(got, "\n"+tt.want+"\n") {
+				t.Errorf("RenderSequence() missing %q in:\n%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRenderSequence_Elements(t *testing.T) {
+	d := sequence.NewDiagram()
+	a := d.AddActor("a", "", sequence.ActorParticipant)
+	b := d.AddActor("b", "B", sequence.ActorParticipant)
+	c := d.AddActor("c c", "C", sequence.ActorParticipant)
+
+	d.AddMessage(a, b, sequence.MessageActivate, "")
+	d.AddMessage(a, c, sequence.MessageResponse, "multi\nline")
+	d.AddNote(sequence.NoteOver, "shared", a, b, c)
+	d.AddNote(sequence.NoteOver, "single", b)
+	d.AddNote(sequence.NoteLeft, "ignored")
+	d.CreateActor(b, "d", "D", sequence.ActorActor).Name = "D"
+	d.Messages[len(d.Messages)-1].Text = "new"
+
+	got := RenderSequence(d)
+
+	for _, want := range []string{
+		"participant a\n",
+		"participant \"C\" as \"c c\"\n",
+		"\nactivate b\n",
+		"a -> \"c c\" : multi\\nline\n",
+		"note over a, \"c c\" : shared\n",
+		"note over b : single\n",
+		"create actor \"D\" as d\nb --> d : new\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderSequence() missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "ignored") || strings.Contains(got, "participant \"D\"") {
+		t.Errorf("RenderSequence() unexpected output:\n%s", got)
+	}
+}
+
+func TestRenderSequenceToFile(t *testing.T) {
+	d := sequence.NewDiagram()
+	d.AddActor("a", "A", sequence.ActorParticipant)
+	path := filepath.Join(t.TempDir(), "out", "sequence.puml")
+
+	if err := RenderSequenceToFile(d, path); err != nil {
+		t.Fatalf("RenderSequenceToFile() error = %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != RenderSequence(d) {
+		t.Errorf("RenderSequenceToFile() wrote %q", content)
+	}
+}
@@ -0,0 +1,296 @@
+// Package render converts diagrams to SVG, PNG or PDF by invoking the mermaid-cli
+// executable (mmdc) installed on the machine.
+//
+// The executable and the way it is run are configurable, so the package can be used with
+// npx or a container, and tests can replace it with a fake binary or Runner. Renders can
+// be cancelled through their context, limited by a timeout, run in batches on a bounded
+// number of workers and cached by the hash of the diagram source.
+package render
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils/basediagram"
+)
+
+// Format is the output format of a rendered diagram.
+type Format string
+
+// Output formats supported by mermaid-cli.
+const (
+	FormatSVG Format = "svg"
+	FormatPNG Format = "png"
+	FormatPDF Format = "pdf"
+)
+
+// DefaultExecutable is the mermaid-cli command used when Options.Executable is empty.
+const DefaultExecutable = "mmdc"
+
+const (
+	inputFileName  string = "diagram.mmd"
+	outputFileName string = "diagram.%s"
+	tempDirPattern string = "gomermaid-render-"
+)
+
+// ErrUnsupportedFormat is returned for output formats mermaid-cli cannot produce.
+var ErrUnsupportedFormat = errors.New("unsupported output format")
+
+// Options configures a Renderer.
+type Options struct {
+	// Executable is the mermaid-cli command. Defaults to DefaultExecutable.
+	Executable string
+	// Args are passed to the executable before the generated arguments, for example
+	// "-p @mermaid-js/mermaid-cli mmdc" when Executable is "npx".
+	Args []string
+	// Runner runs the executable. Defaults to ExecRunner.
+	Runner Runner
+	// Timeout limits the duration of a single render. Zero means no limit.
+	Timeout time.Duration
+	// Concurrency is the number of diagrams RenderAll renders at the same time.
+	// Defaults to the number of CPUs.
+	Concurrency int
+	// Cache stores rendered diagrams by content hash. Nil disables caching.
+	Cache Cache
+
+	// Theme, Background, Width, Height and Scale are passed to mermaid-cli when set.
+	Theme      string
+	Background string
+	Width      int
+	Height     int
+	Scale      float64
+	// ConfigFile and PuppeteerConfigFile are paths to mermaid and puppeteer JSON
+	// configuration files passed to mermaid-cli when set.
+	ConfigFile          string
+	PuppeteerConfigFile string
+}
+
+// Renderer renders diagrams with mermaid-cli. It is safe for concurrent use.
+type Renderer struct {
+	options Options
+}
+
+// Job is a diagram to render in a batch.
+type Job struct {
+	Diagram basediagram.Diagram
+	Format  Format
+}
+
+// Result is the outcome of a Job.
+type Result struct {
+	Output []byte
+	Err    error
+}
+
+// NewRenderer creates a Renderer, filling in the defaults of unset options.
+func NewRenderer(options Options) *Renderer {
+	if options.Executable == "" {
+		options.Executable = DefaultExecutable
+	}
+	if options.Runner == nil {
+		options.Runner = ExecRunner{}
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = runtime.NumCPU()
+	}
+
+	return &Renderer{options: options}
+}
+
+// Render converts the diagram to the given format and returns the output file content.
+//
+// Failures of the executable are returned as *Error. When the context is cancelled or the
+// timeout expires, the returned error wraps the context error.
+func (r *Renderer) Render(ctx context.Context, diagram basediagram.Diagram, format Format) ([]byte, error) {
+	if !format.valid() {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+
+	source := Source(diagram)
+	key := r.cacheKey(source, format)
+	if r.options.Cache != nil {
+		if output, ok := r.options.Cache.Get(key); ok {
+			return output, nil
+		}
+	}
+
+	if r.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.options.Timeout)
+		defer cancel()
+	}
+
+	output, err := r.run(ctx, source, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.options.Cache != nil {
+		r.options.Cache.Put(key, output)
+	}
+
+	return output, nil
+}
+
+// RenderToFile renders the diagram in the format matching the file extension and writes
+// it to the path, creating missing directories.
+func (r *Renderer) RenderToFile(ctx context.Context, diagram basediagram.Diagram, path string) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+
+	output, err := r.Render(ctx, diagram, format)
+	if err != nil {
+		return err
+	}
+
+	return utils.RenderToFile(path, string(output))
+}
+
+// RenderAll renders the jobs on at most Options.Concurrency workers and returns their
+// results in the order of the jobs. Jobs that have not started when the context is
+// cancelled fail with the context error.
+func (r *Renderer) RenderAll(ctx context.Context, jobs []Job) []Result {
+	results := make([]Result, len(jobs))
+	indices := make(chan int)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < r.options.Concurrency && worker < len(jobs); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				if err := ctx.Err(); err != nil {
+					results[i].Err = err
+					continue
+				}
+				results[i].Output, results[i].Err = r.Render(ctx, jobs[i].Diagram, jobs[i].Format)
+			}
+		}()
+	}
+
+	for i := range jobs {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	return results
+}
+
+// run invokes the executable in a temporary directory and reads the output file.
+func (r *Renderer) run(ctx context.Context, source string, format Format) ([]byte, error) {
+	dir, err := os.MkdirTemp("", tempDirPattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, inputFileName)
+	output := filepath.Join(dir, fmt.Sprintf(outputFileName, format))
+	if err := os.WriteFile(input, []byte(source), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write diagram source: %w", err)
+	}
+
+	command := Command{
+		Name: r.options.Executable,
+		Args: append(append([]string(nil), r.options.Args...), r.arguments(input, output, format)...),
+		Dir:  dir,
+	}
+
+	log, err := r.options.Runner.Run(ctx, command)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, &Error{Executable: r.options.Executable, Message: ctxErr.Error(), Output: string(log), Err: ctxErr}
+	}
+	if err != nil {
+		return nil, newError(r.options.Executable, log, err)
+	}
+
+	content, err := os.ReadFile(output)
+	if err != nil {
+		return nil, &Error{Executable: r.options.Executable, Message: "no output file was written", Output: string(log), Err: err}
+	}
+
+	return content, nil
+}
+
+// arguments returns the mermaid-cli arguments for rendering input to output.
+func (r *Renderer) arguments(input string, output string, format Format) []string {
+	args := []string{"-i", input, "-o", output, "-e", string(format)}
+
+	if r.options.Theme != "" {
+		args = append(args, "-t", r.options.Theme)
+	}
+	if r.options.Background != "" {
+		args = append(args, "-b", r.options.Background)
+	}
+	if r.options.Width > 0 {
+		args = append(args, "-w", strconv.Itoa(r.options.Width))
+	}
+	if r.options.Height > 0 {
+		args = append(args, "-H", strconv.Itoa(r.options.Height))
+	}
+	if r.options.Scale > 0 {
+		args = append(args, "-s", strconv.FormatFloat(r.options.Scale, 'f', -1, 64))
+	}
+	if r.options.ConfigFile != "" {
+		args = append(args, "-c", r.options.ConfigFile)
+	}
+	if r.options.PuppeteerConfigFile != "" {
+		args = append(args, "-p", r.options.PuppeteerConfigFile)
+	}
+
+	return args
+}
+
+// cacheKey returns the hash identifying a render of the source with the current settings,
+// including the content of the mermaid configuration file.
+func (r *Renderer) cacheKey(source string, format Format) string {
+	settings := r.arguments("", "", format)
+	hash := sha256.New()
+	hash.Write([]byte(strings.Join(settings, "\x00")))
+	hash.Write([]byte{0})
+	if r.options.ConfigFile != "" {
+		if config, err := os.ReadFile(r.options.ConfigFile); err == nil {
+			hash.Write(config)
+		}
+		hash.Write([]byte{0})
+	}
+	hash.Write([]byte(source))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Source returns the Mermaid syntax of the diagram without a markdown fence.
+func Source(diagram basediagram.Diagram) string {
+	return basediagram.StripFence(diagram.String())
+}
+
+// FormatFromPath returns the output format matching the extension of a file path.
+func FormatFromPath(path string) (Format, error) {
+	format := Format(strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")))
+	if !format.valid() {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, filepath.Ext(path))
+	}
+	return format, nil
+}
+
+// valid reports whether mermaid-cli can produce the format.
+func (f Format) valid() bool {
+	switch f {
+	case FormatSVG, FormatPNG, FormatPDF:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,357 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+)
+
+// helperEnv makes the test binary act as a fake mmdc in TestHelperProcess.
+const helperEnv = "GO_MERMAID_HELPER_PROCESS"
+
+// fakeExecutable returns options running the test binary as a fake mmdc.
+func fakeExecutable(t *testing.T) Options {
+	t.Helper()
+	t.Setenv(helperEnv, "1")
+	return Options{Executable: os.Args[0], Args: []string{"-test.run=TestHelperProcess", "--"}}
+}
+
+// TestHelperProcess is not a real test. It implements a fake mmdc that writes the input,
+// prefixed with the arguments, to the output file. Sources containing "parse error" fail
+// like mermaid-cli does and sources containing "hang" never finish.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv(helperEnv) != "1" {
+		return
+	}
+
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	flags := make(map[string]string)
+	for i := 1; i+1 < len(args); i += 2 {
+		flags[args[i]] = args[i+1]
+	}
+
+	source, err := os.ReadFile(flags["-i"])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: cannot read input")
+		os.Exit(2)
+	}
+
+	switch {
+	case strings.Contains(string(source), "parse error"):
+		fmt.Fprint(os.Stderr, "\nError: Parse error on line 2:\n...art TD    A --> parse error\n----------------------^\nExpecting 'SEMI', 'NEWLINE', got 'NODE_STRING'\n    at Parser.parseError (mermaid.js:1:1)\n")
+		os.Exit(1)
+	case strings.Contains(string(source), "hang"):
+		time.Sleep(time.Minute)
+	}
+
+	output := fmt.Sprintf("%s|%s|%s", flags["-e"], flags["-t"], source)
+	if err := os.WriteFile(flags["-o"], []byte(output), 0644); err != nil {
+		os.Exit(3)
+	}
+	os.Exit(0)
+}
+
+// sampleDiagram returns a flowchart whose source contains the text.
+func sampleDiagram(text string) *flowchart.Flowchart {
+	f := flowchart.NewFlowchart()
+	f.NewNode(text)
+	return f
+}
+
+// countingRunner is a Runner that writes the format to the output file and records
+// how many commands ran and how many ran at the same time.
+type countingRunner struct {
+	delay   time.Duration
+	calls   int32
+	running int32
+	peak    int32
+}
+
+func (r *countingRunner) Run(ctx context.Context, command Command) ([]byte, error) {
+	atomic.AddInt32(&r.calls, 1)
+	running := atomic.AddInt32(&r.running, 1)
+	defer atomic.AddInt32(&r.running, -1)
+	for {
+		peak := atomic.LoadInt32(&r.peak)
+		if running <= peak || atomic.CompareAndSwapInt32(&r.peak, peak, running) {
+			break
+		}
+	}
+
+	select {
+	case <-time.After(r.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var output, format string
+	for i := 0; i+1 < len(command.Args); i++ {
+		switch command.Args[i] {
+		case "-o":
+			output = command.Args[i+1]
+		case "-e":
+			format = command.Args[i+1]
+		}
+	}
+	return nil, os.WriteFile(output, []byte(format), 0644)
+}
+
+func TestRenderer_Render(t *testing.T) {
+	options := fakeExecutable(t)
+	options.Theme = "dark"
+	r := NewRenderer(options)
+
+	d := sampleDiagram("Hello")
+	d.EnableMarkdownFence()
+
+	got, err := r.Render(context.Background(), d, FormatPNG)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := "png|dark|" + Source(d)
+	if string(got) != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+	if strings.Contains(string(got), "```") {
+		t.Error("Render() should strip the markdown fence from the source")
+	}
+}
+
+func TestRenderer_Render_ParseError(t *testing.T) {
+	r := NewRenderer(fakeExecutable(t))
+
+	_, err := r.Render(context.Background(), sampleDiagram("parse error"), FormatSVG)
+
+	var renderErr *Error
+	if !errors.As(err, &renderErr) {
+		t.Fatalf("Render() error = %v, want *Error", err)
+	}
+	if renderErr.Line != 2 || renderErr.Message != "Expecting 'SEMI', 'NEWLINE', got 'NODE_STRING'" {
+		t.Errorf("Render() error line = %d, message = %q", renderErr.Line, renderErr.Message)
+	}
+	if !strings.Contains(renderErr.Output, "Parser.parseError") {
+		t.Error("Render() error should keep the executable output")
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Errorf("Render() error should wrap the exit error, got %v", renderErr.Err)
+	}
+}
+
+func TestRenderer_Render_Timeout(t *testing.T) {
+	options := fakeExecutable(t)
+	options.Timeout = 200 * time.Millisecond
+	r := NewRenderer(options)
+
+	start := time.Now()
+	_, err := r.Render(context.Background(), sampleDiagram("hang"), FormatSVG)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Render() error = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Second {
+		t.Errorf("Render() took %v, the process should be killed on timeout", elapsed)
+	}
+}
+
+func TestRenderer_Render_Cancel(t *testing.T) {
+	r := NewRenderer(Options{Runner: &countingRunner{delay: time.Minute}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	if _, err := r.Render(ctx, sampleDiagram("A"), FormatSVG); !errors.Is(err, context.Canceled) {
+		t.Errorf("Render() error = %v, want context canceled", err)
+	}
+}
+
+func TestRenderer_Render_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		format  Format
+		check   func(error) bool
+	}{
+		{
+			name:   "Unsupported format",
+			format: Format("gif"),
+			check:  func(err error) bool { return errors.Is(err, ErrUnsupportedFormat) },
+		},
+		{
+			name:    "Missing executable",
+			options: Options{Executable: filepath.Join(t.TempDir(), "missing-mmdc")},
+			format:  FormatSVG,
+			check: func(err error) bool {
+				var renderErr *Error
+				return errors.As(err, &renderErr) && strings.HasSuffix(renderErr.Executable, "missing-mmdc")
+			},
+		},
+		{
+			name: "No output",
+			options: Options{Runner: runnerFunc(func(context.Context, Command) ([]byte, error) {
+				return []byte("done"), nil
+			})},
+			format: FormatSVG,
+			check:  func(err error) bool { return errors.Is(err, os.ErrNotExist) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRenderer(tt.options).Render(context.Background(), sampleDiagram("A"), tt.format)
+			if err == nil || !tt.check(err) {
+				t.Errorf("Render() error = %v", err)
+			}
+		})
+	}
+}
+
+// runnerFunc adapts a function to the Runner interface.
+type runnerFunc func(context.Context, Command) ([]byte, error)
+
+func (f runnerFunc) Run(ctx context.Context, command Command) ([]byte, error) {
+	return f(ctx, command)
+}
+
+func TestRenderer_Arguments(t *testing.T) {
+	var got []string
+	runner := runnerFunc(func(_ context.Context, command Command) ([]byte, error) {
+		got = command.Args
+		return nil, errors.New("stop")
+	})
+
+	r := NewRenderer(Options{
+		Executable: "npx",
+		Args:       []string{"-p", "@mermaid-js/mermaid-cli", "mmdc"},
+		Runner:     runner,
+		Theme:      "forest",
+		Background: "transparent",
+		Width:      800,
+		Height:     600,
+		Scale:      1.5,
+		ConfigFile: "mermaid.json",
+	})
+	r.Render(context.Background(), sampleDiagram("A"), FormatPDF)
+
+	if len(got) < 9 {
+		t.Fatalf("Run() args = %v", got)
+	}
+	want := []string{"-p", "@mermaid-js/mermaid-cli", "mmdc", "-i", got[4], "-o", got[6], "-e", "pdf",
+		"-t", "forest", "-b", "transparent", "-w", "800", "-H", "600", "-s", "1.5", "-c", "mermaid.json"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Run() args = %v, want %v", got, want)
+	}
+	if filepath.Base(got[4]) != inputFileName || filepath.Ext(got[6]) != ".pdf" {
+		t.Errorf("Run() input and output = %q, %q", got[4], got[6])
+	}
+}
+
+func TestRenderer_Cache(t *testing.T) {
+	runner := &countingRunner{}
+	cache := NewMemoryCache()
+	r := NewRenderer(Options{Runner: runner, Cache: cache})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := r.Render(ctx, sampleDiagram("A"), FormatSVG); err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+	}
+	r.Render(ctx, sampleDiagram("A"), FormatPNG)
+	r.Render(ctx, sampleDiagram("B"), FormatSVG)
+	NewRenderer(Options{Runner: runner, Cache: cache, Theme: "dark"}).Render(ctx, sampleDiagram("A"), FormatSVG)
+
+	if runner.calls != 4 || cache.Len() != 4 {
+		t.Errorf("Render() ran %d times with %d cache entries, want 4 and 4", runner.calls, cache.Len())
+	}
+}
+
+func TestRenderer_RenderAll(t *testing.T) {
+	runner := &countingRunner{delay: 20 * time.Millisecond}
+	r := NewRenderer(Options{Runner: runner, Concurrency: 3})
+
+	var jobs []Job
+	for i := 0; i < 10; i++ {
+		format := FormatSVG
+		if i%2 == 1 {
+			format = FormatPNG
+		}
+		jobs = append(jobs, Job{Diagram: sampleDiagram(fmt.Sprint(i)), Format: format})
+	}
+	jobs = append(jobs, Job{Diagram: sampleDiagram("x"), Format: Format("bmp")})
+
+	results := r.RenderAll(context.Background(), jobs)
+
+	for i, result := range results[:10] {
+		if result.Err != nil || string(result.Output) != string(jobs[i].Format) {
+			t.Errorf("RenderAll() result %d = %q, %v", i, result.Output, result.Err)
+		}
+	}
+	if !errors.Is(results[10].Err, ErrUnsupportedFormat) {
+		t.Errorf("RenderAll() should report errors per job, got %v", results[10].Err)
+	}
+	if runner.peak > 3 || runner.peak < 2 {
+		t.Errorf("RenderAll() ran %d renders at once, want at most 3", runner.peak)
+	}
+}
+
+func TestRenderer_RenderAll_Cancel(t *testing.T) {
+	runner := &countingRunner{delay: time.Minute}
+	r := NewRenderer(Options{Runner: runner, Concurrency: 2})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var once sync.Once
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		once.Do(cancel)
+	}()
+
+	jobs := make([]Job, 6)
+	for i := range jobs {
+		jobs[i] = Job{Diagram: sampleDiagram(fmt.Sprint(i)), Format: FormatSVG}
+	}
+
+	for i, result := range r.RenderAll(ctx, jobs) {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("RenderAll() result %d error = %v, want context canceled", i, result.Err)
+		}
+	}
+	if runner.calls > 2 {
+		t.Errorf("RenderAll() started %d renders after cancellation", runner.calls)
+	}
+}
+
+func TestRenderer_RenderToFile(t *testing.T) {
+	r := NewRenderer(Options{Runner: &countingRunner{}})
+	path := filepath.Join(t.TempDir(), "out", "diagram.PNG")
+
+	if err := r.RenderToFile(context.Background(), sampleDiagram("A"), path); err != nil {
+		t.Fatalf("RenderToFile() error = %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "png" {
+		t.Errorf("RenderToFile() wrote %q", content)
+	}
+
+	if err := r.RenderToFile(context.Background(), sampleDiagram("A"), "diagram.mmd"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("RenderToFile() error = %v, want unsupported format", err)
+	}
+}
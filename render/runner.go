@@ -0,0 +1,38 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+)
+
+// Command is an invocation of the mermaid-cli executable.
+type Command struct {
+	Name string
+	Args []string
+	// Dir is the working directory, a temporary directory holding the input file.
+	Dir string
+}
+
+// Runner runs a command and returns its combined standard output and error.
+// It is the process abstraction used by Renderer and can be replaced in tests.
+type Runner interface {
+	Run(ctx context.Context, command Command) (output []byte, err error)
+}
+
+// ExecRunner runs commands as local processes. The process is killed when the context
+// is done.
+type ExecRunner struct{}
+
+// Run starts the command and waits for it to finish.
+func (ExecRunner) Run(ctx context.Context, command Command) ([]byte, error) {
+	var output bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, command.Name, command.Args...)
+	cmd.Dir = command.Dir
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+	return output.Bytes(), err
+}
@@ -0,0 +1,333 @@
+package svg
+
+import (
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+	"github.com/TyphonHill/go-mermaid/render/layout"
+)
+
+// Stroke widths of the link shapes.
+const (
+	linkWidth      float64 = 2
+	thickLinkWidth float64 = 3.5
+	dottedLinkDash string  = "3"
+	clusterRadius  float64 = 5
+)
+
+// RenderFlowchart lays out the flowchart and returns it as an SVG document.
+//
+// Subgraphs are drawn as clusters around the nodes used by their links; a node belongs
+// to the first subgraph, in declaration order, that references it. Subgraph directions
+// and curve styles are not applied, links are drawn as straight segments.
+func RenderFlowchart(f *flowchart.Flowchart, options Options) string {
+	options = options.withDefaults()
+	r := &flowchartRenderer{options: options, flowchart: f}
+	return r.render()
+}
+
+// RenderFlowchartToFile renders the flowchart as SVG and saves it to a file at the specified path.
+func RenderFlowchartToFile(f *flowchart.Flowchart, path string, options Options) error {
+	return utils.RenderToFile(path, RenderFlowchart(f, options))
+}
+
+// flowchartRenderer holds the state of a flowchart rendering.
+type flowchartRenderer struct {
+	options   Options
+	flowchart *flowchart.Flowchart
+	nodes     []*flowchart.Node
+	links     []*flowchart.Link
+	clusters  []*flowchart.Subgraph
+	index     map[*flowchart.Node]int
+	labels    []bool
+}
+
+// render builds the layout graph, computes the layout and draws the document.
+func (r *flowchartRenderer) render() string {
+	r.nodes = r.flowchart.Graph().Nodes()
+	r.links = r.flowchart.Links()
+	r.index = make(map[*flowchart.Node]int, len(r.nodes))
+	for i, node := range r.nodes {
+		r.index[node] = i
+	}
+
+	g := layout.Graph{Nodes: make([]layout.Node, len(r.nodes))}
+	r.labels = make([]bool, len(r.nodes))
+	for i, node := range r.nodes {
+		width, height := r.options.textBlockSize(nodeLabel(node), r.options.FontSize)
+		g.Nodes[i].Width, g.Nodes[i].Height, r.labels[i] = shapeSize(node.Shape, width, height)
+	}
+
+	g.Clusters = r.buildClusters(g.Nodes)
+
+	for _, link := range r.links {
+		edge := layout.Edge{From: r.index[link.From], To: r.index[link.To], MinLength: 1 + link.Length}
+		if link.Text != "" {
+			width, height := r.options.textBlockSize(link.Text, r.options.FontSize)
+			padding := r.options.FontSize * labelPaddingRatio
+			edge.LabelWidth, edge.LabelHeight = width+2*padding, height+2*padding
+		}
+		g.Edges = append(g.Edges, edge)
+	}
+
+	result := layout.Layout(g, layout.Options{
+		Direction:   layout.Direction(r.flowchart.Direction),
+		NodeSpacing: r.options.NodeSpacing,
+		RankSpacing: r.options.RankSpacing,
+	})
+
+	offsetX, offsetY := r.options.Padding, r.options.Padding
+	width := result.Width + 2*r.options.Padding
+	height := result.Height + 2*r.options.Padding
+
+	d := &document{}
+
+	if r.flowchart.Title != "" {
+		titleSize := r.options.FontSize * titleFontRatio
+		titleWidth, titleHeight := r.options.textSize(r.flowchart.Title, titleSize)
+		if titleWidth+2*r.options.Padding > width {
+			width = titleWidth + 2*r.options.Padding
+		}
+		d.text(width/2, r.options.Padding+titleHeight/2, r.flowchart.Title, "class", "title", "font-size", num(titleSize), "fill", defaultTextColor)
+		offsetY += titleHeight + r.options.Padding
+		height += titleHeight + r.options.Padding
+	}
+
+	offsetX += (width - result.Width - 2*r.options.Padding) / 2
+	translate(&result, offsetX, offsetY)
+
+	r.drawMarkers(d)
+	r.drawClusters(d, result)
+	r.drawLinks(d, result)
+	r.drawNodes(d, result)
+
+	return d.wrap(width, height, r.options)
+}
+
+// buildClusters assigns nodes to the subgraphs declaring their links and returns the
+// clusters of the subgraphs that contain at least one node.
+func (r *flowchartRenderer) buildClusters(nodes []layout.Node) []layout.Cluster {
+	g := layout.Graph{Nodes: nodes}
+	var subgraphs []*flowchart.Subgraph
+	index := make(map[*flowchart.Subgraph]int)
+
+	var walk func(children []*flowchart.Subgraph, parent int)
+	walk = func(children []*flowchart.Subgraph, parent int) {
+		for _, subgraph := range children {
+			width, height := r.options.textSize(subgraph.Title, r.options.FontSize)
+			index[subgraph] = len(subgraphs)
+			subgraphs = append(subgraphs, subgraph)
+			g.Clusters = append(g.Clusters, layout.Cluster{Parent: parent, LabelWidth: width, LabelHeight: height})
+			walk(subgraph.Subgraphs(), index[subgraph])
+		}
+	}
+	walk(r.flowchart.Subgraphs(), -1)
+
+	nodeSubgraphs := r.flowchart.NodeSubgraphs()
+	for i, node := range r.nodes {
+		nodes[i].Cluster = -1
+		if subgraph, ok := nodeSubgraphs[node]; ok {
+			nodes[i].Cluster = index[subgraph]
+		}
+	}
+
+	for _, i := range g.PruneClusters() {
+		r.clusters = append(r.clusters, subgraphs[i])
+	}
+
+	return g.Clusters
+}
+
+// drawMarkers writes the definitions of the link end markers used by the flowchart.
+func (r *flowchartRenderer) drawMarkers(d *document) {
+	used := make(map[string]bool)
+	for _, link := range r.links {
+		if link.Shape != flowchart.LinkShapeInvisible {
+			used[markerKind(link.Head)] = true
+			used[markerKind(link.Tail)] = true
+		}
+	}
+
+	drawMarkers(d, r.options.IDPrefix, used)
+}
+
+// drawClusters draws the subgraph boxes and titles.
+func (r *flowchartRenderer) drawClusters(d *document, result layout.Result) {
+	if len(r.clusters) == 0 {
+		return
+	}
+
+	d.open("class", "clusters")
+	for i, subgraph := range r.clusters {
+		box := result.Clusters[i]
+		d.open("class", "cluster", "data-id", subgraph.ID)
+		d.element("rect", "x", num(box.X), "y", num(box.Y), "width", num(box.Width), "height", num(box.Height), "rx", num(clusterRadius),
+			"fill", defaultClusterFill, "stroke", defaultClusterLine, "stroke-width", "1")
+		_, height := r.options.textSize(subgraph.Title, r.options.FontSize)
+		d.text(box.X+box.Width/2, box.Y+layout.DefaultClusterPadding/2+height/2, subgraph.Title, "fill", defaultTextColor)
+		d.close()
+	}
+	d.close()
+}
+
+// drawLinks draws the link lines and their labels.
+func (r *flowchartRenderer) drawLinks(d *document, result layout.Result) {
+	var labels document
+
+	d.open("class", "links", "fill", "none", "stroke", defaultEdgeColor)
+	for i, link := range r.links {
+		if link.Shape == flowchart.LinkShapeInvisible {
+			continue
+		}
+
+		route := result.Edges[i]
+		coordinates := r.linkPoints(link, route, result)
+
+		strokeWidth, dash := linkWidth, ""
+		switch link.Shape {
+		case flowchart.LinkShapeThick:
+			strokeWidth = thickLinkWidth
+		case flowchart.LinkShapeDotted:
+			dash = dottedLinkDash
+		}
+
+		d.element("polyline", "points", points(coordinates...), "stroke-width", num(strokeWidth), "stroke-dasharray", dash,
+			"stroke-linejoin", "round", "marker-start", markerURL(r.options.IDPrefix, markerKind(link.Tail)), "marker-end", markerURL(r.options.IDPrefix, markerKind(link.Head)))
+
+		if link.Text != "" {
+			r.drawLabel(&labels, route.Label, link.Text)
+		}
+	}
+	d.close()
+
+	if labels.sb.Len() > 0 {
+		d.open("class", "link-labels")
+		d.raw(labels.sb.String())
+		d.close()
+	}
+}
+
+// linkPoints returns the coordinates of a link route clipped to the outlines of its nodes.
+func (r *flowchartRenderer) linkPoints(link *flowchart.Link, route layout.Route, result layout.Result) []float64 {
+	routePoints := append([]layout.Point(nil), route.Points...)
+
+	if link.From != link.To && len(routePoints) >= 2 {
+		from, to := r.index[link.From], r.index[link.To]
+		last := len(routePoints) - 1
+		routePoints[0] = clip(result.Nodes[from], shapeOutline(link.From.Shape), routePoints[1])
+		routePoints[last] = clip(result.Nodes[to], shapeOutline(link.To.Shape), routePoints[last-1])
+	}
+
+	coordinates := make([]float64, 0, 2*len(routePoints))
+	for _, point := range routePoints {
+		coordinates = append(coordinates, point.X, point.Y)
+	}
+
+	return coordinates
+}
+
+// drawLabel draws a link label on a background box centered at the point.
+func (r *flowchartRenderer) drawLabel(d *document, center layout.Point, text string) {
+	width, height := r.options.textBlockSize(text, r.options.FontSize)
+	padding := r.options.FontSize * labelPaddingRatio
+	d.element("rect", "x", num(center.X-width/2-padding), "y", num(center.Y-height/2-padding),
+		"width", num(width+2*padding), "height", num(height+2*padding), "fill", defaultLabelFill)
+	r.options.drawLines(d, center.X, center.Y, text, "fill", defaultTextColor)
+}
+
+// drawNodes draws the node shapes and labels.
+func (r *flowchartRenderer) drawNodes(d *document, result layout.Result) {
+	d.open("class", "nodes")
+	for i, node := range r.nodes {
+		box := result.Nodes[i]
+		p := nodePaint(node)
+
+		d.open("class", "node", "data-id", node.ID)
+		drawShape(d, node.Shape, box, p)
+		if r.labels[i] {
+			center := box.Center()
+			r.options.drawLines(d, center.X, center.Y+labelOffset(node.Shape, box), nodeLabel(node), "fill", p.color)
+		}
+		d.close()
+	}
+	d.close()
+}
+
+// markerKind returns the marker drawn for an arrow type.
+func markerKind(arrow flowchart.LinkArrowType) string {
+	switch arrow {
+	case flowchart.LinkArrowTypeArrow, flowchart.LinkArrowTypeLeftArrow:
+		return markerArrow
+	case flowchart.LinkArrowTypeBullet:
+		return markerCircle
+	case flowchart.LinkArrowTypeCross:
+		return markerCross
+	}
+	return ""
+}
+
+// nodeLabel returns the text shown in a node, falling back to its ID.
+func nodeLabel(node *flowchart.Node) string {
+	if node.Text == "" {
+		return node.ID
+	}
+	return node.Text
+}
+
+// nodePaint resolves the colours of a node from the defaults, its class and its own style.
+func nodePaint(node *flowchart.Node) paint {
+	p := paint{fill: defaultNodeFill, stroke: defaultNodeStroke, color: defaultTextColor, strokeWidth: 1}
+
+	if node.Class != nil {
+		p = p.apply(node.Class.Style)
+	}
+
+	return p.apply(node.Style)
+}
+
+// apply overrides the paint with the values set in the style.
+func (p paint) apply(style *flowchart.NodeStyle) paint {
+	if style == nil {
+		return p
+	}
+	if style.Fill != "" {
+		p.fill = style.Fill
+	}
+	if style.Stroke != "" {
+		p.stroke = style.Stroke
+	}
+	if style.Color != "" {
+		p.color = style.Color
+	}
+	if style.StrokeWidth > 0 {
+		p.strokeWidth = float64(style.StrokeWidth)
+	}
+	if style.StrokeDash != "" {
+		p.dash = style.StrokeDash
+	}
+	return p
+}
+
+// translate moves every element of the layout by the offset.
+func translate(result *layout.Result, dx float64, dy float64) {
+	move := func(r *layout.Rect) {
+		r.X += dx
+		r.Y += dy
+	}
+
+	for i := range result.Nodes {
+		move(&result.Nodes[i])
+	}
+	for i := range result.Clusters {
+		move(&result.Clusters[i])
+	}
+	for i := range result.Edges {
+		edge := &result.Edges[i]
+		edge.Points = append([]layout.Point(nil), edge.Points...)
+		for j := range edge.Points {
+			edge.Points[j].X += dx
+			edge.Points[j].Y += dy
+		}
+		edge.Label.X += dx
+		edge.Label.Y += dy
+	}
+}
@@ -0,0 +1,212 @@
+package svg
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+)
+
+// sampleFlowchart returns a flowchart using several shapes, link styles and a subgraph.
+func sampleFlowchart() *flowchart.Flowchart {
+	f := flowchart.NewFlowchart()
+	f.Title = "Checkout"
+
+	start := f.NewNode("Start").SetShape(flowchart.NodeShapeStart)
+	decide := f.NewNode("Paid?").SetShape(flowchart.NodeShapeDecision)
+	store := f.NewNode("Orders").SetShape(flowchart.NodeShapeDatabase)
+	mail := f.NewNode("Mail")
+
+	f.NewLink(start, decide).SetText("submit")
+	f.NewLink(decide, store).SetShape(flowchart.LinkShapeDotted)
+	f.NewLink(decide, start).SetShape(flowchart.LinkShapeThick)
+	f.AddSubgraph("Backend").AddLink(store, mail)
+
+	return f
+}
+
+func TestRenderFlowchart(t *testing.T) {
+	got := RenderFlowchart(sampleFlowchart(), Options{})
+
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg"`,
+		`class="title"`,
+		`>Checkout</text>`,
+		`<marker id="gomermaid-arrow"`,
+		`<circle cx=`,
+		`<polygon points=`,
+		`stroke-dasharray="3"`,
+		`stroke-width="3.5"`,
+		`marker-end="url(#gomermaid-arrow)"`,
+		`>submit</text>`,
+		`<g class="cluster" data-id="4">`,
+		`>Backend</text>`,
+		`<g class="node" data-id="3">`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderFlowchart() missing %q", want)
+		}
+	}
+
+	if strings.Contains(got, "gomermaid-circle") || strings.Contains(got, "gomermaid-cross") {
+		t.Error("RenderFlowchart() should only define the markers in use")
+	}
+}
+
+func TestRenderFlowchart_Deterministic(t *testing.T) {
+	first := RenderFlowchart(sampleFlowchart(), Options{})
+
+	for i := 0; i < 5; i++ {
+		if got := RenderFlowchart(sampleFlowchart(), Options{}); got != first {
+			t.Fatal("RenderFlowchart() output differs between runs")
+		}
+	}
+}
+
+func TestRenderFlowchart_Styles(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	class := f.AddClass("hot")
+	class.Style.Fill = "#f00"
+	class.Style.Color = "#fff"
+
+	f.NewNode("Classed").SetClass(class)
+	f.NewNode("Styled").SetClass(class).SetStyle(&flowchart.NodeStyle{Fill: "#0f0", StrokeWidth: 3, StrokeDash: "5 5"})
+	f.NewNode("Plain")
+
+	got := RenderFlowchart(f, Options{})
+
+	for _, want := range []string{
+		`fill="#f00" stroke="#9370DB" stroke-width="1"`,
+		`fill="#0f0" stroke="#9370DB" stroke-width="3" stroke-dasharray="5 5"`,
+		`fill="#ECECFF" stroke="#9370DB" stroke-width="1"`,
+		`fill="#fff">Classed</text>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderFlowchart() missing %q", want)
+		}
+	}
+}
+
+func TestRenderFlowchart_Links(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(link *flowchart.Link)
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "Open link without arrow",
+			setup:   func(link *flowchart.Link) { link.Head = flowchart.LinkArrowTypeNone },
+			notWant: []string{"marker-end", "<defs>"},
+		},
+		{
+			name: "Bullet and cross ends",
+			setup: func(link *flowchart.Link) {
+				link.Head = flowchart.LinkArrowTypeBullet
+				link.Tail = flowchart.LinkArrowTypeCross
+			},
+			want: []string{`marker-end="url(#test-circle)"`, `marker-start="url(#test-cross)"`, `id="test-circle"`, `id="test-cross"`},
+		},
+		{
+			name:    "Invisible link",
+			setup:   func(link *flowchart.Link) { link.Shape = flowchart.LinkShapeInvisible },
+			notWant: []string{"<polyline"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := flowchart.NewFlowchart()
+			tt.setup(f.NewLink(f.NewNode("A"), f.NewNode("B")))
+
+			got := RenderFlowchart(f, Options{IDPrefix: "test"})
+
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("RenderFlowchart() missing %q", want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("RenderFlowchart() should not contain %q", notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderFlowchart_Direction(t *testing.T) {
+	size := regexp.MustCompile(`width="([0-9.]+)" height="([0-9.]+)"`)
+
+	tests := []struct {
+		direction flowchart.FlowchartDirection
+		wide      bool
+	}{
+		{direction: flowchart.FlowchartDirectionTopDown, wide: false},
+		{direction: flowchart.FlowchartDirectionBottomUp, wide: false},
+		{direction: flowchart.FlowchartDirectionLeftRight, wide: true},
+		{direction: flowchart.FlowchartDirectionRightLeft, wide: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.direction), func(t *testing.T) {
+			f := flowchart.NewFlowchart().SetDirection(tt.direction)
+			a, b, c := f.NewNode("A"), f.NewNode("B"), f.NewNode("C")
+			f.NewLink(a, b)
+			f.NewLink(b, c)
+
+			match := size.FindStringSubmatch(RenderFlowchart(f, Options{}))
+			width, _ := strconv.ParseFloat(match[1], 64)
+			height, _ := strconv.ParseFloat(match[2], 64)
+
+			if (width > height) != tt.wide {
+				t.Errorf("RenderFlowchart() size = %v x %v", width, height)
+			}
+		})
+	}
+}
+
+func TestRenderFlowchart_EmptySubgraphSkipped(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	f.NewNode("Alone")
+	f.AddSubgraph("Empty")
+
+	if got := RenderFlowchart(f, Options{}); strings.Contains(got, "cluster") {
+		t.Error("RenderFlowchart() should skip subgraphs without nodes")
+	}
+}
+
+func TestRenderFlowchart_NestedSubgraphs(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	a, b, c := f.NewNode("A"), f.NewNode("B"), f.NewNode("C")
+	outer := f.AddSubgraph("Outer")
+	outer.AddLink(a, b)
+	outer.AddSubgraph("Middle").AddSubgraph("Inner").AddLink(b, c)
+
+	got := RenderFlowchart(f, Options{})
+
+	if strings.Count(got, `<g class="cluster"`) != 3 {
+		t.Errorf("RenderFlowchart() should draw the outer, middle and inner clusters:\n%s", got)
+	}
+}
+
+func TestRenderFlowchartToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "chart.svg")
+
+	if err := RenderFlowchartToFile(sampleFlowchart(), path, Options{}); err != nil {
+		t.Fatalf("RenderFlowchartToFile() error = %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	if string(content) != RenderFlowchart(sampleFlowchart(), Options{}) {
+		t.Error("RenderFlowchartToFile() content differs from RenderFlowchart()")
+	}
+}
@@ -0,0 +1,64 @@
+package svg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Marker kinds drawn at the ends of lines.
+const (
+	markerArrow  string = "arrow"
+	markerOpen   string = "open"
+	markerCircle string = "circle"
+	markerCross  string = "cross"
+)
+
+const (
+	markerOpenString  string = `<marker id="%s" viewBox="0 0 10 10" refX="%s" refY="5" markerWidth="8" markerHeight="8" markerUnits="userSpaceOnUse" orient="auto-start-reverse">` + "\n"
+	markerCloseString string = "</marker>\n"
+	markerIDString    string = "%s-%s"
+	markerURLString   string = "url(#%s)"
+)
+
+// markerOrder is the order in which marker definitions are written.
+var markerOrder = []string{markerArrow, markerOpen, markerCircle, markerCross}
+
+// drawMarkers writes the definitions of the used marker kinds.
+func drawMarkers(d *document, prefix string, used map[string]bool) {
+	var sb strings.Builder
+	for _, kind := range markerOrder {
+		if used[kind] {
+			sb.WriteString(marker(prefix, kind))
+		}
+	}
+
+	if sb.Len() > 0 {
+		d.raw("<defs>\n" + sb.String() + "</defs>\n")
+	}
+}
+
+// markerURL returns the reference to a marker kind, or an empty string for none.
+func markerURL(prefix string, kind string) string {
+	if kind == "" {
+		return ""
+	}
+	return fmt.Sprintf(markerURLString, fmt.Sprintf(markerIDString, prefix, kind))
+}
+
+// marker returns the definition of a line end marker.
+func marker(prefix string, kind string) string {
+	var shape, refX string
+
+	switch kind {
+	case markerArrow:
+		shape, refX = `<path d="M 0 0 L 10 5 L 0 10 z" fill="`+defaultEdgeColor+`"/>`, "10"
+	case markerOpen:
+		shape, refX = `<path d="M 1 1 L 9 5 L 1 9" fill="none" stroke="`+defaultEdgeColor+`" stroke-width="1.5"/>`, "9"
+	case markerCircle:
+		shape, refX = `<circle cx="5" cy="5" r="4" fill="`+defaultEdgeColor+`"/>`, "9"
+	case markerCross:
+		shape, refX = `<path d="M 1 1 L 9 9 M 1 9 L 9 1" stroke="`+defaultEdgeColor+`" stroke-width="2"/>`, "5"
+	}
+
+	return fmt.Sprintf(markerOpenString, escape(fmt.Sprintf(markerIDString, prefix, kind)), refX) + shape + "\n" + markerCloseString
+}
@@ -0,0 +1,659 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/sequence"
+	"github.com/TyphonHill/go-mermaid/diagrams/utils"
+)
+
+// Default sequence diagram geometry, matching the mermaid.js defaults.
+// SequenceConfigurationProperties values replace them when set.
+const (
+	sequenceMarginX         float64 = 50
+	sequenceMarginY         float64 = 10
+	sequenceActorMargin     float64 = 50
+	sequenceActorWidth      float64 = 150
+	sequenceActorHeight     float64 = 65
+	sequenceBoxMargin       float64 = 10
+	sequenceBoxTextMargin   float64 = 5
+	sequenceNoteMargin      float64 = 10
+	sequenceMessageMargin   float64 = 35
+	sequenceActivationWidth float64 = 10
+	selfMessageWidth        float64 = 30
+	selfMessageHeight       float64 = 20
+	destroyMarkSize         float64 = 9
+	badgeRadius             float64 = 8
+	badgeFontRatio          float64 = 0.75
+	sequenceDash            string  = "3 3"
+	lifelineColor           string  = "#999999"
+	badgeTextColor          string  = "#FFFFFF"
+	defaultNoteFill         string  = "#FFF5AD"
+	defaultNoteStroke       string  = "#AAAA33"
+	defaultActivationFill   string  = "#F4F4F4"
+	defaultActivationStroke string  = "#666666"
+)
+
+const (
+	translateString string = "translate(%s,%s)"
+)
+
+// font holds the text attributes of a group of sequence diagram elements.
+type font struct {
+	size       float64
+	attributes []string
+}
+
+// sequenceSettings holds the geometry and fonts of a sequence diagram.
+type sequenceSettings struct {
+	marginX         float64
+	marginY         float64
+	actorMargin     float64
+	actorWidth      float64
+	actorHeight     float64
+	boxMargin       float64
+	boxTextMargin   float64
+	noteMargin      float64
+	messageMargin   float64
+	activationWidth float64
+	mirrorActors    bool
+	hideUnused      bool
+	actorFont       font
+	noteFont        font
+	messageFont     font
+}
+
+// sequenceColumn is the lifeline of an actor.
+type sequenceColumn struct {
+	actor     *sequence.Actor
+	center    float64
+	width     float64
+	height    float64
+	top       float64
+	destroyed bool
+	end       float64
+	active    []float64
+}
+
+// sequenceRenderer holds the state of a sequence diagram rendering.
+type sequenceRenderer struct {
+	options  Options
+	settings sequenceSettings
+	diagram  *sequence.Diagram
+	columns  []*sequenceColumn
+	index    map[*sequence.Actor]*sequenceColumn
+	events   []*sequence.Message
+	number   int
+	numbered bool
+	bounded  bool
+	minX     float64
+	maxX     float64
+	used     map[string]bool
+
+	lifelines   document
+	activations document
+	notes       document
+	messages    document
+	heads       document
+}
+
+// RenderSequence lays out the sequence diagram and returns it as an SVG document.
+//
+// Margins, actor sizes and fonts are taken from the sequence configuration properties
+// when they are set. Messages are numbered when autonumbering or showSequenceNumbers is
+// enabled, and actors are mirrored at the bottom unless mirrorActors is disabled.
+func RenderSequence(d *sequence.Diagram, options Options) string {
+	options = options.withDefaults()
+	r := &sequenceRenderer{
+		options:  options,
+		settings: newSequenceSettings(d.Config, options),
+		diagram:  d,
+		used:     make(map[string]bool),
+	}
+	return r.render()
+}
+
+// RenderSequenceToFile renders the sequence diagram as SVG and saves it to a file at the specified path.
+func RenderSequenceToFile(d *sequence.Diagram, path string, options Options) error {
+	return utils.RenderToFile(path, RenderSequence(d, options))
+}
+
+// newSequenceSettings resolves the sequence geometry from the configuration and the options.
+func newSequenceSettings(config sequence.SequenceConfigurationProperties, options Options) sequenceSettings {
+	length := func(value int, ok bool, fallback float64) float64 {
+		if ok && value > 0 {
+			return float64(value)
+		}
+		return fallback
+	}
+	flag := func(value bool, ok bool, fallback bool) bool {
+		if ok {
+			return value
+		}
+		return fallback
+	}
+	fontOf := func(size int, sizeOK bool, family string, familyOK bool, weight int, weightOK bool) font {
+		f := font{size: length(size, sizeOK, options.FontSize)}
+		if sizeOK && size > 0 {
+			f.attributes = append(f.attributes, "font-size", num(f.size))
+		}
+		if familyOK {
+			f.attributes = append(f.attributes, "font-family", family)
+		}
+		if weightOK && weight > 0 {
+			f.attributes = append(f.attributes, "font-weight", strconv.Itoa(weight))
+		}
+		return f
+	}
+
+	s := sequenceSettings{}
+
+	value, ok := config.DiagramMarginX()
+	s.marginX = length(value, ok, sequenceMarginX)
+	value, ok = config.DiagramMarginY()
+	s.marginY = length(value, ok, sequenceMarginY)
+	value, ok = config.ActorMargin()
+	s.actorMargin = length(value, ok, sequenceActorMargin)
+	value, ok = config.Width()
+	s.actorWidth = length(value, ok, sequenceActorWidth)
+	value, ok = config.Height()
+	s.actorHeight = length(value, ok, sequenceActorHeight)
+	value, ok = config.BoxMargin()
+	s.boxMargin = length(value, ok, sequenceBoxMargin)
+	value, ok = config.BoxTextMargin()
+	s.boxTextMargin = length(value, ok, sequenceBoxTextMargin)
+	value, ok = config.NoteMargin()
+	s.noteMargin = length(value, ok, sequenceNoteMargin)
+	value, ok = config.MessageMargin()
+	s.messageMargin = length(value, ok, sequenceMessageMargin)
+	value, ok = config.ActivationWidth()
+	s.activationWidth = length(value, ok, sequenceActivationWidth)
+
+	mirror, ok := config.MirrorActors()
+	s.mirrorActors = flag(mirror, ok, true)
+	hide, ok := config.HideUnusedParticipants()
+	s.hideUnused = flag(hide, ok, false)
+
+	size, sizeOK := config.ActorFontSize()
+	family, familyOK := config.ActorFontFamily()
+	weight, weightOK := config.ActorFontWeight()
+	s.actorFont = fontOf(size, sizeOK, family, familyOK, weight, weightOK)
+
+	size, sizeOK = config.NoteFontSize()
+	family, familyOK = config.NoteFontFamily()
+	weight, weightOK = config.NoteFontWeight()
+	s.noteFont = fontOf(size, sizeOK, family, familyOK, weight, weightOK)
+
+	size, sizeOK = config.MessageFontSize()
+	family, familyOK = config.MessageFontFamily()
+	weight, weightOK = config.MessageFontWeight()
+	s.messageFont = fontOf(size, sizeOK, family, familyOK, weight, weightOK)
+
+	return s
+}
+
+// render lays out and draws the diagram.
+func (r *sequenceRenderer) render() string {
+	r.collectEvents(r.diagram.Messages)
+
+	shown, ok := r.diagram.Config.ShowSequenceNumbers()
+	r.numbered = r.diagram.AutoNumber() || (ok && shown)
+
+	r.buildColumns()
+	r.placeColumns()
+
+	top := 0.0
+	for _, column := range r.columns {
+		if !r.isCreated(column) {
+			top = math.Max(top, column.height)
+		}
+	}
+
+	y := top
+	for _, event := range r.events {
+		y = r.drawEvent(event, y)
+	}
+	y += 2 * r.settings.boxMargin
+
+	bottom := 0.0
+	for _, column := range r.columns {
+		end := y
+		if column.destroyed {
+			end = column.end
+		}
+
+		for len(column.active) > 0 {
+			r.closeActivation(column, end)
+		}
+
+		if !r.isCreated(column) {
+			r.drawHead(&r.heads, column, 0)
+		}
+		r.lifelines.element("line", "x1", num(column.center), "y1", num(column.top+column.height), "x2", num(column.center), "y2", num(end),
+			"stroke", lifelineColor, "stroke-width", "1")
+
+		if r.settings.mirrorActors && !column.destroyed {
+			r.drawHead(&r.heads, column, y)
+			bottom = math.Max(bottom, column.height)
+		}
+	}
+
+	return r.document(y + bottom)
+}
+
+// document assembles the layers into an SVG document of the given content height.
+func (r *sequenceRenderer) document(contentHeight float64) string {
+	d := &document{}
+	width := r.maxX - r.minX + 2*r.settings.marginX
+	offsetY := r.settings.marginY
+
+	if r.diagram.Title != "" {
+		titleSize := r.options.FontSize * titleFontRatio
+		titleWidth, titleHeight := r.options.textSize(r.diagram.Title, titleSize)
+		width = math.Max(width, titleWidth+2*r.settings.marginX)
+		d.text(width/2, offsetY+titleHeight/2, r.diagram.Title, "class", "title", "font-size", num(titleSize), "fill", defaultTextColor)
+		offsetY += titleHeight + r.settings.boxMargin
+	}
+
+	drawMarkers(d, r.options.IDPrefix, r.used)
+
+	offsetX := (width-(r.maxX-r.minX))/2 - r.minX
+	d.open("transform", fmt.Sprintf(translateString, num(offsetX), num(offsetY)))
+	for _, layer := range []struct {
+		class   string
+		content *document
+	}{
+		{class: "lifelines", content: &r.lifelines},
+		{class: "activations", content: &r.activations},
+		{class: "notes", content: &r.notes},
+		{class: "messages", content: &r.messages},
+		{class: "actors", content: &r.heads},
+	} {
+		if layer.content.sb.Len() > 0 {
+			d.open("class", layer.class)
+			d.raw(layer.content.sb.String())
+			d.close()
+		}
+	}
+	d.close()
+
+	return d.wrap(width, contentHeight+offsetY+r.settings.marginY, r.options)
+}
+
+// collectEvents flattens the messages and their nested messages in drawing order.
+func (r *sequenceRenderer) collectEvents(messages []*sequence.Message) {
+	for _, message := range messages {
+		r.events = append(r.events, message)
+		r.collectEvents(message.Nested)
+	}
+}
+
+// buildColumns creates a lifeline for every displayed actor.
+func (r *sequenceRenderer) buildColumns() {
+	used := make(map[*sequence.Actor]bool)
+	for _, event := range r.events {
+		if event.Note != nil {
+			for _, actor := range event.Note.Actors {
+				used[actor] = true
+			}
+			continue
+		}
+		used[event.From] = true
+		used[event.To] = true
+	}
+
+	r.index = make(map[*sequence.Actor]*sequenceColumn)
+	for _, actor := range r.diagram.Actors {
+		if _, exists := r.index[actor]; exists || (r.settings.hideUnused && !used[actor]) {
+			continue
+		}
+
+		width, height := r.options.textBlockSize(actor.Name, r.settings.actorFont.size)
+		column := &sequenceColumn{
+			actor:  actor,
+			width:  math.Max(r.settings.actorWidth, width+4*r.settings.boxTextMargin),
+			height: math.Max(r.settings.actorHeight, height+2*r.settings.boxTextMargin),
+		}
+		r.columns = append(r.columns, column)
+		r.index[actor] = column
+	}
+}
+
+// placeColumns positions the lifelines so that messages and notes fit between them.
+func (r *sequenceRenderer) placeColumns() {
+	if len(r.columns) == 0 {
+		return
+	}
+
+	gaps := make([]float64, len(r.columns)-1)
+	for i := range gaps {
+		gaps[i] = (r.columns[i].width+r.columns[i+1].width)/2 + r.settings.actorMargin
+	}
+
+	position := make(map[*sequence.Actor]int, len(r.columns))
+	for i, column := range r.columns {
+		position[column.actor] = i
+	}
+
+	require := func(from int, to int, distance float64) {
+		if from > to {
+			from, to = to, from
+		}
+		if from < 0 || to >= len(r.columns) || from == to {
+			return
+		}
+
+		total := 0.0
+		for _, gap := range gaps[from:to] {
+			total += gap
+		}
+		if total < distance {
+			gaps[to-1] += distance - total
+		}
+	}
+
+	for _, event := range r.events {
+		if note := event.Note; note != nil {
+			if len(note.Actors) == 0 {
+				continue
+			}
+			i, ok := position[note.Actors[0]]
+			if !ok {
+				continue
+			}
+			width, _ := r.options.textBlockSize(note.Text, r.settings.noteFont.size)
+			width += 2*r.settings.noteMargin + 2*r.settings.boxMargin
+			switch note.Position {
+			case sequence.NoteLeft:
+				require(i-1, i, width)
+			case sequence.NoteRight:
+				require(i, i+1, width)
+			}
+			continue
+		}
+
+		from, fromOK := position[event.From]
+		to, toOK := position[event.To]
+		if !fromOK || !toOK {
+			continue
+		}
+
+		width, _ := r.options.textBlockSize(event.Text, r.settings.messageFont.size)
+		if from == to {
+			require(from, from+1, math.Max(selfMessageWidth, width/2)+2*r.settings.boxMargin)
+		} else {
+			require(from, to, width+2*r.settings.boxMargin+2*r.settings.activationWidth)
+		}
+	}
+
+	center := r.columns[0].width / 2
+	for i, column := range r.columns {
+		if i > 0 {
+			center += gaps[i-1]
+		}
+		column.center = center
+		r.include(center-column.width/2, center+column.width/2)
+	}
+}
+
+// drawEvent draws a message, note, creation or destruction starting below y and
+// returns the new vertical position.
+func (r *sequenceRenderer) drawEvent(event *sequence.Message, y float64) float64 {
+	if event.Note != nil {
+		return r.drawNote(event.Note, y)
+	}
+
+	to := r.index[event.To]
+
+	switch event.Type {
+	case sequence.MessageDestroy:
+		if to == nil || to.destroyed {
+			return y
+		}
+		y += 2 * r.settings.boxMargin
+		size := destroyMarkSize
+		r.messages.element("path", "d", path("M", to.center-size, y-size, "L", to.center+size, y+size, "M", to.center+size, y-size, "L", to.center-size, y+size),
+			"stroke", defaultEdgeColor, "stroke-width", "2")
+		to.destroyed, to.end = true, y
+		return y
+	case sequence.MessageCreate:
+		return r.drawCreate(event, y)
+	case sequence.MessageActivate, sequence.MessageDeactivate:
+		if event.Text != "" {
+			y = r.drawMessage(event.From, event.To, sequence.MessageSolid, event.Text, y)
+		}
+		if to == nil {
+			return y
+		}
+		if event.Type == sequence.MessageActivate {
+			to.active = append(to.active, y)
+		} else if len(to.active) > 0 {
+			r.closeActivation(to, y)
+		}
+		return y
+	}
+
+	return r.drawMessage(event.From, event.To, event.Type, event.Text, y)
+}
+
+// drawMessage draws a message arrow below y and returns the new vertical position.
+func (r *sequenceRenderer) drawMessage(fromActor *sequence.Actor, toActor *sequence.Actor, messageType sequence.MessageType, text string, y float64) float64 {
+	from, to := r.index[fromActor], r.index[toActor]
+	if from == nil || to == nil {
+		return y
+	}
+
+	_, textHeight := r.options.textBlockSize(text, r.settings.messageFont.size)
+	y += math.Max(r.settings.messageMargin, textHeight+2*r.settings.boxMargin)
+	dash, head := messageStyle(messageType)
+	r.used[head] = true
+	line := []string{"fill", "none", "stroke", defaultEdgeColor, "stroke-width", "1.5", "stroke-dasharray", dash, "marker-end", markerURL(r.options.IDPrefix, head)}
+
+	if from == to {
+		x := r.edge(from, 1)
+		r.messageText(from.center, y-textHeight/2-r.settings.boxTextMargin, text)
+		r.messages.element("path", append([]string{"d", path("M", x, y, "H", x+selfMessageWidth, "V", y+selfMessageHeight, "H", x)}, line...)...)
+		r.include(x, x+selfMessageWidth)
+		r.badge(x, y)
+		return y + selfMessageHeight
+	}
+
+	side := 1.0
+	if to.center < from.center {
+		side = -1
+	}
+
+	x1, x2 := r.edge(from, side), r.edge(to, -side)
+	r.messageText((x1+x2)/2, y-textHeight/2-r.settings.boxTextMargin, text)
+	r.messages.element("line", append([]string{"x1", num(x1), "y1", num(y), "x2", num(x2), "y2", num(y)}, line...)...)
+	r.badge(x1, y)
+
+	return y
+}
+
+// drawCreate draws the head of a created actor at the end of its creation message.
+func (r *sequenceRenderer) drawCreate(event *sequence.Message, y float64) float64 {
+	from, to := r.index[event.From], r.index[event.To]
+	if to == nil {
+		return y
+	}
+
+	_, textHeight := r.options.textBlockSize(event.Text, r.settings.messageFont.size)
+	y += math.Max(r.settings.messageMargin, textHeight+2*r.settings.boxMargin) + to.height/2
+	to.top = y - to.height/2
+	r.drawHead(&r.heads, to, to.top)
+
+	if from != nil && from != to {
+		side := 1.0
+		if to.center < from.center {
+			side = -1
+		}
+
+		x1, x2 := r.edge(from, side), to.center-side*to.width/2
+		r.used[markerArrow] = true
+		r.messageText((x1+x2)/2, y-textHeight/2-r.settings.boxTextMargin, event.Text)
+		r.messages.element("line", "x1", num(x1), "y1", num(y), "x2", num(x2), "y2", num(y),
+			"stroke", defaultEdgeColor, "stroke-width", "1.5", "stroke-dasharray", sequenceDash, "marker-end", markerURL(r.options.IDPrefix, markerArrow))
+		r.badge(x1, y)
+	}
+
+	return y + to.height/2
+}
+
+// drawNote draws a note below y and returns the new vertical position.
+func (r *sequenceRenderer) drawNote(note *sequence.Note, y float64) float64 {
+	var columns []*sequenceColumn
+	for _, actor := range note.Actors {
+		if column := r.index[actor]; column != nil {
+			columns = append(columns, column)
+		}
+	}
+	if len(columns) == 0 {
+		return y
+	}
+
+	textWidth, textHeight := r.options.textBlockSize(note.Text, r.settings.noteFont.size)
+	width, height := textWidth+2*r.settings.noteMargin, textHeight+2*r.settings.noteMargin
+	first := columns[0]
+	var left float64
+
+	switch note.Position {
+	case sequence.NoteLeft:
+		left = first.center - r.settings.boxMargin - width
+	case sequence.NoteRight:
+		left = first.center + r.settings.boxMargin
+	default:
+		low, high := first.center, first.center
+		if len(columns) > 1 {
+			low, high = math.Min(first.center, columns[1].center), math.Max(first.center, columns[1].center)
+			low -= 2 * r.settings.boxMargin
+			high += 2 * r.settings.boxMargin
+		}
+		middle := (low + high) / 2
+		left = math.Min(low, middle-width/2)
+		width = math.Max(high, middle+width/2) - left
+	}
+
+	y += r.settings.boxMargin
+	r.notes.element("rect", "x", num(left), "y", num(y), "width", num(width), "height", num(height),
+		"fill", defaultNoteFill, "stroke", defaultNoteStroke, "stroke-width", "1")
+	r.options.drawLines(&r.notes, left+width/2, y+height/2, note.Text, append([]string{"fill", defaultTextColor}, r.settings.noteFont.attributes...)...)
+	r.include(left, left+width)
+
+	return y + height
+}
+
+// drawHead draws the box or stick figure of an actor with its top at y.
+func (r *sequenceRenderer) drawHead(d *document, column *sequenceColumn, y float64) {
+	text := append([]string{"fill", defaultTextColor}, r.settings.actorFont.attributes...)
+	x := column.center
+
+	d.open("class", "actor", "data-id", column.actor.ID)
+	if column.actor.Type == sequence.ActorActor {
+		_, textHeight := r.options.textBlockSize(column.actor.Name, r.settings.actorFont.size)
+		figure := column.height - textHeight - 2*r.settings.boxTextMargin
+		radius := figure * 0.15
+		neck, waist, arms := y+2*radius, y+figure*0.65, y+figure*0.35
+
+		d.element("circle", "cx", num(x), "cy", num(y+radius), "r", num(radius), "fill", defaultNodeFill, "stroke", defaultNodeStroke, "stroke-width", "2")
+		d.element("path", "d", path("M", x, neck, "V", waist, "M", x-radius*1.6, arms, "H", x+radius*1.6,
+			"M", x-radius*1.3, y+figure, "L", x, waist, "L", x+radius*1.3, y+figure),
+			"fill", "none", "stroke", defaultNodeStroke, "stroke-width", "2")
+		r.options.drawLines(d, x, y+figure+r.settings.boxTextMargin+textHeight/2, column.actor.Name, text...)
+	} else {
+		d.element("rect", "x", num(x-column.width/2), "y", num(y), "width", num(column.width), "height", num(column.height), "rx", "3",
+			"fill", defaultNodeFill, "stroke", defaultNodeStroke, "stroke-width", "1")
+		r.options.drawLines(d, x, y+column.height/2, column.actor.Name, text...)
+	}
+	d.close()
+}
+
+// closeActivation ends the innermost activation of the column at y.
+func (r *sequenceRenderer) closeActivation(column *sequenceColumn, y float64) {
+	last := len(column.active) - 1
+	start := column.active[last]
+	column.active = column.active[:last]
+
+	width := r.settings.activationWidth
+	x := column.center - width/2 + float64(last)*width/2
+	r.activations.element("rect", "x", num(x), "y", num(start), "width", num(width), "height", num(y-start),
+		"fill", defaultActivationFill, "stroke", defaultActivationStroke, "stroke-width", "1")
+}
+
+// edge returns where a message leaves or enters the lifeline on the given side,
+// taking the open activations into account.
+func (r *sequenceRenderer) edge(column *sequenceColumn, side float64) float64 {
+	depth := len(column.active)
+	if depth == 0 {
+		return column.center
+	}
+
+	width := r.settings.activationWidth
+	return column.center + float64(depth-1)*width/2 + side*width/2
+}
+
+// messageText draws a message label centered at the position.
+func (r *sequenceRenderer) messageText(x float64, y float64, text string) {
+	if text == "" {
+		return
+	}
+
+	width, _ := r.options.textBlockSize(text, r.settings.messageFont.size)
+	r.include(x-width/2, x+width/2)
+	r.options.drawLines(&r.messages, x, y, text, append([]string{"fill", defaultTextColor}, r.settings.messageFont.attributes...)...)
+}
+
+// badge draws the sequence number of the next message when numbering is enabled.
+func (r *sequenceRenderer) badge(x float64, y float64) {
+	if !r.numbered {
+		return
+	}
+
+	r.number++
+	r.messages.element("circle", "cx", num(x), "cy", num(y), "r", num(badgeRadius), "fill", defaultEdgeColor)
+	r.messages.text(x, y, strconv.Itoa(r.number), "fill", badgeTextColor, "font-size", num(r.options.FontSize*badgeFontRatio))
+}
+
+// include extends the horizontal bounds of the drawing.
+func (r *sequenceRenderer) include(left float64, right float64) {
+	if !r.bounded {
+		r.bounded, r.minX, r.maxX = true, left, right
+		return
+	}
+	r.minX = math.Min(r.minX, left)
+	r.maxX = math.Max(r.maxX, right)
+}
+
+// isCreated reports whether the actor of the column is created by a message.
+func (r *sequenceRenderer) isCreated(column *sequenceColumn) bool {
+	for _, event := range r.events {
+		if event.Note == nil && event.Type == sequence.MessageCreate && event.To == column.actor {
+			return true
+		}
+	}
+	return false
+}
+
+// messageStyle returns the dash pattern and end marker of a message type from its
+// arrow syntax: a double dash draws a dotted line, ">>" a filled arrow head, ")" an
+// open arrow head for asynchronous messages and "x" a cross.
+func messageStyle(messageType sequence.MessageType) (dash string, head string) {
+	arrow := string(messageType)
+	if strings.HasPrefix(arrow, "--") {
+		dash = sequenceDash
+	}
+
+	switch {
+	case strings.HasSuffix(arrow, ">>"):
+		head = markerArrow
+	case strings.HasSuffix(arrow, ")"):
+		head = markerOpen
+	case strings.HasSuffix(arrow, "x"):
+		head = markerCross
+	}
+
+	return
+}
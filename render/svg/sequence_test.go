@@ -0,0 +1,324 @@
+package svg
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/sequence"
+)
+
+// sampleSequence returns a sequence diagram using actors, notes, activations and lifecycle messages.
+func sampleSequence() *sequence.Diagram {
+	d := sequence.NewDiagram()
+	d.Title = "Login"
+
+	user := d.AddActor("user", "User", sequence.ActorActor)
+	server := d.AddActor("server", "Server", sequence.ActorParticipant)
+	db := d.AddActor("db", "Database", sequence.ActorParticipant)
+
+	d.AddMessage(user, server, sequence.MessageAsync, "login")
+	d.AddMessage(server, db, sequence.MessageActivate, "query")
+	d.AddNote(sequence.NoteRight, "indexed", db)
+	d.AddMessage(server, db, sequence.MessageDeactivate, "close")
+	cache := d.CreateActor(server, "cache", "Cache", sequence.ActorParticipant)
+	d.AddNote(sequence.NoteOver, "warm up", server, cache)
+	d.DestroyActor(cache)
+	d.AddMessage(server, user, sequence.MessageDotted, "token")
+
+	return d
+}
+
+// attributeValues returns the float values of an attribute on every element with the given tag.
+func attributeValues(t *testing.T, svg string, tag string, attribute string) []float64 {
+	t.Helper()
+
+	pattern := regexp.MustCompile(`<` + tag + `(?: [^>]*)? ` + attribute + `="(-?[0-9.]+)"`)
+	var values []float64
+	for _, match := range pattern.FindAllStringSubmatch(svg, -1) {
+		value, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			t.Fatalf("ParseFloat(%q) error = %v", match[1], err)
+		}
+		values = append(values, value)
+	}
+
+	return values
+}
+
+func TestRenderSequence(t *testing.T) {
+	got := RenderSequence(sampleSequence(), Options{})
+
+	for _, want := range []string{
+		`>Login</text>`,
+		`<g class="actor" data-id="user">`,
+		`<g class="actor" data-id="cache">`,
+		`>Database</text>`,
+		`<g class="lifelines">`,
+		`<g class="activations">`,
+		`fill="#FFF5AD"`,
+		`>indexed</text>`,
+		`>warm up</text>`,
+		`marker-end="url(#gomermaid-arrow)"`,
+		`stroke-dasharray="3 3"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderSequence() missing %q", want)
+		}
+	}
+
+	if strings.Count(got, `data-id="server"`) != 2 {
+		t.Error("RenderSequence() should mirror actors at the bottom")
+	}
+
+	if strings.Count(got, `data-id="cache"`) != 1 {
+		t.Error("RenderSequence() should not mirror destroyed actors")
+	}
+
+	if strings.Contains(got, `fill="#FFFFFF"`) {
+		t.Error("RenderSequence() should not number messages without autonumbering")
+	}
+}
+
+func TestRenderSequence_Deterministic(t *testing.T) {
+	first := RenderSequence(sampleSequence(), Options{})
+
+	for i := 0; i < 5; i++ {
+		if got := RenderSequence(sampleSequence(), Options{}); got != first {
+			t.Fatal("RenderSequence() output differs between runs")
+		}
+	}
+}
+
+func TestRenderSequence_ActorTypes(t *testing.T) {
+	d := sequence.NewDiagram()
+	d.AddActor("a", "Person", sequence.ActorActor)
+	d.AddActor("b", "System", sequence.ActorParticipant)
+	d.Config.SetMirrorActors(false)
+
+	got := RenderSequence(d, Options{})
+
+	if strings.Count(got, "<circle") != 1 {
+		t.Error("RenderSequence() should draw a stick figure for actors")
+	}
+
+	if strings.Count(got, `rx="3"`) != 1 {
+		t.Error("RenderSequence() should draw a box for participants")
+	}
+}
+
+func TestRenderSequence_MessageTypes(t *testing.T) {
+	tests := []struct {
+		messageType sequence.MessageType
+		want        []string
+		notWant     []string
+	}{
+		{messageType: sequence.MessageAsync, want: []string{"url(#gomermaid-arrow)"}, notWant: []string{"stroke-dasharray"}},
+		{messageType: sequence.MessageDotted, want: []string{"url(#gomermaid-arrow)", `stroke-dasharray="3 3"`}},
+		{messageType: sequence.MessageSolid, want: []string{`stroke-dasharray="3 3"`}, notWant: []string{"marker-end"}},
+		{messageType: "-)", want: []string{"url(#gomermaid-open)", `id="gomermaid-open"`}},
+		{messageType: "-x", want: []string{"url(#gomermaid-cross)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.messageType), func(t *testing.T) {
+			d := sequence.NewDiagram()
+			a := d.AddActor("a", "A", sequence.ActorParticipant)
+			b := d.AddActor("b", "B", sequence.ActorParticipant)
+			d.AddMessage(a, b, tt.messageType, "call")
+
+			got := RenderSequence(d, Options{})
+
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("RenderSequence() missing %q", want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("RenderSequence() should not contain %q", notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderSequence_Activations(t *testing.T) {
+	d := sequence.NewDiagram()
+	a := d.AddActor("a", "A", sequence.ActorParticipant)
+	b := d.AddActor("b", "B", sequence.ActorParticipant)
+	d.AddMessage(a, b, sequence.MessageActivate, "start")
+	d.AddMessage(a, b, sequence.MessageActivate, "nested")
+	d.AddMessage(a, b, sequence.MessageDeactivate, "")
+	d.AddMessage(a, b, sequence.MessageAsync, "call")
+
+	got := RenderSequence(d, Options{})
+
+	activations := got[strings.Index(got, `<g class="activations">`):strings.Index(got, `<g class="messages">`)]
+	if strings.Count(activations, "<rect") != 2 {
+		t.Fatalf("RenderSequence() should draw two activation bars:\n%s", activations)
+	}
+
+	xs := attributeValues(t, activations, "rect", "x")
+	if xs[0] == xs[1] {
+		t.Error("RenderSequence() should offset nested activations")
+	}
+
+	x2 := attributeValues(t, got, "line", "x2")
+	last := x2[len(x2)-1]
+	if last >= attributeValues(t, got, "line", "x1")[1] {
+		t.Errorf("RenderSequence() message should end on the activation bar, x2 = %v", last)
+	}
+}
+
+func TestRenderSequence_Notes(t *testing.T) {
+	tests := []struct {
+		position sequence.NotePosition
+		check    func(left float64, right float64, center float64) bool
+	}{
+		{position: sequence.NoteLeft, check: func(left, right, center float64) bool { return right < center }},
+		{position: sequence.NoteRight, check: func(left, right, center float64) bool { return left > center }},
+		{position: sequence.NoteOver, check: func(left, right, center float64) bool { return left < center && right > center }},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.position), func(t *testing.T) {
+			d := sequence.NewDiagram()
+			a := d.AddActor("a", "A", sequence.ActorParticipant)
+			d.AddNote(tt.position, "remember", a)
+			d.Config.SetMirrorActors(false)
+
+			got := RenderSequence(d, Options{})
+			notes := got[strings.Index(got, `<g class="notes">`):]
+			left := attributeValues(t, notes, "rect", "x")[0]
+			width := attributeValues(t, notes, "rect", "width")[0]
+			center := attributeValues(t, got, "line", "x1")[0]
+
+			if !tt.check(left, left+width, center) {
+				t.Errorf("RenderSequence() note spans %v to %v around lifeline %v", left, left+width, center)
+			}
+		})
+	}
+}
+
+func TestRenderSequence_Numbering(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(d *sequence.Diagram)
+	}{
+		{name: "Autonumber", setup: func(d *sequence.Diagram) { d.EnableAutoNumber() }},
+		{name: "Show sequence numbers", setup: func(d *sequence.Diagram) { d.Config.SetShowSequenceNumbers(true) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := sequence.NewDiagram()
+			a := d.AddActor("a", "A", sequence.ActorParticipant)
+			b := d.AddActor("b", "B", sequence.ActorParticipant)
+			d.AddMessage(a, b, sequence.MessageAsync, "first")
+			d.AddMessage(b, b, sequence.MessageAsync, "second")
+			tt.setup(d)
+
+			got := RenderSequence(d, Options{})
+
+			for _, want := range []string{`fill="#FFFFFF" font-size="10.5">1</text>`, `fill="#FFFFFF" font-size="10.5">2</text>`} {
+				if !strings.Contains(got, want) {
+					t.Errorf("RenderSequence() missing %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderSequence_CreateAndDestroy(t *testing.T) {
+	d := sequence.NewDiagram()
+	a := d.AddActor("a", "A", sequence.ActorParticipant)
+	b := d.CreateActor(a, "b", "B", sequence.ActorParticipant)
+	d.AddMessage(a, b, sequence.MessageAsync, "work")
+	d.DestroyActor(b)
+
+	got := RenderSequence(d, Options{})
+
+	heads := attributeValues(t, got, "rect", "y")
+	if len(heads) != 3 || heads[0] <= heads[1] {
+		t.Errorf("RenderSequence() created actor head should start below the top, got %v", heads)
+	}
+
+	lines := attributeValues(t, got, "line", "y2")
+	if lines[1] >= lines[0] {
+		t.Errorf("RenderSequence() destroyed lifeline should end early, got %v", lines)
+	}
+
+	if !strings.Contains(got, `stroke="#333333" stroke-width="2"/>`) {
+		t.Error("RenderSequence() should mark the destruction with a cross")
+	}
+}
+
+func TestRenderSequence_Configuration(t *testing.T) {
+	d := sequence.NewDiagram()
+	a := d.AddActor("a", "A", sequence.ActorParticipant)
+	b := d.AddActor("b", "B", sequence.ActorParticipant)
+	d.AddActor("c", "Unused", sequence.ActorParticipant)
+	d.AddMessage(a, b, sequence.MessageAsync, "call")
+	d.Config.SetWidth(100).SetHeight(40).SetActorMargin(20).SetHideUnusedParticipants(true).
+		SetMessageFontSize(20).SetMessageFontFamily("courier").SetActorFontWeight(700).SetDiagramMarginX(5)
+
+	got := RenderSequence(d, Options{})
+
+	for _, want := range []string{
+		`width="100" height="40"`,
+		`font-size="20" font-family="courier">call</text>`,
+		`font-weight="700">A</text>`,
+		`<svg xmlns="http://www.w3.org/2000/svg" width="230"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderSequence() missing %q in:\n%s", want, got)
+		}
+	}
+
+	if strings.Contains(got, "Unused") {
+		t.Error("RenderSequence() should hide unused participants")
+	}
+}
+
+func TestMessageStyle(t *testing.T) {
+	tests := []struct {
+		messageType sequence.MessageType
+		dash        string
+		head        string
+	}{
+		{messageType: "->", dash: "", head: ""},
+		{messageType: "-->", dash: sequenceDash, head: ""},
+		{messageType: "->>", dash: "", head: markerArrow},
+		{messageType: "-->>", dash: sequenceDash, head: markerArrow},
+		{messageType: "--x", dash: sequenceDash, head: markerCross},
+		{messageType: "-)", dash: "", head: markerOpen},
+	}
+
+	for _, tt := range tests {
+		dash, head := messageStyle(tt.messageType)
+		if dash != tt.dash || head != tt.head {
+			t.Errorf("messageStyle(%q) = %q, %q, want %q, %q", tt.messageType, dash, head, tt.dash, tt.head)
+		}
+	}
+}
+
+func TestRenderSequenceToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sequence.svg")
+
+	if err := RenderSequenceToFile(sampleSequence(), path, Options{}); err != nil {
+		t.Fatalf("RenderSequenceToFile() error = %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	if string(content) != RenderSequence(sampleSequence(), Options{}) {
+		t.Error("RenderSequenceToFile() content differs from RenderSequence()")
+	}
+}
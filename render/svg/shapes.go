@@ -0,0 +1,325 @@
+package svg
+
+import (
+	"math"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/render/layout"
+)
+
+// Dimensions of node shapes.
+const (
+	nodePaddingX   float64 = 15
+	nodePaddingY   float64 = 10
+	smallCircle    float64 = 14
+	forkWidth      float64 = 70
+	forkHeight     float64 = 10
+	iconSize       float64 = 30
+	cornerSize     float64 = 10
+	cylinderRadius float64 = 8
+	stackOffset    float64 = 5
+	waveRatio      float64 = 0.1
+	innerGap       float64 = 4
+)
+
+// outline is the geometry used to clip edges at the border of a node.
+type outline int
+
+const (
+	outlineRect outline = iota
+	outlineCircle
+	outlineDiamond
+)
+
+// paint holds the resolved colours of an element.
+type paint struct {
+	fill        string
+	stroke      string
+	color       string
+	strokeWidth float64
+	dash        string
+}
+
+// attributes returns the fill and stroke attributes of the paint.
+func (p paint) attributes() []string {
+	dash := p.dash
+	if dash == "0" {
+		dash = ""
+	}
+	return []string{"fill", p.fill, "stroke", p.stroke, "stroke-width", num(p.strokeWidth), "stroke-dasharray", dash}
+}
+
+// shapeSize returns the size of a node with the shape around a label of the given size,
+// and whether the label is drawn inside the node.
+func shapeSize(shape flowchart.NodeShape, textWidth float64, textHeight float64) (width float64, height float64, label bool) {
+	width, height = textWidth+2*nodePaddingX, textHeight+2*nodePaddingY
+
+	switch shape {
+	case flowchart.NodeShapeStart, flowchart.NodeShapeStopDouble, flowchart.NodeShapeStopFramed:
+		diameter := math.Max(width, height)
+		return diameter, diameter, true
+	case flowchart.NodeShapeStartSmall, flowchart.NodeShapeJunction:
+		return smallCircle, smallCircle, false
+	case flowchart.NodeShapeForkJoin:
+		return forkWidth, forkHeight, false
+	case flowchart.NodeShapeSummary, flowchart.NodeShapeCollate, flowchart.NodeShapeComLink:
+		return iconSize, iconSize * 1.5, false
+	case flowchart.NodeShapeDecision:
+		return width * 1.5, height * 2, true
+	case flowchart.NodeShapeExtract, flowchart.NodeShapeManualFile:
+		return width + 2*height, height * 2, true
+	case flowchart.NodeShapePrepare, flowchart.NodeShapeOdd, flowchart.NodeShapeTerminal, flowchart.NodeShapeDelay, flowchart.NodeShapeDisplay:
+		return width + height/2, height, true
+	case flowchart.NodeShapeInputOutput, flowchart.NodeShapeOutputInput, flowchart.NodeShapeManualOperation, flowchart.NodeShapeManual:
+		return width + height, height, true
+	case flowchart.NodeShapeDatabase, flowchart.NodeShapeDiskStorage:
+		return width, height + 3*cylinderRadius, true
+	case flowchart.NodeShapeStorage, flowchart.NodeShapeStoredData:
+		return width + 2*cylinderRadius, height, true
+	case flowchart.NodeShapeDocument, flowchart.NodeShapeLinedDocument, flowchart.NodeShapeTaggedDocument, flowchart.NodeShapePaperTape:
+		return width, height * (1 + 2*waveRatio), true
+	case flowchart.NodeShapeMultiDocument, flowchart.NodeShapeMultiProcess:
+		return width + 2*stackOffset, height*(1+2*waveRatio) + 2*stackOffset, true
+	case flowchart.NodeShapeSubprocess, flowchart.NodeShapeLinedProcess, flowchart.NodeShapeInternalStorage, flowchart.NodeShapeCard:
+		return width + cornerSize, height + cornerSize/2, true
+	case flowchart.NodeShapeLoopLimit, flowchart.NodeShapeManualInput, flowchart.NodeShapeDividedProcess:
+		return width, height + cornerSize, true
+	case flowchart.NodeShapeComment, flowchart.NodeShapeCommentRight, flowchart.NodeShapeCommentBothSides:
+		return width + cornerSize, height, true
+	}
+
+	return width, height, true
+}
+
+// shapeOutline returns the geometry used to clip edges at the border of the shape.
+func shapeOutline(shape flowchart.NodeShape) outline {
+	switch shape {
+	case flowchart.NodeShapeStart, flowchart.NodeShapeStopDouble, flowchart.NodeShapeStopFramed,
+		flowchart.NodeShapeStartSmall, flowchart.NodeShapeJunction, flowchart.NodeShapeSummary:
+		return outlineCircle
+	case flowchart.NodeShapeDecision:
+		return outlineDiamond
+	}
+	return outlineRect
+}
+
+// labelOffset returns the vertical offset of the label inside the shape.
+func labelOffset(shape flowchart.NodeShape, r layout.Rect) float64 {
+	switch shape {
+	case flowchart.NodeShapeDatabase, flowchart.NodeShapeDiskStorage:
+		return cylinderRadius
+	case flowchart.NodeShapeDocument, flowchart.NodeShapeLinedDocument, flowchart.NodeShapeTaggedDocument:
+		return -r.Height * waveRatio / 2
+	case flowchart.NodeShapeLoopLimit, flowchart.NodeShapeManualInput, flowchart.NodeShapeDividedProcess:
+		return cornerSize / 2
+	case flowchart.NodeShapeExtract:
+		return r.Height / 6
+	case flowchart.NodeShapeManualFile:
+		return -r.Height / 6
+	}
+	return 0
+}
+
+// drawShape draws the outline of a node with the given shape inside the box.
+func drawShape(d *document, shape flowchart.NodeShape, r layout.Rect, p paint) {
+	x, y, w, h := r.X, r.Y, r.Width, r.Height
+	cx, cy := x+w/2, y+h/2
+	style := p.attributes()
+	line := []string{"fill", "none", "stroke", p.stroke, "stroke-width", num(p.strokeWidth)}
+	solid := []string{"fill", p.stroke, "stroke", p.stroke, "stroke-width", num(p.strokeWidth)}
+
+	polygon := func(coordinates ...float64) {
+		d.element("polygon", append([]string{"points", points(coordinates...)}, style...)...)
+	}
+	outlinePath := func(attributes []string, parts ...interface{}) {
+		d.element("path", append([]string{"d", path(parts...)}, attributes...)...)
+	}
+	rect := func(rx float64, rectX float64, rectY float64) {
+		d.element("rect", append([]string{"x", num(rectX), "y", num(rectY), "width", num(w - (rectX - x)), "height", num(h - (rectY - y)), "rx", roundedRadius(rx)}, style...)...)
+	}
+	document := func(docX float64, docY float64, docW float64, docH float64) {
+		a := docH * waveRatio
+		outlinePath(style, "M", docX, docY, "H", docX+docW, "V", docY+docH-a,
+			"C", docX+docW*0.75, docY+docH-3*a, docX+docW*0.25, docY+docH+a, docX, docY+docH-a, "Z")
+	}
+
+	switch shape {
+	case flowchart.NodeShapeEvent:
+		rect(5, x, y)
+	case flowchart.NodeShapeTerminal:
+		rect(h/2, x, y)
+	case flowchart.NodeShapeSubprocess:
+		rect(0, x, y)
+		d.element("line", append([]string{"x1", num(x + cornerSize), "y1", num(y), "x2", num(x + cornerSize), "y2", num(y + h)}, line...)...)
+		d.element("line", append([]string{"x1", num(x + w - cornerSize), "y1", num(y), "x2", num(x + w - cornerSize), "y2", num(y + h)}, line...)...)
+	case flowchart.NodeShapeLinedProcess:
+		rect(0, x, y)
+		d.element("line", append([]string{"x1", num(x + cornerSize), "y1", num(y), "x2", num(x + cornerSize), "y2", num(y + h)}, line...)...)
+	case flowchart.NodeShapeInternalStorage:
+		rect(0, x, y)
+		d.element("line", append([]string{"x1", num(x + cornerSize), "y1", num(y), "x2", num(x + cornerSize), "y2", num(y + h)}, line...)...)
+		d.element("line", append([]string{"x1", num(x), "y1", num(y + cornerSize), "x2", num(x + w), "y2", num(y + cornerSize)}, line...)...)
+	case flowchart.NodeShapeDividedProcess:
+		rect(0, x, y)
+		d.element("line", append([]string{"x1", num(x), "y1", num(y + cornerSize*1.5), "x2", num(x + w), "y2", num(y + cornerSize*1.5)}, line...)...)
+	case flowchart.NodeShapeTaggedProcess:
+		rect(0, x, y)
+		polygon(x+w-cornerSize*1.5, y+h, x+w, y+h, x+w, y+h-cornerSize*1.5)
+	case flowchart.NodeShapeMultiProcess:
+		for offset := 2 * stackOffset; offset > 0; offset -= stackOffset {
+			d.element("rect", append([]string{"x", num(x + offset), "y", num(y), "width", num(w - 2*stackOffset), "height", num(h - 2*stackOffset)}, style...)...)
+		}
+		d.element("rect", append([]string{"x", num(x), "y", num(y + 2*stackOffset), "width", num(w - 2*stackOffset), "height", num(h - 2*stackOffset)}, style...)...)
+	case flowchart.NodeShapeDatabase, flowchart.NodeShapeDiskStorage:
+		ry := cylinderRadius
+		outlinePath(style, "M", x, y+ry, "A", w/2, ry, 0, 0, 0, x+w, y+ry, "A", w/2, ry, 0, 0, 0, x, y+ry,
+			"V", y+h-ry, "A", w/2, ry, 0, 0, 0, x+w, y+h-ry, "V", y+ry)
+		if shape == flowchart.NodeShapeDiskStorage {
+			outlinePath(line, "M", x, y+3*ry, "A", w/2, ry, 0, 0, 0, x+w, y+3*ry)
+		}
+	case flowchart.NodeShapeStorage:
+		rx := cylinderRadius
+		outlinePath(style, "M", x+rx, y, "H", x+w-rx, "A", rx, h/2, 0, 0, 1, x+w-rx, y+h, "H", x+rx, "A", rx, h/2, 0, 0, 1, x+rx, y, "Z")
+		outlinePath(line, "M", x+w-rx, y, "A", rx, h/2, 0, 0, 0, x+w-rx, y+h)
+	case flowchart.NodeShapeStoredData:
+		rx := cylinderRadius
+		outlinePath(style, "M", x+rx, y, "H", x+w, "A", rx, h/2, 0, 0, 0, x+w, y+h, "H", x+rx, "A", rx, h/2, 0, 0, 1, x+rx, y, "Z")
+	case flowchart.NodeShapeStart:
+		d.element("circle", append([]string{"cx", num(cx), "cy", num(cy), "r", num(w / 2)}, style...)...)
+	case flowchart.NodeShapeStopDouble:
+		d.element("circle", append([]string{"cx", num(cx), "cy", num(cy), "r", num(w / 2)}, style...)...)
+		d.element("circle", append([]string{"cx", num(cx), "cy", num(cy), "r", num(w/2 - innerGap)}, style...)...)
+	case flowchart.NodeShapeStopFramed:
+		d.element("circle", append([]string{"cx", num(cx), "cy", num(cy), "r", num(w / 2)}, style...)...)
+		d.element("circle", append([]string{"cx", num(cx), "cy", num(cy), "r", num(w/2 - innerGap)}, line...)...)
+	case flowchart.NodeShapeStartSmall, flowchart.NodeShapeJunction:
+		d.element("circle", append([]string{"cx", num(cx), "cy", num(cy), "r", num(w / 2)}, solid...)...)
+	case flowchart.NodeShapeSummary:
+		r := w / 2
+		d.element("circle", append([]string{"cx", num(cx), "cy", num(cy), "r", num(r)}, style...)...)
+		k := r / math.Sqrt2
+		outlinePath(line, "M", cx-k, cy-k, "L", cx+k, cy+k, "M", cx+k, cy-k, "L", cx-k, cy+k)
+	case flowchart.NodeShapeForkJoin:
+		d.element("rect", append([]string{"x", num(x), "y", num(y), "width", num(w), "height", num(h)}, solid...)...)
+	case flowchart.NodeShapeOdd:
+		polygon(x, y, x+w, y, x+w, y+h, x, y+h, x+h/2, cy)
+	case flowchart.NodeShapeDecision:
+		polygon(cx, y, x+w, cy, cx, y+h, x, cy)
+	case flowchart.NodeShapePrepare:
+		s := h / 4
+		polygon(x+s, y, x+w-s, y, x+w, cy, x+w-s, y+h, x+s, y+h, x, cy)
+	case flowchart.NodeShapeInputOutput:
+		s := h / 2
+		polygon(x+s, y, x+w, y, x+w-s, y+h, x, y+h)
+	case flowchart.NodeShapeOutputInput:
+		s := h / 2
+		polygon(x, y, x+w-s, y, x+w, y+h, x+s, y+h)
+	case flowchart.NodeShapeManualOperation:
+		s := h / 2
+		polygon(x+s, y, x+w-s, y, x+w, y+h, x, y+h)
+	case flowchart.NodeShapeManual:
+		s := h / 2
+		polygon(x, y, x+w, y, x+w-s, y+h, x+s, y+h)
+	case flowchart.NodeShapeCard:
+		polygon(x+cornerSize, y, x+w, y, x+w, y+h, x, y+h, x, y+cornerSize)
+	case flowchart.NodeShapeLoopLimit:
+		polygon(x+cornerSize, y, x+w-cornerSize, y, x+w, y+cornerSize, x+w, y+h, x, y+h, x, y+cornerSize)
+	case flowchart.NodeShapeManualInput:
+		polygon(x, y+cornerSize, x+w, y, x+w, y+h, x, y+h)
+	case flowchart.NodeShapeExtract:
+		polygon(cx, y, x+w, y+h, x, y+h)
+	case flowchart.NodeShapeManualFile:
+		polygon(x, y, x+w, y, cx, y+h)
+	case flowchart.NodeShapeCollate:
+		polygon(x, y, x+w, y, x, y+h, x+w, y+h)
+	case flowchart.NodeShapeComLink:
+		polygon(x+w*0.6, y, x, y+h*0.55, x+w*0.45, y+h*0.55, x+w*0.3, y+h, x+w, y+h*0.4, x+w*0.55, y+h*0.4)
+	case flowchart.NodeShapeDelay:
+		r := h / 2
+		outlinePath(style, "M", x, y, "H", x+w-r, "A", r, r, 0, 0, 1, x+w-r, y+h, "H", x, "Z")
+	case flowchart.NodeShapeDisplay:
+		r := h / 2
+		outlinePath(style, "M", x+r, y, "H", x+w-r, "A", r, r, 0, 0, 1, x+w-r, y+h, "H", x+r, "L", x, cy, "Z")
+	case flowchart.NodeShapeDocument:
+		document(x, y, w, h)
+	case flowchart.NodeShapeLinedDocument:
+		document(x, y, w, h)
+		d.element("line", append([]string{"x1", num(x + cornerSize), "y1", num(y), "x2", num(x + cornerSize), "y2", num(y + h*(1-waveRatio))}, line...)...)
+	case flowchart.NodeShapeTaggedDocument:
+		document(x, y, w, h)
+		polygon(x+w-cornerSize*1.5, y+h*(1-waveRatio), x+w, y+h*(1-waveRatio), x+w, y+h*(1-waveRatio)-cornerSize*1.5)
+	case flowchart.NodeShapeMultiDocument:
+		docW, docH := w-2*stackOffset, h-2*stackOffset
+		document(x+2*stackOffset, y, docW, docH)
+		document(x+stackOffset, y+stackOffset, docW, docH)
+		document(x, y+2*stackOffset, docW, docH)
+	case flowchart.NodeShapePaperTape:
+		a := h * waveRatio
+		outlinePath(style, "M", x, y+a, "C", x+w*0.25, y-a, x+w*0.75, y+3*a, x+w, y+a,
+			"V", y+h-a, "C", x+w*0.75, y+h-3*a, x+w*0.25, y+h+a, x, y+h-a, "Z")
+	case flowchart.NodeShapeText:
+	case flowchart.NodeShapeComment:
+		brace(d, line, x+cornerSize/2, y, h, 1)
+	case flowchart.NodeShapeCommentRight:
+		brace(d, line, x+w-cornerSize/2, y, h, -1)
+	case flowchart.NodeShapeCommentBothSides:
+		brace(d, line, x+cornerSize/2, y, h, 1)
+		brace(d, line, x+w-cornerSize/2, y, h, -1)
+	default:
+		rect(0, x, y)
+	}
+}
+
+// brace draws a curly brace of the given height opening to the right, or to the left for a negative side.
+func brace(d *document, attributes []string, x float64, y float64, h float64, side float64) {
+	k := cornerSize / 2 * side
+	cy := y + h/2
+	d.element("path", append([]string{"d", path(
+		"M", x+k, y, "Q", x, y, x, y+cornerSize/2,
+		"V", cy-cornerSize/2, "Q", x, cy, x-k, cy,
+		"Q", x, cy, x, cy+cornerSize/2,
+		"V", y+h-cornerSize/2, "Q", x, y+h, x+k, y+h,
+	)}, attributes...)...)
+}
+
+// roundedRadius formats the corner radius of a rectangle, omitting square corners.
+func roundedRadius(rx float64) string {
+	if rx <= 0 {
+		return ""
+	}
+	return num(rx)
+}
+
+// clip returns the point where the segment from the center of the box toward target
+// leaves the outline of the node.
+func clip(r layout.Rect, kind outline, target layout.Point) layout.Point {
+	center := r.Center()
+	dx, dy := target.X-center.X, target.Y-center.Y
+	if dx == 0 && dy == 0 {
+		return center
+	}
+
+	halfW, halfH := r.Width/2, r.Height/2
+	var t float64
+
+	switch kind {
+	case outlineCircle:
+		t = math.Min(halfW, halfH) / math.Hypot(dx, dy)
+	case outlineDiamond:
+		t = 1 / (math.Abs(dx)/halfW + math.Abs(dy)/halfH)
+	default:
+		t = math.Inf(1)
+		if dx != 0 {
+			t = halfW / math.Abs(dx)
+		}
+		if dy != 0 {
+			t = math.Min(t, halfH/math.Abs(dy))
+		}
+	}
+
+	if t > 1 {
+		t = 1
+	}
+
+	return layout.Point{X: center.X + dx*t, Y: center.Y + dy*t}
+}
@@ -0,0 +1,102 @@
+package svg
+
+import (
+	"math"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/render/layout"
+)
+
+func TestShapeSize(t *testing.T) {
+	tests := []struct {
+		shape     flowchart.NodeShape
+		width     float64
+		height    float64
+		wantLabel bool
+	}{
+		{shape: flowchart.NodeShapeProcess, width: 70, height: 40, wantLabel: true},
+		{shape: flowchart.NodeShapeStart, width: 70, height: 70, wantLabel: true},
+		{shape: flowchart.NodeShapeDecision, width: 105, height: 80, wantLabel: true},
+		{shape: flowchart.NodeShapeJunction, width: smallCircle, height: smallCircle},
+		{shape: flowchart.NodeShapeForkJoin, width: forkWidth, height: forkHeight},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.shape), func(t *testing.T) {
+			width, height, label := shapeSize(tt.shape, 40, 20)
+			if width != tt.width || height != tt.height || label != tt.wantLabel {
+				t.Errorf("shapeSize() = %v, %v, %v, want %v, %v, %v", width, height, label, tt.width, tt.height, tt.wantLabel)
+			}
+		})
+	}
+}
+
+func TestDrawShape(t *testing.T) {
+	shapes := []flowchart.NodeShape{
+		flowchart.NodeShapeProcess, flowchart.NodeShapeEvent, flowchart.NodeShapeTerminal,
+		flowchart.NodeShapeSubprocess, flowchart.NodeShapeDatabase, flowchart.NodeShapeStart,
+		flowchart.NodeShapeOdd, flowchart.NodeShapeDecision, flowchart.NodeShapePrepare,
+		flowchart.NodeShapeInputOutput, flowchart.NodeShapeManual, flowchart.NodeShapeStopDouble,
+		flowchart.NodeShapeDocument, flowchart.NodeShapeMultiDocument, flowchart.NodeShapeStorage,
+		flowchart.NodeShapeDelay, flowchart.NodeShapeDisplay, flowchart.NodeShapePaperTape,
+		flowchart.NodeShapeComment, flowchart.NodeShapeComLink, flowchart.NodeShapeCollate,
+	}
+	p := paint{fill: "#fff", stroke: "#000", strokeWidth: 1}
+
+	for _, shape := range shapes {
+		t.Run(string(shape), func(t *testing.T) {
+			d := &document{}
+			drawShape(d, shape, layout.Rect{Width: 80, Height: 40}, p)
+			if d.sb.Len() == 0 {
+				t.Error("drawShape() wrote nothing")
+			}
+		})
+	}
+
+	d := &document{}
+	drawShape(d, flowchart.NodeShapeText, layout.Rect{Width: 80, Height: 40}, p)
+	if d.sb.Len() != 0 {
+		t.Errorf("drawShape() text shape should have no outline, got %s", d.sb.String())
+	}
+}
+
+func TestClip(t *testing.T) {
+	box := layout.Rect{X: 0, Y: 0, Width: 40, Height: 20}
+	below := layout.Point{X: 20, Y: 100}
+	right := layout.Point{X: 100, Y: 10}
+
+	tests := []struct {
+		name   string
+		kind   outline
+		target layout.Point
+		want   layout.Point
+	}{
+		{name: "Rectangle below", kind: outlineRect, target: below, want: layout.Point{X: 20, Y: 20}},
+		{name: "Rectangle right", kind: outlineRect, target: right, want: layout.Point{X: 40, Y: 10}},
+		{name: "Circle right", kind: outlineCircle, target: right, want: layout.Point{X: 30, Y: 10}},
+		{name: "Diamond right", kind: outlineDiamond, target: right, want: layout.Point{X: 40, Y: 10}},
+		{name: "Target inside", kind: outlineRect, target: layout.Point{X: 25, Y: 10}, want: layout.Point{X: 25, Y: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := clip(box, tt.kind, tt.target)
+			if math.Abs(got.X-tt.want.X) > 1e-9 || math.Abs(got.Y-tt.want.Y) > 1e-9 {
+				t.Errorf("clip() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaint_Attributes(t *testing.T) {
+	solid := paint{fill: "#fff", stroke: "#000", strokeWidth: 2, dash: "0"}
+	if got := attrs(solid.attributes()...); got != ` fill="#fff" stroke="#000" stroke-width="2"` {
+		t.Errorf("attributes() = %v", got)
+	}
+
+	dashed := paint{fill: "#fff", stroke: "#000", strokeWidth: 1, dash: "5 5"}
+	if got := attrs(dashed.attributes()...); got != ` fill="#fff" stroke="#000" stroke-width="1" stroke-dasharray="5 5"` {
+		t.Errorf("attributes() = %v", got)
+	}
+}
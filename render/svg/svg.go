@@ -0,0 +1,201 @@
+// Package svg renders diagrams as standalone SVG documents without external tools.
+//
+// The output does not aim for pixel parity with mermaid.js but is deterministic:
+// rendering the same diagram twice yields byte-identical documents.
+package svg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Default rendering options used for zero values.
+const (
+	DefaultFontFamily  string  = "trebuchet ms, verdana, arial, sans-serif"
+	DefaultFontSize    float64 = 14
+	DefaultPadding     float64 = 16
+	DefaultIDPrefix    string  = "gomermaid"
+	charWidthRatio     float64 = 0.6
+	lineHeightRatio    float64 = 1.2
+	titleFontRatio     float64 = 1.3
+	labelPaddingRatio  float64 = 0.3
+	svgNamespace       string  = "http://www.w3.org/2000/svg"
+	numberPrecision    float64 = 100
+	defaultEdgeColor   string  = "#333333"
+	defaultTextColor   string  = "#333333"
+	defaultNodeFill    string  = "#ECECFF"
+	defaultNodeStroke  string  = "#9370DB"
+	defaultLabelFill   string  = "#E8E8E8"
+	defaultClusterFill string  = "#FFFFDE"
+	defaultClusterLine string  = "#AAAA33"
+)
+
+const (
+	svgOpenString    string = `<svg xmlns="%s" width="%s" height="%s" viewBox="0 0 %s %s" font-family="%s" font-size="%s">` + "\n"
+	svgCloseString   string = "</svg>\n"
+	elementString    string = "<%s%s/>\n"
+	attributeString  string = ` %s="%s"`
+	textString       string = `<text x="%s" y="%s" text-anchor="middle" dominant-baseline="central"%s>%s</text>` + "\n"
+	groupOpenString  string = "<g%s>\n"
+	groupCloseString string = "</g>\n"
+)
+
+// Options controls the appearance of rendered diagrams. Zero values use the defaults.
+type Options struct {
+	FontFamily string
+	FontSize   float64
+	// Padding is the margin around the diagram.
+	Padding float64
+	// NodeSpacing and RankSpacing are passed to the layered layout.
+	NodeSpacing float64
+	RankSpacing float64
+	// IDPrefix prefixes the IDs of marker definitions so several documents can be embedded in one page.
+	IDPrefix string
+}
+
+// withDefaults replaces zero options with their defaults.
+func (o Options) withDefaults() Options {
+	if o.FontFamily == "" {
+		o.FontFamily = DefaultFontFamily
+	}
+	if o.FontSize <= 0 {
+		o.FontSize = DefaultFontSize
+	}
+	if o.Padding <= 0 {
+		o.Padding = DefaultPadding
+	}
+	if o.IDPrefix == "" {
+		o.IDPrefix = DefaultIDPrefix
+	}
+	return o
+}
+
+// textSize estimates the size of a single line of text.
+func (o Options) textSize(text string, fontSize float64) (width float64, height float64) {
+	return float64(utf8.RuneCountInString(text)) * fontSize * charWidthRatio, fontSize * lineHeightRatio
+}
+
+// document accumulates the elements of an SVG document.
+type document struct {
+	sb strings.Builder
+}
+
+// element writes a self-closing element with the given attribute name and value pairs.
+func (d *document) element(name string, attributes ...string) {
+	d.sb.WriteString(fmt.Sprintf(elementString, name, attrs(attributes...)))
+}
+
+// text writes a text element centered at the position.
+func (d *document) text(x float64, y float64, content string, attributes ...string) {
+	d.sb.WriteString(fmt.Sprintf(textString, num(x), num(y), attrs(attributes...), escape(content)))
+}
+
+// open starts a group with the given attributes.
+func (d *document) open(attributes ...string) {
+	d.sb.WriteString(fmt.Sprintf(groupOpenString, attrs(attributes...)))
+}
+
+// close ends the current group.
+func (d *document) close() {
+	d.sb.WriteString(groupCloseString)
+}
+
+// raw writes markup as is.
+func (d *document) raw(markup string) {
+	d.sb.WriteString(markup)
+}
+
+// wrap returns the document content inside an svg root element of the given size.
+func (d *document) wrap(width float64, height float64, options Options) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf(svgOpenString, svgNamespace, num(width), num(height), num(width), num(height), escape(options.FontFamily), num(options.FontSize)))
+	sb.WriteString(d.sb.String())
+	sb.WriteString(svgCloseString)
+
+	return sb.String()
+}
+
+// attrs formats attribute name and value pairs, skipping pairs with an empty value.
+func attrs(attributes ...string) string {
+	var sb strings.Builder
+
+	for i := 0; i+1 < len(attributes); i += 2 {
+		if attributes[i+1] != "" {
+			sb.WriteString(fmt.Sprintf(attributeString, attributes[i], escape(attributes[i+1])))
+		}
+	}
+
+	return sb.String()
+}
+
+// num formats a coordinate with at most two decimals.
+func num(value float64) string {
+	rounded := math.Round(value*numberPrecision) / numberPrecision
+	if rounded == 0 {
+		rounded = 0
+	}
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
+
+// points formats a list of coordinates for polygon and polyline elements.
+func points(coordinates ...float64) string {
+	parts := make([]string, 0, len(coordinates)/2)
+	for i := 0; i+1 < len(coordinates); i += 2 {
+		parts = append(parts, num(coordinates[i])+","+num(coordinates[i+1]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// path formats path commands, converting numeric arguments with num.
+func path(parts ...interface{}) string {
+	formatted := make([]string, 0, len(parts))
+	for _, part := range parts {
+		switch value := part.(type) {
+		case float64:
+			formatted = append(formatted, num(value))
+		case int:
+			formatted = append(formatted, strconv.Itoa(value))
+		default:
+			formatted = append(formatted, fmt.Sprint(value))
+		}
+	}
+	return strings.Join(formatted, " ")
+}
+
+// lineBreaks lists the separators that split labels into lines.
+var lineBreaks = strings.NewReplacer("<br/>", "\n", "<br />", "\n", "<br>", "\n")
+
+// escape escapes text for use in XML content and attribute values.
+var escape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;").Replace
+
+// textBlockSize estimates the size of a label that may span several lines.
+func (o Options) textBlockSize(text string, fontSize float64) (width float64, height float64) {
+	for _, line := range labelLines(text) {
+		lineWidth, lineHeight := o.textSize(line, fontSize)
+		if lineWidth > width {
+			width = lineWidth
+		}
+		height += lineHeight
+	}
+	return
+}
+
+// drawLines writes the lines of a label centered at the position.
+func (o Options) drawLines(d *document, x float64, y float64, text string, attributes ...string) {
+	lines := labelLines(text)
+	_, lineHeight := o.textSize("", o.FontSize)
+	top := y - lineHeight*float64(len(lines)-1)/2
+
+	for i, line := range lines {
+		d.text(x, top+lineHeight*float64(i), line, attributes...)
+	}
+}
+
+// labelLines splits a label on newlines and HTML line breaks.
+func labelLines(text string) []string {
+	return strings.Split(lineBreaks.Replace(text), "\n")
+}
@@ -0,0 +1,75 @@
+package svg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNum(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{value: 0, want: "0"},
+		{value: 12, want: "12"},
+		{value: 1.005, want: "1"},
+		{value: 3.14159, want: "3.14"},
+		{value: -0.001, want: "0"},
+		{value: -2.5, want: "-2.5"},
+	}
+
+	for _, tt := range tests {
+		if got := num(tt.value); got != tt.want {
+			t.Errorf("num(%v) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestAttrs(t *testing.T) {
+	got := attrs("fill", "#fff", "stroke", "", "data-id", `a"b`)
+	want := ` fill="#fff" data-id="a&quot;b"`
+	if got != want {
+		t.Errorf("attrs() = %v, want %v", got, want)
+	}
+}
+
+func TestPointsAndPath(t *testing.T) {
+	if got := points(1, 2.5, 3.333, 4); got != "1,2.5 3.33,4" {
+		t.Errorf("points() = %v", got)
+	}
+
+	if got := path("M", 1.5, 2, "L", 3, 4); got != "M 1.5 2 L 3 4" {
+		t.Errorf("path() = %v", got)
+	}
+}
+
+func TestLabelLines(t *testing.T) {
+	got := labelLines("one<br>two<br/>three\nfour")
+	want := []string{"one", "two", "three", "four"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("labelLines() = %v, want %v", got, want)
+	}
+}
+
+func TestOptions_TextBlockSize(t *testing.T) {
+	options := Options{}.withDefaults()
+
+	width, height := options.textBlockSize("ab<br>abcd", 10)
+	if width != 24 || height != 24 {
+		t.Errorf("textBlockSize() = %v, %v, want 24, 24", width, height)
+	}
+}
+
+func TestDocument_Wrap(t *testing.T) {
+	d := &document{}
+	d.text(5, 5, "a < b")
+
+	got := d.wrap(10, 20, Options{}.withDefaults())
+
+	for _, want := range []string{`width="10" height="20" viewBox="0 0 10 20"`, "a &lt; b", "</svg>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("wrap() missing %q in:\n%s", want, got)
+		}
+	}
+}
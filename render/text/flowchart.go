@@ -0,0 +1,147 @@
+package text
+
+import (
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/render/layout"
+)
+
+// Glyphs of the flowchart shapes drawn as a single symbol.
+var (
+	glyphStartSmall = &glyph{unicode: "●", ascii: "*"}
+	glyphJunction   = &glyph{unicode: "●", ascii: "o"}
+	glyphSummary    = &glyph{unicode: "⊗", ascii: "(x)"}
+)
+
+// RenderFlowchart returns the flowchart drawn with box-drawing or ASCII characters.
+//
+// Node shapes are approximated by the border of their box: rounded shapes get rounded
+// corners, slanted shapes get angled corners and framed shapes get double borders.
+// Subgraphs are drawn as titled boxes around the nodes used by their links, like the
+// SVG renderer does.
+func RenderFlowchart(f *flowchart.Flowchart, options Options) string {
+	s := &scene{title: f.Title, direction: layout.Direction(f.Direction)}
+	if options.Direction != "" {
+		s.direction = options.Direction
+	}
+
+	nodes := f.Graph().Nodes()
+	index := make(map[*flowchart.Node]int, len(nodes))
+	for i, node := range nodes {
+		index[node] = i
+		s.nodes = append(s.nodes, flowchartNode(node))
+	}
+
+	s.clusters = flowchartClusters(f, nodes, s.nodes)
+
+	for _, link := range f.Links() {
+		edge := sceneEdge{
+			from:      index[link.From],
+			to:        index[link.To],
+			label:     link.Text,
+			head:      flowchartMarker(link.Head),
+			tail:      flowchartMarker(link.Tail),
+			minLength: 1 + link.Length,
+		}
+		switch link.Shape {
+		case flowchart.LinkShapeDotted:
+			edge.style = lineDotted
+		case flowchart.LinkShapeThick:
+			edge.style = lineThick
+		case flowchart.LinkShapeInvisible:
+			edge.invisible = true
+		}
+		s.edges = append(s.edges, edge)
+	}
+
+	return s.render(options)
+}
+
+// flowchartNode returns the scene node approximating the shape of a flowchart node.
+func flowchartNode(node *flowchart.Node) sceneNode {
+	n := sceneNode{label: node.Text, cluster: -1}
+	if n.label == "" {
+		n.label = node.ID
+	}
+
+	switch node.Shape {
+	case flowchart.NodeShapeEvent, flowchart.NodeShapeTerminal, flowchart.NodeShapeStart,
+		flowchart.NodeShapeDelay, flowchart.NodeShapeDatabase, flowchart.NodeShapeStorage,
+		flowchart.NodeShapeDiskStorage, flowchart.NodeShapeDisplay:
+		n.style = boxRounded
+	case flowchart.NodeShapeDecision, flowchart.NodeShapePrepare, flowchart.NodeShapeOdd,
+		flowchart.NodeShapeInputOutput, flowchart.NodeShapeOutputInput, flowchart.NodeShapeManualOperation,
+		flowchart.NodeShapeManual, flowchart.NodeShapeExtract, flowchart.NodeShapeManualFile,
+		flowchart.NodeShapeCollate, flowchart.NodeShapeLoopLimit:
+		n.style = boxAngled
+	case flowchart.NodeShapeSubprocess, flowchart.NodeShapeStopDouble, flowchart.NodeShapeStopFramed,
+		flowchart.NodeShapeMultiProcess, flowchart.NodeShapeMultiDocument:
+		n.style = boxDouble
+	case flowchart.NodeShapeText, flowchart.NodeShapeComment, flowchart.NodeShapeCommentRight,
+		flowchart.NodeShapeCommentBothSides:
+		n.style = boxNone
+	case flowchart.NodeShapeStartSmall:
+		n.glyph = glyphStartSmall
+	case flowchart.NodeShapeJunction:
+		n.glyph = glyphJunction
+	case flowchart.NodeShapeSummary:
+		n.glyph = glyphSummary
+	case flowchart.NodeShapeForkJoin:
+		n.bar = true
+	}
+
+	return n
+}
+
+// flowchartClusters returns the subgraphs that contain nodes as scene clusters and assigns
+// the nodes to them.
+func flowchartClusters(f *flowchart.Flowchart, nodes []*flowchart.Node, sceneNodes []sceneNode) (clusters []sceneCluster) {
+	g := layout.Graph{Nodes: make([]layout.Node, len(nodes))}
+	var subgraphs []*flowchart.Subgraph
+	index := make(map[*flowchart.Subgraph]int)
+
+	var walk func(children []*flowchart.Subgraph, parent int)
+	walk = func(children []*flowchart.Subgraph, parent int) {
+		for _, subgraph := range children {
+			index[subgraph] = len(subgraphs)
+			subgraphs = append(subgraphs, subgraph)
+			g.Clusters = append(g.Clusters, layout.Cluster{Parent: parent})
+			walk(subgraph.Subgraphs(), index[subgraph])
+		}
+	}
+	walk(f.Subgraphs(), -1)
+
+	nodeSubgraphs := f.NodeSubgraphs()
+	for i, node := range nodes {
+		g.Nodes[i].Cluster = -1
+		if subgraph, ok := nodeSubgraphs[node]; ok {
+			g.Nodes[i].Cluster = index[subgraph]
+		}
+	}
+
+	for position, i := range g.PruneClusters() {
+		title := subgraphs[i].Title
+		if title == "" {
+			title = subgraphs[i].ID
+		}
+		clusters = append(clusters, sceneCluster{title: title, parent: g.Clusters[position].Parent})
+	}
+
+	for i := range sceneNodes {
+		sceneNodes[i].cluster = g.Nodes[i].Cluster
+	}
+
+	return
+}
+
+// flowchartMarker returns the marker drawn for a link arrow type.
+func flowchartMarker(arrow flowchart.LinkArrowType) marker {
+	switch arrow {
+	case flowchart.LinkArrowTypeArrow, flowchart.LinkArrowTypeLeftArrow:
+		return markerArrow
+	case flowchart.LinkArrowTypeBullet:
+		return markerBullet
+	case flowchart.LinkArrowTypeCross:
+		return markerCross
+	}
+	return markerNone
+}
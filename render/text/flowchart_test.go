@@ -0,0 +1,204 @@
+package text
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/flowchart"
+	"github.com/TyphonHill/go-mermaid/render/layout"
+)
+
+// fulfilmentFlowchart returns a flowchart with a node of each box drawing, every link line
+// and a subgraph frame.
+func fulfilmentFlowchart() *flowchart.Flowchart {
+	f := flowchart.NewFlowchart()
+	f.Title = "Fulfilment"
+
+	order := f.NewNode("Order").SetShape(flowchart.NodeShapeTerminal)
+	stock := f.NewNode("Stock?").SetShape(flowchart.NodeShapeDecision)
+	pick := f.NewNode("Pick").SetShape(flowchart.NodeShapeSubprocess)
+	ship := f.NewNode("Ship")
+
+	f.NewLink(order, stock).SetText("reserve")
+	f.NewLink(stock, pick).SetShape(flowchart.LinkShapeDotted)
+	f.NewLink(stock, ship).SetShape(flowchart.LinkShapeThick).SetHead(flowchart.LinkArrowTypeCross)
+	f.AddSubgraph("Warehouse").AddLink(pick, ship)
+
+	return f
+}
+
+func TestRenderFlowchart(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	a := f.NewNode("A")
+	b := f.NewNode("B")
+	f.NewLink(a, b).SetText("go")
+
+	tests := []struct {
+		name      string
+		direction flowchart.FlowchartDirection
+		options   Options
+		want      string
+	}{
+		{
+			name:      "Top to bottom",
+			direction: flowchart.FlowchartDirectionTopToBottom,
+			want:      "┌───┐\n│ A │\n└───┘\n   │\n   │\n   │\n  go\n   ▼\n┌───┐\n│ B │\n└───┘\n",
+		},
+		{
+			name:      "ASCII",
+			direction: flowchart.FlowchartDirectionTopToBottom,
+			options:   Options{ASCII: true},
+			want:      "+---+\n| A |\n+---+\n   |\n   |\n   |\n  go\n   v\n+---+\n| B |\n+---+\n",
+		},
+		{
+			name:      "Left to right",
+			direction: flowchart.FlowchartDirectionLeftRight,
+			want:      "┌───┐            ┌───┐\n│ A │───────────►│ B │\n└───┘     go     └───┘\n",
+		},
+		{
+			name:      "Direction override",
+			direction: flowchart.FlowchartDirectionTopToBottom,
+			options:   Options{Direction: layout.LeftToRight},
+			want:      "┌───┐            ┌───┐\n│ A │───────────►│ B │\n└───┘     go     └───┘\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.SetDirection(tt.direction)
+			if got := RenderFlowchart(f, tt.options); got != tt.want {
+				t.Errorf("RenderFlowchart() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderFlowchart_Subgraph(t *testing.T) {
+	labelled := flowchart.NewFlowchart()
+	client := labelled.NewNode("Client")
+	api := labelled.NewNode("API")
+	database := labelled.NewNode("Database")
+	report := labelled.NewNode("Report")
+	labelled.NewLink(client, api).SetText("request")
+	labelled.AddSubgraph("Backend").AddLink(api, database)
+	labelled.NewLink(database, report).SetText("results")
+
+	bent := flowchart.NewFlowchart()
+	decide := bent.NewNode("Paid?")
+	store := bent.NewNode("Orders")
+	mail := bent.NewNode("Mail")
+	bent.NewLink(decide, store)
+	bent.NewLink(decide, mail).SetShape(flowchart.LinkShapeThick)
+	bent.AddSubgraph("Backend").AddLink(store, mail)
+
+	tests := []struct {
+		name      string
+		flowchart *flowchart.Flowchart
+		options   Options
+		want      string
+	}{
+		{
+			name:      "Labels keep clear of the title",
+			flowchart: labelled,
+			want: "  ┌────────┐\n  │ Client │\n  └────────┘\n       │\n       │\n    request\n" +
+				"┌ Backend ───┐\n│      ▼     │\n│   ┌─────┐  │\n│   │ API │  │\n│   └─────┘  │\n" +
+				"│      │     │\n│      │     │\n│      │     │\n│      ▼     │\n│┌──────────┐│\n" +
+				"││ Database ││\n│└──────────┘│\n└──────┼─────┘\n       │\n       │\n    results\n" +
+				"       ▼\n  ┌────────┐\n  │ Report │\n  └────────┘\n",
+		},
+		{
+			name:      "Labels keep clear of the border",
+			flowchart: labelled,
+			options:   Options{Direction: layout.LeftToRight},
+			want: "                          ┌ Backend ──────────────────┐\n" +
+				"                          │                           │\n" +
+				"┌────────┐                │┌─────┐        ┌──────────┐│                ┌────────┐\n" +
+				"│ Client │────────────────►│ API │───────►│ Database │┼───────────────►│ Report │\n" +
+				"└────────┘      request   │└─────┘        └──────────┘│     results    └────────┘\n" +
+				"                          └───────────────────────────┘\n",
+		},
+		{
+			name:      "Arrow heads on the last segment",
+			flowchart: bent,
+			want: "      ┌───────┐\n      │ Paid? │\n      └───────┘\n          ┃\n      ┌───┻━━━━━━┓\n" +
+				"┌ Backend ────┐  ┃\n│     ▼       │  ┃\n│┌────────┐   │  ┃\n││ Orders │   │  ┃\n" +
+				"│└────────┘   │  ┃\n│     │       │  ┃\n│     └───┳━━━╋━━┛\n│         ┃   │\n" +
+				"│         ▼   │\n│     ┌──────┐│\n│     │ Mail ││\n│     └──────┘│\n└─────────────┘\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderFlowchart(tt.flowchart, tt.options); got != tt.want {
+				t.Errorf("RenderFlowchart() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderFlowchart_Elements(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		want    []string
+	}{
+		{
+			name: "Unicode",
+			want: []string{"Fulfilment", "╭───────╮", "/────────\\", "╔══════╗", "┌ Warehouse", "reserve", "┆", "━", "×"},
+		},
+		{
+			name:    "ASCII",
+			options: Options{ASCII: true},
+			want:    []string{"Fulfilment", ".-------.", "/--------\\", "#======#", "+ Warehouse", "reserve", ":", "=", "x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderFlowchart(fulfilmentFlowchart(), tt.options)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("RenderFlowchart() missing %q in:\n%s", want, got)
+				}
+			}
+
+			if tt.options.ASCII {
+				for _, char := range got {
+					if char > 127 {
+						t.Fatalf("RenderFlowchart() contains non-ASCII character %q", char)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRenderFlowchart_MaxWidth(t *testing.T) {
+	f := flowchart.NewFlowchart().SetDirection(flowchart.FlowchartDirectionLeftRight)
+	previous := f.NewNode("Receive the customer order")
+	for _, text := range []string{"Check the available stock", "Ship the parcel to the customer"} {
+		node := f.NewNode(text)
+		f.NewLink(previous, node)
+		previous = node
+	}
+
+	for _, maxWidth := range []int{100, 60, 20} {
+		got := RenderFlowchart(f, Options{MaxWidth: maxWidth})
+		if width(got) > maxWidth {
+			t.Errorf("RenderFlowchart() width = %d, want at most %d:\n%s", width(got), maxWidth, got)
+		}
+	}
+
+	if got := RenderFlowchart(f, Options{MaxWidth: 60}); !strings.Contains(got, "Check the") {
+		t.Errorf("RenderFlowchart() should wrap labels instead of cutting them:\n%s", got)
+	}
+}
+
+func TestRenderFlowchart_Deterministic(t *testing.T) {
+	first := RenderFlowchart(fulfilmentFlowchart(), Options{})
+	for i := 0; i < 10; i++ {
+		if got := RenderFlowchart(fulfilmentFlowchart(), Options{}); got != first {
+			t.Fatal("RenderFlowchart() output differs between runs")
+		}
+	}
+}
@@ -0,0 +1,557 @@
+package text
+
+import (
+	"math"
+
+	"github.com/TyphonHill/go-mermaid/render/layout"
+)
+
+// Spacing of graph layouts in character cells.
+const (
+	verticalNodeSpacing   float64 = 3
+	verticalRankSpacing   float64 = 4
+	horizontalNodeSpacing float64 = 2
+	horizontalRankSpacing float64 = 8
+	clusterPadding        float64 = 1
+	selfLoopSize          float64 = 3
+	barLength             int     = 7
+)
+
+// marker is the symbol drawn at the end of an edge.
+type marker int
+
+const (
+	markerNone marker = iota
+	markerArrow
+	markerBullet
+	markerCross
+)
+
+// glyph is a node drawn as a single symbol instead of a labelled box.
+type glyph struct {
+	unicode string
+	ascii   string
+}
+
+// sceneNode is a node of a graph scene.
+type sceneNode struct {
+	label string
+	style boxStyle
+	// glyph replaces the box when set.
+	glyph *glyph
+	// bar draws the node as a fork or join bar.
+	bar     bool
+	cluster int
+}
+
+// sceneEdge is an edge of a graph scene.
+type sceneEdge struct {
+	from      int
+	to        int
+	label     string
+	style     lineStyle
+	invisible bool
+	head      marker
+	tail      marker
+	minLength int
+}
+
+// sceneCluster is a titled box around nodes.
+type sceneCluster struct {
+	title  string
+	parent int
+}
+
+// scene is a graph ready to be laid out on a character grid. Flowcharts and state
+// diagrams are converted to scenes.
+type scene struct {
+	title     string
+	direction layout.Direction
+	nodes     []sceneNode
+	edges     []sceneEdge
+	clusters  []sceneCluster
+}
+
+// cell is the position of a character on the canvas.
+type cell struct {
+	x int
+	y int
+}
+
+// box is the position and size of a node on the canvas.
+type box struct {
+	x      int
+	y      int
+	width  int
+	height int
+}
+
+// contains reports whether the cell is inside the box.
+func (b box) contains(p cell) bool {
+	return p.x >= b.x && p.x < b.x+b.width && p.y >= b.y && p.y < b.y+b.height
+}
+
+// center returns the middle cell of the box.
+func (b box) center() cell {
+	return cell{x: b.x + b.width/2, y: b.y + b.height/2}
+}
+
+// render draws the scene, fitting it into the maximum width of the options.
+func (s *scene) render(options Options) string {
+	chars := options.chars()
+	return fit(options.MaxWidth, chars, func(wrap int) string {
+		return s.draw(chars, options.ASCII, wrap)
+	})
+}
+
+// draw lays out the scene with labels wrapped to the given width and prints it.
+func (s *scene) draw(chars *charset, ascii bool, wrap int) string {
+	horizontal := s.direction == layout.LeftToRight || s.direction == layout.RightToLeft
+	nodeSpacing, rankSpacing := verticalNodeSpacing, verticalRankSpacing
+	if horizontal {
+		nodeSpacing, rankSpacing = horizontalNodeSpacing, horizontalRankSpacing
+	}
+
+	g := layout.Graph{Nodes: make([]layout.Node, len(s.nodes))}
+	labels := make([][]string, len(s.nodes))
+	glyphs := make([]string, len(s.nodes))
+
+	for i, node := range s.nodes {
+		var width, height int
+		switch {
+		case node.glyph != nil:
+			glyphs[i] = node.glyph.unicode
+			if ascii {
+				glyphs[i] = node.glyph.ascii
+			}
+			width, height = len([]rune(glyphs[i])), 1
+		case node.bar && horizontal:
+			width, height = 1, barLength/2
+		case node.bar:
+			width, height = barLength, 1
+		default:
+			labels[i] = wrapLabel(node.label, wrap)
+			width, height = blockSize(labels[i])
+			width, height = width+4, height+2
+		}
+		g.Nodes[i] = layout.Node{Width: float64(width), Height: float64(height), Cluster: node.cluster}
+	}
+
+	edgeLabels := make([][]string, len(s.edges))
+	for i, edge := range s.edges {
+		e := layout.Edge{From: edge.from, To: edge.to, MinLength: edge.minLength}
+		if edge.label != "" {
+			edgeLabels[i] = wrapLabel(edge.label, wrap)
+			width, height := blockSize(edgeLabels[i])
+			e.LabelWidth, e.LabelHeight = float64(width+2), float64(height)
+		}
+		g.Edges = append(g.Edges, e)
+	}
+
+	titles := make([]string, len(s.clusters))
+	for i, cluster := range s.clusters {
+		titles[i] = cluster.title
+		if wrap > 0 && len([]rune(titles[i])) > wrap {
+			titles[i] = string([]rune(titles[i])[:wrap-1]) + string(chars.ellipsis)
+		}
+		g.Clusters = append(g.Clusters, layout.Cluster{Parent: cluster.parent, LabelWidth: float64(len([]rune(titles[i])) + 4), LabelHeight: 1})
+	}
+
+	result := layout.Layout(g, layout.Options{
+		Direction:      s.direction,
+		NodeSpacing:    nodeSpacing,
+		RankSpacing:    rankSpacing,
+		ClusterPadding: clusterPadding,
+		SelfLoopSize:   selfLoopSize,
+	})
+
+	top := 0
+	var titleLines []string
+	if s.title != "" {
+		titleLines = wrapLabel(s.title, wrap)
+		top = len(titleLines) + 1
+	}
+
+	width := int(math.Ceil(result.Width)) + 2
+	for _, line := range titleLines {
+		width = maxInt(width, len([]rune(line)))
+	}
+	c := newCanvas(chars, width, int(math.Ceil(result.Height))+top+2)
+
+	for i, line := range titleLines {
+		c.center(width/2, i, line)
+	}
+
+	toBox := func(r layout.Rect) box {
+		return box{x: int(math.Round(r.X)), y: int(math.Round(r.Y)) + top, width: int(math.Round(r.Width)), height: int(math.Round(r.Height))}
+	}
+	toCell := func(p layout.Point) cell {
+		return cell{x: int(math.Round(p.X)), y: int(math.Round(p.Y)) + top}
+	}
+
+	for i, rect := range result.Clusters {
+		if rect.Width <= 0 {
+			continue
+		}
+		b := toBox(rect)
+		right, bottom := b.x+b.width-1, b.y+b.height-1
+		for x := b.x; x <= right; x++ {
+			c.reserve(x, b.y)
+			c.reserve(x, bottom)
+		}
+		for y := b.y; y <= bottom; y++ {
+			c.reserve(b.x, y)
+			c.reserve(right, y)
+		}
+		c.segment(b.x, b.y, right, b.y, lineSolid)
+		c.segment(b.x, bottom, right, bottom, lineSolid)
+		c.segment(b.x, b.y, b.x, bottom, lineSolid)
+		c.segment(right, b.y, right, bottom, lineSolid)
+		if titles[i] != "" {
+			c.write(b.x+1, b.y, " "+titles[i]+" ")
+		}
+	}
+
+	boxes := make([]box, len(s.nodes))
+	for i, node := range s.nodes {
+		b := toBox(result.Nodes[i])
+		boxes[i] = b
+
+		switch {
+		case glyphs[i] != "":
+			c.write(b.x, b.y, glyphs[i])
+		case node.bar:
+			bar := chars.lines[lineThick][left|right]
+			if horizontal {
+				bar = chars.lines[lineThick][up|down]
+			}
+			for y := b.y; y < b.y+b.height; y++ {
+				for x := b.x; x < b.x+b.width; x++ {
+					c.set(x, y, bar)
+				}
+			}
+		default:
+			c.box(b.x, b.y, b.width, b.height, node.style)
+			for row, line := range labels[i] {
+				c.center(b.x+b.width/2, b.y+1+row, line)
+			}
+		}
+	}
+
+	paths := make([][]cell, len(s.edges))
+	for i, edge := range s.edges {
+		if edge.invisible {
+			continue
+		}
+
+		points := make([]cell, 0, len(result.Edges[i].Points))
+		for _, point := range result.Edges[i].Points {
+			points = append(points, toCell(point))
+		}
+		if edge.from != edge.to && len(points) >= 2 {
+			last := len(points) - 1
+			points[0] = anchor(boxes[edge.from], points[1], horizontal)
+			for j := 1; j < last; j++ {
+				points[j] = align(points[j], points[j-1], horizontal)
+			}
+			points[last] = anchor(boxes[edge.to], points[last-1], horizontal)
+		}
+
+		corners := c.orthogonal(points, horizontal)
+		for j := 0; j+1 < len(corners); j++ {
+			c.segment(corners[j].x, corners[j].y, corners[j+1].x, corners[j+1].y, edge.style)
+		}
+
+		path := expand(corners)
+		paths[i] = path
+		c.endMarker(path, boxes[edge.to], edge.head, true)
+		c.endMarker(path, boxes[edge.from], edge.tail, false)
+	}
+
+	for i, edge := range s.edges {
+		if edge.invisible || len(edgeLabels[i]) == 0 {
+			continue
+		}
+		lines := make([]string, len(edgeLabels[i]))
+		for row, line := range edgeLabels[i] {
+			lines[row] = " " + line + " "
+		}
+		center := c.labelCenter(toCell(result.Edges[i].Label), lines, paths[i], boxes[edge.from], boxes[edge.to])
+		first := center.y - (len(lines)-1)/2
+		for row, line := range lines {
+			c.center(center.x, first+row, line)
+		}
+	}
+
+	return c.String()
+}
+
+// labelCenter returns where the label of an edge is centered. The label stays at the
+// position of the layout when it fits there, and otherwise moves along the path of the
+// edge to the closest position where it covers no text, node box or cluster border.
+func (c *canvas) labelCenter(center cell, lines []string, path []cell, from box, to box) cell {
+	width, height := blockSize(lines)
+	fits := func(center cell) bool {
+		area := box{x: center.x - width/2, y: center.y - (height-1)/2, width: width, height: height}
+		for y := area.y; y < area.y+area.height; y++ {
+			for x := area.x; x < area.x+area.width; x++ {
+				if !c.isFree(x, y) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	if fits(center) || len(path) == 0 {
+		return center
+	}
+
+	// The label keeps its offset from the path, such as below the line of horizontal layouts.
+	nearest := 0
+	for j, point := range path {
+		if distance(point, center) < distance(path[nearest], center) {
+			nearest = j
+		}
+	}
+	offset := cell{x: center.x - path[nearest].x, y: center.y - path[nearest].y}
+
+	for step := 1; step < len(path); step++ {
+		for _, j := range []int{nearest - step, nearest + step} {
+			if j < 0 || j >= len(path) || from.contains(path[j]) || to.contains(path[j]) {
+				continue
+			}
+			if candidate := (cell{x: path[j].x + offset.x, y: path[j].y + offset.y}); fits(candidate) {
+				return candidate
+			}
+		}
+	}
+
+	return center
+}
+
+// distance returns the number of horizontal and vertical steps between two cells.
+func distance(a cell, b cell) int {
+	dx, dy := a.x-b.x, a.y-b.y
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	return dx + dy
+}
+
+// orthogonal turns a route into horizontal and vertical segments. Diagonal steps are split
+// into three segments that turn on the free row (or column for horizontal layouts) closest
+// to the middle of the step. The turn keeps a free cell on both sides, so the segments
+// entering and leaving a node box are long enough to carry an end marker.
+func (c *canvas) orthogonal(points []cell, horizontal bool) []cell {
+	if len(points) == 0 {
+		return nil
+	}
+
+	corners := []cell{points[0]}
+	for i := 0; i+1 < len(points); i++ {
+		a, b := points[i], points[i+1]
+		if a.x != b.x && a.y != b.y {
+			// Route points between the ends may serve as the turn themselves.
+			var before, after int
+			if i > 0 {
+				before = 1
+			}
+			if i+2 < len(points) {
+				after = 1
+			}
+			if horizontal {
+				step := sign(b.x - a.x)
+				x := c.turn(a.x-before*step, b.x+after*step, func(x int) bool {
+					return c.freeColumn(x, a.y, b.y) && !c.isFixed(x-step, a.y) && !c.isFixed(x+step, b.y)
+				})
+				corners = append(corners, cell{x: x, y: a.y}, cell{x: x, y: b.y})
+			} else {
+				step := sign(b.y - a.y)
+				y := c.turn(a.y-before*step, b.y+after*step, func(y int) bool {
+					return c.freeRow(y, a.x, b.x) && !c.isFixed(a.x, y-step) && !c.isFixed(b.x, y+step)
+				})
+				corners = append(corners, cell{x: a.x, y: y}, cell{x: b.x, y: y})
+			}
+		}
+		corners = append(corners, b)
+	}
+
+	return corners
+}
+
+// turn returns the position between from and to, exclusive, closest to the middle that
+// satisfies free, or the middle when there is none.
+func (c *canvas) turn(from int, to int, free func(int) bool) int {
+	if from > to {
+		from, to = to, from
+	}
+
+	middle := (from + to) / 2
+	for offset := 0; offset <= to-from; offset++ {
+		for _, candidate := range []int{middle - offset, middle + offset} {
+			if candidate > from && candidate < to && free(candidate) {
+				return candidate
+			}
+		}
+	}
+
+	return middle
+}
+
+// freeRow reports whether no fixed cell lies on the row between two columns and the row
+// does not run along a cluster border.
+func (c *canvas) freeRow(y int, x1 int, x2 int) bool {
+	if x1 > x2 {
+		x1, x2 = x2, x1
+	}
+	for x := x1; x <= x2; x++ {
+		if c.isFixed(x, y) || c.isReserved(x, y) && (c.isReserved(x-1, y) || c.isReserved(x+1, y)) {
+			return false
+		}
+	}
+	return true
+}
+
+// freeColumn reports whether no fixed cell lies on the column between two rows and the
+// column does not run along a cluster border.
+func (c *canvas) freeColumn(x int, y1 int, y2 int) bool {
+	if y1 > y2 {
+		y1, y2 = y2, y1
+	}
+	for y := y1; y <= y2; y++ {
+		if c.isFixed(x, y) || c.isReserved(x, y) && (c.isReserved(x, y-1) || c.isReserved(x, y+1)) {
+			return false
+		}
+	}
+	return true
+}
+
+// endMarker draws the marker of an edge end on the last cell outside the node box.
+// The head is searched from the end of the path and the tail from its start.
+func (c *canvas) endMarker(path []cell, node box, kind marker, head bool) {
+	if kind == markerNone || len(path) < 2 {
+		return
+	}
+
+	index, step := len(path)-1, -1
+	if !head {
+		index, step = 0, 1
+	}
+
+	for ; index >= 0 && index < len(path); index += step {
+		if !node.contains(path[index]) {
+			break
+		}
+	}
+	if index < 0 || index >= len(path) {
+		return
+	}
+
+	var char rune
+	switch kind {
+	case markerBullet:
+		char = c.chars.bullet
+	case markerCross:
+		char = c.chars.cross
+	default:
+		// The arrow points into the box, or along the path when it ends outside the box.
+		if inner := index - step; inner >= 0 && inner < len(path) {
+			char = c.chars.arrows[direction(path[index], path[inner])]
+		} else {
+			char = c.chars.arrows[direction(path[index+step], path[index])]
+		}
+	}
+
+	target := path[index]
+	c.set(target.x, target.y, char)
+}
+
+// align moves a route point that is one cell off the previous point in the cross direction
+// of the layout onto its line, avoiding small jogs.
+func align(point cell, previous cell, horizontal bool) cell {
+	if horizontal && (point.y-previous.y == 1 || previous.y-point.y == 1) {
+		point.y = previous.y
+	}
+	if !horizontal && (point.x-previous.x == 1 || previous.x-point.x == 1) {
+		point.x = previous.x
+	}
+	return point
+}
+
+// anchor returns the cell inside a node box where an edge starts or ends. The edge leaves
+// the box in line with the next route point when the box is wide enough, and from its
+// center otherwise.
+func anchor(node box, toward cell, horizontal bool) cell {
+	center := node.center()
+	if horizontal {
+		if toward.y > node.y && toward.y < node.y+node.height-1 {
+			center.y = toward.y
+		}
+	} else if toward.x > node.x && toward.x < node.x+node.width-1 {
+		center.x = toward.x
+	}
+	return center
+}
+
+// direction returns the index of the arrow pointing from one cell to the next.
+func direction(from cell, to cell) int {
+	switch {
+	case to.y < from.y:
+		return 0
+	case to.y > from.y:
+		return 1
+	case to.x < from.x:
+		return 2
+	}
+	return 3
+}
+
+// expand lists every cell of a path made of horizontal and vertical segments.
+func expand(corners []cell) []cell {
+	if len(corners) == 0 {
+		return nil
+	}
+
+	path := []cell{corners[0]}
+	for i := 0; i+1 < len(corners); i++ {
+		current, target := corners[i], corners[i+1]
+		for current != target {
+			switch {
+			case current.x < target.x:
+				current.x++
+			case current.x > target.x:
+				current.x--
+			case current.y < target.y:
+				current.y++
+			default:
+				current.y--
+			}
+			path = append(path, current)
+		}
+	}
+
+	return path
+}
+
+// sign returns -1, 0 or 1 for negative, zero and positive integers.
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// maxInt returns the larger of two integers.
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,533 @@
+package text
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/sequence"
+)
+
+// Spacing of sequence diagrams in character cells.
+const (
+	sequenceActorMargin int = 3
+	sequenceLabelMargin int = 4
+	sequenceNoteMargin  int = 1
+	selfMessageWidth    int = 3
+)
+
+// sequenceColumn is the lifeline of an actor.
+type sequenceColumn struct {
+	actor     *sequence.Actor
+	name      []string
+	center    int
+	width     int
+	height    int
+	top       int
+	created   bool
+	destroyed bool
+	end       int
+	active    []int
+}
+
+// sequenceDrawer holds the state of a sequence diagram drawing.
+type sequenceDrawer struct {
+	diagram  *sequence.Diagram
+	chars    *charset
+	wrap     int
+	canvas   *canvas
+	columns  []*sequenceColumn
+	index    map[*sequence.Actor]*sequenceColumn
+	events   []*sequence.Message
+	numbered bool
+	number   int
+	// activations are the spans drawn on the lifelines once all lines are placed.
+	activations [][3]int
+}
+
+// RenderSequence returns the sequence diagram drawn with box-drawing or ASCII characters.
+//
+// Messages are drawn as arrows below their label, with dotted lines for the dashed
+// arrow types, and are numbered when autonumbering or showSequenceNumbers is enabled.
+// Activations are drawn as thick segments of the lifelines. The hideUnusedParticipants
+// and mirrorActors configuration properties are honoured.
+func RenderSequence(d *sequence.Diagram, options Options) string {
+	chars := options.chars()
+	return fit(options.MaxWidth, chars, func(wrap int) string {
+		r := &sequenceDrawer{diagram: d, chars: chars, wrap: wrap}
+		return r.draw()
+	})
+}
+
+// draw lays out the lifelines and draws the diagram.
+func (r *sequenceDrawer) draw() string {
+	r.collectEvents(r.diagram.Messages)
+
+	shown, ok := r.diagram.Config.ShowSequenceNumbers()
+	r.numbered = r.diagram.AutoNumber() || (ok && shown)
+
+	r.buildColumns()
+	left, right := r.placeColumns()
+
+	var title []string
+	if r.diagram.Title != "" {
+		title = wrapLabel(r.diagram.Title, r.wrap)
+		titleWidth, _ := blockSize(title)
+		right = maxInt(right, left+titleWidth)
+	}
+	for _, column := range r.columns {
+		column.center -= left
+	}
+
+	r.canvas = newCanvas(r.chars, right-left+1, r.heightBound(len(title)))
+	for i, line := range title {
+		r.canvas.center((right-left)/2, i, line)
+	}
+
+	top := 0
+	if len(title) > 0 {
+		top = len(title) + 1
+	}
+
+	heads := 0
+	for _, column := range r.columns {
+		if !column.created {
+			column.top = top
+			heads = maxInt(heads, column.height)
+		}
+	}
+
+	y := top + heads
+	r.number = 0
+	for _, event := range r.events {
+		y = r.drawEvent(event, y)
+	}
+	y++
+
+	mirror, ok := r.diagram.Config.MirrorActors()
+	mirror = mirror || !ok
+	for _, column := range r.columns {
+		end := y
+		if column.destroyed {
+			end = column.end
+		}
+		for len(column.active) > 0 {
+			r.closeActivation(column, end)
+		}
+
+		r.canvas.segment(column.center, column.top+column.height, column.center, end, lineSolid)
+		r.drawHead(column, column.top)
+		if mirror && !column.destroyed {
+			r.drawHead(column, y+1)
+		}
+	}
+
+	for _, span := range r.activations {
+		x := span[0]
+		for row := span[1]; row <= span[2]; row++ {
+			if r.canvas.inside(x, row) && r.canvas.cells[row][x] == 0 && r.canvas.masks[row][x] == up|down {
+				r.canvas.cells[row][x] = r.chars.activation
+			}
+		}
+	}
+
+	return r.canvas.String()
+}
+
+// collectEvents flattens the messages and their nested messages in drawing order.
+func (r *sequenceDrawer) collectEvents(messages []*sequence.Message) {
+	for _, message := range messages {
+		r.events = append(r.events, message)
+		r.collectEvents(message.Nested)
+	}
+}
+
+// buildColumns creates a lifeline for every displayed actor.
+func (r *sequenceDrawer) buildColumns() {
+	used := make(map[*sequence.Actor]bool)
+	created := make(map[*sequence.Actor]bool)
+	for _, event := range r.events {
+		if event.Note != nil {
+			for _, actor := range event.Note.Actors {
+				used[actor] = true
+			}
+			continue
+		}
+		used[event.From] = true
+		used[event.To] = true
+		if event.Type == sequence.MessageCreate {
+			created[event.To] = true
+		}
+	}
+
+	hideUnused, _ := r.diagram.Config.HideUnusedParticipants()
+	r.index = make(map[*sequence.Actor]*sequenceColumn)
+	for _, actor := range r.diagram.Actors {
+		if _, exists := r.index[actor]; exists || (hideUnused && !used[actor]) {
+			continue
+		}
+
+		name := wrapLabel(actor.Name, r.wrap)
+		width, height := blockSize(name)
+		column := &sequenceColumn{actor: actor, name: name, width: width + 4, height: height + 2, created: created[actor]}
+		r.columns = append(r.columns, column)
+		r.index[actor] = column
+	}
+}
+
+// placeColumns positions the lifelines so that labels and notes fit between them and
+// returns the horizontal extent of the drawing.
+func (r *sequenceDrawer) placeColumns() (left int, right int) {
+	if len(r.columns) == 0 {
+		return 0, 0
+	}
+
+	gaps := make([]int, len(r.columns)-1)
+	for i := range gaps {
+		gaps[i] = (r.columns[i].width+r.columns[i+1].width+1)/2 + sequenceActorMargin
+	}
+
+	position := make(map[*sequence.Actor]int, len(r.columns))
+	for i, column := range r.columns {
+		position[column.actor] = i
+	}
+
+	require := func(from int, to int, distance int) {
+		if from > to {
+			from, to = to, from
+		}
+		if from < 0 || to >= len(r.columns) || from == to {
+			return
+		}
+
+		total := 0
+		for _, gap := range gaps[from:to] {
+			total += gap
+		}
+		if total < distance {
+			gaps[to-1] += distance - total
+		}
+	}
+
+	// extents records how far labels and notes reach from a lifeline.
+	type extent struct{ column, from, to int }
+	var extents []extent
+
+	number := 0
+	for _, event := range r.events {
+		if note := event.Note; note != nil {
+			i, ok := r.noteColumn(note, position)
+			if !ok {
+				continue
+			}
+			width, _ := blockSize(wrapLabel(note.Text, r.wrap))
+			width += 4
+			switch note.Position {
+			case sequence.NoteLeft:
+				require(i-1, i, width+sequenceNoteMargin+1)
+				extents = append(extents, extent{i, -width - sequenceNoteMargin, 0})
+			case sequence.NoteRight:
+				require(i, i+1, width+sequenceNoteMargin+1)
+				extents = append(extents, extent{i, 0, width + sequenceNoteMargin})
+			default:
+				extents = append(extents, extent{i, -(width+1)/2 - 1, width/2 + 1})
+			}
+			continue
+		}
+
+		from, fromOK := position[event.From]
+		to, toOK := position[event.To]
+		if !fromOK || !toOK || event.Type == sequence.MessageDestroy {
+			continue
+		}
+		if !r.isMessage(event) {
+			continue
+		}
+
+		number++
+		width, _ := blockSize(wrapLabel(r.label(event.Text, number), r.wrap))
+		if from == to {
+			if from+1 < len(r.columns) {
+				require(from, from+1, maxInt(selfMessageWidth, width+2)+1+r.columns[from+1].width/2)
+			}
+			extents = append(extents, extent{from, 0, maxInt(selfMessageWidth, width+2)})
+		} else {
+			require(from, to, width+sequenceLabelMargin)
+		}
+	}
+
+	center := 0
+	for i, column := range r.columns {
+		if i > 0 {
+			center += gaps[i-1]
+		}
+		column.center = center
+	}
+
+	left, right = r.columns[0].center-r.columns[0].width/2, 0
+	for _, column := range r.columns {
+		left = minInt(left, column.center-column.width/2)
+		right = maxInt(right, column.center+column.width/2)
+	}
+	for _, e := range extents {
+		left = minInt(left, r.columns[e.column].center+e.from)
+		right = maxInt(right, r.columns[e.column].center+e.to)
+	}
+
+	return left, right
+}
+
+// heightBound returns a number of rows large enough to draw the diagram.
+func (r *sequenceDrawer) heightBound(titleLines int) int {
+	height := titleLines + 2
+	for _, column := range r.columns {
+		height += 2 * column.height
+	}
+	for _, event := range r.events {
+		text := event.Text
+		if event.Note != nil {
+			text = event.Note.Text
+		}
+		height += len(wrapLabel(r.label(text, len(r.events)), r.wrap)) + 5
+	}
+	return height
+}
+
+// drawEvent draws a message, note, creation or destruction starting below y and
+// returns the next free row.
+func (r *sequenceDrawer) drawEvent(event *sequence.Message, y int) int {
+	if event.Note != nil {
+		return r.drawNote(event.Note, y)
+	}
+
+	to := r.index[event.To]
+
+	switch event.Type {
+	case sequence.MessageDestroy:
+		if to == nil || to.destroyed {
+			return y
+		}
+		r.canvas.set(to.center, y+1, r.chars.cross)
+		to.destroyed, to.end = true, y+1
+		return y + 2
+	case sequence.MessageCreate:
+		return r.drawCreate(event, y)
+	case sequence.MessageActivate, sequence.MessageDeactivate:
+		if event.Text != "" {
+			y = r.drawMessage(event.From, event.To, sequence.MessageSolid, event.Text, y)
+		}
+		if to == nil {
+			return y
+		}
+		if event.Type == sequence.MessageActivate {
+			to.active = append(to.active, y)
+		} else if len(to.active) > 0 {
+			r.closeActivation(to, y)
+		}
+		return y
+	}
+
+	return r.drawMessage(event.From, event.To, event.Type, event.Text, y)
+}
+
+// drawMessage draws a message arrow and its label below y and returns the next free row.
+func (r *sequenceDrawer) drawMessage(fromActor *sequence.Actor, toActor *sequence.Actor, messageType sequence.MessageType, text string, y int) int {
+	from, to := r.index[fromActor], r.index[toActor]
+	if from == nil || to == nil {
+		return y
+	}
+
+	r.number++
+	lines := wrapLabel(r.label(text, r.number), r.wrap)
+	style, head := messageStyle(messageType)
+	y++
+
+	if from == to {
+		x := from.center
+		for i, line := range lines {
+			r.canvas.write(x+2, y+i, line)
+		}
+		y += len(lines)
+		r.canvas.segment(x, y, x+selfMessageWidth, y, style)
+		r.canvas.segment(x+selfMessageWidth, y, x+selfMessageWidth, y+1, style)
+		r.canvas.segment(x+selfMessageWidth, y+1, x, y+1, style)
+		r.drawMarker(x+1, y+1, head, -1)
+		return y + 2
+	}
+
+	side := 1
+	if to.center < from.center {
+		side = -1
+	}
+
+	for i, line := range lines {
+		r.canvas.center((from.center+to.center+1)/2, y+i, line)
+	}
+	y += len(lines)
+	r.canvas.segment(from.center, y, to.center, y, style)
+	r.drawMarker(to.center-side, y, head, side)
+
+	return y + 1
+}
+
+// drawCreate draws the head of a created actor at the end of its creation message and
+// returns the next free row.
+func (r *sequenceDrawer) drawCreate(event *sequence.Message, y int) int {
+	from, to := r.index[event.From], r.index[event.To]
+	if to == nil {
+		return y
+	}
+
+	r.number++
+	lines := wrapLabel(r.label(event.Text, r.number), r.wrap)
+	y++
+	if from != nil && from != to {
+		for i, line := range lines {
+			r.canvas.center((from.center+to.center+1)/2, y+i, line)
+		}
+		y += len(lines)
+	}
+
+	to.top = y
+	if from != nil && from != to {
+		side := 1
+		if to.center < from.center {
+			side = -1
+		}
+		row, edge := y+to.height/2, to.center-side*(to.width/2+1)
+		r.canvas.segment(from.center, row, edge, row, lineDotted)
+		r.drawMarker(edge, row, markerArrow, side)
+	}
+
+	return to.top + to.height
+}
+
+// drawNote draws a note below y and returns the next free row.
+func (r *sequenceDrawer) drawNote(note *sequence.Note, y int) int {
+	var columns []*sequenceColumn
+	for _, actor := range note.Actors {
+		if column := r.index[actor]; column != nil {
+			columns = append(columns, column)
+		}
+	}
+	if len(columns) == 0 {
+		return y
+	}
+
+	lines := wrapLabel(note.Text, r.wrap)
+	textWidth, textHeight := blockSize(lines)
+	width, height := textWidth+4, textHeight+2
+	first := columns[0]
+	var x int
+
+	switch note.Position {
+	case sequence.NoteLeft:
+		x = first.center - sequenceNoteMargin - width
+	case sequence.NoteRight:
+		x = first.center + sequenceNoteMargin + 1
+	default:
+		low, high := first.center, first.center
+		if len(columns) > 1 {
+			low, high = minInt(first.center, columns[1].center)-2, maxInt(first.center, columns[1].center)+2
+		}
+		middle := (low + high + 1) / 2
+		x = minInt(low, middle-width/2)
+		width = maxInt(high+1, middle-width/2+width) - x
+	}
+
+	y++
+	r.canvas.box(x, y, width, height, boxNote)
+	for i, line := range lines {
+		r.canvas.center(x+width/2, y+1+i, line)
+	}
+
+	return y + height
+}
+
+// drawHead draws the box of an actor with its top at y. Actors are drawn with rounded
+// corners and participants with square corners.
+func (r *sequenceDrawer) drawHead(column *sequenceColumn, y int) {
+	style := boxSquare
+	if column.actor.Type == sequence.ActorActor {
+		style = boxRounded
+	}
+
+	x := column.center - column.width/2
+	r.canvas.box(x, y, column.width, column.height, style)
+	for i, line := range column.name {
+		r.canvas.center(column.center, y+1+i, line)
+	}
+}
+
+// drawMarker draws the head of a message pointing in the given horizontal direction.
+func (r *sequenceDrawer) drawMarker(x int, y int, kind marker, side int) {
+	switch kind {
+	case markerArrow:
+		arrow := r.chars.arrows[3]
+		if side < 0 {
+			arrow = r.chars.arrows[2]
+		}
+		r.canvas.set(x, y, arrow)
+	case markerCross:
+		r.canvas.set(x, y, r.chars.cross)
+	}
+}
+
+// closeActivation ends the innermost activation of the column at y.
+func (r *sequenceDrawer) closeActivation(column *sequenceColumn, y int) {
+	last := len(column.active) - 1
+	r.activations = append(r.activations, [3]int{column.center, column.active[last], y})
+	column.active = column.active[:last]
+}
+
+// label returns the text of a message, prefixed with its number when numbering is enabled.
+func (r *sequenceDrawer) label(text string, number int) string {
+	if !r.numbered {
+		return text
+	}
+	return strings.TrimSpace(strconv.Itoa(number) + ". " + text)
+}
+
+// isMessage reports whether the event draws a message arrow.
+func (r *sequenceDrawer) isMessage(event *sequence.Message) bool {
+	switch event.Type {
+	case sequence.MessageActivate, sequence.MessageDeactivate:
+		return event.Text != ""
+	}
+	return true
+}
+
+// noteColumn returns the position of the first displayed actor of a note.
+func (r *sequenceDrawer) noteColumn(note *sequence.Note, position map[*sequence.Actor]int) (int, bool) {
+	for _, actor := range note.Actors {
+		if i, ok := position[actor]; ok {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// messageStyle returns the line style and head of a message type from its arrow syntax:
+// a double dash draws a dotted line, ">>" or ")" an arrow head and "x" a cross.
+func messageStyle(messageType sequence.MessageType) (style lineStyle, head marker) {
+	arrow := string(messageType)
+	if strings.HasPrefix(arrow, "--") {
+		style = lineDotted
+	}
+
+	switch {
+	case strings.HasSuffix(arrow, ">>"), strings.HasSuffix(arrow, ")"):
+		head = markerArrow
+	case strings.HasSuffix(arrow, "x"):
+		head = markerCross
+	}
+
+	return
+}
+
+// minInt returns the smaller of two integers.
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,126 @@
+package text
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/sequence"
+)
+
+// sampleSequence returns a sequence diagram using activations, notes and self messages.
+func sampleSequence() *sequence.Diagram {
+	d := sequence.NewDiagram()
+	d.Title = "Checkout"
+
+	alice := d.AddActor("alice", "Alice", sequence.ActorActor)
+	api := d.AddActor("api", "API", sequence.ActorParticipant)
+	db := d.AddActor("db", "Database", sequence.ActorParticipant)
+
+	d.AddMessage(alice, api, sequence.MessageAsync, "POST /orders")
+	d.AddMessage(alice, api, sequence.MessageActivate, "")
+	d.AddMessage(api, db, sequence.MessageSolidArrow, "INSERT order")
+	d.AddMessage(api, api, sequence.MessageAsync, "validate")
+	d.AddNote(sequence.NoteRight, "stored", db)
+	d.AddMessage(db, api, sequence.MessageDotted, "ok")
+	d.AddMessage(alice, api, sequence.MessageDeactivate, "")
+	d.AddNote(sequence.NoteOver, "done", alice, api)
+
+	return d
+}
+
+func TestRenderSequence(t *testing.T) {
+	d := sequence.NewDiagram()
+	a := d.AddActor("a", "A", sequence.ActorParticipant)
+	b := d.AddActor("b", "B", sequence.ActorParticipant)
+	d.AddMessage(a, b, sequence.MessageAsync, "hi")
+
+	tests := []struct {
+		name    string
+		options Options
+		want    string
+	}{
+		{
+			name: "Unicode",
+			want: "┌───┐   ┌───┐\n│ A │   │ B │\n└───┘   └───┘\n  │       │\n  │  hi   │\n  ├──────►┤\n  │       │\n  │       │\n┌───┐   ┌───┐\n│ A │   │ B │\n└───┘   └───┘\n",
+		},
+		{
+			name:    "ASCII",
+			options: Options{ASCII: true},
+			want:    "+---+   +---+\n| A |   | B |\n+---+   +---+\n  |       |\n  |  hi   |\n  +------>+\n  |       |\n  |       |\n+---+   +---+\n| A |   | B |\n+---+   +---+\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderSequence(d, tt.options); got != tt.want {
+				t.Errorf("RenderSequence() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderSequence_Elements(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		number  bool
+		want    []string
+	}{
+		{
+			name: "Unicode",
+			want: []string{"Checkout", "╭───────╮", "│ Alice │", "┌─────┐", "POST /orders", "──►┤", "┄►┤", "├──┐", "├◄─┘", "┃", "┆ stored ┆", "┆       done        ┆"},
+		},
+		{
+			name:    "ASCII numbered",
+			options: Options{ASCII: true},
+			number:  true,
+			want:    []string{"1. POST /orders", "2. INSERT order", "3. validate", "4. ok", "--->+", "+<.", "#", ": stored :"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := sampleSequence()
+			if tt.number {
+				d.EnableAutoNumber()
+			}
+
+			got := RenderSequence(d, tt.options)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("RenderSequence() missing %q in:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderSequence_CreateDestroy(t *testing.T) {
+	d := sequence.NewDiagram()
+	a := d.AddActor("a", "A", sequence.ActorParticipant)
+	worker := d.CreateActor(a, "w", "Worker", sequence.ActorParticipant)
+	d.AddMessage(a, worker, sequence.MessageAsync, "run")
+	d.DestroyActor(worker)
+
+	got := RenderSequence(d, Options{})
+	lines := strings.Split(got, "\n")
+
+	if !strings.Contains(lines[0], "┌───┐") || strings.Contains(lines[0], "Worker") {
+		t.Errorf("RenderSequence() should draw created actors below the top row:\n%s", got)
+	}
+	if !strings.Contains(got, "►│ Worker │") {
+		t.Errorf("RenderSequence() should point the creation message at the actor box:\n%s", got)
+	}
+	if strings.Count(got, "Worker") != 1 || !strings.Contains(got, "×") {
+		t.Errorf("RenderSequence() should end destroyed lifelines with a cross:\n%s", got)
+	}
+}
+
+func TestRenderSequence_MaxWidth(t *testing.T) {
+	d := sampleSequence()
+	for _, maxWidth := range []int{80, 40, 20} {
+		if got := RenderSequence(d, Options{MaxWidth: maxWidth}); width(got) > maxWidth {
+			t.Errorf("RenderSequence() width = %d, want at most %d:\n%s", width(got), maxWidth, got)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package text
+
+import (
+	"github.com/TyphonHill/go-mermaid/diagrams/state"
+	"github.com/TyphonHill/go-mermaid/render/layout"
+)
+
+// Glyphs of the pseudo states.
+var (
+	glyphInitial = &glyph{unicode: "●", ascii: "*"}
+	glyphFinal   = &glyph{unicode: "◉", ascii: "@"}
+	glyphChoice  = &glyph{unicode: "◆", ascii: "<>"}
+)
+
+// RenderState returns the state diagram drawn with box-drawing or ASCII characters.
+//
+// Composite states are drawn as titled boxes around their nested states, and transitions
+// to or from a composite state attach to its first nested state. Every composite state gets
+// its own initial and final pseudo states. Notes are drawn as boxes linked to their state
+// with a dotted line, placed before the state when on the left and after it when on the right.
+func RenderState(d *state.Diagram, options Options) string {
+	s := &scene{title: d.Title, direction: options.Direction}
+	if s.direction == "" {
+		s.direction = layout.TopToBottom
+	}
+
+	b := &stateSceneBuilder{
+		scene:   s,
+		index:   make(map[*state.State]int),
+		initial: make(map[int]int),
+		final:   make(map[int]int),
+	}
+
+	for _, current := range d.States {
+		b.addState(current, -1)
+	}
+	for _, current := range d.States {
+		b.addImplicitTransitions(current, -1)
+	}
+
+	for _, transition := range d.Transitions {
+		from, ok := b.endpoint(transition.From, -1, true)
+		if !ok {
+			continue
+		}
+		to, ok := b.endpoint(transition.To, -1, false)
+		if !ok {
+			continue
+		}
+
+		edge := sceneEdge{from: from, to: to, label: transition.Description, head: markerArrow, minLength: 1}
+		if transition.Type == state.TransitionDashed {
+			edge.style = lineDotted
+		}
+		s.edges = append(s.edges, edge)
+	}
+
+	for _, current := range d.States {
+		b.addNotes(current)
+	}
+
+	return s.render(options)
+}
+
+// stateSceneBuilder converts the states of a diagram into scene nodes and clusters.
+type stateSceneBuilder struct {
+	scene *scene
+	// index maps states to their scene node, or composite states to their cluster.
+	index map[*state.State]int
+	// initial and final map a cluster to its pseudo state nodes.
+	initial map[int]int
+	final   map[int]int
+}
+
+// addState adds a state and the states nested in it to the scene.
+func (b *stateSceneBuilder) addState(current *state.State, cluster int) {
+	label := current.Description
+	if label == "" {
+		label = current.ID
+	}
+
+	if b.isComposite(current) {
+		b.index[current] = len(b.scene.clusters)
+		b.scene.clusters = append(b.scene.clusters, sceneCluster{title: label, parent: cluster})
+		for _, nested := range current.Nested {
+			b.addState(nested, b.index[current])
+		}
+		return
+	}
+
+	node := sceneNode{label: label, style: boxRounded, cluster: cluster}
+	switch current.Type {
+	case state.StateChoice:
+		node.glyph = glyphChoice
+	case state.StateFork, state.StateJoin:
+		node.bar = true
+	}
+
+	b.index[current] = len(b.scene.nodes)
+	b.scene.nodes = append(b.scene.nodes, node)
+}
+
+// addImplicitTransitions adds the transitions from the initial state and to the final state
+// declared by start and end states.
+func (b *stateSceneBuilder) addImplicitTransitions(current *state.State, cluster int) {
+	if b.isComposite(current) {
+		for _, nested := range current.Nested {
+			b.addImplicitTransitions(nested, b.index[current])
+		}
+		return
+	}
+
+	switch current.Type {
+	case state.StateStart:
+		b.scene.edges = append(b.scene.edges, sceneEdge{from: b.pseudoState(cluster, true), to: b.index[current], head: markerArrow, minLength: 1})
+	case state.StateEnd:
+		b.scene.edges = append(b.scene.edges, sceneEdge{from: b.index[current], to: b.pseudoState(cluster, false), head: markerArrow, minLength: 1})
+	}
+}
+
+// addNotes adds the notes of a state and its nested states to the scene.
+func (b *stateSceneBuilder) addNotes(current *state.State) {
+	if current.Note != nil {
+		if target, ok := b.endpoint(current, -1, false); ok {
+			note := len(b.scene.nodes)
+			b.scene.nodes = append(b.scene.nodes, sceneNode{label: current.Note.Text, style: boxNote, cluster: b.scene.nodes[target].cluster})
+			from, to := note, target
+			if current.Note.Position == state.NoteRight {
+				from, to = target, note
+			}
+			b.scene.edges = append(b.scene.edges, sceneEdge{from: from, to: to, style: lineDotted, minLength: 1})
+		}
+	}
+
+	for _, nested := range current.Nested {
+		b.addNotes(nested)
+	}
+}
+
+// endpoint returns the scene node a transition end is attached to. A nil state is the
+// initial or final pseudo state of the cluster.
+func (b *stateSceneBuilder) endpoint(current *state.State, cluster int, initial bool) (int, bool) {
+	if current == nil {
+		return b.pseudoState(cluster, initial), true
+	}
+
+	index, ok := b.index[current]
+	if !ok {
+		return 0, false
+	}
+
+	if b.isComposite(current) {
+		for _, nested := range current.Nested {
+			if node, ok := b.endpoint(nested, index, initial); ok {
+				return node, true
+			}
+		}
+		return 0, false
+	}
+
+	return index, true
+}
+
+// isComposite reports whether the state is drawn as a cluster. Composite states without
+// nested states are drawn as plain states.
+func (b *stateSceneBuilder) isComposite(current *state.State) bool {
+	return len(current.Nested) > 0
+}
+
+// pseudoState returns the initial or final pseudo state node of a cluster, creating it on
+// first use.
+func (b *stateSceneBuilder) pseudoState(cluster int, initial bool) int {
+	nodes, g := b.final, glyphFinal
+	if initial {
+		nodes, g = b.initial, glyphInitial
+	}
+
+	if node, ok := nodes[cluster]; ok {
+		return node
+	}
+
+	nodes[cluster] = len(b.scene.nodes)
+	b.scene.nodes = append(b.scene.nodes, sceneNode{glyph: g, cluster: cluster})
+	return nodes[cluster]
+}
@@ -0,0 +1,53 @@
+package text
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TyphonHill/go-mermaid/diagrams/state"
+	"github.com/TyphonHill/go-mermaid/render/layout"
+)
+
+func TestRenderState(t *testing.T) {
+	d := state.NewDiagram()
+	idle := d.AddState("Idle", "", state.StateStart)
+	busy := d.AddState("Busy", "", state.StateEnd)
+	d.AddTransition(idle, busy, "run")
+
+	want := "    *\n    |\n    |\n    |\n    v\n.------.\n| Idle |\n'------'\n    |\n    |\n    |\n   run\n    v\n.------.\n| Busy |\n'------'\n    |\n    |\n    |\n    v\n    @\n"
+	if got := RenderState(d, Options{ASCII: true}); got != want {
+		t.Errorf("RenderState() =\n%s\nwant:\n%s", got, want)
+	}
+
+	got := RenderState(d, Options{Direction: layout.LeftToRight})
+	if lines := strings.Count(got, "\n"); lines != 3 {
+		t.Errorf("RenderState() left to right should be 3 lines high, got:\n%s", got)
+	}
+}
+
+func TestRenderState_Elements(t *testing.T) {
+	d := state.NewDiagram()
+	d.Title = "Jobs"
+	idle := d.AddState("Idle", "Waiting", state.StateStart)
+	check := d.AddState("Check", "", state.StateComposite)
+	check.AddNestedState("Validate", "", state.StateStart)
+	check.AddNestedState("Store", "", state.StateEnd)
+	choice := d.AddState("Route", "", state.StateChoice)
+	fork := d.AddState("Split", "", state.StateFork)
+	done := d.AddState("Done", "", state.StateEnd).AddNote("archived", state.NoteRight)
+	d.AddTransition(idle, check, "submit")
+	d.AddTransition(check, choice, "").SetType(state.TransitionDashed)
+	d.AddTransition(choice, fork, "ok")
+	d.AddTransition(fork, done, "")
+
+	got := RenderState(d, Options{})
+	for _, want := range []string{"Jobs", "╭─────────╮", "│ Waiting │", "┌ Check", "Validate", "Store", "submit", "◆", "━━━", "┆ archived ┆", "┄", "●", "◉"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderState() missing %q in:\n%s", want, got)
+		}
+	}
+
+	if strings.Count(got, "●") != 2 {
+		t.Errorf("RenderState() should draw an initial state per scope:\n%s", got)
+	}
+}
@@ -0,0 +1,403 @@
+// Package text renders diagrams as Unicode box-drawing or plain ASCII art.
+//
+// The output is meant for terminals, logs and code review comments where Mermaid is not
+// rendered. Flowcharts and state diagrams are laid out with the layered layout shared with
+// the SVG renderer, on a grid of character cells. Rendering is deterministic.
+package text
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/TyphonHill/go-mermaid/render/layout"
+)
+
+// Minimum width labels are wrapped to when fitting a diagram into Options.MaxWidth.
+const (
+	minWrapWidth int = 6
+)
+
+// Options controls the character set and size of rendered diagrams.
+type Options struct {
+	// ASCII restricts the output to printable ASCII characters instead of Unicode box drawing.
+	ASCII bool
+	// MaxWidth is the maximum line width in columns. Labels are wrapped to fit and lines
+	// that still exceed it are cut. Zero means unlimited.
+	MaxWidth int
+	// Direction overrides the direction of flowcharts and sets the direction of state
+	// diagrams, which are drawn top to bottom by default.
+	Direction layout.Direction
+}
+
+// lineStyle is the stroke of a line drawn on the canvas.
+type lineStyle int
+
+const (
+	lineSolid lineStyle = iota
+	lineDotted
+	lineThick
+)
+
+// Line direction bits of a canvas cell.
+const (
+	up uint8 = 1 << iota
+	down
+	left
+	right
+)
+
+// charset holds the characters used to draw a diagram.
+type charset struct {
+	// lines maps a style and a combination of direction bits to a character.
+	lines [3][16]rune
+	// arrows are the arrow heads pointing up, down, left and right.
+	arrows     [4]rune
+	bullet     rune
+	cross      rune
+	ellipsis   rune
+	activation rune
+	boxes      map[boxStyle][6]rune
+}
+
+// boxStyle is the border of a box: corners top-left, top-right, bottom-left, bottom-right,
+// then the horizontal and vertical sides.
+type boxStyle int
+
+const (
+	boxSquare boxStyle = iota
+	boxRounded
+	boxAngled
+	boxDouble
+	boxNote
+	boxNone
+)
+
+// lineSet builds the line characters of a style from its straight and corner characters.
+func lineSet(horizontal rune, vertical rune, corners [4]rune, tees [5]rune) (set [16]rune) {
+	set[up], set[down], set[up|down] = vertical, vertical, vertical
+	set[left], set[right], set[left|right] = horizontal, horizontal, horizontal
+	set[down|right], set[down|left], set[up|right], set[up|left] = corners[0], corners[1], corners[2], corners[3]
+	set[up|down|right], set[up|down|left], set[left|right|down], set[left|right|up], set[up|down|left|right] = tees[0], tees[1], tees[2], tees[3], tees[4]
+	return
+}
+
+var unicodeCharset = charset{
+	lines: [3][16]rune{
+		lineSolid:  lineSet('─', '│', [4]rune{'┌', '┐', '└', '┘'}, [5]rune{'├', '┤', '┬', '┴', '┼'}),
+		lineDotted: lineSet('┄', '┆', [4]rune{'┌', '┐', '└', '┘'}, [5]rune{'├', '┤', '┬', '┴', '┼'}),
+		lineThick:  lineSet('━', '┃', [4]rune{'┏', '┓', '┗', '┛'}, [5]rune{'┣', '┫', '┳', '┻', '╋'}),
+	},
+	arrows:     [4]rune{'▲', '▼', '◄', '►'},
+	bullet:     '●',
+	cross:      '×',
+	ellipsis:   '…',
+	activation: '┃',
+	boxes: map[boxStyle][6]rune{
+		boxSquare:  {'┌', '┐', '└', '┘', '─', '│'},
+		boxRounded: {'╭', '╮', '╰', '╯', '─', '│'},
+		boxAngled:  {'/', '\\', '\\', '/', '─', '│'},
+		boxDouble:  {'╔', '╗', '╚', '╝', '═', '║'},
+		boxNote:    {'┌', '┐', '└', '┘', '┄', '┆'},
+		boxNone:    {' ', ' ', ' ', ' ', ' ', ' '},
+	},
+}
+
+var asciiCharset = charset{
+	lines: [3][16]rune{
+		lineSolid:  lineSet('-', '|', [4]rune{'+', '+', '+', '+'}, [5]rune{'+', '+', '+', '+', '+'}),
+		lineDotted: lineSet('.', ':', [4]rune{'+', '+', '+', '+'}, [5]rune{'+', '+', '+', '+', '+'}),
+		lineThick:  lineSet('=', '|', [4]rune{'+', '+', '+', '+'}, [5]rune{'+', '+', '+', '+', '+'}),
+	},
+	arrows:     [4]rune{'^', 'v', '<', '>'},
+	bullet:     'o',
+	cross:      'x',
+	ellipsis:   '~',
+	activation: '#',
+	boxes: map[boxStyle][6]rune{
+		boxSquare:  {'+', '+', '+', '+', '-', '|'},
+		boxRounded: {'.', '.', '\'', '\'', '-', '|'},
+		boxAngled:  {'/', '\\', '\\', '/', '-', '|'},
+		boxDouble:  {'#', '#', '#', '#', '=', '#'},
+		boxNote:    {'+', '+', '+', '+', '.', ':'},
+		boxNone:    {' ', ' ', ' ', ' ', ' ', ' '},
+	},
+}
+
+// chars returns the character set selected by the options.
+func (o Options) chars() *charset {
+	if o.ASCII {
+		return &asciiCharset
+	}
+	return &unicodeCharset
+}
+
+// canvas is a grid of character cells. Lines are recorded as direction bits and resolved
+// to characters when the canvas is printed, so crossing and joining lines merge.
+type canvas struct {
+	chars  *charset
+	width  int
+	height int
+	cells  [][]rune
+	masks  [][]uint8
+	styles [][]lineStyle
+	fixed  [][]bool
+	// reserved cells may be crossed by lines but not covered by labels or line turns.
+	reserved [][]bool
+}
+
+// newCanvas returns an empty canvas of the given size.
+func newCanvas(chars *charset, width int, height int) *canvas {
+	c := &canvas{chars: chars, width: width, height: height}
+	c.cells = make([][]rune, height)
+	c.masks = make([][]uint8, height)
+	c.styles = make([][]lineStyle, height)
+	c.fixed = make([][]bool, height)
+	c.reserved = make([][]bool, height)
+	for y := 0; y < height; y++ {
+		c.cells[y] = make([]rune, width)
+		c.masks[y] = make([]uint8, width)
+		c.styles[y] = make([]lineStyle, width)
+		c.fixed[y] = make([]bool, width)
+		c.reserved[y] = make([]bool, width)
+	}
+	return c
+}
+
+// inside reports whether the cell is on the canvas.
+func (c *canvas) inside(x int, y int) bool {
+	return x >= 0 && y >= 0 && x < c.width && y < c.height
+}
+
+// isFixed reports whether the cell holds text or a box that lines must not cross.
+func (c *canvas) isFixed(x int, y int) bool {
+	return c.inside(x, y) && c.fixed[y][x]
+}
+
+// isReserved reports whether the cell lies on a cluster border.
+func (c *canvas) isReserved(x int, y int) bool {
+	return c.inside(x, y) && c.reserved[y][x]
+}
+
+// isFree reports whether the cell is neither fixed nor reserved.
+func (c *canvas) isFree(x int, y int) bool {
+	return !c.isFixed(x, y) && !c.isReserved(x, y)
+}
+
+// reserve keeps labels and line turns off the cell.
+func (c *canvas) reserve(x int, y int) {
+	if c.inside(x, y) {
+		c.reserved[y][x] = true
+	}
+}
+
+// set writes a character to a cell and protects it from lines.
+func (c *canvas) set(x int, y int, char rune) {
+	if c.inside(x, y) {
+		c.cells[y][x] = char
+		c.fixed[y][x] = true
+	}
+}
+
+// write writes text starting at the cell.
+func (c *canvas) write(x int, y int, content string) {
+	for _, char := range content {
+		c.set(x, y, char)
+		x++
+	}
+}
+
+// center writes text centered on the column.
+func (c *canvas) center(x int, y int, content string) {
+	c.write(x-utf8.RuneCountInString(content)/2, y, content)
+}
+
+// mark adds line directions to a cell that is not fixed.
+func (c *canvas) mark(x int, y int, bits uint8, style lineStyle) {
+	if !c.inside(x, y) || c.fixed[y][x] {
+		return
+	}
+	if c.masks[y][x] == 0 || style != lineSolid {
+		c.styles[y][x] = style
+	}
+	c.masks[y][x] |= bits
+}
+
+// segment draws a horizontal or vertical line between two cells.
+func (c *canvas) segment(x1 int, y1 int, x2 int, y2 int, style lineStyle) {
+	switch {
+	case y1 == y2:
+		if x1 > x2 {
+			x1, x2 = x2, x1
+		}
+		for x := x1; x <= x2; x++ {
+			var bits uint8
+			if x > x1 {
+				bits |= left
+			}
+			if x < x2 {
+				bits |= right
+			}
+			c.mark(x, y1, bits, style)
+		}
+	case x1 == x2:
+		if y1 > y2 {
+			y1, y2 = y2, y1
+		}
+		for y := y1; y <= y2; y++ {
+			var bits uint8
+			if y > y1 {
+				bits |= up
+			}
+			if y < y2 {
+				bits |= down
+			}
+			c.mark(x1, y, bits, style)
+		}
+	}
+}
+
+// box draws a bordered box with its top-left corner at the cell and protects its interior.
+func (c *canvas) box(x int, y int, width int, height int, style boxStyle) {
+	border := c.chars.boxes[style]
+
+	for row := y; row < y+height; row++ {
+		for column := x; column < x+width; column++ {
+			char := ' '
+			switch {
+			case row == y && column == x:
+				char = border[0]
+			case row == y && column == x+width-1:
+				char = border[1]
+			case row == y+height-1 && column == x:
+				char = border[2]
+			case row == y+height-1 && column == x+width-1:
+				char = border[3]
+			case row == y || row == y+height-1:
+				char = border[4]
+			case column == x || column == x+width-1:
+				char = border[5]
+			}
+			c.set(column, row, char)
+		}
+	}
+}
+
+// String prints the canvas with trailing spaces and empty lines removed.
+func (c *canvas) String() string {
+	lines := make([]string, 0, c.height)
+
+	for y := 0; y < c.height; y++ {
+		var sb strings.Builder
+		for x := 0; x < c.width; x++ {
+			char := c.cells[y][x]
+			if char == 0 {
+				char = ' '
+				if mask := c.masks[y][x]; mask != 0 {
+					char = c.chars.lines[c.styles[y][x]][mask]
+				}
+			}
+			sb.WriteRune(char)
+		}
+		lines = append(lines, strings.TrimRight(sb.String(), " "))
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// fit renders with draw, wrapping labels to narrower widths until the output fits into
+// maxWidth, and cuts the lines that still exceed it.
+func fit(maxWidth int, chars *charset, draw func(wrap int) string) string {
+	output := draw(0)
+	if maxWidth <= 0 || width(output) <= maxWidth {
+		return output
+	}
+
+	for wrap := maxInt(maxWidth/2, minWrapWidth); wrap >= minWrapWidth; wrap = wrap * 3 / 4 {
+		if output = draw(wrap); width(output) <= maxWidth {
+			return output
+		}
+	}
+
+	return cut(output, maxWidth, chars.ellipsis)
+}
+
+// width returns the length of the longest line of the output in runes.
+func width(output string) (widest int) {
+	for _, line := range strings.Split(output, "\n") {
+		if length := utf8.RuneCountInString(line); length > widest {
+			widest = length
+		}
+	}
+	return
+}
+
+// cut shortens lines longer than maxWidth, ending them with the ellipsis.
+func cut(output string, maxWidth int, ellipsis rune) string {
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		if runes := []rune(line); len(runes) > maxWidth {
+			lines[i] = string(runes[:maxWidth-1]) + string(ellipsis)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrapLabel splits a label into lines on explicit line breaks and, when width is positive,
+// wraps words so that no line is longer than width. Longer words are broken.
+func wrapLabel(label string, width int) []string {
+	var lines []string
+
+	for _, paragraph := range strings.Split(lineBreaks.Replace(label), "\n") {
+		if width <= 0 {
+			lines = append(lines, paragraph)
+			continue
+		}
+
+		current := ""
+		for _, word := range strings.Fields(paragraph) {
+			for utf8.RuneCountInString(word) > width {
+				if current != "" {
+					lines = append(lines, current)
+					current = ""
+				}
+				runes := []rune(word)
+				lines = append(lines, string(runes[:width]))
+				word = string(runes[width:])
+			}
+
+			switch {
+			case current == "":
+				current = word
+			case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) <= width:
+				current += " " + word
+			default:
+				lines = append(lines, current)
+				current = word
+			}
+		}
+		lines = append(lines, current)
+	}
+
+	return lines
+}
+
+// blockSize returns the width of the longest line and the number of lines.
+func blockSize(lines []string) (width int, height int) {
+	for _, line := range lines {
+		if length := utf8.RuneCountInString(line); length > width {
+			width = length
+		}
+	}
+	return width, len(lines)
+}
+
+// lineBreaks lists the separators that split labels into lines.
+var lineBreaks = strings.NewReplacer("<br/>", "\n", "<br />", "\n", "<br>", "\n")
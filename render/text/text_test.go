@@ -0,0 +1,94 @@
+package text
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWrapLabel(t *testing.T) {
+	tests := []struct {
+		name  string
+		label string
+		width int
+		want  []string
+	}{
+		{name: "Unlimited", label: "one two three", width: 0, want: []string{"one two three"}},
+		{name: "Line breaks", label: "one<br/>two<br>three", width: 0, want: []string{"one", "two", "three"}},
+		{name: "Words", label: "one two three", width: 7, want: []string{"one two", "three"}},
+		{name: "Long word", label: "abcdefghij", width: 4, want: []string{"abcd", "efgh", "ij"}},
+		{name: "Empty", label: "", width: 5, want: []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapLabel(tt.label, tt.width); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wrapLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanvas_Lines(t *testing.T) {
+	tests := []struct {
+		name  string
+		chars *charset
+		want  string
+	}{
+		{name: "Unicode", chars: &unicodeCharset, want: "┌─┬─┐\n│ │ ┆\n└─┴┄┘\n"},
+		{name: "ASCII", chars: &asciiCharset, want: "+-+-+\n| | :\n+-+.+\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCanvas(tt.chars, 5, 3)
+			c.segment(0, 0, 4, 0, lineSolid)
+			c.segment(0, 0, 0, 2, lineSolid)
+			c.segment(2, 0, 2, 2, lineSolid)
+			c.segment(0, 2, 2, 2, lineSolid)
+			c.segment(4, 0, 4, 2, lineDotted)
+			c.segment(2, 2, 4, 2, lineDotted)
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("canvas.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanvas_Box(t *testing.T) {
+	c := newCanvas(&unicodeCharset, 7, 5)
+	c.segment(3, 0, 3, 4, lineSolid)
+	c.box(0, 1, 7, 3, boxRounded)
+	c.center(3, 2, "box")
+
+	want := "   │\n╭─────╮\n│ box │\n╰─────╯\n   │\n"
+	if got := c.String(); got != want {
+		t.Errorf("canvas.String() = %q, want %q", got, want)
+	}
+}
+
+func TestFit(t *testing.T) {
+	draw := func(wrap int) string {
+		return strings.Join(wrapLabel("alpha beta gamma delta", wrap), "\n") + "\n"
+	}
+
+	tests := []struct {
+		name     string
+		maxWidth int
+		want     string
+	}{
+		{name: "Unlimited", maxWidth: 0, want: "alpha beta gamma delta\n"},
+		{name: "Wide enough", maxWidth: 30, want: "alpha beta gamma delta\n"},
+		{name: "Wrapped", maxWidth: 12, want: "alpha\nbeta\ngamma\ndelta\n"},
+		{name: "Cut", maxWidth: 4, want: "alp…\nbeta\ngam…\ndel…\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fit(tt.maxWidth, &unicodeCharset, draw); got != tt.want {
+				t.Errorf("fit() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}